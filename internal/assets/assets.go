@@ -0,0 +1,225 @@
+// Package assets inventories the templates, blueprint defaults, CI templates,
+// migrations and example definitions compiled into the gogo binary and
+// verifies them against the checksums recorded at build time.
+package assets
+
+import (
+	"context"
+	"crypto/sha256"
+	_ "embed"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/flosch/pongo2/v6"
+	"github.com/user/gogo/internal/blueprints"
+	"github.com/user/gogo/internal/catalog"
+	"github.com/user/gogo/internal/cicd"
+	"github.com/user/gogo/internal/components"
+	"github.com/user/gogo/internal/db"
+	"github.com/user/gogo/internal/examples"
+	"github.com/user/gogo/internal/templates"
+)
+
+// SumFile is the name of the checksum list embedded next to this package;
+// regenerate it with go test ./internal/assets -update
+const SumFile = "assets.sum"
+
+//go:embed assets.sum
+var sumFile string
+
+// Asset is one resource compiled into the binary
+type Asset struct {
+	Path     string // e.g. cicd/ci.yml.tmpl or templates/cli/main.go
+	Data     []byte
+	Template bool // rendered with pongo2
+}
+
+// Problem describes an asset that failed verification
+type Problem struct {
+	Path    string
+	Message string
+}
+
+// Report is the result of Verify
+type Report struct {
+	Assets   int
+	Digest   string // SHA-256 over every asset checksum, identifies the asset set
+	Problems []Problem
+}
+
+// OK reports whether every asset passed verification
+func (r *Report) OK() bool {
+	return len(r.Problems) == 0
+}
+
+// All returns every embedded asset sorted by path
+func All() ([]Asset, error) {
+	var assets []Asset
+
+	for _, source := range []struct {
+		prefix   string
+		fsys     fs.FS
+		template bool
+	}{
+		{"cicd", cicd.TemplateFS(), true},
+		{"blueprints", blueprints.DefaultsFS(), false},
+		{"migrations", db.MigrationFS(), false},
+		{"examples", examples.FS(), false},
+	} {
+		err := fs.WalkDir(source.fsys, ".", func(name string, entry fs.DirEntry, err error) error {
+			if err != nil || entry.IsDir() {
+				return err
+			}
+			data, err := fs.ReadFile(source.fsys, name)
+			if err != nil {
+				return err
+			}
+			assets = append(assets, Asset{Path: path.Join(source.prefix, name), Data: data, Template: source.template})
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s assets: %w", source.prefix, err)
+		}
+	}
+
+	// The dependency catalog the templates pin versions from
+	assets = append(assets, Asset{Path: "catalog/catalog.json", Data: catalog.BuiltinJSON()})
+
+	// Project, blueprint and component templates are compiled in as Go values
+	ctx := context.Background()
+	repo := templates.NewRepository()
+	predefined, err := repo.ListPredefinedTemplates(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, template := range predefined {
+		kind := template.Kind
+		files, err := repo.GetTemplateFiles(ctx, kind)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			assets = append(assets, Asset{Path: path.Join("templates", kind, file.Name), Data: []byte(file.Content), Template: true})
+		}
+	}
+	for stack, files := range templates.GetBlueprintTemplates() {
+		for _, file := range files {
+			assets = append(assets, Asset{Path: path.Join("blueprint-templates", stack, file.Name), Data: []byte(file.Content), Template: true})
+		}
+	}
+	for key, files := range components.Templates() {
+		for _, file := range files {
+			assets = append(assets, Asset{Path: path.Join("components", key, file.Name), Data: []byte(file.Content), Template: true})
+		}
+	}
+
+	sort.Slice(assets, func(i, j int) bool { return assets[i].Path < assets[j].Path })
+	return assets, nil
+}
+
+// Verify checks every embedded asset against the embedded checksum list and
+// makes sure templates parse and blueprint defaults and migrations load
+func Verify() (*Report, error) {
+	assets, err := All()
+	if err != nil {
+		return nil, err
+	}
+	sums, err := ParseSums(sumFile)
+	if err != nil {
+		return nil, err
+	}
+
+	report := verify(assets, sums)
+	if _, err := blueprints.Defaults(); err != nil {
+		report.Problems = append(report.Problems, Problem{Path: "blueprints", Message: err.Error()})
+	}
+	if _, err := db.CoreMigrations(); err != nil {
+		report.Problems = append(report.Problems, Problem{Path: "migrations", Message: err.Error()})
+	}
+	if _, err := examples.List(); err != nil {
+		report.Problems = append(report.Problems, Problem{Path: "examples", Message: err.Error()})
+	}
+	return report, nil
+}
+
+func verify(assets []Asset, sums map[string]string) *Report {
+	report := &Report{Assets: len(assets)}
+	seen := make(map[string]bool, len(assets))
+	digest := sha256.New()
+
+	for _, asset := range assets {
+		if seen[asset.Path] {
+			report.Problems = append(report.Problems, Problem{Path: asset.Path, Message: "duplicate asset path"})
+			continue
+		}
+		seen[asset.Path] = true
+
+		sum := Checksum(asset.Data)
+		fmt.Fprintf(digest, "%s  %s\n", sum, asset.Path)
+
+		switch expected, ok := sums[asset.Path]; {
+		case !ok:
+			report.Problems = append(report.Problems, Problem{Path: asset.Path, Message: "not listed in " + SumFile})
+		case expected != sum:
+			report.Problems = append(report.Problems, Problem{Path: asset.Path, Message: fmt.Sprintf("checksum %s does not match %s", sum, expected)})
+		}
+
+		if len(asset.Data) == 0 {
+			report.Problems = append(report.Problems, Problem{Path: asset.Path, Message: "asset is empty"})
+		}
+		if asset.Template {
+			if _, err := pongo2.FromString(string(asset.Data)); err != nil {
+				report.Problems = append(report.Problems, Problem{Path: asset.Path, Message: fmt.Sprintf("template does not parse: %v", err)})
+			}
+		}
+	}
+
+	var missing []string
+	for p := range sums {
+		if !seen[p] {
+			missing = append(missing, p)
+		}
+	}
+	sort.Strings(missing)
+	for _, p := range missing {
+		report.Problems = append(report.Problems, Problem{Path: p, Message: "listed in " + SumFile + " but not embedded"})
+	}
+
+	report.Digest = hex.EncodeToString(digest.Sum(nil))
+	return report
+}
+
+// Checksum returns the hex SHA-256 of data
+func Checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// FormatSums renders the checksum list for assets in sha256sum format
+func FormatSums(assets []Asset) string {
+	var b strings.Builder
+	for _, asset := range assets {
+		fmt.Fprintf(&b, "%s  %s\n", Checksum(asset.Data), asset.Path)
+	}
+	return b.String()
+}
+
+// ParseSums reads a checksum list written by FormatSums
+func ParseSums(content string) (map[string]string, error) {
+	sums := make(map[string]string)
+	for i, line := range strings.Split(content, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		sum, p, ok := strings.Cut(line, "  ")
+		if !ok || len(sum) != sha256.Size*2 {
+			return nil, fmt.Errorf("%s:%d: malformed line", SumFile, i+1)
+		}
+		sums[p] = sum
+	}
+	return sums, nil
+}
@@ -0,0 +1,86 @@
+package assets
+
+import (
+	"flag"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "rewrite "+SumFile+" from the embedded assets")
+
+func TestSumFileIsCurrent(t *testing.T) {
+	assets, err := All()
+	require.NoError(t, err)
+	expected := FormatSums(assets)
+
+	if *update {
+		require.NoError(t, os.WriteFile(SumFile, []byte(expected), 0644))
+		return
+	}
+	assert.Equal(t, expected, sumFile, "embedded assets changed; run go test ./internal/assets -update")
+}
+
+func TestVerify(t *testing.T) {
+	if *update {
+		t.Skip("the embedded " + SumFile + " is being rewritten")
+	}
+	report, err := Verify()
+	require.NoError(t, err)
+	assert.True(t, report.OK(), "problems: %v", report.Problems)
+	assert.Positive(t, report.Assets)
+	assert.Len(t, report.Digest, 64)
+
+	assets, err := All()
+	require.NoError(t, err)
+	prefixes := map[string]bool{}
+	for _, asset := range assets {
+		prefixes[asset.Path[:indexSlash(asset.Path)]] = true
+	}
+	for _, prefix := range []string{"cicd", "blueprints", "migrations", "templates", "blueprint-templates", "components"} {
+		assert.True(t, prefixes[prefix], "no %s assets", prefix)
+	}
+}
+
+func TestVerifyReportsProblems(t *testing.T) {
+	assets := []Asset{
+		{Path: "cicd/ok.tmpl", Data: []byte("{{ Name }}"), Template: true},
+		{Path: "cicd/changed.tmpl", Data: []byte("changed"), Template: true},
+		{Path: "cicd/broken.tmpl", Data: []byte("{% if %}"), Template: true},
+		{Path: "cicd/unlisted.tmpl", Data: []byte("x")},
+	}
+	sums, err := ParseSums(FormatSums(assets))
+	require.NoError(t, err)
+	sums["cicd/changed.tmpl"] = Checksum([]byte("original"))
+	sums["cicd/gone.tmpl"] = Checksum([]byte("gone"))
+	delete(sums, "cicd/unlisted.tmpl")
+
+	report := verify(assets, sums)
+	assert.False(t, report.OK())
+
+	messages := map[string]string{}
+	for _, problem := range report.Problems {
+		messages[problem.Path] = problem.Message
+	}
+	assert.NotContains(t, messages, "cicd/ok.tmpl")
+	assert.Contains(t, messages["cicd/changed.tmpl"], "does not match")
+	assert.Contains(t, messages["cicd/broken.tmpl"], "does not parse")
+	assert.Contains(t, messages["cicd/unlisted.tmpl"], "not listed")
+	assert.Contains(t, messages["cicd/gone.tmpl"], "not embedded")
+}
+
+func TestParseSumsRejectsMalformedLines(t *testing.T) {
+	_, err := ParseSums("not a checksum line\n")
+	assert.ErrorContains(t, err, "malformed")
+}
+
+func indexSlash(p string) int {
+	for i := range p {
+		if p[i] == '/' {
+			return i
+		}
+	}
+	return len(p)
+}
@@ -0,0 +1,85 @@
+package assetsync
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/user/gogo/internal/state"
+	"gopkg.in/yaml.v3"
+)
+
+// LockFile is the name of the lockfile written at the workspace root
+const LockFile = "gogo.lock"
+
+// Lock pins the shared asset repositories consumed by a workspace
+type Lock struct {
+	Sources []Source `yaml:"sources"`
+}
+
+// Source is a shared asset repository and the commit it is pinned to
+type Source struct {
+	Name     string    `yaml:"name"`
+	URL      string    `yaml:"url"`
+	Path     string    `yaml:"path"` // relative to the workspace root
+	Strategy string    `yaml:"strategy"`
+	Ref      string    `yaml:"ref,omitempty"` // branch, tag or commit requested
+	Commit   string    `yaml:"commit"`        // commit currently checked out
+	SyncedAt time.Time `yaml:"synced_at"`
+}
+
+// LoadLock reads the lockfile in dir, from the state store when the org
+// policy keeps it out of the repository; a missing lockfile is an empty lock
+func LoadLock(dir string) (*Lock, error) {
+	path := filepath.Join(dir, LockFile)
+	data, err := state.Read(dir, LockFile)
+	if os.IsNotExist(err) {
+		return &Lock{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var lock Lock
+	if err := yaml.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return &lock, nil
+}
+
+// Save writes the lockfile into dir, or the state store when the org policy
+// keeps it out of the repository, with sources sorted by name
+func (l *Lock) Save(dir string) error {
+	sort.Slice(l.Sources, func(i, j int) bool { return l.Sources[i].Name < l.Sources[j].Name })
+
+	data, err := yaml.Marshal(l)
+	if err != nil {
+		return fmt.Errorf("failed to encode lockfile: %w", err)
+	}
+	header := []byte("# Generated by gogo sync; pins shared asset repositories. Do not edit.\n")
+	if err := state.Write(dir, "", LockFile, append(header, data...)); err != nil {
+		return fmt.Errorf("failed to write %s: %w", LockFile, err)
+	}
+	return nil
+}
+
+// Find returns the source with the given name
+func (l *Lock) Find(name string) (*Source, bool) {
+	for i := range l.Sources {
+		if l.Sources[i].Name == name {
+			return &l.Sources[i], true
+		}
+	}
+	return nil, false
+}
+
+// Set adds source or replaces the source with the same name
+func (l *Lock) Set(source Source) {
+	if existing, ok := l.Find(source.Name); ok {
+		*existing = source
+		return
+	}
+	l.Sources = append(l.Sources, source)
+}
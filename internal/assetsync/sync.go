@@ -0,0 +1,267 @@
+// Package assetsync consumes shared template and asset repositories inside a
+// workspace as git submodules or subtrees and pins them in gogo.lock.
+package assetsync
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/user/gogo/internal/git"
+	"github.com/user/gogo/internal/state"
+	"github.com/user/gogo/internal/workspace"
+)
+
+// Sync strategies
+const (
+	StrategySubmodule = "submodule"
+	StrategySubtree   = "subtree"
+)
+
+// Strategies lists the supported sync strategies
+var Strategies = []string{StrategySubmodule, StrategySubtree}
+
+// Options describes a shared repository to sync
+type Options struct {
+	Name     string // defaults to the repository name
+	URL      string
+	Path     string // defaults to templates/<name>
+	Ref      string // branch, tag or commit; empty follows the remote's default branch
+	Strategy string
+}
+
+// Syncer adds and updates shared repositories under a workspace root
+type Syncer struct {
+	root string
+	git  *git.GitManager
+	now  func() time.Time
+}
+
+// NewSyncer creates a syncer for the workspace containing dir: the go.work
+// root when there is one, otherwise the git repository root
+func NewSyncer(ctx context.Context, dir string) (*Syncer, error) {
+	if !git.IsGitInstalled() {
+		return nil, fmt.Errorf("git is not installed or not available in PATH")
+	}
+
+	root, err := git.NewGitManager(dir).TopLevel(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("gogo sync must run inside a git repository: %w", err)
+	}
+	ws, err := workspace.Find(dir)
+	if err != nil {
+		return nil, err
+	}
+	if ws != nil {
+		root = ws.Root
+	}
+
+	return &Syncer{root: root, git: git.NewGitManager(root), now: time.Now}, nil
+}
+
+// Root returns the directory holding the lockfile
+func (s *Syncer) Root() string {
+	return s.root
+}
+
+// Sync adds the repository described by opts, or updates it when the
+// lockfile already has a source of that name, and records the pinned commit
+func (s *Syncer) Sync(ctx context.Context, opts Options) (*Source, error) {
+	lock, err := LoadLock(s.root)
+	if err != nil {
+		return nil, err
+	}
+
+	source, err := s.resolve(lock, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := source.Commit
+	switch source.Strategy {
+	case StrategySubmodule:
+		err = s.syncSubmodule(ctx, source)
+	case StrategySubtree:
+		err = s.syncSubtree(ctx, source)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// An unchanged pin leaves the lockfile alone, so re-syncing is a no-op
+	if locked, ok := lock.Find(source.Name); ok && locked.Commit == source.Commit && locked.Ref == source.Ref {
+		return source, nil
+	}
+
+	source.SyncedAt = s.now().UTC().Truncate(time.Second)
+	lock.Set(*source)
+	if err := lock.Save(s.root); err != nil {
+		return nil, err
+	}
+	// A lockfile kept in the gogo database is not committed
+	if state.Remote() {
+		return source, nil
+	}
+
+	// Subtree syncs commit, so the pin goes into the same commit; submodule
+	// changes are only staged, next to the lockfile
+	if source.Strategy == StrategySubtree && source.Commit != previous {
+		err = s.git.AmendWith(ctx, LockFile)
+	} else {
+		err = s.git.Add(ctx, LockFile)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to record %s: %w", LockFile, err)
+	}
+	return source, nil
+}
+
+// SyncAll updates every source in the lockfile to its requested ref
+func (s *Syncer) SyncAll(ctx context.Context) ([]Source, error) {
+	lock, err := LoadLock(s.root)
+	if err != nil {
+		return nil, err
+	}
+	if len(lock.Sources) == 0 {
+		return nil, fmt.Errorf("%s lists no shared repositories; add one with gogo sync <url>", LockFile)
+	}
+
+	synced := make([]Source, 0, len(lock.Sources))
+	for _, source := range lock.Sources {
+		updated, err := s.Sync(ctx, Options{Name: source.Name, Ref: source.Ref})
+		if err != nil {
+			return synced, fmt.Errorf("failed to sync %s: %w", source.Name, err)
+		}
+		synced = append(synced, *updated)
+	}
+	return synced, nil
+}
+
+// resolve merges opts with the locked source of the same name
+func (s *Syncer) resolve(lock *Lock, opts Options) (*Source, error) {
+	if opts.Name == "" && opts.URL != "" {
+		opts.Name = repoName(opts.URL)
+	}
+	if opts.Name == "" {
+		return nil, fmt.Errorf("a repository URL or source name is required")
+	}
+
+	source := Source{Name: opts.Name}
+	if locked, ok := lock.Find(opts.Name); ok {
+		source = *locked
+		if opts.URL != "" && opts.URL != locked.URL {
+			return nil, fmt.Errorf("source %s is locked to %s; remove it from %s to change its URL", opts.Name, locked.URL, LockFile)
+		}
+		if opts.Strategy != "" && opts.Strategy != locked.Strategy {
+			return nil, fmt.Errorf("source %s is synced as a %s; remove it to switch to %s", opts.Name, locked.Strategy, opts.Strategy)
+		}
+		if opts.Path != "" && filepath.ToSlash(opts.Path) != locked.Path {
+			return nil, fmt.Errorf("source %s is synced at %s", opts.Name, locked.Path)
+		}
+		if opts.Ref != "" {
+			source.Ref = opts.Ref
+		}
+		return &source, nil
+	}
+
+	if opts.URL == "" {
+		return nil, fmt.Errorf("source %s is not in %s; pass its repository URL", opts.Name, LockFile)
+	}
+	source.URL = opts.URL
+	source.Ref = opts.Ref
+	source.Strategy = opts.Strategy
+	if source.Strategy == "" {
+		source.Strategy = StrategySubmodule
+	}
+	if source.Strategy != StrategySubmodule && source.Strategy != StrategySubtree {
+		return nil, fmt.Errorf("unknown sync strategy %q (valid: %s)", source.Strategy, strings.Join(Strategies, ", "))
+	}
+
+	source.Path = filepath.ToSlash(opts.Path)
+	if source.Path == "" {
+		source.Path = path.Join("templates", source.Name)
+	}
+	if path.IsAbs(source.Path) || strings.HasPrefix(path.Clean(source.Path), "..") {
+		return nil, fmt.Errorf("sync path %s must be inside the workspace", source.Path)
+	}
+	source.Path = path.Clean(source.Path)
+	if _, err := os.Stat(filepath.Join(s.root, source.Path)); err == nil {
+		return nil, fmt.Errorf("%s already exists and is not managed by gogo sync", source.Path)
+	}
+	return &source, nil
+}
+
+func (s *Syncer) syncSubmodule(ctx context.Context, source *Source) error {
+	dir := filepath.Join(s.root, filepath.FromSlash(source.Path))
+	if _, err := os.Stat(filepath.Join(s.root, ".gitmodules")); err == nil && exists(dir) {
+		if err := s.git.SubmoduleUpdate(ctx, source.Path); err != nil {
+			return err
+		}
+	} else if err := s.git.SubmoduleAdd(ctx, source.URL, source.Path, ""); err != nil {
+		return err
+	}
+
+	// Pin the submodule to the requested ref, or to the remote's default branch
+	submodule := git.NewGitManager(dir)
+	commit, err := submodule.Fetch(ctx, "origin", source.Ref)
+	if err != nil {
+		return err
+	}
+	if err := submodule.Checkout(ctx, commit); err != nil {
+		return err
+	}
+	if err := s.git.Add(ctx, ".gitmodules", source.Path); err != nil {
+		return fmt.Errorf("failed to stage submodule %s: %w", source.Path, err)
+	}
+
+	source.Commit = commit
+	return nil
+}
+
+func (s *Syncer) syncSubtree(ctx context.Context, source *Source) error {
+	commit, err := s.git.Fetch(ctx, source.URL, source.Ref)
+	if err != nil {
+		return err
+	}
+	if commit == source.Commit {
+		return nil
+	}
+
+	message := fmt.Sprintf("Sync %s at %s", source.Name, shortCommit(commit))
+	if exists(filepath.Join(s.root, filepath.FromSlash(source.Path))) {
+		err = s.git.SubtreeMerge(ctx, source.Path, commit, message)
+	} else {
+		err = s.git.SubtreeAdd(ctx, source.Path, commit, message)
+	}
+	if err != nil {
+		return err
+	}
+
+	source.Commit = commit
+	return nil
+}
+
+// repoName derives a source name from a repository URL
+func repoName(url string) string {
+	name := strings.TrimSuffix(strings.TrimSuffix(url, "/"), ".git")
+	if i := strings.LastIndexAny(name, "/:"); i >= 0 {
+		name = name[i+1:]
+	}
+	return name
+}
+
+func shortCommit(commit string) string {
+	if len(commit) > 12 {
+		return commit[:12]
+	}
+	return commit
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,154 @@
+package assetsync
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/user/gogo/internal/git"
+)
+
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(output))
+	return strings.TrimSpace(string(output))
+}
+
+// setupRepos creates a shared templates repository with a v1 tag and a
+// workspace repository to sync it into
+func setupRepos(t *testing.T) (shared, work string) {
+	t.Helper()
+	if !git.IsGitInstalled() {
+		t.Skip("Git is not installed")
+	}
+	// Local clones are used as submodule remotes
+	t.Setenv("GIT_CONFIG_COUNT", "1")
+	t.Setenv("GIT_CONFIG_KEY_0", "protocol.file.allow")
+	t.Setenv("GIT_CONFIG_VALUE_0", "always")
+	t.Setenv("GIT_AUTHOR_NAME", "Test Author")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Test Author")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+
+	base := t.TempDir()
+	shared = filepath.Join(base, "shared-templates")
+	work = filepath.Join(base, "work")
+	for _, dir := range []string{shared, work} {
+		require.NoError(t, os.MkdirAll(dir, 0755))
+		runGit(t, dir, "init", "-q", "-b", "main")
+	}
+
+	require.NoError(t, os.WriteFile(filepath.Join(shared, "handler.go.tmpl"), []byte("v1\n"), 0644))
+	runGit(t, shared, "add", ".")
+	runGit(t, shared, "commit", "-q", "-m", "v1")
+	runGit(t, shared, "tag", "v1")
+	require.NoError(t, os.WriteFile(filepath.Join(shared, "handler.go.tmpl"), []byte("v2\n"), 0644))
+	runGit(t, shared, "commit", "-q", "-am", "v2")
+
+	require.NoError(t, os.WriteFile(filepath.Join(work, "go.work"), []byte("go 1.25.1\n"), 0644))
+	runGit(t, work, "add", ".")
+	runGit(t, work, "commit", "-q", "-m", "workspace")
+	return shared, work
+}
+
+func TestSyncer_Submodule(t *testing.T) {
+	shared, work := setupRepos(t)
+	ctx := context.Background()
+
+	syncer, err := NewSyncer(ctx, work)
+	require.NoError(t, err)
+
+	source, err := syncer.Sync(ctx, Options{URL: shared, Ref: "v1", Strategy: StrategySubmodule})
+	require.NoError(t, err)
+	assert.Equal(t, "shared-templates", source.Name)
+	assert.Equal(t, "templates/shared-templates", source.Path)
+	assert.Equal(t, runGit(t, shared, "rev-parse", "v1"), source.Commit)
+
+	content, err := os.ReadFile(filepath.Join(work, "templates", "shared-templates", "handler.go.tmpl"))
+	require.NoError(t, err)
+	assert.Equal(t, "v1\n", string(content))
+
+	lock, err := LoadLock(work)
+	require.NoError(t, err)
+	require.Len(t, lock.Sources, 1)
+	assert.Equal(t, source.Commit, lock.Sources[0].Commit)
+
+	// Updating moves the pin to the new ref
+	source, err = syncer.Sync(ctx, Options{Name: "shared-templates", Ref: "main"})
+	require.NoError(t, err)
+	assert.Equal(t, runGit(t, shared, "rev-parse", "main"), source.Commit)
+	assert.Equal(t, StrategySubmodule, source.Strategy)
+
+	_, err = syncer.Sync(ctx, Options{Name: "shared-templates", Strategy: StrategySubtree})
+	assert.ErrorContains(t, err, "synced as a submodule")
+}
+
+func TestSyncer_Subtree(t *testing.T) {
+	shared, work := setupRepos(t)
+	ctx := context.Background()
+
+	syncer, err := NewSyncer(ctx, work)
+	require.NoError(t, err)
+
+	source, err := syncer.Sync(ctx, Options{URL: shared, Path: "vendor/templates", Ref: "v1", Strategy: StrategySubtree})
+	require.NoError(t, err)
+	assert.Equal(t, runGit(t, shared, "rev-parse", "v1"), source.Commit)
+
+	content, err := os.ReadFile(filepath.Join(work, "vendor", "templates", "handler.go.tmpl"))
+	require.NoError(t, err)
+	assert.Equal(t, "v1\n", string(content))
+
+	assert.Empty(t, runGit(t, work, "status", "--porcelain"), "the lockfile is committed with the subtree")
+
+	synced, err := syncer.SyncAll(ctx)
+	require.NoError(t, err)
+	require.Len(t, synced, 1)
+	assert.Equal(t, source.Commit, synced[0].Commit, "SyncAll keeps the locked ref")
+	assert.Empty(t, runGit(t, work, "status", "--porcelain"), "re-syncing an unchanged pin is a no-op")
+
+	source, err = syncer.Sync(ctx, Options{Name: source.Name, Ref: "main"})
+	require.NoError(t, err)
+	assert.Equal(t, runGit(t, shared, "rev-parse", "main"), source.Commit)
+
+	content, err = os.ReadFile(filepath.Join(work, "vendor", "templates", "handler.go.tmpl"))
+	require.NoError(t, err)
+	assert.Equal(t, "v2\n", string(content))
+}
+
+func TestSyncer_Resolve(t *testing.T) {
+	syncer := &Syncer{root: t.TempDir()}
+	lock := &Lock{Sources: []Source{{Name: "shared", URL: "https://example.com/shared.git", Path: "templates/shared", Strategy: StrategySubmodule}}}
+
+	tests := []struct {
+		name    string
+		opts    Options
+		wantErr string
+	}{
+		{name: "unknown strategy", opts: Options{URL: "https://example.com/other.git", Strategy: "copy"}, wantErr: "unknown sync strategy"},
+		{name: "path outside workspace", opts: Options{URL: "https://example.com/other.git", Path: "../other"}, wantErr: "inside the workspace"},
+		{name: "unknown name without URL", opts: Options{Name: "other"}, wantErr: "pass its repository URL"},
+		{name: "changed URL", opts: Options{Name: "shared", URL: "https://example.com/fork.git"}, wantErr: "locked to"},
+		{name: "locked source", opts: Options{Name: "shared", Ref: "v2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source, err := syncer.resolve(lock, tt.opts)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "v2", source.Ref)
+			assert.Equal(t, "templates/shared", source.Path)
+		})
+	}
+}
@@ -0,0 +1,143 @@
+// Package auth resolves credentials for git hosts and registries. Tokens are
+// looked up in the environment, then the OS keychain, then git credential
+// helpers; gogo never writes secrets to its own config files.
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// DefaultHost is the host used when none is given
+const DefaultHost = "github.com"
+
+// Credential sources, in precedence order
+const (
+	SourceEnv           = "env"
+	SourceKeychain      = "keychain"
+	SourceGitCredential = "git-credential"
+)
+
+// ErrNotFound is returned by a store that has no credential for a host
+var ErrNotFound = errors.New("no credential found")
+
+// ErrUnavailable is returned by a store that cannot be used on this system
+var ErrUnavailable = errors.New("credential store unavailable")
+
+// Credential is a token for a host
+type Credential struct {
+	Host     string
+	Username string
+	Token    string
+	Source   string // where the credential was found
+	Detail   string // e.g. the environment variable it came from
+}
+
+// Masked returns the token with all but its last four characters hidden
+func (c Credential) Masked() string {
+	if len(c.Token) <= 8 {
+		return strings.Repeat("*", len(c.Token))
+	}
+	return strings.Repeat("*", 8) + c.Token[len(c.Token)-4:]
+}
+
+// Store is a place credentials are read from
+type Store interface {
+	Name() string
+	Get(ctx context.Context, host string) (*Credential, error)
+}
+
+// WritableStore is a store gogo auth login can save credentials to
+type WritableStore interface {
+	Store
+	Set(ctx context.Context, cred Credential) error
+}
+
+// Resolver looks credentials up in its stores in order; the first store
+// with a credential wins
+type Resolver struct {
+	stores []Store
+}
+
+// NewResolver creates a resolver with the default precedence: environment,
+// OS keychain, git credential helpers
+func NewResolver() *Resolver {
+	return NewResolverWithStores(NewEnvStore(), NewKeychain(), NewGitCredentialStore())
+}
+
+// NewResolverWithStores creates a resolver consulting stores in order
+func NewResolverWithStores(stores ...Store) *Resolver {
+	return &Resolver{stores: stores}
+}
+
+// Resolve returns the highest-precedence credential for host
+func (r *Resolver) Resolve(ctx context.Context, host string) (*Credential, error) {
+	for _, store := range r.stores {
+		cred, err := store.Get(ctx, host)
+		if err == nil {
+			return cred, nil
+		}
+		if !errors.Is(err, ErrNotFound) && !errors.Is(err, ErrUnavailable) {
+			return nil, fmt.Errorf("%s: %w", store.Name(), err)
+		}
+	}
+	return nil, fmt.Errorf("%w for %s (run gogo auth login --host %s)", ErrNotFound, host, host)
+}
+
+// Token returns the token for host, or "" when there is none; it suits
+// callers for which credentials are optional
+func (r *Resolver) Token(ctx context.Context, host string) string {
+	cred, err := r.Resolve(ctx, host)
+	if err != nil {
+		return ""
+	}
+	return cred.Token
+}
+
+// StoreStatus is what one store holds for a host
+type StoreStatus struct {
+	Store      string
+	Credential *Credential
+	Err        error // ErrNotFound, ErrUnavailable or a lookup failure
+	Active     bool  // this credential is the one Resolve returns
+}
+
+// Status reports every store's credential for host in precedence order
+func (r *Resolver) Status(ctx context.Context, host string) []StoreStatus {
+	statuses := make([]StoreStatus, 0, len(r.stores))
+	active := false
+	for _, store := range r.stores {
+		cred, err := store.Get(ctx, host)
+		status := StoreStatus{Store: store.Name(), Credential: cred, Err: err}
+		if err == nil && !active {
+			status.Active = true
+			active = true
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// Login saves cred to the first writable store available on this system
+// and returns that store's name
+func (r *Resolver) Login(ctx context.Context, cred Credential) (string, error) {
+	if cred.Host == "" || cred.Token == "" {
+		return "", fmt.Errorf("a host and token are required")
+	}
+
+	var failures []error
+	for _, store := range r.stores {
+		writable, ok := store.(WritableStore)
+		if !ok {
+			continue
+		}
+		err := writable.Set(ctx, cred)
+		if err == nil {
+			return store.Name(), nil
+		}
+		failures = append(failures, fmt.Errorf("%s: %w", store.Name(), err))
+	}
+	return "", fmt.Errorf("no credential store could save the token; set %s instead: %w", EnvVar(cred.Host), errors.Join(failures...))
+}
@@ -0,0 +1,139 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryStore is an in-memory credential store
+type memoryStore struct {
+	name  string
+	creds map[string]string
+	err   error
+}
+
+func (m *memoryStore) Name() string { return m.name }
+
+func (m *memoryStore) Get(ctx context.Context, host string) (*Credential, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	token, ok := m.creds[host]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &Credential{Host: host, Token: token, Source: m.name}, nil
+}
+
+// writableMemoryStore is a memoryStore gogo auth login can write to
+type writableMemoryStore struct{ *memoryStore }
+
+func (m writableMemoryStore) Set(ctx context.Context, cred Credential) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.creds[cred.Host] = cred.Token
+	return nil
+}
+
+func TestResolver_Precedence(t *testing.T) {
+	ctx := context.Background()
+	env := &EnvStore{lookup: func(name string) (string, bool) {
+		if name == "GH_TOKEN" {
+			return "env-token", true
+		}
+		return "", false
+	}}
+	keychain := &memoryStore{name: SourceKeychain, creds: map[string]string{"github.com": "keychain-token", "gitlab.com": "keychain-gitlab"}}
+	helper := &memoryStore{name: SourceGitCredential, err: ErrUnavailable}
+	resolver := NewResolverWithStores(env, keychain, helper)
+
+	cred, err := resolver.Resolve(ctx, "github.com")
+	require.NoError(t, err)
+	assert.Equal(t, "env-token", cred.Token)
+	assert.Equal(t, "GH_TOKEN", cred.Detail)
+
+	assert.Equal(t, "keychain-gitlab", resolver.Token(ctx, "gitlab.com"))
+
+	_, err = resolver.Resolve(ctx, "example.com")
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.Empty(t, resolver.Token(ctx, "example.com"))
+
+	statuses := resolver.Status(ctx, "github.com")
+	require.Len(t, statuses, 3)
+	assert.True(t, statuses[0].Active)
+	assert.False(t, statuses[1].Active, "the keychain token is shadowed by the environment")
+	assert.NotNil(t, statuses[1].Credential)
+	assert.ErrorIs(t, statuses[2].Err, ErrUnavailable)
+}
+
+func TestResolver_LookupFailure(t *testing.T) {
+	broken := &memoryStore{name: SourceKeychain, err: errors.New("keychain locked")}
+	_, err := NewResolverWithStores(broken).Resolve(context.Background(), "github.com")
+	assert.ErrorContains(t, err, "keychain locked")
+}
+
+func TestResolver_Login(t *testing.T) {
+	ctx := context.Background()
+	keychain := writableMemoryStore{&memoryStore{name: SourceKeychain, err: ErrUnavailable, creds: map[string]string{}}}
+	helper := writableMemoryStore{&memoryStore{name: SourceGitCredential, creds: map[string]string{}}}
+	resolver := NewResolverWithStores(NewEnvStore(), keychain, helper)
+
+	store, err := resolver.Login(ctx, Credential{Host: "github.com", Token: "secret"})
+	require.NoError(t, err)
+	assert.Equal(t, SourceGitCredential, store, "falls back when the keychain is unavailable")
+	assert.Equal(t, "secret", helper.creds["github.com"])
+
+	_, err = NewResolverWithStores(NewEnvStore(), keychain).Login(ctx, Credential{Host: "github.com", Token: "secret"})
+	assert.ErrorContains(t, err, "GOGO_TOKEN_GITHUB_COM")
+
+	_, err = resolver.Login(ctx, Credential{Host: "github.com"})
+	assert.Error(t, err)
+}
+
+func TestEnvVar(t *testing.T) {
+	assert.Equal(t, "GOGO_TOKEN_GITHUB_COM", EnvVar("github.com"))
+	assert.Equal(t, "GOGO_TOKEN_GIT_EXAMPLE_COM_8443", EnvVar("git.example.com:8443"))
+}
+
+func TestCredential_Masked(t *testing.T) {
+	assert.Equal(t, "********wxyz", Credential{Token: "ghp_abcdefghijklmnopqrstuvwxyz"}.Masked())
+	assert.Equal(t, "*****", Credential{Token: "short"}.Masked())
+}
+
+func TestGitCredentialStore(t *testing.T) {
+	store := &GitCredentialStore{}
+	if _, err := store.Get(context.Background(), "probe.invalid"); errors.Is(err, ErrUnavailable) {
+		t.Skip("git is not installed")
+	}
+
+	// Isolate git from the user's configuration and use a file-backed helper
+	credentials := filepath.Join(t.TempDir(), "credentials")
+	store.env = []string{
+		"GIT_CONFIG_NOSYSTEM=1",
+		"GIT_CONFIG_GLOBAL=/dev/null",
+		"GIT_CONFIG_COUNT=1",
+		"GIT_CONFIG_KEY_0=credential.helper",
+		"GIT_CONFIG_VALUE_0=store --file " + credentials,
+	}
+	ctx := context.Background()
+
+	_, err := store.Get(ctx, "git.example.com")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	require.NoError(t, store.Set(ctx, Credential{Host: "git.example.com", Token: "helper-token"}))
+	cred, err := store.Get(ctx, "git.example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "helper-token", cred.Token)
+	assert.Equal(t, "x-access-token", cred.Username)
+
+	// Without a helper the token would be silently dropped
+	store.env = []string{"GIT_CONFIG_NOSYSTEM=1", "GIT_CONFIG_GLOBAL=/dev/null"}
+	err = store.Set(ctx, Credential{Host: "git.example.com", Token: "dropped"})
+	assert.ErrorIs(t, err, ErrUnavailable)
+}
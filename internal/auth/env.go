@@ -0,0 +1,55 @@
+package auth
+
+import (
+	"context"
+	"os"
+	"strings"
+)
+
+// wellKnownEnv lists the environment variables other tools already use for
+// a host, checked after gogo's own variable
+var wellKnownEnv = map[string][]string{
+	"github.com": {"GH_TOKEN", "GITHUB_TOKEN"},
+	"gitlab.com": {"GITLAB_TOKEN"},
+}
+
+// EnvVar returns gogo's environment variable for host's token, e.g.
+// GOGO_TOKEN_GITHUB_COM for github.com
+func EnvVar(host string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		}
+		return '_'
+	}, host)
+	return "GOGO_TOKEN_" + name
+}
+
+// EnvStore reads tokens from GOGO_TOKEN_<HOST> and the variables other
+// tools use for well-known hosts, such as GH_TOKEN for github.com
+type EnvStore struct {
+	lookup func(string) (string, bool)
+}
+
+// NewEnvStore creates a store reading the process environment
+func NewEnvStore() *EnvStore {
+	return &EnvStore{lookup: os.LookupEnv}
+}
+
+// Name returns the store name
+func (s *EnvStore) Name() string {
+	return SourceEnv
+}
+
+// Get returns the token for host from the environment
+func (s *EnvStore) Get(ctx context.Context, host string) (*Credential, error) {
+	for _, name := range append([]string{EnvVar(host)}, wellKnownEnv[host]...) {
+		if token, ok := s.lookup(name); ok && strings.TrimSpace(token) != "" {
+			return &Credential{Host: host, Token: strings.TrimSpace(token), Source: SourceEnv, Detail: name}, nil
+		}
+	}
+	return nil, ErrNotFound
+}
@@ -0,0 +1,101 @@
+package auth
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// GitCredentialStore reads and writes tokens through the user's configured
+// git credential helpers (git credential fill/approve), so tokens already
+// known to git work for gogo too
+type GitCredentialStore struct {
+	env []string // extra environment for git, used by tests
+}
+
+// NewGitCredentialStore creates a store backed by git credential helpers
+func NewGitCredentialStore() *GitCredentialStore {
+	return &GitCredentialStore{}
+}
+
+// Name returns the store name
+func (s *GitCredentialStore) Name() string {
+	return SourceGitCredential
+}
+
+// Get asks the git credential helpers for host's credential without ever
+// prompting on the terminal
+func (s *GitCredentialStore) Get(ctx context.Context, host string) (*Credential, error) {
+	if _, err := exec.LookPath("git"); err != nil {
+		return nil, fmt.Errorf("%w: git not found", ErrUnavailable)
+	}
+
+	output, err := s.run(ctx, "fill", credentialInput(Credential{Host: host}))
+	if err != nil {
+		// git credential fill fails when no helper has the credential and prompting is disabled
+		return nil, ErrNotFound
+	}
+
+	cred := &Credential{Host: host, Source: SourceGitCredential}
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "username":
+			cred.Username = value
+		case "password":
+			cred.Token = value
+		}
+	}
+	if cred.Token == "" {
+		return nil, ErrNotFound
+	}
+	return cred, nil
+}
+
+// Set saves the credential with git credential approve. Without a
+// configured helper git silently discards it, so the save is verified.
+func (s *GitCredentialStore) Set(ctx context.Context, cred Credential) error {
+	if _, err := exec.LookPath("git"); err != nil {
+		return fmt.Errorf("%w: git not found", ErrUnavailable)
+	}
+	if cred.Username == "" {
+		cred.Username = "x-access-token"
+	}
+
+	if _, err := s.run(ctx, "approve", credentialInput(cred)); err != nil {
+		return fmt.Errorf("git credential approve failed: %w", err)
+	}
+	saved, err := s.Get(ctx, cred.Host)
+	if err != nil || saved.Token != cred.Token {
+		return fmt.Errorf("%w: no git credential helper is configured (see git help credentials)", ErrUnavailable)
+	}
+	return nil
+}
+
+func (s *GitCredentialStore) run(ctx context.Context, action, input string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "git", "credential", action)
+	cmd.Stdin = strings.NewReader(input)
+	cmd.Env = append(append(os.Environ(), "GIT_TERMINAL_PROMPT=0", "GIT_ASKPASS=", "SSH_ASKPASS="), s.env...)
+	return cmd.Output()
+}
+
+func credentialInput(cred Credential) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "protocol=https\nhost=%s\n", cred.Host)
+	if cred.Username != "" {
+		fmt.Fprintf(&b, "username=%s\n", cred.Username)
+	}
+	if cred.Token != "" {
+		fmt.Fprintf(&b, "password=%s\n", cred.Token)
+	}
+	b.WriteString("\n")
+	return b.String()
+}
@@ -0,0 +1,98 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// keychainService is the service name gogo's keychain entries are filed under
+const keychainService = "gogo"
+
+// Keychain stores tokens in the OS keychain: the macOS login keychain via
+// security(1), or the Secret Service (GNOME Keyring, KWallet) via
+// secret-tool(1) on Linux. Other systems report ErrUnavailable.
+type Keychain struct {
+	goos string
+}
+
+// NewKeychain creates a keychain store for the current OS
+func NewKeychain() *Keychain {
+	return &Keychain{goos: runtime.GOOS}
+}
+
+// Name returns the store name
+func (k *Keychain) Name() string {
+	return SourceKeychain
+}
+
+// tool returns the keychain command for this OS, if it is installed
+func (k *Keychain) tool() (string, error) {
+	var tool string
+	switch k.goos {
+	case "darwin":
+		tool = "security"
+	case "linux", "freebsd", "openbsd":
+		tool = "secret-tool"
+	default:
+		return "", fmt.Errorf("%w: no keychain support on %s", ErrUnavailable, k.goos)
+	}
+	if _, err := exec.LookPath(tool); err != nil {
+		return "", fmt.Errorf("%w: %s not found", ErrUnavailable, tool)
+	}
+	return tool, nil
+}
+
+// Get returns the token stored for host
+func (k *Keychain) Get(ctx context.Context, host string) (*Credential, error) {
+	tool, err := k.tool()
+	if err != nil {
+		return nil, err
+	}
+
+	var cmd *exec.Cmd
+	if tool == "security" {
+		cmd = exec.CommandContext(ctx, tool, "find-generic-password", "-s", keychainService, "-a", host, "-w")
+	} else {
+		cmd = exec.CommandContext(ctx, tool, "lookup", "service", keychainService, "host", host)
+	}
+	output, err := cmd.Output()
+	token := strings.TrimSpace(string(output))
+	if err != nil || token == "" {
+		var exitErr *exec.ExitError
+		if err == nil || errors.As(err, &exitErr) {
+			// Both tools exit non-zero when nothing matches
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	return &Credential{Host: host, Token: token, Source: SourceKeychain}, nil
+}
+
+// Set stores the token for cred.Host, replacing any existing entry
+func (k *Keychain) Set(ctx context.Context, cred Credential) error {
+	tool, err := k.tool()
+	if err != nil {
+		return err
+	}
+
+	var cmd *exec.Cmd
+	if tool == "security" {
+		// -w last without a value makes security read the password from the
+		// terminal, so pass it explicitly; -U updates an existing entry
+		cmd = exec.CommandContext(ctx, tool, "add-generic-password", "-U", "-s", keychainService, "-a", cred.Host, "-l", "gogo "+cred.Host, "-w", cred.Token)
+	} else {
+		cmd = exec.CommandContext(ctx, tool, "store", "--label", "gogo "+cred.Host, "service", keychainService, "host", cred.Host)
+		cmd.Stdin = strings.NewReader(cred.Token)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to store token in keychain: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
@@ -2,26 +2,39 @@ package blueprints
 
 import (
 	"context"
+	"embed"
+	"errors"
 	"fmt"
+	"io/fs"
+	"slices"
+	"sort"
+
+	"github.com/user/gogo/internal/db"
+	"gopkg.in/yaml.v3"
 )
 
 // BlueprintConfig represents the configuration for a blueprint
 type BlueprintConfig struct {
-	Components    []string       `json:"components"`
-	Database      map[string]any `json:"database,omitempty"`
-	Observability map[string]any `json:"observability,omitempty"`
-	Testing       map[string]any `json:"testing,omitempty"`
-	CI            map[string]any `json:"ci,omitempty"`
-	Docker        map[string]any `json:"docker,omitempty"`
-	Extra         map[string]any `json:"extra,omitempty"`
+	Components    []string       `json:"components" yaml:"components"`
+	Database      map[string]any `json:"database,omitempty" yaml:"database,omitempty"`
+	Observability map[string]any `json:"observability,omitempty" yaml:"observability,omitempty"`
+	Testing       map[string]any `json:"testing,omitempty" yaml:"testing,omitempty"`
+	CI            map[string]any `json:"ci,omitempty" yaml:"ci,omitempty"`
+	Docker        map[string]any `json:"docker,omitempty" yaml:"docker,omitempty"`
+	Kubernetes    map[string]any `json:"kubernetes,omitempty" yaml:"kubernetes,omitempty"`
+	Environments  []string       `json:"environments,omitempty" yaml:"environments,omitempty"`
+	Deployment    map[string]any `json:"deployment,omitempty" yaml:"deployment,omitempty"`
+	Extra         map[string]any `json:"extra,omitempty" yaml:"extra,omitempty"`
 }
 
 // Blueprint represents a stack blueprint
 type Blueprint struct {
-	ID     int             `json:"id"`
-	Name   string          `json:"name"`
-	Stack  string          `json:"stack"`
-	Config BlueprintConfig `json:"config"`
+	ID          int             `json:"id" yaml:"id"`
+	Name        string          `json:"name" yaml:"name"`
+	Stack       string          `json:"stack" yaml:"stack"`
+	Description string          `json:"description,omitempty" yaml:"description,omitempty"`
+	NextSteps   string          `json:"next_steps,omitempty" yaml:"next_steps,omitempty"` // Shown after gogo init; rendered with the project variables
+	Config      BlueprintConfig `json:"config" yaml:"config"`
 }
 
 // BlueprintResolver interface for resolving blueprint variables
@@ -49,6 +62,8 @@ func (r *Resolver) Resolve(ctx context.Context, blueprint Blueprint, inputs map[
 	if len(blueprint.Config.Components) > 0 {
 		result["Components"] = blueprint.Config.Components
 	}
+	// The swagger component serves the OpenAPI specification with Swagger UI
+	result["HasSwagger"] = slices.Contains(blueprint.Config.Components, "swagger")
 
 	// Process database configuration
 	if len(blueprint.Config.Database) > 0 {
@@ -76,6 +91,9 @@ func (r *Resolver) Resolve(ctx context.Context, blueprint Blueprint, inputs map[
 			result["HasTracing"] = true
 			result["TracingType"] = tracing
 		}
+		if audit, ok := blueprint.Config.Observability["audit"]; ok && audit == true {
+			result["HasAudit"] = true
+		}
 	}
 
 	// Process testing configuration
@@ -90,6 +108,9 @@ func (r *Resolver) Resolve(ctx context.Context, blueprint Blueprint, inputs map[
 		if coverageMin, ok := blueprint.Config.CI["coverage_min"]; ok {
 			result["CoverageMin"] = coverageMin
 		}
+		if exclude, ok := blueprint.Config.CI["coverage_exclude"]; ok {
+			result["CoverageExclude"] = exclude
+		}
 	}
 
 	// Process Docker configuration
@@ -100,6 +121,19 @@ func (r *Resolver) Resolve(ctx context.Context, blueprint Blueprint, inputs map[
 		}
 	}
 
+	// Process Kubernetes configuration
+	result["HasKubernetes"] = len(blueprint.Config.Kubernetes) > 0
+
+	// Process deployment environments
+	if len(blueprint.Config.Environments) > 0 {
+		result["Environments"] = blueprint.Config.Environments
+	}
+
+	// Process deployment targets (systemd, windows-service, kubernetes, helm)
+	if target, ok := blueprint.Config.Deployment["target"]; ok {
+		result["DeploymentTarget"] = target
+	}
+
 	// Add any extra configuration
 	if len(blueprint.Config.Extra) > 0 {
 		for k, v := range blueprint.Config.Extra {
@@ -110,9 +144,16 @@ func (r *Resolver) Resolve(ctx context.Context, blueprint Blueprint, inputs map[
 	return result, nil
 }
 
-// Repository manages blueprint storage and retrieval
+// ErrNoStore is returned when changing blueprints of a repository without a database
+var ErrNoStore = errors.New("blueprint repository has no database")
+
+// Repository manages blueprint storage and retrieval. Predefined blueprints
+// are compiled in; a repository created with NewStoredRepository keeps custom
+// blueprints in the blueprints table, where they take precedence over
+// predefined blueprints of the same name.
 type Repository struct {
 	blueprints map[string]Blueprint
+	store      *db.BlueprintManager
 }
 
 // NewRepository creates a new blueprint repository
@@ -124,8 +165,26 @@ func NewRepository() *Repository {
 	return repo
 }
 
+// NewStoredRepository creates a blueprint repository persisting custom
+// blueprints in the gogo database
+func NewStoredRepository(manager *db.Manager) *Repository {
+	repo := NewRepository()
+	repo.store = db.NewBlueprintManager(manager)
+	return repo
+}
+
 // GetBlueprint retrieves a blueprint by name or ID
 func (r *Repository) GetBlueprint(ctx context.Context, nameOrID string) (Blueprint, error) {
+	if r.store != nil {
+		stored, err := r.store.Get(ctx, nameOrID)
+		if err == nil {
+			return FromStored(stored)
+		}
+		if !errors.Is(err, db.ErrBlueprintNotFound) {
+			return Blueprint{}, err
+		}
+	}
+
 	blueprint, exists := r.blueprints[nameOrID]
 	if !exists {
 		return Blueprint{}, fmt.Errorf("blueprint '%s' not found", nameOrID)
@@ -133,19 +192,101 @@ func (r *Repository) GetBlueprint(ctx context.Context, nameOrID string) (Bluepri
 	return blueprint, nil
 }
 
-// ListBlueprints returns all blueprints
+// IsPredefined reports whether name is a blueprint compiled into gogo. The
+// predefined blueprint may be overridden by a stored one.
+func (r *Repository) IsPredefined(name string) bool {
+	_, ok := r.blueprints[name]
+	return ok
+}
+
+// Create validates and stores a new custom blueprint
+func (r *Repository) Create(ctx context.Context, blueprint Blueprint) error {
+	if r.store == nil {
+		return ErrNoStore
+	}
+	if err := NewValidator().Validate(blueprint); err != nil {
+		return fmt.Errorf("invalid blueprint '%s': %w", blueprint.Name, err)
+	}
+	stored, err := ToStored(blueprint)
+	if err != nil {
+		return err
+	}
+	return r.store.Create(ctx, stored)
+}
+
+// Update validates a custom blueprint and replaces the stored one
+func (r *Repository) Update(ctx context.Context, blueprint Blueprint) error {
+	if r.store == nil {
+		return ErrNoStore
+	}
+	if err := NewValidator().Validate(blueprint); err != nil {
+		return fmt.Errorf("invalid blueprint '%s': %w", blueprint.Name, err)
+	}
+	stored, err := ToStored(blueprint)
+	if err != nil {
+		return err
+	}
+	err = r.store.Update(ctx, stored)
+	if errors.Is(err, db.ErrBlueprintNotFound) && r.IsPredefined(blueprint.Name) {
+		return fmt.Errorf("blueprint '%s' is predefined; create a custom blueprint to override it", blueprint.Name)
+	}
+	return err
+}
+
+// Delete moves a custom blueprint to the trash. Predefined blueprints cannot
+// be deleted, but deleting a custom blueprint that overrides one restores it.
+func (r *Repository) Delete(ctx context.Context, name string) error {
+	if r.store == nil {
+		return ErrNoStore
+	}
+	err := r.store.Delete(ctx, name)
+	if errors.Is(err, db.ErrBlueprintNotFound) && r.IsPredefined(name) {
+		return fmt.Errorf("blueprint '%s' is predefined and cannot be deleted", name)
+	}
+	return err
+}
+
+// Register adds a blueprint, replacing a predefined one of the same name
+func (r *Repository) Register(blueprint Blueprint) {
+	r.blueprints[blueprint.Name] = blueprint
+}
+
+// ListBlueprints returns all blueprints by name
 func (r *Repository) ListBlueprints(ctx context.Context) ([]Blueprint, error) {
-	blueprints := make([]Blueprint, 0, len(r.blueprints))
-	for _, blueprint := range r.blueprints {
+	all := make(map[string]Blueprint, len(r.blueprints))
+	for name, blueprint := range r.blueprints {
+		all[name] = blueprint
+	}
+	if r.store != nil {
+		stored, err := r.store.List(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range stored {
+			blueprint, err := FromStored(entry)
+			if err != nil {
+				return nil, err
+			}
+			all[blueprint.Name] = blueprint
+		}
+	}
+
+	blueprints := make([]Blueprint, 0, len(all))
+	for _, blueprint := range all {
 		blueprints = append(blueprints, blueprint)
 	}
+	sort.Slice(blueprints, func(i, j int) bool { return blueprints[i].Name < blueprints[j].Name })
 	return blueprints, nil
 }
 
 // GetBlueprintsByStack returns blueprints for a specific stack
 func (r *Repository) GetBlueprintsByStack(ctx context.Context, stack string) ([]Blueprint, error) {
+	all, err := r.ListBlueprints(ctx)
+	if err != nil {
+		return nil, err
+	}
 	var blueprints []Blueprint
-	for _, blueprint := range r.blueprints {
+	for _, blueprint := range all {
 		if blueprint.Stack == stack {
 			blueprints = append(blueprints, blueprint)
 		}
@@ -155,103 +296,52 @@ func (r *Repository) GetBlueprintsByStack(ctx context.Context, stack string) ([]
 
 // initPredefinedBlueprints initializes predefined blueprints
 func (r *Repository) initPredefinedBlueprints() {
-	// Web stack blueprint
-	r.blueprints["web-stack"] = Blueprint{
-		ID:    1,
-		Name:  "web-stack",
-		Stack: "web",
-		Config: BlueprintConfig{
-			Components: []string{"gin", "gorm", "viper"},
-			Database: map[string]any{
-				"type":       "postgres",
-				"migrations": "goose",
-			},
-			Observability: map[string]any{
-				"prometheus": true,
-				"logging":    "slog",
-			},
-			Testing: map[string]any{
-				"framework": "testify",
-			},
-			CI: map[string]any{
-				"coverage_min": 0.80,
-			},
-			Docker: map[string]any{
-				"base_image": "golang:1.25.1",
-				"expose":     8080,
-			},
-		},
-	}
-
-	// CLI stack blueprint
-	r.blueprints["cli-stack"] = Blueprint{
-		ID:    2,
-		Name:  "cli-stack",
-		Stack: "cli",
-		Config: BlueprintConfig{
-			Components: []string{"cobra", "viper"},
-			Testing: map[string]any{
-				"framework": "testify",
-			},
-			CI: map[string]any{
-				"coverage_min": 0.75,
-			},
-		},
-	}
-
-	// gRPC stack blueprint
-	r.blueprints["grpc-stack"] = Blueprint{
-		ID:    3,
-		Name:  "grpc-stack",
-		Stack: "grpc",
-		Config: BlueprintConfig{
-			Components: []string{"grpc", "protobuf"},
-			Observability: map[string]any{
-				"tracing": "jaeger",
-				"logging": "slog",
-			},
-			Testing: map[string]any{
-				"framework": "testify",
-			},
-			CI: map[string]any{
-				"coverage_min": 0.80,
-			},
-			Docker: map[string]any{
-				"base_image": "golang:1.25.1",
-				"expose":     50051,
-			},
-		},
-	}
-
-	// Microservice stack blueprint
-	r.blueprints["microservice-stack"] = Blueprint{
-		ID:    4,
-		Name:  "microservice-stack",
-		Stack: "microservice",
-		Config: BlueprintConfig{
-			Components: []string{"gin", "prometheus", "jaeger"},
-			Database: map[string]any{
-				"type":       "postgres",
-				"migrations": "goose",
-			},
-			Observability: map[string]any{
-				"prometheus": true,
-				"tracing":    "jaeger",
-				"logging":    "slog",
-				"health":     true,
-			},
-			Testing: map[string]any{
-				"framework": "testify",
-			},
-			CI: map[string]any{
-				"coverage_min": 0.85,
-			},
-			Docker: map[string]any{
-				"base_image":   "golang:1.25.1",
-				"expose":       8080,
-				"health_check": true,
-				"multi_stage":  true,
-			},
-		},
+	defaults, err := Defaults()
+	if err != nil {
+		// The defaults are compiled in and checked by tests and gogo assets verify
+		panic(err)
+	}
+	for _, blueprint := range defaults {
+		r.blueprints[blueprint.Name] = blueprint
 	}
 }
+
+// defaultsFS holds the predefined blueprints, one YAML file each
+//
+//go:embed defaults/*.yaml
+var defaultsFS embed.FS
+
+// DefaultsFS returns the embedded predefined blueprint files
+func DefaultsFS() fs.FS {
+	sub, err := fs.Sub(defaultsFS, "defaults")
+	if err != nil {
+		panic(err) // the directory is part of the embed pattern
+	}
+	return sub
+}
+
+// Defaults parses the embedded predefined blueprints in file name order
+func Defaults() ([]Blueprint, error) {
+	fsys := DefaultsFS()
+	files, err := fs.Glob(fsys, "*.yaml")
+	if err != nil {
+		return nil, err
+	}
+
+	blueprints := make([]Blueprint, 0, len(files))
+	for _, file := range files {
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read blueprint %s: %w", file, err)
+		}
+		var blueprint Blueprint
+		if err := yaml.Unmarshal(data, &blueprint); err != nil {
+			return nil, fmt.Errorf("failed to parse blueprint %s: %w", file, err)
+		}
+		if blueprint.Name+".yaml" != file {
+			return nil, fmt.Errorf("blueprint %s is named %q", file, blueprint.Name)
+		}
+		blueprints = append(blueprints, blueprint)
+	}
+	return blueprints, nil
+}
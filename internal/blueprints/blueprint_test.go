@@ -2,10 +2,12 @@ package blueprints
 
 import (
 	"context"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/user/gogo/internal/db"
 )
 
 func TestBlueprint_Resolve(t *testing.T) {
@@ -58,6 +60,29 @@ func TestBlueprint_Resolve(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "audit and environments",
+			blueprint: Blueprint{
+				Name:  "audited",
+				Stack: "microservice",
+				Config: BlueprintConfig{
+					Observability: map[string]any{
+						"audit": true,
+					},
+					Kubernetes: map[string]any{
+						"replicas": map[string]any{"prod": 3},
+					},
+					Environments: []string{"dev", "prod"},
+				},
+			},
+			inputs: map[string]any{},
+			expected: map[string]any{
+				"HasAudit":      true,
+				"HasKubernetes": true,
+				"Environments":  []string{"dev", "prod"},
+			},
+			wantErr: false,
+		},
 		{
 			name: "cli stack blueprint",
 			blueprint: Blueprint{
@@ -167,6 +192,18 @@ func TestBlueprintRepository_GetBlueprint(t *testing.T) {
 			expectFound: true,
 			expectStack: "microservice",
 		},
+		{
+			name:        "graphql stack blueprint",
+			blueprintID: "graphql-stack",
+			expectFound: true,
+			expectStack: "graphql",
+		},
+		{
+			name:        "worker stack blueprint",
+			blueprintID: "worker-stack",
+			expectFound: true,
+			expectStack: "worker",
+		},
 		{
 			name:        "non-existent blueprint",
 			blueprintID: "nonexistent",
@@ -201,7 +238,7 @@ func TestBlueprintRepository_ListBlueprints(t *testing.T) {
 	assert.GreaterOrEqual(t, len(blueprints), 4)
 
 	// Verify expected blueprints are present
-	expectedBlueprints := []string{"web-stack", "cli-stack", "grpc-stack", "microservice-stack"}
+	expectedBlueprints := []string{"web-stack", "cli-stack", "grpc-stack", "microservice-stack", "graphql-stack", "worker-stack"}
 	actualNames := make([]string, len(blueprints))
 	for i, bp := range blueprints {
 		actualNames[i] = bp.Name
@@ -240,6 +277,18 @@ func TestBlueprintRepository_GetBlueprintsByStack(t *testing.T) {
 			expectCount: 1,
 			expectNames: []string{"grpc-stack"},
 		},
+		{
+			name:        "graphql stack blueprints",
+			stack:       "graphql",
+			expectCount: 1,
+			expectNames: []string{"graphql-stack"},
+		},
+		{
+			name:        "worker stack blueprints",
+			stack:       "worker",
+			expectCount: 1,
+			expectNames: []string{"worker-stack"},
+		},
 		{
 			name:        "nonexistent stack",
 			stack:       "nonexistent",
@@ -268,3 +317,95 @@ func TestBlueprintRepository_GetBlueprintsByStack(t *testing.T) {
 		})
 	}
 }
+
+func BenchmarkResolver_Resolve(b *testing.B) {
+	ctx := context.Background()
+	blueprint, err := NewRepository().GetBlueprint(ctx, "microservice-stack")
+	require.NoError(b, err)
+	resolver := NewResolver()
+	inputs := map[string]any{"ProjectName": "bench", "ModuleName": "github.com/user/bench"}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := resolver.Resolve(ctx, blueprint, inputs); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestParse_StoredRoundTrip(t *testing.T) {
+	blueprint, err := Parse([]byte(`
+name: queue-stack
+stack: microservice
+config:
+  components: [nats, redis]
+  deployment:
+    target: systemd
+`))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"nats", "redis"}, blueprint.Config.Components)
+
+	stored, err := ToStored(blueprint)
+	require.NoError(t, err)
+	loaded, err := FromStored(stored)
+	require.NoError(t, err)
+	assert.Equal(t, blueprint, loaded)
+
+	_, err = Parse([]byte(`{"name": "web", "config": {}}`))
+	assert.ErrorContains(t, err, "no stack")
+}
+
+func TestStoredRepository_CRUD(t *testing.T) {
+	ctx := context.Background()
+	manager := db.NewManager()
+	require.NoError(t, manager.Open(ctx, filepath.Join(t.TempDir(), "test.db")))
+	defer manager.Close()
+
+	repo := NewStoredRepository(manager)
+	custom := Blueprint{
+		Name:        "batch-stack",
+		Stack:       "cli",
+		Description: "Scheduled batch jobs",
+		Config:      BlueprintConfig{Components: []string{"cobra", "sqlx"}},
+	}
+	require.NoError(t, repo.Create(ctx, custom))
+	assert.Error(t, repo.Create(ctx, custom))
+
+	// A fresh repository on the same database sees the stored blueprint
+	loaded, err := NewStoredRepository(manager).GetBlueprint(ctx, "batch-stack")
+	require.NoError(t, err)
+	assert.Equal(t, custom, loaded)
+
+	custom.Config.Components = append(custom.Config.Components, "redis")
+	require.NoError(t, repo.Update(ctx, custom))
+	byStack, err := repo.GetBlueprintsByStack(ctx, "cli")
+	require.NoError(t, err)
+	var names []string
+	for _, blueprint := range byStack {
+		names = append(names, blueprint.Name)
+		if blueprint.Name == "batch-stack" {
+			assert.Len(t, blueprint.Config.Components, 3)
+		}
+	}
+	assert.Contains(t, names, "batch-stack")
+	assert.Contains(t, names, "cli-stack")
+
+	// Custom blueprints override predefined ones until deleted
+	override := Blueprint{Name: "web-stack", Stack: "web", Config: BlueprintConfig{Components: []string{"chi"}}}
+	assert.ErrorContains(t, repo.Update(ctx, override), "predefined")
+	require.NoError(t, repo.Create(ctx, override))
+	loaded, err = repo.GetBlueprint(ctx, "web-stack")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"chi"}, loaded.Config.Components)
+	require.NoError(t, repo.Delete(ctx, "web-stack"))
+	loaded, err = repo.GetBlueprint(ctx, "web-stack")
+	require.NoError(t, err)
+	assert.NotEqual(t, []string{"chi"}, loaded.Config.Components)
+	assert.ErrorContains(t, repo.Delete(ctx, "web-stack"), "cannot be deleted")
+
+	require.NoError(t, repo.Delete(ctx, "batch-stack"))
+	_, err = repo.GetBlueprint(ctx, "batch-stack")
+	assert.Error(t, err)
+
+	assert.ErrorIs(t, NewRepository().Create(ctx, custom), ErrNoStore)
+}
@@ -0,0 +1,42 @@
+package blueprints
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/user/gogo/internal/db"
+	"gopkg.in/yaml.v3"
+)
+
+// Parse reads a blueprint from YAML or JSON
+func Parse(data []byte) (Blueprint, error) {
+	var blueprint Blueprint
+	if err := yaml.Unmarshal(data, &blueprint); err != nil {
+		return Blueprint{}, err
+	}
+	if blueprint.Name == "" {
+		return Blueprint{}, fmt.Errorf("blueprint has no name")
+	}
+	if blueprint.Stack == "" {
+		return Blueprint{}, fmt.Errorf("blueprint '%s' has no stack", blueprint.Name)
+	}
+	return blueprint, nil
+}
+
+// ToStored converts a blueprint for storage in the gogo database
+func ToStored(blueprint Blueprint) (*db.StoredBlueprint, error) {
+	config, err := json.Marshal(blueprint.Config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode blueprint '%s': %w", blueprint.Name, err)
+	}
+	return &db.StoredBlueprint{Name: blueprint.Name, Stack: blueprint.Stack, Description: blueprint.Description, Config: string(config)}, nil
+}
+
+// FromStored converts a blueprint stored in the gogo database
+func FromStored(stored *db.StoredBlueprint) (Blueprint, error) {
+	blueprint := Blueprint{Name: stored.Name, Stack: stored.Stack, Description: stored.Description}
+	if err := json.Unmarshal([]byte(stored.Config), &blueprint.Config); err != nil {
+		return Blueprint{}, fmt.Errorf("failed to parse blueprint '%s': %w", stored.Name, err)
+	}
+	return blueprint, nil
+}
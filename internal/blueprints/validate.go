@@ -0,0 +1,322 @@
+package blueprints
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/user/gogo/internal/cicd"
+	"github.com/user/gogo/internal/deploy"
+)
+
+// FieldError is a problem with one field of a blueprint. Field is the path
+// of the field in the blueprint file, e.g. config.ci.coverage_min.
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+func (e FieldError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// ValidationErrors lists every problem found in a blueprint
+type ValidationErrors []FieldError
+
+func (e ValidationErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Schema describes the values a blueprint configuration may use. Sections
+// lists the known keys of each map-valued section; keys outside it are
+// reported so typos do not silently disable a feature.
+type Schema struct {
+	Components     []string
+	DatabaseTypes  []string
+	Migrations     []string
+	Logging        []string
+	Tracing        []string
+	TestFrameworks []string
+	CoverageMin    float64
+	CoverageMax    float64
+	Sections       map[string][]string
+}
+
+// DefaultSchema is the schema of the predefined stacks and their templates
+var DefaultSchema = Schema{
+	Components: []string{
+		"asynq", "chi", "cobra", "echo", "gin", "gorm", "gqlgen", "grpc", "jaeger", "kafka", "nats",
+		"opentelemetry", "prometheus", "protobuf", "redis", "sqlx", "swagger", "viper",
+	},
+	DatabaseTypes:  []string{"mysql", "postgres", "sqlite"},
+	Migrations:     []string{"atlas", "goose", "golang-migrate"},
+	Logging:        []string{"slog", "zap", "zerolog"},
+	Tracing:        []string{"jaeger", "otlp", "zipkin"},
+	TestFrameworks: []string{"ginkgo", "testify", "testing"},
+	CoverageMin:    0,
+	CoverageMax:    1,
+	Sections: map[string][]string{
+		"database":      {"type", "migrations"},
+		"observability": {"prometheus", "logging", "tracing", "health", "audit"},
+		"testing":       {"framework"},
+		"ci":            {"coverage_min", "coverage_exclude"},
+		"docker":        {"base_image", "runtime", "multi_stage", "non_root", "build_args", "expose", "health_check", "health_path"},
+		"kubernetes":    {"replicas"},
+		"deployment":    {"target", "provision", "env", "binary_url", "image", "port", "replicas", "max_replicas", "cpu_target", "health_path"},
+	},
+}
+
+// environmentPattern matches environment names, which become file names of
+// the generated config overlays
+var environmentPattern = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+
+// Validator checks blueprints against a schema before they are stored or used
+type Validator struct {
+	schema Schema
+}
+
+// NewValidator creates a validator using DefaultSchema
+func NewValidator() *Validator {
+	return NewValidatorWithSchema(DefaultSchema)
+}
+
+// NewValidatorWithSchema creates a validator using schema
+func NewValidatorWithSchema(schema Schema) *Validator {
+	return &Validator{schema: schema}
+}
+
+// Validate checks a blueprint and returns ValidationErrors listing every
+// problem found, or nil
+func (v *Validator) Validate(blueprint Blueprint) error {
+	var errs ValidationErrors
+	add := func(field, format string, args ...any) {
+		errs = append(errs, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if blueprint.Name == "" {
+		add("name", "is required")
+	}
+	if blueprint.Stack == "" {
+		add("stack", "is required")
+	}
+
+	config := blueprint.Config
+	seen := make(map[string]bool)
+	for i, component := range config.Components {
+		field := fmt.Sprintf("config.components[%d]", i)
+		switch {
+		case seen[component]:
+			add(field, "duplicate component %q", component)
+		case !slices.Contains(v.schema.Components, component):
+			add(field, "unknown component %q%s", component, hint(component, v.schema.Components))
+		}
+		seen[component] = true
+	}
+
+	sections := []struct {
+		name   string
+		values map[string]any
+	}{
+		{"database", config.Database},
+		{"observability", config.Observability},
+		{"testing", config.Testing},
+		{"ci", config.CI},
+		{"docker", config.Docker},
+		{"kubernetes", config.Kubernetes},
+		{"deployment", config.Deployment},
+	}
+	for _, section := range sections {
+		known := v.schema.Sections[section.name]
+		for _, key := range sortedKeys(section.values) {
+			if !slices.Contains(known, key) {
+				add("config."+section.name+"."+key, "unknown field%s", hint(key, known))
+			}
+		}
+	}
+
+	if len(config.Database) > 0 {
+		if dbType, ok := config.Database["type"]; !ok {
+			add("config.database.type", "is required when database is set; use one of %s", strings.Join(v.schema.DatabaseTypes, ", "))
+		} else {
+			v.checkChoice(add, "config.database.type", dbType, v.schema.DatabaseTypes)
+		}
+		if migrations, ok := config.Database["migrations"]; ok {
+			v.checkChoice(add, "config.database.migrations", migrations, v.schema.Migrations)
+		}
+	}
+
+	if logging, ok := config.Observability["logging"]; ok {
+		v.checkChoice(add, "config.observability.logging", logging, v.schema.Logging)
+	}
+	if tracing, ok := config.Observability["tracing"]; ok {
+		v.checkChoice(add, "config.observability.tracing", tracing, v.schema.Tracing)
+	}
+	for _, flag := range []string{"prometheus", "health", "audit"} {
+		if value, ok := config.Observability[flag]; ok {
+			if _, isBool := value.(bool); !isBool {
+				add("config.observability."+flag, "must be true or false, not %v", value)
+			}
+		}
+	}
+
+	if framework, ok := config.Testing["framework"]; ok {
+		v.checkChoice(add, "config.testing.framework", framework, v.schema.TestFrameworks)
+	}
+
+	if coverage, ok := config.CI["coverage_min"]; ok {
+		value, isNumber := toFloat(coverage)
+		switch {
+		case !isNumber:
+			add("config.ci.coverage_min", "must be a number, not %v", coverage)
+		case value < v.schema.CoverageMin || value > v.schema.CoverageMax:
+			add("config.ci.coverage_min", "%v is out of range; use a fraction between %v and %v, e.g. 0.8 for 80%%",
+				coverage, v.schema.CoverageMin, v.schema.CoverageMax)
+		}
+	}
+
+	if exclude, ok := config.CI["coverage_exclude"]; ok {
+		exclusions, isList := exclude.([]any)
+		var names []string
+		for _, item := range exclusions {
+			name, isString := item.(string)
+			if !isString {
+				isList = false
+				break
+			}
+			names = append(names, name)
+		}
+		if !isList {
+			add("config.ci.coverage_exclude", "must be a list of exclusions (%s) or path patterns", strings.Join(cicd.CoverageExclusions, ", "))
+		} else if _, err := cicd.CoveragePatterns(names); err != nil {
+			add("config.ci.coverage_exclude", "%v", err)
+		}
+	}
+
+	if expose, ok := config.Docker["expose"]; ok {
+		port, isNumber := toFloat(expose)
+		if !isNumber || port != float64(int(port)) || port < 1 || port > 65535 {
+			add("config.docker.expose", "must be a port between 1 and 65535, not %v", expose)
+		}
+	}
+
+	if replicas, ok := config.Kubernetes["replicas"]; ok {
+		counts, isMap := replicas.(map[string]any)
+		if !isMap {
+			add("config.kubernetes.replicas", "must map environment names to replica counts")
+		}
+		for _, env := range sortedKeys(counts) {
+			count, isNumber := toFloat(counts[env])
+			if !isNumber || count != float64(int(count)) || count < 0 {
+				add("config.kubernetes.replicas."+env, "must be a replica count of 0 or more, not %v", counts[env])
+			}
+		}
+	}
+
+	seen = make(map[string]bool)
+	for i, env := range config.Environments {
+		field := fmt.Sprintf("config.environments[%d]", i)
+		switch {
+		case seen[env]:
+			add(field, "duplicate environment %q", env)
+		case !environmentPattern.MatchString(env):
+			add(field, "invalid environment name %q (use lower-case letters, digits and '-')", env)
+		}
+		seen[env] = true
+	}
+
+	if _, err := deploy.ParseConfig(config.Deployment); err != nil {
+		add("config.deployment", "%v", err)
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// checkChoice reports value unless it is one of the valid strings
+func (v *Validator) checkChoice(add func(field, format string, args ...any), field string, value any, valid []string) {
+	s, ok := value.(string)
+	if !ok {
+		add(field, "must be a string, not %v", value)
+		return
+	}
+	if !slices.Contains(valid, s) {
+		add(field, "unknown value %q%s", s, hint(s, valid))
+	}
+}
+
+// hint suggests the closest valid value, falling back to listing them all
+func hint(value string, valid []string) string {
+	if len(valid) == 0 {
+		return ""
+	}
+	best, bestDistance := "", len(value)/3+2
+	for _, candidate := range valid {
+		if d := editDistance(value, candidate); d < bestDistance {
+			best, bestDistance = candidate, d
+		}
+	}
+	if best != "" {
+		return fmt.Sprintf(" (did you mean %q?)", best)
+	}
+	return " (valid: " + strings.Join(valid, ", ") + ")"
+}
+
+// editDistance returns the optimal string alignment distance between a and
+// b: the edits, counting a swap of adjacent characters as one, that turn a
+// into b
+func editDistance(a, b string) int {
+	d := make([][]int, len(a)+1)
+	for i := range d {
+		d[i] = make([]int, len(b)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(a)][len(b)]
+}
+
+func sortedKeys(values map[string]any) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// toFloat converts a number decoded from YAML or JSON
+func toFloat(value any) (float64, bool) {
+	switch n := value.(type) {
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case float64:
+		return n, true
+	default:
+		return 0, false
+	}
+}
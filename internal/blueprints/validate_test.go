@@ -0,0 +1,76 @@
+package blueprints
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidator_Defaults(t *testing.T) {
+	defaults, err := Defaults()
+	require.NoError(t, err)
+	for _, blueprint := range defaults {
+		assert.NoError(t, NewValidator().Validate(blueprint), blueprint.Name)
+	}
+}
+
+func TestValidator_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		config BlueprintConfig
+		field  string
+		want   string
+	}{
+		{"unknown component", BlueprintConfig{Components: []string{"gni"}}, "config.components[0]", `did you mean "gin"?`},
+		{"duplicate component", BlueprintConfig{Components: []string{"gin", "gin"}}, "config.components[1]", "duplicate"},
+		{"unknown field", BlueprintConfig{Docker: map[string]any{"expsoe": 8080}}, "config.docker.expsoe", `did you mean "expose"?`},
+		{"missing database type", BlueprintConfig{Database: map[string]any{"migrations": "goose"}}, "config.database.type", "is required"},
+		{"unknown database type", BlueprintConfig{Database: map[string]any{"type": "oracle"}}, "config.database.type", "valid: mysql, postgres, sqlite"},
+		{"non-boolean flag", BlueprintConfig{Observability: map[string]any{"audit": "on"}}, "config.observability.audit", "true or false"},
+		{"coverage as percentage", BlueprintConfig{CI: map[string]any{"coverage_min": 85}}, "config.ci.coverage_min", "out of range"},
+		{"coverage not a number", BlueprintConfig{CI: map[string]any{"coverage_min": "high"}}, "config.ci.coverage_min", "must be a number"},
+		{"coverage exclusion not a list", BlueprintConfig{CI: map[string]any{"coverage_exclude": "generated"}}, "config.ci.coverage_exclude", "must be a list"},
+		{"invalid coverage exclusion", BlueprintConfig{CI: map[string]any{"coverage_exclude": []any{"gen(erated"}}}, "config.ci.coverage_exclude", "invalid coverage exclusion"},
+		{"port out of range", BlueprintConfig{Docker: map[string]any{"expose": 70000}}, "config.docker.expose", "between 1 and 65535"},
+		{"negative replicas", BlueprintConfig{Kubernetes: map[string]any{"replicas": map[string]any{"prod": -1}}}, "config.kubernetes.replicas.prod", "0 or more"},
+		{"environment name", BlueprintConfig{Environments: []string{"Prod"}}, "config.environments[0]", "invalid environment name"},
+		{"deployment", BlueprintConfig{Deployment: map[string]any{"target": "launchd"}}, "config.deployment", "unknown deployment target"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewValidator().Validate(Blueprint{Name: "custom", Stack: "web", Config: tt.config})
+
+			var invalid ValidationErrors
+			require.True(t, errors.As(err, &invalid), "expected validation errors, got %v", err)
+			require.Len(t, invalid, 1, invalid.Error())
+			assert.Equal(t, tt.field, invalid[0].Field)
+			assert.Contains(t, invalid[0].Message, tt.want)
+		})
+	}
+}
+
+func TestValidator_ReportsEveryProblem(t *testing.T) {
+	blueprint := Blueprint{Config: BlueprintConfig{
+		Components: []string{"gin", "kafak"},
+		CI:         map[string]any{"coverage_min": 0.8},
+		Testing:    map[string]any{"framework": "gocheck"},
+	}}
+
+	var invalid ValidationErrors
+	require.True(t, errors.As(NewValidator().Validate(blueprint), &invalid))
+	var fields []string
+	for _, err := range invalid {
+		fields = append(fields, err.Field)
+	}
+	assert.Equal(t, []string{"name", "stack", "config.components[1]", "config.testing.framework"}, fields)
+
+	// Custom schemas accept the components of in-house templates
+	schema := DefaultSchema
+	schema.Components = append([]string{"kafak"}, schema.Components...)
+	schema.TestFrameworks = append([]string{"gocheck"}, schema.TestFrameworks...)
+	blueprint.Name, blueprint.Stack = "custom", "web"
+	assert.NoError(t, NewValidatorWithSchema(schema).Validate(blueprint))
+}
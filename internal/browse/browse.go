@@ -0,0 +1,465 @@
+// Package browse implements gogo db browse, a terminal UI for reading the
+// gogo database without writing SQL.
+package browse
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/user/gogo/internal/db"
+)
+
+// Source is the data the browser reads
+type Source interface {
+	Tables(ctx context.Context) ([]db.BrowseTable, error)
+	Rows(ctx context.Context, table string, offset, limit int) (*db.BrowsePage, error)
+	Row(ctx context.Context, table string, offset int) ([]string, []string, error)
+	Audits(ctx context.Context, entity string, limit int) ([]db.AuditEntry, error)
+	Search(ctx context.Context, query string) ([]db.SearchResult, error)
+}
+
+// NewSource reads tables and the audit log through db.BrowseManager and
+// searches with db.SearchManager
+func NewSource(manager *db.Manager) Source {
+	return &dbSource{
+		BrowseManager: db.NewBrowseManager(manager),
+		search:        db.NewSearchManager(manager),
+	}
+}
+
+type dbSource struct {
+	*db.BrowseManager
+	search *db.SearchManager
+}
+
+func (s *dbSource) Search(ctx context.Context, query string) ([]db.SearchResult, error) {
+	return s.search.Search(ctx, query, db.SearchOptions{Kinds: []string{db.KindTemplate, db.KindBlueprint}, Limit: 50})
+}
+
+// Run starts the browser and blocks until the user quits
+func Run(ctx context.Context, source Source) error {
+	model, err := New(ctx, source)
+	if err != nil {
+		return err
+	}
+	_, err = tea.NewProgram(model, tea.WithAltScreen(), tea.WithContext(ctx)).Run()
+	return err
+}
+
+type view int
+
+const (
+	tablesView view = iota
+	rowsView
+	rowView
+	searchView
+	auditView
+)
+
+// auditLimit caps the audit entries shown at once
+const auditLimit = 200
+
+// maxCellWidth caps the width of a column in the rows view
+const maxCellWidth = 30
+
+var (
+	titleStyle    = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("10"))
+	headerStyle   = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("14"))
+	selectedStyle = lipgloss.NewStyle().Reverse(true)
+	helpStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+	errorStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("9"))
+)
+
+// Model is the browser's Bubble Tea model. Views form a stack: enter opens
+// the selected item and esc returns to the previous view.
+type Model struct {
+	ctx    context.Context
+	source Source
+	height int
+	err    error
+
+	view  view
+	stack []view
+
+	tables     []db.BrowseTable
+	tableIndex int
+
+	page     *db.BrowsePage
+	rowIndex int // Index within page.Rows
+	column   int // First column shown, for scrolling wide tables
+
+	rowColumns []string
+	rowValues  []string
+	rowScroll  int
+
+	query       string
+	editing     bool
+	results     []db.SearchResult
+	resultIndex int
+
+	entity     string // Audit entries are restricted to this entity when set
+	audits     []db.AuditEntry
+	auditIndex int
+}
+
+// New creates a browser showing the tables of source
+func New(ctx context.Context, source Source) (*Model, error) {
+	tables, err := source.Tables(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &Model{ctx: ctx, source: source, height: 24, tables: tables}, nil
+}
+
+// Init implements tea.Model
+func (m *Model) Init() tea.Cmd {
+	return nil
+}
+
+// pageSize is the number of list lines that fit between the title and help
+func (m *Model) pageSize() int {
+	return max(m.height-5, 1)
+}
+
+// Update implements tea.Model
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.height = msg.Height
+		if m.view == rowsView {
+			m.loadPage(m.page.Offset)
+		}
+		return m, nil
+	case tea.KeyMsg:
+		if msg.Type == tea.KeyCtrlC {
+			return m, tea.Quit
+		}
+		if m.editing {
+			m.editQuery(msg)
+			return m, nil
+		}
+		m.err = nil
+		switch msg.String() {
+		case "q":
+			return m, tea.Quit
+		case "esc", "backspace":
+			m.back()
+			return m, nil
+		case "/":
+			m.push(searchView)
+			m.editing = true
+			return m, nil
+		case "a":
+			m.openAudits("")
+			return m, nil
+		}
+		m.handleKey(msg.String())
+	}
+	return m, nil
+}
+
+func (m *Model) handleKey(key string) {
+	switch m.view {
+	case tablesView:
+		switch key {
+		case "up", "k":
+			m.tableIndex = max(m.tableIndex-1, 0)
+		case "down", "j":
+			m.tableIndex = min(m.tableIndex+1, len(m.tables)-1)
+		case "enter", "l":
+			if len(m.tables) > 0 {
+				m.column = 0
+				if m.loadPage(0) {
+					m.push(rowsView)
+				}
+			}
+		}
+	case rowsView:
+		switch key {
+		case "up", "k":
+			if m.rowIndex > 0 {
+				m.rowIndex--
+			} else if m.page.Offset > 0 && m.loadPage(m.page.Offset-m.pageSize()) {
+				m.rowIndex = len(m.page.Rows) - 1
+			}
+		case "down", "j":
+			if m.rowIndex < len(m.page.Rows)-1 {
+				m.rowIndex++
+			} else {
+				m.nextPage()
+			}
+		case "pgdown", "n", " ":
+			m.nextPage()
+		case "pgup", "p":
+			if m.page.Offset > 0 {
+				m.loadPage(m.page.Offset - m.pageSize())
+			}
+		case "right", "l":
+			m.column = min(m.column+1, max(len(m.page.Columns)-1, 0))
+		case "left", "h":
+			m.column = max(m.column-1, 0)
+		case "enter":
+			if len(m.page.Rows) > 0 {
+				columns, values, err := m.source.Row(m.ctx, m.page.Table, m.page.Offset+m.rowIndex)
+				if err != nil {
+					m.err = err
+					return
+				}
+				m.rowColumns, m.rowValues, m.rowScroll = columns, values, 0
+				m.push(rowView)
+			}
+		}
+	case rowView:
+		switch key {
+		case "up", "k":
+			m.rowScroll = max(m.rowScroll-1, 0)
+		case "down", "j":
+			m.rowScroll = min(m.rowScroll+1, max(len(m.rowLines())-m.pageSize(), 0))
+		}
+	case searchView:
+		switch key {
+		case "up", "k":
+			m.resultIndex = max(m.resultIndex-1, 0)
+		case "down", "j":
+			m.resultIndex = min(m.resultIndex+1, len(m.results)-1)
+		case "i":
+			m.editing = true
+		case "enter", "l":
+			if len(m.results) > 0 {
+				result := m.results[m.resultIndex]
+				m.openAudits(result.Kind + ":" + result.Name)
+			}
+		}
+	case auditView:
+		switch key {
+		case "up", "k":
+			m.auditIndex = max(m.auditIndex-1, 0)
+		case "down", "j":
+			m.auditIndex = min(m.auditIndex+1, len(m.audits)-1)
+		}
+	}
+}
+
+// editQuery handles keys while the search query is being typed
+func (m *Model) editQuery(msg tea.KeyMsg) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		m.editing = false
+		results, err := m.source.Search(m.ctx, m.query)
+		if err != nil {
+			m.err = err
+			return
+		}
+		m.results, m.resultIndex, m.err = results, 0, nil
+	case tea.KeyEsc:
+		m.editing = false
+		if m.query == "" {
+			m.back()
+		}
+	case tea.KeyBackspace:
+		if runes := []rune(m.query); len(runes) > 0 {
+			m.query = string(runes[:len(runes)-1])
+		}
+	case tea.KeySpace:
+		m.query += " "
+	case tea.KeyRunes:
+		m.query += string(msg.Runes)
+	}
+}
+
+func (m *Model) push(v view) {
+	if m.view != v {
+		m.stack = append(m.stack, m.view)
+		m.view = v
+	}
+}
+
+func (m *Model) back() {
+	if len(m.stack) == 0 {
+		return
+	}
+	m.view = m.stack[len(m.stack)-1]
+	m.stack = m.stack[:len(m.stack)-1]
+}
+
+// loadPage reads the page of the selected table starting at offset
+func (m *Model) loadPage(offset int) bool {
+	page, err := m.source.Rows(m.ctx, m.tables[m.tableIndex].Name, max(offset, 0), m.pageSize())
+	if err != nil {
+		m.err = err
+		return false
+	}
+	m.page, m.rowIndex = page, 0
+	return true
+}
+
+func (m *Model) nextPage() {
+	if next := m.page.Offset + len(m.page.Rows); next < m.page.Total {
+		m.loadPage(next)
+	}
+}
+
+func (m *Model) openAudits(entity string) {
+	audits, err := m.source.Audits(m.ctx, entity, auditLimit)
+	if err != nil {
+		m.err = err
+		return
+	}
+	m.entity, m.audits, m.auditIndex = entity, audits, 0
+	m.push(auditView)
+}
+
+// View implements tea.Model
+func (m *Model) View() string {
+	var title, body, help string
+	switch m.view {
+	case tablesView:
+		title = "gogo database"
+		lines := make([]string, len(m.tables))
+		for i, table := range m.tables {
+			lines[i] = fmt.Sprintf("%-24s %8d rows", table.Name, table.Rows)
+		}
+		body = m.list(lines, m.tableIndex)
+		help = "↑/↓ move • enter open • / search • a audit log • q quit"
+	case rowsView:
+		title = fmt.Sprintf("%s — rows %d-%d of %d", m.page.Table,
+			min(m.page.Offset+1, m.page.Total), m.page.Offset+len(m.page.Rows), m.page.Total)
+		body = m.rowsTable()
+		help = "↑/↓ move • n/p page • ←/→ columns • enter view row • esc back • q quit"
+	case rowView:
+		title = fmt.Sprintf("%s — row %d", m.page.Table, m.page.Offset+m.rowIndex+1)
+		lines := m.rowLines()
+		end := min(m.rowScroll+m.pageSize(), len(lines))
+		body = strings.Join(lines[m.rowScroll:end], "\n")
+		help = "↑/↓ scroll • esc back • q quit"
+	case searchView:
+		title = "Search templates and blueprints"
+		cursor := ""
+		if m.editing {
+			cursor = "█"
+		}
+		lines := make([]string, len(m.results))
+		for i, result := range m.results {
+			lines[i] = fmt.Sprintf("%-10s %-24s %s", result.Kind, result.Name, result.Snippet)
+		}
+		body = "/ " + m.query + cursor + "\n\n"
+		if len(m.results) == 0 && !m.editing {
+			body += helpStyle.Render("No matches")
+		} else {
+			body += m.list(lines, m.resultIndex)
+		}
+		help = "↑/↓ move • enter audit history • i edit query • esc back • q quit"
+		if m.editing {
+			help = "type a query • enter search • esc stop editing"
+		}
+	case auditView:
+		title = "Audit log"
+		if m.entity != "" {
+			title = "Audit history of " + m.entity
+		}
+		lines := make([]string, len(m.audits))
+		for i, entry := range m.audits {
+			lines[i] = fmt.Sprintf("%-20s %-10s %-10s %-30s %s", entry.CreatedAt, entry.Actor, entry.Action, entry.Entity, entry.Details)
+		}
+		if len(lines) == 0 {
+			body = helpStyle.Render("No audit entries")
+		} else {
+			body = m.list(lines, m.auditIndex)
+		}
+		help = "↑/↓ move • esc back • q quit"
+	}
+
+	view := titleStyle.Render(title) + "\n\n" + body + "\n\n"
+	if m.err != nil {
+		view += errorStyle.Render("Error: "+m.err.Error()) + "\n"
+	}
+	return view + helpStyle.Render(help)
+}
+
+// list renders the window of lines around the selected one
+func (m *Model) list(lines []string, selected int) string {
+	size := m.pageSize()
+	if m.view == searchView {
+		size = max(size-2, 1)
+	}
+	start := max(selected-size+1, 0)
+	end := min(start+size, len(lines))
+
+	var b strings.Builder
+	for i := start; i < end; i++ {
+		if i > start {
+			b.WriteByte('\n')
+		}
+		if i == selected {
+			b.WriteString(selectedStyle.Render(lines[i]))
+		} else {
+			b.WriteString(lines[i])
+		}
+	}
+	return b.String()
+}
+
+// rowsTable renders the current page as aligned columns from m.column on
+func (m *Model) rowsTable() string {
+	columns := m.page.Columns[min(m.column, len(m.page.Columns)):]
+	widths := make([]int, len(columns))
+	for i, column := range columns {
+		widths[i] = lipgloss.Width(column)
+		for _, row := range m.page.Rows {
+			widths[i] = max(widths[i], lipgloss.Width(row[m.column+i]))
+		}
+		widths[i] = min(widths[i], maxCellWidth)
+	}
+
+	format := func(cells []string) string {
+		padded := make([]string, len(cells))
+		for i, cell := range cells {
+			padded[i] = pad(cell, widths[i])
+		}
+		return strings.Join(padded, "  ")
+	}
+
+	lines := make([]string, len(m.page.Rows))
+	for i, row := range m.page.Rows {
+		lines[i] = format(row[m.column:])
+	}
+	if len(lines) == 0 {
+		return headerStyle.Render(format(columns)) + "\n" + helpStyle.Render("(empty table)")
+	}
+	return headerStyle.Render(format(columns)) + "\n" + m.list(lines, m.rowIndex)
+}
+
+// rowLines renders the full row as "column: value" lines
+func (m *Model) rowLines() []string {
+	width := 0
+	for _, column := range m.rowColumns {
+		width = max(width, len(column))
+	}
+	var lines []string
+	for i, column := range m.rowColumns {
+		label := headerStyle.Render(fmt.Sprintf("%-*s", width, column))
+		for j, line := range strings.Split(m.rowValues[i], "\n") {
+			if j == 0 {
+				lines = append(lines, label+"  "+line)
+			} else {
+				lines = append(lines, strings.Repeat(" ", width+2)+line)
+			}
+		}
+	}
+	return lines
+}
+
+// pad cuts or pads cell to exactly width columns
+func pad(cell string, width int) string {
+	if lipgloss.Width(cell) > width {
+		runes := []rune(cell)
+		for lipgloss.Width(string(runes))+1 > width && len(runes) > 0 {
+			runes = runes[:len(runes)-1]
+		}
+		cell = string(runes) + "…"
+	}
+	return cell + strings.Repeat(" ", width-lipgloss.Width(cell))
+}
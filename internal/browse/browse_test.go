@@ -0,0 +1,136 @@
+package browse
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/user/gogo/internal/db"
+)
+
+// fakeSource serves a templates table of n rows and a fixed audit log
+type fakeSource struct {
+	rows    int
+	queries []string
+}
+
+func (f *fakeSource) Tables(ctx context.Context) ([]db.BrowseTable, error) {
+	return []db.BrowseTable{{Name: "audits", Rows: 2}, {Name: "templates", Rows: f.rows}}, nil
+}
+
+func (f *fakeSource) Rows(ctx context.Context, table string, offset, limit int) (*db.BrowsePage, error) {
+	page := &db.BrowsePage{Table: table, Columns: []string{"id", "name"}, Offset: offset, Total: f.rows}
+	for i := offset; i < min(offset+limit, f.rows); i++ {
+		page.Rows = append(page.Rows, []string{fmt.Sprint(i + 1), fmt.Sprintf("template-%d", i+1)})
+	}
+	return page, nil
+}
+
+func (f *fakeSource) Row(ctx context.Context, table string, offset int) ([]string, []string, error) {
+	return []string{"id", "name"}, []string{fmt.Sprint(offset + 1), fmt.Sprintf("template-%d", offset+1)}, nil
+}
+
+func (f *fakeSource) Audits(ctx context.Context, entity string, limit int) ([]db.AuditEntry, error) {
+	entries := []db.AuditEntry{
+		{Actor: "ann", Action: "add", Entity: "template:rest-api"},
+		{Actor: "bob", Action: "add", Entity: "blueprint:stack"},
+	}
+	var filtered []db.AuditEntry
+	for _, entry := range entries {
+		if entity == "" || entry.Entity == entity {
+			filtered = append(filtered, entry)
+		}
+	}
+	return filtered, nil
+}
+
+func (f *fakeSource) Search(ctx context.Context, query string) ([]db.SearchResult, error) {
+	f.queries = append(f.queries, query)
+	return []db.SearchResult{{Kind: db.KindTemplate, Name: "rest-api", Snippet: "HTTP [api]"}}, nil
+}
+
+func press(m *Model, keys ...string) {
+	for _, key := range keys {
+		var msg tea.KeyMsg
+		switch key {
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		case "esc":
+			msg = tea.KeyMsg{Type: tea.KeyEsc}
+		case "down":
+			msg = tea.KeyMsg{Type: tea.KeyDown}
+		case " ":
+			msg = tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")}
+		default:
+			msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+		}
+		m.Update(msg)
+	}
+}
+
+func TestModel_Rows(t *testing.T) {
+	source := &fakeSource{rows: 25}
+	m, err := New(context.Background(), source)
+	require.NoError(t, err)
+	m.Update(tea.WindowSizeMsg{Width: 80, Height: 15}) // 10 rows per page
+
+	assert.Contains(t, m.View(), "templates")
+	press(m, "down", "enter")
+	assert.Equal(t, rowsView, m.view)
+	assert.Contains(t, m.View(), "templates — rows 1-10 of 25")
+	assert.Contains(t, m.View(), "template-10")
+
+	press(m, "n")
+	assert.Contains(t, m.View(), "rows 11-20 of 25")
+	press(m, "n", "n")
+	assert.Contains(t, m.View(), "rows 21-25 of 25", "paging stops at the last page")
+	press(m, "p")
+	assert.Contains(t, m.View(), "rows 11-20 of 25")
+
+	press(m, "down", "enter")
+	assert.Equal(t, rowView, m.view)
+	assert.Contains(t, m.View(), "templates — row 12")
+	assert.Contains(t, m.View(), "template-12")
+
+	press(m, "esc", "esc")
+	assert.Equal(t, tablesView, m.view)
+	press(m, "esc")
+	assert.Equal(t, tablesView, m.view, "esc on the first view stays put")
+}
+
+func TestModel_SearchAndAudit(t *testing.T) {
+	source := &fakeSource{}
+	m, err := New(context.Background(), source)
+	require.NoError(t, err)
+
+	press(m, "/", "r", "e", "s", "t", " ", "a", "p", "i")
+	assert.True(t, m.editing)
+	assert.Contains(t, m.View(), "/ rest api")
+	assert.Equal(t, searchView, m.view, "keys are typed into the query while editing")
+
+	press(m, "enter")
+	assert.Equal(t, []string{"rest api"}, source.queries)
+	assert.Contains(t, m.View(), "HTTP [api]")
+
+	press(m, "enter")
+	assert.Equal(t, auditView, m.view)
+	assert.Contains(t, m.View(), "Audit history of template:rest-api")
+	assert.Len(t, m.audits, 1)
+
+	press(m, "esc", "esc", "a")
+	assert.Equal(t, auditView, m.view)
+	assert.Len(t, m.audits, 2)
+	assert.Contains(t, m.View(), "blueprint:stack")
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	require.NotNil(t, cmd)
+	assert.Equal(t, tea.Quit(), cmd())
+}
+
+func TestPad(t *testing.T) {
+	assert.Equal(t, "ab  ", pad("ab", 4))
+	assert.Equal(t, "abc…", pad("abcdefg", 4))
+}
@@ -0,0 +1,163 @@
+// Package catalog pins the versions of the modules generated projects
+// require. Templates look versions up with the version filter, as in
+// github.com/gin-gonic/gin {{ "github.com/gin-gonic/gin"|version }}, so a
+// dependency is bumped in the catalog rather than in every template that
+// requires it. The catalog compiled into gogo can be refreshed from the Go
+// module proxy with gogo catalog update, which records each bump.
+package catalog
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/user/gogo/internal/fsutil"
+	"golang.org/x/mod/semver"
+)
+
+// EnvCatalogPath overrides the location of the catalog file
+const EnvCatalogPath = "GOGO_CATALOG"
+
+//go:embed catalog.json
+var builtinJSON []byte
+
+// Catalog is a versioned list of module versions
+type Catalog struct {
+	Version int               `json:"version"` // incremented by each update that bumps a module
+	Updated time.Time         `json:"updated,omitzero"`
+	Modules map[string]string `json:"modules"` // module path -> version
+	History []Revision        `json:"history,omitempty"`
+}
+
+// Revision records the modules an update bumped
+type Revision struct {
+	Version int       `json:"version"`
+	Date    time.Time `json:"date"`
+	Changes []Change  `json:"changes"`
+}
+
+// Change is a module version bumped by an update
+type Change struct {
+	Module string `json:"module"`
+	From   string `json:"from"`
+	To     string `json:"to"`
+}
+
+// Builtin returns the catalog compiled into gogo
+func Builtin() *Catalog {
+	c, err := parse(builtinJSON)
+	if err != nil {
+		panic(fmt.Sprintf("invalid built-in catalog: %v", err)) // checked by the tests
+	}
+	return c
+}
+
+// BuiltinJSON returns the catalog compiled into gogo as written in its file
+func BuiltinJSON() []byte {
+	return builtinJSON
+}
+
+var current *Catalog
+
+// Use makes c the catalog templates look versions up in; nil restores the
+// built-in one
+func Use(c *Catalog) {
+	current = c
+}
+
+// Current returns the catalog templates look versions up in
+func Current() *Catalog {
+	if current == nil {
+		current = Builtin()
+	}
+	return current
+}
+
+// DefaultPath returns the location of the catalog gogo catalog update writes
+func DefaultPath() string {
+	if path := os.Getenv(EnvCatalogPath); path != "" {
+		return path
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return ".gogo-catalog.json"
+	}
+	return filepath.Join(homeDir, ".gogo", "catalog.json")
+}
+
+// Load reads the catalog file at path, or returns the built-in catalog when
+// there is none. Modules the file lacks, or pins older than the built-in
+// catalog does, such as after gogo itself was upgraded, get the built-in
+// versions.
+func Load(path string) (*Catalog, error) {
+	builtin := Builtin()
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return builtin, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read catalog: %w", err)
+	}
+	c, err := parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid catalog %s: %w", path, err)
+	}
+	for module, version := range builtin.Modules {
+		if current, ok := c.Modules[module]; !ok || semver.Compare(version, current) > 0 {
+			c.Modules[module] = version
+		}
+	}
+	return c, nil
+}
+
+func parse(data []byte) (*Catalog, error) {
+	var c Catalog
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, err
+	}
+	if c.Modules == nil {
+		c.Modules = make(map[string]string)
+	}
+	for module, version := range c.Modules {
+		if !semver.IsValid(version) {
+			return nil, fmt.Errorf("invalid version %q of %s", version, module)
+		}
+	}
+	return &c, nil
+}
+
+// Save writes the catalog to path
+func (c *Catalog) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode catalog: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create catalog directory: %w", err)
+	}
+	if err := fsutil.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write catalog: %w", err)
+	}
+	return nil
+}
+
+// Lookup returns the version module is pinned to
+func (c *Catalog) Lookup(module string) (string, bool) {
+	version, ok := c.Modules[module]
+	return version, ok
+}
+
+// Paths returns the module paths of the catalog, sorted
+func (c *Catalog) Paths() []string {
+	paths := make([]string, 0, len(c.Modules))
+	for module := range c.Modules {
+		paths = append(paths, module)
+	}
+	sort.Strings(paths)
+	return paths
+}
@@ -0,0 +1,148 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuiltin(t *testing.T) {
+	c := Builtin()
+	assert.Positive(t, c.Version)
+	version, ok := c.Lookup("github.com/gin-gonic/gin")
+	require.True(t, ok)
+	assert.Regexp(t, `^v1\.`, version)
+}
+
+func TestLoad(t *testing.T) {
+	builtin := Builtin()
+
+	c, err := Load(filepath.Join(t.TempDir(), "missing.json"))
+	require.NoError(t, err)
+	assert.Equal(t, builtin, c)
+
+	path := filepath.Join(t.TempDir(), "catalog.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{
+  "version": 3,
+  "modules": {
+    "github.com/gin-gonic/gin": "v1.0.0",
+    "github.com/spf13/cobra": "v9.0.0",
+    "example.com/extra": "v0.1.0"
+  }
+}`), 0644))
+	c, err = Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, 3, c.Version)
+	assert.Equal(t, builtin.Modules["github.com/gin-gonic/gin"], c.Modules["github.com/gin-gonic/gin"], "older pins get the built-in version")
+	assert.Equal(t, "v9.0.0", c.Modules["github.com/spf13/cobra"])
+	assert.Equal(t, "v0.1.0", c.Modules["example.com/extra"])
+	assert.Equal(t, builtin.Modules["gorm.io/gorm"], c.Modules["gorm.io/gorm"], "missing modules get the built-in version")
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"modules": {"example.com/extra": "latest"}}`), 0644))
+	_, err = Load(path)
+	assert.ErrorContains(t, err, `invalid version "latest" of example.com/extra`)
+}
+
+func TestCatalog_Update(t *testing.T) {
+	c := &Catalog{Version: 1, Modules: map[string]string{
+		"example.com/a": "v1.2.0",
+		"example.com/b": "v2.0.0+incompatible",
+		"example.com/c": "v0.3.0",
+	}}
+	latest := map[string]string{
+		"example.com/a": "v1.3.1",
+		"example.com/b": "v2.1.0+incompatible",
+		"example.com/c": "v0.2.0",
+	}
+	lookup := func(ctx context.Context, module string) (string, error) {
+		return latest[module], nil
+	}
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	changes, err := c.Update(context.Background(), lookup, nil, now)
+	require.NoError(t, err)
+	assert.Equal(t, []Change{
+		{Module: "example.com/a", From: "v1.2.0", To: "v1.3.1"},
+		{Module: "example.com/b", From: "v2.0.0+incompatible", To: "v2.1.0+incompatible"},
+	}, changes, "versions are never lowered")
+	assert.Equal(t, 2, c.Version)
+	assert.Equal(t, "v1.3.1", c.Modules["example.com/a"])
+	assert.Equal(t, "v0.3.0", c.Modules["example.com/c"])
+	require.Len(t, c.History, 1)
+	assert.Equal(t, Revision{Version: 2, Date: now, Changes: changes}, c.History[0])
+
+	// Up to date: no new revision
+	changes, err = c.Update(context.Background(), lookup, nil, now)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+	assert.Equal(t, 2, c.Version)
+
+	// A failed lookup changes nothing
+	latest["example.com/a"] = "v1.4.0"
+	failing := func(ctx context.Context, module string) (string, error) {
+		if module == "example.com/c" {
+			return "", fmt.Errorf("proxy unavailable")
+		}
+		return lookup(ctx, module)
+	}
+	_, err = c.Update(context.Background(), failing, nil, now)
+	assert.ErrorContains(t, err, "proxy unavailable")
+	assert.Equal(t, "v1.3.1", c.Modules["example.com/a"])
+
+	_, err = c.Update(context.Background(), lookup, []string{"example.com/unknown"}, now)
+	assert.ErrorContains(t, err, "module example.com/unknown is not in the catalog")
+}
+
+func TestProxy_Latest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/github.com/!burnt!sushi/toml/@latest":
+			_, _ = w.Write([]byte(`{"Version":"v1.4.0","Time":"2024-06-01T00:00:00Z"}`))
+		default:
+			http.Error(w, "not found: "+r.URL.Path, http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	proxy := NewProxy(server.URL + "/")
+	version, err := proxy.Latest(context.Background(), "github.com/BurntSushi/toml")
+	require.NoError(t, err)
+	assert.Equal(t, "v1.4.0", version)
+
+	_, err = proxy.Latest(context.Background(), "example.com/missing")
+	assert.ErrorContains(t, err, "404")
+}
+
+func TestSave_RoundTrip(t *testing.T) {
+	c := Builtin()
+	_, err := c.Update(context.Background(), func(ctx context.Context, module string) (string, error) {
+		if module == "github.com/gin-gonic/gin" {
+			return "v1.99.0", nil
+		}
+		return c.Modules[module], nil
+	}, nil, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "nested", "catalog.json")
+	require.NoError(t, c.Save(path))
+	loaded, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, c, loaded)
+}
+
+func TestProxyURL(t *testing.T) {
+	t.Setenv("GOPROXY", "")
+	assert.Equal(t, DefaultProxy, ProxyURL())
+	t.Setenv("GOPROXY", "direct")
+	assert.Equal(t, DefaultProxy, ProxyURL())
+	t.Setenv("GOPROXY", "off,https://goproxy.example.com/|https://proxy.golang.org")
+	assert.Equal(t, "https://goproxy.example.com", ProxyURL())
+}
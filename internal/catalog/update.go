@@ -0,0 +1,119 @@
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+const (
+	// DefaultProxy is the module proxy updates query unless GOPROXY names another
+	DefaultProxy = "https://proxy.golang.org"
+	// DefaultTimeout bounds each proxy request
+	DefaultTimeout = 10 * time.Second
+)
+
+// ProxyURL returns the first HTTP proxy of GOPROXY, or DefaultProxy
+func ProxyURL() string {
+	for _, entry := range strings.FieldsFunc(os.Getenv("GOPROXY"), func(r rune) bool { return r == ',' || r == '|' }) {
+		if strings.HasPrefix(entry, "https://") || strings.HasPrefix(entry, "http://") {
+			return strings.TrimSuffix(entry, "/")
+		}
+	}
+	return DefaultProxy
+}
+
+// Proxy looks up module versions in a Go module proxy
+type Proxy struct {
+	url        string
+	httpClient *http.Client
+}
+
+// NewProxy creates a client of the module proxy at url
+func NewProxy(url string) *Proxy {
+	return &Proxy{url: strings.TrimSuffix(url, "/"), httpClient: &http.Client{Timeout: DefaultTimeout}}
+}
+
+// Latest returns the latest version of module known to the proxy
+func (p *Proxy) Latest(ctx context.Context, modulePath string) (string, error) {
+	escaped, err := module.EscapePath(modulePath)
+	if err != nil {
+		return "", fmt.Errorf("invalid module path %s: %w", modulePath, err)
+	}
+	url := p.url + "/" + escaped + "/@latest"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request for %s: %w", url, err)
+	}
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return "", fmt.Errorf("proxy returned %s for %s: %s", resp.Status, modulePath, strings.TrimSpace(string(body)))
+	}
+
+	var info struct {
+		Version string
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return "", fmt.Errorf("invalid proxy response for %s: %w", modulePath, err)
+	}
+	if !semver.IsValid(info.Version) {
+		return "", fmt.Errorf("proxy returned invalid version %q for %s", info.Version, modulePath)
+	}
+	return info.Version, nil
+}
+
+// Update bumps the modules, or every module of the catalog when none are
+// given, to the latest version latest returns, and records the bumps as a
+// new revision. Versions are never lowered. Nothing changes unless every
+// module could be looked up.
+func (c *Catalog) Update(ctx context.Context, latest func(ctx context.Context, modulePath string) (string, error), modules []string, now time.Time) ([]Change, error) {
+	if len(modules) == 0 {
+		modules = c.Paths()
+	}
+
+	var changes []Change
+	var errs []error
+	for _, modulePath := range modules {
+		current, ok := c.Modules[modulePath]
+		if !ok {
+			errs = append(errs, fmt.Errorf("module %s is not in the catalog", modulePath))
+			continue
+		}
+		version, err := latest(ctx, modulePath)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if semver.Compare(version, current) > 0 {
+			changes = append(changes, Change{Module: modulePath, From: current, To: version})
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	if len(changes) == 0 {
+		return nil, nil
+	}
+
+	for _, change := range changes {
+		c.Modules[change.Module] = change.To
+	}
+	c.Version++
+	c.Updated = now.UTC()
+	c.History = append(c.History, Revision{Version: c.Version, Date: c.Updated, Changes: changes})
+	return changes, nil
+}
@@ -0,0 +1,86 @@
+package cicd
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// CoverageFile is the go-test-coverage configuration written with coverage
+// exclusions
+const CoverageFile = ".testcoverage.yml"
+
+// Coverage exclusions standing for the patterns of common kinds of code
+// tests do not reach
+const (
+	ExcludeGenerated = "generated" // protobuf, gqlgen, sqlc and other generated code
+	ExcludeCmd       = "cmd"       // main packages under cmd/
+	ExcludeMocks     = "mocks"     // mocks packages and mock_*.go files
+)
+
+// CoverageExclusions lists the named coverage exclusions
+var CoverageExclusions = []string{ExcludeGenerated, ExcludeCmd, ExcludeMocks}
+
+// coverageExclusionPatterns are the path patterns of the named exclusions
+var coverageExclusionPatterns = map[string][]string{
+	ExcludeGenerated: {`\.pb(\.gw)?\.go$`, `_gen(erated)?\.go$`, `(^|/)zz_generated[^/]*\.go$`, `(^|/)generated/`},
+	ExcludeCmd:       {`^cmd/`},
+	ExcludeMocks:     {`(^|/)mocks?/`, `(^|/)mock_[^/]*\.go$`, `_mock\.go$`},
+}
+
+// CoveragePatterns expands coverage exclusions into the path patterns left
+// out of coverage. An exclusion is a name of CoverageExclusions or a regular
+// expression matched against the files' paths in the module, such as
+// ^internal/legacy/.
+func CoveragePatterns(exclusions []string) ([]string, error) {
+	var patterns []string
+	for _, exclusion := range exclusions {
+		exclusion = strings.TrimSpace(exclusion)
+		if exclusion == "" {
+			continue
+		}
+		expanded, ok := coverageExclusionPatterns[strings.ToLower(exclusion)]
+		if !ok {
+			if _, err := regexp.Compile(exclusion); err != nil {
+				return nil, fmt.Errorf("invalid coverage exclusion %q: neither one of %s nor a regular expression: %w", exclusion, strings.Join(CoverageExclusions, ", "), err)
+			}
+			// The pattern is quoted for the shell and YAML
+			if strings.Contains(exclusion, "'") {
+				return nil, fmt.Errorf("invalid coverage exclusion %q: quotes are not allowed", exclusion)
+			}
+			expanded = []string{exclusion}
+		}
+		for _, pattern := range expanded {
+			if !slices.Contains(patterns, pattern) {
+				patterns = append(patterns, pattern)
+			}
+		}
+	}
+	return patterns, nil
+}
+
+// GenerateCoverageConfig generates .testcoverage.yml, the configuration of
+// go-test-coverage with the threshold and exclusions of the pipeline
+func (g *Generator) GenerateCoverageConfig(ctx context.Context, outputDir string, config Config) error {
+	template, err := loadTemplate("testcoverage.yml.tmpl")
+	if err != nil {
+		return err
+	}
+	patterns, err := CoveragePatterns(config.CoverageExclude)
+	if err != nil {
+		return err
+	}
+
+	variables := map[string]any{
+		"ModuleName":        config.ModuleName,
+		"CoverageThreshold": int(math.Round(config.CoverageMin * 100)),
+		"CoveragePatterns":  patterns,
+	}
+
+	outputPath := filepath.Join(outputDir, CoverageFile)
+	return g.templateEngine.RenderToFile(ctx, template, variables, outputPath)
+}
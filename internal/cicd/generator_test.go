@@ -2,12 +2,14 @@ package cicd
 
 import (
 	"context"
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
 )
 
 func TestNewGenerator(t *testing.T) {
@@ -245,3 +247,242 @@ func TestGenerator_GenerateGitHubActions_NoDatabaseService(t *testing.T) {
 	assert.NotContains(t, contentStr, "postgres:")
 	assert.NotContains(t, contentStr, "DATABASE_URL")
 }
+
+func TestGenerator_GenerateGitHubActions_ScaffoldCheck(t *testing.T) {
+	for version, install := range map[string]string{
+		"v1.4.0":              GogoPackage + "@v1.4.0",
+		"1.4.0":               GogoPackage + "@v1.4.0",
+		"v1.4.0-3-gabc-dirty": GogoPackage + "@latest",
+		"dev":                 GogoPackage + "@latest",
+		"":                    GogoPackage + "@latest",
+	} {
+		tmpDir := t.TempDir()
+		config := Config{ProjectName: "demo", GoVersion: "1.25.1", GogoVersion: version}
+		require.NoError(t, NewGenerator().GenerateGitHubActions(context.Background(), tmpDir, config))
+
+		content, err := os.ReadFile(filepath.Join(tmpDir, ".github", "workflows", "ci.yml"))
+		require.NoError(t, err)
+		assert.Contains(t, string(content), "run: go install "+install+"\n", version)
+		assert.Contains(t, string(content), "run: gogo drift --fail-on-change\n")
+	}
+}
+
+func TestGenerator_GenerateGitHubActions_LicenseReport(t *testing.T) {
+	tmpDir := t.TempDir()
+	config := Config{ProjectName: "demo", GoVersion: "1.25.1", LicenseReport: true}
+	require.NoError(t, NewGenerator().GenerateGitHubActions(context.Background(), tmpDir, config))
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, ".github", "workflows", "ci.yml"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "run: sh scripts/licenses.sh\n")
+	assert.Contains(t, string(content), "path: third_party_licenses.csv\n")
+
+	config.LicenseReport = false
+	require.NoError(t, NewGenerator().GenerateGitHubActions(context.Background(), tmpDir, config))
+	content, err = os.ReadFile(filepath.Join(tmpDir, ".github", "workflows", "ci.yml"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), "licenses")
+}
+
+func TestParseProvider(t *testing.T) {
+	for name, want := range map[string]string{
+		"":          ProviderGitHub,
+		"GitHub":    ProviderGitHub,
+		"gitlab-ci": ProviderGitLab,
+		"circleci":  ProviderCircleCI,
+	} {
+		provider, err := ParseProvider(name)
+		require.NoError(t, err)
+		assert.Equal(t, want, provider, name)
+	}
+
+	_, err := ParseProvider("jenkins")
+	assert.ErrorContains(t, err, "unknown CI provider")
+}
+
+func TestGenerator_GenerateAll_Providers(t *testing.T) {
+	for _, provider := range []string{ProviderGitLab, ProviderCircleCI} {
+		t.Run(provider, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			config := Config{
+				Provider:      provider,
+				ProjectName:   "fullproject",
+				GoVersion:     "1.25.1",
+				CoverageMin:   0.75,
+				HasDatabase:   true,
+				LicenseReport: true,
+			}
+
+			require.NoError(t, NewGenerator().GenerateAll(context.Background(), tmpDir, config))
+			for _, file := range Files(config) {
+				assert.FileExists(t, filepath.Join(tmpDir, file))
+			}
+			assert.NoDirExists(t, filepath.Join(tmpDir, ".github"))
+
+			content, err := os.ReadFile(filepath.Join(tmpDir, PipelineFile(provider)))
+			require.NoError(t, err)
+			var pipeline map[string]any
+			require.NoError(t, yaml.Unmarshal(content, &pipeline), "the pipeline must be valid YAML")
+
+			contentStr := string(content)
+			assert.Contains(t, contentStr, "go test -race -covermode atomic -coverprofile=coverage.out ./...")
+			assert.Contains(t, contentStr, "coverage < 75")
+			assert.Contains(t, contentStr, "postgres:postgres@")
+			assert.Contains(t, contentStr, "golangci-lint run")
+			assert.Contains(t, contentStr, "go install "+GogoPackage+"@latest")
+			assert.Contains(t, contentStr, "gogo drift --fail-on-change")
+			assert.Contains(t, contentStr, "sh scripts/licenses.sh")
+			assert.Contains(t, contentStr, `["linux", "darwin", "windows"]`)
+			assert.Contains(t, contentStr, "./cmd/fullproject")
+		})
+	}
+}
+
+func TestParseUpdateBot(t *testing.T) {
+	for name, want := range map[string]string{
+		"":           "",
+		"none":       "",
+		"Dependabot": UpdatesDependabot,
+		"renovate":   UpdatesRenovate,
+	} {
+		bot, err := ParseUpdateBot(name)
+		require.NoError(t, err)
+		assert.Equal(t, want, bot, name)
+	}
+
+	_, err := ParseUpdateBot("greenkeeper")
+	assert.ErrorContains(t, err, "unknown dependency update bot")
+}
+
+func TestGenerator_GenerateDependencyUpdates(t *testing.T) {
+	t.Run("dependabot", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		config := Config{ProjectName: "testproject", DependencyUpdates: UpdatesDependabot}
+		require.NoError(t, NewGenerator().GenerateAll(context.Background(), tmpDir, config))
+		for _, file := range Files(config) {
+			assert.FileExists(t, filepath.Join(tmpDir, file))
+		}
+
+		content, err := os.ReadFile(filepath.Join(tmpDir, ".github", "dependabot.yml"))
+		require.NoError(t, err)
+		var dependabot struct {
+			Version int `yaml:"version"`
+			Updates []struct {
+				Ecosystem string `yaml:"package-ecosystem"`
+			} `yaml:"updates"`
+		}
+		require.NoError(t, yaml.Unmarshal(content, &dependabot))
+		assert.Equal(t, 2, dependabot.Version)
+		require.Len(t, dependabot.Updates, 2)
+		assert.Equal(t, "gomod", dependabot.Updates[0].Ecosystem)
+		assert.Equal(t, "github-actions", dependabot.Updates[1].Ecosystem)
+	})
+
+	t.Run("renovate", func(t *testing.T) {
+		for provider, manager := range map[string]string{
+			ProviderGitHub:   "github-actions",
+			ProviderGitLab:   "gitlabci",
+			ProviderCircleCI: "circleci",
+		} {
+			tmpDir := t.TempDir()
+			config := Config{Provider: provider, ProjectName: "testproject", DependencyUpdates: UpdatesRenovate}
+			require.NoError(t, NewGenerator().GenerateDependencyUpdates(context.Background(), tmpDir, config))
+
+			content, err := os.ReadFile(filepath.Join(tmpDir, "renovate.json"))
+			require.NoError(t, err)
+			var renovate struct {
+				EnabledManagers []string `json:"enabledManagers"`
+				PackageRules    []struct {
+					MatchManagers []string `json:"matchManagers"`
+				} `json:"packageRules"`
+			}
+			require.NoError(t, json.Unmarshal(content, &renovate), "renovate.json must be valid JSON")
+			assert.Equal(t, []string{"gomod", manager}, renovate.EnabledManagers, provider)
+			require.Len(t, renovate.PackageRules, 2)
+			assert.Equal(t, []string{manager}, renovate.PackageRules[1].MatchManagers)
+		}
+	})
+
+	t.Run("dependabot without GitHub Actions", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		config := Config{Provider: ProviderGitLab, DependencyUpdates: UpdatesDependabot}
+		require.NoError(t, NewGenerator().GenerateDependencyUpdates(context.Background(), tmpDir, config))
+		content, err := os.ReadFile(filepath.Join(tmpDir, ".github", "dependabot.yml"))
+		require.NoError(t, err)
+		assert.NotContains(t, string(content), "github-actions")
+	})
+}
+
+func TestGenerator_GenerateCircleCI_CacheKey(t *testing.T) {
+	tmpDir := t.TempDir()
+	config := Config{ProjectName: "testproject", GoVersion: "1.25.1", BuildTargets: []string{"linux"}}
+
+	require.NoError(t, NewGenerator().GenerateCircleCI(context.Background(), tmpDir, config))
+	content, err := os.ReadFile(filepath.Join(tmpDir, ".circleci", "config.yml"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `key: go-mod-1.25.1-{{ checksum "go.sum" }}`)
+	assert.Contains(t, string(content), "image: cimg/go:1.25.1")
+	assert.NotContains(t, string(content), "licenses")
+}
+
+func TestCoveragePatterns(t *testing.T) {
+	patterns, err := CoveragePatterns([]string{"cmd", "Mocks", `^internal/legacy/`, "cmd"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{`^cmd/`, `(^|/)mocks?/`, `(^|/)mock_[^/]*\.go$`, `_mock\.go$`, `^internal/legacy/`}, patterns)
+
+	_, err = CoveragePatterns([]string{"gen(erated"})
+	assert.ErrorContains(t, err, "neither one of generated, cmd, mocks")
+	_, err = CoveragePatterns([]string{`it's`})
+	assert.ErrorContains(t, err, "quotes")
+}
+
+func TestGenerator_GenerateAll_CoverageExclude(t *testing.T) {
+	for _, provider := range Providers {
+		t.Run(provider, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			config := Config{
+				Provider:        provider,
+				ProjectName:     "svc",
+				ModuleName:      "github.com/acme/svc",
+				CoverageMin:     0.85,
+				CoverageExclude: []string{"generated", "cmd"},
+			}
+			require.NoError(t, NewGenerator().GenerateAll(context.Background(), tmpDir, config))
+			assert.Contains(t, Files(config), CoverageFile)
+
+			pipeline, err := os.ReadFile(filepath.Join(tmpDir, PipelineFile(provider)))
+			require.NoError(t, err)
+			var parsed map[string]any
+			require.NoError(t, yaml.Unmarshal(pipeline, &parsed))
+			assert.Contains(t, string(pipeline), `COVERAGE_EXCLUDE`)
+			assert.Contains(t, string(pipeline), `'\.pb(\.gw)?\.go$|`)
+			assert.Contains(t, string(pipeline), `|^cmd/'`)
+			assert.Contains(t, string(pipeline), "mv coverage.filtered.out coverage.out")
+
+			data, err := os.ReadFile(filepath.Join(tmpDir, CoverageFile))
+			require.NoError(t, err)
+			var coverage struct {
+				LocalPrefix string `yaml:"local-prefix"`
+				Threshold   struct {
+					Total int `yaml:"total"`
+				} `yaml:"threshold"`
+				Exclude struct {
+					Paths []string `yaml:"paths"`
+				} `yaml:"exclude"`
+			}
+			require.NoError(t, yaml.Unmarshal(data, &coverage))
+			assert.Equal(t, "github.com/acme/svc", coverage.LocalPrefix)
+			assert.Equal(t, 85, coverage.Threshold.Total)
+			assert.Contains(t, coverage.Exclude.Paths, `\.pb(\.gw)?\.go$`)
+			assert.Contains(t, coverage.Exclude.Paths, `^cmd/`)
+		})
+	}
+
+	// Without exclusions, coverage covers everything as before
+	tmpDir := t.TempDir()
+	require.NoError(t, NewGenerator().GenerateAll(context.Background(), tmpDir, Config{ProjectName: "svc"}))
+	assert.NoFileExists(t, filepath.Join(tmpDir, CoverageFile))
+	pipeline, err := os.ReadFile(filepath.Join(tmpDir, PipelineFile(ProviderGitHub)))
+	require.NoError(t, err)
+	assert.NotContains(t, string(pipeline), "COVERAGE_EXCLUDE")
+}
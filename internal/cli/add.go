@@ -2,27 +2,426 @@ package cli
 
 import (
 	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
+	"github.com/user/gogo/internal/components"
+	"github.com/user/gogo/internal/deploy"
+	"github.com/user/gogo/internal/docker"
+	"github.com/user/gogo/internal/policy"
+	"github.com/user/gogo/internal/project"
+	"github.com/user/gogo/internal/workspace"
 )
 
+// addTypes are the component types gogo add generates
+var addTypes = []string{"handler", "model", "service", "repository", "proto", "resolver", "job", "middleware", "migration", "test"}
+
+// addDeployTargets are the cluster targets gogo add deploy generates
+var addDeployTargets = []string{deploy.TargetKubernetes, deploy.TargetHelm}
+
 func newAddCommand() *cobra.Command {
+	var (
+		framework  string
+		database   string
+		target     string
+		dockerOpts dockerFlags
+		force      bool
+	)
+
 	cmd := &cobra.Command{
-		Use:   "add",
+		Use:   "add <type> <name>",
 		Short: "Add components to existing project",
-		Long: color.GreenString(`Add components to an existing Go project.
+		Long: color.GreenString(`Add a component to the Go project in the current directory.
+
+The project root is the nearest directory with a go.mod, at or above the
+current one; the module path and project name come from it. Types are
+handler, model, service, repository, proto, resolver, job, middleware,
+migration and test. Existing files are never overwritten unless --force is given.
+
+In projects generated by gogo, --framework and --database default to the
+framework and database library recorded in .gogo.yaml. A repository is an
+interface in internal/repository with an implementation on the database
+library (gorm, sqlx or pgx), an in-memory mock and tests; the tests of the
+implementation run against the Postgres database in TEST_DATABASE_URL.
+Services come with their repository and call it, and their tests use the
+mock. With sqlx, queries run through prepared statements; with pgx, through
+its statement cache.
+
+A proto is a gRPC service: a .proto file under proto/, a server stub in
+internal/server registered by RegisterServices, a make proto target that
+generates the Go code with buf or protoc, and buf's configuration.
+
+A resolver is a GraphQL type of the graphql stack: a schema under graph/
+extending Query and Mutation, its resolvers, and an in-memory store added
+to the root Resolver. Run go generate ./... afterwards to regenerate the
+gqlgen code.
+
+A job is a background job of the worker stack: a handler and its payload
+under internal/jobs, with a test, registered with the worker in Register.
+
+add deploy takes no name and generates cluster deployment files for the
+project's container image: with --target k8s (or kubernetes), a Deployment,
+Service, HorizontalPodAutoscaler and ConfigMap under deploy/kubernetes,
+applied with kubectl apply -k; with --target helm, a chart in
+deploy/helm/<project> with the same resources set from values.yaml.
+Blueprints generate them at gogo init with deployment.target.
 
-This is an alias for the generate command for convenience.
+add docker takes no name and generates a Dockerfile building cmd/<project>:
+a multi-stage build copying the binary onto alpine, or distroless with
+--runtime distroless, running as an unprivileged user unless --root is
+given. --build-arg declares build arguments with their defaults, --port
+exposes a port and --health-check requests /health on it. Blueprints
+generate it at gogo init from their docker section.
+
+For workspace services, shared libraries and variants, use gogo generate.
 
 Examples:
-  gogo add --type=handler --name=Health
-  gogo add --type=model --name=User`),
+  gogo add handler user
+  gogo add handler user --framework chi
+  gogo add model order --database sqlx
+  gogo add service order --database pgx
+  gogo add repository order --database sqlx
+  gogo add proto order                       # Then make proto
+  gogo add resolver product                  # Then go generate ./...
+  gogo add job send_email
+  gogo add migration create_orders
+  gogo add middleware auth --framework echo
+  gogo add deploy --target k8s
+  gogo add deploy --target helm
+  gogo add docker --runtime distroless --port 8080
+  gogo add docker --port 8080 --health-check --build-arg VERSION=dev
+  gogo add handler user --json               # Print the files created as JSON`),
+		Args: func(cmd *cobra.Command, args []string) error {
+			if len(args) > 0 && (args[0] == "deploy" || args[0] == "docker") {
+				return cobra.ExactArgs(1)(cmd, args)
+			}
+			return cobra.ExactArgs(2)(cmd, args)
+		},
+		ValidArgs: append(slices.Clone(addTypes), "deploy", "docker"),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			color.Yellow("Add command is an alias for generate")
-			return fmt.Errorf("add command not fully implemented yet")
+			switch args[0] {
+			case "deploy":
+				return addDeployment(cmd, target, force)
+			case "docker":
+				return addDockerfile(cmd, dockerOpts, force)
+			}
+			componentType, name := args[0], args[1]
+			if !slices.Contains(addTypes, componentType) {
+				return fmt.Errorf("unsupported component type '%s', supported types: %s", componentType, strings.Join(addTypes, ", "))
+			}
+			// The arguments are valid; later failures are not usage errors
+			cmd.SilenceUsage = true
+
+			root, err := findModuleRoot(".")
+			if err != nil {
+				return err
+			}
+			modulePath, err := workspace.ModulePath(root)
+			if err != nil {
+				return err
+			}
+
+			projectName := projectNameFromModule(modulePath)
+			// Components use the constructs of the project's Go version
+			projectGoVersion, languageLevel := goVersion, ""
+
+			// Default to the stack the project was generated with
+			if project.HasManifest(root) {
+				manifest, err := project.LoadManifest(root)
+				if err != nil {
+					return err
+				}
+				if manifest.ProjectName != "" {
+					projectName = manifest.ProjectName
+				}
+				if framework == "" {
+					framework = manifest.Framework
+				}
+				if database == "" {
+					database = manifest.Database
+				}
+				if projectGoVersion == "" {
+					projectGoVersion = manifest.GoVersion
+					languageLevel, _ = manifest.Variables["LanguageLevel"].(string)
+				}
+			}
+			if framework == "" {
+				framework = settings.Framework
+			}
+
+			generator := components.NewGenerator()
+			orgPolicy, err := policy.Load(policy.DefaultPath())
+			if err != nil {
+				return err
+			}
+			bus, closeEvents := useEventBus(cmd.Context(), orgPolicy.Hooks)
+			defer closeEvents()
+			generator.SetEvents(bus)
+			opts := components.GenerateOptions{
+				Type:          componentType,
+				Name:          name,
+				OutputDir:     root,
+				ProjectName:   projectName,
+				ModuleName:    modulePath,
+				GoVersion:     projectGoVersion,
+				LanguageLevel: languageLevel,
+				Framework:     framework,
+				Database:      database,
+				Force:         force,
+				DryRun:        dryRun,
+			}
+
+			// Render the paths first so nothing is written over existing files
+			if !force && !dryRun {
+				planned := opts
+				planned.DryRun = true
+				result, err := generator.Generate(cmd.Context(), planned)
+				if err != nil {
+					return fmt.Errorf("failed to generate component: %w", err)
+				}
+				var existing []string
+				for _, file := range result.Files {
+					if _, err := os.Stat(filepath.Join(root, file)); err == nil {
+						existing = append(existing, file)
+					}
+				}
+				if len(existing) > 0 {
+					return fmt.Errorf("refusing to overwrite existing files: %s (use --force)", strings.Join(existing, ", "))
+				}
+			}
+
+			color.Yellow("Adding %s %s to %s", componentType, name, modulePath)
+			result, err := generator.Generate(cmd.Context(), opts)
+			if err != nil {
+				return fmt.Errorf("failed to generate component: %w", err)
+			}
+
+			return printAdded(addResult{Type: componentType, Name: name, DryRun: dryRun, Message: result.Message}, root, result.Files)
 		},
 	}
 
-	return cmd
+	cmd.Flags().StringVar(&framework, "framework", "", "HTTP framework for handlers and middleware ("+strings.Join(components.Frameworks, ", ")+"; default from .gogo.yaml, else gogo config, else gin)")
+	cmd.Flags().StringVar(&database, "database", "", "Database library for models and services ("+strings.Join(components.Databases, ", ")+"; default from .gogo.yaml, else gorm)")
+	cmd.Flags().StringVar(&target, "target", "", "Deployment target of add deploy ("+strings.Join(addDeployTargets, ", ")+")")
+	cmd.Flags().StringVar(&dockerOpts.runtime, "runtime", "", "Runtime image of add docker ("+strings.Join(docker.Runtimes, ", ")+"; default alpine)")
+	cmd.Flags().BoolVar(&dockerOpts.singleStage, "single-stage", false, "Run the binary in the Go image instead of a runtime image (add docker)")
+	cmd.Flags().BoolVar(&dockerOpts.root, "root", false, "Run the container as root instead of an unprivileged user (add docker)")
+	cmd.Flags().StringArrayVar(&dockerOpts.buildArgs, "build-arg", nil, "Build argument NAME=default of add docker (repeatable)")
+	cmd.Flags().IntVar(&dockerOpts.port, "port", 0, "Port the container exposes (add docker)")
+	cmd.Flags().BoolVar(&dockerOpts.healthCheck, "health-check", false, "Check /health on --port from a HEALTHCHECK (add docker)")
+	cmd.Flags().BoolVar(&force, "force", false, "Overwrite existing files")
+
+	return supportsJSON(cmd)
+}
+
+// addDeployment generates the Kubernetes manifests or Helm chart of target
+// for the project in the current directory
+func addDeployment(cmd *cobra.Command, target string, force bool) error {
+	if target == "" {
+		return fmt.Errorf("add deploy needs --target (%s)", strings.Join(addDeployTargets, ", "))
+	}
+	config, err := deploy.ParseConfig(map[string]any{"target": target})
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(addDeployTargets, config.Targets[0]) {
+		return fmt.Errorf("add deploy generates cluster deployments (%s); host targets come from a blueprint's deployment.target", strings.Join(addDeployTargets, ", "))
+	}
+	cmd.SilenceUsage = true
+
+	root, err := findModuleRoot(".")
+	if err != nil {
+		return err
+	}
+	config.ModuleName, err = workspace.ModulePath(root)
+	if err != nil {
+		return err
+	}
+	config.ProjectName = projectNameFromModule(config.ModuleName)
+	if project.HasManifest(root) {
+		manifest, err := project.LoadManifest(root)
+		if err != nil {
+			return err
+		}
+		if !deploy.SupportsTemplate(manifest.Template) {
+			return fmt.Errorf("the %s template does not run as a service and cannot be deployed", manifest.Template)
+		}
+		if manifest.ProjectName != "" {
+			config.ProjectName = manifest.ProjectName
+		}
+		config.Description, _ = manifest.Variables["Description"].(string)
+	}
+
+	files := deploy.Files(config)
+	if !force && !dryRun {
+		var existing []string
+		for _, file := range files {
+			if _, err := os.Stat(filepath.Join(root, file)); err == nil {
+				existing = append(existing, file)
+			}
+		}
+		if len(existing) > 0 {
+			return fmt.Errorf("refusing to overwrite existing files: %s (use --force)", strings.Join(existing, ", "))
+		}
+	}
+	if dryRun {
+		return printAdded(addResult{Type: "deploy", Name: config.Targets[0], DryRun: true, Message: fmt.Sprintf("Would create %d files", len(files))}, root, files)
+	}
+
+	color.Yellow("Adding %s deployment to %s", config.Targets[0], config.ModuleName)
+	if err := deploy.NewGenerator().GenerateAll(cmd.Context(), root, config); err != nil {
+		return fmt.Errorf("failed to generate deployment: %w", err)
+	}
+	message := fmt.Sprintf("Generated %s deployment for %s", config.Targets[0], config.ProjectName)
+	return printAdded(addResult{Type: "deploy", Name: config.Targets[0], Message: message}, root, files)
+}
+
+// dockerFlags are the flags of gogo add docker
+type dockerFlags struct {
+	runtime     string
+	singleStage bool
+	root        bool
+	buildArgs   []string
+	port        int
+	healthCheck bool
+}
+
+// addDockerfile generates the Dockerfile of the project in the current
+// directory from the add docker flags
+func addDockerfile(cmd *cobra.Command, flags dockerFlags, force bool) error {
+	section := map[string]any{
+		"multi_stage":  !flags.singleStage,
+		"non_root":     !flags.root,
+		"health_check": flags.healthCheck,
+	}
+	if flags.runtime != "" {
+		section["runtime"] = flags.runtime
+	}
+	if flags.port != 0 {
+		section["expose"] = flags.port
+	}
+	if len(flags.buildArgs) > 0 {
+		buildArgs := make(map[string]any, len(flags.buildArgs))
+		for _, arg := range flags.buildArgs {
+			name, value, _ := strings.Cut(arg, "=")
+			buildArgs[name] = value
+		}
+		section["build_args"] = buildArgs
+	}
+	config, err := docker.ParseConfig(section)
+	if err != nil {
+		return err
+	}
+	cmd.SilenceUsage = true
+
+	root, err := findModuleRoot(".")
+	if err != nil {
+		return err
+	}
+	modulePath, err := workspace.ModulePath(root)
+	if err != nil {
+		return err
+	}
+	config.ProjectName = projectNameFromModule(modulePath)
+	if project.HasManifest(root) {
+		manifest, err := project.LoadManifest(root)
+		if err != nil {
+			return err
+		}
+		if manifest.ProjectName != "" {
+			config.ProjectName = manifest.ProjectName
+		}
+		config.GoVersion = manifest.GoVersion
+	}
+	if goVersion != "" {
+		config.GoVersion = goVersion
+	}
+	if _, err := os.Stat(filepath.Join(root, "cmd", config.ProjectName)); err != nil {
+		return fmt.Errorf("no cmd/%s to build; the Dockerfile builds the project's main package there", config.ProjectName)
+	}
+
+	files := docker.Files()
+	if !force && !dryRun {
+		for _, file := range files {
+			if _, err := os.Stat(filepath.Join(root, file)); err == nil {
+				return fmt.Errorf("refusing to overwrite existing files: %s (use --force)", file)
+			}
+		}
+	}
+	if dryRun {
+		return printAdded(addResult{Type: "docker", DryRun: true, Message: fmt.Sprintf("Would create %d files", len(files))}, root, files)
+	}
+
+	color.Yellow("Adding a Dockerfile to %s", modulePath)
+	if err := docker.NewGenerator().Generate(cmd.Context(), root, config); err != nil {
+		return fmt.Errorf("failed to generate Dockerfile: %w", err)
+	}
+	message := fmt.Sprintf("Generated a Dockerfile for %s", config.ProjectName)
+	return printAdded(addResult{Type: "docker", Message: message}, root, files)
+}
+
+// addResult is what gogo add generated, as printed with --json
+type addResult struct {
+	Type    string   `json:"type"`
+	Name    string   `json:"name,omitempty"` // component name, or deployment target
+	DryRun  bool     `json:"dry_run,omitempty"`
+	Message string   `json:"message"`
+	Files   []string `json:"files"`
+}
+
+// printAdded prints result with the files, relative to root, that gogo add
+// created or would create
+func printAdded(result addResult, root string, files []string) error {
+	result.Files = make([]string, 0, len(files))
+	for _, file := range files {
+		result.Files = append(result.Files, filepath.ToSlash(filepath.Join(root, file)))
+	}
+	if jsonOutput() {
+		return printJSON(result)
+	}
+
+	color.Green(result.Message)
+	for _, file := range result.Files {
+		color.Cyan("  - %s", file)
+	}
+	return nil
+}
+
+// findModuleRoot returns the nearest directory at or above dir with a go.mod
+func findModuleRoot(dir string) (string, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", dir, err)
+	}
+	for current := abs; ; current = filepath.Dir(current) {
+		if _, err := os.Stat(filepath.Join(current, "go.mod")); err == nil {
+			// Keep paths in output relative to where the command ran
+			if rel, err := filepath.Rel(abs, current); err == nil {
+				return rel, nil
+			}
+			return current, nil
+		}
+		if filepath.Dir(current) == current {
+			return "", fmt.Errorf("no go.mod found in %s or any parent directory (run gogo add inside a Go project)", abs)
+		}
+	}
+}
+
+// majorVersionSuffix matches the /vN element of a major version module path
+var majorVersionSuffix = regexp.MustCompile(`^v[0-9]+$`)
+
+// projectNameFromModule derives the project name from a module path, skipping
+// a major version suffix: github.com/acme/api/v2 is api
+func projectNameFromModule(modulePath string) string {
+	name := path.Base(modulePath)
+	if majorVersionSuffix.MatchString(name) && path.Dir(modulePath) != "." {
+		name = path.Base(path.Dir(modulePath))
+	}
+	return name
 }
@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/user/gogo/internal/assets"
+)
+
+func newAssetsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "assets",
+		Short: "Inspect assets embedded in the gogo binary",
+		Long: color.GreenString(`Inspect the templates, blueprint defaults, CI templates and migrations
+compiled into the gogo binary. gogo never reads them from disk.`),
+	}
+
+	cmd.AddCommand(newAssetsVerifyCommand())
+	cmd.AddCommand(newAssetsListCommand())
+
+	return cmd
+}
+
+func newAssetsVerifyCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "verify",
+		Short: "Verify embedded asset integrity",
+		Long: color.GreenString(`Check every embedded asset against the checksums recorded at build time,
+make sure every template parses, and load the blueprint defaults and
+migrations. The printed digest identifies the asset set, so two binaries
+can be compared.`),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			report, err := assets.Verify()
+			if err != nil {
+				return fmt.Errorf("failed to verify assets: %w", err)
+			}
+
+			if !report.OK() {
+				color.Red("✗ %d of %d assets failed verification:", len(report.Problems), report.Assets)
+				for _, problem := range report.Problems {
+					fmt.Printf("  %s: %s\n", problem.Path, problem.Message)
+				}
+				return fmt.Errorf("embedded assets failed verification")
+			}
+
+			color.Green("✓ %d embedded assets verified", report.Assets)
+			fmt.Printf("Digest: %s\n", report.Digest)
+			return nil
+		},
+	}
+}
+
+func newAssetsListCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List embedded assets with their checksums",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			all, err := assets.All()
+			if err != nil {
+				return fmt.Errorf("failed to list assets: %w", err)
+			}
+			fmt.Print(assets.FormatSums(all))
+			return nil
+		},
+	}
+}
@@ -0,0 +1,162 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/manifoldco/promptui"
+	"github.com/spf13/cobra"
+	"github.com/user/gogo/internal/auth"
+)
+
+func newAuthCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "auth",
+		Short: "Manage credentials for git hosts and registries",
+		Long: color.GreenString(`Manage the tokens gogo uses for git hosts and template registries.
+
+Credentials are resolved in this order; the first match wins:
+  1. Environment: GOGO_TOKEN_<HOST> (e.g. GOGO_TOKEN_GITHUB_COM), then
+     GH_TOKEN/GITHUB_TOKEN for github.com and GITLAB_TOKEN for gitlab.com
+  2. OS keychain (macOS Keychain, or Secret Service via secret-tool on Linux)
+  3. git credential helpers (git credential fill)
+
+gogo never writes tokens to its config files.`),
+	}
+
+	cmd.AddCommand(newAuthLoginCommand())
+	cmd.AddCommand(newAuthStatusCommand())
+
+	return cmd
+}
+
+func newAuthLoginCommand() *cobra.Command {
+	var (
+		host      string
+		username  string
+		withToken bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "login",
+		Short: "Store a token in the OS keychain or a git credential helper",
+		Long: color.GreenString(`Store a token for a host in the OS keychain, falling back to your git
+credential helper when no keychain is available.
+
+Examples:
+  gogo auth login                                  # Prompt for a github.com token
+  gogo auth login --host gitlab.example.com
+  echo "$TOKEN" | gogo auth login --with-token     # Read the token from stdin`),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			token, err := readToken(host, withToken)
+			if err != nil {
+				return err
+			}
+
+			store, err := auth.NewResolver().Login(cmd.Context(), auth.Credential{Host: host, Username: username, Token: token})
+			if err != nil {
+				return err
+			}
+			color.Green("✓ Token for %s stored in %s", host, store)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&host, "host", auth.DefaultHost, "Host the token is for")
+	cmd.Flags().StringVar(&username, "username", "", "Username for git credential helpers (default x-access-token)")
+	cmd.Flags().BoolVar(&withToken, "with-token", false, "Read the token from standard input")
+
+	return cmd
+}
+
+// readToken reads a token from stdin or a masked prompt
+func readToken(host string, fromStdin bool) (string, error) {
+	if fromStdin {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			return "", fmt.Errorf("failed to read token from stdin: %w", err)
+		}
+		token := strings.TrimSpace(line)
+		if token == "" {
+			return "", fmt.Errorf("no token on stdin")
+		}
+		return token, nil
+	}
+
+	prompt := promptui.Prompt{
+		Label: fmt.Sprintf("Token for %s", host),
+		Mask:  '*',
+		Validate: func(input string) error {
+			if strings.TrimSpace(input) == "" {
+				return fmt.Errorf("token cannot be empty")
+			}
+			return nil
+		},
+	}
+	token, err := prompt.Run()
+	if err != nil {
+		return "", fmt.Errorf("token prompt failed: %w", err)
+	}
+	return strings.TrimSpace(token), nil
+}
+
+func newAuthStatusCommand() *cobra.Command {
+	var hosts []string
+
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show which credential gogo uses for each host",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			resolver := auth.NewResolver()
+			missing := 0
+			for _, host := range hosts {
+				color.Cyan("%s", host)
+				active := false
+				for _, status := range resolver.Status(cmd.Context(), host) {
+					fmt.Printf("  %s\n", describeStoreStatus(status))
+					active = active || status.Active
+				}
+				if !active {
+					color.Yellow("  no credential; run gogo auth login --host %s or set %s", host, auth.EnvVar(host))
+					missing++
+				}
+			}
+			if missing > 0 {
+				return fmt.Errorf("%d of %d hosts have no credential", missing, len(hosts))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&hosts, "host", []string{auth.DefaultHost}, "Hosts to report on")
+	cmd.SilenceUsage = true
+
+	return cmd
+}
+
+func describeStoreStatus(status auth.StoreStatus) string {
+	switch {
+	case status.Credential != nil:
+		line := fmt.Sprintf("%-15s %s", status.Store, status.Credential.Masked())
+		if status.Credential.Detail != "" {
+			line += " (" + status.Credential.Detail + ")"
+		}
+		if status.Credential.Username != "" {
+			line += " as " + status.Credential.Username
+		}
+		if status.Active {
+			return color.GreenString("✓ %s [active]", line)
+		}
+		return "  " + line + " [shadowed]"
+	case errors.Is(status.Err, auth.ErrNotFound):
+		return fmt.Sprintf("  %-15s not set", status.Store)
+	default:
+		return fmt.Sprintf("  %-15s unavailable: %v", status.Store, status.Err)
+	}
+}
@@ -0,0 +1,547 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/user/gogo/internal/blueprints"
+	"github.com/user/gogo/internal/db"
+	"gopkg.in/yaml.v3"
+)
+
+func newBlueprintCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "blueprint",
+		Short: "Blueprint management commands",
+		Long: color.GreenString(`Manage stack blueprints.
+
+Predefined blueprints are compiled into gogo. Custom blueprints are kept in
+the gogo database and take precedence over predefined blueprints of the
+same name in gogo init --blueprint.`),
+	}
+
+	cmd.AddCommand(newBlueprintValidateCommand())
+	cmd.AddCommand(newBlueprintCreateCommand())
+	cmd.AddCommand(newBlueprintListCommand())
+	cmd.AddCommand(newBlueprintShowCommand())
+	cmd.AddCommand(newBlueprintEditCommand())
+	cmd.AddCommand(newBlueprintDeleteCommand())
+
+	return cmd
+}
+
+func newBlueprintValidateCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "validate <file>",
+		Short: "Check a blueprint file without storing it",
+		Long: color.GreenString(`Check a YAML or JSON blueprint definition against the blueprint schema:
+known component names, database types, logging, tracing and test frameworks,
+a coverage minimum between 0 and 1, valid ports, replica counts, environment
+names and deployment settings. Every problem is listed with the field it
+concerns. gogo blueprint create and edit run the same checks.
+
+Examples:
+  gogo blueprint validate queue-stack.yaml
+  gogo blueprint validate queue-stack.yaml && gogo blueprint create queue-stack.yaml`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Used in scripts; the problems are the useful output
+			cmd.SilenceUsage = true
+
+			blueprint, err := readBlueprintFile(args[0])
+			if err != nil {
+				return err
+			}
+			color.Green("✓ Blueprint %s (stack %s) is valid", blueprint.Name, blueprint.Stack)
+			return nil
+		},
+	}
+}
+
+func newBlueprintCreateCommand() *cobra.Command {
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "create <file>",
+		Short: "Store a custom blueprint from a YAML or JSON file",
+		Long: color.GreenString(`Store a custom blueprint in the gogo database.
+
+The file holds a blueprint definition in YAML or JSON, in the format printed
+by gogo blueprint show --format yaml. It needs a name and a stack; the
+stack picks the templates gogo init renders. The definition is checked like
+gogo blueprint validate before it is stored.
+
+Examples:
+  gogo blueprint show web-stack --format yaml > queue-stack.yaml
+  gogo blueprint create queue-stack.yaml
+  gogo blueprint create queue-stack.yaml --force
+  gogo init orders --template api --blueprint queue-stack`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			blueprint, err := readBlueprintFile(args[0])
+			if err != nil {
+				return err
+			}
+
+			manager := db.NewManager()
+			if err := manager.Open(ctx, dbPath); err != nil {
+				return fmt.Errorf("failed to open database: %w", err)
+			}
+			defer func() {
+				if closeErr := manager.Close(); closeErr != nil {
+					color.Red("Warning: failed to close database: %v", closeErr)
+				}
+			}()
+
+			repo := blueprints.NewStoredRepository(manager)
+			err = repo.Create(ctx, blueprint)
+			if errors.Is(err, db.ErrBlueprintExists) && force {
+				err = repo.Update(ctx, blueprint)
+			}
+			if err != nil {
+				if errors.Is(err, db.ErrBlueprintExists) {
+					return fmt.Errorf("%w (use --force to replace it)", err)
+				}
+				return err
+			}
+
+			color.Green("✓ Stored blueprint %s (stack %s)", blueprint.Name, blueprint.Stack)
+			if repo.IsPredefined(blueprint.Name) {
+				color.Yellow("⚠ It replaces the predefined %s blueprint in gogo init", blueprint.Name)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&force, "force", false, "Replace a custom blueprint of the same name")
+	return cmd
+}
+
+func newBlueprintListCommand() *cobra.Command {
+	var (
+		stack string
+		tags  []string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List predefined and custom blueprints",
+		Long: color.GreenString(`List the predefined blueprints and the custom ones stored or installed in
+the database. A custom blueprint named like a predefined one overrides it.
+
+Use --stack to list the blueprints of one stack, --tag to list those
+carrying every tag given, and --json for a machine-readable list.
+
+Examples:
+  gogo blueprint list --stack web
+  gogo blueprint list --tag internal --tag payment`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			manager := db.NewManager()
+			if err := manager.Open(ctx, dbPath); err != nil {
+				return fmt.Errorf("failed to open database: %w", err)
+			}
+			defer func() {
+				if closeErr := manager.Close(); closeErr != nil {
+					color.Red("Warning: failed to close database: %v", closeErr)
+				}
+			}()
+
+			stored, err := db.NewBlueprintManager(manager).List(ctx)
+			if err != nil {
+				return err
+			}
+			sources := make(map[string]*db.StoredBlueprint, len(stored))
+			for _, entry := range stored {
+				sources[entry.Name] = entry
+			}
+			tagged, err := db.NewTagManager(manager).Tagged(ctx, db.KindBlueprint)
+			if err != nil {
+				return err
+			}
+
+			repo := blueprints.NewStoredRepository(manager)
+			all, err := repo.ListBlueprints(ctx)
+			if err != nil {
+				return err
+			}
+
+			entries := make([]blueprintListEntry, 0, len(all))
+			for _, blueprint := range all {
+				if stack != "" && blueprint.Stack != stack {
+					continue
+				}
+				if !db.HasAllTags(tagged[blueprint.Name], tags) {
+					continue
+				}
+				source, description := "predefined", blueprint.Description
+				if entry, ok := sources[blueprint.Name]; ok {
+					source = "custom"
+					if entry.Source != nil {
+						source = "installed"
+						if description == "" {
+							description = installOrigin(entry.Source)
+						}
+					}
+				}
+				_, custom := sources[blueprint.Name]
+				entries = append(entries, blueprintListEntry{
+					Name:        blueprint.Name,
+					Stack:       blueprint.Stack,
+					Source:      source,
+					Overrides:   custom && repo.IsPredefined(blueprint.Name),
+					Components:  blueprint.Config.Components,
+					Tags:        tagged[blueprint.Name],
+					Description: description,
+				})
+			}
+			if jsonOutput() {
+				return printJSON(entries)
+			}
+
+			if len(entries) == 0 {
+				color.Yellow("No blueprints match")
+				return nil
+			}
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "NAME\tSTACK\tSOURCE\tCOMPONENTS\tTAGS\tDESCRIPTION")
+			for _, entry := range entries {
+				source := entry.Source
+				if entry.Overrides {
+					source += " (overrides predefined)"
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", entry.Name, entry.Stack, source,
+					strings.Join(entry.Components, ","), strings.Join(entry.Tags, ","), entry.Description)
+			}
+			return w.Flush()
+		},
+	}
+
+	cmd.Flags().StringVar(&stack, "stack", "", "Only list blueprints of this stack")
+	cmd.Flags().StringSliceVar(&tags, "tag", nil, "Only list blueprints carrying all of these tags")
+	return supportsJSON(cmd)
+}
+
+// blueprintListEntry is a blueprint listed by gogo blueprint list
+type blueprintListEntry struct {
+	Name        string   `json:"name"`
+	Stack       string   `json:"stack"`
+	Source      string   `json:"source"` // predefined, custom or installed
+	Overrides   bool     `json:"overrides_predefined,omitempty"`
+	Components  []string `json:"components"`
+	Tags        []string `json:"tags,omitempty"`
+	Description string   `json:"description"`
+}
+
+func newBlueprintShowCommand() *cobra.Command {
+	var (
+		format string
+		vars   map[string]string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "show <name>",
+		Short: "Show a blueprint and the variables it resolves to",
+		Long: color.GreenString(`Print a blueprint and the template variables gogo init resolves from it.
+
+Project variables such as ProjectName can be given with --var to see how
+they combine with the blueprint. --format yaml or json prints only the
+definition, ready to edit and pass to gogo blueprint create.
+
+Examples:
+  gogo blueprint show web-stack
+  gogo blueprint show web-stack --var ProjectName=orders
+  gogo blueprint show web-stack --format yaml > my-stack.yaml`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			repo, closeStore := useBlueprintStore(ctx)
+			defer closeStore()
+
+			blueprint, err := repo.GetBlueprint(ctx, args[0])
+			if err != nil {
+				return err
+			}
+
+			switch format {
+			case "text":
+			case "yaml":
+				encoder := yaml.NewEncoder(os.Stdout)
+				encoder.SetIndent(2)
+				return encoder.Encode(definitionOf(blueprint))
+			case "json":
+				encoder := json.NewEncoder(os.Stdout)
+				encoder.SetIndent("", "  ")
+				return encoder.Encode(definitionOf(blueprint))
+			default:
+				return fmt.Errorf("unsupported format %q (use text, yaml or json)", format)
+			}
+
+			inputs := make(map[string]any, len(vars))
+			for name, value := range vars {
+				inputs[name] = value
+			}
+			resolved, err := blueprints.NewResolver().Resolve(ctx, blueprint, inputs)
+			if err != nil {
+				return fmt.Errorf("failed to resolve blueprint variables: %w", err)
+			}
+
+			color.Cyan("Blueprint: %s", blueprint.Name)
+			fmt.Printf("  Stack: %s\n", blueprint.Stack)
+			if blueprint.Description != "" {
+				fmt.Printf("  Description: %s\n", blueprint.Description)
+			}
+			if blueprint.NextSteps != "" {
+				fmt.Println("  Next steps:")
+				for _, line := range strings.Split(strings.TrimSpace(blueprint.NextSteps), "\n") {
+					fmt.Printf("    %s\n", line)
+				}
+			}
+
+			fmt.Println()
+			color.Cyan("Configuration:")
+			config, err := yaml.Marshal(blueprint.Config)
+			if err != nil {
+				return fmt.Errorf("failed to encode blueprint '%s': %w", blueprint.Name, err)
+			}
+			for _, line := range strings.Split(strings.TrimSuffix(string(config), "\n"), "\n") {
+				fmt.Printf("  %s\n", line)
+			}
+
+			fmt.Println()
+			color.Cyan("Resolved variables:")
+			names := make([]string, 0, len(resolved))
+			for name := range resolved {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			for _, name := range names {
+				fmt.Fprintf(w, "  %s\t%v\n", name, resolved[name])
+			}
+			return w.Flush()
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "text", "Output format (text, yaml, json)")
+	cmd.Flags().StringToStringVar(&vars, "var", nil, "Project variable to resolve with (name=value, repeatable)")
+	return cmd
+}
+
+func newBlueprintEditCommand() *cobra.Command {
+	var file string
+
+	cmd := &cobra.Command{
+		Use:   "edit <name>",
+		Short: "Edit a custom blueprint",
+		Long: color.GreenString(`Edit a custom blueprint in $EDITOR, or replace it with the definition in
+--file. The name cannot be changed; create a new blueprint instead.
+
+Predefined blueprints cannot be edited. Override one by creating a custom
+blueprint of the same name from gogo blueprint show --format yaml.
+
+Examples:
+  gogo blueprint edit queue-stack
+  gogo blueprint edit queue-stack --file queue-stack.yaml`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			manager := db.NewManager()
+			if err := manager.Open(ctx, dbPath); err != nil {
+				return fmt.Errorf("failed to open database: %w", err)
+			}
+			defer func() {
+				if closeErr := manager.Close(); closeErr != nil {
+					color.Red("Warning: failed to close database: %v", closeErr)
+				}
+			}()
+
+			repo := blueprints.NewStoredRepository(manager)
+			var (
+				blueprint blueprints.Blueprint
+				err       error
+			)
+			if file != "" {
+				blueprint, err = readBlueprintFile(file)
+			} else {
+				blueprint, err = editBlueprint(ctx, manager, args[0])
+			}
+			if err != nil {
+				return err
+			}
+			if blueprint.Name != args[0] {
+				return fmt.Errorf("blueprint is named %q, not %q; use gogo blueprint create to add it", blueprint.Name, args[0])
+			}
+
+			if err := repo.Update(ctx, blueprint); err != nil {
+				return err
+			}
+			color.Green("✓ Updated blueprint %s", blueprint.Name)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&file, "file", "", "Replace the blueprint with this YAML or JSON file instead of opening $EDITOR")
+	return cmd
+}
+
+func newBlueprintDeleteCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "delete <name>",
+		Short: "Move a custom blueprint to the trash",
+		Long: color.GreenString(`Delete a custom blueprint.
+
+Deleted blueprints are kept in the trash and can be brought back with
+'gogo template trash restore --blueprint' until they are purged. Deleting a
+custom blueprint that overrides a predefined one makes the predefined
+blueprint available again.`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			manager := db.NewManager()
+			if err := manager.Open(ctx, dbPath); err != nil {
+				return fmt.Errorf("failed to open database: %w", err)
+			}
+			defer func() {
+				if closeErr := manager.Close(); closeErr != nil {
+					color.Red("Warning: failed to close database: %v", closeErr)
+				}
+			}()
+
+			repo := blueprints.NewStoredRepository(manager)
+			if err := repo.Delete(ctx, args[0]); err != nil {
+				return err
+			}
+
+			color.Green("✓ Moved blueprint %s to the trash", args[0])
+			color.Cyan("  Undo with: gogo template trash restore %s%s", args[0], entryKindFlag(true))
+			if repo.IsPredefined(args[0]) {
+				color.Yellow("⚠ The predefined %s blueprint is used again", args[0])
+			}
+			return nil
+		},
+	}
+}
+
+// readBlueprintFile parses and validates a blueprint definition file
+func readBlueprintFile(path string) (blueprints.Blueprint, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return blueprints.Blueprint{}, fmt.Errorf("failed to read blueprint: %w", err)
+	}
+	blueprint, err := parseBlueprint(data)
+	if err != nil {
+		return blueprints.Blueprint{}, fmt.Errorf("invalid blueprint %s: %w", path, err)
+	}
+	return blueprint, nil
+}
+
+// parseBlueprint parses a blueprint definition and validates it against the
+// blueprint schema, listing each problem on its own line
+func parseBlueprint(data []byte) (blueprints.Blueprint, error) {
+	blueprint, err := blueprints.Parse(data)
+	if err != nil {
+		return blueprints.Blueprint{}, err
+	}
+	var invalid blueprints.ValidationErrors
+	if err := blueprints.NewValidator().Validate(blueprint); errors.As(err, &invalid) {
+		lines := make([]string, len(invalid))
+		for i, fieldErr := range invalid {
+			lines[i] = "\n  " + fieldErr.Error()
+		}
+		return blueprints.Blueprint{}, fmt.Errorf("%d problems:%s", len(invalid), strings.Join(lines, ""))
+	}
+	return blueprint, nil
+}
+
+// blueprintDefinition is a blueprint as written to and read from definition
+// files. The ID of predefined blueprints is left out so a printed definition
+// can be stored as a custom blueprint.
+type blueprintDefinition struct {
+	Name        string                     `json:"name" yaml:"name"`
+	Stack       string                     `json:"stack" yaml:"stack"`
+	Description string                     `json:"description,omitempty" yaml:"description,omitempty"`
+	NextSteps   string                     `json:"next_steps,omitempty" yaml:"next_steps,omitempty"`
+	Config      blueprints.BlueprintConfig `json:"config" yaml:"config"`
+}
+
+// definitionOf returns the definition file contents of a blueprint
+func definitionOf(blueprint blueprints.Blueprint) blueprintDefinition {
+	return blueprintDefinition{
+		Name:        blueprint.Name,
+		Stack:       blueprint.Stack,
+		Description: blueprint.Description,
+		NextSteps:   blueprint.NextSteps,
+		Config:      blueprint.Config,
+	}
+}
+
+// editBlueprint opens a custom blueprint as YAML in $EDITOR and returns the
+// edited definition
+func editBlueprint(ctx context.Context, manager *db.Manager, name string) (blueprints.Blueprint, error) {
+	stored, err := db.NewBlueprintManager(manager).Get(ctx, name)
+	if err != nil {
+		if errors.Is(err, db.ErrBlueprintNotFound) && blueprints.NewRepository().IsPredefined(name) {
+			return blueprints.Blueprint{}, fmt.Errorf("blueprint '%s' is predefined; create a custom blueprint to override it", name)
+		}
+		return blueprints.Blueprint{}, err
+	}
+	blueprint, err := blueprints.FromStored(stored)
+	if err != nil {
+		return blueprints.Blueprint{}, err
+	}
+	original, err := yaml.Marshal(definitionOf(blueprint))
+	if err != nil {
+		return blueprints.Blueprint{}, fmt.Errorf("failed to encode blueprint '%s': %w", name, err)
+	}
+
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		editor = "vi"
+	}
+
+	tmp, err := os.CreateTemp("", "gogo-blueprint-*.yaml")
+	if err != nil {
+		return blueprints.Blueprint{}, fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(original); err != nil {
+		tmp.Close()
+		return blueprints.Blueprint{}, fmt.Errorf("failed to write temp file: %w", err)
+	}
+	tmp.Close()
+
+	cmd := exec.CommandContext(ctx, editor, tmp.Name())
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return blueprints.Blueprint{}, fmt.Errorf("editor failed: %w", err)
+	}
+
+	data, err := os.ReadFile(tmp.Name())
+	if err != nil {
+		return blueprints.Blueprint{}, fmt.Errorf("failed to read edited file: %w", err)
+	}
+	edited, err := parseBlueprint(data)
+	if err != nil {
+		return blueprints.Blueprint{}, fmt.Errorf("invalid blueprint: %w", err)
+	}
+	return edited, nil
+}
@@ -0,0 +1,33 @@
+package cli
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlueprintList_Tags(t *testing.T) {
+	home := setupHome(t)
+	require.NoError(t, runGogo(t, home, "template", "tag", "web-stack", "internal", "payment", "--blueprint"))
+	require.NoError(t, runGogo(t, home, "template", "tag", "grpc-stack", "internal", "--blueprint"))
+
+	list := func(args ...string) []string {
+		var entries []blueprintListEntry
+		output := captureStdout(t, func() {
+			require.NoError(t, runGogo(t, home, append([]string{"blueprint", "list", "--json"}, args...)...))
+		})
+		require.NoError(t, json.Unmarshal([]byte(output), &entries))
+		names := []string{}
+		for _, entry := range entries {
+			names = append(names, entry.Name)
+		}
+		return names
+	}
+
+	assert.ElementsMatch(t, []string{"web-stack", "grpc-stack"}, list("--tag", "internal"))
+	assert.Equal(t, []string{"web-stack"}, list("--tag", "internal", "--tag", "payment"), "every tag is required")
+	assert.Empty(t, list("--tag", "deprecated"))
+	assert.Greater(t, len(list()), 2)
+}
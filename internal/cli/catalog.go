@@ -0,0 +1,151 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/user/gogo/internal/catalog"
+)
+
+// useCatalog makes templates pin dependency versions from the catalog file
+// written by gogo catalog update, when there is one
+func useCatalog() error {
+	c, err := catalog.Load(catalog.DefaultPath())
+	if err != nil {
+		return fmt.Errorf("%w (run gogo catalog update, or remove it to use the built-in catalog)", err)
+	}
+	catalog.Use(c)
+	return nil
+}
+
+func newCatalogCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "catalog",
+		Short: "Manage the dependency versions generated projects require",
+		Long: color.GreenString(`Manage the catalog of module versions the templates require in generated
+go.mod files. Templates look versions up in it, so a dependency is bumped
+once in the catalog rather than in every template.
+
+gogo uses the catalog compiled into it until gogo catalog update writes a
+refreshed one to ~/.gogo/catalog.json (or $` + catalog.EnvCatalogPath + `). Modules that file
+lacks, or pins older than the built-in catalog, get the built-in versions.`),
+	}
+
+	cmd.AddCommand(newCatalogListCommand())
+	cmd.AddCommand(newCatalogUpdateCommand())
+	cmd.AddCommand(newCatalogHistoryCommand())
+
+	return cmd
+}
+
+func newCatalogListCommand() *cobra.Command {
+	return supportsJSON(&cobra.Command{
+		Use:   "list",
+		Short: "List the pinned module versions",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := catalog.Current()
+			if jsonOutput() {
+				return printJSON(c.Modules)
+			}
+			color.Cyan("Catalog version %d (%d modules)", c.Version, len(c.Modules))
+			for _, module := range c.Paths() {
+				fmt.Printf("  %-45s %s\n", module, c.Modules[module])
+			}
+			return nil
+		},
+	})
+}
+
+func newCatalogUpdateCommand() *cobra.Command {
+	var (
+		file     string
+		proxyURL string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "update [module...]",
+		Short: "Bump the catalog to the latest module versions",
+		Long: color.GreenString(`Look up the latest version of every module of the catalog, or of the
+modules given, in the Go module proxy (proxy.golang.org, or the first
+HTTP proxy of GOPROXY) and bump those that are newer. Versions are never
+lowered, and nothing is written unless every module could be looked up.
+
+Each update that bumps a module increments the catalog version and records
+the bumps in its history (see gogo catalog history). With --dry-run the
+bumps are only printed. --file writes another catalog, such as
+internal/catalog/catalog.json when bumping the catalog built into gogo.
+
+Examples:
+  gogo catalog update                              # Bump every module
+  gogo catalog update github.com/gin-gonic/gin     # Bump Gin only
+  gogo catalog update --dry-run                    # Show what would be bumped`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := catalog.Load(file)
+			if err != nil {
+				return err
+			}
+			cmd.SilenceUsage = true
+
+			proxy := catalog.NewProxy(proxyURL)
+			changes, err := c.Update(cmd.Context(), proxy.Latest, args, time.Now())
+			if err != nil {
+				return fmt.Errorf("failed to update catalog: %w", err)
+			}
+			if !dryRun && len(changes) > 0 {
+				if err := c.Save(file); err != nil {
+					return err
+				}
+			}
+			if jsonOutput() {
+				return printJSON(changes)
+			}
+
+			if len(changes) == 0 {
+				color.Green("✓ The catalog is up to date")
+				return nil
+			}
+			for _, change := range changes {
+				fmt.Printf("  %-45s %s → %s\n", change.Module, change.From, change.To)
+			}
+			if dryRun {
+				color.Yellow("Dry run: %d modules would be bumped", len(changes))
+				return nil
+			}
+			color.Green("✓ Bumped %d modules in %s (catalog version %d)", len(changes), file, c.Version)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&file, "file", catalog.DefaultPath(), "Catalog file to update")
+	cmd.Flags().StringVar(&proxyURL, "proxy", catalog.ProxyURL(), "Go module proxy to look versions up in")
+	return supportsJSON(cmd)
+}
+
+func newCatalogHistoryCommand() *cobra.Command {
+	return supportsJSON(&cobra.Command{
+		Use:   "history",
+		Short: "Show the modules each catalog update bumped",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := catalog.Current()
+			if jsonOutput() {
+				return printJSON(c.History)
+			}
+			if len(c.History) == 0 {
+				fmt.Println("The catalog has not been updated")
+				return nil
+			}
+			for i := len(c.History) - 1; i >= 0; i-- {
+				revision := c.History[i]
+				color.Cyan("Version %d, %s", revision.Version, formatTime(revision.Date))
+				for _, change := range revision.Changes {
+					fmt.Printf("  %-45s %s → %s\n", change.Module, change.From, change.To)
+				}
+			}
+			return nil
+		},
+	})
+}
@@ -0,0 +1,173 @@
+package cli
+
+import (
+	"cmp"
+	"context"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/user/gogo/internal/blueprints"
+	"github.com/user/gogo/internal/components"
+	"github.com/user/gogo/internal/db"
+	"github.com/user/gogo/internal/templates"
+)
+
+// completionShells are the shells gogo completion writes scripts for
+var completionShells = []string{"bash", "zsh", "fish", "powershell"}
+
+func newCompletionCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "completion bash|zsh|fish|powershell",
+		Short: "Generate the shell completion script",
+		Long: color.GreenString(`Write the completion script for a shell to standard output.
+
+Besides commands and flags, the scripts complete the names of templates
+(--template) and blueprints (--blueprint), including those stored in the
+database, component types (gogo add, gogo generate) and the tables of
+gogo db export --tables. The database is only read: completing never
+creates or upgrades it.
+
+Bash (requires the bash-completion package):
+  source <(gogo completion bash)                                 # Current shell
+  gogo completion bash > /etc/bash_completion.d/gogo             # Linux
+  gogo completion bash > $(brew --prefix)/etc/bash_completion.d/gogo  # macOS
+
+Zsh (compinit must be enabled):
+  gogo completion zsh > "${fpath[1]}/_gogo"
+
+Fish:
+  gogo completion fish > ~/.config/fish/completions/gogo.fish
+
+PowerShell:
+  gogo completion powershell | Out-String | Invoke-Expression    # Add to $PROFILE to keep it
+
+Start a new shell for the completions to take effect.`),
+		Args:                  cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+		ValidArgs:             completionShells,
+		DisableFlagsInUseLine: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, out := cmd.Root(), cmd.OutOrStdout()
+			switch args[0] {
+			case "bash":
+				return root.GenBashCompletionV2(out, true)
+			case "zsh":
+				return root.GenZshCompletion(out)
+			case "fish":
+				return root.GenFishCompletion(out, true)
+			default:
+				return root.GenPowerShellCompletionWithDesc(out)
+			}
+		},
+	}
+}
+
+// completionDatabase opens the database for a completion function, or
+// returns nil when there is none. Completions run on every press of tab and
+// their output is read by the shell, so they neither create the database
+// nor upgrade one of an older gogo, and report no errors.
+func completionDatabase(ctx context.Context) *db.Manager {
+	if _, err := os.Stat(dbPath); err != nil {
+		return nil
+	}
+	db.SetUpgradeConfirm(func(string, int) bool { return false })
+	manager := db.NewManager()
+	if err := manager.Open(ctx, dbPath); err != nil {
+		return nil
+	}
+	return manager
+}
+
+// completions returns the candidates of values, described, in name order
+func completions(values map[string]string) []string {
+	candidates := make([]string, 0, len(values))
+	for value, description := range values {
+		candidates = append(candidates, cobra.CompletionWithDesc(value, description))
+	}
+	slices.Sort(candidates)
+	return candidates
+}
+
+// completeTemplates completes --template with the built-in templates and
+// those added with gogo template add or install
+func completeTemplates(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	ctx := cmd.Context()
+	names := make(map[string]string)
+	predefined, _ := templates.NewRepository().ListPredefinedTemplates(ctx)
+	for _, template := range predefined {
+		names[template.Kind] = template.Name
+	}
+
+	if manager := completionDatabase(ctx); manager != nil {
+		defer manager.Close()
+		stored, _ := db.NewTemplateManager(manager).List(ctx)
+		for _, template := range stored {
+			names[template.Name] = cmp.Or(template.Description, "Stored template")
+		}
+	}
+	return completions(names), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeBlueprints completes --blueprint with the predefined blueprints
+// and those stored in the database
+func completeBlueprints(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	ctx := cmd.Context()
+	repo := blueprints.NewRepository()
+	if manager := completionDatabase(ctx); manager != nil {
+		defer manager.Close()
+		repo = blueprints.NewStoredRepository(manager)
+	}
+
+	list, _ := repo.ListBlueprints(ctx)
+	names := make(map[string]string, len(list))
+	for _, blueprint := range list {
+		names[blueprint.Name] = cmp.Or(blueprint.Description, blueprint.Stack)
+	}
+	return completions(names), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeComponentTypes completes the component type gogo generate takes
+// as its first argument or --type
+func completeComponentTypes(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if cmd.Flags().Changed("type") || len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return components.NewGenerator().GetSupportedTypes(), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeTables completes db export --tables with the tables an export
+// writes, leaving out those already given. Tables listed before the last
+// comma of the value are kept in front of each candidate.
+func completeTables(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	ctx := cmd.Context()
+	manager := completionDatabase(ctx)
+	if manager == nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	defer manager.Close()
+
+	tables, err := db.NewExportManager(manager).Tables(ctx)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	listed := toComplete[:strings.LastIndex(toComplete, ",")+1]
+	given, _ := cmd.Flags().GetStringSlice("tables")
+	given = append(given, strings.Split(listed, ",")...)
+
+	var candidates []string
+	for _, table := range tables {
+		if !slices.Contains(given, table) {
+			candidates = append(candidates, listed+table)
+		}
+	}
+	return candidates, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeFlag completes the values of the flag name of cmd with complete
+func completeFlag(cmd *cobra.Command, name string, complete cobra.CompletionFunc) {
+	if err := cmd.RegisterFlagCompletionFunc(name, complete); err != nil {
+		panic(err) // the flag is not defined, or already completed
+	}
+}
@@ -0,0 +1,378 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/user/gogo/internal/cicd"
+	"github.com/user/gogo/internal/config"
+)
+
+func newConfigCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Settings management commands",
+		Long: color.GreenString(`Manage gogo's settings, the defaults of the options of its commands.
+
+Settings (` + strings.Join(config.Keys, ", ") + `):
+  author, email, license, go_version   Defaults of new projects
+  framework                            HTTP framework of gogo init and gogo add
+  ci_provider                          CI provider of generated pipelines
+  db_path                              Database used without --db-path
+  profile                              Profile gogo init uses without --profile
+
+Profiles are named sets of defaults for gogo init, such as a team's or a
+client's: author, email, license, module prefix, template, blueprint, Go
+version, framework and CI provider. Those a profile sets take precedence
+over the settings; see gogo config profile.
+
+Flags, presets and a project's .gogo.yaml take precedence over settings.
+Settings are kept in ` + config.DefaultPath() + ` (env ` + config.EnvConfigPath + `);
+each is overridden by an environment variable such as ` + config.EnvName(config.KeyGoVersion) + `.
+Unknown keys in the file are ignored, with a warning.
+
+Examples:
+  gogo config set author "Ada Lovelace"
+  gogo config set framework chi
+  gogo config set framework ""     # Unset
+  gogo config get go_version
+  gogo config list
+  gogo config show --effective     # Values used, after profile and defaults
+  gogo config doctor               # Report unknown keys and invalid values
+  gogo config profile set work module_prefix github.com/acme
+  gogo config set profile work     # Use the work profile by default`),
+		// Settings are not loaded, so a broken settings file can be fixed
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return nil
+		},
+	}
+
+	cmd.AddCommand(newConfigGetCommand())
+	cmd.AddCommand(newConfigSetCommand())
+	cmd.AddCommand(newConfigListCommand())
+	cmd.AddCommand(newConfigDoctorCommand())
+	cmd.AddCommand(newConfigProfileCommand())
+
+	return cmd
+}
+
+func newConfigGetCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:       "get <key>",
+		Short:     "Print the value of a setting",
+		Args:      cobra.ExactArgs(1),
+		ValidArgs: config.Keys,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := config.Validate(args[0], ""); err != nil {
+				return err
+			}
+			cmd.SilenceUsage = true
+
+			current, err := config.Load(config.DefaultPath())
+			if err != nil {
+				return err
+			}
+			fmt.Println(current.Get(args[0]))
+			return nil
+		},
+	}
+}
+
+func newConfigSetCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:       "set <key> <value>",
+		Short:     "Set a setting; an empty value unsets it",
+		Args:      cobra.ExactArgs(2),
+		ValidArgs: config.Keys,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			path := config.DefaultPath()
+			if err := config.Set(path, args[0], args[1]); err != nil {
+				return err
+			}
+			if args[1] == "" {
+				color.Green("✓ Unset %s", args[0])
+			} else {
+				color.Green("✓ Set %s to %s", args[0], args[1])
+			}
+			if value, ok := os.LookupEnv(config.EnvName(args[0])); ok && value != "" {
+				color.Yellow("Warning: %s=%s overrides the setting", config.EnvName(args[0]), value)
+			}
+			return nil
+		},
+	}
+}
+
+func newConfigListCommand() *cobra.Command {
+	var effective bool
+
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"show"},
+		Short:   "List the settings and their values",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			path := config.DefaultPath()
+			current, err := config.Load(path)
+			if err != nil {
+				return err
+			}
+			if effective {
+				return printEffectiveSettings(current)
+			}
+
+			color.Cyan("Settings of %s", path)
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "KEY\tVALUE\tSOURCE")
+			for _, key := range config.Keys {
+				value, source := current.Get(key), "file"
+				switch {
+				case os.Getenv(config.EnvName(key)) != "":
+					source = "env " + config.EnvName(key)
+				case value == "":
+					value, source = "-", "unset"
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\n", key, value, source)
+			}
+			return w.Flush()
+		},
+	}
+
+	cmd.Flags().BoolVar(&effective, "effective", false, "List the values gogo uses, after the profile and gogo's own defaults")
+
+	return cmd
+}
+
+// builtinSettings are the values gogo uses for the settings left unset
+var builtinSettings = map[string]string{
+	config.KeyLicense:    "MIT",
+	config.KeyDBPath:     getDefaultDBPath(),
+	config.KeyCIProvider: cicd.ProviderGitHub,
+}
+
+// printEffectiveSettings lists the values gogo init and the other commands
+// use without flags, and where each comes from
+func printEffectiveSettings(current config.Config) error {
+	defaults, err := current.Defaults("")
+	if err != nil {
+		return err
+	}
+	var profile config.Profile
+	if current.Profile != "" {
+		profile = current.Profiles[current.Profile]
+		color.Cyan("Effective settings with profile %s", current.Profile)
+	} else {
+		color.Cyan("Effective settings")
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "KEY\tVALUE\tSOURCE")
+	for _, key := range config.ProfileKeys {
+		value, source := defaults.Get(key), "file"
+		switch {
+		case profile.Get(key) != "":
+			source = "profile " + current.Profile
+		case slices.Contains(config.Keys, key) && os.Getenv(config.EnvName(key)) != "":
+			source = "env " + config.EnvName(key)
+		case value == "" && builtinSettings[key] != "":
+			value, source = builtinSettings[key], "default"
+		case value == "":
+			value, source = "-", "unset"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", key, value, source)
+	}
+
+	value, source := current.DBPath, "file"
+	switch {
+	case os.Getenv(config.EnvName(config.KeyDBPath)) != "":
+		source = "env " + config.EnvName(config.KeyDBPath)
+	case value == "":
+		value, source = builtinSettings[config.KeyDBPath], "default"
+	}
+	fmt.Fprintf(w, "%s\t%s\t%s\n", config.KeyDBPath, value, source)
+	return w.Flush()
+}
+
+func newConfigDoctorCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "doctor",
+		Short: "Check the settings file for unknown keys and invalid values",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			path := config.DefaultPath()
+			problems, err := config.Check(path)
+			if err != nil {
+				return err
+			}
+			if len(problems) == 0 {
+				color.Green("✓ No problems in %s", path)
+				return nil
+			}
+			for _, problem := range problems {
+				color.Yellow("%s:%s", path, problem)
+			}
+			return fmt.Errorf("%d problem(s) in %s", len(problems), path)
+		},
+	}
+}
+
+// warnSettingsProblems warns about the keys of the settings file at path
+// that are ignored, such as misspelled settings
+func warnSettingsProblems(path string) {
+	problems, err := config.Check(path)
+	if err != nil {
+		return
+	}
+	for _, problem := range problems {
+		color.Yellow("Warning: %s:%s", path, problem)
+	}
+	if len(problems) > 0 {
+		color.Yellow("Run gogo config doctor to check the settings file")
+	}
+}
+
+func newConfigProfileCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "profile",
+		Short: "Profile management commands",
+		Long: color.GreenString(`Manage profiles, named sets of defaults for gogo init.
+
+Fields (` + strings.Join(config.ProfileKeys, ", ") + `):
+  module_prefix   New projects are named <prefix>/<project> without --module
+  template        Template used without --template
+  blueprint       Blueprint used without --blueprint
+  others          As the settings of the same name
+
+gogo init --profile <name> uses a profile; the profile setting names the one
+used without --profile. Flags and presets take precedence over a profile,
+and a profile over the settings.
+
+Examples:
+  gogo config profile set work author "Platform Team"
+  gogo config profile set work module_prefix github.com/acme
+  gogo config profile set work blueprint web-stack
+  gogo config profile set work ci_provider gitlab
+  gogo config profile show work
+  gogo config profile list
+  gogo init billing --profile work --no-wizard
+  gogo config profile delete work`),
+	}
+
+	cmd.AddCommand(newConfigProfileListCommand())
+	cmd.AddCommand(newConfigProfileShowCommand())
+	cmd.AddCommand(newConfigProfileSetCommand())
+	cmd.AddCommand(newConfigProfileDeleteCommand())
+
+	return cmd
+}
+
+func newConfigProfileListCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List the profiles",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			current, err := config.Load(config.DefaultPath())
+			if err != nil {
+				return err
+			}
+			names := current.ProfileNames()
+			if len(names) == 0 {
+				color.Yellow("No profiles; create one with gogo config profile set <name> <key> <value>")
+				return nil
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "NAME\tDEFAULT\tMODULE PREFIX\tTEMPLATE\tBLUEPRINT")
+			for _, name := range names {
+				profile := current.Profiles[name]
+				isDefault := ""
+				if name == current.Profile {
+					isDefault = "yes"
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", name, isDefault, profile.ModulePrefix, profile.Template, profile.Blueprint)
+			}
+			return w.Flush()
+		},
+	}
+}
+
+func newConfigProfileShowCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "show <name>",
+		Short: "Show the fields of a profile",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			current, err := config.Load(config.DefaultPath())
+			if err != nil {
+				return err
+			}
+			if _, err := current.Defaults(args[0]); err != nil {
+				return err
+			}
+
+			profile := current.Profiles[args[0]]
+			color.Cyan("Profile %s", args[0])
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "KEY\tVALUE")
+			for _, key := range config.ProfileKeys {
+				value := profile.Get(key)
+				if value == "" {
+					value = "-"
+				}
+				fmt.Fprintf(w, "%s\t%s\n", key, value)
+			}
+			return w.Flush()
+		},
+	}
+}
+
+func newConfigProfileSetCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "set <name> <key> <value>",
+		Short: "Set a field of a profile, creating it; an empty value unsets it",
+		Args:  cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			name, key, value := args[0], args[1], args[2]
+			if err := config.SetProfile(config.DefaultPath(), name, key, value); err != nil {
+				return err
+			}
+			if value == "" {
+				color.Green("✓ Unset %s of profile %s", key, name)
+			} else {
+				color.Green("✓ Set %s of profile %s to %s", key, name, value)
+			}
+			return nil
+		},
+	}
+}
+
+func newConfigProfileDeleteCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "delete <name>",
+		Short: "Delete a profile",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			if err := config.DeleteProfile(config.DefaultPath(), args[0]); err != nil {
+				return err
+			}
+			color.Green("✓ Deleted profile %s", args[0])
+			return nil
+		},
+	}
+}
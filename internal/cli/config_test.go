@@ -0,0 +1,19 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigShowEffective(t *testing.T) {
+	home := setupHome(t)
+	t.Setenv("GOGO_LICENSE", "Apache-2.0")
+
+	out := captureStdout(t, func() {
+		require.NoError(t, runGogo(t, home, "config", "show", "--effective"))
+	})
+	assert.Regexp(t, `license\s+Apache-2.0\s+env GOGO_LICENSE`, out)
+	assert.Regexp(t, `ci_provider\s+github\s+default`, out)
+}
@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/user/gogo/internal/project"
+)
+
+func newDriftCommand() *cobra.Command {
+	var (
+		failOnChange bool
+		ignore       []string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "drift [path]",
+		Short: "Report template-managed files edited since generation",
+		Long: color.GreenString(`List the managed files of a generated project that no longer match what
+gogo generated, so hand edits to template-managed files are noticed.
+
+Files teams are expected to edit, such as Go sources and go.mod, are skipped
+through the drift_ignore patterns in .gogo.yaml; --ignore adds patterns for
+one run. A pattern without a slash matches file names in any directory, a
+pattern ending in a slash a whole directory. With --fail-on-change the
+command exits non-zero on drift, which the generated CI workflow and the
+make scaffold-check target use to flag hand edits in pull requests.
+
+Examples:
+  gogo drift
+  gogo drift ./myproject --ignore 'config/'
+  gogo drift --fail-on-change`),
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Run in CI; a usage dump on drift only adds noise
+			cmd.SilenceUsage = true
+
+			dir := "."
+			if len(args) > 0 {
+				dir = args[0]
+			}
+
+			manifest, err := project.LoadManifest(dir)
+			if err != nil {
+				return err
+			}
+			drift, err := project.DetectDrift(dir, manifest)
+			if err != nil {
+				return fmt.Errorf("failed to detect drift: %w", err)
+			}
+			drift, ignored := project.IgnoreDrift(drift, append(manifest.DriftIgnore, ignore...))
+
+			if verbose {
+				for _, file := range ignored {
+					fmt.Printf("  ignored   %s (%s)\n", file.Path, file.State)
+				}
+			}
+			if len(drift) == 0 {
+				color.Green("✓ No template-managed files have drifted")
+				return nil
+			}
+
+			color.Yellow("⚠ %d template-managed files have drifted:", len(drift))
+			for _, file := range drift {
+				fmt.Printf("  %-9s %s\n", file.State, file.Path)
+			}
+			fmt.Println("Restore them, regenerate the scaffold, or add them to drift_ignore in " + project.ManifestFile)
+			if failOnChange {
+				return fmt.Errorf("%d template-managed files drifted from the generated scaffold", len(drift))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&failOnChange, "fail-on-change", false, "Exit non-zero when template-managed files drifted")
+	cmd.Flags().StringSliceVar(&ignore, "ignore", nil, "Additional file patterns to skip (repeatable)")
+	return cmd
+}
@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"context"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/manifoldco/promptui"
+	"github.com/user/gogo/internal/db"
+	"github.com/user/gogo/internal/events"
+	"github.com/user/gogo/internal/hooks"
+	"github.com/user/gogo/internal/policy"
+	"github.com/user/gogo/internal/prompt"
+)
+
+// newEventBus returns the event bus of a command. Events are recorded in the
+// audit log and run the hooks stored for them in the database, through
+// manager, which must stay open while events are published; a nil manager
+// leaves both out. With --verbose, events are also printed as they happen.
+func newEventBus(manager *db.Manager, hookPolicy policy.HookPolicy) *events.Bus {
+	bus := events.NewBus()
+	bus.OnError(func(event events.Event, err error) {
+		color.Yellow("Warning: %s %s: %v", event.Kind, event.Subject, err)
+	})
+	if verbose {
+		bus.Subscribe(func(ctx context.Context, event events.Event) error {
+			color.Cyan("• %s %s", event.Kind, event.Subject)
+			return nil
+		})
+	}
+	if manager == nil {
+		return bus
+	}
+
+	bus.Subscribe(db.NewEventRecorder(manager).Record)
+	hooks.Subscribe(bus, func(ctx context.Context, event string) ([]hooks.Hook, error) {
+		stored, err := manager.EventHooks(ctx, event)
+		if err != nil {
+			return nil, err
+		}
+		attached := make([]hooks.Hook, len(stored))
+		for i, hook := range stored {
+			source := hooks.SourceLocal
+			if hook.Source != db.HookSourceLocal {
+				source = hooks.SourceThirdParty
+			}
+			attached[i] = hooks.Hook{
+				Name:     hook.Name,
+				Event:    hook.Event,
+				Language: hook.Language,
+				Script:   hook.Script,
+				Source:   source,
+			}
+		}
+		return attached, nil
+	}, hookPolicy, confirmHook)
+	return bus
+}
+
+// confirmHook asks before a third-party hook, one brought in by gogo db
+// import or restore, runs; --yes runs it without asking
+func confirmHook(hook hooks.Hook) (bool, error) {
+	if assumeYes {
+		return true, nil
+	}
+	return askHook(hook)
+}
+
+// askHook shows the script of hook and asks whether to run it. Without a
+// terminal, or with JSON output, it declines.
+var askHook = func(hook hooks.Hook) (bool, error) {
+	if jsonOutput() || !prompt.IsTerminal() {
+		return false, nil
+	}
+	color.Yellow("Hook %s of %s was imported from another database and runs:", hook.Name, hook.Event)
+	for _, line := range strings.Split(strings.TrimSpace(hook.Script), "\n") {
+		color.Yellow("  %s", line)
+	}
+	confirm := promptui.Prompt{
+		Label:     "Run it",
+		IsConfirm: true,
+	}
+	_, err := confirm.Run()
+	return err == nil, nil
+}
+
+// useEventBus opens the database for the event bus of a command that does not
+// otherwise need it. Like stored templates the database is optional: when it
+// cannot be opened, events are neither recorded nor run hooks. The returned
+// function closes the database.
+func useEventBus(ctx context.Context, hookPolicy policy.HookPolicy) (*events.Bus, func()) {
+	manager := db.NewManager()
+	if err := manager.Open(ctx, dbPath); err != nil {
+		if verbose {
+			color.Yellow("Audit log and hooks unavailable: %v", err)
+		}
+		return newEventBus(nil, hookPolicy), func() {}
+	}
+
+	return newEventBus(manager, hookPolicy), func() {
+		if closeErr := manager.Close(); closeErr != nil {
+			color.Red("Warning: failed to close database: %v", closeErr)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package cli
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/user/gogo/internal/db"
+	"github.com/user/gogo/internal/hooks"
+)
+
+func TestImportedHooksAskBeforeRunning(t *testing.T) {
+	t.Setenv("GIT_AUTHOR_NAME", "Test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+
+	// Someone else's database, with a hook on every new project
+	other := setupHome(t)
+	otherDB := db.NewManager()
+	require.NoError(t, otherDB.Open(context.Background(), filepath.Join(other, ".gogo.db")))
+	_, err := otherDB.GetDB().Exec(`INSERT INTO hooks (name, event, script) VALUES ('mark', 'project.generated', 'touch hooked')`)
+	require.NoError(t, err)
+	require.NoError(t, otherDB.Close())
+	dump := filepath.Join(other, "dump.json")
+	require.NoError(t, runGogo(t, other, "db", "export", "--output", dump))
+
+	home := setupHome(t)
+	require.NoError(t, runGogo(t, home, "db", "import", "--from", dump, "--replace"))
+
+	original := askHook
+	t.Cleanup(func() { askHook = original })
+	var asked []string
+
+	for _, confirmed := range []bool{false, true} {
+		askHook = func(hook hooks.Hook) (bool, error) {
+			asked = append(asked, hook.Name)
+			return confirmed, nil
+		}
+		dir := filepath.Join(t.TempDir(), "svc")
+		require.NoError(t, runGogo(t, home, "init", "svc", "--no-wizard", "--template=api",
+			"--module=github.com/acme/svc", "--output-dir", dir))
+
+		if confirmed {
+			assert.FileExists(t, filepath.Join(dir, "hooked"))
+		} else {
+			assert.NoFileExists(t, filepath.Join(dir, "hooked"), "a declined hook does not run")
+		}
+	}
+	assert.Equal(t, []string{"mark", "mark"}, asked)
+}
@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"text/tabwriter"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/user/gogo/internal/examples"
+)
+
+func newExampleCommand() *cobra.Command {
+	var (
+		open   bool
+		output string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "example [name]",
+		Short: "Render an example project to see what a template generates",
+		Long: color.GreenString(`Render a pre-configured example project for a template or blueprint into a
+temporary directory, so you can inspect what you would get before answering
+the init wizard. Without a name, lists the available examples.
+
+Examples:
+  gogo example                     # List examples
+  gogo example api                 # Render the api example into a temp dir
+  gogo example web-stack --open    # Render and open it in the file browser
+  gogo example cli --output ./cli-example`),
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return listExamples()
+			}
+			name := args[0]
+
+			dir := output
+			if dir == "" {
+				tmp, err := os.MkdirTemp("", "gogo-example-"+name+"-*")
+				if err != nil {
+					return fmt.Errorf("failed to create temp directory: %w", err)
+				}
+				dir = tmp
+			}
+
+			result, err := examples.Materialize(cmd.Context(), name, dir)
+			if err != nil {
+				if output == "" {
+					os.RemoveAll(dir)
+				}
+				return err
+			}
+
+			color.Green("✓ Rendered example %s into %s", name, result.Dir)
+			color.Cyan("Equivalent to: gogo init %s", initArgs(result.Example))
+			fmt.Println()
+			for _, file := range result.Files {
+				fmt.Printf("  %s\n", file)
+			}
+
+			if open {
+				if err := openPath(result.Dir); err != nil {
+					return fmt.Errorf("failed to open %s: %w", result.Dir, err)
+				}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&open, "open", false, "Open the rendered project in the system file browser")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Directory to render into (default: a new temp directory)")
+
+	return cmd
+}
+
+func listExamples() error {
+	list, err := examples.List()
+	if err != nil {
+		return fmt.Errorf("failed to list examples: %w", err)
+	}
+
+	color.Cyan("Available examples:")
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, example := range list {
+		fmt.Fprintf(w, "  %s\t%s\n", example.Name, example.Description)
+	}
+	return w.Flush()
+}
+
+// initArgs returns the gogo init arguments that reproduce an example
+func initArgs(example examples.Example) string {
+	args := fmt.Sprintf("%s --module=%s --template=%s", example.ProjectName(), example.ModuleName(), example.Template)
+	if example.Blueprint != "" {
+		args += " --blueprint=" + example.Blueprint
+	}
+	return args + " --no-wizard"
+}
+
+// openPath opens path with the platform's default handler
+func openPath(path string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", path)
+	case "windows":
+		cmd = exec.Command("explorer", path)
+	default:
+		cmd = exec.Command("xdg-open", path)
+	}
+	return cmd.Start()
+}
@@ -1,47 +1,183 @@
 package cli
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 	"github.com/user/gogo/internal/components"
+	"github.com/user/gogo/internal/generator"
+	"github.com/user/gogo/internal/policy"
+	"github.com/user/gogo/internal/project"
+	"github.com/user/gogo/internal/workspace"
 )
 
 func newGenerateCommand() *cobra.Command {
 	var (
 		componentType string
 		name          string
+		service       string
+		variant       string
+		framework     string
+		collection    string
+		writeReport   bool
+		showDiff      bool
+		profile       profileFlags
+		gitBranch     gitBranchFlag
 	)
 
 	cmd := &cobra.Command{
-		Use:   "generate",
+		Use:   "generate [type] [name]",
 		Short: "Generate project components",
 		Long: color.GreenString(`Generate components for an existing Go project.
 
+Inside a go.work workspace, components are written into the module that
+contains the current directory, or into the module selected with --service.
+
+With --report, a JSON record of the files written (with their SHA-256),
+the template variables and how long generation took is written to
+.gogo/report.json in the module, for CI pipelines to archive.
+
+With --diff, nothing is written: the component is generated into a scratch
+copy of the module, and the files that would be created are listed and the
+existing files that would change are shown as unified diffs, so running a
+generator in a mature codebase can be reviewed first.
+
 Examples:
   gogo generate --type=handler --name=Health
   gogo generate --type=model --name=User
-  gogo generate --type=test --name=service`),
+  gogo generate repository user
+  gogo generate proto payment                    # gRPC service; then make proto
+  gogo generate resolver product                 # GraphQL type; then go generate ./...
+  gogo generate job send_email                   # Worker job handler
+  gogo generate --type=test --name=service
+  gogo generate handler user --service payments
+  gogo generate shared common
+  gogo generate middleware --variant idempotency --framework chi
+  gogo generate handler user --cpuprofile cpu.out --trace trace.out
+  gogo generate handler user --git-branch          # Commit on gogo/update-<date> for review
+  gogo generate handler user --report              # Also write .gogo/report.json
+  gogo generate proto payment --diff               # Review the changes to the Makefile and server.go first`),
+		Args:              cobra.MaximumNArgs(2),
+		ValidArgsFunction: completeComponentTypes,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			stopProfiling, err := profile.start()
+			if err != nil {
+				return err
+			}
+			defer stopProfiling()
+
+			if len(args) > 0 {
+				componentType = args[0]
+			}
+			if len(args) > 1 {
+				name = args[1]
+			}
+			if componentType == "" || (name == "" && variant == "") {
+				return fmt.Errorf("component type and name are required")
+			}
+
 			// Set up component generator
 			generator := components.NewGenerator()
+			orgPolicy, err := policy.Load(policy.DefaultPath())
+			if err != nil {
+				return err
+			}
+			bus, closeEvents := useEventBus(cmd.Context(), orgPolicy.Hooks)
+			defer closeEvents()
+			generator.SetEvents(bus)
+
+			if framework == "" {
+				framework = settings.Framework
+			}
 
 			// Build options
 			opts := components.GenerateOptions{
-				Type:      componentType,
-				Name:      name,
-				OutputDir: ".",
-				DryRun:    false, // Will be handled by global flag
+				Type:       componentType,
+				Name:       name,
+				Variant:    variant,
+				Framework:  framework,
+				Collection: collection,
+				OutputDir:  ".",
+				DryRun:     false, // Will be handled by global flag
+			}
+
+			// Resolve the target module when running inside a workspace
+			ws, err := workspace.Find(".")
+			if err != nil {
+				return fmt.Errorf("failed to load workspace: %w", err)
+			}
+			if componentType == "shared" {
+				if writeReport {
+					return fmt.Errorf("--report is not supported for shared libraries")
+				}
+				if showDiff {
+					return fmt.Errorf("--diff is not supported for shared libraries")
+				}
+				session, err := gitBranch.start(cmd.Context(), ".")
+				if err != nil {
+					return err
+				}
+				err = generateSharedLibrary(cmd, generator, opts, ws, service)
+				return finishGitBranch(cmd.Context(), session, err, generationCommitMessage("generate shared "+opts.Name, ".", nil, nil))
+			}
+			switch {
+			case ws != nil && service != "":
+				module, err := ws.Service(service)
+				if err != nil {
+					return err
+				}
+				applyWorkspaceModule(&opts, ws, module)
+			case ws != nil:
+				if module, ok := ws.ModuleFor("."); ok {
+					applyWorkspaceModule(&opts, ws, module)
+				}
+			case service != "":
+				return fmt.Errorf("--service requires a %s workspace", workspace.WorkFile)
+			}
+			if opts.ModuleName == "" {
+				if modPath, err := workspace.ModulePath(opts.OutputDir); err == nil {
+					opts.ModuleName = modPath
+				}
+			}
+
+			if showDiff {
+				return previewComponent(cmd.Context(), generator, opts)
+			}
+
+			session, err := gitBranch.start(cmd.Context(), ".")
+			if err != nil {
+				return err
 			}
 
 			color.Yellow("Generating component: %s", componentType)
-			color.Yellow("Name: %s", name)
+			if variant != "" {
+				color.Yellow("Variant: %s", variant)
+			}
+			if name != "" {
+				color.Yellow("Name: %s", name)
+			}
+			if opts.ModuleName != "" {
+				color.Yellow("Module: %s (%s)", opts.ModuleName, opts.OutputDir)
+			}
 
+			var report *project.Report
+			if writeReport {
+				report = project.NewReport("generate")
+				report.GogoVersion = gogoVersion
+				report.Component = strings.TrimSuffix(componentType+"/"+variant, "/")
+			}
+			renderStart := time.Now()
 			result, err := generator.Generate(cmd.Context(), opts)
 			if err != nil {
-				return fmt.Errorf("failed to generate component: %w", err)
+				return finishGitBranch(cmd.Context(), session, fmt.Errorf("failed to generate component: %w", err), "")
 			}
+			report.Phase("render", renderStart)
 
 			if result.Success {
 				color.Green(result.Message)
@@ -55,14 +191,171 @@ Examples:
 				color.Red("Component generation failed")
 			}
 
+			// Hashed before --git-branch moves the files onto its branch
+			if err := report.TrackFiles(opts.OutputDir, result.Files); err != nil {
+				return finishGitBranch(cmd.Context(), session, err, "")
+			}
+
+			subject := strings.TrimSpace("generate " + componentType + " " + name)
+			gitStart := time.Now()
+			if err := finishGitBranch(cmd.Context(), session, nil, generationCommitMessage(subject, opts.OutputDir, componentDetails(opts), result.Files)); err != nil {
+				return err
+			}
+			if report == nil {
+				return nil
+			}
+
+			// Written after the commit so the report stays out of it
+			if session != nil {
+				report.Hook("git commit", gitStart)
+				report.Phase("git", gitStart)
+			}
+			report.Variables = result.Variables
+			path, err := report.Save(opts.OutputDir)
+			if err != nil {
+				return err
+			}
+			color.Cyan("Report written to %s", path)
 			return nil
 		},
 	}
 
-	cmd.Flags().StringVar(&componentType, "type", "", "Component type (handler, model, service, migration, middleware, test)")
+	cmd.Flags().StringVar(&componentType, "type", "", "Component type (handler, model, service, repository, migration, middleware, test, shared, notifier, storage, proto, resolver, job)")
 	cmd.Flags().StringVar(&name, "name", "", "Component name")
-	_ = cmd.MarkFlagRequired("type")
-	_ = cmd.MarkFlagRequired("name")
+	cmd.Flags().StringVar(&variant, "variant", "", "Named component variant (e.g. idempotency for middleware)")
+	cmd.Flags().StringVar(&framework, "framework", "", "HTTP framework for generated code (gin, echo, chi; default from gogo config, else gin)")
+	cmd.Flags().StringVar(&collection, "collection", "bruno", "Request collection to refresh for handlers (bruno, postman, none)")
+	cmd.Flags().BoolVar(&showDiff, "diff", false, "Show the files that would be created and diffs of existing files that would change, without writing anything")
+	cmd.Flags().BoolVar(&writeReport, "report", false, "Write a JSON report of the generated files, variables and timings to .gogo/report.json")
+	cmd.Flags().StringVar(&service, "service", "", "Workspace service (module directory or name) to generate into, or to wire a shared library into")
+
+	completeFlag(cmd, "type", cobra.FixedCompletions(components.NewGenerator().GetSupportedTypes(), cobra.ShellCompDirectiveNoFileComp))
+	profile.register(cmd)
+	gitBranch.register(cmd)
 
 	return cmd
 }
+
+// previewComponent prints what generating a component would do without
+// writing anything: the new files, and a unified diff of every existing file
+// whose content would change
+func previewComponent(ctx context.Context, gen *components.Generator, opts components.GenerateOptions) error {
+	dir, result, err := gen.Stage(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("failed to generate component: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	preview, err := generator.CompareFiles(opts.OutputDir, dir, result.Files)
+	if err != nil {
+		return err
+	}
+
+	if count := preview.Count(generator.ChangeNew); count > 0 {
+		color.Cyan("New files:")
+		for _, file := range preview.Files {
+			if file.Kind == generator.ChangeNew {
+				color.Green("  + %s", file.Path)
+			}
+		}
+	}
+	for _, file := range preview.Files {
+		if file.Diff != "" {
+			fmt.Println()
+			printDiff(file.Diff)
+		}
+	}
+	fmt.Println()
+	color.Yellow("%d new, %d changed and %d unchanged files; nothing was written",
+		preview.Count(generator.ChangeNew), preview.Count(generator.ChangeModified), preview.Count(generator.ChangeUnchanged))
+	return nil
+}
+
+// componentDetails describes the component template used, for commit messages
+func componentDetails(opts components.GenerateOptions) []string {
+	detail := "component: " + opts.Type
+	var extras []string
+	if opts.Variant != "" {
+		extras = append(extras, "variant "+opts.Variant)
+	}
+	if opts.Framework != "" {
+		extras = append(extras, "framework "+opts.Framework)
+	}
+	if len(extras) > 0 {
+		detail += " (" + strings.Join(extras, ", ") + ")"
+	}
+	return []string{detail}
+}
+
+// applyWorkspaceModule targets component generation at a workspace module
+func applyWorkspaceModule(opts *components.GenerateOptions, ws *workspace.Workspace, module workspace.Module) {
+	dir := ws.Abs(module)
+	if cwd, err := filepath.Abs("."); err == nil {
+		if rel, err := filepath.Rel(cwd, dir); err == nil {
+			dir = rel
+		}
+	}
+	opts.OutputDir = dir
+	opts.ModuleName = module.Path
+	opts.ProjectName = module.Name()
+}
+
+// generateSharedLibrary scaffolds a pkg/ library module at the workspace root,
+// adds it to go.work and wires require/replace directives into the services
+func generateSharedLibrary(cmd *cobra.Command, generator *components.Generator, opts components.GenerateOptions, ws *workspace.Workspace, service string) error {
+	if ws == nil {
+		return fmt.Errorf("shared libraries require a %s workspace (run from inside a monorepo)", workspace.WorkFile)
+	}
+
+	targets := ws.Modules
+	if service != "" {
+		module, err := ws.Service(service)
+		if err != nil {
+			return err
+		}
+		targets = []workspace.Module{module}
+	}
+
+	// Render once in dry-run mode to learn the library directory
+	preview := opts
+	preview.DryRun = true
+	planned, err := generator.Generate(cmd.Context(), preview)
+	if err != nil {
+		return fmt.Errorf("failed to generate shared library: %w", err)
+	}
+	libDir := filepath.ToSlash(filepath.Dir(planned.Files[0]))
+
+	opts.OutputDir = ws.Root
+	opts.ProjectName = opts.Name
+	opts.ModuleName = ws.SuggestModulePath(libDir)
+
+	color.Yellow("Generating shared library: %s", opts.Name)
+	color.Yellow("Module: %s (%s)", opts.ModuleName, libDir)
+
+	result, err := generator.Generate(cmd.Context(), opts)
+	if err != nil {
+		return fmt.Errorf("failed to generate shared library: %w", err)
+	}
+	color.Green(result.Message)
+	color.Cyan("Generated files:")
+	for _, file := range result.Files {
+		color.Cyan("  - %s", file)
+	}
+
+	if err := ws.AddUse(libDir); err != nil {
+		return fmt.Errorf("failed to update %s: %w", workspace.WorkFile, err)
+	}
+	color.Green("Added %s to %s", libDir, workspace.WorkFile)
+
+	for _, module := range targets {
+		if module.Dir == libDir {
+			continue
+		}
+		if err := ws.AddReplace(module, opts.ModuleName, libDir); err != nil {
+			return fmt.Errorf("failed to wire %s into %s: %w", opts.ModuleName, module.Dir, err)
+		}
+		color.Green("Wired %s into %s", opts.ModuleName, module.Dir)
+	}
+
+	return nil
+}
@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/user/gogo/internal/git"
+	"github.com/user/gogo/internal/policy"
+	"github.com/user/gogo/internal/project"
+)
+
+// autoBranch is the --git-branch value used when the flag is given without
+// a name; it is replaced by gogo/update-<date>
+const autoBranch = "auto"
+
+// gitBranchFlag applies a command's changes on a new git branch, commits them
+// with a structured message and switches back, so the working branch is left
+// untouched and the changes can be reviewed as a pull request
+type gitBranchFlag struct {
+	name string
+}
+
+func (f *gitBranchFlag) register(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&f.name, "git-branch", "", "Apply changes on a new git branch and commit them there (default name gogo/update-<date>)")
+	cmd.Flags().Lookup("git-branch").NoOptDefVal = autoBranch
+}
+
+// start checks out the new branch when the flag is set; it returns nil when
+// it is not
+func (f *gitBranchFlag) start(ctx context.Context, dir string) (*git.BranchSession, error) {
+	if f.name == "" {
+		return nil, nil
+	}
+	if dryRun {
+		return nil, fmt.Errorf("--git-branch cannot be combined with --dry-run")
+	}
+
+	manager := git.NewGitManager(dir)
+	name := f.name
+	if name == autoBranch {
+		name = "gogo/update-" + time.Now().Format("2006-01-02")
+		base := name
+		for i := 2; manager.BranchExists(ctx, name); i++ {
+			name = fmt.Sprintf("%s-%d", base, i)
+		}
+	}
+
+	session, err := manager.StartBranch(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("cannot use --git-branch: %w", err)
+	}
+	color.Yellow("Applying changes on branch %s", name)
+	return session, nil
+}
+
+// finishGitBranch commits the session's changes, or abandons the branch when the
+// command failed
+func finishGitBranch(ctx context.Context, session *git.BranchSession, runErr error, message string) error {
+	if session == nil {
+		return runErr
+	}
+	if runErr == nil {
+		message, runErr = withPolicyTrailers(message)
+	}
+	if runErr != nil {
+		if err := session.Abort(ctx); err != nil {
+			color.Red("Warning: %v", err)
+		}
+		return runErr
+	}
+
+	if err := session.Commit(ctx, message); err != nil {
+		return err
+	}
+	color.Green("Committed changes to branch %s; your current branch is unchanged", session.Branch)
+	return nil
+}
+
+// withPolicyTrailers adds the org policy's commit trailers to message
+func withPolicyTrailers(message string) (string, error) {
+	orgPolicy, err := policy.Load(policy.DefaultPath())
+	if err != nil {
+		return "", err
+	}
+	return git.AppendTrailers(message, orgPolicy.Commits.AllTrailers(), git.CommitVars{GogoVersion: gogoVersion})
+}
+
+// generationCommitMessage builds the structured commit message for changes
+// applied with --git-branch, listing the template versions involved
+func generationCommitMessage(subject, projectDir string, details, files []string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "gogo: %s\n\nTemplate versions:\n", subject)
+	fmt.Fprintf(&b, "- gogo: %s\n", gogoVersion)
+	if manifest, err := project.LoadManifest(projectDir); err == nil {
+		generatedWith := manifest.GogoVersion
+		if generatedWith == "" {
+			generatedWith = "unknown"
+		}
+		fmt.Fprintf(&b, "- project template: %s (generated by gogo %s)\n", manifest.Template, generatedWith)
+		if manifest.Blueprint != "" {
+			fmt.Fprintf(&b, "- blueprint: %s\n", manifest.Blueprint)
+		}
+	}
+	for _, detail := range details {
+		fmt.Fprintf(&b, "- %s\n", detail)
+	}
+
+	if len(files) > 0 {
+		b.WriteString("\nFiles:\n")
+		for _, file := range files {
+			fmt.Fprintf(&b, "- %s\n", file)
+		}
+	}
+	return b.String()
+}
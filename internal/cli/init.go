@@ -1,51 +1,138 @@
 package cli
 
 import (
+	"context"
 	"fmt"
+	"math"
+	"strings"
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
+	"github.com/user/gogo/internal/cicd"
+	"github.com/user/gogo/internal/components"
+	"github.com/user/gogo/internal/db"
 	"github.com/user/gogo/internal/generator"
+	"github.com/user/gogo/internal/policy"
+	"github.com/user/gogo/internal/preset"
 	"github.com/user/gogo/internal/prompt"
+	"github.com/user/gogo/internal/taskrunner"
 	"github.com/user/gogo/internal/templates"
 )
 
 func newInitCommand() *cobra.Command {
+	return initCommand(false)
+}
+
+// initCommand returns gogo init, or with planning gogo plan, which takes the
+// same flags and writes a plan of what gogo init would do instead
+func initCommand(planning bool) *cobra.Command {
 	var (
-		template   string
-		blueprint  string
-		moduleName string
-		author     string
-		license    string
-		gitInit    bool
-		force      bool
-		wizard     bool
-		noWizard   bool
+		planFile    string
+		template    string
+		blueprint   string
+		moduleName  string
+		author      string
+		license     string
+		gitInit     bool
+		force       bool
+		wizard      bool
+		noWizard    bool
+		nonInteract bool
+		usePrompts  bool
+		answersFile string
+		saveAnswers string
+		tsClient    bool
+		licenses    bool
+		tags        []string
+		into        string
+		branch      string
+		message     string
+		keepPart    bool
+		verifyBld   bool
+		report      bool
+		omit        []string
+		framework   string
+		onConflict  string
+		ciProvider  string
+		updateBot   string
+		taskRunner  string
+		langLevel   string
+		coverage    float64
+		covExclude  []string
+		presetName  string
+		saveAs      string
+		profileName string
+		profile     profileFlags
 	)
 
 	cmd := &cobra.Command{
 		Use:   "init [project-name]",
 		Short: "Initialize a new Go project",
-		Long: color.GreenString(`Initialize a new Go project with the specified template and blueprint.
+		Long: color.GreenString(`Initialize a new Go project from a template and blueprint.
 
-By default, runs in interactive wizard mode for the best user experience.
-Use --no-wizard to disable interactive mode when providing all flags.
+A full-screen wizard asks for every option unless --no-wizard is given;
+--prompts, or TERM=dumb, asks one question at a time instead. Flags,
+--preset, --profile and the settings of gogo config are its defaults.
+Without a terminal, or with --non-interactive, the questions are answered
+by GOGO_WIZARD_* variables, such as GOGO_WIZARD_MODULE, or an --answers file.
+
+The coverage minimum is a percentage everywhere: --coverage-min=85, the
+wizard's question and GOGO_WIZARD_COVERAGE_MIN=85.
 
 Examples:
-  gogo init                                          # Interactive wizard (default)
-  gogo init myproject --module=github.com/user/myproject --no-wizard
-  gogo init myapi --template=api --blueprint=web-stack --no-wizard`),
+  gogo init                                          # Interactive wizard
+  gogo init myapi --module=github.com/acme/myapi --template=api --blueprint=web-stack --no-wizard
+  gogo init orders --template=api --ci-provider=gitlab --coverage-min=85 --no-wizard
+  gogo init --into . --template=api --no-wizard --dry-run   # Preview scaffolding the cloned repo`),
 		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			stopProfiling, err := profile.start()
+			if err != nil {
+				return err
+			}
+			defer stopProfiling()
+
 			projectName := ""
 			if len(args) > 0 {
 				projectName = args[0]
 			}
 
+			// A preset fills in the flags not given on the command line
+			if presetName != "" {
+				if err := applyPreset(cmd, presetName, projectName, &moduleName); err != nil {
+					return err
+				}
+			}
+			if saveAs != "" {
+				if err := saveInitPreset(cmd, saveAs, projectName, moduleName); err != nil {
+					return err
+				}
+			}
+			// The profile and settings fill in what neither the flags nor the
+			// preset gave
+			defaults, err := settings.Defaults(profileName)
+			if err != nil {
+				return err
+			}
+			applySetting(cmd, "author", &author, defaults.Author)
+			applySetting(cmd, "license", &license, defaults.License)
+			applySetting(cmd, "framework", &framework, defaults.Framework)
+			applySetting(cmd, "go-version", &goVersion, defaults.GoVersion)
+			applySetting(cmd, "template", &template, defaults.Template)
+			applySetting(cmd, "blueprint", &blueprint, defaults.Blueprint)
+			if moduleName == "" {
+				moduleName = defaults.Module(projectName)
+			}
+
 			// Set up generator
 			engine := templates.NewEngine()
 			repo := templates.NewRepository()
+			closeStore := useTemplateStore(cmd.Context(), repo)
+			defer closeStore()
 			gen := generator.NewProjectGenerator(engine, repo)
+			blueprintRepo, closeBlueprints := useBlueprintStore(cmd.Context())
+			defer closeBlueprints()
+			gen.SetBlueprintRepository(blueprintRepo)
 
 			// Build initial options
 			opts := generator.InitOptions{
@@ -61,6 +148,72 @@ Examples:
 				GitInit:     gitInit,
 				Force:       force,
 				DryRun:      dryRun,
+				Omit:        omit,
+				Email:       defaults.Email,
+				CIProvider:  defaults.CIProvider,
+
+				GenerateTSClient: tsClient,
+				KeepPartial:      keepPart,
+				VerifyBuild:      verifyBld,
+				Report:           report,
+				Framework:        framework,
+				CoverageMin:      coverage / 100,
+				CoverageExclude:  covExclude,
+				LanguageLevel:    langLevel,
+			}
+			if opts.TaskRunner, err = taskrunner.ParseRunner(taskRunner); err != nil {
+				return err
+			}
+
+			// Choosing a CI provider asks for CI/CD
+			if cmd.Flags().Changed("ci-provider") {
+				provider, err := cicd.ParseProvider(ciProvider)
+				if err != nil {
+					return err
+				}
+				opts.GenerateCI = true
+				opts.CIProvider = provider
+			}
+			// So does a dependency update bot; none keeps the wizard from asking
+			if cmd.Flags().Changed("dependency-updates") {
+				if _, err := cicd.ParseUpdateBot(updateBot); err != nil {
+					return err
+				}
+				opts.GenerateCI = true
+				opts.DependencyUpdates = updateBot
+			}
+			// A percentage, as the wizard asks; a fraction such as 0.85 is a
+			// leftover of when the flag took one
+			if cmd.Flags().Changed("coverage-min") {
+				if coverage < 0 || coverage > 100 {
+					return fmt.Errorf("--coverage-min must be a percentage between 0 and 100, not %v", coverage)
+				}
+				if coverage > 0 && coverage < 1 {
+					percentage := math.Round(coverage*1000) / 10
+					return fmt.Errorf("--coverage-min is a percentage: use %v for %v%%", percentage, percentage)
+				}
+			}
+
+			// Generating into an existing repository: take the project name,
+			// module path and license from it and commit instead of git init
+			if into != "" {
+				repo, err := generator.DetectExistingRepo(cmd.Context(), into)
+				if err != nil {
+					return fmt.Errorf("cannot initialize into %s: %w", into, err)
+				}
+				if opts.ProjectName == "" {
+					opts.ProjectName = repo.ProjectName
+					projectName = repo.ProjectName
+				}
+				if opts.ModuleName == "" {
+					opts.ModuleName = repo.ModuleName
+					moduleName = repo.ModuleName
+				}
+				if repo.License != "" && !cmd.Flags().Changed("license") {
+					opts.License = repo.License
+				}
+				opts.OutputDir = into
+				opts.GitInit = false
 			}
 
 			// Determine if we should run the wizard (default behavior)
@@ -76,19 +229,71 @@ Examples:
 				needsWizard = false
 			}
 
+			// An answers file is replayed through the wizard
+			var answers map[string]string
+			if answersFile != "" {
+				if answers, err = prompt.LoadAnswers(answersFile); err != nil {
+					return err
+				}
+				needsWizard = true
+			}
+
+			// JSON output is for scripts, which cannot answer prompts
+			if jsonOutput() {
+				if wizard || answersFile != "" {
+					return fmt.Errorf("--wizard and --answers cannot be used with --json")
+				}
+				needsWizard = false
+			}
+			if saveAnswers != "" && !needsWizard {
+				color.Yellow("Warning: --save-answers is ignored when the wizard does not run")
+			}
+
+			// Prompts would hang without a terminal, so answers come from flags,
+			// GOGO_WIZARD_* variables and defaults
+			interactive := !nonInteract && prompt.IsTerminal()
+			if wizard && !interactive {
+				if nonInteract {
+					return fmt.Errorf("--wizard cannot be used with --non-interactive")
+				}
+				return fmt.Errorf("--wizard needs a terminal (use --non-interactive with %s* variables)", prompt.EnvPrefix)
+			}
+
 			if needsWizard {
-				color.Cyan("Starting interactive wizard...")
+				if interactive {
+					color.Cyan("Starting interactive wizard...")
+				} else {
+					color.Cyan("Running the wizard non-interactively...")
+				}
 				fmt.Println()
 
 				wizard := prompt.NewWizard()
+				wizard.SetInteractive(interactive)
+				if usePrompts {
+					wizard.SetForm(false)
+				}
+				wizard.SetAnswers(answers)
+				wizard.SetBlueprintRepository(blueprintRepo)
+				wizard.SetPreviewer(gen)
+				loadWizardEntries(cmd.Context(), wizard)
+				wizard.SetRequiredTags(tags)
 				wizardOptions, err := wizard.RunInitWizard(cmd.Context(), opts)
 				if err != nil {
 					return fmt.Errorf("wizard failed: %w", err)
 				}
+				if saveAnswers != "" {
+					if err := prompt.SaveAnswers(saveAnswers, wizardOptions); err != nil {
+						return err
+					}
+					color.Green("✓ Saved the answers to %s (replay them with --answers)", saveAnswers)
+				}
 
-				// Convert wizard options to generator options
-				opts = wizardOptions.ConvertToInitOptions()
-				opts.DryRun = dryRun // Preserve the dry-run flag from CLI
+				// The wizard's answers replace the options it asks about
+				opts = wizardOptions.ApplyTo(opts)
+				if into != "" {
+					opts.OutputDir = into
+					opts.GitInit = false
+				}
 			}
 
 			// Validate that we have required options
@@ -101,7 +306,11 @@ Examples:
 
 			// Show what we're doing (unless we just showed it in wizard)
 			if !needsWizard {
-				color.Yellow("Initializing project: %s", opts.ProjectName)
+				action := "Initializing"
+				if planning {
+					action = "Planning"
+				}
+				color.Yellow("%s project: %s", action, opts.ProjectName)
 				color.Yellow("Template: %s", opts.Template)
 				if opts.Blueprint != "" {
 					color.Yellow("Blueprint: %s", opts.Blueprint)
@@ -109,33 +318,316 @@ Examples:
 				color.Yellow("Module: %s", opts.ModuleName)
 			}
 
-			result, err := gen.InitProject(cmd.Context(), opts)
+			opts.GogoVersion = gogoVersion
+			// A flag, or a preset setting it, overrides the wizard's answer
+			if cmd.Flags().Changed("coverage-min") {
+				opts.CoverageMin = coverage / 100
+			}
+			if cmd.Flags().Changed("on-conflict") {
+				policy, err := generator.ParseConflictPolicy(onConflict)
+				if err != nil {
+					return err
+				}
+				opts.OnConflict = policy
+			}
+
+			orgPolicy, err := policy.Load(policy.DefaultPath())
 			if err != nil {
-				return fmt.Errorf("failed to initialize project: %w", err)
+				return err
+			}
+			opts.CommitPolicy = orgPolicy.Commits
+			opts.LicenseReport = licenses || orgPolicy.Licenses.Required
+			opts.AllowedLicenses = orgPolicy.Licenses.Allowed
+			opts.StoreState = orgPolicy.State.Remote()
+			if into != "" {
+				opts.Into = true
+				opts.Branch = branch
+				opts.InitialCommitMessage = message
 			}
 
-			if result.Success {
-				color.Green(result.Message)
-				if opts.GitInit {
-					color.Green("Git repository initialized")
-				}
-			} else {
-				color.Red("Project initialization failed")
+			if planning {
+				return writePlan(cmd.Context(), gen, opts, planFile)
 			}
 
-			return nil
+			bus, closeEvents := useEventBus(cmd.Context(), orgPolicy.Hooks)
+			defer closeEvents()
+			gen.SetEvents(bus)
+
+			result, err := gen.InitProject(cmd.Context(), opts)
+			if err != nil {
+				return fmt.Errorf("failed to initialize project: %w", err)
+			}
+			return printInitResult(result, opts)
 		},
 	}
 
-	cmd.Flags().StringVar(&template, "template", "cli", "Project template (cli, library, api, grpc, microservice)")
-	cmd.Flags().StringVar(&blueprint, "blueprint", "", "Stack blueprint name (web-stack, cli-stack, grpc-stack, microservice-stack)")
+	if planning {
+		cmd.Use = "plan [project-name]"
+		cmd.Short = "Plan a new Go project for review before it is generated"
+		cmd.Long = color.GreenString(`Write a plan of what gogo init would do with the same flags, without
+writing any project file.
+
+The plan is a JSON file listing every file with what would happen to it
+(new, modified, merged or unchanged), its SHA-256 and a diff against the
+existing file, the commands run on the project (--verify-build), the hooks
+stored for project.generated, and the git repository, branch and commit
+message. Once reviewed, gogo apply carries it out.
+
+Examples:
+  gogo plan myapi --module=github.com/acme/myapi --template=api --blueprint=web-stack --no-wizard
+  gogo plan --into . --template=api --no-wizard --out scaffold.plan.json
+  gogo apply plan.json`)
+		cmd.Flags().StringVar(&planFile, "out", "plan.json", "Plan file to write")
+	}
+
+	cmd.Flags().StringVar(&template, "template", "cli", "Project template (cli, library, api, grpc, microservice, or one added with gogo template add or install)")
+	cmd.Flags().StringVar(&blueprint, "blueprint", "", "Stack blueprint name (web-stack, cli-stack, grpc-stack, microservice-stack, graphql-stack, worker-stack)")
 	cmd.Flags().StringVar(&moduleName, "module", "", "Go module name (e.g., github.com/user/project)")
 	cmd.Flags().StringVar(&author, "author", "", "Author name for generated files")
 	cmd.Flags().StringVar(&license, "license", "MIT", "License type (MIT, Apache, GPL)")
 	cmd.Flags().BoolVar(&gitInit, "git-init", false, "Initialize git repository")
+	cmd.Flags().StringVar(&taskRunner, "task-runner", taskrunner.RunnerMake, "Task runner of the generated build, test, lint and stack targets ("+strings.Join(taskrunner.Runners, ", ")+"); task writes a Taskfile.yml instead of a Makefile")
+	cmd.Flags().StringVar(&langLevel, "language-level", "", "Go release whose constructs generated code may use, at least "+templates.MinLanguageLevel+" (e.g. 1.21 for log/slog without range-over-int); --go-version's when unset")
+	cmd.Flags().Float64Var(&coverage, "coverage-min", 0, "Minimum test coverage CI and the cover target enforce, as a percentage (e.g. 85); 80 when unset")
+	cmd.Flags().StringSliceVar(&covExclude, "coverage-exclude", nil, "Code CI leaves out of the coverage it checks, also written to .testcoverage.yml: "+strings.Join(cicd.CoverageExclusions, ", ")+" or regular expressions of paths in the module; the blueprint's when unset")
+	cmd.Flags().StringVar(&presetName, "preset", "", "Take the options not given as flags from this preset (see gogo preset)")
+	cmd.Flags().StringVar(&profileName, "profile", "", "Take the defaults of the options not given as flags or by --preset from this profile (see gogo config profile); the profile setting's when unset")
+	cmd.Flags().StringVar(&saveAs, "save-preset", "", "Save the options given as flags, and those of --preset, as a preset of this name")
+	cmd.Flags().StringVar(&updateBot, "dependency-updates", "", "Generate CI/CD configurations with a dependency update bot ("+strings.Join(cicd.UpdateBots, ", ")+", none)")
+	cmd.Flags().StringVar(&ciProvider, "ci-provider", "", "Generate CI/CD configurations with a pipeline for this CI provider ("+strings.Join(cicd.Providers, ", ")+")")
 	cmd.Flags().BoolVar(&force, "force", false, "Overwrite existing files")
+	cmd.Flags().StringVar(&onConflict, "on-conflict", "", "What to do with existing files that differ: fail, skip, overwrite, or backup (keeps <file>.orig); default fail, or overwrite with --force")
+	cmd.Flags().BoolVar(&keepPart, "keep-partial", false, "Keep the files rendered before a failure instead of rolling back")
+	cmd.Flags().StringSliceVar(&omit, "omit", nil, "Optional generated files to leave out ("+strings.Join(generator.OptionalFiles, ", ")+")")
+	cmd.Flags().StringVar(&framework, "framework", "", "HTTP framework for the api, microservice and web-stack templates ("+strings.Join(components.Frameworks, ", ")+"), replacing the blueprint's")
+	cmd.Flags().BoolVar(&verifyBld, "verify-build", false, "Run go mod tidy, go generate ./... and go build ./... on the generated project and fail, naming the offending files, if it does not build")
+	cmd.Flags().BoolVar(&report, "report", false, "Write a JSON report of the files created, variables, commands run, timings and warnings to .gogo/report.json")
 	cmd.Flags().BoolVar(&wizard, "wizard", false, "Force interactive wizard mode (overrides --no-wizard)")
 	cmd.Flags().BoolVar(&noWizard, "no-wizard", false, "Disable interactive wizard mode")
+	cmd.Flags().StringVar(&answersFile, "answers", "", "Answer the wizard from this YAML file, as written by --save-answers")
+	cmd.Flags().StringVar(&saveAnswers, "save-answers", "", "Write the wizard's answers to this YAML file for --answers")
+	cmd.Flags().BoolVar(&nonInteract, "non-interactive", false, "Never prompt; answer the wizard from flags, GOGO_WIZARD_* variables and defaults (implied without a terminal)")
+	cmd.Flags().BoolVar(&usePrompts, "prompts", false, "Ask the wizard's questions one prompt after another instead of in a full-screen form")
+	cmd.Flags().BoolVar(&tsClient, "ts-client", false, "Generate a TypeScript client SDK under clients/ts (api, grpc, microservice)")
+	cmd.Flags().BoolVar(&licenses, "licenses", false, "Generate a third-party license check, make licenses target and CI report job (always on when the org policy sets licenses.required)")
+	cmd.Flags().StringSliceVar(&tags, "tag", nil, "Only offer templates and blueprints carrying these tags in the wizard")
+	cmd.Flags().StringVar(&into, "into", "", "Generate into an existing git repository (e.g. a freshly cloned empty repo)")
+	cmd.Flags().StringVar(&branch, "branch", generator.DefaultIntoBranch, "With --into, commit on this new branch (empty commits on the current branch)")
+	cmd.Flags().StringVar(&message, "message", "", "With --into, commit message (default: a pull-request-ready summary)")
+
+	completeFlag(cmd, "template", completeTemplates)
+	completeFlag(cmd, "blueprint", completeBlueprints)
+	profile.register(cmd)
+
+	return supportsJSON(cmd)
+}
+
+// printInitResult prints the result of gogo init or gogo apply
+func printInitResult(result generator.Result, opts generator.InitOptions) error {
+	if jsonOutput() {
+		return printJSON(result)
+	}
+	if !result.Success {
+		color.Red("Project initialization failed")
+		return nil
+	}
+
+	if result.Preview != nil {
+		printPreview(result.Preview, opts.OutputDir)
+	}
+	color.Green(result.Message)
+	if opts.GitInit {
+		color.Green("Git repository initialized")
+	}
+	for _, file := range result.FilesMerged {
+		color.Cyan("  merged into existing %s", file)
+	}
+	for _, conflict := range result.Conflicts {
+		switch conflict.Action {
+		case generator.ConflictSkip:
+			color.Yellow("  kept existing %s", conflict.Path)
+		case generator.ConflictBackup:
+			color.Yellow("  overwrote %s (previous version in %s%s)", conflict.Path, conflict.Path, generator.BackupSuffix)
+		default:
+			color.Yellow("  overwrote %s", conflict.Path)
+		}
+	}
+	if opts.Into && opts.Branch != "" && !opts.DryRun {
+		color.Cyan("Push %s and open a pull request to review the scaffold", opts.Branch)
+	}
+	if result.ReportPath != "" {
+		color.Cyan("Report written to %s", result.ReportPath)
+	}
+	if verbose {
+		for _, warning := range result.Warnings {
+			color.Yellow("Warning: %s", warning)
+		}
+	}
+	if len(result.NextSteps) > 0 {
+		fmt.Println()
+		color.Cyan("Next steps:")
+		for _, step := range result.NextSteps {
+			fmt.Printf("  %s\n", step)
+		}
+	}
+	return nil
+}
+
+// printPreview prints the files a dry run would write as a tree, followed by
+// a unified diff for every existing file whose content would change
+func printPreview(preview *generator.Preview, outputDir string) {
+	fmt.Print(preview.Tree(outputDir))
+
+	for _, file := range preview.Files {
+		if file.Diff == "" {
+			continue
+		}
+		fmt.Println()
+		printDiff(file.Diff)
+	}
+	fmt.Println()
+
+	if modified := preview.Count(generator.ChangeModified); modified > 0 {
+		color.Yellow("⚠ %d existing files would be overwritten; review the diffs before running with --force", modified)
+	}
+}
 
-	return cmd
+// applySetting sets value to setting when the flag was not given and the
+// setting is set
+func applySetting(cmd *cobra.Command, flag string, value *string, setting string) {
+	if setting != "" && !cmd.Flags().Changed(flag) {
+		*value = setting
+	}
+}
+
+// applyPreset sets the init flags of the named preset that were not given,
+// and names the module after the project under the preset's module prefix
+func applyPreset(cmd *cobra.Command, name, projectName string, moduleName *string) error {
+	presets, err := preset.Load(preset.DefaultPath())
+	if err != nil {
+		return err
+	}
+	p, err := preset.Find(presets, name)
+	if err != nil {
+		return err
+	}
+
+	for flag, value := range p.Flags() {
+		if cmd.Flags().Changed(flag) {
+			continue
+		}
+		if err := cmd.Flags().Set(flag, value); err != nil {
+			return fmt.Errorf("preset %s: invalid --%s: %w", name, flag, err)
+		}
+	}
+	if *moduleName == "" {
+		*moduleName = p.Module(projectName)
+	}
+	if verbose {
+		color.Cyan("Using preset %s", name)
+	}
+	return nil
+}
+
+// saveInitPreset saves the preset-able init flags that were given, or set by
+// --preset, as the named preset, replacing one of that name. A module under
+// a path ending in the project name is saved as the preset's module prefix.
+func saveInitPreset(cmd *cobra.Command, name, projectName, moduleName string) error {
+	p := preset.Preset{Name: name}
+	for _, flagName := range preset.FlagNames() {
+		flag := cmd.Flags().Lookup(flagName)
+		if flag == nil || !flag.Changed {
+			continue
+		}
+		value := flag.Value.String()
+		if slice, ok := flag.Value.(interface{ GetSlice() []string }); ok {
+			value = strings.Join(slice.GetSlice(), ",")
+		}
+		if err := p.SetFlag(flagName, value); err != nil {
+			return err
+		}
+	}
+	if prefix, ok := strings.CutSuffix(moduleName, "/"+projectName); ok && projectName != "" {
+		p.ModulePrefix = prefix
+	}
+
+	path := preset.DefaultPath()
+	presets, err := preset.Load(path)
+	if err != nil {
+		return err
+	}
+	if presets, err = preset.Put(presets, p, true); err != nil {
+		return err
+	}
+	if err := preset.Save(path, presets); err != nil {
+		return err
+	}
+	color.Green("✓ Saved preset %s to %s", name, path)
+	return nil
+}
+
+// printDiff prints a unified diff, colored like git diff
+func printDiff(diff string) {
+	for _, line := range strings.SplitAfter(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+			fmt.Print(color.New(color.Bold).Sprint(line))
+		case strings.HasPrefix(line, "@@"):
+			fmt.Print(color.CyanString(line))
+		case strings.HasPrefix(line, "+"):
+			fmt.Print(color.GreenString(line))
+		case strings.HasPrefix(line, "-"):
+			fmt.Print(color.RedString(line))
+		default:
+			fmt.Print(line)
+		}
+	}
+}
+
+// loadWizardEntries shows stored templates, tags and ratings in the wizard;
+// all are optional, so a missing or unreadable database only disables them
+func loadWizardEntries(ctx context.Context, wizard *prompt.Wizard) {
+	manager := db.NewManager()
+	if err := manager.Open(ctx, dbPath); err != nil {
+		if verbose {
+			color.Yellow("Tags unavailable: %v", err)
+		}
+		return
+	}
+	defer func() {
+		if closeErr := manager.Close(); closeErr != nil {
+			color.Red("Warning: failed to close database: %v", closeErr)
+		}
+	}()
+
+	stored, err := db.NewTemplateManager(manager).List(ctx)
+	if err != nil {
+		if verbose {
+			color.Yellow("Stored templates unavailable: %v", err)
+		}
+	} else {
+		wizard.SetStoredTemplates(stored)
+	}
+
+	tagManager := db.NewTagManager(manager)
+	annotationManager := db.NewAnnotationManager(manager)
+	for _, kind := range []string{db.KindTemplate, db.KindBlueprint} {
+		tagged, err := tagManager.Tagged(ctx, kind)
+		if err != nil {
+			if verbose {
+				color.Yellow("Tags unavailable: %v", err)
+			}
+			return
+		}
+		wizard.SetTags(kind, tagged)
+
+		annotations, err := annotationManager.All(ctx, kind)
+		if err != nil {
+			if verbose {
+				color.Yellow("Ratings unavailable: %v", err)
+			}
+			return
+		}
+		wizard.SetAnnotations(kind, annotations)
+	}
 }
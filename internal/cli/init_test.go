@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/user/gogo/internal/git"
+)
+
+func TestInit_CoverageMinAnswers(t *testing.T) {
+	if !git.IsGitInstalled() {
+		t.Skip("Git is not installed")
+	}
+	t.Setenv("GIT_AUTHOR_NAME", "Test Author")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Test Author")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+	t.Setenv("GOGO_WIZARD_MODULE", "github.com/acme/svc")
+	t.Setenv("GOGO_WIZARD_GIT_INIT", "true")
+	t.Setenv("GOGO_WIZARD_CI", "true")
+	t.Setenv("GOGO_WIZARD_CI_PROVIDER", "github")
+	t.Setenv("GOGO_WIZARD_DEPENDENCY_UPDATES", "none")
+
+	home := setupHome(t)
+	answers := filepath.Join(home, "answers.yaml")
+	require.NoError(t, os.WriteFile(answers, []byte("coverage_min: 91\n"), 0644))
+
+	tests := []struct {
+		name     string
+		env      string
+		args     []string
+		expected string
+	}{
+		{name: "variable", env: "93", expected: "93"},
+		{name: "answers file", args: []string{"--answers", answers}, expected: "91"},
+		{name: "flag overrides the answer", env: "93", args: []string{"--coverage-min", "90"}, expected: "90"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("GOGO_WIZARD_COVERAGE_MIN", tt.env)
+			dir := filepath.Join(t.TempDir(), "svc")
+			args := append([]string{"init", "svc", "--non-interactive", "--template=api", "--output-dir", dir}, tt.args...)
+			require.NoError(t, runGogo(t, home, args...))
+
+			makefile, err := os.ReadFile(filepath.Join(dir, "Makefile"))
+			require.NoError(t, err)
+			assert.Contains(t, string(makefile), "failing below "+tt.expected+"%")
+			ci, err := os.ReadFile(filepath.Join(dir, ".github", "workflows", "ci.yml"))
+			require.NoError(t, err)
+			assert.Contains(t, string(ci), "below minimum "+tt.expected)
+		})
+	}
+}
+
+func TestInit_CoverageMinIsAPercentage(t *testing.T) {
+	home := setupHome(t)
+	dir := filepath.Join(t.TempDir(), "svc")
+
+	err := runGogo(t, home, "init", "svc", "--no-wizard", "--template=api", "--module=github.com/acme/svc",
+		"--output-dir", dir, "--coverage-min", "0.85")
+	assert.ErrorContains(t, err, "--coverage-min is a percentage: use 85 for 85%")
+	assert.NoDirExists(t, dir)
+}
+
+func TestInit_PercentagePreset(t *testing.T) {
+	home := setupHome(t)
+
+	for name, coverage := range map[string]string{"percentage": "85", "legacy fraction": "0.85"} {
+		t.Run(name, func(t *testing.T) {
+			shared := filepath.Join(t.TempDir(), "presets.yaml")
+			require.NoError(t, os.WriteFile(shared, []byte("presets:\n  - name: team\n    template: api\n    coverage_min: "+coverage+"\n"), 0644))
+			require.NoError(t, runGogo(t, home, "preset", "import", shared, "--force"))
+
+			dir := filepath.Join(t.TempDir(), "svc")
+			require.NoError(t, runGogo(t, home, "init", "svc", "--no-wizard", "--preset", "team",
+				"--module=github.com/acme/svc", "--output-dir", dir))
+			makefile, err := os.ReadFile(filepath.Join(dir, "Makefile"))
+			require.NoError(t, err)
+			assert.Contains(t, string(makefile), "failing below 85%")
+		})
+	}
+}
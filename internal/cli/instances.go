@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/user/gogo/internal/db"
+)
+
+// exclusiveCommands replace or rewrite the database. Two of them never run at
+// once, and other commands are warned while one runs.
+var exclusiveCommands = []string{"gogo db migrate", "gogo db restore", "gogo db import", "gogo db vacuum"}
+
+// unregisteredCommands only read the database and may run where its
+// directory is read-only, as container health checks do, or run on every
+// press of tab in shells with completion
+var unregisteredCommands = []string{"gogo db healthprobe", "gogo completion",
+	"gogo " + cobra.ShellCompRequestCmd, "gogo " + cobra.ShellCompNoDescRequestCmd}
+
+// releaseInstance removes this process from the instance registry once the
+// command has run
+var releaseInstance = func() error { return nil }
+
+// registerInstance records the command in the instance registry of the
+// database and reports the other gogo processes using it. An exclusive
+// command fails while another exclusive one runs, so they do not overwrite
+// each other's changes. A registry that cannot be written is skipped.
+func registerInstance(cmd *cobra.Command) error {
+	role := cmd.CommandPath()
+	if slices.Contains(unregisteredCommands, role) {
+		return nil
+	}
+	if _, err := os.Stat(filepath.Dir(dbPath)); err != nil {
+		return nil
+	}
+
+	registry := db.NewInstanceRegistry(dbPath)
+	exclusive := slices.Contains(exclusiveCommands, role)
+	others, release, err := registry.Register(db.Instance{PID: os.Getpid(), Role: role, Exclusive: exclusive})
+	if err != nil {
+		if verbose {
+			color.Yellow("Warning: not registered in %s: %v", registry.Path(), err)
+		}
+		return nil
+	}
+	releaseInstance = release
+
+	for _, other := range others {
+		switch {
+		case exclusive && other.Exclusive:
+			if err := release(); err != nil && verbose {
+				color.Yellow("Warning: failed to unregister from %s: %v", registry.Path(), err)
+			}
+			releaseInstance = func() error { return nil }
+			cmd.SilenceUsage = true
+			return fmt.Errorf("%s (pid %d) is already rewriting %s; run %s when it has finished", other.Role, other.PID, dbPath, role)
+		case other.Exclusive:
+			color.Yellow("Warning: %s (pid %d) is rewriting %s; changes made meanwhile may be lost", other.Role, other.PID, dbPath)
+		case exclusive:
+			color.Yellow("Warning: %s (pid %d, running %s) is using %s while it is rewritten", other.Role, other.PID, time.Since(other.StartedAt).Round(time.Second), dbPath)
+		}
+	}
+	return nil
+}
+
+// otherInstances returns the other gogo processes using the database
+func otherInstances() ([]db.Instance, error) {
+	instances, err := db.NewInstanceRegistry(dbPath).List()
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(instances, func(instance db.Instance) bool {
+		return instance.PID == os.Getpid()
+	}), nil
+}
+
+// printInstances lists the other gogo processes using the database
+func printInstances() {
+	instances, err := otherInstances()
+	if err != nil {
+		color.Yellow("Warning: could not read the instance registry: %v", err)
+		return
+	}
+	if len(instances) == 0 {
+		return
+	}
+
+	fmt.Println()
+	color.Yellow("=== Other gogo Processes ===")
+	for _, instance := range instances {
+		mode := ""
+		if instance.Exclusive {
+			mode = " (rewriting the database)"
+		}
+		fmt.Printf("pid %-8d %s, running %s%s\n", instance.PID, instance.Role, time.Since(instance.StartedAt).Round(time.Second), mode)
+	}
+}
@@ -0,0 +1,51 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// annotationJSON marks the commands that support --json
+const annotationJSON = "gogo/json"
+
+// printAsJSON is set by the global --json flag. It is not named --output,
+// which many commands use for the file they write.
+var printAsJSON bool
+
+// jsonOutput reports whether results are printed as JSON
+func jsonOutput() bool {
+	return printAsJSON
+}
+
+// supportsJSON marks cmd as printing its result as JSON with --json
+func supportsJSON(cmd *cobra.Command) *cobra.Command {
+	if cmd.Annotations == nil {
+		cmd.Annotations = make(map[string]string)
+	}
+	cmd.Annotations[annotationJSON] = "true"
+	return cmd
+}
+
+// setOutputFormat validates --json for cmd. With JSON, progress and
+// warnings printed in color go to stderr so that stdout is only the result.
+func setOutputFormat(cmd *cobra.Command) error {
+	if !printAsJSON {
+		return nil
+	}
+	if cmd.Annotations[annotationJSON] == "" {
+		return fmt.Errorf("%s does not support --json", cmd.CommandPath())
+	}
+	color.Output = os.Stderr
+	return nil
+}
+
+// printJSON writes v to stdout as indented JSON
+func printJSON(v any) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
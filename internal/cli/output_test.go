@@ -0,0 +1,23 @@
+package cli
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONFlag_KeepsOutputFileFlags(t *testing.T) {
+	home := setupHome(t)
+	exportPath := filepath.Join(home, "export.sql")
+
+	// --output stays the file of db export; --json is the global flag
+	err := runGogo(t, home, "db", "export", "--output", exportPath, "--json")
+	assert.ErrorContains(t, err, "gogo db export does not support --json")
+	assert.NoFileExists(t, exportPath)
+
+	require.NoError(t, runGogo(t, home, "db", "export", "--output", exportPath))
+	assert.FileExists(t, exportPath)
+	require.NoError(t, runGogo(t, home, "db", "status", "--json"))
+}
@@ -0,0 +1,162 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/user/gogo/internal/db"
+	"github.com/user/gogo/internal/events"
+	"github.com/user/gogo/internal/generator"
+	"github.com/user/gogo/internal/policy"
+	"github.com/user/gogo/internal/templates"
+)
+
+func newPlanCommand() *cobra.Command {
+	return initCommand(true)
+}
+
+func newApplyCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "apply <plan-file>",
+		Short: "Generate the project a plan written by gogo plan describes",
+		Long: color.GreenString(`Carry out a plan written by gogo plan: write its files, run its commands
+and hooks and create its git repository or commit.
+
+The project is rendered again before anything is written, and the plan is
+refused when a file would no longer be generated as planned, or the hooks
+of project.generated changed, because templates, blueprints or the output
+directory changed since the plan was made. Run gogo plan again then.
+
+Examples:
+  gogo plan myapi --module=github.com/acme/myapi --template=api --no-wizard
+  gogo apply plan.json`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			plan, err := generator.LoadPlan(args[0])
+			if err != nil {
+				return err
+			}
+			cmd.SilenceUsage = true
+			if plan.GogoVersion != "" && plan.GogoVersion != gogoVersion {
+				color.Yellow("Warning: %s was planned with gogo %s; this is gogo %s", args[0], plan.GogoVersion, gogoVersion)
+			}
+
+			engine := templates.NewEngine()
+			repo := templates.NewRepository()
+			closeStore := useTemplateStore(ctx, repo)
+			defer closeStore()
+			gen := generator.NewProjectGenerator(engine, repo)
+			blueprintRepo, closeBlueprints := useBlueprintStore(ctx)
+			defer closeBlueprints()
+			gen.SetBlueprintRepository(blueprintRepo)
+
+			hooks, err := plannedHooks(ctx)
+			if err != nil {
+				return err
+			}
+			if !slices.Equal(hooks, plan.Hooks) {
+				return fmt.Errorf("the plan is stale: the hooks of %s changed since it was made (run gogo plan again)", events.ProjectGenerated)
+			}
+
+			orgPolicy, err := policy.Load(policy.DefaultPath())
+			if err != nil {
+				return err
+			}
+			// A plan made before the state policy changed lists the wrong files
+			plan.Options.StoreState = orgPolicy.State.Remote()
+			bus, closeEvents := useEventBus(ctx, orgPolicy.Hooks)
+			defer closeEvents()
+			gen.SetEvents(bus)
+
+			color.Yellow("Applying %s: %s in %s", args[0], plan.Options.ProjectName, plan.Options.OutputDir)
+			result, err := gen.Apply(ctx, plan)
+			if err != nil {
+				return fmt.Errorf("failed to apply plan: %w", err)
+			}
+			return printInitResult(result, plan.Options)
+		},
+	}
+	return supportsJSON(cmd)
+}
+
+// writePlan plans the generation of opts into path and prints the plan
+func writePlan(ctx context.Context, gen *generator.Generator, opts generator.InitOptions, path string) error {
+	plan, err := gen.Plan(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("failed to plan project: %w", err)
+	}
+	if plan.Hooks, err = plannedHooks(ctx); err != nil {
+		return err
+	}
+	if err := generator.SavePlan(path, plan); err != nil {
+		return err
+	}
+	if jsonOutput() {
+		return printJSON(plan)
+	}
+
+	fmt.Print((&generator.Preview{Files: plan.Files}).Tree(plan.Options.OutputDir))
+	fmt.Println()
+	color.Green("Plan: %d files in %s (%d new, %d modified, %d merged, %d unchanged)",
+		len(plan.Files), plan.Options.OutputDir, plan.Count(generator.ChangeNew), plan.Count(generator.ChangeModified),
+		plan.Count(generator.ChangeMerged), plan.Count(generator.ChangeUnchanged))
+	for _, command := range plan.Commands {
+		fmt.Printf("  run %s\n", command)
+	}
+	for _, hook := range plan.Hooks {
+		fmt.Printf("  hook %s on %s\n", hook.Name, hook.Event)
+	}
+	if plan.Git != nil {
+		switch {
+		case plan.Git.Init:
+			fmt.Println("  git init")
+		case plan.Git.Branch != "":
+			fmt.Printf("  git checkout -b %s\n", plan.Git.Branch)
+		}
+		subject, _, _ := strings.Cut(plan.Git.CommitMessage, "\n")
+		fmt.Printf("  git commit: %s\n", subject)
+	}
+	if modified := plan.Count(generator.ChangeModified); modified > 0 {
+		color.Yellow("⚠ %d existing files would be overwritten; the plan file has their diffs", modified)
+	}
+	color.Cyan("Review %s, then run gogo apply %s", path, path)
+	return nil
+}
+
+// plannedHooks returns the stored hooks gogo init runs when the project is
+// generated. Like the event bus, they are left out when the database cannot
+// be opened.
+func plannedHooks(ctx context.Context) ([]generator.PlanHook, error) {
+	manager := db.NewManager()
+	if err := manager.Open(ctx, dbPath); err != nil {
+		if verbose {
+			color.Yellow("Hooks unavailable: %v", err)
+		}
+		return nil, nil
+	}
+	defer func() {
+		if closeErr := manager.Close(); closeErr != nil {
+			color.Red("Warning: failed to close database: %v", closeErr)
+		}
+	}()
+
+	stored, err := manager.EventHooks(ctx, string(events.ProjectGenerated))
+	if err != nil {
+		return nil, err
+	}
+	var hooks []generator.PlanHook
+	for _, hook := range stored {
+		hooks = append(hooks, generator.PlanHook{
+			Name:     hook.Name,
+			Event:    hook.Event,
+			Language: hook.Language,
+			Script:   hook.Script,
+		})
+	}
+	return hooks, nil
+}
@@ -0,0 +1,241 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/user/gogo/internal/fsutil"
+	"github.com/user/gogo/internal/preset"
+	"gopkg.in/yaml.v3"
+)
+
+func newPresetCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "preset",
+		Short: "Preset management commands",
+		Long: color.GreenString(`Manage presets, named combinations of gogo init options.
+
+A preset sets the template, blueprint, framework, CI provider, dependency
+update bot, coverage minimum, task runner and other init flags at once:
+
+  gogo init orders --preset org-microservice --no-wizard
+
+Flags given on the command line override the preset's. A preset with a
+module_prefix also names the module after the project when --module is not
+given. Save the flags of an init run as a preset with --save-preset.
+
+Presets are kept in ` + preset.DefaultPath() + ` (env ` + preset.EnvPresetsPath + `).
+Share them with gogo preset export and gogo preset import.`),
+	}
+
+	cmd.AddCommand(newPresetListCommand())
+	cmd.AddCommand(newPresetShowCommand())
+	cmd.AddCommand(newPresetDeleteCommand())
+	cmd.AddCommand(newPresetExportCommand())
+	cmd.AddCommand(newPresetImportCommand())
+
+	return cmd
+}
+
+func newPresetListCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List saved presets",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			presets, err := preset.Load(preset.DefaultPath())
+			if err != nil {
+				return err
+			}
+			if len(presets) == 0 {
+				color.Yellow("No presets saved; save one with gogo init --save-preset <name>")
+				return nil
+			}
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "NAME\tTEMPLATE\tBLUEPRINT\tDESCRIPTION")
+			for _, p := range presets {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", p.Name, p.Template, p.Blueprint, p.Description)
+			}
+			return w.Flush()
+		},
+	}
+}
+
+func newPresetShowCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "show <name>",
+		Short: "Show the init flags a preset sets",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			presets, err := preset.Load(preset.DefaultPath())
+			if err != nil {
+				return err
+			}
+			p, err := preset.Find(presets, args[0])
+			if err != nil {
+				return err
+			}
+
+			color.Cyan("Preset %s", p.Name)
+			if p.Description != "" {
+				fmt.Println(p.Description)
+			}
+			flags := p.Flags()
+			names := make([]string, 0, len(flags))
+			for name := range flags {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				fmt.Printf("  --%s=%s\n", name, flags[name])
+			}
+			if p.ModulePrefix != "" {
+				fmt.Printf("  --module=%s\n", p.Module("<project>"))
+			}
+			return nil
+		},
+	}
+}
+
+func newPresetDeleteCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "delete <name>",
+		Short: "Delete a saved preset",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			path := preset.DefaultPath()
+			presets, err := preset.Load(path)
+			if err != nil {
+				return err
+			}
+			if _, err := preset.Find(presets, args[0]); err != nil {
+				return err
+			}
+
+			kept := presets[:0]
+			for _, p := range presets {
+				if p.Name != args[0] {
+					kept = append(kept, p)
+				}
+			}
+			if err := preset.Save(path, kept); err != nil {
+				return err
+			}
+			color.Green("✓ Deleted preset %s", args[0])
+			return nil
+		},
+	}
+}
+
+func newPresetExportCommand() *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "export [name...]",
+		Short: "Write presets to a file others can import",
+		Long: color.GreenString(`Export saved presets, all of them or those named, as YAML for
+gogo preset import. Without --output the YAML is printed.
+
+Examples:
+  gogo preset export org-microservice --output presets.yaml
+  gogo preset export > presets.yaml`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			presets, err := preset.Load(preset.DefaultPath())
+			if err != nil {
+				return err
+			}
+			if len(args) > 0 {
+				selected := make([]preset.Preset, 0, len(args))
+				for _, name := range args {
+					p, err := preset.Find(presets, name)
+					if err != nil {
+						return err
+					}
+					selected = append(selected, p)
+				}
+				presets = selected
+			}
+
+			data, err := yaml.Marshal(preset.File{Presets: presets})
+			if err != nil {
+				return fmt.Errorf("failed to encode presets: %w", err)
+			}
+			if output == "" {
+				fmt.Print(string(data))
+				return nil
+			}
+			if err := fsutil.WriteFile(output, data, 0644); err != nil {
+				return fmt.Errorf("failed to write %s: %w", output, err)
+			}
+			color.Green("✓ Exported %d presets to %s", len(presets), output)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Write the presets to this file instead of printing them")
+	return cmd
+}
+
+func newPresetImportCommand() *cobra.Command {
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "import <file>",
+		Short: "Add the presets of an export",
+		Long: color.GreenString(`Import the presets of a file written by gogo preset export.
+Presets named like saved ones are only replaced with --force.
+
+Examples:
+  gogo preset import presets.yaml
+  gogo preset import presets.yaml --force`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			if _, err := os.Stat(args[0]); err != nil {
+				return fmt.Errorf("failed to read presets: %w", err)
+			}
+			imported, err := preset.Load(args[0])
+			if err != nil {
+				return err
+			}
+			if len(imported) == 0 {
+				return fmt.Errorf("%s has no presets", args[0])
+			}
+
+			path := preset.DefaultPath()
+			presets, err := preset.Load(path)
+			if err != nil {
+				return err
+			}
+			names := make([]string, 0, len(imported))
+			for _, p := range imported {
+				if presets, err = preset.Put(presets, p, force); err != nil {
+					return err
+				}
+				names = append(names, p.Name)
+			}
+			if err := preset.Save(path, presets); err != nil {
+				return err
+			}
+			color.Green("✓ Imported presets %s", strings.Join(names, ", "))
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&force, "force", false, "Replace saved presets of the same name")
+	return cmd
+}
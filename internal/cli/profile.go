@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"runtime/trace"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// profileFlags captures CPU, heap and execution-trace profiles of a single
+// command run, so users reporting slow generation can attach them
+type profileFlags struct {
+	cpu   string
+	mem   string
+	trace string
+}
+
+func (p *profileFlags) register(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&p.cpu, "cpuprofile", "", "Write a CPU profile to this file")
+	cmd.Flags().StringVar(&p.mem, "memprofile", "", "Write a heap profile to this file when the command finishes")
+	cmd.Flags().StringVar(&p.trace, "trace", "", "Write an execution trace to this file")
+}
+
+// start begins the requested profiles; the returned stop function writes
+// them out and must be called once the command's work is done
+func (p *profileFlags) start() (func(), error) {
+	var stops []func() error
+
+	stop := func() {
+		for i := len(stops) - 1; i >= 0; i-- {
+			if err := stops[i](); err != nil {
+				color.Red("Warning: failed to write profile: %v", err)
+			}
+		}
+	}
+
+	if p.cpu != "" {
+		file, err := os.Create(p.cpu)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create CPU profile: %w", err)
+		}
+		if err := pprof.StartCPUProfile(file); err != nil {
+			file.Close()
+			return nil, fmt.Errorf("failed to start CPU profile: %w", err)
+		}
+		stops = append(stops, func() error {
+			pprof.StopCPUProfile()
+			return file.Close()
+		})
+	}
+
+	if p.trace != "" {
+		file, err := os.Create(p.trace)
+		if err != nil {
+			stop()
+			return nil, fmt.Errorf("failed to create trace: %w", err)
+		}
+		if err := trace.Start(file); err != nil {
+			file.Close()
+			stop()
+			return nil, fmt.Errorf("failed to start trace: %w", err)
+		}
+		stops = append(stops, func() error {
+			trace.Stop()
+			return file.Close()
+		})
+	}
+
+	if p.mem != "" {
+		path := p.mem
+		stops = append(stops, func() error {
+			file, err := os.Create(path)
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+			runtime.GC() // report live objects, not garbage awaiting collection
+			if err := pprof.WriteHeapProfile(file); err != nil {
+				return err
+			}
+			return file.Close()
+		})
+	}
+
+	return stop, nil
+}
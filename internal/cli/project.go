@@ -0,0 +1,124 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/user/gogo/internal/db"
+	"github.com/user/gogo/internal/project"
+)
+
+func newProjectCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "project",
+		Short: "Generated project commands",
+		Long: color.GreenString(`Work with projects generated by gogo.
+
+Projects are identified by the .gogo.yaml manifest written by gogo init.`),
+	}
+
+	cmd.AddCommand(newProjectArchiveCommand())
+
+	return cmd
+}
+
+func newProjectArchiveCommand() *cobra.Command {
+	var (
+		output string
+		noDB   bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "archive [path]",
+		Short: "Archive a generated project for later reconstruction",
+		Long: color.GreenString(`Write a tarball that can reconstruct or audit a project without gogo's database.
+
+The archive holds the project files (without .git), its .gogo.yaml manifest,
+the gogo.lock of its workspace when there is one, a replay record with the
+gogo init command that generated it, and the database entries, tags and
+annotations of its template and blueprint. ARCHIVE.json lists every file
+with its SHA-256 checksum.
+
+Examples:
+  gogo project archive
+  gogo project archive ./myproject -o myproject.tar.gz
+  gogo project archive --no-db`),
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir := "."
+			if len(args) > 0 {
+				dir = args[0]
+			}
+
+			manifest, err := project.LoadManifest(dir)
+			if err != nil {
+				return err
+			}
+			if output == "" {
+				output = fmt.Sprintf("%s-%s.tar.gz", manifest.ProjectName, time.Now().Format("2006-01-02"))
+			}
+
+			opts := project.ArchiveOptions{Dir: dir, Output: output, GogoVersion: gogoVersion}
+			if !noDB {
+				if opts.Entries, err = archiveEntries(cmd.Context(), manifest); err != nil {
+					return err
+				}
+			}
+
+			index, err := project.Archive(opts)
+			if err != nil {
+				return fmt.Errorf("failed to archive project: %w", err)
+			}
+
+			absOutput, _ := filepath.Abs(output)
+			color.Green("✓ Archived %s (%d files) to %s", index.Project, len(index.Files), absOutput)
+			if index.Lockfile {
+				fmt.Println("  Included gogo.lock")
+			}
+			if index.Database {
+				fmt.Printf("  Included %d database entries\n", len(opts.Entries))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Archive file to write (default <project>-<date>.tar.gz)")
+	cmd.Flags().BoolVar(&noDB, "no-db", false, "Leave database entries out of the archive")
+
+	return cmd
+}
+
+// archiveEntries exports the database records of a project's template and
+// blueprint
+func archiveEntries(ctx context.Context, manifest *project.Manifest) ([]*db.EntryRecord, error) {
+	manager := db.NewManager()
+	if err := manager.Open(ctx, dbPath); err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	defer func() {
+		if closeErr := manager.Close(); closeErr != nil {
+			color.Red("Warning: failed to close database: %v", closeErr)
+		}
+	}()
+
+	exportManager := db.NewExportManager(manager)
+	entries := make([]*db.EntryRecord, 0, 2)
+	record, err := exportManager.ExportEntry(ctx, db.KindTemplate, manifest.Template)
+	if err != nil {
+		return nil, err
+	}
+	entries = append(entries, record)
+
+	if manifest.Blueprint != "" {
+		record, err := exportManager.ExportEntry(ctx, db.KindBlueprint, manifest.Blueprint)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, record)
+	}
+	return entries, nil
+}
@@ -2,47 +2,164 @@ package cli
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/fatih/color"
+	"github.com/manifoldco/promptui"
 	"github.com/spf13/cobra"
+	"github.com/user/gogo/internal/config"
+	"github.com/user/gogo/internal/db"
+	"github.com/user/gogo/internal/prompt"
 )
 
 var (
-	dbPath    string
-	outputDir string
-	goVersion string
-	dryRun    bool
-	verbose   bool
+	dbPath      string
+	outputDir   string
+	goVersion   string
+	dryRun      bool
+	verbose     bool
+	gogoVersion string
+	timezone    string
+	dbFileMode  string
+	assumeYes   bool
+	displayLoc  = time.Local
+	settings    config.Config // Defaults set with gogo config
 )
 
+// EnvTimezone configures the default display timezone when --tz is not given
+const EnvTimezone = "GOGO_TZ"
+
 // Execute runs the root command
 func Execute(ctx context.Context, version string) error {
+	rootCmd := newRootCommand(version)
+	defer func() {
+		if err := releaseInstance(); err != nil && verbose {
+			color.Yellow("Warning: failed to unregister from the instance registry: %v", err)
+		}
+	}()
+	return rootCmd.ExecuteContext(ctx)
+}
+
+// newRootCommand builds the gogo command tree, resetting the global flags
+func newRootCommand(version string) *cobra.Command {
+	gogoVersion = version
+
 	rootCmd := &cobra.Command{
 		Use:   "gogo",
 		Short: "A Go project scaffolding CLI tool",
 		Long: color.CyanString(`gogo - Go Project Scaffolding CLI
 
 A command-line tool for generating idiomatic Go project scaffolds with templates,
-blueprints, and team collaboration features.`),
+blueprints, and team collaboration features.
+
+A database created by an older gogo is upgraded when a command opens it,
+after asking (or with --yes) and backing it up to <db-path>.v<version>.bak.
+Hooks brought in by gogo db import or restore run only after asking, or
+with --yes.`),
 		Version: version,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := setOutputFormat(cmd); err != nil {
+				return err
+			}
+			var err error
+			if settings, err = config.Load(config.DefaultPath()); err != nil {
+				return fmt.Errorf("%w (fix it with gogo config set)", err)
+			}
+			warnSettingsProblems(config.DefaultPath())
+			if settings.DBPath != "" && !cmd.Flags().Changed("db-path") {
+				dbPath = settings.DBPath
+			}
+
+			loc, err := loadDisplayLocation(timezone)
+			if err != nil {
+				return err
+			}
+			displayLoc = loc
+
+			mode, err := db.ParseFileMode(dbFileMode)
+			if err != nil {
+				return fmt.Errorf("invalid --db-file-mode: %w", err)
+			}
+			if err := db.SetFileMode(mode); err != nil {
+				return fmt.Errorf("invalid --db-file-mode: %w", err)
+			}
+			db.SetUpgradeConfirm(confirmDatabaseUpgrade)
+			if err := useProjectState(); err != nil {
+				return err
+			}
+			if err := useCatalog(); err != nil {
+				return err
+			}
+			return registerInstance(cmd)
+		},
 	}
 
 	// Global flags
-	rootCmd.PersistentFlags().StringVar(&dbPath, "db-path", getDefaultDBPath(), "Path to SQLite database")
+	rootCmd.PersistentFlags().StringVar(&dbPath, "db-path", getDefaultDBPath(), "Path to SQLite database (default from gogo config "+config.KeyDBPath+")")
 	rootCmd.PersistentFlags().StringVar(&outputDir, "output-dir", ".", "Output directory for generated files")
 	rootCmd.PersistentFlags().StringVar(&goVersion, "go-version", "", "Go version to use (auto-detect if empty)")
 	rootCmd.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "Preview changes without writing files")
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Enable verbose output")
+	rootCmd.PersistentFlags().StringVar(&dbFileMode, "db-file-mode", envOr(db.EnvFileMode, fmt.Sprintf("%04o", db.DefaultFileMode)), "Permissions for the database, backups and exports (octal; env "+db.EnvFileMode+")")
+	rootCmd.PersistentFlags().BoolVar(&assumeYes, "yes", false, "Upgrade a database created by an older gogo (it is backed up first) and run imported hooks without asking")
+	rootCmd.PersistentFlags().BoolVar(&printAsJSON, "json", false, "Print results as JSON (db status, db size, template list, blueprint list, init, plan, apply, add and catalog)")
+	rootCmd.PersistentFlags().StringVar(&timezone, "tz", os.Getenv(EnvTimezone), "Timezone for displayed timestamps (local, UTC, or an IANA name; env "+EnvTimezone+")")
 
 	// Add subcommands
 	rootCmd.AddCommand(newInitCommand())
+	rootCmd.AddCommand(newPlanCommand())
+	rootCmd.AddCommand(newApplyCommand())
 	rootCmd.AddCommand(newGenerateCommand())
 	rootCmd.AddCommand(newAddCommand())
 	rootCmd.AddCommand(newDBCommand())
+	rootCmd.AddCommand(newTemplateCommand())
+	rootCmd.AddCommand(newBlueprintCommand())
+	rootCmd.AddCommand(newPresetCommand())
+	rootCmd.AddCommand(newConfigCommand())
+	rootCmd.AddCommand(newStatusCommand())
+	rootCmd.AddCommand(newDriftCommand())
+	rootCmd.AddCommand(newVerifyCommand())
+	rootCmd.AddCommand(newUpgradeCommand())
+	rootCmd.AddCommand(newSearchCommand())
+	rootCmd.AddCommand(newAssetsCommand())
+	rootCmd.AddCommand(newExampleCommand())
+	rootCmd.AddCommand(newSyncCommand())
+	rootCmd.AddCommand(newAuthCommand())
+	rootCmd.AddCommand(newProjectCommand())
+	rootCmd.AddCommand(newCatalogCommand())
+	rootCmd.AddCommand(newCompletionCommand())
+	rootCmd.CompletionOptions.DisableDefaultCmd = true
+	return rootCmd
+}
 
-	return rootCmd.ExecuteContext(ctx)
+// confirmDatabaseUpgrade asks before a database created by an older gogo is
+// backed up and upgraded. Without a terminal, or with JSON output, only
+// --yes upgrades it.
+func confirmDatabaseUpgrade(path string, version int) bool {
+	if assumeYes {
+		return true
+	}
+	if jsonOutput() || !prompt.IsTerminal() {
+		return false
+	}
+	color.Yellow("%s was created by an older gogo (schema version %d); it needs upgrading to version %d.", path, version, db.SchemaVersion)
+	confirm := promptui.Prompt{
+		Label:     "Back it up and upgrade it",
+		IsConfirm: true,
+	}
+	_, err := confirm.Run()
+	return err == nil
+}
+
+// envOr returns the environment variable name, or fallback when it is unset
+func envOr(name, fallback string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+	return fallback
 }
 
 func getDefaultDBPath() string {
@@ -52,3 +169,20 @@ func getDefaultDBPath() string {
 	}
 	return filepath.Join(homeDir, ".gogo.db")
 }
+
+// loadDisplayLocation resolves the --tz value; timestamps are stored in UTC
+func loadDisplayLocation(name string) (*time.Location, error) {
+	if name == "" || name == "local" {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --tz %q: %w", name, err)
+	}
+	return loc, nil
+}
+
+// formatTime renders a timestamp in the display timezone
+func formatTime(t time.Time) string {
+	return t.In(displayLoc).Format("2006-01-02 15:04:05 MST")
+}
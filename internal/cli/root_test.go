@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/user/gogo/internal/catalog"
+	"github.com/user/gogo/internal/config"
+	"github.com/user/gogo/internal/policy"
+	"github.com/user/gogo/internal/preset"
+)
+
+// setupHome points gogo's database, config, policy, presets and catalog at a
+// temporary home directory and returns it
+func setupHome(t *testing.T) string {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv(config.EnvConfigPath, "")
+	t.Setenv(policy.EnvPolicyPath, "")
+	t.Setenv(preset.EnvPresetsPath, "")
+	t.Setenv(catalog.EnvCatalogPath, "")
+	return home
+}
+
+// runGogo runs gogo with args against the database in home
+func runGogo(t *testing.T, home string, args ...string) error {
+	t.Helper()
+	cmd := newRootCommand("test")
+	cmd.SetArgs(append([]string{"--db-path", filepath.Join(home, ".gogo.db")}, args...))
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	err := cmd.ExecuteContext(context.Background())
+	require.NoError(t, releaseInstance())
+	releaseInstance = func() error { return nil }
+	return err
+}
+
+// captureStdout returns what run prints to stdout, where results are printed
+func captureStdout(t *testing.T, run func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	done := make(chan []byte)
+	go func() {
+		data, _ := io.ReadAll(r)
+		done <- data
+	}()
+	run()
+	require.NoError(t, w.Close())
+	return string(<-done)
+}
@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/user/gogo/internal/db"
+)
+
+func newSearchCommand() *cobra.Command {
+	var (
+		kinds   []string
+		tags    []string
+		limit   int
+		reindex bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "search <query>",
+		Short: "Search stored templates, blueprints and audit entries",
+		Long: color.GreenString(`Search stored templates (names, descriptions and file contents),
+blueprints and the audit log.
+
+Results match any word of the query and are ranked by relevance. Builds with
+FTS5 (go build -tags sqlite_fts5) use a full-text index with stemming; other
+builds fall back to substring matching.
+
+Examples:
+  gogo search "template that sets up kafka consumers"
+  gogo search kafka --kind template --limit 5
+  gogo search consumer --tag payment --tag internal
+  gogo search --reindex`),
+		Args: func(cmd *cobra.Command, args []string) error {
+			if reindex {
+				return cobra.NoArgs(cmd, args)
+			}
+			return cobra.MinimumNArgs(1)(cmd, args)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			manager := db.NewManager()
+			if err := manager.Open(ctx, dbPath); err != nil {
+				return fmt.Errorf("failed to open database: %w", err)
+			}
+			defer func() {
+				if closeErr := manager.Close(); closeErr != nil {
+					color.Red("Warning: failed to close database: %v", closeErr)
+				}
+			}()
+
+			search := db.NewSearchManager(manager)
+
+			if reindex {
+				if err := search.Reindex(ctx); err != nil {
+					return err
+				}
+				color.Green("✓ Rebuilt search index")
+				return nil
+			}
+
+			if verbose && !search.FullText() {
+				color.Yellow("Full-text index unavailable; using substring matching")
+			}
+
+			results, err := search.Search(ctx, strings.Join(args, " "), db.SearchOptions{Kinds: kinds, Tags: tags, Limit: limit})
+			if err != nil {
+				return err
+			}
+
+			if len(results) == 0 {
+				color.Yellow("No matches")
+				return nil
+			}
+
+			annotations := make(map[string]map[string]db.Annotation)
+			for _, kind := range []string{db.KindTemplate, db.KindBlueprint} {
+				if annotations[kind], err = db.NewAnnotationManager(manager).All(ctx, kind); err != nil {
+					return err
+				}
+			}
+
+			for _, result := range results {
+				annotation := annotations[result.Kind][result.Name]
+				fmt.Printf("%s %s %s\n", color.CyanString("%-10s", result.Kind), result.Name, color.YellowString(annotation.Stars()))
+				if annotation.Note != "" {
+					fmt.Printf("           Note: %s\n", annotation.Note)
+				}
+				if result.Snippet != "" {
+					fmt.Printf("           %s\n", result.Snippet)
+				}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&kinds, "kind", nil, "Restrict results to kinds (template, blueprint, audit)")
+	cmd.Flags().StringSliceVar(&tags, "tag", nil, "Only return templates and blueprints carrying all of these tags")
+	cmd.Flags().IntVar(&limit, "limit", 20, "Maximum number of results")
+	cmd.Flags().BoolVar(&reindex, "reindex", false, "Rebuild the full-text index instead of searching")
+	return cmd
+}
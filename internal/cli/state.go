@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"context"
+
+	"github.com/fatih/color"
+	"github.com/user/gogo/internal/db"
+	"github.com/user/gogo/internal/policy"
+	"github.com/user/gogo/internal/state"
+)
+
+// useProjectState keeps project manifests and lockfiles in the gogo
+// database when the org policy asks for it
+func useProjectState() error {
+	orgPolicy, err := policy.Load(policy.DefaultPath())
+	if err != nil {
+		return err
+	}
+	if orgPolicy.State.Remote() {
+		state.Use(dbState{})
+	}
+	return nil
+}
+
+// dbState is a state.Store in the gogo database, which is opened for each
+// access since most commands never touch project state
+type dbState struct{}
+
+func (dbState) Get(modulePath, name string) ([]byte, error) {
+	var content []byte
+	err := withStateManager(func(ctx context.Context, states *db.ProjectStateManager) error {
+		var err error
+		content, err = states.Get(ctx, modulePath, name)
+		return err
+	})
+	return content, err
+}
+
+func (dbState) Save(modulePath, name string, content []byte) error {
+	return withStateManager(func(ctx context.Context, states *db.ProjectStateManager) error {
+		return states.Save(ctx, modulePath, name, content)
+	})
+}
+
+func withStateManager(fn func(ctx context.Context, states *db.ProjectStateManager) error) error {
+	ctx := context.Background()
+	manager := db.NewManager()
+	if err := manager.Open(ctx, dbPath); err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := manager.Close(); closeErr != nil {
+			color.Red("Warning: failed to close database: %v", closeErr)
+		}
+	}()
+	return fn(ctx, db.NewProjectStateManager(manager))
+}
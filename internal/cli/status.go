@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/user/gogo/internal/project"
+)
+
+func newStatusCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "status [path]",
+		Short: "Show the status of a generated project",
+		Long: color.GreenString(`Summarize how a project was generated and what has changed since.
+
+Reads the .gogo.yaml manifest written by gogo init and reports the gogo
+version used, template and blueprint, files that drifted from what was
+generated, CI configuration that the current gogo would render differently,
+and whether a newer gogo is available to upgrade the scaffold.
+
+Examples:
+  gogo status
+  gogo status ./myproject`),
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir := "."
+			if len(args) > 0 {
+				dir = args[0]
+			}
+
+			status, err := project.CheckStatus(cmd.Context(), dir, gogoVersion)
+			if err != nil {
+				return fmt.Errorf("failed to check project status: %w", err)
+			}
+
+			manifest := status.Manifest
+			color.Cyan("Project: %s (%s)", manifest.ProjectName, manifest.ModuleName)
+			fmt.Printf("  Generated by:   gogo %s\n", displayVersion(manifest.GogoVersion))
+			fmt.Printf("  Template:       %s\n", manifest.Template)
+			if manifest.Blueprint != "" {
+				fmt.Printf("  Blueprint:      %s\n", manifest.Blueprint)
+			}
+			fmt.Printf("  Go version:     %s\n", manifest.GoVersion)
+			fmt.Printf("  Last generated: %s (%s ago)\n",
+				formatTime(manifest.GeneratedAt),
+				time.Since(manifest.GeneratedAt).Round(time.Minute))
+			fmt.Println()
+
+			if len(status.Drift) == 0 {
+				color.Green("✓ %d managed files match the generated scaffold", len(manifest.Files))
+			} else {
+				color.Yellow("⚠ %d of %d managed files have drifted:", len(status.Drift), len(manifest.Files))
+				for _, drift := range status.Drift {
+					fmt.Printf("  %-9s %s\n", drift.State, drift.Path)
+				}
+			}
+
+			switch {
+			case manifest.CI == nil:
+				fmt.Println("- CI configuration was not generated")
+			case len(status.StaleCIFiles) == 0:
+				color.Green("✓ CI configuration is up to date")
+			default:
+				color.Yellow("⚠ CI configuration is stale (gogo %s renders it differently):", displayVersion(status.CurrentVersion))
+				for _, file := range status.StaleCIFiles {
+					fmt.Printf("  %s\n", file)
+				}
+			}
+
+			if status.UpgradeAvailable {
+				color.Yellow("⚠ Scaffold upgrade available: generated with gogo %s, running %s (run gogo upgrade)",
+					manifest.GogoVersion, status.CurrentVersion)
+			} else {
+				color.Green("✓ No pending scaffold upgrades")
+			}
+
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+func displayVersion(version string) string {
+	if version == "" {
+		return "unknown"
+	}
+	return version
+}
@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/user/gogo/internal/assetsync"
+)
+
+func newSyncCommand() *cobra.Command {
+	var (
+		strategy string
+		path     string
+		ref      string
+		name     string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "sync [repository-url]",
+		Short: "Consume a shared templates repository as a submodule or subtree",
+		Long: color.GreenString(`Add or update a shared templates/assets repository inside the current
+workspace and pin the synced commit in gogo.lock at the workspace root.
+
+With a URL, the repository is added (or updated if gogo.lock already has it).
+With --name, the locked source of that name is updated. Without arguments,
+every source in gogo.lock is updated to its locked ref.
+
+Strategies:
+  submodule   git submodule; changes are staged for you to commit
+  subtree     squashed git subtree; each sync is committed with the lockfile
+
+Examples:
+  gogo sync https://github.com/acme/gogo-templates.git --strategy submodule
+  gogo sync https://github.com/acme/gogo-templates.git --strategy subtree --path vendor/templates --ref v1.2.0
+  gogo sync --name gogo-templates --ref v1.3.0
+  gogo sync`),
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			syncer, err := assetsync.NewSyncer(cmd.Context(), ".")
+			if err != nil {
+				return err
+			}
+
+			var synced []assetsync.Source
+			if len(args) == 0 && name == "" {
+				synced, err = syncer.SyncAll(cmd.Context())
+				if err != nil {
+					return err
+				}
+			} else {
+				opts := assetsync.Options{Name: name, Path: path, Ref: ref, Strategy: strategy}
+				if len(args) > 0 {
+					opts.URL = args[0]
+				}
+				source, err := syncer.Sync(cmd.Context(), opts)
+				if err != nil {
+					return err
+				}
+				synced = append(synced, *source)
+			}
+
+			for _, source := range synced {
+				color.Green("✓ %s (%s) at %s pinned to %s", source.Name, source.Strategy, source.Path, source.Commit)
+			}
+			fmt.Printf("Lockfile: %s/%s\n", syncer.Root(), assetsync.LockFile)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&strategy, "strategy", "", fmt.Sprintf("How to consume the repository (%s; default submodule)", strings.Join(assetsync.Strategies, ", ")))
+	cmd.Flags().StringVar(&path, "path", "", "Directory for the repository, relative to the workspace root (default templates/<name>)")
+	cmd.Flags().StringVar(&ref, "ref", "", "Branch, tag or commit to pin (default: the remote's default branch)")
+	cmd.Flags().StringVar(&name, "name", "", "Source name in gogo.lock (default: the repository name)")
+
+	return cmd
+}
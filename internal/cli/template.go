@@ -0,0 +1,1699 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/user/gogo/internal/auth"
+	"github.com/user/gogo/internal/blueprints"
+	"github.com/user/gogo/internal/db"
+	"github.com/user/gogo/internal/fixtures"
+	"github.com/user/gogo/internal/policy"
+	"github.com/user/gogo/internal/registry"
+	"github.com/user/gogo/internal/templates"
+)
+
+func newTemplateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "template",
+		Short: "Template management commands",
+		Long:  color.GreenString(`Manage project templates and remote template registries.`),
+	}
+
+	cmd.AddCommand(newTemplateAddCommand())
+	cmd.AddCommand(newTemplateInstallCommand())
+	cmd.AddCommand(newTemplateListCommand())
+	cmd.AddCommand(newTemplateShowCommand())
+	cmd.AddCommand(newTemplateRefreshCommand())
+	cmd.AddCommand(newTemplateDownloadCommand())
+	cmd.AddCommand(newTemplateKeygenCommand())
+	cmd.AddCommand(newTemplateSignCommand())
+	cmd.AddCommand(newTemplateVerifyCommand())
+	cmd.AddCommand(newTemplateTrustCommand())
+	cmd.AddCommand(newTemplateRemoveCommand())
+	cmd.AddCommand(newTemplateTrashCommand())
+	cmd.AddCommand(newTemplateTagCommand())
+	cmd.AddCommand(newTemplateUntagCommand())
+	cmd.AddCommand(newTemplateTagsCommand())
+	cmd.AddCommand(newTemplateAnnotateCommand())
+	cmd.AddCommand(newTemplateVarsCommand())
+	cmd.AddCommand(newTemplateTestCommand())
+
+	return cmd
+}
+
+func newTemplateAddCommand() *cobra.Command {
+	var (
+		description string
+		force       bool
+		strict      bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "add [name] <dir|archive|repo>",
+		Short: "Store a template from a directory, archive or git repository",
+		Long: color.GreenString(`Store a user template in the gogo database.
+
+The template is read from a directory, a .tar.gz, .tgz or .zip archive, or
+a directory of a git repository written <repo>//<dir>@<ref>, such as
+github.com/acme/templates//templates/my-api@v1.2.0. The repository is a
+git URL or a host/owner/repo path fetched over HTTPS; the //<dir> part is
+optional for a template at the repository root, and the ref is a branch,
+tag or full commit (the default branch when left out). Only that commit is
+cloned, its template.yaml is checked, and the template is pinned to the
+commit, so sharing a template is sharing its URL. Without a name, a
+template from a repository is named after its directory.
+
+File paths and contents are rendered like the built-in templates, so both
+may use variables such as {{ ProjectName }}; a trailing .tmpl extension is
+dropped. Stored templates take precedence over built-in templates of the
+same name in gogo init.
+
+A template.yaml at the template root may declare a base template, such as
+"base: api". The stored files are then merged over the base's when the
+template is used: files at the same path replace the base's and the others
+are added, so an org template can be the api template plus its own logging
+and auth without copying it. A template based on its own name, like an api
+template with "base: api", builds on the built-in one.
+The file may also set next_steps, instructions printed after gogo init
+(e.g. "Run make dev"); they may use variables, and a template without
+them inherits its base's.
+
+An archive with a detached signature next to it (<archive>.sig) is verified
+against the trusted keys. With --strict, or "signing: strict" in the org
+policy, unsigned archives, directories and git repositories are refused.
+
+Examples:
+  gogo template add worker ./templates/worker
+  gogo template add org-api ./templates/org-api   # template.yaml: "base: api"
+  gogo template add worker worker-template.tar.gz --force
+  gogo template add github.com/acme/templates//templates/my-api@v1.2.0   # Stored as my-api
+  gogo template add org-api github.com/acme/templates//templates/my-api@3f2c1e9d4b5a6c7e8f9a0b1c2d3e4f5a6b7c8d9e
+  gogo init myworker --template=worker --no-wizard`),
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			name, source := "", args[len(args)-1]
+			if len(args) == 2 {
+				name = args[0]
+			}
+			strict, err := strictSigning(strict)
+			if err != nil {
+				return err
+			}
+			var (
+				files     []templates.TemplateFile
+				installed *db.InstallSource
+			)
+			if registry.IsRepoSource(source) {
+				if strict {
+					cmd.SilenceUsage = true
+					return fmt.Errorf("%s: %w (git repositories cannot be signed; add a signed archive)", source, registry.ErrUnsigned)
+				}
+				repo, _ := registry.ParseRepoSource(source)
+				if name == "" {
+					name = repo.Name()
+				}
+				color.Cyan("Fetching %s...", source)
+				files, installed, err = registry.FetchRepoTemplate(ctx, repo)
+			} else {
+				if name == "" {
+					return fmt.Errorf("a template from a directory or archive needs a name: gogo template add <name> %s", source)
+				}
+				if info, statErr := os.Stat(source); strict && statErr == nil && info.IsDir() {
+					cmd.SilenceUsage = true
+					return fmt.Errorf("%s: %w (directories cannot be signed; add a signed archive)", source, registry.ErrUnsigned)
+				}
+				files, err = templates.ReadTemplateFiles(source)
+			}
+			// The arguments are valid; later failures are not usage errors
+			cmd.SilenceUsage = true
+			if err != nil {
+				return err
+			}
+			config, err := templates.ParseTemplateConfig(files)
+			if err != nil {
+				return err
+			}
+
+			manager := db.NewManager()
+			if err := manager.Open(ctx, dbPath); err != nil {
+				return fmt.Errorf("failed to open database: %w", err)
+			}
+			defer func() {
+				if closeErr := manager.Close(); closeErr != nil {
+					color.Red("Warning: failed to close database: %v", closeErr)
+				}
+			}()
+
+			if info, err := os.Stat(source); err == nil && !info.IsDir() {
+				trusted, err := registry.NewTrustStore(manager).List(ctx)
+				if err != nil {
+					return err
+				}
+				result, err := registry.VerifyFile(source, trusted, registry.VerifyOptions{Strict: strict})
+				if err != nil {
+					return fmt.Errorf("verification failed: %w", err)
+				}
+				if result.Signed {
+					color.Green("✓ %s signed by %s (%s)", source, result.KeyName, result.KeyID)
+				}
+			}
+
+			stored := &db.StoredTemplate{Name: name, Description: description, Source: installed}
+			for _, file := range files {
+				stored.Files = append(stored.Files, db.StoredFile{Path: file.Path, Content: file.Content})
+			}
+			if err := db.NewTemplateManager(manager).Save(ctx, stored, force); err != nil {
+				if errors.Is(err, db.ErrTemplateExists) {
+					return fmt.Errorf("%w (use --force to replace it)", err)
+				}
+				return err
+			}
+
+			color.Green("✓ Stored template %s (%d files)", stored.Name, len(stored.Files))
+			if installed != nil {
+				color.Cyan("  Pinned to commit %s", installed.Commit)
+			}
+			if config.Base != "" {
+				color.Cyan("  Based on %s; files at the same path replace the base's", config.Base)
+			}
+			if _, err := templates.NewRepository().GetPredefinedTemplate(ctx, stored.Name); err == nil {
+				color.Yellow("⚠ It replaces the built-in %s template in gogo init", stored.Name)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&description, "description", "", "Description shown in gogo template list")
+	cmd.Flags().BoolVar(&force, "force", false, "Replace a stored template of the same name")
+	cmd.Flags().BoolVar(&strict, "strict", false, "Refuse sources without a signature by a trusted key")
+	return cmd
+}
+
+func newTemplateInstallCommand() *cobra.Command {
+	var opts registry.InstallOptions
+
+	cmd := &cobra.Command{
+		Use:   "install <url>",
+		Short: "Install templates and blueprints from a git repository or archive",
+		Long: color.GreenString(`Install templates and blueprints into the gogo database from a git
+repository or an HTTPS .tar.gz, .tgz or .zip archive.
+
+A source with templates/<name>/ directories or blueprints/<name>.yaml files
+is a pack: each template and blueprint in it is installed. Any other source
+is installed as a single template named after the URL or --name.
+
+Git sources accept a branch, tag or commit with --ref or <url>@<ref> and are
+pinned to the commit it resolves to. Archives are checked against --sha256
+and against the signature published at <url>.sig, which must be made by a
+key trusted with gogo template trust. With --strict, or "signing: strict"
+in the org policy, unsigned archives and git sources are refused. Run the
+install again with --force to update installed entries; they are verified
+the same way.
+The source, commit and checksum are recorded with every installed entry.
+Installed templates are offered by the gogo init wizard next to the
+built-in ones.
+
+Examples:
+  gogo template install https://github.com/acme/go-templates@v1.4.0
+  gogo template install git@github.com:acme/go-templates.git --ref 3f2c1e9
+  gogo template install https://example.com/worker.tar.gz --sha256 9b74c98...
+  gogo template install https://example.com/worker.tar.gz --name worker --force`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			strict, err := strictSigning(opts.Strict)
+			if err != nil {
+				return err
+			}
+			opts.Strict = strict
+
+			manager := db.NewManager()
+			if err := manager.Open(ctx, dbPath); err != nil {
+				return fmt.Errorf("failed to open database: %w", err)
+			}
+			defer func() {
+				if closeErr := manager.Close(); closeErr != nil {
+					color.Red("Warning: failed to close database: %v", closeErr)
+				}
+			}()
+
+			client := registry.NewClient(registry.NewCache(manager))
+			client.SetTokenSource(auth.NewResolver().Token)
+
+			result, err := registry.NewInstaller(manager, client).Install(ctx, args[0], opts)
+			if err != nil {
+				if registry.IsConflict(err) {
+					return fmt.Errorf("%w (use --force to replace it)", err)
+				}
+				return fmt.Errorf("install failed: %w", err)
+			}
+
+			for _, name := range result.Templates {
+				color.Green("✓ Installed template %s", name)
+			}
+			for _, name := range result.Blueprints {
+				color.Green("✓ Installed blueprint %s", name)
+			}
+			if result.Signature.Signed {
+				color.Green("✓ Signed by %s (%s)", result.Signature.KeyName, result.Signature.KeyID)
+			}
+			switch {
+			case result.Source.Commit != "":
+				color.Cyan("Pinned to commit %s", result.Source.Commit)
+			case opts.SHA256 != "":
+				color.Cyan("Verified SHA-256 %s", result.Source.SHA256)
+			default:
+				color.Yellow("⚠ No --sha256 given; pin this archive with --sha256 %s", result.Source.SHA256)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.Ref, "ref", "", "Git branch, tag or commit to install")
+	cmd.Flags().StringVar(&opts.SHA256, "sha256", "", "Expected SHA-256 of the archive")
+	cmd.Flags().StringVar(&opts.Name, "name", "", "Template name for a source holding a single template")
+	cmd.Flags().BoolVar(&opts.Force, "force", false, "Replace installed templates and blueprints of the same name")
+	cmd.Flags().BoolVar(&opts.Strict, "strict", false, "Refuse sources without a signature by a trusted key")
+	return cmd
+}
+
+// strictSigning reports whether templates must be signed by a trusted key,
+// because of the org policy or strict for this run
+func strictSigning(strict bool) (bool, error) {
+	orgPolicy, err := policy.Load(policy.DefaultPath())
+	if err != nil {
+		return false, err
+	}
+	return strict || orgPolicy.Signing.Strict, nil
+}
+
+// installOrigin describes where an installed template came from
+func installOrigin(source *db.InstallSource) string {
+	switch {
+	case source.Ref != "":
+		return source.URL + "@" + source.Ref
+	case source.Commit != "":
+		return source.URL + "@" + source.Commit[:min(len(source.Commit), 12)]
+	default:
+		return source.URL
+	}
+}
+
+func newTemplateListCommand() *cobra.Command {
+	var tags []string
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List built-in and stored templates",
+		Long: color.GreenString(`List the built-in templates and those stored or installed in the database,
+with their ratings and tags. A stored template named like a built-in one
+overrides it.
+
+Use --tag to list only the templates carrying every tag given, and --json
+for a machine-readable list.
+
+Examples:
+  gogo template list --tag internal --tag payment`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			manager := db.NewManager()
+			if err := manager.Open(ctx, dbPath); err != nil {
+				return fmt.Errorf("failed to open database: %w", err)
+			}
+			defer func() {
+				if closeErr := manager.Close(); closeErr != nil {
+					color.Red("Warning: failed to close database: %v", closeErr)
+				}
+			}()
+
+			stored, err := db.NewTemplateManager(manager).List(ctx)
+			if err != nil {
+				return err
+			}
+			tagged, err := db.NewTagManager(manager).Tagged(ctx, db.KindTemplate)
+			if err != nil {
+				return err
+			}
+			annotations, err := db.NewAnnotationManager(manager).All(ctx, db.KindTemplate)
+			if err != nil {
+				return err
+			}
+
+			rows := make(map[string]templateListEntry)
+			repo := templates.NewRepository()
+			predefined, err := repo.ListPredefinedTemplates(ctx)
+			if err != nil {
+				return err
+			}
+			for _, tmpl := range predefined {
+				files, err := repo.GetTemplateFiles(ctx, tmpl.Kind)
+				if err != nil {
+					return err
+				}
+				rows[tmpl.Kind] = templateListEntry{Name: tmpl.Kind, Source: "built-in", Files: len(files), Description: tmpl.Name}
+			}
+			for _, tmpl := range stored {
+				source, description := "stored", tmpl.Description
+				if tmpl.Source != nil {
+					source = "installed"
+					if description == "" {
+						description = installOrigin(tmpl.Source)
+					}
+				}
+				_, overrides := rows[tmpl.Name]
+				rows[tmpl.Name] = templateListEntry{Name: tmpl.Name, Source: source, Overrides: overrides, Files: len(tmpl.Files), Description: description}
+			}
+
+			names := make([]string, 0, len(rows))
+			for name := range rows {
+				if db.HasAllTags(tagged[name], tags) {
+					names = append(names, name)
+				}
+			}
+			sort.Strings(names)
+
+			if jsonOutput() {
+				entries := make([]templateListEntry, 0, len(names))
+				for _, name := range names {
+					entry := rows[name]
+					entry.Rating = annotations[name].Rating
+					entry.Tags = tagged[name]
+					entries = append(entries, entry)
+				}
+				return printJSON(entries)
+			}
+
+			if len(names) == 0 {
+				color.Yellow("No templates tagged %s", strings.Join(tags, ", "))
+				return nil
+			}
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "NAME\tSOURCE\tFILES\tRATING\tTAGS\tDESCRIPTION")
+			for _, name := range names {
+				r := rows[name]
+				source := r.Source
+				if r.Overrides {
+					source += " (overrides built-in)"
+				}
+				annotation := annotations[name]
+				fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\t%s\n", r.Name, source, r.Files,
+					annotation.Stars(), strings.Join(tagged[name], ","), r.Description)
+			}
+			return w.Flush()
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&tags, "tag", nil, "Only list templates carrying all of these tags")
+	return supportsJSON(cmd)
+}
+
+// templateListEntry is a template listed by gogo template list
+type templateListEntry struct {
+	Name        string   `json:"name"`
+	Source      string   `json:"source"` // built-in, stored or installed
+	Overrides   bool     `json:"overrides_builtin,omitempty"`
+	Files       int      `json:"files"`
+	Rating      int      `json:"rating,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	Description string   `json:"description"`
+}
+
+func newTemplateShowCommand() *cobra.Command {
+	var list bool
+
+	cmd := &cobra.Command{
+		Use:   "show <name> [file]",
+		Short: "Show the files of a template",
+		Long: color.GreenString(`Print the files of a stored or built-in template.
+
+Without a file argument every file is printed under a header with its
+output path; --list only prints the paths.
+
+Examples:
+  gogo template show worker
+  gogo template show worker --list
+  gogo template show cli go.mod`),
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			manager := db.NewManager()
+			if err := manager.Open(ctx, dbPath); err != nil {
+				return fmt.Errorf("failed to open database: %w", err)
+			}
+			defer func() {
+				if closeErr := manager.Close(); closeErr != nil {
+					color.Red("Warning: failed to close database: %v", closeErr)
+				}
+			}()
+
+			repo := templates.NewRepository()
+			repo.SetStore(templates.NewDBStore(manager))
+			files, err := repo.GetTemplateFiles(ctx, args[0])
+			if err != nil {
+				return err
+			}
+
+			if len(args) == 2 {
+				for _, file := range files {
+					if file.Path == args[1] || file.Name == args[1] {
+						fmt.Print(file.Content)
+						return nil
+					}
+				}
+				return fmt.Errorf("template %s has no file %s", args[0], args[1])
+			}
+
+			for i, file := range files {
+				if list {
+					fmt.Println(file.Path)
+					continue
+				}
+				if i > 0 {
+					fmt.Println()
+				}
+				color.Cyan("==> %s <==", file.Path)
+				fmt.Print(file.Content)
+				if !strings.HasSuffix(file.Content, "\n") {
+					fmt.Println()
+				}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&list, "list", false, "Only list the file paths")
+	return cmd
+}
+
+// useTemplateStore makes repo resolve templates stored in the database first.
+// Stored templates are optional, so a missing or unreadable database only
+// leaves the built-in templates. The returned function closes the database.
+func useTemplateStore(ctx context.Context, repo *templates.Repository) func() {
+	manager := db.NewManager()
+	if err := manager.Open(ctx, dbPath); err != nil {
+		if verbose {
+			color.Yellow("Stored templates unavailable: %v", err)
+		}
+		return func() {}
+	}
+
+	repo.SetStore(templates.NewDBStore(manager))
+	return func() {
+		if closeErr := manager.Close(); closeErr != nil {
+			color.Red("Warning: failed to close database: %v", closeErr)
+		}
+	}
+}
+
+// useBlueprintStore returns a blueprint repository that includes the custom
+// blueprints stored in the database. Like stored templates they are optional,
+// so a missing or unreadable database only leaves the predefined blueprints.
+// The returned function closes the database.
+func useBlueprintStore(ctx context.Context) (*blueprints.Repository, func()) {
+	manager := db.NewManager()
+	if err := manager.Open(ctx, dbPath); err != nil {
+		if verbose {
+			color.Yellow("Stored blueprints unavailable: %v", err)
+		}
+		return blueprints.NewRepository(), func() {}
+	}
+
+	return blueprints.NewStoredRepository(manager), func() {
+		if closeErr := manager.Close(); closeErr != nil {
+			color.Red("Warning: failed to close database: %v", closeErr)
+		}
+	}
+}
+
+func newTemplateRefreshCommand() *cobra.Command {
+	var force bool
+	var ttl time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "refresh [url...]",
+		Short: "Refresh cached registry indexes",
+		Long: color.GreenString(`Refresh cached remote registry resources.
+
+Without arguments, every cached URL is revalidated. Cached entries younger
+than --ttl are reused without contacting the registry; expired entries are
+revalidated with conditional requests (ETag / If-Modified-Since).
+
+Use --force to bypass the cache and re-download everything.`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			manager := db.NewManager()
+			if err := manager.Open(ctx, dbPath); err != nil {
+				return fmt.Errorf("failed to open database: %w", err)
+			}
+			defer func() {
+				if closeErr := manager.Close(); closeErr != nil {
+					color.Red("Warning: failed to close database: %v", closeErr)
+				}
+			}()
+
+			cache := registry.NewCache(manager)
+			client := registry.NewClient(cache)
+			client.SetTTL(ttl)
+			client.SetTokenSource(auth.NewResolver().Token)
+
+			urls := args
+			if len(urls) == 0 {
+				cached, err := cache.URLs(ctx)
+				if err != nil {
+					return err
+				}
+				urls = cached
+			}
+
+			if len(urls) == 0 {
+				color.Yellow("No cached registry resources to refresh")
+				return nil
+			}
+
+			failed := 0
+			for _, url := range urls {
+				result, err := client.Fetch(ctx, url, registry.FetchOptions{Force: force})
+				if err != nil {
+					color.Red("✗ %s: %v", url, err)
+					failed++
+					continue
+				}
+
+				switch {
+				case result.Stale:
+					color.Yellow("⚠ %s (registry unreachable, using cached copy)", url)
+				case result.NotModified:
+					color.Green("✓ %s (not modified)", url)
+				case result.FromCache:
+					color.Green("✓ %s (cached)", url)
+				default:
+					color.Green("✓ %s (updated, %d bytes)", url, len(result.Body))
+				}
+			}
+
+			if failed > 0 {
+				return fmt.Errorf("failed to refresh %d of %d resources", failed, len(urls))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&force, "force", false, "Bypass the cache and re-download")
+	cmd.Flags().DurationVar(&ttl, "ttl", registry.DefaultTTL, "Reuse cached entries younger than this without revalidation")
+	return cmd
+}
+
+func newTemplateDownloadCommand() *cobra.Command {
+	var (
+		output    string
+		limitRate string
+		retries   int
+		strict    bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "download <url>",
+		Short: "Download a template or blueprint pack",
+		Long: color.GreenString(`Download a template bundle in ranged chunks.
+
+Interrupted downloads are kept as <output>.part and resume from the last
+verified chunk when the command is run again. When the registry publishes
+<url>.chunks, every chunk is checked against its SHA-256 and re-requested
+on mismatch.
+
+The signature published at <url>.sig is saved next to the bundle and
+verified against the trusted keys; a bundle that fails verification is
+deleted. With --strict, or "signing: strict" in the org policy, unsigned
+bundles are deleted too.
+
+Examples:
+  gogo template download https://registry.example.com/packs/web.tar.gz
+  gogo template download https://registry.example.com/packs/web.tar.gz --limit-rate 500K`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			url := args[0]
+			if output == "" {
+				output = path.Base(url)
+			}
+
+			var rate int64
+			if limitRate != "" {
+				var err error
+				if rate, err = registry.ParseByteSize(limitRate); err != nil {
+					return fmt.Errorf("invalid --limit-rate: %w", err)
+				}
+			}
+
+			opts := registry.DownloadOptions{RateLimit: rate, Retries: retries}
+			if verbose {
+				opts.Progress = func(done, total int64) {
+					color.Cyan("  %d/%d bytes", done, total)
+				}
+			}
+
+			strict, err := strictSigning(strict)
+			if err != nil {
+				return err
+			}
+			result, err := registry.NewDownloader().Download(cmd.Context(), url, output, opts)
+			if err != nil {
+				return fmt.Errorf("download failed: %w", err)
+			}
+			signature, err := verifyDownload(cmd.Context(), url, result.Path, strict)
+			if err != nil {
+				os.Remove(result.Path)
+				os.Remove(result.Path + registry.SignatureExtension)
+				return fmt.Errorf("verification failed: %w", err)
+			}
+
+			if result.Resumed > 0 {
+				color.Yellow("Resumed after %d bytes", result.Resumed)
+			}
+			if result.Verified {
+				color.Green("✓ Downloaded %s (%d bytes, all chunks verified)", result.Path, result.Size)
+			} else {
+				color.Green("✓ Downloaded %s (%d bytes)", result.Path, result.Size)
+				color.Yellow("⚠ Registry published no chunk checksums")
+			}
+			if signature.Signed {
+				color.Green("✓ Signed by %s (%s)", signature.KeyName, signature.KeyID)
+			} else {
+				color.Yellow("⚠ %s is not signed", result.Path)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Destination file (defaults to the last URL path segment)")
+	cmd.Flags().StringVar(&limitRate, "limit-rate", "", "Maximum download rate in bytes per second (e.g. 500K, 2M)")
+	cmd.Flags().IntVar(&retries, "retries", registry.DefaultRetries, "Attempts per chunk before giving up")
+	cmd.Flags().BoolVar(&strict, "strict", false, "Refuse bundles without a signature by a trusted key")
+	return cmd
+}
+
+// verifyDownload saves the signature published for a downloaded bundle next
+// to it and verifies the bundle against the trusted keys
+func verifyDownload(ctx context.Context, url, bundlePath string, strict bool) (*registry.VerifyResult, error) {
+	manager := db.NewManager()
+	if err := manager.Open(ctx, dbPath); err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	defer func() {
+		if closeErr := manager.Close(); closeErr != nil {
+			color.Red("Warning: failed to close database: %v", closeErr)
+		}
+	}()
+
+	client := registry.NewClient(registry.NewCache(manager))
+	client.SetTokenSource(auth.NewResolver().Token)
+	sig, err := client.FetchSignature(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	if sig != nil {
+		sigData, err := sig.Marshal()
+		if err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(bundlePath+registry.SignatureExtension, sigData, 0644); err != nil {
+			return nil, fmt.Errorf("failed to write signature: %w", err)
+		}
+	}
+
+	trusted, err := registry.NewTrustStore(manager).List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return registry.VerifyFile(bundlePath, trusted, registry.VerifyOptions{Strict: strict})
+}
+
+func newTemplateKeygenCommand() *cobra.Command {
+	var name string
+
+	cmd := &cobra.Command{
+		Use:   "keygen",
+		Short: "Generate a template signing key pair",
+		Long: color.GreenString(`Generate an ed25519 key pair for signing template bundles.
+
+Writes <name>.key (private, mode 0600) and <name>.pub (public).
+Share the public key with consumers so they can run
+'gogo template trust add <name> <name>.pub'.`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			pub, priv, err := registry.GenerateKey()
+			if err != nil {
+				return err
+			}
+
+			if err := os.WriteFile(name+".key", []byte(registry.EncodeKey(priv)+"\n"), 0600); err != nil {
+				return fmt.Errorf("failed to write private key: %w", err)
+			}
+			if err := os.WriteFile(name+".pub", []byte(registry.EncodeKey(pub)+"\n"), 0644); err != nil {
+				return fmt.Errorf("failed to write public key: %w", err)
+			}
+
+			color.Green("✓ Generated key %s (id %s)", name, registry.KeyID(pub))
+			fmt.Printf("Private key: %s.key\n", name)
+			fmt.Printf("Public key:  %s.pub\n", name)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&name, "name", "gogo-signing", "Base file name for the key pair")
+	return cmd
+}
+
+func newTemplateSignCommand() *cobra.Command {
+	var keyFile string
+
+	cmd := &cobra.Command{
+		Use:   "sign <bundle>",
+		Short: "Sign a template bundle",
+		Long: color.GreenString(`Create a detached signature (<bundle>.sig) for a template bundle.
+
+Example:
+  gogo template sign my-template.tar.gz --key gogo-signing.key`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			bundlePath := args[0]
+
+			keyData, err := os.ReadFile(keyFile)
+			if err != nil {
+				return fmt.Errorf("failed to read private key: %w", err)
+			}
+			priv, err := registry.ParsePrivateKey(string(keyData))
+			if err != nil {
+				return err
+			}
+
+			data, err := os.ReadFile(bundlePath)
+			if err != nil {
+				return fmt.Errorf("failed to read bundle: %w", err)
+			}
+
+			sigData, err := registry.Sign(data, priv).Marshal()
+			if err != nil {
+				return fmt.Errorf("failed to encode signature: %w", err)
+			}
+
+			sigPath := bundlePath + registry.SignatureExtension
+			if err := os.WriteFile(sigPath, sigData, 0644); err != nil {
+				return fmt.Errorf("failed to write signature: %w", err)
+			}
+
+			color.Green("✓ Signed %s -> %s", bundlePath, sigPath)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&keyFile, "key", "", "Private key file")
+	_ = cmd.MarkFlagRequired("key")
+	return cmd
+}
+
+func newTemplateVerifyCommand() *cobra.Command {
+	var strict bool
+
+	cmd := &cobra.Command{
+		Use:   "verify <bundle>",
+		Short: "Verify a template bundle signature",
+		Long: color.GreenString(`Verify a template bundle against its detached signature and the trusted keys.
+
+Use --strict, or "signing: strict" in the org policy, to refuse bundles
+without a signature.`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			manager := db.NewManager()
+			if err := manager.Open(ctx, dbPath); err != nil {
+				return fmt.Errorf("failed to open database: %w", err)
+			}
+			defer func() {
+				if closeErr := manager.Close(); closeErr != nil {
+					color.Red("Warning: failed to close database: %v", closeErr)
+				}
+			}()
+
+			trusted, err := registry.NewTrustStore(manager).List(ctx)
+			if err != nil {
+				return err
+			}
+			strict, err := strictSigning(strict)
+			if err != nil {
+				return err
+			}
+
+			result, err := registry.VerifyFile(args[0], trusted, registry.VerifyOptions{Strict: strict})
+			if err != nil {
+				return fmt.Errorf("verification failed: %w", err)
+			}
+
+			if result.Signed {
+				color.Green("✓ %s signed by %s (%s)", args[0], result.KeyName, result.KeyID)
+			} else {
+				color.Yellow("⚠ %s is not signed", args[0])
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&strict, "strict", false, "Refuse unsigned bundles")
+	return cmd
+}
+
+func newTemplateTrustCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "trust",
+		Short: "Manage trusted template signing keys",
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "add <name> <public-key-file|public-key>",
+		Short: "Trust a signing key",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			encoded := args[1]
+			if data, err := os.ReadFile(args[1]); err == nil {
+				encoded = string(data)
+			}
+			pub, err := registry.ParsePublicKey(encoded)
+			if err != nil {
+				return err
+			}
+
+			manager := db.NewManager()
+			if err := manager.Open(ctx, dbPath); err != nil {
+				return fmt.Errorf("failed to open database: %w", err)
+			}
+			defer func() {
+				if closeErr := manager.Close(); closeErr != nil {
+					color.Red("Warning: failed to close database: %v", closeErr)
+				}
+			}()
+
+			if err := registry.NewTrustStore(manager).Add(ctx, args[0], pub); err != nil {
+				return err
+			}
+
+			color.Green("✓ Trusted key %s (%s)", args[0], registry.KeyID(pub))
+			return nil
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "List trusted signing keys",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			manager := db.NewManager()
+			if err := manager.Open(ctx, dbPath); err != nil {
+				return fmt.Errorf("failed to open database: %w", err)
+			}
+			defer func() {
+				if closeErr := manager.Close(); closeErr != nil {
+					color.Red("Warning: failed to close database: %v", closeErr)
+				}
+			}()
+
+			keys, err := registry.NewTrustStore(manager).List(ctx)
+			if err != nil {
+				return err
+			}
+
+			if len(keys) == 0 {
+				color.Yellow("No trusted keys")
+				return nil
+			}
+
+			for _, key := range keys {
+				fmt.Printf("%-20s %s\n", key.Name, registry.KeyID(key.PublicKey))
+			}
+			return nil
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "remove <name>",
+		Short: "Stop trusting a signing key",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			manager := db.NewManager()
+			if err := manager.Open(ctx, dbPath); err != nil {
+				return fmt.Errorf("failed to open database: %w", err)
+			}
+			defer func() {
+				if closeErr := manager.Close(); closeErr != nil {
+					color.Red("Warning: failed to close database: %v", closeErr)
+				}
+			}()
+
+			if err := registry.NewTrustStore(manager).Remove(ctx, args[0]); err != nil {
+				return err
+			}
+
+			color.Green("✓ Removed trusted key %s", args[0])
+			return nil
+		},
+	})
+
+	return cmd
+}
+
+func newTemplateRemoveCommand() *cobra.Command {
+	var blueprint bool
+
+	cmd := &cobra.Command{
+		Use:   "remove <name>",
+		Short: "Move a stored template or blueprint to the trash",
+		Long: color.GreenString(`Remove a stored template (or blueprint with --blueprint).
+
+Removed entries are kept in the trash and can be brought back with
+'gogo template trash restore' until they are purged.`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			manager := db.NewManager()
+			if err := manager.Open(ctx, dbPath); err != nil {
+				return fmt.Errorf("failed to open database: %w", err)
+			}
+			defer func() {
+				if closeErr := manager.Close(); closeErr != nil {
+					color.Red("Warning: failed to close database: %v", closeErr)
+				}
+			}()
+
+			kind := entryKind(blueprint)
+			if err := db.NewTrashManager(manager).Delete(ctx, kind, args[0]); err != nil {
+				return err
+			}
+
+			color.Green("✓ Moved %s %s to the trash", kind, args[0])
+			color.Cyan("  Undo with: gogo template trash restore %s%s", args[0], entryKindFlag(blueprint))
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&blueprint, "blueprint", false, "Remove a blueprint instead of a template")
+	return cmd
+}
+
+func newTemplateTrashCommand() *cobra.Command {
+	var (
+		blueprint bool
+		olderThan time.Duration
+		all       bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "trash",
+		Short: "Manage removed templates and blueprints",
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "List removed templates and blueprints",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			manager := db.NewManager()
+			if err := manager.Open(ctx, dbPath); err != nil {
+				return fmt.Errorf("failed to open database: %w", err)
+			}
+			defer func() {
+				if closeErr := manager.Close(); closeErr != nil {
+					color.Red("Warning: failed to close database: %v", closeErr)
+				}
+			}()
+
+			items, err := db.NewTrashManager(manager).List(ctx)
+			if err != nil {
+				return err
+			}
+
+			if len(items) == 0 {
+				color.Yellow("Trash is empty")
+				return nil
+			}
+
+			for _, item := range items {
+				fmt.Printf("%-10s %-30s deleted %s\n", item.Kind, item.Name, formatTime(item.DeletedAt))
+			}
+			return nil
+		},
+	})
+
+	restoreCmd := &cobra.Command{
+		Use:   "restore <name>",
+		Short: "Restore a removed template or blueprint",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			manager := db.NewManager()
+			if err := manager.Open(ctx, dbPath); err != nil {
+				return fmt.Errorf("failed to open database: %w", err)
+			}
+			defer func() {
+				if closeErr := manager.Close(); closeErr != nil {
+					color.Red("Warning: failed to close database: %v", closeErr)
+				}
+			}()
+
+			kind := entryKind(blueprint)
+			if err := db.NewTrashManager(manager).Restore(ctx, kind, args[0]); err != nil {
+				return err
+			}
+
+			color.Green("✓ Restored %s %s", kind, args[0])
+			return nil
+		},
+	}
+	restoreCmd.Flags().BoolVar(&blueprint, "blueprint", false, "Restore a blueprint instead of a template")
+	cmd.AddCommand(restoreCmd)
+
+	purgeCmd := &cobra.Command{
+		Use:   "purge [name]",
+		Short: "Permanently delete removed templates and blueprints",
+		Long: color.GreenString(`Permanently delete entries from the trash.
+
+Examples:
+  gogo template trash purge my-template
+  gogo template trash purge web-stack --blueprint
+  gogo template trash purge --older-than 720h
+  gogo template trash purge --all`),
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 && !all && olderThan == 0 {
+				return fmt.Errorf("specify a name, --older-than or --all")
+			}
+
+			ctx := cmd.Context()
+
+			manager := db.NewManager()
+			if err := manager.Open(ctx, dbPath); err != nil {
+				return fmt.Errorf("failed to open database: %w", err)
+			}
+			defer func() {
+				if closeErr := manager.Close(); closeErr != nil {
+					color.Red("Warning: failed to close database: %v", closeErr)
+				}
+			}()
+
+			trash := db.NewTrashManager(manager)
+
+			if len(args) == 1 {
+				kind := entryKind(blueprint)
+				if err := trash.Purge(ctx, kind, args[0]); err != nil {
+					return err
+				}
+				color.Green("✓ Permanently deleted %s %s", kind, args[0])
+				return nil
+			}
+
+			purged, err := trash.PurgeOlderThan(ctx, olderThan)
+			for _, item := range purged {
+				color.Green("✓ Permanently deleted %s %s", item.Kind, item.Name)
+			}
+			if err != nil {
+				return err
+			}
+			if len(purged) == 0 {
+				color.Yellow("Nothing to purge")
+			}
+			return nil
+		},
+	}
+	purgeCmd.Flags().BoolVar(&blueprint, "blueprint", false, "Purge a blueprint instead of a template")
+	purgeCmd.Flags().DurationVar(&olderThan, "older-than", 0, "Purge every entry removed longer ago than this")
+	purgeCmd.Flags().BoolVar(&all, "all", false, "Empty the trash")
+	cmd.AddCommand(purgeCmd)
+
+	return cmd
+}
+
+func newTemplateTagCommand() *cobra.Command {
+	var blueprint bool
+
+	cmd := &cobra.Command{
+		Use:   "tag <name> <tag>...",
+		Short: "Tag a template or blueprint",
+		Long: color.GreenString(`Tag a template (or blueprint with --blueprint) for filtering in
+search results and the init wizard. Built-in templates are tagged by kind.
+
+Examples:
+  gogo template tag api internal payment
+  gogo template tag web-stack deprecated --blueprint`),
+		Args: cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			manager := db.NewManager()
+			if err := manager.Open(ctx, dbPath); err != nil {
+				return fmt.Errorf("failed to open database: %w", err)
+			}
+			defer func() {
+				if closeErr := manager.Close(); closeErr != nil {
+					color.Red("Warning: failed to close database: %v", closeErr)
+				}
+			}()
+
+			kind := entryKind(blueprint)
+			if err := db.NewTagManager(manager).Tag(ctx, kind, args[0], args[1:]...); err != nil {
+				return err
+			}
+
+			color.Green("✓ Tagged %s %s: %s", kind, args[0], strings.Join(args[1:], ", "))
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&blueprint, "blueprint", false, "Tag a blueprint instead of a template")
+	return cmd
+}
+
+func newTemplateUntagCommand() *cobra.Command {
+	var blueprint bool
+
+	cmd := &cobra.Command{
+		Use:   "untag <name> <tag>...",
+		Short: "Remove tags from a template or blueprint",
+		Args:  cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			manager := db.NewManager()
+			if err := manager.Open(ctx, dbPath); err != nil {
+				return fmt.Errorf("failed to open database: %w", err)
+			}
+			defer func() {
+				if closeErr := manager.Close(); closeErr != nil {
+					color.Red("Warning: failed to close database: %v", closeErr)
+				}
+			}()
+
+			kind := entryKind(blueprint)
+			if err := db.NewTagManager(manager).Untag(ctx, kind, args[0], args[1:]...); err != nil {
+				return err
+			}
+
+			color.Green("✓ Removed tags from %s %s: %s", kind, args[0], strings.Join(args[1:], ", "))
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&blueprint, "blueprint", false, "Untag a blueprint instead of a template")
+	return cmd
+}
+
+func newTemplateTagsCommand() *cobra.Command {
+	var blueprint bool
+
+	cmd := &cobra.Command{
+		Use:   "tags [name]",
+		Short: "List tags, or the tags of one template or blueprint",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			manager := db.NewManager()
+			if err := manager.Open(ctx, dbPath); err != nil {
+				return fmt.Errorf("failed to open database: %w", err)
+			}
+			defer func() {
+				if closeErr := manager.Close(); closeErr != nil {
+					color.Red("Warning: failed to close database: %v", closeErr)
+				}
+			}()
+
+			tagManager := db.NewTagManager(manager)
+
+			if len(args) == 1 {
+				tags, err := tagManager.TagsFor(ctx, entryKind(blueprint), args[0])
+				if err != nil {
+					return err
+				}
+				if len(tags) == 0 {
+					color.Yellow("%s %s has no tags", entryKind(blueprint), args[0])
+					return nil
+				}
+				fmt.Println(strings.Join(tags, "\n"))
+				return nil
+			}
+
+			counts, err := tagManager.List(ctx)
+			if err != nil {
+				return err
+			}
+			if len(counts) == 0 {
+				color.Yellow("No tags")
+				return nil
+			}
+			for _, tag := range counts {
+				fmt.Printf("%-20s %d\n", tag.Name, tag.Count)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&blueprint, "blueprint", false, "Show the tags of a blueprint instead of a template")
+	return cmd
+}
+
+func newTemplateAnnotateCommand() *cobra.Command {
+	var (
+		blueprint bool
+		rating    int
+		remove    bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "annotate <name> [note]",
+		Short: "Attach a local note and rating to a template or blueprint",
+		Long: color.GreenString(`Attach a note and a 1-5 rating to a template or blueprint to help your
+team curate which entries to use. Without a note or --rating, the current
+annotation is shown. Names that only match a blueprint are annotated as one.
+
+Examples:
+  gogo template annotate web-stack "works great with our infra" --rating 5
+  gogo template annotate api --rating 3
+  gogo template annotate api
+  gogo template annotate api --clear`),
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			name := args[0]
+
+			manager := db.NewManager()
+			if err := manager.Open(ctx, dbPath); err != nil {
+				return fmt.Errorf("failed to open database: %w", err)
+			}
+			defer func() {
+				if closeErr := manager.Close(); closeErr != nil {
+					color.Red("Warning: failed to close database: %v", closeErr)
+				}
+			}()
+
+			kind, err := resolveEntryKind(ctx, manager, name, blueprint)
+			if err != nil {
+				return err
+			}
+			annotations := db.NewAnnotationManager(manager)
+
+			if remove {
+				if err := annotations.Delete(ctx, kind, name); err != nil {
+					return err
+				}
+				color.Green("✓ Removed annotation from %s %s", kind, name)
+				return nil
+			}
+
+			annotation, err := annotations.Get(ctx, kind, name)
+			if err != nil {
+				return err
+			}
+
+			if len(args) == 1 && !cmd.Flags().Changed("rating") {
+				if annotation == nil {
+					color.Yellow("%s %s has no annotation", kind, name)
+					return nil
+				}
+				printAnnotation(annotation)
+				return nil
+			}
+
+			if annotation == nil {
+				annotation = &db.Annotation{Kind: kind, Name: name}
+			}
+			if len(args) == 2 {
+				annotation.Note = args[1]
+			}
+			if cmd.Flags().Changed("rating") {
+				annotation.Rating = rating
+			}
+			if err := annotations.Save(ctx, annotation); err != nil {
+				return err
+			}
+
+			color.Green("✓ Annotated %s %s", kind, name)
+			printAnnotation(annotation)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&blueprint, "blueprint", false, "Annotate a blueprint even if a template has the same name")
+	cmd.Flags().IntVar(&rating, "rating", 0, fmt.Sprintf("Rating from 1 to %d (0 clears the rating)", db.MaxRating))
+	cmd.Flags().BoolVar(&remove, "clear", false, "Remove the note and rating")
+	return cmd
+}
+
+// resolveEntryKind decides whether name refers to a template or a blueprint,
+// preferring templates when both exist
+func resolveEntryKind(ctx context.Context, manager *db.Manager, name string, blueprint bool) (string, error) {
+	if blueprint {
+		return db.KindBlueprint, nil
+	}
+	if _, err := templates.NewRepository().GetPredefinedTemplate(ctx, name); err == nil {
+		return db.KindTemplate, nil
+	}
+
+	var storedTemplates int
+	if err := manager.GetDB().QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM templates WHERE name = ?`, name).Scan(&storedTemplates); err != nil {
+		return "", fmt.Errorf("failed to look up template %s: %w", name, err)
+	}
+	if storedTemplates > 0 {
+		return db.KindTemplate, nil
+	}
+
+	if _, err := blueprints.NewRepository().GetBlueprint(ctx, name); err == nil {
+		return db.KindBlueprint, nil
+	}
+	var storedBlueprints int
+	if err := manager.GetDB().QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM blueprints WHERE name = ?`, name).Scan(&storedBlueprints); err != nil {
+		return "", fmt.Errorf("failed to look up blueprint %s: %w", name, err)
+	}
+	if storedBlueprints > 0 {
+		return db.KindBlueprint, nil
+	}
+
+	return db.KindTemplate, nil
+}
+
+func printAnnotation(annotation *db.Annotation) {
+	if stars := annotation.Stars(); stars != "" {
+		fmt.Printf("  Rating:  %s\n", stars)
+	}
+	if annotation.Note != "" {
+		fmt.Printf("  Note:    %s\n", annotation.Note)
+	}
+	fmt.Printf("  Updated: %s\n", formatTime(annotation.UpdatedAt))
+}
+
+func entryKind(blueprint bool) string {
+	if blueprint {
+		return db.KindBlueprint
+	}
+	return db.KindTemplate
+}
+
+func entryKindFlag(blueprint bool) string {
+	if blueprint {
+		return " --blueprint"
+	}
+	return ""
+}
+
+func newTemplateVarsCommand() *cobra.Command {
+	var (
+		blueprint bool
+		format    string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "vars <kind>",
+		Short: "Document the variables a template uses",
+		Long: color.GreenString(`Statically analyze the files of a template and list every variable they
+reference: its inferred type, whether it is required, and where it is used.
+
+A variable is required when it is rendered outside any if block without a
+default filter; variables only rendered inside if blocks show the variables
+that guard them. Use --format markdown to generate documentation.
+
+Examples:
+  gogo template vars api
+  gogo template vars web-stack --blueprint
+  gogo template vars cli --format markdown > docs/cli-template.md`),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			sources, err := templateVarSources(cmd.Context(), args[0], blueprint)
+			if err != nil {
+				return err
+			}
+			vars := templates.AnalyzeVariables(sources)
+
+			switch format {
+			case "table":
+				printVarsTable(vars)
+			case "markdown":
+				printVarsMarkdown(args[0], vars)
+			case "json":
+				encoder := json.NewEncoder(os.Stdout)
+				encoder.SetIndent("", "  ")
+				return encoder.Encode(vars)
+			default:
+				return fmt.Errorf("unsupported format %q (use table, markdown or json)", format)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&blueprint, "blueprint", false, "Analyze the templates of a blueprint (name or stack) instead of a project template")
+	cmd.Flags().StringVar(&format, "format", "table", "Output format (table, markdown, json)")
+	return cmd
+}
+
+func newTemplateTestCommand() *cobra.Command {
+	var (
+		fixturesPath string
+		run          string
+		noBuild      bool
+		keep         string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "test [dir|archive]",
+		Short: "Render and build a template source for every fixture case",
+		Long: color.GreenString(`Render a template source (a single template or a pack with templates/
+and blueprints/ directories) once for every case of its fixtures file, then
+run go mod tidy and go build in each rendered project. Conditional branches
+a single render never takes, such as the no-database variant, are built too.
+
+The fixtures file (fixtures.yaml at the source root, never installed as a
+template file) lists explicit cases and a matrix of variable values:
+
+  template: worker
+  variables:
+    Author: CI
+  cases:
+    - name: gin-postgres
+      variables: {Components: [gin], HasDatabase: true, DatabaseType: postgres}
+    - name: no-db
+      variables: {HasDatabase: false}
+      build: false            # render only
+    - name: oldest-go
+      go_version: "1.21"      # go.mod and language level of Go 1.21
+  matrix:
+    variables:
+      Framework: [gin, chi]
+      DatabaseType: [postgres, sqlite]
+    exclude:
+      - {Framework: chi, DatabaseType: postgres}
+
+Matrix cases are named after their values, e.g. DatabaseType=sqlite,Framework=chi.
+Every case is rendered as project fixture, module example.com/fixture.
+To build with the toolchain of each Go release a template supports, use
+the github.com/user/gogo/pkg/scaffoldtest helpers in the template
+repository's go tests.
+
+Examples:
+  gogo template test ./templates/worker
+  gogo template test . --run 'postgres'
+  gogo template test pack.tar.gz --no-build --keep ./rendered
+
+  # In the CI of a template repository
+  - run: go install github.com/user/gogo/cmd/gogo@latest
+  - run: gogo template test .`),
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Run in CI; the failing cases are the useful output
+			cmd.SilenceUsage = true
+			ctx := cmd.Context()
+
+			source := "."
+			if len(args) > 0 {
+				source = args[0]
+			}
+			if fixturesPath == "" {
+				info, err := os.Stat(source)
+				if err != nil {
+					return fmt.Errorf("failed to read template source: %w", err)
+				}
+				if !info.IsDir() {
+					return fmt.Errorf("--fixtures is required for archive sources")
+				}
+				fixturesPath = filepath.Join(source, templates.FixturesFile)
+			}
+
+			runner, err := fixtures.NewRunner(source)
+			if err != nil {
+				return err
+			}
+			set, err := fixtures.Load(fixturesPath)
+			if err != nil {
+				return err
+			}
+			cases, err := set.Expand(runner.DefaultTemplate())
+			if err != nil {
+				return fmt.Errorf("%s: %w", fixturesPath, err)
+			}
+			if cases, err = fixtures.Select(cases, run); err != nil {
+				return err
+			}
+			if len(cases) == 0 {
+				return fmt.Errorf("no fixture cases match %q", run)
+			}
+
+			workDir := keep
+			if workDir != "" {
+				if err := os.MkdirAll(workDir, 0755); err != nil {
+					return fmt.Errorf("failed to create %s: %w", workDir, err)
+				}
+			} else {
+				if workDir, err = os.MkdirTemp("", "gogo-fixtures-*"); err != nil {
+					return fmt.Errorf("failed to create temporary directory: %w", err)
+				}
+				defer os.RemoveAll(workDir)
+			}
+
+			failed := 0
+			for _, c := range cases {
+				result := runner.Run(ctx, c, workDir, !noBuild)
+				if result.Err != nil {
+					failed++
+					color.Red("✗ %s (%s): %v", c.Name, c.Template, result.Err)
+					for _, line := range strings.Split(strings.TrimRight(result.Output, "\n"), "\n") {
+						if line != "" {
+							fmt.Printf("    %s\n", line)
+						}
+					}
+					continue
+				}
+				status := "rendered"
+				if result.Built {
+					status = "built"
+				}
+				color.Green("✓ %s (%s): %s %d files in %s", c.Name, c.Template, status, result.Files,
+					result.Duration.Round(10*time.Millisecond))
+				if verbose || keep != "" {
+					fmt.Printf("    %s\n", result.Dir)
+				}
+			}
+
+			if failed > 0 {
+				return fmt.Errorf("%d of %d fixture cases failed", failed, len(cases))
+			}
+			color.Green("All %d fixture cases passed", len(cases))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&fixturesPath, "fixtures", "", "Fixtures file (default: fixtures.yaml in the source directory)")
+	cmd.Flags().StringVar(&run, "run", "", "Only run cases whose names match this regular expression")
+	cmd.Flags().BoolVar(&noBuild, "no-build", false, "Only render the cases, without go mod tidy and go build")
+	cmd.Flags().StringVar(&keep, "keep", "", "Render the cases under this directory and keep them")
+	return cmd
+}
+
+// templateVarSources collects the file bodies and output paths of a project
+// template or a blueprint's stack templates
+func templateVarSources(ctx context.Context, name string, blueprint bool) ([]templates.Source, error) {
+	var sources []templates.Source
+	add := func(fileName, filePath, content string) {
+		sources = append(sources, templates.Source{Name: fileName, Content: content})
+		if strings.Contains(filePath, "{") {
+			sources = append(sources, templates.Source{Name: fileName + " (path)", Content: filePath})
+		}
+	}
+
+	if blueprint {
+		stack := name
+		repo, closeStore := useBlueprintStore(ctx)
+		defer closeStore()
+		if bp, err := repo.GetBlueprint(ctx, name); err == nil {
+			stack = bp.Stack
+		}
+		files, ok := templates.GetBlueprintTemplates()[stack]
+		if !ok {
+			return nil, fmt.Errorf("no templates for blueprint or stack %q", name)
+		}
+		for _, file := range files {
+			add(file.Name, file.Path, file.Content)
+		}
+		return sources, nil
+	}
+
+	repo := templates.NewRepository()
+	closeStore := useTemplateStore(ctx, repo)
+	defer closeStore()
+
+	files, err := repo.GetTemplateFiles(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		add(file.Name, file.Path, file.Content)
+	}
+	return sources, nil
+}
+
+func printVarsTable(vars []templates.Variable) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VARIABLE\tTYPE\tREQUIRED\tUSED IN")
+	for _, v := range vars {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", v.Name, v.Type, varRequirement(v), varUsages(v))
+	}
+	w.Flush()
+}
+
+func printVarsMarkdown(name string, vars []templates.Variable) {
+	fmt.Printf("# Variables of %s\n\n", name)
+	fmt.Println("| Variable | Type | Required | Used in |")
+	fmt.Println("| --- | --- | --- | --- |")
+	for _, v := range vars {
+		fmt.Printf("| `%s` | %s | %s | %s |\n", v.Name, v.Type, varRequirement(v), varUsages(v))
+	}
+}
+
+func varRequirement(v templates.Variable) string {
+	switch {
+	case v.Required:
+		return "yes"
+	case len(v.When) > 0:
+		return "if " + strings.Join(v.When, ", ")
+	default:
+		return "no"
+	}
+}
+
+func varUsages(v templates.Variable) string {
+	usages := make([]string, len(v.Usages))
+	for i, usage := range v.Usages {
+		usages[i] = fmt.Sprintf("%s:%d", usage.Source, usage.Line)
+	}
+	return strings.Join(usages, ", ")
+}
@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/user/gogo/internal/registry"
+)
+
+// writeBundle writes a template bundle holding files to path
+func writeBundle(t *testing.T, path string, files map[string]string) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+	require.NoError(t, os.WriteFile(path, buf.Bytes(), 0644))
+	return buf.Bytes()
+}
+
+func TestTemplateAdd_StrictSigning(t *testing.T) {
+	home := setupHome(t)
+	bundle := filepath.Join(home, "worker.tar.gz")
+	data := writeBundle(t, bundle, map[string]string{"main.go.tmpl": "package main\n"})
+	pub, priv, err := registry.GenerateKey()
+	require.NoError(t, err)
+	require.NoError(t, runGogo(t, home, "template", "trust", "add", "acme", registry.EncodeKey(pub)))
+	writeSignature := func(data []byte) {
+		sig, err := registry.Sign(data, priv).Marshal()
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(bundle+registry.SignatureExtension, sig, 0644))
+	}
+
+	// Unsigned bundles are refused with --strict or by the org policy
+	err = runGogo(t, home, "template", "add", "worker", bundle, "--strict")
+	assert.ErrorIs(t, err, registry.ErrUnsigned)
+	require.NoError(t, os.MkdirAll(filepath.Join(home, ".gogo"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(home, ".gogo", "policy.yaml"), []byte("signing:\n  strict: true\n"), 0644))
+	err = runGogo(t, home, "template", "add", "worker", bundle)
+	assert.ErrorIs(t, err, registry.ErrUnsigned)
+	err = runGogo(t, home, "template", "add", "github.com/acme/templates//worker@v1")
+	assert.ErrorIs(t, err, registry.ErrUnsigned)
+	dir := filepath.Join(home, "worker")
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go.tmpl"), []byte("package main\n"), 0644))
+	err = runGogo(t, home, "template", "add", "worker", dir)
+	assert.ErrorIs(t, err, registry.ErrUnsigned)
+	assert.ErrorContains(t, err, "add a signed archive")
+
+	// A signature over other contents is refused
+	writeSignature([]byte("tampered"))
+	err = runGogo(t, home, "template", "add", "worker", bundle)
+	assert.ErrorContains(t, err, "signature verification failed")
+
+	writeSignature(data)
+	require.NoError(t, runGogo(t, home, "template", "add", "worker", bundle))
+}
+
+func TestTemplateList_Tags(t *testing.T) {
+	home := setupHome(t)
+	require.NoError(t, runGogo(t, home, "template", "tag", "api", "internal", "payment"))
+	require.NoError(t, runGogo(t, home, "template", "tag", "grpc", "internal"))
+
+	list := func(args ...string) []string {
+		var entries []templateListEntry
+		output := captureStdout(t, func() {
+			require.NoError(t, runGogo(t, home, append([]string{"template", "list", "--json"}, args...)...))
+		})
+		require.NoError(t, json.Unmarshal([]byte(output), &entries))
+		names := []string{}
+		for _, entry := range entries {
+			names = append(names, entry.Name)
+		}
+		return names
+	}
+
+	assert.Equal(t, []string{"api", "grpc"}, list("--tag", "internal"))
+	assert.Equal(t, []string{"api"}, list("--tag", "internal", "--tag", "Payment"), "every tag is required")
+	assert.Equal(t, []string{"api"}, list("--tag", "internal,payment"))
+	assert.Empty(t, list("--tag", "deprecated"))
+	assert.Greater(t, len(list()), 2)
+}
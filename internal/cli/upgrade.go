@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/user/gogo/internal/generator"
+	"github.com/user/gogo/internal/merge"
+	"github.com/user/gogo/internal/templates"
+	"github.com/user/gogo/internal/upgrade"
+)
+
+func newUpgradeCommand() *cobra.Command {
+	var interactive bool
+
+	cmd := &cobra.Command{
+		Use:   "upgrade [path]",
+		Short: "Re-apply updated templates to a generated project",
+		Long: color.GreenString(`Re-render a project generated by gogo with this version of gogo and apply
+what changed in the templates, such as updated CI workflows or Dockerfiles.
+
+The project is rendered again from its .gogo.yaml manifest, then:
+  - files you have not edited are replaced with the new output
+  - files you have edited get a three-way merge of the template changes
+    into your changes, using the originally generated content from the
+    project's git history as the base
+  - in files with "# gogo:start" and "# gogo:end" markers, such as the CI
+    workflows and .golangci.yml, only the sections within the markers are
+    regenerated; everything outside them is yours and kept as it is
+  - template changes that conflict with your edits are written to a .rej
+    file next to the file, or resolved one by one with --interactive
+  - new files are added; files you deleted are not restored, and files
+    the templates no longer generate are left in place
+
+The manifest records the new gogo version. Commit or stash your work first
+so the upgrade is easy to review and undo.
+
+Examples:
+  gogo upgrade
+  gogo upgrade ./myproject --dry-run
+  gogo upgrade --interactive`),
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// The arguments are valid; later failures are not usage errors
+			cmd.SilenceUsage = true
+
+			dir := "."
+			if len(args) > 0 {
+				dir = args[0]
+			}
+
+			repo := templates.NewRepository()
+			closeStore := useTemplateStore(cmd.Context(), repo)
+			defer closeStore()
+			gen := generator.NewProjectGenerator(templates.NewEngine(), repo)
+			blueprintRepo, closeBlueprints := useBlueprintStore(cmd.Context())
+			defer closeBlueprints()
+			gen.SetBlueprintRepository(blueprintRepo)
+
+			opts := upgrade.Options{GogoVersion: gogoVersion, DryRun: dryRun}
+			if interactive {
+				opts.Resolver = merge.NewInteractiveResolver()
+			}
+			report, err := upgrade.Run(cmd.Context(), dir, gen, opts)
+			if err != nil {
+				return fmt.Errorf("failed to upgrade project: %w", err)
+			}
+
+			if dryRun {
+				color.Yellow("DRY RUN: upgrading from gogo %s to %s would change:", report.From, report.To)
+			} else {
+				color.Cyan("Upgraded from gogo %s to %s", report.From, report.To)
+			}
+			changed := report.Changed()
+			for _, file := range changed {
+				switch file.Action {
+				case upgrade.ActionConflict:
+					color.Red("  %-9s %s (%d hunks in %s.rej)", file.Action, file.Path, file.Rejected, file.Path)
+				case upgrade.ActionSkipped, upgrade.ActionObsolete:
+					color.Yellow("  %-9s %s", file.Action, file.Path)
+				default:
+					color.Green("  %-9s %s", file.Action, file.Path)
+				}
+				if file.Sections {
+					fmt.Printf("            regenerated the gogo:start/gogo:end sections; kept the rest\n")
+				}
+				if file.Markers != "" {
+					fmt.Printf("            %s; merged the whole file\n", file.Markers)
+				}
+				if file.NoBase {
+					fmt.Printf("            original content not in git history; compared the whole file\n")
+				}
+			}
+			if len(changed) == 0 {
+				color.Green("✓ Project is up to date")
+				return nil
+			}
+
+			fmt.Println()
+			if conflicts := report.Count(upgrade.ActionConflict); conflicts > 0 {
+				color.Yellow("%d files have conflicting changes; review the .rej files and apply what you need", conflicts)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&interactive, "interactive", false, "Resolve conflicting changes one by one instead of writing .rej files")
+
+	return cmd
+}
@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/user/gogo/internal/generator"
+	"github.com/user/gogo/internal/templates"
+	"github.com/user/gogo/internal/verify"
+)
+
+func newVerifyCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "verify [path]",
+		Short: "Check the health of a generated project",
+		Long: color.GreenString(`Run a quick sanity check on a project generated by gogo, for example after
+manual edits or a merge.
+
+Checks that:
+  - the .gogo.yaml manifest is complete and every managed file still exists
+  - no managed file holds unresolved merge conflict markers
+  - go.mod declares the module path the project was generated with
+  - the CI workflows and other generated YAML files parse
+  - the Makefile, Dockerfile and CI configuration match a fresh render
+    from the manifest
+
+Edited files and critical files that differ from a fresh render are
+reported as warnings; anything else that fails makes the command exit
+non-zero.
+
+Examples:
+  gogo verify
+  gogo verify ./myproject`),
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Run in CI; a usage dump on a failed check only adds noise
+			cmd.SilenceUsage = true
+
+			dir := "."
+			if len(args) > 0 {
+				dir = args[0]
+			}
+
+			repo := templates.NewRepository()
+			closeStore := useTemplateStore(cmd.Context(), repo)
+			defer closeStore()
+			gen := generator.NewProjectGenerator(templates.NewEngine(), repo)
+			blueprintRepo, closeBlueprints := useBlueprintStore(cmd.Context())
+			defer closeBlueprints()
+			gen.SetBlueprintRepository(blueprintRepo)
+
+			report, err := verify.Run(cmd.Context(), dir, gen)
+			if err != nil {
+				return fmt.Errorf("failed to verify project: %w", err)
+			}
+
+			color.Cyan("Verifying %s (%s)", report.Manifest.ProjectName, report.Manifest.ModuleName)
+			for _, check := range report.Checks {
+				switch check.Status {
+				case verify.StatusOK:
+					color.Green("✓ %s: %s", check.Name, check.Summary)
+				case verify.StatusWarn:
+					color.Yellow("⚠ %s: %s", check.Name, check.Summary)
+				default:
+					color.Red("✗ %s: %s", check.Name, check.Summary)
+				}
+				for _, detail := range check.Details {
+					fmt.Printf("  %s\n", detail)
+				}
+			}
+			fmt.Println()
+
+			if !report.Healthy() {
+				return fmt.Errorf("%d of %d checks failed", report.Count(verify.StatusFail), len(report.Checks))
+			}
+			if warnings := report.Count(verify.StatusWarn); warnings > 0 {
+				color.Yellow("Project is healthy with %d warnings", warnings)
+			} else {
+				color.Green("Project is healthy")
+			}
+			return nil
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,204 @@
+package components
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Request collection formats emitted alongside generated handlers
+const (
+	CollectionBruno   = "bruno"
+	CollectionPostman = "postman"
+	CollectionNone    = "none"
+)
+
+// CollectionDir is where request collections are kept, relative to the project root
+const CollectionDir = "docs/requests"
+
+const postmanSchema = "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+
+// crudRoute is a generated handler route with an example payload
+type crudRoute struct {
+	Name   string
+	Method string
+	Path   string
+	Body   map[string]any
+}
+
+// crudRoutes mirrors the routes registered by the handler template, with example
+// payloads derived from the model's create/update request fields
+func crudRoutes(variables map[string]any) []crudRoute {
+	title := variables["TitleName"].(string)
+	base := "/api/v1/" + variables["KebabName"].(string) + "s"
+	example := map[string]any{
+		"name":        "Example " + title,
+		"description": "An example " + strings.ToLower(title),
+	}
+
+	return []crudRoute{
+		{Name: "List " + title + "s", Method: "GET", Path: base},
+		{Name: "Get " + title, Method: "GET", Path: base + "/:id"},
+		{Name: "Create " + title, Method: "POST", Path: base, Body: example},
+		{Name: "Update " + title, Method: "PUT", Path: base + "/:id", Body: example},
+		{Name: "Delete " + title, Method: "DELETE", Path: base + "/:id"},
+	}
+}
+
+// generateCollection writes the request collection for a handler and returns the files written
+func (g *Generator) generateCollection(opts GenerateOptions, variables map[string]any) ([]string, error) {
+	switch opts.Collection {
+	case CollectionNone:
+		return nil, nil
+	case CollectionPostman:
+		return writePostmanCollection(opts, variables)
+	case "", CollectionBruno:
+		return writeBrunoCollection(opts, variables)
+	default:
+		return nil, fmt.Errorf("unsupported collection format '%s' (expected %s, %s, or %s)", opts.Collection, CollectionBruno, CollectionPostman, CollectionNone)
+	}
+}
+
+// writeBrunoCollection writes one .bru file per route into a folder for the resource,
+// creating the collection's bruno.json and local environment on first use
+func writeBrunoCollection(opts GenerateOptions, variables map[string]any) ([]string, error) {
+	root := filepath.Join(opts.OutputDir, filepath.FromSlash(CollectionDir))
+	folder := variables["KebabName"].(string) + "s"
+
+	var files []string
+	name := opts.ProjectName
+	if name == "" {
+		name = "api"
+	}
+	scaffold := map[string]string{
+		"bruno.json":             fmt.Sprintf("{\n  \"version\": \"1\",\n  \"name\": %q,\n  \"type\": \"collection\"\n}\n", name),
+		"environments/local.bru": "vars {\n  baseUrl: http://localhost:8080\n}\n",
+	}
+	for rel, content := range scaffold {
+		path := filepath.Join(root, filepath.FromSlash(rel))
+		if _, err := os.Stat(path); err == nil {
+			continue
+		}
+		if err := writeCollectionFile(path, []byte(content)); err != nil {
+			return nil, err
+		}
+		files = append(files, CollectionDir+"/"+rel)
+	}
+
+	for i, route := range crudRoutes(variables) {
+		var b strings.Builder
+		fmt.Fprintf(&b, "meta {\n  name: %s\n  type: http\n  seq: %d\n}\n\n", route.Name, i+1)
+
+		bodyMode := "none"
+		if route.Body != nil {
+			bodyMode = "json"
+		}
+		fmt.Fprintf(&b, "%s {\n  url: {{baseUrl}}%s\n  body: %s\n  auth: none\n}\n",
+			strings.ToLower(route.Method), brunoPath(route.Path), bodyMode)
+
+		if route.Body != nil {
+			payload, err := json.MarshalIndent(route.Body, "  ", "  ")
+			if err != nil {
+				return nil, err
+			}
+			fmt.Fprintf(&b, "\nbody:json {\n  %s\n}\n", payload)
+		}
+
+		rel := folder + "/" + toKebabCase(route.Name) + ".bru"
+		if err := writeCollectionFile(filepath.Join(root, filepath.FromSlash(rel)), []byte(b.String())); err != nil {
+			return nil, err
+		}
+		files = append(files, CollectionDir+"/"+rel)
+	}
+
+	return files, nil
+}
+
+// writePostmanCollection adds or refreshes the resource's folder in the project's
+// Postman collection, preserving every other folder and setting
+func writePostmanCollection(opts GenerateOptions, variables map[string]any) ([]string, error) {
+	name := opts.ProjectName
+	if name == "" {
+		name = "api"
+	}
+	rel := CollectionDir + "/" + name + ".postman_collection.json"
+	path := filepath.Join(opts.OutputDir, filepath.FromSlash(rel))
+
+	collection := map[string]any{
+		"info": map[string]any{"name": name, "schema": postmanSchema},
+		"item": []any{},
+		"variable": []any{
+			map[string]any{"key": "baseUrl", "value": "http://localhost:8080"},
+		},
+	}
+	if data, err := os.ReadFile(path); err == nil {
+		if err := json.Unmarshal(data, &collection); err != nil {
+			return nil, fmt.Errorf("failed to parse existing collection %s: %w", rel, err)
+		}
+	}
+
+	folderName := variables["TitleName"].(string)
+	var requests []any
+	for _, route := range crudRoutes(variables) {
+		segments := strings.Split(strings.TrimPrefix(route.Path, "/"), "/")
+		request := map[string]any{
+			"method": route.Method,
+			"header": []any{},
+			"url": map[string]any{
+				"raw":  "{{baseUrl}}" + route.Path,
+				"host": []string{"{{baseUrl}}"},
+				"path": segments,
+			},
+		}
+		if route.Body != nil {
+			payload, err := json.MarshalIndent(route.Body, "", "  ")
+			if err != nil {
+				return nil, err
+			}
+			request["header"] = []any{map[string]any{"key": "Content-Type", "value": "application/json"}}
+			request["body"] = map[string]any{"mode": "raw", "raw": string(payload)}
+		}
+		requests = append(requests, map[string]any{"name": route.Name, "request": request})
+	}
+	folder := map[string]any{"name": folderName, "item": requests}
+
+	items, _ := collection["item"].([]any)
+	replaced := false
+	for i, item := range items {
+		if existing, ok := item.(map[string]any); ok && existing["name"] == folderName {
+			items[i] = folder
+			replaced = true
+		}
+	}
+	if !replaced {
+		items = append(items, folder)
+	}
+	collection["item"] = items
+
+	data, err := json.MarshalIndent(collection, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode collection: %w", err)
+	}
+	if err := writeCollectionFile(path, append(data, '\n')); err != nil {
+		return nil, err
+	}
+
+	return []string{rel}, nil
+}
+
+// brunoPath converts route parameters to an example value, since Bruno has no path variables
+func brunoPath(path string) string {
+	return strings.ReplaceAll(path, ":id", "1")
+}
+
+func writeCollectionFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package components
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateHandler_BrunoCollection(t *testing.T) {
+	tempDir := t.TempDir()
+	generator := NewGenerator()
+
+	result, err := generator.Generate(context.Background(), GenerateOptions{
+		Type:        "handler",
+		Name:        "user",
+		OutputDir:   tempDir,
+		ProjectName: "myapi",
+	})
+	require.NoError(t, err)
+	assert.Contains(t, result.Files, "docs/requests/users/create-user.bru")
+	assert.Contains(t, result.Files, "docs/requests/bruno.json")
+
+	create, err := os.ReadFile(filepath.Join(tempDir, "docs", "requests", "users", "create-user.bru"))
+	require.NoError(t, err)
+	assert.Contains(t, string(create), "post {")
+	assert.Contains(t, string(create), "url: {{baseUrl}}/api/v1/users")
+	assert.Contains(t, string(create), `"name": "Example User"`)
+
+	get, err := os.ReadFile(filepath.Join(tempDir, "docs", "requests", "users", "get-user.bru"))
+	require.NoError(t, err)
+	assert.Contains(t, string(get), "url: {{baseUrl}}/api/v1/users/1")
+	assert.Contains(t, string(get), "body: none")
+
+	// A second handler reuses the collection scaffold
+	result, err = generator.Generate(context.Background(), GenerateOptions{
+		Type:      "handler",
+		Name:      "order",
+		OutputDir: tempDir,
+	})
+	require.NoError(t, err)
+	assert.NotContains(t, result.Files, "docs/requests/bruno.json")
+	assert.FileExists(t, filepath.Join(tempDir, "docs", "requests", "orders", "list-orders.bru"))
+}
+
+func TestGenerateHandler_PostmanCollection(t *testing.T) {
+	tempDir := t.TempDir()
+	generator := NewGenerator()
+
+	for _, name := range []string{"user", "order", "user"} {
+		_, err := generator.Generate(context.Background(), GenerateOptions{
+			Type:        "handler",
+			Name:        name,
+			OutputDir:   tempDir,
+			ProjectName: "myapi",
+			Collection:  CollectionPostman,
+		})
+		require.NoError(t, err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(tempDir, "docs", "requests", "myapi.postman_collection.json"))
+	require.NoError(t, err)
+
+	var collection struct {
+		Info struct {
+			Schema string `json:"schema"`
+		} `json:"info"`
+		Item []struct {
+			Name string `json:"name"`
+			Item []struct {
+				Name    string `json:"name"`
+				Request struct {
+					Method string `json:"method"`
+				} `json:"request"`
+			} `json:"item"`
+		} `json:"item"`
+	}
+	require.NoError(t, json.Unmarshal(data, &collection))
+	assert.Equal(t, postmanSchema, collection.Info.Schema)
+	require.Len(t, collection.Item, 2, "regenerating a handler should refresh its folder, not duplicate it")
+	assert.Equal(t, "User", collection.Item[0].Name)
+	assert.Equal(t, "Order", collection.Item[1].Name)
+	assert.Len(t, collection.Item[0].Item, 5)
+}
+
+func TestGenerateHandler_NoCollection(t *testing.T) {
+	tempDir := t.TempDir()
+
+	_, err := NewGenerator().Generate(context.Background(), GenerateOptions{
+		Type:       "handler",
+		Name:       "user",
+		OutputDir:  tempDir,
+		Collection: CollectionNone,
+	})
+	require.NoError(t, err)
+	assert.NoDirExists(t, filepath.Join(tempDir, "docs"))
+}
@@ -4,32 +4,49 @@ import (
 	"context"
 	"fmt"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/user/gogo/internal/events"
 	"github.com/user/gogo/internal/templates"
 	"github.com/user/gogo/internal/validate"
 )
 
 // GenerateOptions contains options for component generation
 type GenerateOptions struct {
-	Type        string // handler, model, service, migration, middleware, test
+	Type        string // handler, model, service, repository, migration, middleware, test, shared, notifier, storage, proto, resolver, job
 	Name        string
+	Variant     string // Named variant of the component type, e.g. idempotency for middleware
 	OutputDir   string
 	ProjectName string
 	ModuleName  string
-	Framework   string // gin, echo, chi
-	Database    string // gorm, sqlx, pgx
-	DryRun      bool
-	Force       bool
+	GoVersion   string
+	// Go version whose constructs the component may use, such as 1.21;
+	// GoVersion's when empty
+	LanguageLevel string
+	Framework     string // gin, echo, chi
+	Database      string // gorm, sqlx, pgx
+	Collection    string // Request collection for handlers: bruno (default), postman, none
+	DryRun        bool
+	Force         bool
 }
 
+// Frameworks and Databases list the values GenerateOptions.Framework and
+// GenerateOptions.Database accept
+var (
+	Frameworks = []string{"gin", "echo", "chi"}
+	Databases  = []string{"gorm", "sqlx", "pgx"}
+)
+
 // GenerateResult contains the result of a component generation
 type GenerateResult struct {
-	Success      bool
-	FilesCreated int
-	Message      string
-	Files        []string
+	Success      bool           `json:"success"`
+	FilesCreated int            `json:"files_created"`
+	Message      string         `json:"message"`
+	Files        []string       `json:"files"`
+	Variables    map[string]any `json:"-"` // values the templates were rendered with
 }
 
 // ComponentGenerator interface for generating components
@@ -41,6 +58,7 @@ type ComponentGenerator interface {
 // Generator implements ComponentGenerator
 type Generator struct {
 	templateEngine templates.TemplateRenderer
+	events         *events.Bus
 }
 
 // NewGenerator creates a new component generator
@@ -50,8 +68,18 @@ func NewGenerator() *Generator {
 	}
 }
 
+// SetEvents sets the bus ComponentGenerated is published on
+func (g *Generator) SetEvents(bus *events.Bus) {
+	g.events = bus
+}
+
 // Generate generates a component based on the options
 func (g *Generator) Generate(ctx context.Context, opts GenerateOptions) (GenerateResult, error) {
+	// Variants have fixed file names, so the name defaults to the variant
+	if opts.Name == "" && opts.Variant != "" {
+		opts.Name = opts.Variant
+	}
+
 	// Validate options
 	if err := g.validateOptions(opts); err != nil {
 		return GenerateResult{}, fmt.Errorf("invalid options: %w", err)
@@ -67,12 +95,30 @@ func (g *Generator) Generate(ctx context.Context, opts GenerateOptions) (Generat
 	if opts.Database == "" {
 		opts.Database = "gorm"
 	}
+	if opts.GoVersion == "" {
+		opts.GoVersion = "1.25.1"
+	}
+	if opts.LanguageLevel == "" {
+		opts.LanguageLevel = opts.GoVersion
+	}
 
 	// Get component templates
-	componentTemplates, err := g.getComponentTemplates(opts.Type)
+	componentTemplates, err := g.getComponentTemplates(templateKey(opts))
 	if err != nil {
 		return GenerateResult{}, fmt.Errorf("failed to get component templates: %w", err)
 	}
+	// Some files only apply to one database library
+	componentTemplates = slices.DeleteFunc(slices.Clone(componentTemplates), func(template ComponentTemplate) bool {
+		return len(template.Databases) > 0 && !slices.Contains(template.Databases, opts.Database)
+	})
+	// and some need a newer Go than the project's
+	componentTemplates = slices.DeleteFunc(componentTemplates, func(template ComponentTemplate) bool {
+		if template.LanguageLevel == "" {
+			return false
+		}
+		older, err := templates.CompareLanguageLevels(opts.LanguageLevel, template.LanguageLevel)
+		return err == nil && older < 0
+	})
 
 	// Prepare template variables
 	variables := g.prepareVariables(opts)
@@ -81,6 +127,7 @@ func (g *Generator) Generate(ctx context.Context, opts GenerateOptions) (Generat
 		Success:      true,
 		FilesCreated: len(componentTemplates),
 		Files:        make([]string, len(componentTemplates)),
+		Variables:    variables,
 	}
 
 	// Dry run - just validate and return
@@ -88,7 +135,7 @@ func (g *Generator) Generate(ctx context.Context, opts GenerateOptions) (Generat
 		for i, template := range componentTemplates {
 			renderedPath, err := g.templateEngine.RenderString(ctx, template.Path, variables)
 			if err != nil {
-				return GenerateResult{}, fmt.Errorf("failed to render path template: %w", err)
+				return GenerateResult{}, fmt.Errorf("failed to render path template for %s: %w", template.Name, templates.Named(err, template.Path))
 			}
 			result.Files[i] = renderedPath
 		}
@@ -101,7 +148,7 @@ func (g *Generator) Generate(ctx context.Context, opts GenerateOptions) (Generat
 		// Render the file path
 		renderedPath, err := g.templateEngine.RenderString(ctx, template.Path, variables)
 		if err != nil {
-			return GenerateResult{}, fmt.Errorf("failed to render path template: %w", err)
+			return GenerateResult{}, fmt.Errorf("failed to render path template for %s: %w", template.Name, templates.Named(err, template.Path))
 		}
 
 		outputPath := filepath.Join(opts.OutputDir, renderedPath)
@@ -110,11 +157,64 @@ func (g *Generator) Generate(ctx context.Context, opts GenerateOptions) (Generat
 		// Render and write the file
 		err = g.templateEngine.RenderToFile(ctx, template.Content, variables, outputPath)
 		if err != nil {
-			return GenerateResult{}, fmt.Errorf("failed to render component file %s: %w", template.Name, err)
+			return GenerateResult{}, fmt.Errorf("failed to render component file %s: %w", template.Name, templates.Named(err, template.Name))
+		}
+	}
+
+	// Keep the request collection in sync with the generated routes
+	if opts.Type == "handler" {
+		collectionFiles, err := g.generateCollection(opts, variables)
+		if err != nil {
+			return GenerateResult{}, fmt.Errorf("failed to generate request collection: %w", err)
+		}
+		result.Files = append(result.Files, collectionFiles...)
+		result.FilesCreated += len(collectionFiles)
+	}
+
+	// Build the service's code with make proto and serve it from RegisterServices
+	if opts.Type == "proto" {
+		grpcFiles, err := g.wireGRPCService(opts, variables)
+		if err != nil {
+			return GenerateResult{}, fmt.Errorf("failed to wire gRPC service: %w", err)
+		}
+		result.Files = append(result.Files, grpcFiles...)
+		result.FilesCreated += len(grpcFiles)
+	}
+
+	// Resolvers reach their store through the root Resolver
+	if opts.Type == "resolver" {
+		resolverFiles, err := g.wireResolver(opts, variables)
+		if err != nil {
+			return GenerateResult{}, fmt.Errorf("failed to wire resolver: %w", err)
+		}
+		result.Files = append(result.Files, resolverFiles...)
+		result.FilesCreated += len(resolverFiles)
+	}
+
+	// Jobs are consumed once Register hands them to the worker
+	if opts.Type == "job" {
+		jobFiles, err := g.wireJob(opts, variables)
+		if err != nil {
+			return GenerateResult{}, fmt.Errorf("failed to wire job: %w", err)
 		}
+		result.Files = append(result.Files, jobFiles...)
+		result.FilesCreated += len(jobFiles)
 	}
 
-	result.Message = fmt.Sprintf("Created %d files", len(componentTemplates))
+	g.events.Publish(ctx, events.Event{
+		Kind:    events.ComponentGenerated,
+		Subject: strings.TrimSuffix(opts.Type+"/"+opts.Name, "/"),
+		Dir:     opts.OutputDir,
+		Details: map[string]any{
+			"type":    opts.Type,
+			"name":    opts.Name,
+			"variant": opts.Variant,
+			"module":  opts.ModuleName,
+			"files":   result.Files,
+		},
+	})
+
+	result.Message = fmt.Sprintf("Created %d files", result.FilesCreated)
 	return result, nil
 }
 
@@ -124,12 +224,40 @@ func (g *Generator) GetSupportedTypes() []string {
 		"handler",
 		"model",
 		"service",
+		"repository",
 		"migration",
 		"middleware",
 		"test",
+		"shared",
+		"notifier",
+		"storage",
+		"proto",
+		"resolver",
+		"job",
 	}
 }
 
+// GetVariants returns the named variants available for a component type
+func (g *Generator) GetVariants(componentType string) []string {
+	var variants []string
+	prefix := componentType + "/"
+	for key := range getComponentTemplates() {
+		if strings.HasPrefix(key, prefix) {
+			variants = append(variants, strings.TrimPrefix(key, prefix))
+		}
+	}
+	sort.Strings(variants)
+	return variants
+}
+
+// templateKey returns the template set key for a component type and optional variant
+func templateKey(opts GenerateOptions) string {
+	if opts.Variant != "" {
+		return opts.Type + "/" + opts.Variant
+	}
+	return opts.Type
+}
+
 // validateOptions validates the generation options
 func (g *Generator) validateOptions(opts GenerateOptions) error {
 	if opts.Type == "" {
@@ -153,6 +281,34 @@ func (g *Generator) validateOptions(opts GenerateOptions) error {
 		return fmt.Errorf("unsupported component type '%s', supported types: %s", opts.Type, strings.Join(supportedTypes, ", "))
 	}
 
+	// Validate variant
+	if opts.Variant != "" {
+		variants := g.GetVariants(opts.Type)
+		validVariant := false
+		for _, v := range variants {
+			if v == opts.Variant {
+				validVariant = true
+				break
+			}
+		}
+		if !validVariant {
+			return fmt.Errorf("unsupported %s variant '%s', supported variants: %s", opts.Type, opts.Variant, strings.Join(variants, ", "))
+		}
+	}
+
+	if opts.Framework != "" && !slices.Contains(Frameworks, opts.Framework) {
+		return fmt.Errorf("unsupported framework '%s', supported frameworks: %s", opts.Framework, strings.Join(Frameworks, ", "))
+	}
+	if opts.Database != "" && !slices.Contains(Databases, opts.Database) {
+		return fmt.Errorf("unsupported database '%s', supported databases: %s", opts.Database, strings.Join(Databases, ", "))
+	}
+
+	if opts.LanguageLevel != "" {
+		if _, err := templates.ParseLanguageLevel(opts.LanguageLevel); err != nil {
+			return err
+		}
+	}
+
 	// Validate component name
 	if err := validate.ValidateProjectName(opts.Name); err != nil {
 		return fmt.Errorf("invalid component name: %w", err)
@@ -180,10 +336,16 @@ func (g *Generator) prepareVariables(opts GenerateOptions) map[string]any {
 		"ModuleName":  opts.ModuleName,
 		"Framework":   opts.Framework,
 		"Database":    opts.Database,
+		"GoVersion":   opts.GoVersion,
 		"Timestamp":   time.Now().Format("20060102150405"),
 		"Year":        time.Now().Year(),
 	}
 
+	// Add the constructs the language level has
+	for name, value := range templates.LanguageVariables(opts.LanguageLevel) {
+		variables[name] = value
+	}
+
 	// Add framework-specific variables
 	variables["IsGin"] = opts.Framework == "gin"
 	variables["IsEcho"] = opts.Framework == "echo"
@@ -193,6 +355,11 @@ func (g *Generator) prepareVariables(opts GenerateOptions) map[string]any {
 	variables["IsGorm"] = opts.Database == "gorm"
 	variables["IsSqlx"] = opts.Database == "sqlx"
 	variables["IsPgx"] = opts.Database == "pgx"
+	// GORM models embed uint IDs; the others scan an int64 primary key
+	variables["IDType"] = "int64"
+	if opts.Database == "gorm" {
+		variables["IDType"] = "uint"
+	}
 
 	return variables
 }
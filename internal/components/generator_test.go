@@ -4,10 +4,12 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/user/gogo/internal/events"
 )
 
 func TestComponentGenerator_GenerateHandler(t *testing.T) {
@@ -83,6 +85,59 @@ func TestComponentGenerator_GenerateHandler(t *testing.T) {
 			},
 			expectError: false,
 		},
+		{
+			name: "generate notifier",
+			opts: GenerateOptions{
+				Type:        "notifier",
+				Name:        "alerts",
+				OutputDir:   tempDir,
+				ProjectName: "myapi",
+				ModuleName:  "github.com/user/myapi",
+			},
+			expectFiles: []string{
+				"internal/alerts/notifier.go",
+				"internal/alerts/smtp.go",
+				"internal/alerts/webhook.go",
+				"internal/alerts/notifier_test.go",
+			},
+			expectError: false,
+		},
+		{
+			name: "generate storage",
+			opts: GenerateOptions{
+				Type:        "storage",
+				Name:        "blobs",
+				OutputDir:   tempDir,
+				ProjectName: "myapi",
+				ModuleName:  "github.com/user/myapi",
+			},
+			expectFiles: []string{
+				"internal/blobs/storage.go",
+				"internal/blobs/local.go",
+				"internal/blobs/s3.go",
+				"internal/blobs/local_test.go",
+				"internal/blobs/s3_integration_test.go",
+			},
+			expectError: false,
+		},
+		{
+			name: "generate idempotency middleware variant",
+			opts: GenerateOptions{
+				Type:        "middleware",
+				Variant:     "idempotency",
+				OutputDir:   tempDir,
+				ProjectName: "myapi",
+				ModuleName:  "github.com/user/myapi",
+				Framework:   "chi",
+			},
+			expectFiles: []string{
+				"internal/middleware/idempotency.go",
+				"internal/middleware/idempotency_chi.go",
+				"internal/middleware/idempotency_redis.go",
+				"internal/middleware/idempotency_test.go",
+			},
+			expectError: false,
+		},
 		{
 			name: "invalid component type",
 			opts: GenerateOptions{
@@ -184,6 +239,24 @@ func TestComponentGenerator_ValidateOptions(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "unsupported framework",
+			opts: GenerateOptions{
+				Type:      "handler",
+				Name:      "user",
+				Framework: "fiber",
+			},
+			wantErr: true,
+		},
+		{
+			name: "unsupported database",
+			opts: GenerateOptions{
+				Type:     "model",
+				Name:     "user",
+				Database: "ent",
+			},
+			wantErr: true,
+		},
 		{
 			name: "empty output dir defaults to current",
 			opts: GenerateOptions{
@@ -221,6 +294,17 @@ func TestComponentGenerator_GetSupportedTypes(t *testing.T) {
 	}
 }
 
+func TestComponentGenerator_GetVariants(t *testing.T) {
+	generator := NewGenerator()
+
+	assert.Contains(t, generator.GetVariants("middleware"), "idempotency")
+	assert.Contains(t, generator.GetVariants("middleware"), "audit")
+	assert.Empty(t, generator.GetVariants("model"))
+
+	err := generator.validateOptions(GenerateOptions{Type: "middleware", Name: "x", Variant: "unknown"})
+	assert.Error(t, err)
+}
+
 func TestComponentGenerator_DryRun(t *testing.T) {
 	tempDir := t.TempDir()
 	generator := NewGenerator()
@@ -245,3 +329,310 @@ func TestComponentGenerator_DryRun(t *testing.T) {
 	require.NoError(t, err)
 	assert.Empty(t, entries, "no files should be created in dry run")
 }
+
+func TestComponentGenerator_DatabaseRepositories(t *testing.T) {
+	generator := NewGenerator()
+	ctx := context.Background()
+
+	for database, want := range map[string][]string{
+		"gorm": {"func NewOrderItemRepository(db *gorm.DB) *GormOrderItemRepository", "clause.Locking", "gorm.ErrRecordNotFound"},
+		"sqlx": {"PreparexContext", "PrepareNamedContext", "BeginTxx", "sql.ErrNoRows"},
+		"pgx":  {"pgx.BeginFunc", "func scanOrderItem(row pgx.Row)", "pgx.ErrNoRows"},
+	} {
+		dir := t.TempDir()
+		implementation := "internal/repository/order_item_repository_" + database + ".go"
+		result, err := generator.Generate(ctx, GenerateOptions{
+			Type:       "repository",
+			Name:       "order_item",
+			OutputDir:  dir,
+			ModuleName: "github.com/user/shop",
+			Database:   database,
+		})
+		require.NoError(t, err, database)
+		assert.Equal(t, []string{
+			"internal/repository/order_item_repository.go",
+			implementation,
+			"internal/repository/order_item_repository_mock.go",
+			"internal/repository/order_item_repository_test.go",
+		}, result.Files, database)
+
+		repository, err := os.ReadFile(filepath.Join(dir, implementation))
+		require.NoError(t, err, database)
+		for _, s := range want {
+			assert.Contains(t, string(repository), s, database)
+		}
+		assert.Contains(t, string(repository), "WithTx(ctx context.Context, fn func(tx OrderItemRepository) error) error", database)
+
+		mock, err := os.ReadFile(filepath.Join(dir, "internal", "repository", "order_item_repository_mock.go"))
+		require.NoError(t, err, database)
+		assert.Contains(t, string(mock), "var _ OrderItemRepository = (*MockOrderItemRepository)(nil)", database)
+	}
+
+	// Services call the repository generated with them, and test on its mock
+	for _, database := range Databases {
+		dir := t.TempDir()
+		result, err := generator.Generate(ctx, GenerateOptions{
+			Type:       "service",
+			Name:       "order_item",
+			OutputDir:  dir,
+			ModuleName: "github.com/user/shop",
+			Database:   database,
+		})
+		require.NoError(t, err, database)
+		assert.Contains(t, result.Files, "internal/repository/order_item_repository_"+database+".go", database)
+
+		service, err := os.ReadFile(filepath.Join(dir, "internal", "services", "order_item_service.go"))
+		require.NoError(t, err, database)
+		assert.Contains(t, string(service), "func NewOrderItemService(repo repository.OrderItemRepository) OrderItemService", database)
+		assert.NotContains(t, string(service), "not implemented", database)
+
+		serviceTest, err := os.ReadFile(filepath.Join(dir, "internal", "services", "order_item_service_test.go"))
+		require.NoError(t, err, database)
+		assert.Contains(t, string(serviceTest), "NewOrderItemService(repository.NewMockOrderItemRepository())", database)
+	}
+}
+
+func TestComponentGenerator_Proto(t *testing.T) {
+	generator := NewGenerator()
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	serverFile := filepath.Join(dir, filepath.FromSlash(GRPCServerFile))
+	require.NoError(t, os.MkdirAll(filepath.Dir(serverFile), 0755))
+	require.NoError(t, os.WriteFile(serverFile, []byte("package server\n\nimport \"google.golang.org/grpc\"\n\nfunc RegisterServices(srv *grpc.Server) {\n}\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Makefile"), []byte("build:\n\tgo build ./..."), 0644))
+
+	opts := GenerateOptions{
+		Type:       "proto",
+		Name:       "order_item",
+		OutputDir:  dir,
+		ModuleName: "github.com/user/shop",
+	}
+	result, err := generator.Generate(ctx, opts)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"proto/order_item/v1/order_item.proto",
+		"internal/server/order_item_server.go",
+		"internal/server/order_item_server_test.go",
+		"buf.yaml",
+		"buf.gen.yaml",
+		"Makefile",
+		GRPCServerFile,
+	}, result.Files)
+
+	proto, err := os.ReadFile(filepath.Join(dir, "proto", "order_item", "v1", "order_item.proto"))
+	require.NoError(t, err)
+	assert.Contains(t, string(proto), `option go_package = "github.com/user/shop/proto/order_item/v1;orderitemv1";`)
+	assert.Contains(t, string(proto), "service OrderItemService {")
+
+	// A second service is registered after the first; regenerating adds nothing
+	opts.Name = "payment"
+	_, err = generator.Generate(ctx, opts)
+	require.NoError(t, err)
+	result, err = generator.Generate(ctx, opts)
+	require.NoError(t, err)
+	assert.NotContains(t, result.Files, "Makefile")
+	assert.NotContains(t, result.Files, GRPCServerFile)
+
+	server, err := os.ReadFile(serverFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(server), "func RegisterServices(srv *grpc.Server) {\n\tRegisterOrderItemServer(srv)\n\tRegisterPaymentServer(srv)\n}\n")
+
+	makefile, err := os.ReadFile(filepath.Join(dir, "Makefile"))
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(makefile), "build:\n\tgo build ./...\n\n# Generates"))
+	assert.Equal(t, 1, strings.Count(string(makefile), "\nproto:\n"))
+
+	// Projects without RegisterServices register the service by hand
+	result, err = generator.Generate(ctx, GenerateOptions{Type: "proto", Name: "payment", OutputDir: t.TempDir()})
+	require.NoError(t, err)
+	assert.NotContains(t, result.Files, GRPCServerFile)
+}
+
+func TestComponentGenerator_Stage(t *testing.T) {
+	generator := NewGenerator()
+	dir := t.TempDir()
+
+	serverFile := filepath.Join(dir, filepath.FromSlash(GRPCServerFile))
+	server := "package server\n\nimport \"google.golang.org/grpc\"\n\nfunc RegisterServices(srv *grpc.Server) {\n}\n"
+	require.NoError(t, os.MkdirAll(filepath.Dir(serverFile), 0755))
+	require.NoError(t, os.WriteFile(serverFile, []byte(server), 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".git"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".git", "HEAD"), []byte("ref: refs/heads/main\n"), 0644))
+
+	staged, result, err := generator.Stage(context.Background(), GenerateOptions{Type: "proto", Name: "payment", OutputDir: dir})
+	require.NoError(t, err)
+	defer os.RemoveAll(staged)
+	assert.Contains(t, result.Files, GRPCServerFile)
+
+	// The copy is wired; the project is left as it was
+	wired, err := os.ReadFile(filepath.Join(staged, filepath.FromSlash(GRPCServerFile)))
+	require.NoError(t, err)
+	assert.Contains(t, string(wired), "\tRegisterPaymentServer(srv)\n")
+	content, err := os.ReadFile(serverFile)
+	require.NoError(t, err)
+	assert.Equal(t, server, string(content))
+	assert.NoFileExists(t, filepath.Join(dir, "buf.yaml"))
+	assert.NoDirExists(t, filepath.Join(staged, ".git"))
+}
+
+func TestComponentGenerator_PublishesComponentGenerated(t *testing.T) {
+	generator := NewGenerator()
+	bus := events.NewBus()
+	var published []events.Event
+	bus.Subscribe(func(ctx context.Context, event events.Event) error {
+		published = append(published, event)
+		return nil
+	}, events.ComponentGenerated)
+	generator.SetEvents(bus)
+
+	ctx := context.Background()
+	dir := t.TempDir()
+	opts := GenerateOptions{Type: "model", Name: "order", OutputDir: dir, ModuleName: "github.com/user/shop"}
+
+	// Neither dry runs nor staged previews change the project
+	preview := opts
+	preview.DryRun = true
+	_, err := generator.Generate(ctx, preview)
+	require.NoError(t, err)
+	staged, _, err := generator.Stage(ctx, opts)
+	require.NoError(t, err)
+	defer os.RemoveAll(staged)
+	assert.Empty(t, published)
+
+	result, err := generator.Generate(ctx, opts)
+	require.NoError(t, err)
+	require.Len(t, published, 1)
+	assert.Equal(t, "model/order", published[0].Subject)
+	assert.Equal(t, dir, published[0].Dir)
+	assert.Equal(t, result.Files, published[0].Details["files"])
+}
+
+func TestComponentGenerator_Resolver(t *testing.T) {
+	generator := NewGenerator()
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	resolverFile := filepath.Join(dir, filepath.FromSlash(GraphQLResolverFile))
+	require.NoError(t, os.MkdirAll(filepath.Dir(resolverFile), 0755))
+	require.NoError(t, os.WriteFile(resolverFile, []byte("package graph\n\ntype Resolver struct{}\n"), 0644))
+
+	opts := GenerateOptions{
+		Type:       "resolver",
+		Name:       "order_item",
+		OutputDir:  dir,
+		ModuleName: "github.com/user/shop",
+	}
+	result, err := generator.Generate(ctx, opts)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"graph/order_item.graphqls",
+		"graph/order_item_store.go",
+		"graph/order_item.resolvers.go",
+		GraphQLResolverFile,
+	}, result.Files)
+
+	schema, err := os.ReadFile(filepath.Join(dir, "graph", "order_item.graphqls"))
+	require.NoError(t, err)
+	assert.Contains(t, string(schema), "  orderItems: [OrderItem!]!\n")
+	assert.Contains(t, string(schema), "  createOrderItem(input: NewOrderItem!): OrderItem!\n")
+
+	resolvers, err := os.ReadFile(filepath.Join(dir, "graph", "order_item.resolvers.go"))
+	require.NoError(t, err)
+	assert.Contains(t, string(resolvers), `"github.com/user/shop/graph/model"`)
+	assert.Contains(t, string(resolvers), "return r.orderItemStore.List(), nil")
+
+	// A second store is added after the first; regenerating adds nothing
+	opts.Name = "payment"
+	_, err = generator.Generate(ctx, opts)
+	require.NoError(t, err)
+	result, err = generator.Generate(ctx, opts)
+	require.NoError(t, err)
+	assert.NotContains(t, result.Files, GraphQLResolverFile)
+
+	resolver, err := os.ReadFile(resolverFile)
+	require.NoError(t, err)
+	assert.Equal(t, "package graph\n\ntype Resolver struct {\n\torderItemStore OrderItemStore\n\tpaymentStore   PaymentStore\n}\n", string(resolver))
+
+	// Projects without a Resolver wire the store by hand
+	result, err = generator.Generate(ctx, GenerateOptions{Type: "resolver", Name: "payment", OutputDir: t.TempDir()})
+	require.NoError(t, err)
+	assert.NotContains(t, result.Files, GraphQLResolverFile)
+}
+
+func TestComponentGenerator_Job(t *testing.T) {
+	generator := NewGenerator()
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	registerFile := filepath.Join(dir, filepath.FromSlash(JobsRegisterFile))
+	require.NoError(t, os.MkdirAll(filepath.Dir(registerFile), 0755))
+	require.NoError(t, os.WriteFile(registerFile, []byte("package jobs\n\nfunc Register(mux *worker.Mux) {\n}\n"), 0644))
+
+	opts := GenerateOptions{
+		Type:       "job",
+		Name:       "send_email",
+		OutputDir:  dir,
+		ModuleName: "github.com/user/mailer",
+	}
+	result, err := generator.Generate(ctx, opts)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"internal/jobs/send_email.go",
+		"internal/jobs/send_email_test.go",
+		JobsRegisterFile,
+	}, result.Files)
+
+	job, err := os.ReadFile(filepath.Join(dir, "internal", "jobs", "send_email.go"))
+	require.NoError(t, err)
+	assert.Contains(t, string(job), `const SendEmailJobType = "send-email"`)
+	assert.Contains(t, string(job), "func HandleSendEmail(ctx context.Context, payload []byte) error {")
+	assert.Contains(t, string(job), `"github.com/user/mailer/internal/worker"`)
+
+	// A second job is registered after the first; regenerating adds nothing
+	opts.Name = "resize_image"
+	_, err = generator.Generate(ctx, opts)
+	require.NoError(t, err)
+	result, err = generator.Generate(ctx, opts)
+	require.NoError(t, err)
+	assert.NotContains(t, result.Files, JobsRegisterFile)
+
+	register, err := os.ReadFile(registerFile)
+	require.NoError(t, err)
+	assert.Equal(t, "package jobs\n\nfunc Register(mux *worker.Mux) {\n\tmux.Handle(SendEmailJobType, HandleSendEmail)\n\tmux.Handle(ResizeImageJobType, HandleResizeImage)\n}\n", string(register))
+
+	// Projects without Register wire the job by hand
+	result, err = generator.Generate(ctx, GenerateOptions{Type: "job", Name: "send_email", OutputDir: t.TempDir()})
+	require.NoError(t, err)
+	assert.NotContains(t, result.Files, JobsRegisterFile)
+}
+
+func TestComponentGenerator_LanguageLevel(t *testing.T) {
+	generator := NewGenerator()
+	ctx := context.Background()
+
+	opts := GenerateOptions{
+		Type:          "job",
+		Name:          "send_email",
+		OutputDir:     t.TempDir(),
+		ModuleName:    "github.com/user/mailer",
+		LanguageLevel: "1.20",
+	}
+	_, err := generator.Generate(ctx, opts)
+	require.NoError(t, err)
+	job, err := os.ReadFile(filepath.Join(opts.OutputDir, "internal", "jobs", "send_email.go"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(job), "slog")
+	assert.Contains(t, string(job), `log.Printf("processed send-email job %s", p.ID)`)
+
+	// Files needing a newer Go than the language level are left out
+	result, err := generator.Generate(ctx, GenerateOptions{Type: "shared", Name: "platform", OutputDir: t.TempDir(), DryRun: true, LanguageLevel: "1.20"})
+	require.NoError(t, err)
+	assert.NotContains(t, result.Files, "pkg/platform/logging/logging.go")
+	result, err = generator.Generate(ctx, GenerateOptions{Type: "shared", Name: "platform", OutputDir: t.TempDir(), DryRun: true, GoVersion: "1.21"})
+	require.NoError(t, err)
+	assert.Contains(t, result.Files, "pkg/platform/logging/logging.go")
+
+	_, err = generator.Generate(ctx, GenerateOptions{Type: "job", Name: "send_email", OutputDir: t.TempDir(), LanguageLevel: "latest"})
+	assert.ErrorContains(t, err, "invalid language level")
+}
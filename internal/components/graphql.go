@@ -0,0 +1,73 @@
+package components
+
+import (
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// GraphQLResolverFile holds the root Resolver of the graphql stack, which
+// the resolvers of every type hang off
+const GraphQLResolverFile = "graph/resolver.go"
+
+// resolverStruct finds the declaration of the root Resolver, in either its
+// empty or its multi-line form
+var resolverStruct = regexp.MustCompile(`(?m)^type Resolver struct\s*\{(\s*\}|[^\n]*\n)`)
+
+// wireResolver gives the root Resolver the store of a resolver component,
+// which its resolvers reach through r. It returns the files changed.
+func (g *Generator) wireResolver(opts GenerateOptions, variables map[string]any) ([]string, error) {
+	name := variables["CamelName"].(string) + "Store"
+	fieldType := variables["TitleName"].(string) + "Store"
+	changed, err := addResolverField(filepath.Join(opts.OutputDir, filepath.FromSlash(GraphQLResolverFile)), name, fieldType)
+	if err != nil {
+		return nil, err
+	}
+	if changed {
+		return []string{GraphQLResolverFile}, nil
+	}
+	return nil, nil
+}
+
+// addResolverField adds a field of the given name and type at the end of the
+// Resolver struct. Projects without the struct are left alone; the field is
+// then added by hand.
+func addResolverField(path, name, fieldType string) (bool, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	match := resolverStruct.FindSubmatchIndex(data)
+	if match == nil || regexp.MustCompile(`(?m)^\s*`+regexp.QuoteMeta(name)+`\s`).Match(data) {
+		return false, nil
+	}
+	field := name + " " + fieldType
+
+	var content string
+	if strings.TrimSpace(string(data[match[2]:match[3]])) == "}" {
+		// type Resolver struct{} has no body to add to yet
+		content = string(data[:match[0]]) + "type Resolver struct {\n\t" + field + "\n}" + string(data[match[1]:])
+	} else {
+		end := closingBrace.FindIndex(data[match[1]:])
+		if end == nil {
+			return false, nil
+		}
+		at := match[1] + end[0]
+		content = string(data[:at]) + "\t" + field + "\n" + string(data[at:])
+	}
+	// Align the fields as gofmt would; a file that does not parse is left as is
+	if formatted, err := format.Source([]byte(content)); err == nil {
+		content = string(formatted)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return false, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return true, nil
+}
@@ -0,0 +1,136 @@
+package components
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// GRPCServerFile holds RegisterServices, which the grpc templates call
+// from main.go
+const GRPCServerFile = "internal/server/server.go"
+
+// registerServicesFunc finds the opening line of RegisterServices, and
+// closingBrace the end of a gofmt'ed top-level function
+var (
+	registerServicesFunc = regexp.MustCompile(`(?m)^func RegisterServices\(\s*(\w+)\s+[^)]*\)\s*\{[^\n]*\n`)
+	closingBrace         = regexp.MustCompile(`(?m)^\}`)
+)
+
+// protoTarget generates Go code from the project's .proto files, with buf
+// when it is installed and protoc otherwise
+const protoTarget = `# Generates Go code next to the .proto files under proto/ with buf, or with
+# protoc, protoc-gen-go and protoc-gen-go-grpc when buf is not installed
+.PHONY: proto
+proto:
+	@if command -v buf >/dev/null 2>&1; then \
+		buf generate; \
+	else \
+		protoc -I proto --go_out=proto --go_opt=paths=source_relative \
+			--go-grpc_out=proto --go-grpc_opt=paths=source_relative \
+			$$(find proto -name '*.proto'); \
+	fi
+`
+
+// bufFiles configure buf generate to match the protoc command of protoTarget
+var bufFiles = map[string]string{
+	"buf.yaml": "version: v2\nmodules:\n  - path: proto\n",
+	"buf.gen.yaml": `version: v2
+plugins:
+  - local: protoc-gen-go
+    out: proto
+    opt: paths=source_relative
+  - local: protoc-gen-go-grpc
+    out: proto
+    opt: paths=source_relative
+`,
+}
+
+// wireGRPCService sets a project up to build the service of a proto
+// component: buf configuration and a make proto target on first use, and a
+// call to its Register function in RegisterServices. It returns the files
+// written or changed.
+func (g *Generator) wireGRPCService(opts GenerateOptions, variables map[string]any) ([]string, error) {
+	var files []string
+	for _, name := range []string{"buf.yaml", "buf.gen.yaml"} {
+		path := filepath.Join(opts.OutputDir, name)
+		if _, err := os.Stat(path); err == nil {
+			continue
+		}
+		if err := os.WriteFile(path, []byte(bufFiles[name]), 0644); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", name, err)
+		}
+		files = append(files, name)
+	}
+
+	changed, err := addMakeTarget(filepath.Join(opts.OutputDir, "Makefile"), "proto", protoTarget)
+	if err != nil {
+		return nil, err
+	}
+	if changed {
+		files = append(files, "Makefile")
+	}
+
+	register := "Register" + variables["TitleName"].(string) + "Server"
+	changed, err = addServiceRegistration(filepath.Join(opts.OutputDir, filepath.FromSlash(GRPCServerFile)), register)
+	if err != nil {
+		return nil, err
+	}
+	if changed {
+		files = append(files, GRPCServerFile)
+	}
+	return files, nil
+}
+
+// addMakeTarget appends target to a Makefile unless it already has a rule
+// of that name, creating the Makefile if needed
+func addMakeTarget(path, name, target string) (bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return false, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if regexp.MustCompile(`(?m)^` + regexp.QuoteMeta(name) + `\s*:`).Match(data) {
+		return false, nil
+	}
+
+	content := string(data)
+	if content != "" {
+		content = strings.TrimRight(content, "\n") + "\n\n"
+	}
+	if err := os.WriteFile(path, []byte(content+target), 0644); err != nil {
+		return false, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return true, nil
+}
+
+// addServiceRegistration adds a call to register at the end of
+// RegisterServices. Projects without the function are left alone; the
+// service is then registered by hand.
+func addServiceRegistration(path, register string) (bool, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	match := registerServicesFunc.FindSubmatchIndex(data)
+	if match == nil || strings.Contains(string(data), register+"(") {
+		return false, nil
+	}
+	end := closingBrace.FindIndex(data[match[1]:])
+	if end == nil {
+		return false, nil
+	}
+	at := match[1] + end[0]
+	server := string(data[match[2]:match[3]])
+	call := fmt.Sprintf("\t%s(%s)\n", register, server)
+	content := string(data[:at]) + call + string(data[at:])
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return false, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return true, nil
+}
@@ -0,0 +1,82 @@
+package components
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Stage generates a component into a scratch copy of opts.OutputDir, so the
+// files it would write or change can be reviewed first; opts.OutputDir is
+// left alone. It returns the copy, which the caller removes, and the result
+// of generating into it.
+func (g *Generator) Stage(ctx context.Context, opts GenerateOptions) (string, GenerateResult, error) {
+	if opts.OutputDir == "" {
+		opts.OutputDir = "."
+	}
+	scratch, err := os.MkdirTemp("", "gogo-stage-")
+	if err != nil {
+		return "", GenerateResult{}, fmt.Errorf("failed to create staging directory: %w", err)
+	}
+
+	// Wiring edits existing files such as the Makefile, so the generator
+	// needs the project as it is
+	if err := copyProject(opts.OutputDir, scratch); err != nil {
+		os.RemoveAll(scratch)
+		return "", GenerateResult{}, err
+	}
+
+	// Nothing happens to the project, so nothing is published
+	quiet := *g
+	quiet.events = nil
+	staged := opts
+	staged.OutputDir = scratch
+	staged.DryRun = false
+	result, err := quiet.Generate(ctx, staged)
+	if err != nil {
+		os.RemoveAll(scratch)
+		return "", GenerateResult{}, err
+	}
+	return scratch, result, nil
+}
+
+// copyProject copies the regular files under src to dst, leaving out .git
+func copyProject(src, dst string) error {
+	err := filepath.WalkDir(src, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if entry.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		return os.WriteFile(target, data, info.Mode().Perm())
+	})
+	if err != nil {
+		return fmt.Errorf("failed to copy %s for staging: %w", src, err)
+	}
+	return nil
+}
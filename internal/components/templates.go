@@ -2,9 +2,18 @@ package components
 
 // ComponentTemplate represents a template for generating components
 type ComponentTemplate struct {
-	Name    string
-	Path    string
-	Content string
+	Name      string
+	Path      string
+	Content   string
+	Databases []string // Database libraries the file is generated for; empty for all
+	// Oldest language level the file compiles with, such as 1.21; any when empty
+	LanguageLevel string
+}
+
+// Templates returns every component template, keyed by component type or
+// type/variant
+func Templates() map[string][]ComponentTemplate {
+	return getComponentTemplates()
 }
 
 // getComponentTemplates returns all component templates organized by type
@@ -211,7 +220,7 @@ type {{ TitleName }} struct {
 	UpdatedAt time.Time      ` + "`json:\"updated_at\"`" + `
 	DeletedAt gorm.DeletedAt ` + "`gorm:\"index\" json:\"deleted_at,omitempty\"`" + `
 {% else %}
-	ID        string    ` + "`json:\"id\" db:\"id\"`" + `
+	ID        int64     ` + "`json:\"id\" db:\"id\"`" + `
 	CreatedAt time.Time ` + "`json:\"created_at\" db:\"created_at\"`" + `
 	UpdatedAt time.Time ` + "`json:\"updated_at\" db:\"updated_at\"`" + `
 {% endif %}
@@ -287,276 +296,3530 @@ func TestCreate{{ TitleName }}Request_Validation(t *testing.T) {
 	
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			// Add validation logic here
-			assert.NotEmpty(t, tt.request.Name == "" && !tt.valid)
+			// Replace with your validation logic
+			assert.Equal(t, tt.valid, tt.request.Name != "")
 		})
 	}
 }`,
 		},
 	}
 
-	// Service templates
-	templates["service"] = []ComponentTemplate{
+	// Repository templates: an interface for the entity's data layer, its
+	// implementation on the database library, an in-memory mock and tests
+	templates["repository"] = []ComponentTemplate{
 		{
-			Name: "service",
-			Path: "internal/services/{{ SnakeName }}_service.go",
-			Content: `package services
+			Name: "repository",
+			Path: "internal/repository/{{ SnakeName }}_repository.go",
+			Content: `package repository
+
+import (
+	"context"
+	"errors"
+{% if ModuleName %}
+
+	"{{ ModuleName }}/internal/models"
+{% endif %}
+)
+
+// Err{{ TitleName }}NotFound is returned when no {{ TitleName }} has the requested ID
+var Err{{ TitleName }}NotFound = errors.New("{{ TitleName }} not found")
+
+// {{ TitleName }}Repository stores {{ TitleName }}s. New{{ TitleName }}Repository returns
+// the {{ Database }} implementation; Mock{{ TitleName }}Repository keeps them in memory
+// for tests.
+type {{ TitleName }}Repository interface {
+	// List returns the {{ TitleName }}s that are not deleted, oldest first
+	List(ctx context.Context) ([]*models.{{ TitleName }}, error)
+	// Get returns the {{ TitleName }} with the given ID
+	Get(ctx context.Context, id {{ IDType }}) (*models.{{ TitleName }}, error)
+	// GetForUpdate returns the {{ TitleName }} with the given ID and locks it
+	// until the transaction ends; use it inside WithTx
+	GetForUpdate(ctx context.Context, id {{ IDType }}) (*models.{{ TitleName }}, error)
+	// Create inserts a {{ TitleName }}, filling in its ID and timestamps
+	Create(ctx context.Context, {{ CamelName }} *models.{{ TitleName }}) error
+	// Update saves the fields of a {{ TitleName }}, refreshing its timestamps
+	Update(ctx context.Context, {{ CamelName }} *models.{{ TitleName }}) error
+	// Delete soft-deletes the {{ TitleName }} with the given ID
+	Delete(ctx context.Context, id {{ IDType }}) error
+	// WithTx runs fn with a repository whose operations run in one
+	// transaction, committed when fn returns nil and rolled back otherwise
+	WithTx(ctx context.Context, fn func(tx {{ TitleName }}Repository) error) error
+}`,
+		},
+		{
+			Name:      "repository_gorm",
+			Path:      "internal/repository/{{ SnakeName }}_repository_{{ Database }}.go",
+			Databases: []string{"gorm"},
+			Content: `package repository
 
 import (
+	"context"
+	"errors"
 	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 {% if ModuleName %}
+
 	"{{ ModuleName }}/internal/models"
 {% endif %}
 )
 
-// {{ TitleName }}Service defines the interface for {{ TitleName }} operations
-type {{ TitleName }}Service interface {
-	GetAll() ([]*models.{{ TitleName }}, error)
-	GetByID(id string) (*models.{{ TitleName }}, error)
-	Create(req *models.Create{{ TitleName }}Request) (*models.{{ TitleName }}, error)
-	Update(id string, req *models.Update{{ TitleName }}Request) (*models.{{ TitleName }}, error)
-	Delete(id string) error
+var _ {{ TitleName }}Repository = (*Gorm{{ TitleName }}Repository)(nil)
+
+// Gorm{{ TitleName }}Repository implements {{ TitleName }}Repository with GORM. Deletes
+// are soft through the model's DeletedAt, which GORM also filters on.
+type Gorm{{ TitleName }}Repository struct {
+	db *gorm.DB
 }
 
-// {{ CamelName }}Service implements {{ TitleName }}Service
-type {{ CamelName }}Service struct {
-	// Add your dependencies here (repository, database, etc.)
+// New{{ TitleName }}Repository creates a repository on db
+func New{{ TitleName }}Repository(db *gorm.DB) *Gorm{{ TitleName }}Repository {
+	return &Gorm{{ TitleName }}Repository{db: db}
 }
 
-// New{{ TitleName }}Service creates a new {{ TitleName }} service
-func New{{ TitleName }}Service() {{ TitleName }}Service {
-	return &{{ CamelName }}Service{}
+// WithTx runs fn with a repository whose queries run in one transaction,
+// committed when fn returns nil and rolled back otherwise. Called inside
+// fn, it runs a nested transaction on a savepoint.
+func (r *Gorm{{ TitleName }}Repository) WithTx(ctx context.Context, fn func(tx {{ TitleName }}Repository) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&Gorm{{ TitleName }}Repository{db: tx})
+	})
 }
 
-// GetAll retrieves all {{ TitleName }}s
-func (s *{{ CamelName }}Service) GetAll() ([]*models.{{ TitleName }}, error) {
-	// TODO: Implement GetAll logic
-	return nil, fmt.Errorf("not implemented")
+// List returns the {{ TitleName }}s that are not deleted, oldest first
+func (r *Gorm{{ TitleName }}Repository) List(ctx context.Context) ([]*models.{{ TitleName }}, error) {
+	{{ CamelName }}s := []*models.{{ TitleName }}{}
+	if err := r.db.WithContext(ctx).Order("id").Find(&{{ CamelName }}s).Error; err != nil {
+		return nil, {{ CamelName }}Error("list", err)
+	}
+	return {{ CamelName }}s, nil
 }
 
-// GetByID retrieves a {{ TitleName }} by ID
-func (s *{{ CamelName }}Service) GetByID(id string) (*models.{{ TitleName }}, error) {
-	// TODO: Implement GetByID logic
-	return nil, fmt.Errorf("not implemented")
+// Get returns the {{ TitleName }} with the given ID
+func (r *Gorm{{ TitleName }}Repository) Get(ctx context.Context, id uint) (*models.{{ TitleName }}, error) {
+	return r.get{{ TitleName }}(r.db.WithContext(ctx), id)
 }
 
-// Create creates a new {{ TitleName }}
-func (s *{{ CamelName }}Service) Create(req *models.Create{{ TitleName }}Request) (*models.{{ TitleName }}, error) {
-	// TODO: Implement Create logic
-	return nil, fmt.Errorf("not implemented")
+// GetForUpdate returns the {{ TitleName }} with the given ID and locks its row
+// until the transaction ends; use it inside WithTx
+func (r *Gorm{{ TitleName }}Repository) GetForUpdate(ctx context.Context, id uint) (*models.{{ TitleName }}, error) {
+	return r.get{{ TitleName }}(r.db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}), id)
 }
 
-// Update updates an existing {{ TitleName }}
-func (s *{{ CamelName }}Service) Update(id string, req *models.Update{{ TitleName }}Request) (*models.{{ TitleName }}, error) {
-	// TODO: Implement Update logic
-	return nil, fmt.Errorf("not implemented")
+func (r *Gorm{{ TitleName }}Repository) get{{ TitleName }}(db *gorm.DB, id uint) (*models.{{ TitleName }}, error) {
+	var {{ CamelName }} models.{{ TitleName }}
+	if err := db.First(&{{ CamelName }}, id).Error; err != nil {
+		return nil, {{ CamelName }}Error("get", err)
+	}
+	return &{{ CamelName }}, nil
 }
 
-// Delete deletes a {{ TitleName }}
-func (s *{{ CamelName }}Service) Delete(id string) error {
-	// TODO: Implement Delete logic
-	return fmt.Errorf("not implemented")
+// Create inserts a {{ TitleName }}, filling in its ID and timestamps
+func (r *Gorm{{ TitleName }}Repository) Create(ctx context.Context, {{ CamelName }} *models.{{ TitleName }}) error {
+	if err := r.db.WithContext(ctx).Create({{ CamelName }}).Error; err != nil {
+		return {{ CamelName }}Error("create", err)
+	}
+	return nil
+}
+
+// Update saves the fields of a {{ TitleName }}, refreshing its timestamps.
+// Empty fields are saved too.
+func (r *Gorm{{ TitleName }}Repository) Update(ctx context.Context, {{ CamelName }} *models.{{ TitleName }}) error {
+	result := r.db.WithContext(ctx).Model({{ CamelName }}).Select("Name", "Description").Updates({{ CamelName }})
+	if result.Error != nil {
+		return {{ CamelName }}Error("update", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return Err{{ TitleName }}NotFound
+	}
+	return nil
+}
+
+// Delete soft-deletes the {{ TitleName }} with the given ID
+func (r *Gorm{{ TitleName }}Repository) Delete(ctx context.Context, id uint) error {
+	result := r.db.WithContext(ctx).Delete(&models.{{ TitleName }}{}, id)
+	if result.Error != nil {
+		return {{ CamelName }}Error("delete", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return Err{{ TitleName }}NotFound
+	}
+	return nil
+}
+
+// {{ CamelName }}Error wraps a failed query, reporting a missing row as
+// Err{{ TitleName }}NotFound
+func {{ CamelName }}Error(op string, err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return Err{{ TitleName }}NotFound
+	}
+	return fmt.Errorf("%s {{ TitleName }}: %w", op, err)
 }`,
 		},
 		{
-			Name: "service_test",
-			Path: "internal/services/{{ SnakeName }}_service_test.go",
-			Content: `package services
+			Name:      "repository_sqlx",
+			Path:      "internal/repository/{{ SnakeName }}_repository_{{ Database }}.go",
+			Databases: []string{"sqlx"},
+			Content: `package repository
 
 import (
-	"testing"
-	
-	"github.com/stretchr/testify/assert"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
 {% if ModuleName %}
+
 	"{{ ModuleName }}/internal/models"
 {% endif %}
 )
 
-func TestNew{{ TitleName }}Service(t *testing.T) {
-	service := New{{ TitleName }}Service()
-	assert.NotNil(t, service)
-}
+var _ {{ TitleName }}Repository = (*Sqlx{{ TitleName }}Repository)(nil)
 
-func Test{{ TitleName }}Service_GetAll(t *testing.T) {
-	service := New{{ TitleName }}Service()
-	
-	{{ CamelName }}s, err := service.GetAll()
-	
-	// Since this is not implemented yet, we expect an error
-	assert.Error(t, err)
-	assert.Nil(t, {{ CamelName }}s)
-	assert.Contains(t, err.Error(), "not implemented")
+// {{ CamelName }}Columns are scanned into models.{{ TitleName }} by its db tags
+const {{ CamelName }}Columns = "id, name, COALESCE(description, '') AS description, created_at, updated_at"
+
+// Sqlx{{ TitleName }}Repository implements {{ TitleName }}Repository on the {{ SnakeName }}s
+// table using statements prepared once by New{{ TitleName }}Repository
+type Sqlx{{ TitleName }}Repository struct {
+	db *sqlx.DB
+	tx *sqlx.Tx // set on the repository WithTx passes to its callback
+
+	list      *sqlx.Stmt
+	get       *sqlx.Stmt
+	getLocked *sqlx.Stmt
+	delete    *sqlx.Stmt
+	create    *sqlx.NamedStmt
+	update    *sqlx.NamedStmt
 }
 
-func Test{{ TitleName }}Service_GetByID(t *testing.T) {
-	service := New{{ TitleName }}Service()
-	
-	{{ CamelName }}, err := service.GetByID("test-id")
-	
-	// Since this is not implemented yet, we expect an error
-	assert.Error(t, err)
-	assert.Nil(t, {{ CamelName }})
-	assert.Contains(t, err.Error(), "not implemented")
+// New{{ TitleName }}Repository prepares the {{ TitleName }} statements on db. Close
+// releases them.
+func New{{ TitleName }}Repository(ctx context.Context, db *sqlx.DB) (*Sqlx{{ TitleName }}Repository, error) {
+	r := &Sqlx{{ TitleName }}Repository{db: db}
+	statements := []struct {
+		stmt  **sqlx.Stmt
+		query string
+	}{
+		{&r.list, "SELECT " + {{ CamelName }}Columns + " FROM {{ SnakeName }}s WHERE deleted_at IS NULL ORDER BY id"},
+		{&r.get, "SELECT " + {{ CamelName }}Columns + " FROM {{ SnakeName }}s WHERE id = $1 AND deleted_at IS NULL"},
+		{&r.getLocked, "SELECT " + {{ CamelName }}Columns + " FROM {{ SnakeName }}s WHERE id = $1 AND deleted_at IS NULL FOR UPDATE"},
+		{&r.delete, "UPDATE {{ SnakeName }}s SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL"},
+	}
+	for _, s := range statements {
+		stmt, err := db.PreparexContext(ctx, s.query)
+		if err != nil {
+			r.Close()
+			return nil, fmt.Errorf("prepare %q: %w", s.query, err)
+		}
+		*s.stmt = stmt
+	}
+
+	namedStatements := []struct {
+		stmt  **sqlx.NamedStmt
+		query string
+	}{
+		{&r.create, "INSERT INTO {{ SnakeName }}s (name, description) VALUES (:name, :description) RETURNING " + {{ CamelName }}Columns},
+		{&r.update, "UPDATE {{ SnakeName }}s SET name = :name, description = :description, updated_at = NOW() WHERE id = :id AND deleted_at IS NULL RETURNING " + {{ CamelName }}Columns},
+	}
+	for _, s := range namedStatements {
+		stmt, err := db.PrepareNamedContext(ctx, s.query)
+		if err != nil {
+			r.Close()
+			return nil, fmt.Errorf("prepare %q: %w", s.query, err)
+		}
+		*s.stmt = stmt
+	}
+	return r, nil
 }
 
-func Test{{ TitleName }}Service_Create(t *testing.T) {
-	service := New{{ TitleName }}Service()
-	req := &models.Create{{ TitleName }}Request{
-		Name:        "Test {{ TitleName }}",
-		Description: "Test description",
+// Close releases the prepared statements
+func (r *Sqlx{{ TitleName }}Repository) Close() error {
+	var errs []error
+	for _, stmt := range []*sqlx.Stmt{r.list, r.get, r.getLocked, r.delete} {
+		if stmt != nil {
+			errs = append(errs, stmt.Close())
+		}
 	}
-	
-	{{ CamelName }}, err := service.Create(req)
-	
-	// Since this is not implemented yet, we expect an error
-	assert.Error(t, err)
-	assert.Nil(t, {{ CamelName }})
-	assert.Contains(t, err.Error(), "not implemented")
-}`,
-		},
+	for _, stmt := range []*sqlx.NamedStmt{r.create, r.update} {
+		if stmt != nil {
+			errs = append(errs, stmt.Close())
+		}
 	}
+	return errors.Join(errs...)
+}
 
-	// Migration templates
-	templates["migration"] = []ComponentTemplate{
-		{
-			Name: "migration",
-			Path: "migrations/001_{{ SnakeName }}.sql",
-			Content: `-- Migration: {{ Name }}
--- Created: {{ Year }}
+// WithTx runs fn with a repository whose statements run in one transaction,
+// committed when fn returns nil and rolled back otherwise. Called inside
+// fn, it joins the running transaction.
+func (r *Sqlx{{ TitleName }}Repository) WithTx(ctx context.Context, fn func(tx {{ TitleName }}Repository) error) error {
+	if r.tx != nil {
+		return fn(r)
+	}
 
--- +goose Up
--- SQL in this section is executed when the migration is applied.
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+	}()
 
-CREATE TABLE IF NOT EXISTS {{ SnakeName }}s (
-    id SERIAL PRIMARY KEY,
-    name VARCHAR(255) NOT NULL,
-    description TEXT,
-    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
-    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
-    deleted_at TIMESTAMP WITH TIME ZONE
-);
+	txRepo := *r
+	txRepo.tx = tx
+	if err := fn(&txRepo); err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			return errors.Join(err, fmt.Errorf("rollback: %w", rollbackErr))
+		}
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
 
--- Add indexes
-CREATE INDEX IF NOT EXISTS idx_{{ SnakeName }}s_name ON {{ SnakeName }}s(name);
-CREATE INDEX IF NOT EXISTS idx_{{ SnakeName }}s_deleted_at ON {{ SnakeName }}s(deleted_at);
+// stmt returns a prepared statement, bound to the transaction inside WithTx
+func (r *Sqlx{{ TitleName }}Repository) stmt(ctx context.Context, stmt *sqlx.Stmt) *sqlx.Stmt {
+	if r.tx != nil {
+		return r.tx.StmtxContext(ctx, stmt)
+	}
+	return stmt
+}
 
--- +goose Down  
--- SQL in this section is executed when the migration is rolled back.
+// namedStmt returns a prepared named statement, bound to the transaction
+// inside WithTx
+func (r *Sqlx{{ TitleName }}Repository) namedStmt(ctx context.Context, stmt *sqlx.NamedStmt) *sqlx.NamedStmt {
+	if r.tx != nil {
+		return r.tx.NamedStmtContext(ctx, stmt)
+	}
+	return stmt
+}
 
-DROP TABLE IF EXISTS {{ SnakeName }}s;`,
-		},
+// List returns the {{ TitleName }}s that are not deleted, oldest first
+func (r *Sqlx{{ TitleName }}Repository) List(ctx context.Context) ([]*models.{{ TitleName }}, error) {
+	{{ CamelName }}s := []*models.{{ TitleName }}{}
+	if err := r.stmt(ctx, r.list).SelectContext(ctx, &{{ CamelName }}s); err != nil {
+		return nil, fmt.Errorf("list {{ TitleName }}s: %w", err)
 	}
+	return {{ CamelName }}s, nil
+}
 
-	// Middleware templates
-	templates["middleware"] = []ComponentTemplate{
-		{
-			Name: "middleware",
-			Path: "internal/middleware/{{ SnakeName }}_middleware.go",
-			Content: `package middleware
+// Get returns the {{ TitleName }} with the given ID
+func (r *Sqlx{{ TitleName }}Repository) Get(ctx context.Context, id int64) (*models.{{ TitleName }}, error) {
+	return r.get{{ TitleName }}(ctx, r.get, id)
+}
 
-import (
-{% if IsGin %}
-	"github.com/gin-gonic/gin"
-{% elif IsEcho %}
-	"github.com/labstack/echo/v4"
-{% elif IsChi %}
-	"net/http"
-{% endif %}
-)
+// GetForUpdate returns the {{ TitleName }} with the given ID and locks its row
+// until the transaction ends; use it inside WithTx
+func (r *Sqlx{{ TitleName }}Repository) GetForUpdate(ctx context.Context, id int64) (*models.{{ TitleName }}, error) {
+	return r.get{{ TitleName }}(ctx, r.getLocked, id)
+}
 
-{% if IsGin %}
-// {{ TitleName }}Middleware creates a new {{ TitleName }} middleware
-func {{ TitleName }}Middleware() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		// TODO: Implement {{ TitleName }} middleware logic
-		
-		c.Next()
+func (r *Sqlx{{ TitleName }}Repository) get{{ TitleName }}(ctx context.Context, stmt *sqlx.Stmt, id int64) (*models.{{ TitleName }}, error) {
+	var {{ CamelName }} models.{{ TitleName }}
+	if err := r.stmt(ctx, stmt).GetContext(ctx, &{{ CamelName }}, id); err != nil {
+		return nil, {{ CamelName }}Error("get", err)
 	}
+	return &{{ CamelName }}, nil
 }
-{% elif IsEcho %}
-// {{ TitleName }}Middleware creates a new {{ TitleName }} middleware
-func {{ TitleName }}Middleware() echo.MiddlewareFunc {
-	return func(next echo.HandlerFunc) echo.HandlerFunc {
-		return func(c echo.Context) error {
-			// TODO: Implement {{ TitleName }} middleware logic
-			
-			return next(c)
-		}
+
+// Create inserts a {{ TitleName }}, filling in its ID and timestamps
+func (r *Sqlx{{ TitleName }}Repository) Create(ctx context.Context, {{ CamelName }} *models.{{ TitleName }}) error {
+	if err := r.namedStmt(ctx, r.create).GetContext(ctx, {{ CamelName }}, {{ CamelName }}); err != nil {
+		return {{ CamelName }}Error("create", err)
 	}
+	return nil
 }
-{% elif IsChi %}
-// {{ TitleName }}Middleware creates a new {{ TitleName }} middleware
-func {{ TitleName }}Middleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// TODO: Implement {{ TitleName }} middleware logic
-		
-		next.ServeHTTP(w, r)
-	})
+
+// Update saves the fields of a {{ TitleName }}, refreshing its timestamps
+func (r *Sqlx{{ TitleName }}Repository) Update(ctx context.Context, {{ CamelName }} *models.{{ TitleName }}) error {
+	if err := r.namedStmt(ctx, r.update).GetContext(ctx, {{ CamelName }}, {{ CamelName }}); err != nil {
+		return {{ CamelName }}Error("update", err)
+	}
+	return nil
 }
-{% endif %}`,
-		},
+
+// Delete soft-deletes the {{ TitleName }} with the given ID
+func (r *Sqlx{{ TitleName }}Repository) Delete(ctx context.Context, id int64) error {
+	result, err := r.stmt(ctx, r.delete).ExecContext(ctx, id)
+	if err != nil {
+		return {{ CamelName }}Error("delete", err)
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return {{ CamelName }}Error("delete", err)
+	}
+	if deleted == 0 {
+		return Err{{ TitleName }}NotFound
 	}
+	return nil
+}
 
-	// Test templates
-	templates["test"] = []ComponentTemplate{
+// {{ CamelName }}Error wraps a failed query, reporting a missing row as
+// Err{{ TitleName }}NotFound
+func {{ CamelName }}Error(op string, err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return Err{{ TitleName }}NotFound
+	}
+	return fmt.Errorf("%s {{ TitleName }}: %w", op, err)
+}`,
+		},
 		{
-			Name: "test",
-			Path: "internal/{{ SnakeName }}/{{ SnakeName }}_test.go",
-			Content: `package {{ SnakeName }}
+			Name:      "repository_pgx",
+			Path:      "internal/repository/{{ SnakeName }}_repository_{{ Database }}.go",
+			Databases: []string{"pgx"},
+			Content: `package repository
 
 import (
-	"testing"
-	
-	"github.com/stretchr/testify/assert"
-	"github.com/stretchr/testify/require"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+{% if ModuleName %}
+
+	"{{ ModuleName }}/internal/models"
+{% endif %}
 )
 
-func Test{{ TitleName }}(t *testing.T) {
-	tests := []struct {
-		name     string
-		input    interface{}
-		expected interface{}
-		wantErr  bool
-	}{
-		{
-			name:     "valid case",
-			input:    "test input",
-			expected: "test output",
-			wantErr:  false,
-		},
-		{
-			name:     "error case",
-			input:    nil,
-			expected: nil,
-			wantErr:  true,
-		},
+var _ {{ TitleName }}Repository = (*Pgx{{ TitleName }}Repository)(nil)
+
+// The {{ TitleName }} queries. pgx prepares each statement the first time a
+// connection runs it and reuses it from then on (QueryExecModeCacheStatement,
+// the pool's default), so every query is parsed and planned once per
+// connection.
+const (
+	{{ CamelName }}Columns = "id, name, COALESCE(description, ''), created_at, updated_at"
+
+	list{{ TitleName }}sQuery     = "SELECT " + {{ CamelName }}Columns + " FROM {{ SnakeName }}s WHERE deleted_at IS NULL ORDER BY id"
+	get{{ TitleName }}Query       = "SELECT " + {{ CamelName }}Columns + " FROM {{ SnakeName }}s WHERE id = $1 AND deleted_at IS NULL"
+	getLocked{{ TitleName }}Query = get{{ TitleName }}Query + " FOR UPDATE"
+	create{{ TitleName }}Query    = "INSERT INTO {{ SnakeName }}s (name, description) VALUES ($1, $2) RETURNING " + {{ CamelName }}Columns
+	update{{ TitleName }}Query    = "UPDATE {{ SnakeName }}s SET name = $2, description = $3, updated_at = NOW() WHERE id = $1 AND deleted_at IS NULL RETURNING " + {{ CamelName }}Columns
+	delete{{ TitleName }}Query    = "UPDATE {{ SnakeName }}s SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL"
+)
+
+// {{ CamelName }}DB is implemented by *pgxpool.Pool, *pgx.Conn and pgx.Tx, so the
+// repository runs the same queries inside and outside a transaction
+type {{ CamelName }}DB interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// Pgx{{ TitleName }}Repository implements {{ TitleName }}Repository on the {{ SnakeName }}s table
+type Pgx{{ TitleName }}Repository struct {
+	db {{ CamelName }}DB
+}
+
+// New{{ TitleName }}Repository creates a repository on db, usually a *pgxpool.Pool
+func New{{ TitleName }}Repository(db {{ CamelName }}DB) *Pgx{{ TitleName }}Repository {
+	return &Pgx{{ TitleName }}Repository{db: db}
+}
+
+// WithTx runs fn with a repository whose queries run in one transaction,
+// committed when fn returns nil and rolled back otherwise. Called inside
+// fn, it runs a nested transaction on a savepoint.
+func (r *Pgx{{ TitleName }}Repository) WithTx(ctx context.Context, fn func(tx {{ TitleName }}Repository) error) error {
+	return pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		return fn(&Pgx{{ TitleName }}Repository{db: tx})
+	})
+}
+
+// scan{{ TitleName }} reads a row selected with {{ CamelName }}Columns
+func scan{{ TitleName }}(row pgx.Row) (*models.{{ TitleName }}, error) {
+	var {{ CamelName }} models.{{ TitleName }}
+	err := row.Scan(&{{ CamelName }}.ID, &{{ CamelName }}.Name, &{{ CamelName }}.Description, &{{ CamelName }}.CreatedAt, &{{ CamelName }}.UpdatedAt)
+	if err != nil {
+		return nil, err
 	}
-	
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			// TODO: Implement your test logic here
-			
-			if tt.wantErr {
-				assert.Error(t, nil) // Replace with actual error check
-			} else {
-				assert.NoError(t, nil) // Replace with actual success check
-				assert.Equal(t, tt.expected, tt.input) // Replace with actual assertion
-			}
-		})
+	return &{{ CamelName }}, nil
+}
+
+// List returns the {{ TitleName }}s that are not deleted, oldest first
+func (r *Pgx{{ TitleName }}Repository) List(ctx context.Context) ([]*models.{{ TitleName }}, error) {
+	rows, err := r.db.Query(ctx, list{{ TitleName }}sQuery)
+	if err != nil {
+		return nil, {{ CamelName }}Error("list", err)
+	}
+	defer rows.Close()
+
+	{{ CamelName }}s := []*models.{{ TitleName }}{}
+	for rows.Next() {
+		{{ CamelName }}, err := scan{{ TitleName }}(rows)
+		if err != nil {
+			return nil, {{ CamelName }}Error("list", err)
+		}
+		{{ CamelName }}s = append({{ CamelName }}s, {{ CamelName }})
 	}
+	if err := rows.Err(); err != nil {
+		return nil, {{ CamelName }}Error("list", err)
+	}
+	return {{ CamelName }}s, nil
 }
 
-func TestIntegration{{ TitleName }}(t *testing.T) {
-	// Integration tests
-	t.Skip("Integration test not implemented")
+// Get returns the {{ TitleName }} with the given ID
+func (r *Pgx{{ TitleName }}Repository) Get(ctx context.Context, id int64) (*models.{{ TitleName }}, error) {
+	{{ CamelName }}, err := scan{{ TitleName }}(r.db.QueryRow(ctx, get{{ TitleName }}Query, id))
+	if err != nil {
+		return nil, {{ CamelName }}Error("get", err)
+	}
+	return {{ CamelName }}, nil
 }
 
-func BenchmarkTest{{ TitleName }}(b *testing.B) {
-	for i := 0; i < b.N; i++ {
-		// TODO: Add benchmark logic
+// GetForUpdate returns the {{ TitleName }} with the given ID and locks its row
+// until the transaction ends; use it inside WithTx
+func (r *Pgx{{ TitleName }}Repository) GetForUpdate(ctx context.Context, id int64) (*models.{{ TitleName }}, error) {
+	{{ CamelName }}, err := scan{{ TitleName }}(r.db.QueryRow(ctx, getLocked{{ TitleName }}Query, id))
+	if err != nil {
+		return nil, {{ CamelName }}Error("get", err)
+	}
+	return {{ CamelName }}, nil
+}
+
+// Create inserts a {{ TitleName }}, filling in its ID and timestamps
+func (r *Pgx{{ TitleName }}Repository) Create(ctx context.Context, {{ CamelName }} *models.{{ TitleName }}) error {
+	created, err := scan{{ TitleName }}(r.db.QueryRow(ctx, create{{ TitleName }}Query, {{ CamelName }}.Name, {{ CamelName }}.Description))
+	if err != nil {
+		return {{ CamelName }}Error("create", err)
+	}
+	*{{ CamelName }} = *created
+	return nil
+}
+
+// Update saves the fields of a {{ TitleName }}, refreshing its timestamps
+func (r *Pgx{{ TitleName }}Repository) Update(ctx context.Context, {{ CamelName }} *models.{{ TitleName }}) error {
+	updated, err := scan{{ TitleName }}(r.db.QueryRow(ctx, update{{ TitleName }}Query, {{ CamelName }}.ID, {{ CamelName }}.Name, {{ CamelName }}.Description))
+	if err != nil {
+		return {{ CamelName }}Error("update", err)
+	}
+	*{{ CamelName }} = *updated
+	return nil
+}
+
+// Delete soft-deletes the {{ TitleName }} with the given ID
+func (r *Pgx{{ TitleName }}Repository) Delete(ctx context.Context, id int64) error {
+	tag, err := r.db.Exec(ctx, delete{{ TitleName }}Query, id)
+	if err != nil {
+		return {{ CamelName }}Error("delete", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return Err{{ TitleName }}NotFound
+	}
+	return nil
+}
+
+// {{ CamelName }}Error wraps a failed query, reporting a missing row as
+// Err{{ TitleName }}NotFound
+func {{ CamelName }}Error(op string, err error) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Err{{ TitleName }}NotFound
+	}
+	return fmt.Errorf("%s {{ TitleName }}: %w", op, err)
+}`,
+		},
+		{
+			Name: "repository_mock",
+			Path: "internal/repository/{{ SnakeName }}_repository_mock.go",
+			Content: `package repository
+
+import (
+	"context"
+{% if HasSlices %}	"maps"
+{% endif %}	"sync"
+	"time"
+{% if ModuleName %}
+
+	"{{ ModuleName }}/internal/models"
+{% endif %}
+)
+
+var _ {{ TitleName }}Repository = (*Mock{{ TitleName }}Repository)(nil)
+
+// Mock{{ TitleName }}Repository is an in-memory {{ TitleName }}Repository for tests of
+// the code that uses one. Set Err to make every call fail with it.
+type Mock{{ TitleName }}Repository struct {
+	Err error
+
+	mu     sync.Mutex
+	nextID {{ IDType }}
+	rows   map[{{ IDType }}]models.{{ TitleName }}
+}
+
+// NewMock{{ TitleName }}Repository creates an empty mock repository
+func NewMock{{ TitleName }}Repository() *Mock{{ TitleName }}Repository {
+	return &Mock{{ TitleName }}Repository{rows: make(map[{{ IDType }}]models.{{ TitleName }})}
+}
+
+// WithTx runs fn on the mock itself, restoring the stored {{ TitleName }}s when
+// fn fails as a rolled back transaction would
+func (m *Mock{{ TitleName }}Repository) WithTx(ctx context.Context, fn func(tx {{ TitleName }}Repository) error) error {
+	if m.Err != nil {
+		return m.Err
+	}
+
+	m.mu.Lock()
+{% if HasSlices %}	saved := maps.Clone(m.rows)
+{% else %}	saved := make(map[{{ IDType }}]models.{{ TitleName }}, len(m.rows))
+	for id, row := range m.rows {
+		saved[id] = row
+	}
+{% endif %}	m.mu.Unlock()
+
+	if err := fn(m); err != nil {
+		m.mu.Lock()
+		m.rows = saved
+		m.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// List returns copies of the stored {{ TitleName }}s, oldest first
+func (m *Mock{{ TitleName }}Repository) List(ctx context.Context) ([]*models.{{ TitleName }}, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	{{ CamelName }}s := []*models.{{ TitleName }}{}
+	for id := {{ IDType }}(1); id <= m.nextID; id++ {
+		if {{ CamelName }}, ok := m.rows[id]; ok {
+			{{ CamelName }}s = append({{ CamelName }}s, &{{ CamelName }})
+		}
+	}
+	return {{ CamelName }}s, nil
+}
+
+// Get returns a copy of the {{ TitleName }} with the given ID
+func (m *Mock{{ TitleName }}Repository) Get(ctx context.Context, id {{ IDType }}) (*models.{{ TitleName }}, error) {
+	if m.Err != nil {
+		return nil, m.Err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	{{ CamelName }}, ok := m.rows[id]
+	if !ok {
+		return nil, Err{{ TitleName }}NotFound
+	}
+	return &{{ CamelName }}, nil
+}
+
+// GetForUpdate is Get; the mock has no locks to take
+func (m *Mock{{ TitleName }}Repository) GetForUpdate(ctx context.Context, id {{ IDType }}) (*models.{{ TitleName }}, error) {
+	return m.Get(ctx, id)
+}
+
+// Create stores a copy of a {{ TitleName }}, filling in its ID and timestamps
+func (m *Mock{{ TitleName }}Repository) Create(ctx context.Context, {{ CamelName }} *models.{{ TitleName }}) error {
+	if m.Err != nil {
+		return m.Err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.nextID++
+	now := time.Now()
+	{{ CamelName }}.ID = m.nextID
+	{{ CamelName }}.CreatedAt = now
+	{{ CamelName }}.UpdatedAt = now
+	m.rows[{{ CamelName }}.ID] = *{{ CamelName }}
+	return nil
+}
+
+// Update replaces the stored copy of a {{ TitleName }}, refreshing UpdatedAt
+func (m *Mock{{ TitleName }}Repository) Update(ctx context.Context, {{ CamelName }} *models.{{ TitleName }}) error {
+	if m.Err != nil {
+		return m.Err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stored, ok := m.rows[{{ CamelName }}.ID]
+	if !ok {
+		return Err{{ TitleName }}NotFound
+	}
+	{{ CamelName }}.CreatedAt = stored.CreatedAt
+	{{ CamelName }}.UpdatedAt = time.Now()
+	m.rows[{{ CamelName }}.ID] = *{{ CamelName }}
+	return nil
+}
+
+// Delete removes the {{ TitleName }} with the given ID
+func (m *Mock{{ TitleName }}Repository) Delete(ctx context.Context, id {{ IDType }}) error {
+	if m.Err != nil {
+		return m.Err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.rows[id]; !ok {
+		return Err{{ TitleName }}NotFound
+	}
+	delete(m.rows, id)
+	return nil
+}`,
+		},
+		{
+			Name: "repository_test",
+			Path: "internal/repository/{{ SnakeName }}_repository_test.go",
+			Content: `package repository
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+{% if IsGorm %}
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+{% elif IsSqlx %}
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+{% else %}
+	"github.com/jackc/pgx/v5/pgxpool"
+{% endif %}
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+{% if ModuleName %}
+
+	"{{ ModuleName }}/internal/models"
+{% endif %}
+)
+{% if not IsGorm %}
+
+// {{ CamelName }}TestSchema matches the {{ SnakeName }} migration
+const {{ CamelName }}TestSchema = "CREATE TABLE IF NOT EXISTS {{ SnakeName }}s (" +
+	"id SERIAL PRIMARY KEY, name VARCHAR(255) NOT NULL, description TEXT, " +
+	"created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(), updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(), " +
+	"deleted_at TIMESTAMP WITH TIME ZONE)"
+{% endif %}
+
+// test{{ TitleName }}Repository checks the behaviour every {{ TitleName }}Repository
+// shares, so the mock stays true to the database implementation
+func test{{ TitleName }}Repository(t *testing.T, repo {{ TitleName }}Repository) {
+	ctx := context.Background()
+
+	{{ CamelName }} := &models.{{ TitleName }}{Name: "Test {{ TitleName }}", Description: "Test description"}
+	require.NoError(t, repo.Create(ctx, {{ CamelName }}))
+	require.NotZero(t, {{ CamelName }}.ID)
+	assert.False(t, {{ CamelName }}.CreatedAt.IsZero())
+
+	got, err := repo.Get(ctx, {{ CamelName }}.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Test {{ TitleName }}", got.Name)
+
+	got.Description = "Updated description"
+	require.NoError(t, repo.Update(ctx, got))
+	got, err = repo.Get(ctx, {{ CamelName }}.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Updated description", got.Description)
+
+	// A failed transaction leaves the {{ TitleName }} as it was
+	errRollback := errors.New("rollback")
+	err = repo.WithTx(ctx, func(tx {{ TitleName }}Repository) error {
+		locked, err := tx.GetForUpdate(ctx, {{ CamelName }}.ID)
+		if err != nil {
+			return err
+		}
+		locked.Name = "Renamed"
+		if err := tx.Update(ctx, locked); err != nil {
+			return err
+		}
+		return errRollback
+	})
+	assert.ErrorIs(t, err, errRollback)
+	got, err = repo.Get(ctx, {{ CamelName }}.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Test {{ TitleName }}", got.Name)
+
+	{{ CamelName }}s, err := repo.List(ctx)
+	require.NoError(t, err)
+	assert.NotEmpty(t, {{ CamelName }}s)
+
+	require.NoError(t, repo.Delete(ctx, {{ CamelName }}.ID))
+	_, err = repo.Get(ctx, {{ CamelName }}.ID)
+	assert.ErrorIs(t, err, Err{{ TitleName }}NotFound)
+	assert.ErrorIs(t, repo.Delete(ctx, {{ CamelName }}.ID), Err{{ TitleName }}NotFound)
+	assert.ErrorIs(t, repo.Update(ctx, {{ CamelName }}), Err{{ TitleName }}NotFound)
+}
+
+func TestMock{{ TitleName }}Repository(t *testing.T) {
+	test{{ TitleName }}Repository(t, NewMock{{ TitleName }}Repository())
+}
+
+// Test{{ TitleName }}Repository runs against the Postgres database in
+// TEST_DATABASE_URL and is skipped when it is not set
+func Test{{ TitleName }}Repository(t *testing.T) {
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	ctx := context.Background()
+
+{% if IsGorm %}
+	db, err := gorm.Open(postgres.Open(url), &gorm.Config{})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+	require.NoError(t, db.WithContext(ctx).AutoMigrate(&models.{{ TitleName }}{}))
+
+	test{{ TitleName }}Repository(t, New{{ TitleName }}Repository(db))
+{% elif IsSqlx %}
+	db, err := sqlx.ConnectContext(ctx, "postgres", url)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	_, err = db.ExecContext(ctx, {{ CamelName }}TestSchema)
+	require.NoError(t, err)
+
+	repo, err := New{{ TitleName }}Repository(ctx, db)
+	require.NoError(t, err)
+	t.Cleanup(func() { repo.Close() })
+
+	test{{ TitleName }}Repository(t, repo)
+{% else %}
+	pool, err := pgxpool.New(ctx, url)
+	require.NoError(t, err)
+	t.Cleanup(pool.Close)
+	_, err = pool.Exec(ctx, {{ CamelName }}TestSchema)
+	require.NoError(t, err)
+
+	test{{ TitleName }}Repository(t, New{{ TitleName }}Repository(pool))
+{% endif %}
+}`,
+		},
+	}
+
+	// Service templates. Services run on a repository, generated with them.
+	templates["service"] = append([]ComponentTemplate{
+		{
+			Name: "service",
+			Path: "internal/services/{{ SnakeName }}_service.go",
+			Content: `package services
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+{% if ModuleName %}
+
+	"{{ ModuleName }}/internal/models"
+	"{{ ModuleName }}/internal/repository"
+{% endif %}
+)
+
+// {{ TitleName }}Service defines the interface for {{ TitleName }} operations
+type {{ TitleName }}Service interface {
+	GetAll() ([]*models.{{ TitleName }}, error)
+	GetByID(id string) (*models.{{ TitleName }}, error)
+	Create(req *models.Create{{ TitleName }}Request) (*models.{{ TitleName }}, error)
+	Update(id string, req *models.Update{{ TitleName }}Request) (*models.{{ TitleName }}, error)
+	Delete(id string) error
+}
+
+// {{ CamelName }}Service implements {{ TitleName }}Service on a repository.
+// Its methods take no context, so queries run under context.Background().
+type {{ CamelName }}Service struct {
+	repo repository.{{ TitleName }}Repository
+}
+
+// New{{ TitleName }}Service creates a new {{ TitleName }} service
+func New{{ TitleName }}Service(repo repository.{{ TitleName }}Repository) {{ TitleName }}Service {
+	return &{{ CamelName }}Service{repo: repo}
+}
+
+// parse{{ TitleName }}ID converts a path parameter to a {{ TitleName }} ID; IDs that
+// cannot exist are reported as not found
+func parse{{ TitleName }}ID(id string) ({{ IDType }}, error) {
+	n, err := strconv.ParseInt(id, 10, 64)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid {{ TitleName }} ID %q: %w", id, repository.Err{{ TitleName }}NotFound)
+	}
+	return {% if IsGorm %}uint(n){% else %}n{% endif %}, nil
+}
+
+// GetAll retrieves all {{ TitleName }}s
+func (s *{{ CamelName }}Service) GetAll() ([]*models.{{ TitleName }}, error) {
+	return s.repo.List(context.Background())
+}
+
+// GetByID retrieves a {{ TitleName }} by ID
+func (s *{{ CamelName }}Service) GetByID(id string) (*models.{{ TitleName }}, error) {
+	n, err := parse{{ TitleName }}ID(id)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.Get(context.Background(), n)
+}
+
+// Create creates a new {{ TitleName }}
+func (s *{{ CamelName }}Service) Create(req *models.Create{{ TitleName }}Request) (*models.{{ TitleName }}, error) {
+	{{ CamelName }} := &models.{{ TitleName }}{
+		Name:        req.Name,
+		Description: req.Description,
 	}
+	if err := s.repo.Create(context.Background(), {{ CamelName }}); err != nil {
+		return nil, err
+	}
+	return {{ CamelName }}, nil
+}
+
+// Update updates an existing {{ TitleName }}, leaving fields the request leaves
+// empty unchanged. The row is locked between reading and writing it.
+func (s *{{ CamelName }}Service) Update(id string, req *models.Update{{ TitleName }}Request) (*models.{{ TitleName }}, error) {
+	n, err := parse{{ TitleName }}ID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	var updated *models.{{ TitleName }}
+	err = s.repo.WithTx(ctx, func(tx repository.{{ TitleName }}Repository) error {
+		{{ CamelName }}, err := tx.GetForUpdate(ctx, n)
+		if err != nil {
+			return err
+		}
+		if req.Name != "" {
+			{{ CamelName }}.Name = req.Name
+		}
+		if req.Description != "" {
+			{{ CamelName }}.Description = req.Description
+		}
+		if err := tx.Update(ctx, {{ CamelName }}); err != nil {
+			return err
+		}
+		updated = {{ CamelName }}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// Delete deletes a {{ TitleName }}
+func (s *{{ CamelName }}Service) Delete(id string) error {
+	n, err := parse{{ TitleName }}ID(id)
+	if err != nil {
+		return err
+	}
+	return s.repo.Delete(context.Background(), n)
+}`,
+		},
+		{
+			Name: "service_test",
+			Path: "internal/services/{{ SnakeName }}_service_test.go",
+			Content: `package services
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+{% if ModuleName %}
+
+	"{{ ModuleName }}/internal/models"
+	"{{ ModuleName }}/internal/repository"
+{% endif %}
+)
+
+func Test{{ TitleName }}Service_CRUD(t *testing.T) {
+	service := New{{ TitleName }}Service(repository.NewMock{{ TitleName }}Repository())
+
+	created, err := service.Create(&models.Create{{ TitleName }}Request{
+		Name:        "Test {{ TitleName }}",
+		Description: "Test description",
+	})
+	require.NoError(t, err)
+	require.NotZero(t, created.ID)
+	id := {% if IsGorm %}strconv.FormatUint(uint64(created.ID), 10){% else %}strconv.FormatInt(created.ID, 10){% endif %}
+
+	{{ CamelName }}, err := service.GetByID(id)
+	require.NoError(t, err)
+	assert.Equal(t, "Test {{ TitleName }}", {{ CamelName }}.Name)
+
+	updated, err := service.Update(id, &models.Update{{ TitleName }}Request{Description: "Updated description"})
+	require.NoError(t, err)
+	assert.Equal(t, "Test {{ TitleName }}", updated.Name, "empty fields are left unchanged")
+	assert.Equal(t, "Updated description", updated.Description)
+
+	{{ CamelName }}s, err := service.GetAll()
+	require.NoError(t, err)
+	assert.Len(t, {{ CamelName }}s, 1)
+
+	require.NoError(t, service.Delete(id))
+	_, err = service.GetByID(id)
+	assert.ErrorIs(t, err, repository.Err{{ TitleName }}NotFound)
+	assert.ErrorIs(t, service.Delete(id), repository.Err{{ TitleName }}NotFound)
+}
+
+func Test{{ TitleName }}Service_InvalidID(t *testing.T) {
+	service := New{{ TitleName }}Service(repository.NewMock{{ TitleName }}Repository())
+
+	_, err := service.GetByID("not-a-number")
+	assert.ErrorIs(t, err, repository.Err{{ TitleName }}NotFound)
+	assert.ErrorIs(t, service.Delete("0"), repository.Err{{ TitleName }}NotFound)
+}
+
+func Test{{ TitleName }}Service_RepositoryError(t *testing.T) {
+	repo := repository.NewMock{{ TitleName }}Repository()
+	repo.Err = errors.New("connection refused")
+	service := New{{ TitleName }}Service(repo)
+
+	_, err := service.GetAll()
+	assert.ErrorIs(t, err, repo.Err)
+	_, err = service.Update("1", &models.Update{{ TitleName }}Request{Name: "Renamed"})
+	assert.ErrorIs(t, err, repo.Err)
 }`,
 		},
+	}, templates["repository"]...)
+
+	// Migration templates
+	templates["migration"] = []ComponentTemplate{
+		{
+			Name: "migration",
+			Path: "migrations/001_{{ SnakeName }}.sql",
+			Content: `-- Migration: {{ Name }}
+-- Created: {{ Year }}
+
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+CREATE TABLE IF NOT EXISTS {{ SnakeName }}s (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+-- Add indexes
+CREATE INDEX IF NOT EXISTS idx_{{ SnakeName }}s_name ON {{ SnakeName }}s(name);
+CREATE INDEX IF NOT EXISTS idx_{{ SnakeName }}s_deleted_at ON {{ SnakeName }}s(deleted_at);
+
+-- +goose Down  
+-- SQL in this section is executed when the migration is rolled back.
+
+DROP TABLE IF EXISTS {{ SnakeName }}s;`,
+		},
+	}
+
+	// Middleware templates
+	templates["middleware"] = []ComponentTemplate{
+		{
+			Name: "middleware",
+			Path: "internal/middleware/{{ SnakeName }}_middleware.go",
+			Content: `package middleware
+
+import (
+{% if IsGin %}
+	"github.com/gin-gonic/gin"
+{% elif IsEcho %}
+	"github.com/labstack/echo/v4"
+{% elif IsChi %}
+	"net/http"
+{% endif %}
+)
+
+{% if IsGin %}
+// {{ TitleName }}Middleware creates a new {{ TitleName }} middleware
+func {{ TitleName }}Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// TODO: Implement {{ TitleName }} middleware logic
+		
+		c.Next()
+	}
+}
+{% elif IsEcho %}
+// {{ TitleName }}Middleware creates a new {{ TitleName }} middleware
+func {{ TitleName }}Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			// TODO: Implement {{ TitleName }} middleware logic
+			
+			return next(c)
+		}
+	}
+}
+{% elif IsChi %}
+// {{ TitleName }}Middleware creates a new {{ TitleName }} middleware
+func {{ TitleName }}Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// TODO: Implement {{ TitleName }} middleware logic
+		
+		next.ServeHTTP(w, r)
+	})
+}
+{% endif %}`,
+		},
+	}
+
+	// Test templates
+	templates["test"] = []ComponentTemplate{
+		{
+			Name: "test",
+			Path: "internal/{{ SnakeName }}/{{ SnakeName }}_test.go",
+			Content: `package {{ SnakeName }}
+
+import (
+	"testing"
+	
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test{{ TitleName }}(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    interface{}
+		expected interface{}
+		wantErr  bool
+	}{
+		{
+			name:     "valid case",
+			input:    "test input",
+			expected: "test output",
+			wantErr:  false,
+		},
+		{
+			name:     "error case",
+			input:    nil,
+			expected: nil,
+			wantErr:  true,
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// TODO: Implement your test logic here
+			
+			if tt.wantErr {
+				assert.Error(t, nil) // Replace with actual error check
+			} else {
+				assert.NoError(t, nil) // Replace with actual success check
+				assert.Equal(t, tt.expected, tt.input) // Replace with actual assertion
+			}
+		})
+	}
+}
+
+func TestIntegration{{ TitleName }}(t *testing.T) {
+	// Integration tests
+	t.Skip("Integration test not implemented")
+}
+
+func BenchmarkTest{{ TitleName }}(b *testing.B) {
+{% if HasRangeInt %}	for range b.N {
+{% else %}	for i := 0; i < b.N; i++ {
+{% endif %}
+		// TODO: Add benchmark logic
+	}
+}`,
+		},
+	}
+
+	// Shared library templates (monorepo pkg/ modules)
+	templates["shared"] = []ComponentTemplate{
+		{
+			Name: "go.mod",
+			Path: "pkg/{{ KebabName }}/go.mod",
+			Content: `module {{ ModuleName }}
+
+go {{ GoVersion }}
+`,
+		},
+		{
+			Name: "errors",
+			Path: "pkg/{{ KebabName }}/errors/errors.go",
+			Content: `// Package errors provides error helpers shared across services
+package errors
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Common sentinel errors
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrInvalidInput = errors.New("invalid input")
+	ErrConflict     = errors.New("conflict")
+	ErrUnauthorized = errors.New("unauthorized")
+)
+
+// Error is an error with a stable machine-readable code
+type Error struct {
+	Code    string
+	Message string
+	Err     error
+}
+
+// Error implements the error interface
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %s: %v", e.Code, e.Message, e.Err)
+	}
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+// Unwrap returns the wrapped error
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// New creates a coded error
+func New(code, message string) *Error {
+	return &Error{Code: code, Message: message}
+}
+
+// Wrap wraps err with a code and message
+func Wrap(err error, code, message string) *Error {
+	return &Error{Code: code, Message: message, Err: err}
+}
+
+// Is reports whether any error in err's chain matches target
+func Is(err, target error) bool {
+	return errors.Is(err, target)
+}
+
+// As finds the first error in err's chain that matches target
+func As(err error, target any) bool {
+	return errors.As(err, target)
+}
+`,
+		},
+		{
+			Name:          "logging",
+			Path:          "pkg/{{ KebabName }}/logging/logging.go",
+			LanguageLevel: "1.21", // log/slog
+			Content: `// Package logging provides a consistently configured structured logger
+package logging
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// Options configures a logger
+type Options struct {
+	Service string
+	Level   string // debug, info, warn, error
+	JSON    bool
+	Output  io.Writer
+}
+
+// New creates a structured logger tagged with the service name
+func New(opts Options) *slog.Logger {
+	if opts.Output == nil {
+		opts.Output = os.Stderr
+	}
+
+	handlerOpts := &slog.HandlerOptions{Level: ParseLevel(opts.Level)}
+
+	var handler slog.Handler
+	if opts.JSON {
+		handler = slog.NewJSONHandler(opts.Output, handlerOpts)
+	} else {
+		handler = slog.NewTextHandler(opts.Output, handlerOpts)
+	}
+
+	logger := slog.New(handler)
+	if opts.Service != "" {
+		logger = logger.With("service", opts.Service)
+	}
+	return logger
+}
+
+// ParseLevel converts a level name into a slog level, defaulting to info
+func ParseLevel(level string) slog.Level {
+	switch strings.ToLower(level) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn", "warning":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+`,
+		},
+		{
+			Name: "config",
+			Path: "pkg/{{ KebabName }}/config/config.go",
+			Content: `// Package config provides helpers for reading configuration from the environment
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// String returns the value of key or fallback when unset
+func String(key, fallback string) string {
+	if value, ok := os.LookupEnv(key); ok {
+		return value
+	}
+	return fallback
+}
+
+// Int returns the integer value of key or fallback when unset or invalid
+func Int(key string, fallback int) int {
+	if value, ok := os.LookupEnv(key); ok {
+		if parsed, err := strconv.Atoi(value); err == nil {
+			return parsed
+		}
+	}
+	return fallback
+}
+
+// Bool returns the boolean value of key or fallback when unset or invalid
+func Bool(key string, fallback bool) bool {
+	if value, ok := os.LookupEnv(key); ok {
+		if parsed, err := strconv.ParseBool(value); err == nil {
+			return parsed
+		}
+	}
+	return fallback
+}
+
+// Duration returns the duration value of key or fallback when unset or invalid
+func Duration(key string, fallback time.Duration) time.Duration {
+	if value, ok := os.LookupEnv(key); ok {
+		if parsed, err := time.ParseDuration(value); err == nil {
+			return parsed
+		}
+	}
+	return fallback
+}
+
+// Required returns the value of key or an error when it is unset or empty
+func Required(key string) (string, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return "", fmt.Errorf("required environment variable %s is not set", key)
+	}
+	return value, nil
+}
+`,
+		},
+	}
+
+	// Notifier templates (email/chat notifications)
+	templates["notifier"] = []ComponentTemplate{
+		{
+			Name: "notifier",
+			Path: "internal/{{ SnakeName }}/notifier.go",
+			Content: `// Package {{ SnakeName }} delivers {{ TitleName }} notifications over email and webhooks
+package {{ SnakeName }}
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"text/template"
+	"time"
+)
+
+// ErrPermanent marks a delivery failure that must not be retried
+var ErrPermanent = errors.New("permanent delivery failure")
+
+// Message is a notification to deliver
+type Message struct {
+	To      []string
+	Subject string
+	Body    string
+}
+
+// Notifier delivers messages over a channel such as email or chat
+type Notifier interface {
+	Notify(ctx context.Context, msg Message) error
+}
+
+// Templates renders message subjects and bodies from named text templates.
+// Each message name needs a "<name>.subject" and a "<name>.body" template.
+type Templates struct {
+	tpl *template.Template
+}
+
+// DefaultTemplates are the built-in message templates
+var DefaultTemplates = map[string]string{
+	"welcome.subject": "Welcome, {{ "{{" }} .Name {{ "}}" }}!",
+	"welcome.body":    "Hi {{ "{{" }} .Name {{ "}}" }},\n\nYour account is ready.",
+	"alert.subject":   "[{{ "{{" }} .Severity {{ "}}" }}] {{ "{{" }} .Title {{ "}}" }}",
+	"alert.body":      "{{ "{{" }} .Description {{ "}}" }}",
+}
+
+// NewTemplates parses message templates
+func NewTemplates(definitions map[string]string) (*Templates, error) {
+	root := template.New("{{ SnakeName }}").Option("missingkey=error")
+	for name, text := range definitions {
+		if _, err := root.New(name).Parse(text); err != nil {
+			return nil, fmt.Errorf("failed to parse template %s: %w", name, err)
+		}
+	}
+	return &Templates{tpl: root}, nil
+}
+
+// Render builds a message for recipients from the named templates
+func (t *Templates) Render(name string, data any, to ...string) (Message, error) {
+	subject, err := t.execute(name+".subject", data)
+	if err != nil {
+		return Message{}, err
+	}
+	body, err := t.execute(name+".body", data)
+	if err != nil {
+		return Message{}, err
+	}
+	return Message{To: to, Subject: subject, Body: body}, nil
+}
+
+func (t *Templates) execute(name string, data any) (string, error) {
+	var buf bytes.Buffer
+	if err := t.tpl.ExecuteTemplate(&buf, name, data); err != nil {
+		return "", fmt.Errorf("failed to render template %s: %w", name, err)
+	}
+	return buf.String(), nil
+}
+
+// RetryPolicy controls how failed deliveries are retried
+type RetryPolicy struct {
+	Attempts int
+	Backoff  time.Duration // Initial delay, doubled after each failed attempt
+}
+
+// DefaultRetryPolicy retries three times starting at 500ms
+var DefaultRetryPolicy = RetryPolicy{Attempts: 3, Backoff: 500 * time.Millisecond}
+
+type retryNotifier struct {
+	next   Notifier
+	policy RetryPolicy
+}
+
+// WithRetry wraps a notifier so transient failures are retried with exponential backoff
+func WithRetry(next Notifier, policy RetryPolicy) Notifier {
+	if policy.Attempts < 1 {
+		policy.Attempts = 1
+	}
+	return &retryNotifier{next: next, policy: policy}
+}
+
+// Notify delivers the message, retrying transient failures
+func (r *retryNotifier) Notify(ctx context.Context, msg Message) error {
+	var err error
+	backoff := r.policy.Backoff
+	for attempt := 1; attempt <= r.policy.Attempts; attempt++ {
+		err = r.next.Notify(ctx, msg)
+		if err == nil || errors.Is(err, ErrPermanent) {
+			return err
+		}
+		if attempt == r.policy.Attempts {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+	return fmt.Errorf("notification failed after %d attempts: %w", r.policy.Attempts, err)
+}
+`,
+		},
+		{
+			Name: "smtp",
+			Path: "internal/{{ SnakeName }}/smtp.go",
+			Content: `package {{ SnakeName }}
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+)
+
+// Transport sends a raw email message; smtp.SendMail satisfies it through TransportFunc
+type Transport interface {
+	Send(addr string, auth smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// TransportFunc adapts a function to the Transport interface
+type TransportFunc func(addr string, auth smtp.Auth, from string, to []string, msg []byte) error
+
+// Send calls f
+func (f TransportFunc) Send(addr string, auth smtp.Auth, from string, to []string, msg []byte) error {
+	return f(addr, auth, from, to, msg)
+}
+
+// SMTPConfig holds SMTP server settings
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+// SMTPNotifier delivers messages as plain-text email
+type SMTPNotifier struct {
+	config    SMTPConfig
+	transport Transport
+}
+
+// NewSMTPNotifier creates an email notifier using net/smtp
+func NewSMTPNotifier(config SMTPConfig) *SMTPNotifier {
+	if config.Port == 0 {
+		config.Port = 587
+	}
+	return &SMTPNotifier{config: config, transport: TransportFunc(smtp.SendMail)}
+}
+
+// WithTransport replaces the transport, typically with a fake in tests
+func (n *SMTPNotifier) WithTransport(transport Transport) *SMTPNotifier {
+	n.transport = transport
+	return n
+}
+
+// Notify sends msg as an email
+func (n *SMTPNotifier) Notify(ctx context.Context, msg Message) error {
+	if len(msg.To) == 0 {
+		return fmt.Errorf("%w: message has no recipients", ErrPermanent)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if n.config.Username != "" {
+		auth = smtp.PlainAuth("", n.config.Username, n.config.Password, n.config.Host)
+	}
+
+	addr := net.JoinHostPort(n.config.Host, strconv.Itoa(n.config.Port))
+	if err := n.transport.Send(addr, auth, n.config.From, msg.To, n.buildMessage(msg)); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
+
+func (n *SMTPNotifier) buildMessage(msg Message) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", n.config.From)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(msg.To, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", msg.Subject)
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(msg.Body)
+	return []byte(b.String())
+}
+`,
+		},
+		{
+			Name: "webhook",
+			Path: "internal/{{ SnakeName }}/webhook.go",
+			Content: `package {{ SnakeName }}
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// HTTPDoer sends HTTP requests; *http.Client satisfies it
+type HTTPDoer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// WebhookNotifier posts messages to a Slack-compatible incoming webhook
+type WebhookNotifier struct {
+	url    string
+	client HTTPDoer
+}
+
+// NewWebhookNotifier creates a webhook notifier for url
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{url: url, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// WithClient replaces the HTTP client
+func (n *WebhookNotifier) WithClient(client HTTPDoer) *WebhookNotifier {
+	n.client = client
+	return n
+}
+
+// Notify posts msg to the webhook
+func (n *WebhookNotifier) Notify(ctx context.Context, msg Message) error {
+	payload, err := json.Marshal(map[string]string{
+		"text": fmt.Sprintf("*%s*\n%s", msg.Subject, msg.Body),
+	})
+	if err != nil {
+		return fmt.Errorf("%w: failed to encode payload: %v", ErrPermanent, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("%w: failed to build request: %v", ErrPermanent, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	switch {
+	case resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	default:
+		return fmt.Errorf("%w: webhook returned %s", ErrPermanent, resp.Status)
+	}
+}
+`,
+		},
+		{
+			Name: "notifier_test",
+			Path: "internal/{{ SnakeName }}/notifier_test.go",
+			Content: `package {{ SnakeName }}
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTransport records emails instead of sending them
+type fakeTransport struct {
+	sent []string
+	err  error
+}
+
+func (f *fakeTransport) Send(addr string, auth smtp.Auth, from string, to []string, msg []byte) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.sent = append(f.sent, string(msg))
+	return nil
+}
+
+// flakyNotifier fails a fixed number of times before succeeding
+type flakyNotifier struct {
+	failures int
+	calls    int
+	err      error
+}
+
+func (f *flakyNotifier) Notify(ctx context.Context, msg Message) error {
+	f.calls++
+	if f.calls <= f.failures {
+		return f.err
+	}
+	return nil
+}
+
+func TestTemplates_Render(t *testing.T) {
+	templates, err := NewTemplates(DefaultTemplates)
+	require.NoError(t, err)
+
+	msg, err := templates.Render("welcome", map[string]string{"Name": "Ada"}, "ada@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "Welcome, Ada!", msg.Subject)
+	assert.Contains(t, msg.Body, "Hi Ada")
+	assert.Equal(t, []string{"ada@example.com"}, msg.To)
+
+	_, err = templates.Render("missing", nil)
+	assert.Error(t, err)
+}
+
+func TestSMTPNotifier_Notify(t *testing.T) {
+	transport := &fakeTransport{}
+	notifier := NewSMTPNotifier(SMTPConfig{Host: "localhost", From: "noreply@example.com"}).WithTransport(transport)
+
+	err := notifier.Notify(context.Background(), Message{To: []string{"a@example.com"}, Subject: "Hello", Body: "World"})
+	require.NoError(t, err)
+	require.Len(t, transport.sent, 1)
+	assert.Contains(t, transport.sent[0], "Subject: Hello")
+	assert.True(t, strings.HasSuffix(transport.sent[0], "World"))
+
+	err = notifier.Notify(context.Background(), Message{Subject: "No recipients"})
+	assert.ErrorIs(t, err, ErrPermanent)
+}
+
+func TestWebhookNotifier_Notify(t *testing.T) {
+	var payload map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	err := NewWebhookNotifier(server.URL).Notify(context.Background(), Message{Subject: "Deploy", Body: "done"})
+	require.NoError(t, err)
+	assert.Equal(t, "*Deploy*\ndone", payload["text"])
+}
+
+func TestWebhookNotifier_ClientErrorIsPermanent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	err := NewWebhookNotifier(server.URL).Notify(context.Background(), Message{Subject: "x"})
+	assert.ErrorIs(t, err, ErrPermanent)
+}
+
+func TestWithRetry(t *testing.T) {
+	policy := RetryPolicy{Attempts: 3, Backoff: time.Millisecond}
+
+	flaky := &flakyNotifier{failures: 2, err: errors.New("temporary")}
+	require.NoError(t, WithRetry(flaky, policy).Notify(context.Background(), Message{}))
+	assert.Equal(t, 3, flaky.calls)
+
+	permanent := &flakyNotifier{failures: 5, err: ErrPermanent}
+	assert.ErrorIs(t, WithRetry(permanent, policy).Notify(context.Background(), Message{}), ErrPermanent)
+	assert.Equal(t, 1, permanent.calls)
+
+	exhausted := &flakyNotifier{failures: 5, err: errors.New("temporary")}
+	assert.Error(t, WithRetry(exhausted, policy).Notify(context.Background(), Message{}))
+	assert.Equal(t, 3, exhausted.calls)
+}
+`,
+		},
+	}
+
+	// Storage templates (blob storage abstraction)
+	templates["storage"] = []ComponentTemplate{
+		{
+			Name: "storage",
+			Path: "internal/{{ SnakeName }}/storage.go",
+			Content: `// Package {{ SnakeName }} provides {{ TitleName }} blob storage backed by the local filesystem or S3
+package {{ SnakeName }}
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// ErrNotFound is returned when an object does not exist
+var ErrNotFound = errors.New("object not found")
+
+// Store is a blob storage backend
+type Store interface {
+	// Put streams r into the object at key, replacing any existing object
+	Put(ctx context.Context, key string, r io.Reader, contentType string) error
+	// Get opens the object at key for streaming; callers must close the reader
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the object at key; deleting a missing object is not an error
+	Delete(ctx context.Context, key string) error
+	// Exists reports whether an object exists at key
+	Exists(ctx context.Context, key string) (bool, error)
+	// PresignGet returns a URL granting temporary read access to key
+	PresignGet(ctx context.Context, key string, ttl time.Duration) (string, error)
+	// PresignPut returns a URL granting temporary upload access to key
+	PresignPut(ctx context.Context, key string, ttl time.Duration) (string, error)
+}
+
+// Config selects and configures a storage backend
+type Config struct {
+	Driver string // local or s3
+	Local  LocalConfig
+	S3     S3Config
+}
+
+// New creates a store for the configured driver
+func New(ctx context.Context, config Config) (Store, error) {
+	switch config.Driver {
+	case "", "local":
+		return NewLocalStore(config.Local)
+	case "s3":
+		return NewS3Store(ctx, config.S3)
+	default:
+		return nil, fmt.Errorf("unsupported storage driver %q", config.Driver)
+	}
+}
+`,
+		},
+		{
+			Name: "local",
+			Path: "internal/{{ SnakeName }}/local.go",
+			Content: `package {{ SnakeName }}
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LocalConfig configures filesystem storage
+type LocalConfig struct {
+	Dir        string // Root directory for objects
+	BaseURL    string // Public URL that serves objects, used for presigned URLs
+	SigningKey []byte // HMAC key for presigned URLs
+}
+
+// LocalStore stores objects as files under a root directory
+type LocalStore struct {
+	config LocalConfig
+	now    func() time.Time
+}
+
+// NewLocalStore creates a filesystem store, creating the root directory if needed
+func NewLocalStore(config LocalConfig) (*LocalStore, error) {
+	if config.Dir == "" {
+		return nil, fmt.Errorf("local storage directory is required")
+	}
+	if err := os.MkdirAll(config.Dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create storage directory: %w", err)
+	}
+	return &LocalStore{config: config, now: time.Now}, nil
+}
+
+// Put streams r into a temporary file and renames it into place
+func (s *LocalStore) Put(ctx context.Context, key string, r io.Reader, contentType string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", key, err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, &contextReader{ctx: ctx, r: r}); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s: %w", key, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", key, err)
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// Get opens the file for key
+func (s *LocalStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, key)
+	}
+	return file, err
+}
+
+// Delete removes the file for key
+func (s *LocalStore) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete %s: %w", key, err)
+	}
+	return nil
+}
+
+// Exists reports whether the file for key exists
+func (s *LocalStore) Exists(ctx context.Context, key string) (bool, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return false, err
+	}
+	_, err = os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// PresignGet returns an HMAC-signed download URL under BaseURL
+func (s *LocalStore) PresignGet(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	return s.presign("GET", key, ttl)
+}
+
+// PresignPut returns an HMAC-signed upload URL under BaseURL
+func (s *LocalStore) PresignPut(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	return s.presign("PUT", key, ttl)
+}
+
+// VerifySignature checks a presigned URL's query parameters for method and key
+func (s *LocalStore) VerifySignature(method, key string, query url.Values) error {
+	expires, err := strconv.ParseInt(query.Get("expires"), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid expiry")
+	}
+	if s.now().Unix() > expires {
+		return fmt.Errorf("signature expired")
+	}
+
+	expected := s.signature(method, key, expires)
+	if !hmac.Equal([]byte(expected), []byte(query.Get("signature"))) {
+		return fmt.Errorf("invalid signature")
+	}
+	return nil
+}
+
+func (s *LocalStore) presign(method, key string, ttl time.Duration) (string, error) {
+	if s.config.BaseURL == "" || len(s.config.SigningKey) == 0 {
+		return "", fmt.Errorf("presigned URLs require BaseURL and SigningKey")
+	}
+	if _, err := s.path(key); err != nil {
+		return "", err
+	}
+
+	expires := s.now().Add(ttl).Unix()
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(expires, 10))
+	query.Set("signature", s.signature(method, key, expires))
+
+	return strings.TrimSuffix(s.config.BaseURL, "/") + "/" + key + "?" + query.Encode(), nil
+}
+
+func (s *LocalStore) signature(method, key string, expires int64) string {
+	mac := hmac.New(sha256.New, s.config.SigningKey)
+	fmt.Fprintf(mac, "%s\n%s\n%d", method, key, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// path maps key to a file under the root, rejecting keys that escape it
+func (s *LocalStore) path(key string) (string, error) {
+	clean := filepath.Clean(filepath.FromSlash(key))
+	if key == "" || filepath.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid object key %q", key)
+	}
+	return filepath.Join(s.config.Dir, clean), nil
+}
+
+// contextReader stops a copy when the context is cancelled
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (c *contextReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.r.Read(p)
+}
+`,
+		},
+		{
+			Name: "s3",
+			Path: "internal/{{ SnakeName }}/s3.go",
+			Content: `package {{ SnakeName }}
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// S3Config configures S3 (or S3-compatible) storage
+type S3Config struct {
+	Bucket       string
+	Region       string
+	Endpoint     string // Custom endpoint for MinIO, LocalStack, etc.
+	UsePathStyle bool
+}
+
+// S3Store stores objects in an S3 bucket
+type S3Store struct {
+	bucket   string
+	client   *s3.Client
+	uploader *manager.Uploader
+	presign  *s3.PresignClient
+}
+
+// NewS3Store creates an S3 store using the default AWS credential chain
+func NewS3Store(ctx context.Context, config S3Config) (*S3Store, error) {
+	if config.Bucket == "" {
+		return nil, fmt.Errorf("s3 bucket is required")
+	}
+
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx, awsconfig.WithRegion(config.Region))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+
+	client := s3.NewFromConfig(awsCfg, func(o *s3.Options) {
+		if config.Endpoint != "" {
+			o.BaseEndpoint = aws.String(config.Endpoint)
+		}
+		o.UsePathStyle = config.UsePathStyle
+	})
+
+	return &S3Store{
+		bucket:   config.Bucket,
+		client:   client,
+		uploader: manager.NewUploader(client),
+		presign:  s3.NewPresignClient(client),
+	}, nil
+}
+
+// Put streams r to S3, using multipart uploads for large bodies
+func (s *S3Store) Put(ctx context.Context, key string, r io.Reader, contentType string) error {
+	input := &s3.PutObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+		Body:   r,
+	}
+	if contentType != "" {
+		input.ContentType = aws.String(contentType)
+	}
+
+	if _, err := s.uploader.Upload(ctx, input); err != nil {
+		return fmt.Errorf("failed to upload %s: %w", key, err)
+	}
+	return nil
+}
+
+// Get streams the object body from S3
+func (s *S3Store) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	out, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		var noSuchKey *types.NoSuchKey
+		if errors.As(err, &noSuchKey) {
+			return nil, fmt.Errorf("%w: %s", ErrNotFound, key)
+		}
+		return nil, fmt.Errorf("failed to get %s: %w", key, err)
+	}
+	return out.Body, nil
+}
+
+// Delete removes the object from S3
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete %s: %w", key, err)
+	}
+	return nil
+}
+
+// Exists checks for the object with a HEAD request
+func (s *S3Store) Exists(ctx context.Context, key string) (bool, error) {
+	_, err := s.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		var notFound *types.NotFound
+		if errors.As(err, &notFound) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to check %s: %w", key, err)
+	}
+	return true, nil
+}
+
+// PresignGet returns a presigned GET URL
+func (s *S3Store) PresignGet(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	req, err := s.presign.PresignGetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	}, s3.WithPresignExpires(ttl))
+	if err != nil {
+		return "", fmt.Errorf("failed to presign GET %s: %w", key, err)
+	}
+	return req.URL, nil
+}
+
+// PresignPut returns a presigned PUT URL
+func (s *S3Store) PresignPut(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	req, err := s.presign.PresignPutObject(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	}, s3.WithPresignExpires(ttl))
+	if err != nil {
+		return "", fmt.Errorf("failed to presign PUT %s: %w", key, err)
+	}
+	return req.URL, nil
+}
+`,
+		},
+		{
+			Name: "local_test",
+			Path: "internal/{{ SnakeName }}/local_test.go",
+			Content: `package {{ SnakeName }}
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestLocalStore(t *testing.T) *LocalStore {
+	store, err := NewLocalStore(LocalConfig{
+		Dir:        t.TempDir(),
+		BaseURL:    "http://localhost:8080/files",
+		SigningKey: []byte("test-key"),
+	})
+	require.NoError(t, err)
+	return store
+}
+
+func TestLocalStore_PutGetDelete(t *testing.T) {
+	store := newTestLocalStore(t)
+	ctx := context.Background()
+
+	require.NoError(t, store.Put(ctx, "docs/readme.txt", strings.NewReader("hello"), "text/plain"))
+
+	exists, err := store.Exists(ctx, "docs/readme.txt")
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	reader, err := store.Get(ctx, "docs/readme.txt")
+	require.NoError(t, err)
+	data, err := io.ReadAll(reader)
+	require.NoError(t, reader.Close())
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+
+	require.NoError(t, store.Delete(ctx, "docs/readme.txt"))
+	_, err = store.Get(ctx, "docs/readme.txt")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestLocalStore_RejectsEscapingKeys(t *testing.T) {
+	store := newTestLocalStore(t)
+	err := store.Put(context.Background(), "../outside.txt", strings.NewReader("x"), "")
+	assert.Error(t, err)
+}
+
+func TestLocalStore_Presign(t *testing.T) {
+	store := newTestLocalStore(t)
+	now := time.Now()
+	store.now = func() time.Time { return now }
+
+	raw, err := store.PresignGet(context.Background(), "a/b.png", time.Minute)
+	require.NoError(t, err)
+
+	parsed, err := url.Parse(raw)
+	require.NoError(t, err)
+	assert.Equal(t, "/files/a/b.png", parsed.Path)
+	assert.NoError(t, store.VerifySignature("GET", "a/b.png", parsed.Query()))
+	assert.Error(t, store.VerifySignature("PUT", "a/b.png", parsed.Query()))
+
+	store.now = func() time.Time { return now.Add(2 * time.Minute) }
+	assert.Error(t, store.VerifySignature("GET", "a/b.png", parsed.Query()))
+}
+`,
+		},
+		{
+			Name: "s3_integration_test",
+			Path: "internal/{{ SnakeName }}/s3_integration_test.go",
+			Content: `//go:build integration
+
+package {{ SnakeName }}
+
+import (
+	"context"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Run with: STORAGE_S3_BUCKET=my-bucket go test -tags integration ./internal/{{ SnakeName }}/...
+func TestS3Store_Integration(t *testing.T) {
+	bucket := os.Getenv("STORAGE_S3_BUCKET")
+	if bucket == "" {
+		t.Skip("STORAGE_S3_BUCKET not set")
+	}
+
+	ctx := context.Background()
+	store, err := NewS3Store(ctx, S3Config{
+		Bucket:       bucket,
+		Region:       os.Getenv("AWS_REGION"),
+		Endpoint:     os.Getenv("STORAGE_S3_ENDPOINT"),
+		UsePathStyle: os.Getenv("STORAGE_S3_ENDPOINT") != "",
+	})
+	require.NoError(t, err)
+
+	key := "integration/" + time.Now().Format("20060102150405") + ".txt"
+	require.NoError(t, store.Put(ctx, key, strings.NewReader("hello"), "text/plain"))
+	defer func() { _ = store.Delete(ctx, key) }()
+
+	exists, err := store.Exists(ctx, key)
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	reader, err := store.Get(ctx, key)
+	require.NoError(t, err)
+	data, err := io.ReadAll(reader)
+	require.NoError(t, reader.Close())
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+
+	presigned, err := store.PresignGet(ctx, key, time.Minute)
+	require.NoError(t, err)
+	assert.Contains(t, presigned, key)
+}
+`,
+		},
+	}
+
+	// gRPC service templates: a .proto file and a server stub that
+	// RegisterServices in internal/server/server.go calls
+	templates["proto"] = []ComponentTemplate{
+		{
+			Name: "proto",
+			Path: "proto/{{ SnakeName }}/v1/{{ SnakeName }}.proto",
+			Content: `syntax = "proto3";
+
+package {{ SnakeName }}.v1;
+{% if ModuleName %}
+
+option go_package = "{{ ModuleName }}/proto/{{ SnakeName }}/v1;{{ CamelName|lower }}v1";
+{% endif %}
+
+import "google/protobuf/timestamp.proto";
+
+// {{ TitleName }}Service manages {{ TitleName }}s
+service {{ TitleName }}Service {
+  rpc Get{{ TitleName }}(Get{{ TitleName }}Request) returns (Get{{ TitleName }}Response);
+  rpc List{{ TitleName }}s(List{{ TitleName }}sRequest) returns (List{{ TitleName }}sResponse);
+  rpc Create{{ TitleName }}(Create{{ TitleName }}Request) returns (Create{{ TitleName }}Response);
+  rpc Delete{{ TitleName }}(Delete{{ TitleName }}Request) returns (Delete{{ TitleName }}Response);
+}
+
+message {{ TitleName }} {
+  int64 id = 1;
+  string name = 2;
+  string description = 3;
+  google.protobuf.Timestamp create_time = 4;
+}
+
+message Get{{ TitleName }}Request {
+  int64 id = 1;
+}
+
+message Get{{ TitleName }}Response {
+  {{ TitleName }} {{ SnakeName }} = 1;
+}
+
+message List{{ TitleName }}sRequest {
+  int32 page_size = 1;
+  string page_token = 2;
+}
+
+message List{{ TitleName }}sResponse {
+  repeated {{ TitleName }} {{ SnakeName }}s = 1;
+  string next_page_token = 2;
+}
+
+message Create{{ TitleName }}Request {
+  string name = 1;
+  string description = 2;
+}
+
+message Create{{ TitleName }}Response {
+  {{ TitleName }} {{ SnakeName }} = 1;
+}
+
+message Delete{{ TitleName }}Request {
+  int64 id = 1;
+}
+
+message Delete{{ TitleName }}Response {}`,
+		},
+		{
+			Name: "grpc_server",
+			Path: "internal/server/{{ SnakeName }}_server.go",
+			Content: `package server
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+{% if ModuleName %}
+
+	pb "{{ ModuleName }}/proto/{{ SnakeName }}/v1"
+{% endif %}
+)
+
+// {{ TitleName }}Server implements pb.{{ TitleName }}ServiceServer. The embedded
+// pb.Unimplemented{{ TitleName }}ServiceServer answers RPCs added to the .proto file
+// until they are implemented here.
+type {{ TitleName }}Server struct {
+	pb.Unimplemented{{ TitleName }}ServiceServer
+	// Add your dependencies here (services, repositories, etc.)
+}
+
+// New{{ TitleName }}Server creates the {{ TitleName }} service
+func New{{ TitleName }}Server() *{{ TitleName }}Server {
+	return &{{ TitleName }}Server{}
+}
+
+// Register{{ TitleName }}Server registers the {{ TitleName }} service on s. RegisterServices
+// calls it.
+func Register{{ TitleName }}Server(s grpc.ServiceRegistrar) {
+	pb.Register{{ TitleName }}ServiceServer(s, New{{ TitleName }}Server())
+}
+
+// Get{{ TitleName }} returns a {{ TitleName }} by ID
+func (s *{{ TitleName }}Server) Get{{ TitleName }}(ctx context.Context, req *pb.Get{{ TitleName }}Request) (*pb.Get{{ TitleName }}Response, error) {
+	if req.GetId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "id must be positive")
+	}
+	// TODO: Implement Get{{ TitleName }} logic
+	return nil, status.Error(codes.Unimplemented, "Get{{ TitleName }} is not implemented")
+}
+
+// List{{ TitleName }}s returns a page of {{ TitleName }}s
+func (s *{{ TitleName }}Server) List{{ TitleName }}s(ctx context.Context, req *pb.List{{ TitleName }}sRequest) (*pb.List{{ TitleName }}sResponse, error) {
+	if req.GetPageSize() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "page_size must not be negative")
+	}
+	// TODO: Implement List{{ TitleName }}s logic
+	return nil, status.Error(codes.Unimplemented, "List{{ TitleName }}s is not implemented")
+}
+
+// Create{{ TitleName }} creates a {{ TitleName }}
+func (s *{{ TitleName }}Server) Create{{ TitleName }}(ctx context.Context, req *pb.Create{{ TitleName }}Request) (*pb.Create{{ TitleName }}Response, error) {
+	if req.GetName() == "" {
+		return nil, status.Error(codes.InvalidArgument, "name is required")
+	}
+	// TODO: Implement Create{{ TitleName }} logic
+	return nil, status.Error(codes.Unimplemented, "Create{{ TitleName }} is not implemented")
+}
+
+// Delete{{ TitleName }} deletes a {{ TitleName }} by ID
+func (s *{{ TitleName }}Server) Delete{{ TitleName }}(ctx context.Context, req *pb.Delete{{ TitleName }}Request) (*pb.Delete{{ TitleName }}Response, error) {
+	if req.GetId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "id must be positive")
+	}
+	// TODO: Implement Delete{{ TitleName }} logic
+	return nil, status.Error(codes.Unimplemented, "Delete{{ TitleName }} is not implemented")
+}`,
+		},
+		{
+			Name: "grpc_server_test",
+			Path: "internal/server/{{ SnakeName }}_server_test.go",
+			Content: `package server
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+{% if ModuleName %}
+
+	pb "{{ ModuleName }}/proto/{{ SnakeName }}/v1"
+{% endif %}
+)
+
+// new{{ TitleName }}TestClient serves the {{ TitleName }} service in memory and
+// returns a client connected to it
+func new{{ TitleName }}TestClient(t *testing.T) pb.{{ TitleName }}ServiceClient {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	s := grpc.NewServer()
+	Register{{ TitleName }}Server(s)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return pb.New{{ TitleName }}ServiceClient(conn)
+}
+
+func Test{{ TitleName }}Server_Validation(t *testing.T) {
+	client := new{{ TitleName }}TestClient(t)
+	ctx := context.Background()
+
+	_, err := client.Get{{ TitleName }}(ctx, &pb.Get{{ TitleName }}Request{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = client.Create{{ TitleName }}(ctx, &pb.Create{{ TitleName }}Request{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = client.Delete{{ TitleName }}(ctx, &pb.Delete{{ TitleName }}Request{Id: -1})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func Test{{ TitleName }}Server_Get{{ TitleName }}(t *testing.T) {
+	client := new{{ TitleName }}TestClient(t)
+
+	_, err := client.Get{{ TitleName }}(context.Background(), &pb.Get{{ TitleName }}Request{Id: 1})
+
+	// Since this is not implemented yet, we expect codes.Unimplemented
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+}`,
+		},
+	}
+
+	// Idempotency middleware variant (Idempotency-Key request deduplication)
+	templates["middleware/idempotency"] = []ComponentTemplate{
+		{
+			Name: "idempotency",
+			Path: "internal/middleware/idempotency.go",
+			Content: `package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// IdempotencyHeader is the request header carrying the client-chosen idempotency key
+const IdempotencyHeader = "Idempotency-Key"
+
+// StoredResponse is a response captured for replay
+type StoredResponse struct {
+	RequestHash string              ` + "`json:\"request_hash\"`" + `
+	StatusCode  int                 ` + "`json:\"status_code\"`" + `
+	Header      map[string][]string ` + "`json:\"header\"`" + `
+	Body        []byte              ` + "`json:\"body\"`" + `
+}
+
+// IdempotencyStore persists responses keyed by idempotency key
+type IdempotencyStore interface {
+	// Get returns the stored response for key, or nil when none exists
+	Get(ctx context.Context, key string) (*StoredResponse, error)
+	// Reserve claims key for an in-flight request; it returns false if already claimed
+	Reserve(ctx context.Context, key string, ttl time.Duration) (bool, error)
+	// Save stores the response for key and releases the reservation
+	Save(ctx context.Context, key string, resp *StoredResponse, ttl time.Duration) error
+	// Release drops the reservation without storing a response
+	Release(ctx context.Context, key string) error
+}
+
+// Idempotency replays stored responses for repeated requests with the same Idempotency-Key
+type Idempotency struct {
+	store   IdempotencyStore
+	ttl     time.Duration
+	methods map[string]bool
+}
+
+// NewIdempotency creates idempotency middleware; responses are kept for ttl
+func NewIdempotency(store IdempotencyStore, ttl time.Duration) *Idempotency {
+	return &Idempotency{
+		store: store,
+		ttl:   ttl,
+		methods: map[string]bool{
+			http.MethodPost:  true,
+			http.MethodPut:   true,
+			http.MethodPatch: true,
+		},
+	}
+}
+
+// Handler wraps a net/http handler
+func (m *Idempotency) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(IdempotencyHeader)
+		if key == "" || !m.methods[r.Method] {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "failed to read request body", http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		requestHash := hashRequest(r, body)
+		ctx := r.Context()
+
+		stored, err := m.store.Get(ctx, key)
+		if err != nil {
+			http.Error(w, "idempotency store unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		if stored != nil {
+			if stored.RequestHash != requestHash {
+				http.Error(w, "idempotency key reused with a different request", http.StatusUnprocessableEntity)
+				return
+			}
+			replay(w, stored)
+			return
+		}
+
+		reserved, err := m.store.Reserve(ctx, key, m.ttl)
+		if err != nil {
+			http.Error(w, "idempotency store unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		if !reserved {
+			http.Error(w, "a request with this idempotency key is in progress", http.StatusConflict)
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(recorder, r)
+
+		// Server errors are not cached so clients can retry them
+		if recorder.status >= http.StatusInternalServerError {
+			_ = m.store.Release(ctx, key)
+			return
+		}
+		_ = m.store.Save(ctx, key, &StoredResponse{
+			RequestHash: requestHash,
+			StatusCode:  recorder.status,
+			Header:      w.Header().Clone(),
+			Body:        recorder.body.Bytes(),
+		}, m.ttl)
+	})
+}
+
+func hashRequest(r *http.Request, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(r.Method + " " + r.URL.Path + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func replay(w http.ResponseWriter, stored *StoredResponse) {
+	for name, values := range stored.Header {
+		for _, value := range values {
+			w.Header().Add(name, value)
+		}
+	}
+	w.Header().Set("Idempotent-Replayed", "true")
+	w.WriteHeader(stored.StatusCode)
+	_, _ = w.Write(stored.Body)
+}
+
+// responseRecorder tees the response to the client while capturing it for storage
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(p []byte) (int, error) {
+	r.body.Write(p)
+	return r.ResponseWriter.Write(p)
+}
+
+// MemoryIdempotencyStore is an in-process store suitable for tests and single instances
+type MemoryIdempotencyStore struct {
+	mu       sync.Mutex
+	entries  map[string]memoryEntry
+	reserved map[string]time.Time
+}
+
+type memoryEntry struct {
+	response  *StoredResponse
+	expiresAt time.Time
+}
+
+// NewMemoryIdempotencyStore creates an in-memory store
+func NewMemoryIdempotencyStore() *MemoryIdempotencyStore {
+	return &MemoryIdempotencyStore{
+		entries:  make(map[string]memoryEntry),
+		reserved: make(map[string]time.Time),
+	}
+}
+
+// Get returns the unexpired stored response for key
+func (s *MemoryIdempotencyStore) Get(ctx context.Context, key string) (*StoredResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[key]
+	if !ok || time.Now().After(entry.expiresAt) {
+		delete(s.entries, key)
+		return nil, nil
+	}
+	return entry.response, nil
+}
+
+// Reserve claims key unless another unexpired reservation holds it
+func (s *MemoryIdempotencyStore) Reserve(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if expiresAt, ok := s.reserved[key]; ok && time.Now().Before(expiresAt) {
+		return false, nil
+	}
+	s.reserved[key] = time.Now().Add(ttl)
+	return true, nil
+}
+
+// Save stores the response and releases the reservation
+func (s *MemoryIdempotencyStore) Save(ctx context.Context, key string, resp *StoredResponse, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[key] = memoryEntry{response: resp, expiresAt: time.Now().Add(ttl)}
+	delete(s.reserved, key)
+	return nil
+}
+
+// Release drops the reservation for key
+func (s *MemoryIdempotencyStore) Release(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.reserved, key)
+	return nil
+}
+`,
+		},
+		{
+			Name: "idempotency_framework",
+			Path: "internal/middleware/idempotency_{{ Framework }}.go",
+			Content: `package middleware
+{% if IsGin %}
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Gin adapts the idempotency middleware to gin
+func (m *Idempotency) Gin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		called := false
+		m.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			called = true
+			c.Request = r
+			c.Writer = &ginRecorder{ResponseWriter: c.Writer, w: w}
+			c.Next()
+		})).ServeHTTP(c.Writer, c.Request)
+
+		if !called {
+			c.Abort()
+		}
+	}
+}
+
+// ginRecorder routes gin writes through the idempotency recorder
+type ginRecorder struct {
+	gin.ResponseWriter
+	w http.ResponseWriter
+}
+
+func (g *ginRecorder) WriteHeader(status int) {
+	g.w.WriteHeader(status)
+}
+
+func (g *ginRecorder) Write(p []byte) (int, error) {
+	return g.w.Write(p)
+}
+
+func (g *ginRecorder) WriteString(s string) (int, error) {
+	return g.w.Write([]byte(s))
+}
+{% elif IsEcho %}
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Echo adapts the idempotency middleware to echo
+func (m *Idempotency) Echo() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			var nextErr error
+			original := c.Response().Writer
+			m.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				c.SetRequest(r)
+				c.Response().Writer = w
+				nextErr = next(c)
+			})).ServeHTTP(original, c.Request())
+			c.Response().Writer = original
+			return nextErr
+		}
+	}
+}
+{% else %}
+// The chi router uses standard net/http middleware, so Idempotency.Handler
+// can be passed to r.Use directly:
+//
+//	r.Use(middleware.NewIdempotency(store, 24*time.Hour).Handler)
+{% endif %}`,
+		},
+		{
+			Name: "idempotency_redis",
+			Path: "internal/middleware/idempotency_redis.go",
+			Content: `package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisIdempotencyStore shares idempotency state across instances via Redis
+type RedisIdempotencyStore struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewRedisIdempotencyStore creates a Redis-backed store with keys under prefix
+func NewRedisIdempotencyStore(client *redis.Client, prefix string) *RedisIdempotencyStore {
+	if prefix == "" {
+		prefix = "idempotency:"
+	}
+	return &RedisIdempotencyStore{client: client, prefix: prefix}
+}
+
+// Get returns the stored response for key
+func (s *RedisIdempotencyStore) Get(ctx context.Context, key string) (*StoredResponse, error) {
+	data, err := s.client.Get(ctx, s.prefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read idempotency key: %w", err)
+	}
+
+	var resp StoredResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, fmt.Errorf("failed to decode stored response: %w", err)
+	}
+	return &resp, nil
+}
+
+// Reserve claims key with SET NX
+func (s *RedisIdempotencyStore) Reserve(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	ok, err := s.client.SetNX(ctx, s.prefix+key+":lock", 1, ttl).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to reserve idempotency key: %w", err)
+	}
+	return ok, nil
+}
+
+// Save stores the response and releases the reservation
+func (s *RedisIdempotencyStore) Save(ctx context.Context, key string, resp *StoredResponse, ttl time.Duration) error {
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return fmt.Errorf("failed to encode response: %w", err)
+	}
+
+	pipe := s.client.TxPipeline()
+	pipe.Set(ctx, s.prefix+key, data, ttl)
+	pipe.Del(ctx, s.prefix+key+":lock")
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to store response: %w", err)
+	}
+	return nil
+}
+
+// Release drops the reservation for key
+func (s *RedisIdempotencyStore) Release(ctx context.Context, key string) error {
+	return s.client.Del(ctx, s.prefix+key+":lock").Err()
+}
+`,
+		},
+		{
+			Name: "idempotency_test",
+			Path: "internal/middleware/idempotency_test.go",
+			Content: `package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newIdempotentServer(calls *int32) http.Handler {
+	store := NewMemoryIdempotencyStore()
+	return NewIdempotency(store, time.Hour).Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(calls, 1)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, ` + "`{\"order\":%d}`" + `, n)
+	}))
+}
+
+func doRequest(handler http.Handler, method, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/orders", strings.NewReader(body))
+	if key != "" {
+		req.Header.Set(IdempotencyHeader, key)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestIdempotency_ReplaysStoredResponse(t *testing.T) {
+	var calls int32
+	handler := newIdempotentServer(&calls)
+
+	first := doRequest(handler, http.MethodPost, "abc", ` + "`{\"item\":1}`" + `)
+	second := doRequest(handler, http.MethodPost, "abc", ` + "`{\"item\":1}`" + `)
+
+	assert.Equal(t, http.StatusCreated, first.Code)
+	assert.Equal(t, http.StatusCreated, second.Code)
+	assert.Equal(t, first.Body.String(), second.Body.String())
+	assert.Equal(t, "true", second.Header().Get("Idempotent-Replayed"))
+	assert.Equal(t, int32(1), calls)
+}
+
+func TestIdempotency_RejectsKeyReuseWithDifferentPayload(t *testing.T) {
+	var calls int32
+	handler := newIdempotentServer(&calls)
+
+	doRequest(handler, http.MethodPost, "abc", ` + "`{\"item\":1}`" + `)
+	rec := doRequest(handler, http.MethodPost, "abc", ` + "`{\"item\":2}`" + `)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Equal(t, int32(1), calls)
+}
+
+func TestIdempotency_PassesThroughWithoutKey(t *testing.T) {
+	var calls int32
+	handler := newIdempotentServer(&calls)
+
+	doRequest(handler, http.MethodPost, "", "{}")
+	doRequest(handler, http.MethodPost, "", "{}")
+	doRequest(handler, http.MethodGet, "abc", "")
+	doRequest(handler, http.MethodGet, "abc", "")
+
+	assert.Equal(t, int32(4), calls)
+}
+
+func TestIdempotency_DoesNotStoreServerErrors(t *testing.T) {
+	var calls int32
+	handler := NewIdempotency(NewMemoryIdempotencyStore(), time.Hour).Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+
+	doRequest(handler, http.MethodPost, "abc", "{}")
+	doRequest(handler, http.MethodPost, "abc", "{}")
+
+	assert.Equal(t, int32(2), calls)
+}
+
+func TestMemoryIdempotencyStore_Reserve(t *testing.T) {
+	store := NewMemoryIdempotencyStore()
+	ctx := context.Background()
+
+	ok, err := store.Reserve(ctx, "k", time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = store.Reserve(ctx, "k", time.Minute)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	assert.NoError(t, store.Release(ctx, "k"))
+	ok, _ = store.Reserve(ctx, "k", time.Minute)
+	assert.True(t, ok)
+}
+`,
+		},
+	}
+
+	// Audit trail middleware variant (records mutating API requests)
+	templates["middleware/audit"] = []ComponentTemplate{
+		{
+			Name: "audit_store",
+			Path: "internal/audit/audit.go",
+			Content: `// Package audit records mutating API requests for compliance and debugging
+package audit
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Entry is a single audited request
+type Entry struct {
+	ID            int64
+	Actor         string
+	Method        string
+	Route         string
+	PayloadDigest string // SHA-256 of the request body, hex-encoded
+	Status        int
+	Result        string // success or failure
+	CreatedAt     time.Time
+}
+
+// Store persists audit entries
+type Store interface {
+	Record(ctx context.Context, entry Entry) error
+}
+
+// SQLStore writes audit entries to the audit_logs table
+type SQLStore struct {
+	db      *sql.DB
+	dialect string
+}
+
+// NewSQLStore creates a store for db; dialect selects placeholder syntax (postgres uses $n, others ?)
+func NewSQLStore(db *sql.DB, dialect string) *SQLStore {
+	return &SQLStore{db: db, dialect: dialect}
+}
+
+// Record inserts an audit entry
+func (s *SQLStore) Record(ctx context.Context, entry Entry) error {
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now().UTC()
+	}
+
+	query := "INSERT INTO audit_logs (actor, method, route, payload_digest, status, result, created_at) VALUES (" + s.placeholders(7) + ")"
+	_, err := s.db.ExecContext(ctx, query,
+		entry.Actor, entry.Method, entry.Route, entry.PayloadDigest, entry.Status, entry.Result, entry.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record audit entry: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLStore) placeholders(n int) string {
+	parts := make([]string, n)
+	for i := range parts {
+		if s.dialect == "postgres" {
+			parts[i] = fmt.Sprintf("$%d", i+1)
+		} else {
+			parts[i] = "?"
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+`,
+		},
+		{
+			Name: "audit_model",
+			Path: "internal/models/audit_log.go",
+			Content: `package models
+
+import "time"
+
+// AuditLog is a persisted audit trail entry for a mutating API request
+type AuditLog struct {
+	ID            int64     ` + "`json:\"id\" db:\"id\"`" + `
+	Actor         string    ` + "`json:\"actor\" db:\"actor\"`" + `
+	Method        string    ` + "`json:\"method\" db:\"method\"`" + `
+	Route         string    ` + "`json:\"route\" db:\"route\"`" + `
+	PayloadDigest string    ` + "`json:\"payload_digest\" db:\"payload_digest\"`" + `
+	Status        int       ` + "`json:\"status\" db:\"status\"`" + `
+	Result        string    ` + "`json:\"result\" db:\"result\"`" + `
+	CreatedAt     time.Time ` + "`json:\"created_at\" db:\"created_at\"`" + `
+}
+
+// TableName returns the table name for AuditLog
+func (AuditLog) TableName() string {
+	return "audit_logs"
+}
+`,
+		},
+		{
+			Name: "audit_migration",
+			Path: "migrations/{{ Timestamp }}_create_audit_logs.sql",
+			Content: `-- Migration: create_audit_logs
+-- Created: {{ Year }}
+
+-- +goose Up
+-- SQL in this section is executed when the migration is applied.
+
+CREATE TABLE IF NOT EXISTS audit_logs (
+    id BIGSERIAL PRIMARY KEY,
+    actor VARCHAR(255) NOT NULL,
+    method VARCHAR(16) NOT NULL,
+    route VARCHAR(512) NOT NULL,
+    payload_digest CHAR(64) NOT NULL,
+    status INTEGER NOT NULL,
+    result VARCHAR(16) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_logs_actor ON audit_logs(actor);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_created_at ON audit_logs(created_at);
+
+-- +goose Down
+-- SQL in this section is executed when the migration is rolled back.
+
+DROP TABLE IF EXISTS audit_logs;`,
+		},
+		{
+			Name: "audit_middleware",
+			Path: "internal/middleware/audit.go",
+			Content: `package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+{% if HasSlog %}	"log/slog"
+{% else %}	"log"
+{% endif %}	"net/http"
+{% if IsGin %}
+	"github.com/gin-gonic/gin"
+{% elif IsEcho %}
+	"github.com/labstack/echo/v4"
+{% endif %}
+{% if ModuleName %}
+	"{{ ModuleName }}/internal/audit"
+{% endif %}
+)
+
+// ActorFunc extracts the authenticated actor from a request
+type ActorFunc func(r *http.Request) string
+
+// HeaderActor reads the actor from the X-User-ID header
+func HeaderActor(r *http.Request) string {
+	if actor := r.Header.Get("X-User-ID"); actor != "" {
+		return actor
+	}
+	return "anonymous"
+}
+
+// Audit records mutating requests (POST, PUT, PATCH, DELETE) to an audit store
+type Audit struct {
+	store audit.Store
+	actor ActorFunc
+}
+
+// NewAudit creates audit middleware; actor defaults to HeaderActor
+func NewAudit(store audit.Store, actor ActorFunc) *Audit {
+	if actor == nil {
+		actor = HeaderActor
+	}
+	return &Audit{store: store, actor: actor}
+}
+
+// Handler wraps a net/http handler
+func (a *Audit) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !isMutating(r.Method) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		digest := a.digestBody(r)
+		recorder := &auditStatusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(recorder, r)
+		a.record(r, digest, recorder.status)
+	})
+}
+{% if IsGin %}
+// Gin adapts the audit middleware to gin
+func (a *Audit) Gin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !isMutating(c.Request.Method) {
+			c.Next()
+			return
+		}
+
+		digest := a.digestBody(c.Request)
+		c.Next()
+		a.record(c.Request, digest, c.Writer.Status())
+	}
+}
+{% elif IsEcho %}
+// Echo adapts the audit middleware to echo
+func (a *Audit) Echo() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if !isMutating(c.Request().Method) {
+				return next(c)
+			}
+
+			digest := a.digestBody(c.Request())
+			err := next(c)
+			status := c.Response().Status
+			if err != nil {
+				if httpErr, ok := err.(*echo.HTTPError); ok {
+					status = httpErr.Code
+				} else {
+					status = http.StatusInternalServerError
+				}
+			}
+			a.record(c.Request(), digest, status)
+			return err
+		}
+	}
+}
+{% endif %}
+// digestBody hashes the request body and restores it for downstream handlers
+func (a *Audit) digestBody(r *http.Request) string {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		body = nil
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
+func (a *Audit) record(r *http.Request, digest string, status int) {
+	result := "success"
+	if status >= http.StatusBadRequest {
+		result = "failure"
+	}
+
+	entry := audit.Entry{
+		Actor:         a.actor(r),
+		Method:        r.Method,
+		Route:         r.URL.Path,
+		PayloadDigest: digest,
+		Status:        status,
+		Result:        result,
+	}
+
+	// Auditing must not fail the request, and must outlive its cancellation
+{% if HasWithoutCancel %}	if err := a.store.Record(context.WithoutCancel(r.Context()), entry); err != nil {
+{% else %}	if err := a.store.Record(context.Background(), entry); err != nil {
+{% endif %}{% if HasSlog %}		slog.Error("failed to record audit entry", "error", err, "route", entry.Route)
+{% else %}		log.Printf("failed to record audit entry for %s: %v", entry.Route, err)
+{% endif %}	}
+}
+
+func isMutating(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// auditStatusRecorder captures the response status code
+type auditStatusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *auditStatusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+`,
+		},
+		{
+			Name: "audit_test",
+			Path: "internal/middleware/audit_test.go",
+			Content: `package middleware
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+{% if ModuleName %}
+	"{{ ModuleName }}/internal/audit"
+{% endif %}
+)
+
+type fakeAuditStore struct {
+	mu      sync.Mutex
+	entries []audit.Entry
+}
+
+func (f *fakeAuditStore) Record(ctx context.Context, entry audit.Entry) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.entries = append(f.entries, entry)
+	return nil
+}
+
+func TestAudit_RecordsMutatingRequests(t *testing.T) {
+	store := &fakeAuditStore{}
+	var seenBody string
+	handler := NewAudit(store, nil).Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		seenBody = string(body)
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(` + "`{\"item\":1}`" + `))
+	req.Header.Set("X-User-ID", "user-42")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, ` + "`{\"item\":1}`" + `, seenBody, "body should be restored for the handler")
+	require.Len(t, store.entries, 1)
+	entry := store.entries[0]
+	assert.Equal(t, "user-42", entry.Actor)
+	assert.Equal(t, "/orders", entry.Route)
+	assert.Equal(t, http.StatusCreated, entry.Status)
+	assert.Equal(t, "success", entry.Result)
+	assert.Len(t, entry.PayloadDigest, 64)
+}
+
+func TestAudit_RecordsFailures(t *testing.T) {
+	store := &fakeAuditStore{}
+	handler := NewAudit(store, nil).Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodDelete, "/orders/1", nil))
+
+	require.Len(t, store.entries, 1)
+	assert.Equal(t, "anonymous", store.entries[0].Actor)
+	assert.Equal(t, "failure", store.entries[0].Result)
+}
+
+func TestAudit_IgnoresReads(t *testing.T) {
+	store := &fakeAuditStore{}
+	handler := NewAudit(store, nil).Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/orders", nil))
+
+	assert.Empty(t, store.entries)
+}
+`,
+		},
+	}
+
+	templates["resolver"] = []ComponentTemplate{
+		{
+			Name: "schema",
+			Path: "graph/{{ SnakeName }}.graphqls",
+			Content: `type {{ TitleName }} {
+  id: ID!
+  name: String!
+  description: String
+  createdAt: String!
+}
+
+input New{{ TitleName }} {
+  name: String!
+  description: String
+}
+
+extend type Query {
+  {{ CamelName }}(id: ID!): {{ TitleName }}
+  {{ CamelName }}s: [{{ TitleName }}!]!
+}
+
+extend type Mutation {
+  create{{ TitleName }}(input: New{{ TitleName }}!): {{ TitleName }}!
+  delete{{ TitleName }}(id: ID!): Boolean!
+}
+`,
+		},
+		{
+			Name: "store",
+			Path: "graph/{{ SnakeName }}_store.go",
+			Content: `package graph
+
+import (
+{% if HasSlices %}	"slices"
+{% endif %}	"strconv"
+	"sync"
+	"time"
+
+	"{{ ModuleName }}/graph/model"
+)
+
+// {{ TitleName }}Store keeps {{ TitleName }}s in memory. Replace it with a
+// database-backed store before going to production.
+type {{ TitleName }}Store struct {
+	mu     sync.RWMutex
+	nextID int
+	items  []*model.{{ TitleName }}
+}
+
+// Get returns the {{ TitleName }} with the given ID, or nil if there is none
+func (s *{{ TitleName }}Store) Get(id string) *model.{{ TitleName }} {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, item := range s.items {
+		if item.ID == id {
+			return item
+		}
+	}
+	return nil
+}
+
+// List returns all {{ TitleName }}s, oldest first
+func (s *{{ TitleName }}Store) List() []*model.{{ TitleName }} {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+{% if HasSlices %}	return slices.Clone(s.items)
+{% else %}	return append([]*model.{{ TitleName }}(nil), s.items...)
+{% endif %}}
+
+// Create adds a {{ TitleName }} and returns it
+func (s *{{ TitleName }}Store) Create(input model.New{{ TitleName }}) *model.{{ TitleName }} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextID++
+	item := &model.{{ TitleName }}{
+		ID:          strconv.Itoa(s.nextID),
+		Name:        input.Name,
+		Description: input.Description,
+		CreatedAt:   time.Now().UTC().Format(time.RFC3339),
+	}
+	s.items = append(s.items, item)
+	return item
+}
+
+// Delete removes the {{ TitleName }} with the given ID and reports whether it existed
+func (s *{{ TitleName }}Store) Delete(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+{% if HasSlices %}	i := slices.IndexFunc(s.items, func(item *model.{{ TitleName }}) bool { return item.ID == id })
+	if i < 0 {
+		return false
+	}
+	s.items = slices.Delete(s.items, i, i+1)
+	return true
+{% else %}	for i, item := range s.items {
+		if item.ID == id {
+			s.items = append(s.items[:i], s.items[i+1:]...)
+			return true
+		}
+	}
+	return false
+{% endif %}}
+`,
+		},
+		{
+			Name: "resolvers",
+			Path: "graph/{{ SnakeName }}.resolvers.go",
+			Content: `package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+
+import (
+	"context"
+	"errors"
+
+	"{{ ModuleName }}/graph/model"
+)
+
+// Create{{ TitleName }} is the resolver for the create{{ TitleName }} field.
+func (r *mutationResolver) Create{{ TitleName }}(ctx context.Context, input model.New{{ TitleName }}) (*model.{{ TitleName }}, error) {
+	if input.Name == "" {
+		return nil, errors.New("name is required")
+	}
+	return r.{{ CamelName }}Store.Create(input), nil
+}
+
+// Delete{{ TitleName }} is the resolver for the delete{{ TitleName }} field.
+func (r *mutationResolver) Delete{{ TitleName }}(ctx context.Context, id string) (bool, error) {
+	return r.{{ CamelName }}Store.Delete(id), nil
+}
+
+// {{ TitleName }} is the resolver for the {{ CamelName }} field.
+func (r *queryResolver) {{ TitleName }}(ctx context.Context, id string) (*model.{{ TitleName }}, error) {
+	return r.{{ CamelName }}Store.Get(id), nil
+}
+
+// {{ TitleName }}s is the resolver for the {{ CamelName }}s field.
+func (r *queryResolver) {{ TitleName }}s(ctx context.Context) ([]*model.{{ TitleName }}, error) {
+	return r.{{ CamelName }}Store.List(), nil
+}
+`,
+		},
+	}
+
+	// Job handlers of the worker stack, registered in internal/jobs/jobs.go
+	templates["job"] = []ComponentTemplate{
+		{
+			Name: "job",
+			Path: "internal/jobs/{{ SnakeName }}.go",
+			Content: `package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+{% if HasSlog %}	"log/slog"
+{% else %}	"log"
+{% endif %}
+	"{{ ModuleName }}/internal/worker"
+)
+
+// {{ TitleName }}JobType is the type {{ TitleName }} jobs are enqueued with
+const {{ TitleName }}JobType = "{{ KebabName }}"
+
+// {{ TitleName }}Payload is the JSON payload of a {{ TitleName }} job
+type {{ TitleName }}Payload struct {
+	ID string ` + "`json:\"id\"`" + `
+}
+
+// Handle{{ TitleName }} processes a {{ TitleName }} job. Returned errors retry the job
+// with backoff, except those marked with worker.Permanent.
+func Handle{{ TitleName }}(ctx context.Context, payload []byte) error {
+	var p {{ TitleName }}Payload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return worker.Permanent(fmt.Errorf("invalid {{ KebabName }} payload: %w", err))
+	}
+	if p.ID == "" {
+		return worker.Permanent(errors.New("{{ KebabName }} payload has no id"))
+	}
+
+	// TODO: Implement {{ TitleName }} job logic
+{% if HasSlog %}	slog.InfoContext(ctx, "processed {{ KebabName }} job", "id", p.ID)
+{% else %}	log.Printf("processed {{ KebabName }} job %s", p.ID)
+{% endif %}
+	return nil
+}
+`,
+		},
+		{
+			Name: "job_test",
+			Path: "internal/jobs/{{ SnakeName }}_test.go",
+			Content: `package jobs
+
+import (
+	"context"
+	"testing"
+
+	"{{ ModuleName }}/internal/worker"
+)
+
+func TestHandle{{ TitleName }}(t *testing.T) {
+	tests := []struct {
+		name      string
+		payload   string
+		wantErr   bool
+		permanent bool
+	}{
+		{name: "valid payload", payload: ` + "`{\"id\":\"42\"}`" + `},
+		{name: "malformed payload", payload: ` + "`{`" + `, wantErr: true, permanent: true},
+		{name: "missing id", payload: ` + "`{}`" + `, wantErr: true, permanent: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Handle{{ TitleName }}(context.Background(), []byte(tt.payload))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Handle{{ TitleName }}() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if worker.IsPermanent(err) != tt.permanent {
+				t.Errorf("Handle{{ TitleName }}() permanent = %v, want %v", worker.IsPermanent(err), tt.permanent)
+			}
+		})
+	}
+}
+`,
+		},
 	}
 
 	return templates
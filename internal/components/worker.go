@@ -0,0 +1,61 @@
+package components
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// JobsRegisterFile holds Register, which registers the job handlers of the
+// worker stack with the worker's mux
+const JobsRegisterFile = "internal/jobs/jobs.go"
+
+// registerJobsFunc finds the signature of Register, capturing the name of
+// its mux parameter
+var registerJobsFunc = regexp.MustCompile(`(?m)^func Register\(\s*(\w+)\s+\*worker\.Mux\)\s*\{[^\n]*\n`)
+
+// wireJob registers the handler of a job component in Register. It returns
+// the files changed.
+func (g *Generator) wireJob(opts GenerateOptions, variables map[string]any) ([]string, error) {
+	title := variables["TitleName"].(string)
+	changed, err := addJobRegistration(filepath.Join(opts.OutputDir, filepath.FromSlash(JobsRegisterFile)), title+"JobType", "Handle"+title)
+	if err != nil {
+		return nil, err
+	}
+	if changed {
+		return []string{JobsRegisterFile}, nil
+	}
+	return nil, nil
+}
+
+// addJobRegistration adds a Handle call for handler at the end of Register.
+// Projects without the function, or already registering handler, are left
+// alone.
+func addJobRegistration(path, jobType, handler string) (bool, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	match := registerJobsFunc.FindSubmatchIndex(data)
+	if match == nil || strings.Contains(string(data), ", "+handler+")") {
+		return false, nil
+	}
+	end := closingBrace.FindIndex(data[match[1]:])
+	if end == nil {
+		return false, nil
+	}
+	at := match[1] + end[0]
+	mux := string(data[match[2]:match[3]])
+	call := fmt.Sprintf("\t%s.Handle(%s, %s)\n", mux, jobType, handler)
+	content := string(data[:at]) + call + string(data[at:])
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return false, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return true, nil
+}
@@ -0,0 +1,185 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// keyProfiles is the key of the profiles in the settings file
+const keyProfiles = "profiles"
+
+// deprecatedKeys maps settings and profile fields that were renamed to
+// their replacement. Load ignores the old keys; Check tells how to migrate.
+// No key has been renamed yet.
+var deprecatedKeys = map[string]string{}
+
+// Problem is a key or value of the settings file that Load ignores or
+// rejects
+type Problem struct {
+	Line    int
+	Column  int
+	Key     string // Path of the key, e.g. profiles.work.template
+	Message string
+}
+
+// String formats the problem as line:column: key: message
+func (p Problem) String() string {
+	return fmt.Sprintf("%d:%d: %s: %s", p.Line, p.Column, p.Key, p.Message)
+}
+
+// Check reports the problems of the settings file at path: unknown keys,
+// which Load silently ignores, with the setting or profile field they were
+// probably meant as, and invalid values. A missing file has none.
+func Check(path string) ([]Problem, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read settings file %s: %w", path, err)
+	}
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("failed to parse settings file %s: %w", path, err)
+	}
+	if len(document.Content) == 0 {
+		return nil, nil
+	}
+
+	root := document.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return []Problem{{Line: root.Line, Column: root.Column, Message: "the settings must be a mapping of keys to values"}}, nil
+	}
+	var problems []Problem
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, value := root.Content[i], root.Content[i+1]
+		// Viper matches keys regardless of case
+		name := strings.ToLower(key.Value)
+		switch {
+		case name == keyProfiles:
+			problems = append(problems, checkProfiles(value)...)
+		case deprecatedKeys[name] != "" && slices.Contains(Keys, deprecatedKeys[name]):
+			problems = append(problems, deprecated(key, key.Value, value, "gogo config set"))
+		case slices.Contains(Keys, name):
+			problems = append(problems, checkValue(name, value, Validate)...)
+		case slices.Contains(ProfileKeys, name):
+			problems = append(problems, Problem{
+				Line: key.Line, Column: key.Column, Key: key.Value,
+				Message: fmt.Sprintf("ignored: %s is a profile field, not a setting; move it to a profile with gogo config profile set <name> %s %s", name, name, value.Value),
+			})
+		default:
+			problems = append(problems, Problem{
+				Line: key.Line, Column: key.Column, Key: key.Value,
+				Message: "ignored: unknown setting" + suggest(name, append(slices.Clone(Keys), keyProfiles)),
+			})
+		}
+	}
+	return problems, nil
+}
+
+// checkProfiles reports the problems of the profiles mapping
+func checkProfiles(profiles *yaml.Node) []Problem {
+	if profiles.Kind != yaml.MappingNode {
+		return []Problem{{Line: profiles.Line, Column: profiles.Column, Key: keyProfiles, Message: "must map profile names to their fields"}}
+	}
+	var problems []Problem
+	for i := 0; i+1 < len(profiles.Content); i += 2 {
+		name, fields := profiles.Content[i], profiles.Content[i+1]
+		path := keyProfiles + "." + name.Value
+		if !profileNamePattern.MatchString(name.Value) {
+			problems = append(problems, Problem{Line: name.Line, Column: name.Column, Key: path, Message: "invalid profile name (use lower-case letters, digits, '_' and '-')"})
+			continue
+		}
+		if fields.Kind != yaml.MappingNode {
+			problems = append(problems, Problem{Line: fields.Line, Column: fields.Column, Key: path, Message: "must map profile fields to their values"})
+			continue
+		}
+		for j := 0; j+1 < len(fields.Content); j += 2 {
+			key, value := fields.Content[j], fields.Content[j+1]
+			field := strings.ToLower(key.Value)
+			if replacement := deprecatedKeys[field]; replacement != "" && slices.Contains(ProfileKeys, replacement) {
+				problems = append(problems, deprecated(key, path+"."+key.Value, value, "gogo config profile set "+name.Value))
+				continue
+			}
+			if !slices.Contains(ProfileKeys, field) {
+				problems = append(problems, Problem{
+					Line: key.Line, Column: key.Column, Key: path + "." + key.Value,
+					Message: "ignored: unknown profile field" + suggest(field, ProfileKeys),
+				})
+				continue
+			}
+			for _, problem := range checkValue(field, value, ValidateProfile) {
+				problem.Key = path + "." + problem.Key
+				problems = append(problems, problem)
+			}
+		}
+	}
+	return problems
+}
+
+// deprecated reports the renamed key at path, with the set command that
+// moves its value to the replacement
+func deprecated(key *yaml.Node, path string, value *yaml.Node, set string) Problem {
+	replacement := deprecatedKeys[strings.ToLower(key.Value)]
+	return Problem{
+		Line: key.Line, Column: key.Column, Key: path,
+		Message: fmt.Sprintf("ignored: deprecated, renamed to %s; migrate with %s %s %s and remove %s", replacement, set, replacement, value.Value, key.Value),
+	}
+}
+
+// checkValue reports the value of key unless validate accepts it
+func checkValue(key string, value *yaml.Node, validate func(key, value string) error) []Problem {
+	if value.Kind != yaml.ScalarNode {
+		return []Problem{{Line: value.Line, Column: value.Column, Key: key, Message: "must be a single value"}}
+	}
+	if err := validate(key, value.Value); err != nil {
+		// The problem names the key already
+		message := strings.TrimPrefix(err.Error(), key+": ")
+		return []Problem{{Line: value.Line, Column: value.Column, Key: key, Message: message}}
+	}
+	return nil
+}
+
+// suggest names the closest of the valid keys, or lists them all
+func suggest(key string, valid []string) string {
+	best, bestDistance := "", len(key)/3+2
+	for _, candidate := range valid {
+		if d := editDistance(key, candidate); d < bestDistance {
+			best, bestDistance = candidate, d
+		}
+	}
+	if best != "" {
+		return fmt.Sprintf(" (did you mean %s?)", best)
+	}
+	return " (valid: " + strings.Join(valid, ", ") + ")"
+}
+
+// editDistance returns the edits, counting a swap of adjacent characters as
+// one, that turn a into b
+func editDistance(a, b string) int {
+	d := make([][]int, len(a)+1)
+	for i := range d {
+		d[i] = make([]int, len(b)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(a)][len(b)]
+}
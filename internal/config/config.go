@@ -0,0 +1,239 @@
+// Package config reads and writes gogo's own settings, by default in
+// ~/.gogo/config.yaml: the author, email, license, Go version, framework and
+// CI provider new projects default to, and the database gogo keeps its
+// templates in. Each setting can be overridden with an environment variable
+// named after its key, such as GOGO_GO_VERSION for go_version. Named profiles
+// in the same file bundle the defaults of a team or client.
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/viper"
+	"github.com/user/gogo/internal/cicd"
+	"github.com/user/gogo/internal/components"
+	"github.com/user/gogo/internal/validate"
+)
+
+// EnvConfigPath overrides the location of the settings file
+const EnvConfigPath = "GOGO_CONFIG"
+
+// envPrefix prefixes the environment variables overriding settings
+const envPrefix = "GOGO"
+
+// Keys of the settings
+const (
+	KeyAuthor     = "author"
+	KeyEmail      = "email"
+	KeyLicense    = "license"
+	KeyGoVersion  = "go_version"
+	KeyFramework  = "framework"
+	KeyDBPath     = "db_path"
+	KeyCIProvider = "ci_provider"
+	KeyProfile    = "profile"
+)
+
+// Keys lists the settings in the order they are listed
+var Keys = []string{KeyAuthor, KeyEmail, KeyLicense, KeyGoVersion, KeyFramework, KeyDBPath, KeyCIProvider, KeyProfile}
+
+// Config holds gogo's settings; empty fields are unset and leave gogo's own
+// defaults in place
+type Config struct {
+	Author     string `mapstructure:"author"`
+	Email      string `mapstructure:"email"`
+	License    string `mapstructure:"license"`
+	GoVersion  string `mapstructure:"go_version"`
+	Framework  string `mapstructure:"framework"`   // HTTP framework of init and add, e.g. chi
+	DBPath     string `mapstructure:"db_path"`     // Database used when --db-path is not given
+	CIProvider string `mapstructure:"ci_provider"` // CI provider of generated pipelines, e.g. gitlab
+	Profile    string `mapstructure:"profile"`     // Profile gogo init uses without --profile
+
+	Profiles map[string]Profile `mapstructure:"profiles"`
+}
+
+// DefaultPath returns the location of the settings file
+func DefaultPath() string {
+	if path := os.Getenv(EnvConfigPath); path != "" {
+		return path
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return ".gogo-config.yaml"
+	}
+	return filepath.Join(homeDir, ".gogo", "config.yaml")
+}
+
+// EnvName returns the environment variable overriding the setting key
+func EnvName(key string) string {
+	return envPrefix + "_" + strings.ToUpper(key)
+}
+
+// Load reads the settings of path, overridden by their environment
+// variables; a missing file sets nothing
+func Load(path string) (Config, error) {
+	v, err := readFile(path)
+	if err != nil {
+		return Config{}, err
+	}
+	v.SetEnvPrefix(envPrefix)
+	for _, key := range Keys {
+		if err := v.BindEnv(key); err != nil {
+			return Config{}, fmt.Errorf("failed to bind %s: %w", EnvName(key), err)
+		}
+	}
+
+	var config Config
+	if err := v.Unmarshal(&config); err != nil {
+		return Config{}, fmt.Errorf("failed to parse settings file %s: %w", path, err)
+	}
+	for _, key := range Keys {
+		if err := Validate(key, config.Get(key)); err != nil {
+			return Config{}, fmt.Errorf("invalid setting in %s or %s: %w", path, EnvName(key), err)
+		}
+	}
+	for name, profile := range config.Profiles {
+		for _, key := range ProfileKeys {
+			if err := ValidateProfile(key, profile.Get(key)); err != nil {
+				return Config{}, fmt.Errorf("invalid profile %s in %s: %w", name, path, err)
+			}
+		}
+	}
+	if config.Profile != "" {
+		if _, ok := config.Profiles[config.Profile]; !ok {
+			return Config{}, fmt.Errorf("invalid setting in %s or %s: profile %q does not exist", path, EnvName(KeyProfile), config.Profile)
+		}
+	}
+	config.DBPath = expandHome(config.DBPath)
+	return config, nil
+}
+
+// Set writes the setting key to the settings file at path, creating it; an
+// empty value removes the setting
+func Set(path, key, value string) error {
+	if err := Validate(key, value); err != nil {
+		return err
+	}
+	v, err := readFile(path)
+	if err != nil {
+		return err
+	}
+
+	if key == KeyProfile && value != "" {
+		if _, ok := v.GetStringMap("profiles")[value]; !ok {
+			return fmt.Errorf("profile %q does not exist (create it with gogo config profile set %s <key> <value>)", value, value)
+		}
+	}
+
+	settings := v.AllSettings()
+	if value == "" {
+		delete(settings, key)
+	} else {
+		settings[key] = value
+	}
+	return writeFile(path, settings)
+}
+
+// writeFile replaces the settings file at path with settings, creating it
+func writeFile(path string, settings map[string]any) error {
+	// Viper cannot unset a key, so the remaining settings are written afresh
+	updated := viper.New()
+	updated.SetConfigType("yaml")
+	for name, setting := range settings {
+		updated.Set(name, setting)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", path, err)
+	}
+	if err := updated.WriteConfigAs(path); err != nil {
+		return fmt.Errorf("failed to write settings file %s: %w", path, err)
+	}
+	return nil
+}
+
+// Validate checks a value of the setting key; empty values unset it
+func Validate(key, value string) error {
+	if !slices.Contains(Keys, key) {
+		return fmt.Errorf("unknown setting %q (valid: %s)", key, strings.Join(Keys, ", "))
+	}
+	if value == "" {
+		return nil
+	}
+	switch key {
+	case KeyEmail:
+		if !strings.Contains(value, "@") {
+			return fmt.Errorf("%s must be an email address, not %q", key, value)
+		}
+	case KeyGoVersion:
+		if err := validate.ValidateGoVersion(value); err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+	case KeyFramework:
+		if !slices.Contains(components.Frameworks, value) {
+			return fmt.Errorf("%s: unsupported framework '%s', supported frameworks: %s", key, value, strings.Join(components.Frameworks, ", "))
+		}
+	case KeyCIProvider:
+		if _, err := cicd.ParseProvider(value); err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+	case KeyProfile:
+		if !profileNamePattern.MatchString(value) {
+			return fmt.Errorf("%s: invalid profile name '%s' (use lower-case letters, digits, '_' and '-')", key, value)
+		}
+	}
+	return nil
+}
+
+// Get returns the value of the setting key, empty when unset or unknown
+func (c Config) Get(key string) string {
+	switch key {
+	case KeyAuthor:
+		return c.Author
+	case KeyEmail:
+		return c.Email
+	case KeyLicense:
+		return c.License
+	case KeyGoVersion:
+		return c.GoVersion
+	case KeyFramework:
+		return c.Framework
+	case KeyDBPath:
+		return c.DBPath
+	case KeyCIProvider:
+		return c.CIProvider
+	case KeyProfile:
+		return c.Profile
+	}
+	return ""
+}
+
+// readFile reads the settings file at path, without the environment
+func readFile(path string) (*viper.Viper, error) {
+	v := viper.New()
+	v.SetConfigFile(path)
+	v.SetConfigType("yaml")
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return v, nil
+	}
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("failed to read settings file %s: %w", path, err)
+	}
+	return v, nil
+}
+
+// expandHome replaces a leading ~/ in path with the home directory
+func expandHome(path string) string {
+	rest, ok := strings.CutPrefix(path, "~/")
+	if !ok {
+		return path
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(homeDir, rest)
+}
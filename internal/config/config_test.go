@@ -0,0 +1,192 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad_Missing(t *testing.T) {
+	config, err := Load(filepath.Join(t.TempDir(), "config.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, Config{}, config)
+}
+
+func TestSetAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gogo", "config.yaml")
+
+	require.NoError(t, Set(path, KeyAuthor, "Ada Lovelace"))
+	require.NoError(t, Set(path, KeyGoVersion, "1.24"))
+	require.NoError(t, Set(path, KeyFramework, "chi"))
+	require.NoError(t, Set(path, KeyCIProvider, "gitlab"))
+
+	config, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, "Ada Lovelace", config.Author)
+	assert.Equal(t, "1.24", config.GoVersion)
+	assert.Equal(t, "chi", config.Get(KeyFramework))
+	assert.Equal(t, "gitlab", config.CIProvider)
+
+	// An empty value removes the setting and keeps the others
+	require.NoError(t, Set(path, KeyFramework, ""))
+	config, err = Load(path)
+	require.NoError(t, err)
+	assert.Empty(t, config.Framework)
+	assert.Equal(t, "Ada Lovelace", config.Author)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "framework")
+}
+
+func TestLoad_Env(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, Set(path, KeyLicense, "MIT"))
+
+	t.Setenv(EnvName(KeyLicense), "Apache-2.0")
+	t.Setenv("GOGO_DB_PATH", "~/work/gogo.db")
+	config, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, "Apache-2.0", config.License)
+	home, err := os.UserHomeDir()
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(home, "work", "gogo.db"), config.DBPath)
+
+	// The environment is not written back to the file
+	require.NoError(t, Set(path, KeyAuthor, "Ada"))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "license: MIT")
+	assert.NotContains(t, string(data), "db_path")
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, Validate(KeyEmail, ""))
+	assert.NoError(t, Validate(KeyEmail, "ada@example.com"))
+	assert.ErrorContains(t, Validate(KeyEmail, "ada"), "email address")
+	assert.ErrorContains(t, Validate(KeyGoVersion, "latest"), "invalid Go version format")
+	assert.ErrorContains(t, Validate(KeyFramework, "fiber"), "unsupported framework")
+	assert.ErrorContains(t, Validate(KeyCIProvider, "jenkins"), "unknown CI provider")
+	assert.ErrorContains(t, Validate("editor", "vim"), "unknown setting")
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("framework: fiber\n"), 0644))
+
+	_, err := Load(path)
+	assert.ErrorContains(t, err, "unsupported framework")
+}
+
+func TestProfiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, Set(path, KeyAuthor, "Ada"))
+	require.NoError(t, Set(path, KeyLicense, "MIT"))
+	require.NoError(t, SetProfile(path, "work", KeyLicense, "Apache-2.0"))
+	require.NoError(t, SetProfile(path, "work", KeyModulePrefix, "github.com/acme"))
+	require.NoError(t, SetProfile(path, "work", KeyBlueprint, "web-stack"))
+	require.NoError(t, SetProfile(path, "oss", KeyCIProvider, "github"))
+
+	config, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"oss", "work"}, config.ProfileNames())
+
+	defaults, err := config.Defaults("work")
+	require.NoError(t, err)
+	assert.Equal(t, "Ada", defaults.Author, "settings fill in what the profile leaves unset")
+	assert.Equal(t, "Apache-2.0", defaults.License)
+	assert.Equal(t, "web-stack", defaults.Blueprint)
+	assert.Equal(t, "github.com/acme/billing", defaults.Module("billing"))
+
+	defaults, err = config.Defaults("")
+	require.NoError(t, err)
+	assert.Equal(t, "MIT", defaults.License)
+	assert.Empty(t, defaults.Module("billing"))
+
+	_, err = config.Defaults("home")
+	assert.ErrorContains(t, err, "profiles: oss, work")
+
+	// The profile setting picks the profile used without a name
+	assert.ErrorContains(t, Set(path, KeyProfile, "home"), "does not exist")
+	require.NoError(t, Set(path, KeyProfile, "work"))
+	config, err = Load(path)
+	require.NoError(t, err)
+	defaults, err = config.Defaults("")
+	require.NoError(t, err)
+	assert.Equal(t, "Apache-2.0", defaults.License)
+
+	// Deleting the profile unsets it as the default
+	require.NoError(t, DeleteProfile(path, "work"))
+	config, err = Load(path)
+	require.NoError(t, err)
+	assert.Empty(t, config.Profile)
+	assert.Equal(t, []string{"oss"}, config.ProfileNames())
+	assert.ErrorContains(t, DeleteProfile(path, "work"), "does not exist")
+}
+
+func TestValidateProfile(t *testing.T) {
+	assert.NoError(t, ValidateProfile(KeyModulePrefix, "github.com/acme"))
+	assert.ErrorContains(t, ValidateProfile(KeyModulePrefix, "github.com/acme/"), "trailing slash")
+	assert.ErrorContains(t, ValidateProfile(KeyFramework, "fiber"), "unsupported framework")
+	assert.ErrorContains(t, ValidateProfile(KeyDBPath, "/tmp/gogo.db"), "unknown profile field")
+	assert.ErrorContains(t, SetProfile(filepath.Join(t.TempDir(), "config.yaml"), "my.team", KeyAuthor, "Ada"), "invalid profile name")
+}
+
+func TestCheck(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	problems, err := Check(path)
+	require.NoError(t, err)
+	assert.Empty(t, problems, "a missing file has no problems")
+
+	require.NoError(t, os.WriteFile(path, []byte(`author: Ada
+go_verison: "1.24"
+template: api
+email: nobody
+profiles:
+  work:
+    modul_prefix: github.com/acme
+    license: MIT
+`), 0644))
+
+	// Load ignores the unknown keys, which Check reports
+	_, err = Load(path)
+	require.Error(t, err, "the email is invalid")
+	problems, err = Check(path)
+	require.NoError(t, err)
+	require.Len(t, problems, 4)
+	assert.Equal(t, Problem{Line: 2, Column: 1, Key: "go_verison", Message: "ignored: unknown setting (did you mean go_version?)"}, problems[0])
+	assert.Equal(t, "template", problems[1].Key)
+	assert.Contains(t, problems[1].Message, "gogo config profile set <name> template api")
+	assert.Equal(t, "4:8: email: email must be an email address, not \"nobody\"", problems[2].String())
+	assert.Equal(t, "profiles.work.modul_prefix", problems[3].Key)
+	assert.Contains(t, problems[3].Message, "did you mean module_prefix?")
+}
+
+func TestCheck_ValuesAndDeprecatedKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`go_version: "1.x"
+editor: vim
+profiles:
+  work:
+    prefix: github.com/acme
+`), 0644))
+
+	deprecatedKeys["editor"] = KeyAuthor
+	deprecatedKeys["prefix"] = KeyModulePrefix
+	t.Cleanup(func() {
+		delete(deprecatedKeys, "editor")
+		delete(deprecatedKeys, "prefix")
+	})
+
+	problems, err := Check(path)
+	require.NoError(t, err)
+	require.Len(t, problems, 3)
+	// The key is not repeated in the message
+	assert.Equal(t, "go_version", problems[0].Key)
+	assert.NotContains(t, problems[0].Message, "go_version")
+	assert.Equal(t, "ignored: deprecated, renamed to author; migrate with gogo config set author vim and remove editor", problems[1].Message)
+	assert.Equal(t, "profiles.work.prefix", problems[2].Key)
+	assert.Contains(t, problems[2].Message, "gogo config profile set work module_prefix github.com/acme")
+}
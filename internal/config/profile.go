@@ -0,0 +1,213 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/user/gogo/internal/validate"
+)
+
+// Keys of the profile fields besides those shared with the settings
+const (
+	KeyModulePrefix = "module_prefix"
+	KeyTemplate     = "template"
+	KeyBlueprint    = "blueprint"
+)
+
+// ProfileKeys lists the fields of a profile in the order they are listed
+var ProfileKeys = []string{KeyAuthor, KeyEmail, KeyLicense, KeyModulePrefix, KeyTemplate, KeyBlueprint, KeyGoVersion, KeyFramework, KeyCIProvider}
+
+// profileNamePattern matches profile names; dots would split viper keys
+var profileNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// Profile is a named set of defaults for gogo init, such as those of a team
+// or a client, kept under profiles in the settings file. Its fields take
+// precedence over the settings; empty fields leave them in place.
+type Profile struct {
+	Author       string `mapstructure:"author"`
+	Email        string `mapstructure:"email"`
+	License      string `mapstructure:"license"`
+	ModulePrefix string `mapstructure:"module_prefix"` // Projects are named <prefix>/<project> without --module
+	Template     string `mapstructure:"template"`
+	Blueprint    string `mapstructure:"blueprint"`
+	GoVersion    string `mapstructure:"go_version"`
+	Framework    string `mapstructure:"framework"`
+	CIProvider   string `mapstructure:"ci_provider"`
+}
+
+// Get returns the value of the profile field key, empty when unset or unknown
+func (p Profile) Get(key string) string {
+	switch key {
+	case KeyAuthor:
+		return p.Author
+	case KeyEmail:
+		return p.Email
+	case KeyLicense:
+		return p.License
+	case KeyModulePrefix:
+		return p.ModulePrefix
+	case KeyTemplate:
+		return p.Template
+	case KeyBlueprint:
+		return p.Blueprint
+	case KeyGoVersion:
+		return p.GoVersion
+	case KeyFramework:
+		return p.Framework
+	case KeyCIProvider:
+		return p.CIProvider
+	}
+	return ""
+}
+
+// Module returns the module path of project under ModulePrefix, or nothing
+// without a prefix
+func (p Profile) Module(project string) string {
+	if p.ModulePrefix == "" || project == "" {
+		return ""
+	}
+	return p.ModulePrefix + "/" + project
+}
+
+// ProfileNames returns the names of the profiles, sorted
+func (c Config) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Defaults returns the defaults of gogo init: the settings, overridden by the
+// fields of the named profile, or of the profile setting when name is empty
+func (c Config) Defaults(name string) (Profile, error) {
+	defaults := Profile{
+		Author:     c.Author,
+		Email:      c.Email,
+		License:    c.License,
+		GoVersion:  c.GoVersion,
+		Framework:  c.Framework,
+		CIProvider: c.CIProvider,
+	}
+	if name == "" {
+		name = c.Profile
+	}
+	if name == "" {
+		return defaults, nil
+	}
+	profile, ok := c.Profiles[name]
+	if !ok {
+		return Profile{}, c.unknownProfile(name)
+	}
+
+	for _, field := range []struct{ value, override *string }{
+		{&defaults.Author, &profile.Author},
+		{&defaults.Email, &profile.Email},
+		{&defaults.License, &profile.License},
+		{&defaults.GoVersion, &profile.GoVersion},
+		{&defaults.Framework, &profile.Framework},
+		{&defaults.CIProvider, &profile.CIProvider},
+	} {
+		if *field.override != "" {
+			*field.value = *field.override
+		}
+	}
+	defaults.ModulePrefix = profile.ModulePrefix
+	defaults.Template = profile.Template
+	defaults.Blueprint = profile.Blueprint
+	return defaults, nil
+}
+
+// SetProfile writes the field key of the named profile to the settings file
+// at path, creating the profile; an empty value removes the field
+func SetProfile(path, name, key, value string) error {
+	if !profileNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid profile name '%s' (use lower-case letters, digits, '_' and '-')", name)
+	}
+	if err := ValidateProfile(key, value); err != nil {
+		return err
+	}
+	v, err := readFile(path)
+	if err != nil {
+		return err
+	}
+
+	settings := v.AllSettings()
+	profiles, _ := settings["profiles"].(map[string]any)
+	if profiles == nil {
+		profiles = make(map[string]any)
+	}
+	profile, _ := profiles[name].(map[string]any)
+	if profile == nil {
+		profile = make(map[string]any)
+	}
+	if value == "" {
+		delete(profile, key)
+	} else {
+		profile[key] = value
+	}
+	profiles[name] = profile
+	settings["profiles"] = profiles
+	return writeFile(path, settings)
+}
+
+// DeleteProfile removes the named profile from the settings file at path,
+// and the profile setting if it names it
+func DeleteProfile(path, name string) error {
+	v, err := readFile(path)
+	if err != nil {
+		return err
+	}
+	settings := v.AllSettings()
+	profiles, _ := settings["profiles"].(map[string]any)
+	if _, ok := profiles[name]; !ok {
+		var config Config
+		if err := v.Unmarshal(&config); err != nil {
+			return fmt.Errorf("failed to parse settings file %s: %w", path, err)
+		}
+		return config.unknownProfile(name)
+	}
+	delete(profiles, name)
+	if settings[KeyProfile] == name {
+		delete(settings, KeyProfile)
+	}
+	return writeFile(path, settings)
+}
+
+// ValidateProfile checks a value of the profile field key; empty values
+// unset it
+func ValidateProfile(key, value string) error {
+	if !slices.Contains(ProfileKeys, key) {
+		return fmt.Errorf("unknown profile field %q (valid: %s)", key, strings.Join(ProfileKeys, ", "))
+	}
+	if value == "" {
+		return nil
+	}
+	switch key {
+	case KeyModulePrefix:
+		if strings.HasSuffix(value, "/") {
+			return fmt.Errorf("%s must be a module path without a trailing slash", key)
+		}
+		if err := validate.ValidateModuleName(value); err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+	case KeyTemplate, KeyBlueprint:
+		if strings.ContainsAny(value, " \t/") {
+			return fmt.Errorf("%s: invalid name '%s'", key, value)
+		}
+	default:
+		return Validate(key, value)
+	}
+	return nil
+}
+
+func (c Config) unknownProfile(name string) error {
+	if len(c.Profiles) == 0 {
+		return fmt.Errorf("profile %q does not exist (create it with gogo config profile set %s <key> <value>)", name, name)
+	}
+	return fmt.Errorf("profile %q does not exist (profiles: %s)", name, strings.Join(c.ProfileNames(), ", "))
+}
@@ -0,0 +1,121 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MaxRating is the highest rating an entry can be given
+const MaxRating = 5
+
+// Annotation is a local note and rating attached to a template or blueprint
+type Annotation struct {
+	Kind      string
+	Name      string
+	Note      string
+	Rating    int // 1 to MaxRating; 0 means unrated
+	UpdatedAt time.Time
+}
+
+// Stars renders the rating as filled and empty stars, or "" when unrated
+func (a Annotation) Stars() string {
+	if a.Rating <= 0 {
+		return ""
+	}
+	return strings.Repeat("★", a.Rating) + strings.Repeat("☆", MaxRating-a.Rating)
+}
+
+// AnnotationManager stores notes and ratings for templates and blueprints.
+// Like tags, entries are identified by kind and name.
+type AnnotationManager struct {
+	db  *Manager
+	now func() time.Time
+}
+
+// NewAnnotationManager creates a new annotation manager
+func NewAnnotationManager(manager *Manager) *AnnotationManager {
+	return &AnnotationManager{
+		db:  manager,
+		now: time.Now,
+	}
+}
+
+// Get returns the annotation of an entry, or nil if it has none
+func (a *AnnotationManager) Get(ctx context.Context, kind, name string) (*Annotation, error) {
+	annotation := &Annotation{Kind: kind, Name: name}
+	var updatedAt string
+	err := a.db.GetDB().QueryRowContext(ctx,
+		`SELECT note, rating, updated_at FROM annotations WHERE kind = ? AND entry_name = ?`, kind, name).
+		Scan(&annotation.Note, &annotation.Rating, &updatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get annotation for %s '%s': %w", kind, name, err)
+	}
+	if annotation.UpdatedAt, err = ParseTimestamp(updatedAt); err != nil {
+		return nil, err
+	}
+	return annotation, nil
+}
+
+// Save creates or replaces an annotation
+func (a *AnnotationManager) Save(ctx context.Context, annotation *Annotation) error {
+	if _, err := trashTable(annotation.Kind); err != nil {
+		return err
+	}
+	if annotation.Rating < 0 || annotation.Rating > MaxRating {
+		return fmt.Errorf("rating must be between 1 and %d", MaxRating)
+	}
+
+	annotation.UpdatedAt = a.now().UTC().Truncate(time.Second)
+	query := `INSERT INTO annotations (kind, entry_name, note, rating, updated_at) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(kind, entry_name) DO UPDATE SET
+			note = excluded.note, rating = excluded.rating, updated_at = excluded.updated_at`
+	if _, err := a.db.GetDB().ExecContext(ctx, query,
+		annotation.Kind, annotation.Name, annotation.Note, annotation.Rating, FormatTimestamp(annotation.UpdatedAt)); err != nil {
+		return fmt.Errorf("failed to annotate %s '%s': %w", annotation.Kind, annotation.Name, err)
+	}
+	return nil
+}
+
+// Delete removes the annotation of an entry
+func (a *AnnotationManager) Delete(ctx context.Context, kind, name string) error {
+	err := expectOneRow(a.db.GetDB().ExecContext(ctx,
+		`DELETE FROM annotations WHERE kind = ? AND entry_name = ?`, kind, name))
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%s '%s' has no annotation", kind, name)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to remove annotation of %s '%s': %w", kind, name, err)
+	}
+	return nil
+}
+
+// All returns every annotation of a kind, keyed by entry name
+func (a *AnnotationManager) All(ctx context.Context, kind string) (map[string]Annotation, error) {
+	rows, err := a.db.GetDB().QueryContext(ctx,
+		`SELECT entry_name, note, rating, updated_at FROM annotations WHERE kind = ?`, kind)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query annotations: %w", err)
+	}
+	defer rows.Close()
+
+	annotations := make(map[string]Annotation)
+	for rows.Next() {
+		annotation := Annotation{Kind: kind}
+		var updatedAt string
+		if err := rows.Scan(&annotation.Name, &annotation.Note, &annotation.Rating, &updatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan annotation: %w", err)
+		}
+		if annotation.UpdatedAt, err = ParseTimestamp(updatedAt); err != nil {
+			return nil, err
+		}
+		annotations[annotation.Name] = annotation
+	}
+	return annotations, rows.Err()
+}
@@ -0,0 +1,53 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnnotationManager(t *testing.T) {
+	manager, dbPath, cleanup := setupTestManager(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	require.NoError(t, manager.Open(ctx, dbPath))
+	defer manager.Close()
+
+	annotations := NewAnnotationManager(manager)
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	annotations.now = func() time.Time { return now }
+
+	annotation, err := annotations.Get(ctx, KindBlueprint, "web-stack")
+	require.NoError(t, err)
+	assert.Nil(t, annotation)
+
+	require.NoError(t, annotations.Save(ctx, &Annotation{
+		Kind: KindBlueprint, Name: "web-stack", Note: "works great with our infra", Rating: 4,
+	}))
+
+	annotation, err = annotations.Get(ctx, KindBlueprint, "web-stack")
+	require.NoError(t, err)
+	require.NotNil(t, annotation)
+	assert.Equal(t, "works great with our infra", annotation.Note)
+	assert.Equal(t, 4, annotation.Rating)
+	assert.Equal(t, "★★★★☆", annotation.Stars())
+	assert.Equal(t, now, annotation.UpdatedAt)
+
+	// Saving again replaces the annotation
+	annotation.Rating = 0
+	require.NoError(t, annotations.Save(ctx, annotation))
+	all, err := annotations.All(ctx, KindBlueprint)
+	require.NoError(t, err)
+	require.Contains(t, all, "web-stack")
+	assert.Equal(t, "", all["web-stack"].Stars())
+
+	assert.Error(t, annotations.Save(ctx, &Annotation{Kind: KindTemplate, Name: "api", Rating: 6}))
+	assert.Error(t, annotations.Save(ctx, &Annotation{Kind: "plugin", Name: "api"}))
+
+	require.NoError(t, annotations.Delete(ctx, KindBlueprint, "web-stack"))
+	assert.Error(t, annotations.Delete(ctx, KindBlueprint, "web-stack"))
+}
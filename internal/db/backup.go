@@ -10,12 +10,15 @@ import (
 	"time"
 
 	"github.com/fatih/color"
+	"github.com/user/gogo/internal/events"
+	"github.com/user/gogo/internal/fsutil"
 )
 
 // BackupManager handles database backup and restore operations
 type BackupManager struct {
-	db   *Manager
-	path string
+	db     *Manager
+	path   string
+	events *events.Bus
 }
 
 // NewBackupManager creates a new backup manager
@@ -26,12 +29,32 @@ func NewBackupManager(manager *Manager, dbPath string) *BackupManager {
 	}
 }
 
+// SetEvents sets the bus BackupCompleted is published on
+func (b *BackupManager) SetEvents(bus *events.Bus) {
+	b.events = bus
+}
+
 // BackupOptions contains options for database backup
 type BackupOptions struct {
-	OutputPath string
-	Compress   bool
-	Verify     bool
-	Verbose    bool
+	OutputPath  string
+	Compress    bool        // Compress with gzip unless Compression names another algorithm
+	Compression Compression // Algorithm to compress with; implies Compress
+	Level       int         // Compression level; DefaultCompressionLevel for the algorithm's default
+	Verify      bool
+	Verbose     bool
+	Resume      bool                    // Write through a resumable transfer, continuing an interrupted one
+	Progress    func(done, total int64) // Reports the transfer when Resume is set
+}
+
+// compression returns the algorithm the backup is compressed with
+func (o BackupOptions) compression() Compression {
+	if o.Compression != "" {
+		return o.Compression
+	}
+	if o.Compress {
+		return CompressionGzip
+	}
+	return CompressionNone
 }
 
 // RestoreOptions contains options for database restore
@@ -41,6 +64,8 @@ type RestoreOptions struct {
 	CreateBackup bool
 	Force        bool
 	Verbose      bool
+	Resume       bool                    // Copy the backup through a resumable transfer, continuing an interrupted one
+	Progress     func(done, total int64) // Reports the transfer when Resume is set
 }
 
 // Backup creates a backup of the database
@@ -60,8 +85,12 @@ func (b *BackupManager) Backup(ctx context.Context, opts BackupOptions) error {
 		return fmt.Errorf("failed to create output directory: %w", err)
 	}
 
+	if opts.Resume {
+		return b.backupResumable(ctx, opts)
+	}
+
 	// Perform backup
-	if opts.Compress {
+	if opts.compression() != CompressionNone {
 		if err := b.backupCompressed(ctx, opts); err != nil {
 			return fmt.Errorf("compressed backup failed: %w", err)
 		}
@@ -78,17 +107,86 @@ func (b *BackupManager) Backup(ctx context.Context, opts BackupOptions) error {
 		}
 	}
 
-	// Get backup file size
-	stat, err := os.Stat(opts.OutputPath)
-	if err == nil {
-		color.Green("✓ Backup completed: %s (%.2f MB)", opts.OutputPath, float64(stat.Size())/1024/1024)
+	b.reportBackup(ctx, opts)
+	return nil
+}
+
+// backupResumable writes the backup to a local spool file and transfers it to
+// the output in checkpointed chunks. The spool is kept until the transfer
+// completes, so a rerun with Resume continues uploading the same backup.
+func (b *BackupManager) backupResumable(ctx context.Context, opts BackupOptions) error {
+	state, err := LoadTransferState(opts.OutputPath)
+	if err != nil {
+		return err
+	}
+
+	var spool string
+	if state != nil {
+		spool = state.Source
+		if opts.Verbose {
+			color.Yellow("Resuming upload of %s...", spool)
+		}
 	} else {
-		color.Green("✓ Backup completed: %s", opts.OutputPath)
+		file, err := os.CreateTemp("", "gogo-backup-*.spool")
+		if err != nil {
+			return fmt.Errorf("failed to create spool file: %w", err)
+		}
+		spool = file.Name()
+		file.Close()
+
+		spoolOpts := opts
+		spoolOpts.OutputPath = spool
+		if opts.compression() != CompressionNone {
+			err = b.backupCompressed(ctx, spoolOpts)
+		} else {
+			err = b.backupRaw(ctx, spoolOpts)
+		}
+		if err != nil {
+			os.Remove(spool)
+			return fmt.Errorf("backup failed: %w", err)
+		}
+	}
+
+	// Verifying the spool avoids reading the backup back from a slow target
+	if opts.Verify {
+		if err := b.verifyBackup(ctx, spool, opts.Verbose); err != nil {
+			return fmt.Errorf("backup verification failed: %w", err)
+		}
+	}
+
+	result, err := Transfer(ctx, spool, opts.OutputPath, TransferOptions{Resume: true, Progress: opts.Progress})
+	if err != nil {
+		return fmt.Errorf("failed to upload backup (rerun with --resume to continue): %w", err)
+	}
+	if result.Resumed > 0 {
+		color.Yellow("Resumed after %d bytes", result.Resumed)
+	}
+	if err := os.Remove(spool); err != nil && !os.IsNotExist(err) {
+		color.Red("Warning: failed to remove spool file %s: %v", spool, err)
 	}
 
+	b.reportBackup(ctx, opts)
 	return nil
 }
 
+// reportBackup prints the completed backup and its size, and publishes
+// BackupCompleted
+func (b *BackupManager) reportBackup(ctx context.Context, opts BackupOptions) {
+	path := opts.OutputPath
+	details := map[string]any{
+		"compression": string(opts.compression()),
+		"verified":    opts.Verify,
+	}
+	stat, err := os.Stat(path)
+	if err == nil {
+		color.Green("✓ Backup completed: %s (%.2f MB)", path, float64(stat.Size())/1024/1024)
+		details["size"] = stat.Size()
+	} else {
+		color.Green("✓ Backup completed: %s", path)
+	}
+	b.events.Publish(ctx, events.Event{Kind: events.BackupCompleted, Subject: path, Details: details})
+}
+
 // backupRaw performs a raw file copy backup
 func (b *BackupManager) backupRaw(ctx context.Context, opts BackupOptions) error {
 	// Open source database file
@@ -98,8 +196,8 @@ func (b *BackupManager) backupRaw(ctx context.Context, opts BackupOptions) error
 	}
 	defer srcFile.Close()
 
-	// Create destination file
-	dstFile, err := os.Create(opts.OutputPath)
+	// Create destination file; it only appears once complete
+	dstFile, err := fsutil.Create(opts.OutputPath, FileMode())
 	if err != nil {
 		return fmt.Errorf("failed to create backup file: %w", err)
 	}
@@ -115,7 +213,7 @@ func (b *BackupManager) backupRaw(ctx context.Context, opts BackupOptions) error
 		return fmt.Errorf("failed to copy database: %w", err)
 	}
 
-	return dstFile.Sync()
+	return dstFile.Commit()
 }
 
 // backupCompressed performs a compressed backup
@@ -127,37 +225,43 @@ func (b *BackupManager) backupCompressed(ctx context.Context, opts BackupOptions
 	}
 	defer srcFile.Close()
 
-	// Create destination file
-	dstFile, err := os.Create(opts.OutputPath)
+	// Create destination file; it only appears once complete
+	dstFile, err := fsutil.Create(opts.OutputPath, FileMode())
 	if err != nil {
 		return fmt.Errorf("failed to create backup file: %w", err)
 	}
 	defer dstFile.Close()
 
-	// Create gzip writer
-	gzWriter := gzip.NewWriter(dstFile)
-	defer gzWriter.Close()
+	// Create compressing writer
+	compression := opts.compression()
+	writer, err := NewCompressWriter(dstFile, compression, opts.Level)
+	if err != nil {
+		return err
+	}
+	defer writer.Close()
 
 	// Set gzip metadata
-	gzWriter.Name = filepath.Base(b.path)
-	gzWriter.ModTime = time.Now()
+	if gzWriter, ok := writer.(*gzip.Writer); ok {
+		gzWriter.Name = filepath.Base(b.path)
+		gzWriter.ModTime = time.Now()
+	}
 
 	if opts.Verbose {
-		color.Yellow("Compressing database...")
+		color.Yellow("Compressing database with %s...", compression)
 	}
 
 	// Copy and compress database
-	_, err = io.Copy(gzWriter, srcFile)
+	_, err = io.Copy(writer, srcFile)
 	if err != nil {
 		return fmt.Errorf("failed to compress database: %w", err)
 	}
 
 	// Ensure everything is written
-	if err := gzWriter.Close(); err != nil {
+	if err := writer.Close(); err != nil {
 		return fmt.Errorf("failed to finalize compression: %w", err)
 	}
 
-	return dstFile.Sync()
+	return dstFile.Commit()
 }
 
 // Restore restores a database from backup
@@ -195,14 +299,32 @@ func (b *BackupManager) Restore(ctx context.Context, opts RestoreOptions) error
 		}
 	}
 
-	// Determine if backup is compressed
-	isCompressed, err := b.isCompressedFile(opts.BackupPath)
+	// Copy a remote backup locally first, so restoring never reads from a slow target
+	source := opts.BackupPath
+	if opts.Resume {
+		if err := os.MkdirAll(filepath.Dir(b.path), 0755); err != nil {
+			return fmt.Errorf("failed to create destination directory: %w", err)
+		}
+		local := b.path + ".restore"
+		result, err := Transfer(ctx, opts.BackupPath, local, TransferOptions{Resume: true, Progress: opts.Progress})
+		if err != nil {
+			return fmt.Errorf("failed to download backup (rerun with --resume to continue): %w", err)
+		}
+		defer os.Remove(local)
+		if result.Resumed > 0 {
+			color.Yellow("Resumed after %d bytes", result.Resumed)
+		}
+		opts.BackupPath = local
+	}
+
+	// Determine the compression from the backup's magic bytes
+	compression, err := DetectFileCompression(opts.BackupPath)
 	if err != nil {
 		return fmt.Errorf("failed to check backup format: %w", err)
 	}
 
 	// Restore from backup
-	if isCompressed {
+	if compression != CompressionNone {
 		if err := b.restoreCompressed(ctx, opts); err != nil {
 			return fmt.Errorf("compressed restore failed: %w", err)
 		}
@@ -212,6 +334,10 @@ func (b *BackupManager) Restore(ctx context.Context, opts RestoreOptions) error
 		}
 	}
 
+	if err := b.markRestoredHooks(ctx); err != nil {
+		return err
+	}
+
 	// Verify restored database if requested
 	if opts.Verify {
 		if err := b.verifyDatabase(ctx, b.path, opts.Verbose); err != nil {
@@ -219,10 +345,21 @@ func (b *BackupManager) Restore(ctx context.Context, opts RestoreOptions) error
 		}
 	}
 
-	color.Green("✓ Database restored successfully from: %s", opts.BackupPath)
+	color.Green("✓ Database restored successfully from: %s", source)
 	return nil
 }
 
+// markRestoredHooks marks the hooks of the restored database as
+// third-party, since the backup may be someone else's
+func (b *BackupManager) markRestoredHooks(ctx context.Context) error {
+	manager := NewManager()
+	if err := manager.Open(ctx, b.path); err != nil {
+		return fmt.Errorf("failed to open restored database: %w", err)
+	}
+	defer manager.Close()
+	return manager.markRestoredHooks(ctx)
+}
+
 // restoreRaw restores from a raw database file
 func (b *BackupManager) restoreRaw(ctx context.Context, opts RestoreOptions) error {
 	// Create destination directory if needed
@@ -238,8 +375,8 @@ func (b *BackupManager) restoreRaw(ctx context.Context, opts RestoreOptions) err
 	}
 	defer srcFile.Close()
 
-	// Create destination file
-	dstFile, err := os.Create(b.path)
+	// Create destination file; it replaces the database once complete
+	dstFile, err := fsutil.Create(b.path, FileMode())
 	if err != nil {
 		return fmt.Errorf("failed to create destination database: %w", err)
 	}
@@ -255,7 +392,7 @@ func (b *BackupManager) restoreRaw(ctx context.Context, opts RestoreOptions) err
 		return fmt.Errorf("failed to copy backup: %w", err)
 	}
 
-	return dstFile.Sync()
+	return dstFile.Commit()
 }
 
 // restoreCompressed restores from a compressed backup
@@ -273,31 +410,31 @@ func (b *BackupManager) restoreCompressed(ctx context.Context, opts RestoreOptio
 	}
 	defer srcFile.Close()
 
-	// Create gzip reader
-	gzReader, err := gzip.NewReader(srcFile)
+	// Create decompressing reader
+	reader, compression, err := NewDecompressReader(srcFile)
 	if err != nil {
-		return fmt.Errorf("failed to create gzip reader: %w", err)
+		return fmt.Errorf("failed to read compressed backup: %w", err)
 	}
-	defer gzReader.Close()
+	defer reader.Close()
 
-	// Create destination file
-	dstFile, err := os.Create(b.path)
+	// Create destination file; it replaces the database once complete
+	dstFile, err := fsutil.Create(b.path, FileMode())
 	if err != nil {
 		return fmt.Errorf("failed to create destination database: %w", err)
 	}
 	defer dstFile.Close()
 
 	if opts.Verbose {
-		color.Yellow("Decompressing backup...")
+		color.Yellow("Decompressing %s backup...", compression)
 	}
 
 	// Decompress and copy
-	_, err = io.Copy(dstFile, gzReader)
+	_, err = io.Copy(dstFile, reader)
 	if err != nil {
 		return fmt.Errorf("failed to decompress backup: %w", err)
 	}
 
-	return dstFile.Sync()
+	return dstFile.Commit()
 }
 
 // verifyBackup verifies the integrity of a backup file
@@ -306,12 +443,12 @@ func (b *BackupManager) verifyBackup(ctx context.Context, backupPath string, ver
 		color.Yellow("Verifying backup integrity...")
 	}
 
-	isCompressed, err := b.isCompressedFile(backupPath)
+	compression, err := DetectFileCompression(backupPath)
 	if err != nil {
 		return err
 	}
 
-	if isCompressed {
+	if compression != CompressionNone {
 		return b.verifyCompressedBackup(backupPath, verbose)
 	}
 
@@ -326,14 +463,14 @@ func (b *BackupManager) verifyCompressedBackup(backupPath string, verbose bool)
 	}
 	defer file.Close()
 
-	gzReader, err := gzip.NewReader(file)
+	reader, _, err := NewDecompressReader(file)
 	if err != nil {
-		return fmt.Errorf("backup file is corrupted (invalid gzip): %w", err)
+		return fmt.Errorf("backup file is corrupted: %w", err)
 	}
-	defer gzReader.Close()
+	defer reader.Close()
 
 	// Read and discard content to verify decompression works
-	_, err = io.Copy(io.Discard, gzReader)
+	_, err = io.Copy(io.Discard, reader)
 	if err != nil {
 		return fmt.Errorf("backup file is corrupted (decompression failed): %w", err)
 	}
@@ -372,25 +509,6 @@ func (b *BackupManager) verifyDatabase(ctx context.Context, dbPath string, verbo
 	return nil
 }
 
-// isCompressedFile checks if a file is gzip compressed
-func (b *BackupManager) isCompressedFile(path string) (bool, error) {
-	file, err := os.Open(path)
-	if err != nil {
-		return false, fmt.Errorf("failed to open file: %w", err)
-	}
-	defer file.Close()
-
-	// Read first few bytes to check for gzip magic number
-	buffer := make([]byte, 2)
-	_, err = file.Read(buffer)
-	if err != nil {
-		return false, fmt.Errorf("failed to read file header: %w", err)
-	}
-
-	// Gzip magic number is 0x1f, 0x8b
-	return buffer[0] == 0x1f && buffer[1] == 0x8b, nil
-}
-
 // GetBackupInfo returns information about a backup file
 func (b *BackupManager) GetBackupInfo(backupPath string) (*BackupInfo, error) {
 	stat, err := os.Stat(backupPath)
@@ -398,7 +516,7 @@ func (b *BackupManager) GetBackupInfo(backupPath string) (*BackupInfo, error) {
 		return nil, fmt.Errorf("failed to stat backup file: %w", err)
 	}
 
-	isCompressed, err := b.isCompressedFile(backupPath)
+	compression, err := DetectFileCompression(backupPath)
 	if err != nil {
 		return nil, err
 	}
@@ -407,7 +525,8 @@ func (b *BackupManager) GetBackupInfo(backupPath string) (*BackupInfo, error) {
 		Path:         backupPath,
 		Size:         stat.Size(),
 		ModTime:      stat.ModTime(),
-		IsCompressed: isCompressed,
+		IsCompressed: compression != CompressionNone,
+		Compression:  compression,
 	}, nil
 }
 
@@ -417,13 +536,14 @@ type BackupInfo struct {
 	Size         int64
 	ModTime      time.Time
 	IsCompressed bool
+	Compression  Compression
 }
 
 // String returns a string representation of backup info
 func (bi *BackupInfo) String() string {
 	compressionStatus := "Raw"
 	if bi.IsCompressed {
-		compressionStatus = "Compressed"
+		compressionStatus = fmt.Sprintf("Compressed (%s)", bi.Compression)
 	}
 
 	return fmt.Sprintf("%s (%.2f MB, %s, %s)",
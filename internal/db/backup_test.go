@@ -295,7 +295,7 @@ func TestBackupManager_CompressedBackupWorkflow(t *testing.T) {
 	assert.Equal(t, 100, count)
 }
 
-func setupTestManager(t *testing.T) (*Manager, string, func()) {
+func setupTestManager(t testing.TB) (*Manager, string, func()) {
 	// Create temporary database file
 	tmpFile, err := os.CreateTemp("", "test_backup_*.db")
 	require.NoError(t, err)
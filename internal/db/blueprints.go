@@ -0,0 +1,188 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Errors returned by BlueprintManager
+var (
+	ErrBlueprintNotFound = errors.New("blueprint not found")
+	ErrBlueprintExists   = errors.New("blueprint already exists")
+)
+
+// StoredBlueprint is a blueprint kept in the blueprints table. Config holds
+// the blueprint configuration as JSON.
+type StoredBlueprint struct {
+	Name        string
+	Stack       string
+	Description string
+	Config      string
+	Source      *InstallSource // Set for blueprints added with gogo template install
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// BlueprintManager stores user blueprints
+type BlueprintManager struct {
+	db    *Manager
+	actor string
+	now   func() time.Time
+}
+
+// NewBlueprintManager creates a new blueprint manager
+func NewBlueprintManager(manager *Manager) *BlueprintManager {
+	return &BlueprintManager{
+		db:    manager,
+		actor: currentActor(),
+		now:   time.Now,
+	}
+}
+
+// Create stores a new blueprint
+func (b *BlueprintManager) Create(ctx context.Context, blueprint *StoredBlueprint) error {
+	return b.Save(ctx, blueprint, false)
+}
+
+// Update replaces a stored blueprint that is not in the trash
+func (b *BlueprintManager) Update(ctx context.Context, blueprint *StoredBlueprint) error {
+	if _, err := b.Get(ctx, blueprint.Name); err != nil {
+		return err
+	}
+	return b.Save(ctx, blueprint, true)
+}
+
+// Delete moves a stored blueprint to the trash, from where it can be
+// restored with gogo template trash
+func (b *BlueprintManager) Delete(ctx context.Context, name string) error {
+	if _, err := b.Get(ctx, name); err != nil {
+		return err
+	}
+	return NewTrashManager(b.db).Delete(ctx, KindBlueprint, name)
+}
+
+// Save stores a blueprint. An existing blueprint of the same name, including
+// one in the trash, is only replaced when replace is set.
+func (b *BlueprintManager) Save(ctx context.Context, blueprint *StoredBlueprint, replace bool) error {
+	if !tagPattern.MatchString(blueprint.Name) {
+		return fmt.Errorf("invalid blueprint name '%s' (use lower-case letters, digits, '.', '_' and '-')", blueprint.Name)
+	}
+	if blueprint.Stack == "" {
+		return fmt.Errorf("blueprint '%s' has no stack", blueprint.Name)
+	}
+	if !json.Valid([]byte(blueprint.Config)) {
+		return fmt.Errorf("blueprint '%s' has an invalid configuration", blueprint.Name)
+	}
+	metadata, err := json.Marshal(entryMetadata{Source: blueprint.Source})
+	if err != nil {
+		return fmt.Errorf("failed to encode blueprint '%s': %w", blueprint.Name, err)
+	}
+
+	return b.db.WithTx(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		var deletedAt sql.NullString
+		err := tx.QueryRowContext(ctx, `SELECT deleted_at FROM blueprints WHERE name = ?`, blueprint.Name).Scan(&deletedAt)
+		exists := err == nil
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("failed to look up blueprint '%s': %w", blueprint.Name, err)
+		}
+		if exists && !replace {
+			if deletedAt.Valid {
+				return fmt.Errorf("%w in the trash: %s", ErrBlueprintExists, blueprint.Name)
+			}
+			return fmt.Errorf("%w: %s", ErrBlueprintExists, blueprint.Name)
+		}
+
+		now := FormatTimestamp(b.now())
+		action := "add"
+		if exists {
+			action = "replace"
+			query := `UPDATE blueprints SET stack = ?, description = ?, config_json = ?, metadata_json = ?, updated_at = ?, deleted_at = NULL WHERE name = ?`
+			_, err = tx.ExecContext(ctx, query, blueprint.Stack, blueprint.Description, blueprint.Config, string(metadata), now, blueprint.Name)
+		} else {
+			query := `INSERT INTO blueprints (name, stack, description, config_json, metadata_json, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?)`
+			_, err = tx.ExecContext(ctx, query, blueprint.Name, blueprint.Stack, blueprint.Description, blueprint.Config, string(metadata), now, now)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to save blueprint '%s': %w", blueprint.Name, err)
+		}
+
+		auditDetails := map[string]any{"kind": KindBlueprint, "name": blueprint.Name, "stack": blueprint.Stack}
+		if blueprint.Source != nil {
+			auditDetails["source"] = blueprint.Source.URL
+		}
+		details, err := json.Marshal(auditDetails)
+		if err != nil {
+			return err
+		}
+		query := `INSERT INTO audits (actor, action, entity, details_json, created_at) VALUES (?, ?, ?, ?, ?)`
+		if _, err := tx.ExecContext(ctx, query, b.actor, action, KindBlueprint+":"+blueprint.Name, string(details), now); err != nil {
+			return fmt.Errorf("failed to record audit entry: %w", err)
+		}
+		return nil
+	})
+}
+
+// Get returns a stored blueprint that is not in the trash
+func (b *BlueprintManager) Get(ctx context.Context, name string) (*StoredBlueprint, error) {
+	query := `SELECT name, stack, description, config_json, metadata_json, created_at, updated_at
+		FROM blueprints WHERE name = ? AND deleted_at IS NULL`
+	blueprint, err := scanBlueprint(b.db.GetDB().QueryRowContext(ctx, query, name))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", ErrBlueprintNotFound, name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load blueprint '%s': %w", name, err)
+	}
+	return blueprint, nil
+}
+
+// List returns stored blueprints that are not in the trash, by name
+func (b *BlueprintManager) List(ctx context.Context) ([]*StoredBlueprint, error) {
+	query := `SELECT name, stack, description, config_json, metadata_json, created_at, updated_at
+		FROM blueprints WHERE deleted_at IS NULL ORDER BY name`
+	rows, err := b.db.GetDB().QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query blueprints: %w", err)
+	}
+	defer rows.Close()
+
+	var blueprints []*StoredBlueprint
+	for rows.Next() {
+		blueprint, err := scanBlueprint(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan blueprint row: %w", err)
+		}
+		blueprints = append(blueprints, blueprint)
+	}
+	return blueprints, rows.Err()
+}
+
+// scanBlueprint reads a blueprint row selected as name, stack, description,
+// config_json, metadata_json, created_at, updated_at
+func scanBlueprint(row interface{ Scan(...any) error }) (*StoredBlueprint, error) {
+	var (
+		blueprint            StoredBlueprint
+		metadataJSON         string
+		createdAt, updatedAt string
+	)
+	if err := row.Scan(&blueprint.Name, &blueprint.Stack, &blueprint.Description, &blueprint.Config, &metadataJSON, &createdAt, &updatedAt); err != nil {
+		return nil, err
+	}
+	var metadata entryMetadata
+	if json.Unmarshal([]byte(metadataJSON), &metadata) == nil {
+		blueprint.Source = metadata.Source
+	}
+
+	var err error
+	if blueprint.CreatedAt, err = ParseTimestamp(createdAt); err != nil {
+		return nil, err
+	}
+	if blueprint.UpdatedAt, err = ParseTimestamp(updatedAt); err != nil {
+		return nil, err
+	}
+	return &blueprint, nil
+}
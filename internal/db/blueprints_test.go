@@ -0,0 +1,89 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlueprintManager_SaveGetList(t *testing.T) {
+	manager, dbPath, cleanup := setupTestManager(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	require.NoError(t, manager.Open(ctx, dbPath))
+	defer manager.Close()
+
+	blueprints := NewBlueprintManager(manager)
+	blueprint := &StoredBlueprint{
+		Name:   "queue-stack",
+		Stack:  "microservice",
+		Config: `{"components":["nats"]}`,
+		Source: &InstallSource{URL: "https://example.com/packs.git", Commit: "0123abc"},
+	}
+	require.NoError(t, blueprints.Save(ctx, blueprint, false))
+
+	loaded, err := blueprints.Get(ctx, "queue-stack")
+	require.NoError(t, err)
+	assert.Equal(t, "microservice", loaded.Stack)
+	assert.JSONEq(t, blueprint.Config, loaded.Config)
+	require.NotNil(t, loaded.Source)
+	assert.Equal(t, "0123abc", loaded.Source.Commit)
+
+	assert.ErrorIs(t, blueprints.Save(ctx, blueprint, false), ErrBlueprintExists)
+	blueprint.Stack = "web"
+	require.NoError(t, blueprints.Save(ctx, blueprint, true))
+
+	list, err := blueprints.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, "web", list[0].Stack)
+
+	require.NoError(t, NewTrashManager(manager).Delete(ctx, KindBlueprint, "queue-stack"))
+	_, err = blueprints.Get(ctx, "queue-stack")
+	assert.ErrorIs(t, err, ErrBlueprintNotFound)
+	assert.ErrorContains(t, blueprints.Save(ctx, blueprint, false), "in the trash")
+}
+
+func TestBlueprintManager_SaveValidates(t *testing.T) {
+	manager, dbPath, cleanup := setupTestManager(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	require.NoError(t, manager.Open(ctx, dbPath))
+	defer manager.Close()
+
+	blueprints := NewBlueprintManager(manager)
+	assert.ErrorContains(t, blueprints.Save(ctx, &StoredBlueprint{Name: "Web Stack", Stack: "web", Config: "{}"}, false), "invalid blueprint name")
+	assert.ErrorContains(t, blueprints.Save(ctx, &StoredBlueprint{Name: "web", Config: "{}"}, false), "no stack")
+	assert.ErrorContains(t, blueprints.Save(ctx, &StoredBlueprint{Name: "web", Stack: "web", Config: "{"}, false), "invalid configuration")
+}
+
+func TestBlueprintManager_CreateUpdateDelete(t *testing.T) {
+	manager, dbPath, cleanup := setupTestManager(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	require.NoError(t, manager.Open(ctx, dbPath))
+	defer manager.Close()
+
+	blueprints := NewBlueprintManager(manager)
+	blueprint := &StoredBlueprint{Name: "batch-stack", Stack: "cli", Description: "Batch jobs", Config: `{}`}
+	assert.ErrorIs(t, blueprints.Update(ctx, blueprint), ErrBlueprintNotFound)
+	require.NoError(t, blueprints.Create(ctx, blueprint))
+	assert.ErrorIs(t, blueprints.Create(ctx, blueprint), ErrBlueprintExists)
+
+	blueprint.Description = "Scheduled batch jobs"
+	require.NoError(t, blueprints.Update(ctx, blueprint))
+	loaded, err := blueprints.Get(ctx, "batch-stack")
+	require.NoError(t, err)
+	assert.Equal(t, "Scheduled batch jobs", loaded.Description)
+
+	require.NoError(t, blueprints.Delete(ctx, "batch-stack"))
+	assert.ErrorIs(t, blueprints.Delete(ctx, "batch-stack"), ErrBlueprintNotFound)
+	require.NoError(t, NewTrashManager(manager).Restore(ctx, KindBlueprint, "batch-stack"))
+	_, err = blueprints.Get(ctx, "batch-stack")
+	assert.NoError(t, err)
+}
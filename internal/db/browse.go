@@ -0,0 +1,232 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// BrowseTable is a table of the gogo database with its row count
+type BrowseTable struct {
+	Name string
+	Rows int
+}
+
+// BrowsePage is a page of rows from one table, rendered as text
+type BrowsePage struct {
+	Table   string
+	Columns []string
+	Rows    [][]string
+	Offset  int
+	Total   int
+}
+
+// AuditEntry is a row of the audit log
+type AuditEntry struct {
+	Actor     string
+	Action    string
+	Entity    string
+	Details   string
+	CreatedAt string
+}
+
+// BrowseManager reads the gogo database for db browse. It only ever reads.
+type BrowseManager struct {
+	db *Manager
+}
+
+// NewBrowseManager creates a new browse manager
+func NewBrowseManager(manager *Manager) *BrowseManager {
+	return &BrowseManager{
+		db: manager,
+	}
+}
+
+// Tables lists the tables of the database with their row counts. The search
+// index is derived data and left out.
+func (b *BrowseManager) Tables(ctx context.Context) ([]BrowseTable, error) {
+	rows, err := b.db.db.QueryContext(ctx,
+		`SELECT name FROM sqlite_master WHERE type='table' AND name NOT LIKE 'sqlite_%' AND name NOT LIKE 'search_index%' ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tables: %w", err)
+	}
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan table name: %w", err)
+		}
+		names = append(names, name)
+	}
+	err = rows.Err()
+	rows.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tables: %w", err)
+	}
+
+	tables := make([]BrowseTable, len(names))
+	for i, name := range names {
+		tables[i].Name = name
+		if err := b.db.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM "+quoteIdentifier(name)).Scan(&tables[i].Rows); err != nil {
+			return nil, fmt.Errorf("failed to count rows of %s: %w", name, err)
+		}
+	}
+	return tables, nil
+}
+
+// Rows returns up to limit rows of table starting at offset, in rowid order.
+// Long values are cut to keep pages readable; Row returns a row in full.
+func (b *BrowseManager) Rows(ctx context.Context, table string, offset, limit int) (*BrowsePage, error) {
+	if err := b.checkTable(ctx, table); err != nil {
+		return nil, err
+	}
+	page := &BrowsePage{Table: table, Offset: offset}
+	if err := b.db.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM "+quoteIdentifier(table)).Scan(&page.Total); err != nil {
+		return nil, fmt.Errorf("failed to count rows of %s: %w", table, err)
+	}
+
+	query := fmt.Sprintf("SELECT * FROM %s ORDER BY rowid LIMIT ? OFFSET ?", quoteIdentifier(table))
+	columns, values, err := b.query(ctx, query, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", table, err)
+	}
+	page.Columns = columns
+	for _, row := range values {
+		for i, value := range row {
+			row[i] = truncateValue(value, 60)
+		}
+		page.Rows = append(page.Rows, row)
+	}
+	return page, nil
+}
+
+// Row returns the row of table at offset as column/value pairs, untruncated
+func (b *BrowseManager) Row(ctx context.Context, table string, offset int) ([]string, []string, error) {
+	if err := b.checkTable(ctx, table); err != nil {
+		return nil, nil, err
+	}
+	query := fmt.Sprintf("SELECT * FROM %s ORDER BY rowid LIMIT 1 OFFSET ?", quoteIdentifier(table))
+	columns, values, err := b.query(ctx, query, offset)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read %s: %w", table, err)
+	}
+	if len(values) == 0 {
+		return nil, nil, fmt.Errorf("%s has no row %d", table, offset+1)
+	}
+	return columns, values[0], nil
+}
+
+// Audits returns the most recent audit entries, newest first. A non-empty
+// entity restricts them to one template or blueprint ("template:name").
+func (b *BrowseManager) Audits(ctx context.Context, entity string, limit int) ([]AuditEntry, error) {
+	query := `SELECT actor, action, entity, details_json, created_at FROM audits`
+	var args []any
+	if entity != "" {
+		query += ` WHERE entity = ?`
+		args = append(args, entity)
+	}
+	query += ` ORDER BY created_at DESC, id DESC LIMIT ?`
+	args = append(args, limit)
+
+	rows, err := b.db.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query audit log: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []AuditEntry
+	for rows.Next() {
+		var entry AuditEntry
+		var createdAt any
+		if err := rows.Scan(&entry.Actor, &entry.Action, &entry.Entity, &entry.Details, &createdAt); err != nil {
+			return nil, fmt.Errorf("failed to scan audit entry: %w", err)
+		}
+		entry.CreatedAt = formatBrowseValue(createdAt)
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+// checkTable rejects names that are not tables of the database, since table
+// names cannot be bound as query parameters
+func (b *BrowseManager) checkTable(ctx context.Context, table string) error {
+	var count int
+	if err := b.db.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM sqlite_master WHERE type='table' AND name = ?`, table).Scan(&count); err != nil {
+		return fmt.Errorf("failed to look up table %s: %w", table, err)
+	}
+	if count == 0 {
+		return fmt.Errorf("table '%s' not found", table)
+	}
+	return nil
+}
+
+func (b *BrowseManager) query(ctx context.Context, query string, args ...any) ([]string, [][]string, error) {
+	rows, err := b.db.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, nil, err
+	}
+	var result [][]string
+	for rows.Next() {
+		values := make([]any, len(columns))
+		valuePtrs := make([]any, len(columns))
+		for i := range values {
+			valuePtrs[i] = &values[i]
+		}
+		if err := rows.Scan(valuePtrs...); err != nil {
+			return nil, nil, err
+		}
+		row := make([]string, len(columns))
+		for i, value := range values {
+			row[i] = formatBrowseValue(value)
+		}
+		result = append(result, row)
+	}
+	return columns, result, rows.Err()
+}
+
+// formatBrowseValue renders a column value as text. Binary values such as
+// template archives are shown by size.
+func formatBrowseValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "NULL"
+	case []byte:
+		if isText(v) {
+			return string(v)
+		}
+		return fmt.Sprintf("<%d bytes>", len(v))
+	case time.Time:
+		return FormatTimestamp(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func isText(data []byte) bool {
+	for _, c := range data {
+		if c == 0 {
+			return false
+		}
+	}
+	return utf8.Valid(data)
+}
+
+// truncateValue shortens a value to a single line of at most width runes
+func truncateValue(value string, width int) string {
+	value = strings.Join(strings.Fields(value), " ")
+	runes := []rune(value)
+	if len(runes) <= width {
+		return value
+	}
+	return string(runes[:width-1]) + "…"
+}
@@ -0,0 +1,71 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBrowseManager(t *testing.T) {
+	manager, dbPath, cleanup := setupTestManager(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	require.NoError(t, manager.Open(ctx, dbPath))
+	defer manager.Close()
+
+	templates := NewTemplateManager(manager)
+	for _, name := range []string{"rest-api", "worker", "cli-tool"} {
+		require.NoError(t, templates.Save(ctx, &StoredTemplate{Name: name, Kind: "project", Description: "A " + name + " template",
+			Files: []StoredFile{{Path: "main.go", Content: "package main"}}}, false))
+	}
+
+	browse := NewBrowseManager(manager)
+
+	tables, err := browse.Tables(ctx)
+	require.NoError(t, err)
+	counts := make(map[string]int)
+	for _, table := range tables {
+		counts[table.Name] = table.Rows
+	}
+	assert.Equal(t, 3, counts["templates"])
+	assert.Equal(t, 3, counts["audits"])
+	assert.NotContains(t, counts, "search_index")
+
+	page, err := browse.Rows(ctx, "templates", 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 3, page.Total)
+	require.Len(t, page.Rows, 2)
+	assert.Contains(t, page.Columns, "name")
+	assert.Contains(t, page.Rows[0], "worker")
+
+	columns, values, err := browse.Row(ctx, "templates", 2)
+	require.NoError(t, err)
+	assert.Len(t, values, len(columns))
+	assert.Contains(t, values, "A cli-tool template")
+
+	_, _, err = browse.Row(ctx, "templates", 3)
+	assert.Error(t, err)
+
+	_, err = browse.Rows(ctx, "templates; DROP TABLE templates", 0, 10)
+	assert.ErrorContains(t, err, "not found")
+
+	audits, err := browse.Audits(ctx, "", 10)
+	require.NoError(t, err)
+	require.Len(t, audits, 3)
+
+	audits, err = browse.Audits(ctx, KindTemplate+":worker", 10)
+	require.NoError(t, err)
+	require.Len(t, audits, 1)
+	assert.Equal(t, "add", audits[0].Action)
+	assert.Contains(t, audits[0].Details, `"name":"worker"`)
+}
+
+func TestTruncateValue(t *testing.T) {
+	assert.Equal(t, "a b", truncateValue("a\n  b", 10))
+	assert.Equal(t, "abcd…", truncateValue("abcdefgh", 5))
+	assert.Equal(t, "<3 bytes>", formatBrowseValue([]byte{0, 1, 2}))
+	assert.Equal(t, "NULL", formatBrowseValue(nil))
+}
@@ -0,0 +1,199 @@
+package db
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz"
+)
+
+// Compression is an algorithm backups and exports can be compressed with
+type Compression string
+
+const (
+	CompressionNone Compression = "none"
+	CompressionGzip Compression = "gzip"
+	CompressionZstd Compression = "zstd"
+	CompressionXZ   Compression = "xz"
+)
+
+// Compressions lists the supported algorithms
+var Compressions = []Compression{CompressionNone, CompressionGzip, CompressionZstd, CompressionXZ}
+
+// DefaultCompressionLevel selects each algorithm's own default level
+const DefaultCompressionLevel = 0
+
+// compressionFormats describes each algorithm: its file extension, the magic
+// bytes its streams start with and the range of levels it accepts
+var compressionFormats = map[Compression]struct {
+	extension string
+	magic     []byte
+	minLevel  int
+	maxLevel  int
+}{
+	CompressionGzip: {".gz", []byte{0x1f, 0x8b}, gzip.BestSpeed, gzip.BestCompression},
+	CompressionZstd: {".zst", []byte{0x28, 0xb5, 0x2f, 0xfd}, 1, 22},
+	CompressionXZ:   {".xz", []byte{0xfd, '7', 'z', 'X', 'Z', 0x00}, 1, 9},
+}
+
+// xzDictionarySizes are the dictionary sizes of the xz presets 1 to 9
+var xzDictionarySizes = []int{1 << 20, 2 << 20, 4 << 20, 4 << 20, 8 << 20, 8 << 20, 16 << 20, 32 << 20, 64 << 20}
+
+// ParseCompression parses an algorithm name; empty means CompressionNone
+func ParseCompression(name string) (Compression, error) {
+	if name == "" {
+		return CompressionNone, nil
+	}
+	for _, compression := range Compressions {
+		if string(compression) == name {
+			return compression, nil
+		}
+	}
+	names := make([]string, len(Compressions))
+	for i, compression := range Compressions {
+		names[i] = string(compression)
+	}
+	return "", fmt.Errorf("unknown compression %q (want %s)", name, strings.Join(names, ", "))
+}
+
+// Extension returns the file extension of the algorithm, such as .zst
+func (c Compression) Extension() string {
+	return compressionFormats[c].extension
+}
+
+// CompressionFromExtension returns the algorithm a file name's extension
+// indicates, or CompressionNone
+func CompressionFromExtension(name string) Compression {
+	for compression, format := range compressionFormats {
+		if strings.HasSuffix(name, format.extension) {
+			return compression
+		}
+	}
+	return CompressionNone
+}
+
+// TrimCompressionExtension removes a compression extension from a file name,
+// so export.sql.zst becomes export.sql
+func TrimCompressionExtension(name string) string {
+	return strings.TrimSuffix(name, CompressionFromExtension(name).Extension())
+}
+
+// ValidateCompressionLevel checks level is DefaultCompressionLevel or within
+// the range the algorithm accepts
+func ValidateCompressionLevel(c Compression, level int) error {
+	if level == DefaultCompressionLevel {
+		return nil
+	}
+	format, ok := compressionFormats[c]
+	if !ok {
+		return fmt.Errorf("compression level %d given without compression", level)
+	}
+	if level < format.minLevel || level > format.maxLevel {
+		return fmt.Errorf("%s compression level must be between %d and %d, got %d", c, format.minLevel, format.maxLevel, level)
+	}
+	return nil
+}
+
+// NewCompressWriter returns a writer compressing into w. Closing it flushes
+// the compressed stream but does not close w.
+func NewCompressWriter(w io.Writer, c Compression, level int) (io.WriteCloser, error) {
+	if err := ValidateCompressionLevel(c, level); err != nil {
+		return nil, err
+	}
+
+	switch c {
+	case CompressionNone, "":
+		return nopWriteCloser{w}, nil
+	case CompressionGzip:
+		if level == DefaultCompressionLevel {
+			level = gzip.DefaultCompression
+		}
+		return gzip.NewWriterLevel(w, level)
+	case CompressionZstd:
+		options := []zstd.EOption{}
+		if level != DefaultCompressionLevel {
+			options = append(options, zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(level)))
+		}
+		return zstd.NewWriter(w, options...)
+	case CompressionXZ:
+		config := xz.WriterConfig{}
+		if level != DefaultCompressionLevel {
+			config.DictCap = xzDictionarySizes[level-1]
+		}
+		return config.NewWriter(w)
+	default:
+		return nil, fmt.Errorf("unsupported compression: %s", c)
+	}
+}
+
+// DetectCompression identifies the algorithm of a stream from its magic bytes
+func DetectCompression(header []byte) Compression {
+	for compression, format := range compressionFormats {
+		if bytes.HasPrefix(header, format.magic) {
+			return compression
+		}
+	}
+	return CompressionNone
+}
+
+// DetectFileCompression identifies the algorithm a file is compressed with
+func DetectFileCompression(path string) (Compression, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	header := make([]byte, 8)
+	n, err := io.ReadFull(file, header)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return "", fmt.Errorf("failed to read file header: %w", err)
+	}
+	return DetectCompression(header[:n]), nil
+}
+
+// NewDecompressReader returns a reader decompressing r with the algorithm its
+// magic bytes identify; uncompressed streams are read as they are
+func NewDecompressReader(r io.Reader) (io.ReadCloser, Compression, error) {
+	buffered := bufio.NewReader(r)
+	header, err := buffered.Peek(8)
+	if err != nil && err != io.EOF {
+		return nil, "", fmt.Errorf("failed to read header: %w", err)
+	}
+
+	compression := DetectCompression(header)
+	var reader io.ReadCloser
+	switch compression {
+	case CompressionGzip:
+		reader, err = gzip.NewReader(buffered)
+	case CompressionZstd:
+		var decoder *zstd.Decoder
+		decoder, err = zstd.NewReader(buffered)
+		if err == nil {
+			reader = decoder.IOReadCloser()
+		}
+	case CompressionXZ:
+		var xzReader *xz.Reader
+		xzReader, err = xz.NewReader(buffered)
+		reader = io.NopCloser(xzReader)
+	default:
+		reader = io.NopCloser(buffered)
+	}
+	if err != nil {
+		return nil, compression, fmt.Errorf("invalid %s stream: %w", compression, err)
+	}
+	return reader, compression, nil
+}
+
+// nopWriteCloser passes writes through uncompressed
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
@@ -0,0 +1,176 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompression_RoundTrip(t *testing.T) {
+	content := []byte(strings.Repeat("INSERT INTO templates VALUES ('gogo');\n", 200))
+
+	for _, compression := range Compressions {
+		for _, level := range []int{DefaultCompressionLevel, 1} {
+			if compression == CompressionNone && level != DefaultCompressionLevel {
+				continue
+			}
+			var buf bytes.Buffer
+			w, err := NewCompressWriter(&buf, compression, level)
+			require.NoError(t, err)
+			_, err = w.Write(content)
+			require.NoError(t, err)
+			require.NoError(t, w.Close())
+
+			if compression != CompressionNone {
+				assert.Less(t, buf.Len(), len(content), "%s level %d", compression, level)
+			}
+			assert.Equal(t, compression, DetectCompression(buf.Bytes()))
+
+			r, detected, err := NewDecompressReader(&buf)
+			require.NoError(t, err)
+			assert.Equal(t, compression, detected)
+			decompressed, err := io.ReadAll(r)
+			require.NoError(t, err)
+			require.NoError(t, r.Close())
+			assert.Equal(t, content, decompressed, "%s level %d", compression, level)
+		}
+	}
+}
+
+func TestCompression_Levels(t *testing.T) {
+	assert.NoError(t, ValidateCompressionLevel(CompressionZstd, 22))
+	assert.NoError(t, ValidateCompressionLevel(CompressionXZ, 9))
+	assert.NoError(t, ValidateCompressionLevel(CompressionNone, DefaultCompressionLevel))
+	assert.ErrorContains(t, ValidateCompressionLevel(CompressionGzip, 10), "between 1 and 9")
+	assert.ErrorContains(t, ValidateCompressionLevel(CompressionZstd, 23), "between 1 and 22")
+	assert.ErrorContains(t, ValidateCompressionLevel(CompressionNone, 3), "without compression")
+
+	_, err := NewCompressWriter(io.Discard, CompressionXZ, 10)
+	assert.Error(t, err)
+}
+
+func TestCompression_Names(t *testing.T) {
+	compression, err := ParseCompression("zstd")
+	require.NoError(t, err)
+	assert.Equal(t, CompressionZstd, compression)
+	compression, err = ParseCompression("")
+	require.NoError(t, err)
+	assert.Equal(t, CompressionNone, compression)
+	_, err = ParseCompression("bzip2")
+	assert.ErrorContains(t, err, "none, gzip, zstd, xz")
+
+	assert.Equal(t, CompressionXZ, CompressionFromExtension("export.sql.xz"))
+	assert.Equal(t, CompressionNone, CompressionFromExtension("export.sql"))
+	assert.Equal(t, "export.json", TrimCompressionExtension("export.json.zst"))
+	assert.Equal(t, "backup.db", TrimCompressionExtension("backup.db"))
+	assert.Equal(t, ".gz", CompressionGzip.Extension())
+}
+
+func TestCompression_CorruptStream(t *testing.T) {
+	// zstd magic bytes followed by garbage
+	r, _, err := NewDecompressReader(bytes.NewReader([]byte{0x28, 0xb5, 0x2f, 0xfd, 1, 2, 3, 4}))
+	if err == nil {
+		_, err = io.ReadAll(r)
+	}
+	assert.Error(t, err)
+}
+
+func TestBackupManager_CompressionAlgorithms(t *testing.T) {
+	captureWarnings(t)
+	manager, dbPath, cleanup := setupTestManager(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	require.NoError(t, manager.Open(ctx, dbPath))
+	setupExportData(t, ctx, manager)
+	manager.Close()
+
+	for _, compression := range []Compression{CompressionZstd, CompressionXZ} {
+		t.Run(string(compression), func(t *testing.T) {
+			backupPath := filepath.Join(t.TempDir(), "backup.db"+compression.Extension())
+			require.NoError(t, NewBackupManager(manager, dbPath).Backup(ctx, BackupOptions{
+				OutputPath:  backupPath,
+				Compression: compression,
+				Level:       3,
+				Verify:      true,
+			}))
+
+			info, err := NewBackupManager(manager, dbPath).GetBackupInfo(backupPath)
+			require.NoError(t, err)
+			assert.True(t, info.IsCompressed)
+			assert.Equal(t, compression, info.Compression)
+			assert.Contains(t, info.String(), "Compressed ("+string(compression)+")")
+
+			restorePath := filepath.Join(t.TempDir(), "restored.db")
+			restored := NewManager()
+			require.NoError(t, NewBackupManager(restored, restorePath).Restore(ctx, RestoreOptions{
+				BackupPath: backupPath,
+				Verify:     true,
+			}))
+			require.NoError(t, restored.Open(ctx, restorePath))
+			defer restored.Close()
+
+			var count int
+			require.NoError(t, restored.GetDB().QueryRowContext(ctx, "SELECT COUNT(*) FROM templates").Scan(&count))
+			assert.Equal(t, 20, count)
+		})
+	}
+}
+
+func TestExportManager_CompressedExport(t *testing.T) {
+	manager, dbPath, cleanup := setupTestManager(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	require.NoError(t, manager.Open(ctx, dbPath))
+	defer manager.Close()
+	setupExportData(t, ctx, manager)
+
+	exportManager := NewExportManager(manager)
+	exportManager.now = func() time.Time { return time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC) }
+
+	for _, format := range []ExportFormat{FormatSQL, FormatJSON} {
+		t.Run(string(format), func(t *testing.T) {
+			dir := t.TempDir()
+			plain := filepath.Join(dir, "export."+string(format))
+			compressed := plain + CompressionZstd.Extension()
+
+			require.NoError(t, exportManager.Export(ctx, ExportOptions{
+				OutputPath: plain, Format: format, IncludeSchema: true, IncludeData: true,
+			}))
+			require.NoError(t, exportManager.Export(ctx, ExportOptions{
+				OutputPath: compressed, Format: format, IncludeSchema: true, IncludeData: true,
+				Compression: CompressionZstd, Level: 19,
+			}))
+
+			detected, err := DetectFileCompression(compressed)
+			require.NoError(t, err)
+			assert.Equal(t, CompressionZstd, detected)
+			assert.Less(t, fileSize(t, compressed), fileSize(t, plain))
+
+			// Imports detect the compression from the contents
+			assert.NoError(t, exportManager.Import(ctx, ImportOptions{
+				InputPath: compressed, Format: format, Validate: true, DryRun: true,
+			}))
+		})
+	}
+
+	err := exportManager.Export(ctx, ExportOptions{
+		OutputPath: filepath.Join(t.TempDir(), "export"), Format: FormatCSV, Compression: CompressionGzip,
+	})
+	assert.ErrorContains(t, err, "CSV exports cannot be compressed")
+}
+
+func fileSize(t *testing.T, path string) int64 {
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	return info.Size()
+}
@@ -0,0 +1,49 @@
+package db
+
+import (
+	"context"
+	"fmt"
+)
+
+// EntryRecord is everything the database holds about one template or
+// blueprint, exported so a project archive can outlive the database
+type EntryRecord struct {
+	Kind        string                `json:"kind"`
+	Name        string                `json:"name"`
+	Row         TableRow              `json:"row,omitempty"` // absent for built-in entries
+	ColumnTypes map[string]ColumnType `json:"column_types,omitempty"`
+	Tags        []string              `json:"tags,omitempty"`
+	Annotation  *Annotation           `json:"annotation,omitempty"`
+}
+
+// ExportEntry collects the stored row, tags and annotation of a template or
+// blueprint. Entries the database knows nothing about yield an empty record.
+func (e *ExportManager) ExportEntry(ctx context.Context, kind, name string) (*EntryRecord, error) {
+	var table string
+	switch kind {
+	case KindTemplate:
+		table = "templates"
+	case KindBlueprint:
+		table = "blueprints"
+	default:
+		return nil, fmt.Errorf("unknown entry kind: %s", kind)
+	}
+
+	record := &EntryRecord{Kind: kind, Name: name}
+	rows, types, err := e.queryRows(ctx, fmt.Sprintf("SELECT * FROM %s WHERE name = ?", table), name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s '%s': %w", kind, name, err)
+	}
+	if len(rows) > 0 {
+		record.Row = rows[0]
+		record.ColumnTypes = types
+	}
+
+	if record.Tags, err = NewTagManager(e.db).TagsFor(ctx, kind, name); err != nil {
+		return nil, err
+	}
+	if record.Annotation, err = NewAnnotationManager(e.db).Get(ctx, kind, name); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
@@ -0,0 +1,41 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportManager_ExportEntry(t *testing.T) {
+	manager, dbPath, cleanup := setupTestManager(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	require.NoError(t, manager.Open(ctx, dbPath))
+	defer manager.Close()
+	setupExportData(t, ctx, manager)
+
+	require.NoError(t, NewTagManager(manager).Tag(ctx, KindBlueprint, "web-stack", "internal"))
+	require.NoError(t, NewAnnotationManager(manager).Save(ctx, &Annotation{Kind: KindBlueprint, Name: "web-stack", Note: "our default", Rating: 5}))
+
+	exportManager := NewExportManager(manager)
+	record, err := exportManager.ExportEntry(ctx, KindBlueprint, "web-stack")
+	require.NoError(t, err)
+	assert.Equal(t, "web", record.Row["stack"])
+	assert.Equal(t, ColumnText, record.ColumnTypes["stack"])
+	assert.Equal(t, []string{"internal"}, record.Tags)
+	require.NotNil(t, record.Annotation)
+	assert.Equal(t, 5, record.Annotation.Rating)
+
+	// Built-in entries have no row, tags or annotation
+	record, err = exportManager.ExportEntry(ctx, KindTemplate, "api")
+	require.NoError(t, err)
+	assert.Nil(t, record.Row)
+	assert.Empty(t, record.Tags)
+	assert.Nil(t, record.Annotation)
+
+	_, err = exportManager.ExportEntry(ctx, "plugin", "x")
+	assert.Error(t, err)
+}
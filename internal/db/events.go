@@ -0,0 +1,153 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/user/gogo/internal/events"
+)
+
+// EventRecorder records lifecycle events in the audits table, where db
+// browse and search show them next to template and blueprint changes
+type EventRecorder struct {
+	db    *Manager
+	actor string
+}
+
+// NewEventRecorder creates a new event recorder
+func NewEventRecorder(manager *Manager) *EventRecorder {
+	return &EventRecorder{
+		db:    manager,
+		actor: currentActor(),
+	}
+}
+
+// Record is an events.Handler adding an audit entry for the event, as in
+// action "generated" on entity "project:shop"
+func (r *EventRecorder) Record(ctx context.Context, event events.Event) error {
+	details := make(map[string]any, len(event.Details)+1)
+	for key, value := range event.Details {
+		details[key] = value
+	}
+	if event.Dir != "" {
+		details["dir"] = event.Dir
+	}
+	data, err := json.Marshal(details)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s details: %w", event.Kind, err)
+	}
+
+	query := `INSERT INTO audits (actor, action, entity, details_json, created_at) VALUES (?, ?, ?, ?, ?)`
+	if _, err := r.db.db.ExecContext(ctx, query, r.actor, event.Action(), event.Entity(), string(data), FormatTimestamp(event.Time)); err != nil {
+		return fmt.Errorf("failed to record audit entry: %w", err)
+	}
+	return nil
+}
+
+// Where the stored hooks come from
+const (
+	HookSourceLocal      = "local"       // Added to the database by its user
+	HookSourceThirdParty = "third-party" // Brought in by gogo db import or restore
+)
+
+// StoredHook is an enabled row of the hooks table
+type StoredHook struct {
+	Name     string
+	Event    string
+	Language string
+	Script   string
+	Source   string // HookSourceLocal or HookSourceThirdParty
+}
+
+// EventHooks returns the enabled hooks attached to event, in the order they
+// were added
+func (m *Manager) EventHooks(ctx context.Context, event string) ([]StoredHook, error) {
+	rows, err := m.db.QueryContext(ctx,
+		`SELECT name, event, language, script, source FROM hooks WHERE event = ? AND enabled = 1 ORDER BY id`, event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query hooks: %w", err)
+	}
+	defer rows.Close()
+
+	var hooks []StoredHook
+	for rows.Next() {
+		var hook StoredHook
+		if err := rows.Scan(&hook.Name, &hook.Event, &hook.Language, &hook.Script, &hook.Source); err != nil {
+			return nil, fmt.Errorf("failed to scan hook: %w", err)
+		}
+		hooks = append(hooks, hook)
+	}
+	return hooks, rows.Err()
+}
+
+// storedHooks returns the event and script of each stored hook by id, or
+// nothing when the hooks table does not exist
+func storedHooks(ctx context.Context, tx *sql.Tx) (map[int64]string, error) {
+	hooks := make(map[int64]string)
+	var exists int
+	if err := tx.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'hooks'`).Scan(&exists); err != nil || exists == 0 {
+		return hooks, err
+	}
+
+	rows, err := tx.QueryContext(ctx, `SELECT id, event, script FROM hooks`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query hooks: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			id            int64
+			event, script string
+		)
+		if err := rows.Scan(&id, &event, &script); err != nil {
+			return nil, fmt.Errorf("failed to scan hook: %w", err)
+		}
+		hooks[id] = event + "\x00" + script
+	}
+	return hooks, rows.Err()
+}
+
+// markImportedHooks marks the hooks an import added or changed, those not
+// among before with the same event and script, as third-party, so that they
+// only run once confirmed. An imported hooks table without a source column
+// came from the import as a whole.
+func markImportedHooks(ctx context.Context, tx *sql.Tx, before map[int64]string) error {
+	after, err := storedHooks(ctx, tx)
+	if err != nil || len(after) == 0 {
+		return err
+	}
+
+	var hasSource int
+	if err := tx.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM pragma_table_info('hooks') WHERE name = 'source'`).Scan(&hasSource); err != nil {
+		return fmt.Errorf("failed to inspect hooks: %w", err)
+	}
+	if hasSource == 0 {
+		if _, err := tx.ExecContext(ctx, `ALTER TABLE hooks ADD COLUMN source TEXT NOT NULL DEFAULT '`+HookSourceThirdParty+`'`); err != nil {
+			return fmt.Errorf("failed to add hooks.source: %w", err)
+		}
+		return nil
+	}
+
+	for id, hook := range after {
+		if before[id] == hook {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, `UPDATE hooks SET source = ? WHERE id = ?`, HookSourceThirdParty, id); err != nil {
+			return fmt.Errorf("failed to mark imported hook: %w", err)
+		}
+	}
+	return nil
+}
+
+// markRestoredHooks marks every hook of the database, restored from a
+// backup that may be someone else's, as third-party
+func (m *Manager) markRestoredHooks(ctx context.Context) error {
+	if _, err := m.db.ExecContext(ctx, `UPDATE hooks SET source = ?`, HookSourceThirdParty); err != nil {
+		return fmt.Errorf("failed to mark restored hooks: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/user/gogo/internal/events"
+)
+
+func TestEventRecorder_Record(t *testing.T) {
+	manager, dbPath, cleanup := setupTestManager(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	require.NoError(t, manager.Open(ctx, dbPath))
+	defer manager.Close()
+
+	bus := events.NewBus()
+	bus.Subscribe(NewEventRecorder(manager).Record)
+	bus.Publish(ctx, events.Event{
+		Kind:    events.ProjectGenerated,
+		Subject: "shop",
+		Dir:     "/src/shop",
+		Details: map[string]any{"template": "api"},
+		Time:    time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+	})
+
+	entries, err := NewBrowseManager(manager).Audits(ctx, "project:shop", 10)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "generated", entries[0].Action)
+	assert.JSONEq(t, `{"template":"api","dir":"/src/shop"}`, entries[0].Details)
+	assert.Equal(t, FormatTimestamp(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)), entries[0].CreatedAt)
+}
+
+func TestManager_EventHooks(t *testing.T) {
+	manager, dbPath, cleanup := setupTestManager(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	require.NoError(t, manager.Open(ctx, dbPath))
+	defer manager.Close()
+
+	_, err := manager.GetDB().ExecContext(ctx, `INSERT INTO hooks (name, event, script, enabled) VALUES
+		('fmt', 'component.generated', 'gofmt -w .', 1),
+		('off', 'component.generated', 'echo off', 0),
+		('tidy', 'project.generated', 'go mod tidy', 1),
+		('vet', 'component.generated', 'go vet ./...', 1)`)
+	require.NoError(t, err)
+
+	hooks, err := manager.EventHooks(ctx, string(events.ComponentGenerated))
+	require.NoError(t, err)
+	require.Len(t, hooks, 2)
+	assert.Equal(t, "fmt", hooks[0].Name)
+	assert.Equal(t, "shell", hooks[0].Language)
+	assert.Equal(t, HookSourceLocal, hooks[0].Source)
+	assert.Equal(t, "vet", hooks[1].Name)
+}
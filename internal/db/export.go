@@ -1,28 +1,37 @@
 package db
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/csv"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/fatih/color"
+	"github.com/user/gogo/internal/fsutil"
 )
 
 // ExportManager handles database export and import operations
 type ExportManager struct {
-	db *Manager
+	db  *Manager
+	now func() time.Time
 }
 
 // NewExportManager creates a new export manager
 func NewExportManager(manager *Manager) *ExportManager {
 	return &ExportManager{
-		db: manager,
+		db:  manager,
+		now: time.Now,
 	}
 }
 
@@ -33,6 +42,9 @@ type ExportOptions struct {
 	Tables        []string
 	IncludeSchema bool
 	IncludeData   bool
+	Parallel      int         // Tables exported concurrently; values below 1 export sequentially
+	Compression   Compression // Algorithm SQL and JSON exports are compressed with; empty for none
+	Level         int         // Compression level; DefaultCompressionLevel for the algorithm's default
 	Verbose       bool
 }
 
@@ -70,8 +82,15 @@ type ExportMetadata struct {
 	Format     string    `json:"format"`
 	TableCount int       `json:"table_count"`
 	RowCount   int       `json:"row_count"`
+	// ColumnTypes maps table to column to the encoding of its values;
+	// exports before version 1.1 have none
+	ColumnTypes map[string]map[string]ColumnType `json:"column_types,omitempty"`
 }
 
+// JSONExportVersion is the version of the JSON export layout; 1.1 added
+// typed column encoding
+const JSONExportVersion = "1.1"
+
 // TableRow represents a generic table row
 type TableRow map[string]interface{}
 
@@ -107,6 +126,13 @@ func (e *ExportManager) Export(ctx context.Context, opts ExportOptions) error {
 		return fmt.Errorf("failed to create output directory: %w", err)
 	}
 
+	if opts.Compression != "" && opts.Compression != CompressionNone && opts.Format == FormatCSV {
+		return fmt.Errorf("CSV exports cannot be compressed; compress the export directory instead")
+	}
+	if err := ValidateCompressionLevel(opts.Compression, opts.Level); err != nil {
+		return err
+	}
+
 	// Export based on format
 	switch opts.Format {
 	case FormatSQL:
@@ -122,51 +148,72 @@ func (e *ExportManager) Export(ctx context.Context, opts ExportOptions) error {
 
 // exportSQL exports database as SQL dump
 func (e *ExportManager) exportSQL(ctx context.Context, opts ExportOptions) error {
-	file, err := os.Create(opts.OutputPath)
-	if err != nil {
-		return fmt.Errorf("failed to create output file: %w", err)
-	}
-	defer file.Close()
-
-	// Write header
-	fmt.Fprintf(file, "-- gogo database export\n")
-	fmt.Fprintf(file, "-- Generated on: %s\n", time.Now().Format("2006-01-02 15:04:05"))
-	fmt.Fprintf(file, "-- Format: SQL\n\n")
-
 	// Get tables to export
 	tables, err := e.getTablesToExport(ctx, opts.Tables)
 	if err != nil {
 		return fmt.Errorf("failed to get tables: %w", err)
 	}
 
-	totalRows := 0
-
-	for _, table := range tables {
-		if opts.Verbose {
-			color.Yellow("Exporting table: %s", table)
-		}
+	// Each table is rendered into its own buffer, then written in table order
+	buffers := make([]bytes.Buffer, len(tables))
+	rowCounts, err := exportTablesParallel(ctx, tables, opts, func(ctx context.Context, i int, table string) (int, error) {
+		w := &buffers[i]
 
 		// Export table schema if requested
 		if opts.IncludeSchema {
-			if err := e.exportTableSchema(ctx, file, table); err != nil {
-				return fmt.Errorf("failed to export schema for table %s: %w", table, err)
+			if err := e.exportTableSchema(ctx, w, table); err != nil {
+				return 0, fmt.Errorf("failed to export schema for table %s: %w", table, err)
 			}
 		}
 
+		rows := 0
 		// Export table data if requested
 		if opts.IncludeData {
-			rows, err := e.exportTableData(ctx, file, table)
+			n, err := e.exportTableData(ctx, w, table)
 			if err != nil {
-				return fmt.Errorf("failed to export data for table %s: %w", table, err)
+				return 0, fmt.Errorf("failed to export data for table %s: %w", table, err)
 			}
-			totalRows += rows
+			rows = n
 		}
 
-		fmt.Fprintf(file, "\n")
+		fmt.Fprintf(w, "\n")
+		return rows, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	file, err := fsutil.Create(opts.OutputPath, FileMode())
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	defer file.Close()
+
+	w, err := NewCompressWriter(file, opts.Compression, opts.Level)
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+
+	// Write header
+	fmt.Fprintf(w, "-- gogo database export\n")
+	fmt.Fprintf(w, "-- Generated on: %s\n", FormatTimestamp(e.now()))
+	fmt.Fprintf(w, "-- Format: SQL\n\n")
+
+	for i := range buffers {
+		if _, err := buffers[i].WriteTo(w); err != nil {
+			return fmt.Errorf("failed to write output file: %w", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to finalize compression: %w", err)
+	}
+	if err := file.Commit(); err != nil {
+		return fmt.Errorf("failed to write output file: %w", err)
 	}
 
 	if opts.Verbose {
-		color.Green("✓ SQL export completed: %d tables, %d rows", len(tables), totalRows)
+		color.Green("✓ SQL export completed: %d tables, %d rows", len(tables), sum(rowCounts))
 	}
 
 	return nil
@@ -177,9 +224,10 @@ func (e *ExportManager) exportJSON(ctx context.Context, opts ExportOptions) erro
 	// Collect data
 	exportData := &ExportedData{
 		Metadata: ExportMetadata{
-			ExportedAt: time.Now(),
-			Version:    "1.0",
-			Format:     "json",
+			ExportedAt:  e.now().UTC(),
+			Version:     JSONExportVersion,
+			Format:      "json",
+			ColumnTypes: make(map[string]map[string]ColumnType),
 		},
 		Tables: make(map[string][]TableRow),
 	}
@@ -190,20 +238,24 @@ func (e *ExportManager) exportJSON(ctx context.Context, opts ExportOptions) erro
 		return fmt.Errorf("failed to get tables: %w", err)
 	}
 
-	totalRows := 0
-
-	for _, table := range tables {
-		if opts.Verbose {
-			color.Yellow("Exporting table: %s", table)
-		}
-
-		rows, err := e.getTableRows(ctx, table)
+	tableRows := make([][]TableRow, len(tables))
+	tableTypes := make([]map[string]ColumnType, len(tables))
+	rowCounts, err := exportTablesParallel(ctx, tables, opts, func(ctx context.Context, i int, table string) (int, error) {
+		rows, types, err := e.getTableRows(ctx, table)
 		if err != nil {
-			return fmt.Errorf("failed to get rows for table %s: %w", table, err)
+			return 0, fmt.Errorf("failed to get rows for table %s: %w", table, err)
 		}
+		tableRows[i] = rows
+		tableTypes[i] = types
+		return len(rows), nil
+	})
+	if err != nil {
+		return err
+	}
 
-		exportData.Tables[table] = rows
-		totalRows += len(rows)
+	for i, table := range tables {
+		exportData.Tables[table] = tableRows[i]
+		exportData.Metadata.ColumnTypes[table] = tableTypes[i]
 
 		// Special handling for templates and blueprints
 		if table == "templates" {
@@ -219,21 +271,34 @@ func (e *ExportManager) exportJSON(ctx context.Context, opts ExportOptions) erro
 		}
 	}
 
+	totalRows := sum(rowCounts)
 	exportData.Metadata.TableCount = len(tables)
 	exportData.Metadata.RowCount = totalRows
 
 	// Write JSON to file
-	file, err := os.Create(opts.OutputPath)
+	file, err := fsutil.Create(opts.OutputPath, FileMode())
 	if err != nil {
 		return fmt.Errorf("failed to create output file: %w", err)
 	}
 	defer file.Close()
 
-	encoder := json.NewEncoder(file)
+	w, err := NewCompressWriter(file, opts.Compression, opts.Level)
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+
+	encoder := json.NewEncoder(w)
 	encoder.SetIndent("", "  ")
 	if err := encoder.Encode(exportData); err != nil {
 		return fmt.Errorf("failed to encode JSON: %w", err)
 	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to finalize compression: %w", err)
+	}
+	if err := file.Commit(); err != nil {
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
 
 	if opts.Verbose {
 		color.Green("✓ JSON export completed: %d tables, %d rows", len(tables), totalRows)
@@ -250,40 +315,123 @@ func (e *ExportManager) exportCSV(ctx context.Context, opts ExportOptions) error
 		return fmt.Errorf("failed to get tables: %w", err)
 	}
 
-	totalRows := 0
-
 	// Create base directory for CSV files
-	baseDir := strings.TrimSuffix(opts.OutputPath, filepath.Ext(opts.OutputPath))
+	baseDir := csvExportDir(opts.OutputPath)
 	if err := os.MkdirAll(baseDir, 0755); err != nil {
 		return fmt.Errorf("failed to create CSV directory: %w", err)
 	}
 
-	for _, table := range tables {
-		if opts.Verbose {
-			color.Yellow("Exporting table: %s", table)
-		}
-
-		csvFile := filepath.Join(baseDir, table+".csv")
-		rows, err := e.exportTableCSV(ctx, csvFile, table)
+	// Every table has its own file, so workers never share a writer
+	files := make([]ManifestFile, len(tables))
+	rowCounts, err := exportTablesParallel(ctx, tables, opts, func(ctx context.Context, i int, table string) (int, error) {
+		file, err := e.exportTableCSV(ctx, baseDir, table)
 		if err != nil {
-			return fmt.Errorf("failed to export CSV for table %s: %w", table, err)
+			return 0, fmt.Errorf("failed to export CSV for table %s: %w", table, err)
 		}
-		totalRows += rows
+		files[i] = file
+		return file.Rows, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	manifest := &ExportManifest{
+		Version:    ManifestVersion,
+		Format:     string(FormatCSV),
+		ExportedAt: e.now().UTC(),
+		Files:      files,
+	}
+	if err := writeManifest(baseDir, manifest); err != nil {
+		return err
 	}
 
 	if opts.Verbose {
-		color.Green("✓ CSV export completed: %d tables, %d rows in %s", len(tables), totalRows, baseDir)
+		color.Green("✓ CSV export completed: %d tables, %d rows in %s", len(tables), sum(rowCounts), baseDir)
 	}
 
 	return nil
 }
 
+// exportTablesParallel runs export for every table using up to opts.Parallel
+// workers and returns per-table row counts in table order. The first error
+// cancels the remaining work, and cancelling ctx fails the export even if
+// every table that was started completed.
+func exportTablesParallel(parent context.Context, tables []string, opts ExportOptions,
+	export func(ctx context.Context, i int, table string) (int, error)) ([]int, error) {
+	workers := opts.Parallel
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(tables) {
+		workers = len(tables)
+	}
+
+	ctx, cancel := context.WithCancel(parent)
+	defer cancel()
+
+	rowCounts := make([]int, len(tables))
+	errs := make([]error, len(tables))
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if opts.Verbose {
+					color.Yellow("Exporting table: %s", tables[i])
+				}
+				rowCounts[i], errs[i] = export(ctx, i, tables[i])
+				if errs[i] != nil {
+					cancel()
+				}
+			}
+		}()
+	}
+
+feed:
+	for i := range tables {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if err := parent.Err(); err != nil {
+		return nil, err
+	}
+	// Report the first failing table in table order
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return rowCounts, nil
+}
+
+func sum(values []int) int {
+	total := 0
+	for _, v := range values {
+		total += v
+	}
+	return total
+}
+
 // Import imports data from a file
 func (e *ExportManager) Import(ctx context.Context, opts ImportOptions) error {
 	if opts.Verbose {
 		color.Yellow("Starting database import...")
 	}
 
+	// CSV exports are a directory named after the requested output path
+	if opts.Format == FormatCSV {
+		opts.InputPath = csvExportDir(opts.InputPath)
+	}
+
 	// Validate input file exists
 	if _, err := os.Stat(opts.InputPath); os.IsNotExist(err) {
 		return fmt.Errorf("input file does not exist: %s", opts.InputPath)
@@ -295,14 +443,28 @@ func (e *ExportManager) Import(ctx context.Context, opts ImportOptions) error {
 		return e.importSQL(ctx, opts)
 	case FormatJSON:
 		return e.importJSON(ctx, opts)
+	case FormatCSV:
+		return e.importCSV(ctx, opts)
 	default:
 		return fmt.Errorf("unsupported import format: %s", opts.Format)
 	}
 }
 
-// importSQL imports from SQL dump
+// importSQL imports from SQL dump, compressed or not
 func (e *ExportManager) importSQL(ctx context.Context, opts ImportOptions) error {
-	content, err := os.ReadFile(opts.InputPath)
+	file, err := os.Open(opts.InputPath)
+	if err != nil {
+		return fmt.Errorf("failed to read SQL file: %w", err)
+	}
+	defer file.Close()
+
+	reader, _, err := NewDecompressReader(file)
+	if err != nil {
+		return fmt.Errorf("failed to read SQL file: %w", err)
+	}
+	defer reader.Close()
+
+	content, err := io.ReadAll(reader)
 	if err != nil {
 		return fmt.Errorf("failed to read SQL file: %w", err)
 	}
@@ -322,6 +484,12 @@ func (e *ExportManager) importSQL(ctx context.Context, opts ImportOptions) error
 	}
 	defer tx.Rollback()
 
+	// Hooks the import brings in are someone else's
+	hooksBefore, err := storedHooks(ctx, tx)
+	if err != nil {
+		return err
+	}
+
 	executed := 0
 	for _, stmt := range statements {
 		stmt = strings.TrimSpace(stmt)
@@ -339,6 +507,9 @@ func (e *ExportManager) importSQL(ctx context.Context, opts ImportOptions) error
 		executed++
 	}
 
+	if err := markImportedHooks(ctx, tx, hooksBefore); err != nil {
+		return err
+	}
 	if err := tx.Commit(); err != nil {
 		return fmt.Errorf("failed to commit import transaction: %w", err)
 	}
@@ -347,7 +518,7 @@ func (e *ExportManager) importSQL(ctx context.Context, opts ImportOptions) error
 	return nil
 }
 
-// importJSON imports from JSON export
+// importJSON imports from JSON export, compressed or not
 func (e *ExportManager) importJSON(ctx context.Context, opts ImportOptions) error {
 	file, err := os.Open(opts.InputPath)
 	if err != nil {
@@ -355,8 +526,15 @@ func (e *ExportManager) importJSON(ctx context.Context, opts ImportOptions) erro
 	}
 	defer file.Close()
 
+	reader, _, err := NewDecompressReader(file)
+	if err != nil {
+		return fmt.Errorf("failed to open JSON file: %w", err)
+	}
+	defer reader.Close()
+
 	var exportData ExportedData
-	decoder := json.NewDecoder(file)
+	decoder := json.NewDecoder(reader)
+	decoder.UseNumber() // keep integers exact until their column type is known
 	if err := decoder.Decode(&exportData); err != nil {
 		return fmt.Errorf("failed to decode JSON: %w", err)
 	}
@@ -373,6 +551,18 @@ func (e *ExportManager) importJSON(ctx context.Context, opts ImportOptions) erro
 		return nil
 	}
 
+	tx, err := e.db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Hooks the import brings in are someone else's
+	hooksBefore, err := storedHooks(ctx, tx)
+	if err != nil {
+		return err
+	}
+
 	// Import data
 	totalImported := 0
 	for tableName, rows := range exportData.Tables {
@@ -380,26 +570,168 @@ func (e *ExportManager) importJSON(ctx context.Context, opts ImportOptions) erro
 			color.Yellow("Importing table: %s (%d rows)", tableName, len(rows))
 		}
 
-		imported, err := e.importTableRows(ctx, tableName, rows, opts.ReplaceExisting)
+		rows, err := decodeTableRows(rows, exportData.Metadata.ColumnTypes[tableName])
+		if err != nil {
+			return fmt.Errorf("failed to decode table %s: %w", tableName, err)
+		}
+
+		imported, err := insertTableRows(ctx, tx, tableName, rows, opts.ReplaceExisting)
 		if err != nil {
 			return fmt.Errorf("failed to import table %s: %w", tableName, err)
 		}
 		totalImported += imported
 	}
 
+	if err := markImportedHooks(ctx, tx, hooksBefore); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit import transaction: %w", err)
+	}
+
 	color.Green("✓ JSON import completed: %d rows imported", totalImported)
 	return nil
 }
 
+// importCSV imports a CSV export directory after verifying its manifest, so
+// a partial or corrupted transfer is rejected before any row is loaded
+func (e *ExportManager) importCSV(ctx context.Context, opts ImportOptions) error {
+	manifest, err := VerifyManifest(opts.InputPath)
+	if err != nil {
+		return err
+	}
+	if opts.Verbose {
+		color.Green("✓ Manifest verified: %d files", len(manifest.Files))
+	}
+
+	if opts.DryRun {
+		rows := 0
+		for _, file := range manifest.Files {
+			rows += file.Rows
+		}
+		color.Yellow("DRY RUN: Would import %d tables with %d total rows", len(manifest.Files), rows)
+		return nil
+	}
+
+	tx, err := e.db.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Hooks the import brings in are someone else's
+	hooksBefore, err := storedHooks(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	totalImported := 0
+	for _, file := range manifest.Files {
+		notNull, err := notNullColumns(ctx, tx, file.Table)
+		if err != nil {
+			return fmt.Errorf("failed to inspect table %s: %w", file.Table, err)
+		}
+		rows, err := readCSVRows(filepath.Join(opts.InputPath, file.Path), notNull)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", file.Path, err)
+		}
+		if opts.Verbose {
+			color.Yellow("Importing table: %s (%d rows)", file.Table, len(rows))
+		}
+
+		imported, err := insertTableRows(ctx, tx, file.Table, rows, opts.ReplaceExisting)
+		if err != nil {
+			return fmt.Errorf("failed to import table %s: %w", file.Table, err)
+		}
+		totalImported += imported
+	}
+
+	if err := markImportedHooks(ctx, tx, hooksBefore); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit import transaction: %w", err)
+	}
+
+	color.Green("✓ CSV import completed: %d rows imported", totalImported)
+	return nil
+}
+
 // Helper functions
 
+// csvExportDir returns the directory holding the CSV files for path; both
+// "export.csv" and "export" name the directory "export"
+func csvExportDir(path string) string {
+	return strings.TrimSuffix(path, filepath.Ext(path))
+}
+
+// notNullColumns returns the columns of table declared NOT NULL
+func notNullColumns(ctx context.Context, tx *sql.Tx, table string) (map[string]bool, error) {
+	rows, err := tx.QueryContext(ctx, `SELECT name FROM pragma_table_info(?) WHERE "notnull" = 1`, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		columns[name] = true
+	}
+	return columns, rows.Err()
+}
+
+// readCSVRows reads a CSV export file into rows keyed by the header columns.
+// CSV cannot tell NULL from an empty string, so empty fields become NULL
+// unless the column is declared NOT NULL.
+func readCSVRows(filename string, notNull map[string]bool) ([]TableRow, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	records, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("missing CSV header")
+	}
+
+	header := records[0]
+	rows := make([]TableRow, 0, len(records)-1)
+	for _, record := range records[1:] {
+		row := make(TableRow, len(header))
+		for i, col := range header {
+			if record[i] == "" && !notNull[col] {
+				row[col] = nil
+			} else {
+				row[col] = record[i]
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// Tables lists the tables Export writes when no tables are requested
+func (e *ExportManager) Tables(ctx context.Context) ([]string, error) {
+	return e.getTablesToExport(ctx, nil)
+}
+
 func (e *ExportManager) getTablesToExport(ctx context.Context, requestedTables []string) ([]string, error) {
 	if len(requestedTables) > 0 {
 		return requestedTables, nil
 	}
 
 	// Get all tables
-	query := `SELECT name FROM sqlite_master WHERE type='table' AND name NOT LIKE 'sqlite_%' ORDER BY name`
+	// The search index and its shadow tables are derived data, rebuilt on
+	// open, and rewind staging tables are scratch copies
+	query := `SELECT name FROM sqlite_master WHERE type='table' AND name NOT LIKE 'sqlite_%' AND name NOT LIKE 'search_index%' AND name NOT LIKE '` + rewindStagingPrefix + `%' ORDER BY name`
 	rows, err := e.db.db.QueryContext(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query tables: %w", err)
@@ -478,6 +810,8 @@ func (e *ExportManager) exportTableData(ctx context.Context, w io.Writer, tableN
 				fmt.Fprintf(w, "NULL")
 			} else if str, ok := val.(string); ok {
 				fmt.Fprintf(w, "'%s'", strings.ReplaceAll(str, "'", "''"))
+			} else if t, ok := val.(time.Time); ok {
+				fmt.Fprintf(w, "'%s'", FormatTimestamp(t))
 			} else {
 				fmt.Fprintf(w, "%v", val)
 			}
@@ -489,19 +823,26 @@ func (e *ExportManager) exportTableData(ctx context.Context, w io.Writer, tableN
 	return rowCount, nil
 }
 
-func (e *ExportManager) getTableRows(ctx context.Context, tableName string) ([]TableRow, error) {
-	rows, err := e.db.db.QueryContext(ctx, fmt.Sprintf("SELECT * FROM %s", tableName))
+// getTableRows reads every row of tableName with values encoded for a typed
+// JSON export, along with the type of each column
+func (e *ExportManager) getTableRows(ctx context.Context, tableName string) ([]TableRow, map[string]ColumnType, error) {
+	return e.queryRows(ctx, fmt.Sprintf("SELECT * FROM %s", tableName))
+}
+
+// queryRows runs a query and returns its rows with inferred column types
+func (e *ExportManager) queryRows(ctx context.Context, query string, args ...interface{}) ([]TableRow, map[string]ColumnType, error) {
+	rows, err := e.db.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query table: %w", err)
+		return nil, nil, fmt.Errorf("failed to query table: %w", err)
 	}
 	defer rows.Close()
 
 	columns, err := rows.Columns()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get columns: %w", err)
+		return nil, nil, fmt.Errorf("failed to get columns: %w", err)
 	}
 
-	var result []TableRow
+	var scanned [][]interface{}
 	for rows.Next() {
 		values := make([]interface{}, len(columns))
 		valuePtrs := make([]interface{}, len(columns))
@@ -510,17 +851,25 @@ func (e *ExportManager) getTableRows(ctx context.Context, tableName string) ([]T
 		}
 
 		if err := rows.Scan(valuePtrs...); err != nil {
-			return nil, fmt.Errorf("failed to scan row: %w", err)
+			return nil, nil, fmt.Errorf("failed to scan row: %w", err)
 		}
+		scanned = append(scanned, values)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("failed to read table: %w", err)
+	}
 
+	types := inferColumnTypes(columns, scanned)
+	var result []TableRow
+	for _, values := range scanned {
 		row := make(TableRow)
 		for i, col := range columns {
-			row[col] = values[i]
+			row[col] = encodeTypedValue(types[col], values[i])
 		}
 		result = append(result, row)
 	}
 
-	return result, nil
+	return result, types, nil
 }
 
 func (e *ExportManager) getTemplatesForExport(ctx context.Context) ([]ExportedTemplate, error) {
@@ -535,10 +884,76 @@ func (e *ExportManager) getBlueprintsForExport(ctx context.Context) ([]ExportedB
 	return []ExportedBlueprint{}, nil
 }
 
-func (e *ExportManager) exportTableCSV(ctx context.Context, filename, tableName string) (int, error) {
-	// CSV export implementation
-	// This is a simplified version - you'd want proper CSV escaping
-	return 0, fmt.Errorf("CSV export not fully implemented")
+// exportTableCSV writes tableName to dir/<table>.csv and describes the
+// written file for the manifest
+func (e *ExportManager) exportTableCSV(ctx context.Context, dir, tableName string) (ManifestFile, error) {
+	result := ManifestFile{Path: tableName + ".csv", Table: tableName}
+
+	rows, err := e.db.db.QueryContext(ctx, fmt.Sprintf("SELECT * FROM %s", tableName))
+	if err != nil {
+		return result, fmt.Errorf("failed to query table data: %w", err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return result, fmt.Errorf("failed to get columns: %w", err)
+	}
+	result.Columns = columns
+
+	file, err := fsutil.Create(filepath.Join(dir, result.Path), FileMode())
+	if err != nil {
+		return result, fmt.Errorf("failed to create CSV file: %w", err)
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	counter := &countingWriter{}
+	writer := csv.NewWriter(io.MultiWriter(file, hash, counter))
+	if err := writer.Write(columns); err != nil {
+		return result, fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	record := make([]string, len(columns))
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		valuePtrs := make([]interface{}, len(columns))
+		for i := range values {
+			valuePtrs[i] = &values[i]
+		}
+
+		if err := rows.Scan(valuePtrs...); err != nil {
+			return result, fmt.Errorf("failed to scan row: %w", err)
+		}
+
+		for i, val := range values {
+			switch v := val.(type) {
+			case nil:
+				record[i] = ""
+			case []byte:
+				record[i] = string(v)
+			case time.Time:
+				record[i] = FormatTimestamp(v)
+			default:
+				record[i] = fmt.Sprint(v)
+			}
+		}
+		if err := writer.Write(record); err != nil {
+			return result, fmt.Errorf("failed to write CSV row: %w", err)
+		}
+		result.Rows++
+	}
+	if err := rows.Err(); err != nil {
+		return result, fmt.Errorf("failed to read table data: %w", err)
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return result, fmt.Errorf("failed to write CSV file: %w", err)
+	}
+	result.SHA256 = hex.EncodeToString(hash.Sum(nil))
+	result.Size = counter.n
+	return result, file.Commit()
 }
 
 func (e *ExportManager) validateImportData(data *ExportedData) error {
@@ -548,10 +963,43 @@ func (e *ExportManager) validateImportData(data *ExportedData) error {
 	return nil
 }
 
-func (e *ExportManager) importTableRows(ctx context.Context, tableName string, rows []TableRow, replaceExisting bool) (int, error) {
-	// Implementation for importing table rows
-	// This would handle INSERT or INSERT OR REPLACE depending on replaceExisting
-	return len(rows), nil
+// insertTableRows inserts rows into tableName inside tx. Existing rows with
+// the same key are replaced when replaceExisting is set; otherwise a conflict
+// fails the import.
+func insertTableRows(ctx context.Context, tx *sql.Tx, tableName string, rows []TableRow, replaceExisting bool) (int, error) {
+	verb := "INSERT"
+	if replaceExisting {
+		verb = "INSERT OR REPLACE"
+	}
+
+	imported := 0
+	for _, row := range rows {
+		columns := make([]string, 0, len(row))
+		for col := range row {
+			columns = append(columns, col)
+		}
+		sort.Strings(columns)
+
+		quoted := make([]string, len(columns))
+		args := make([]interface{}, len(columns))
+		for i, col := range columns {
+			quoted[i] = quoteIdentifier(col)
+			args[i] = row[col]
+		}
+
+		query := fmt.Sprintf("%s INTO %s (%s) VALUES (%s)", verb, quoteIdentifier(tableName),
+			strings.Join(quoted, ", "), strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", "))
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return imported, fmt.Errorf("failed to insert row %d: %w", imported+1, err)
+		}
+		imported++
+	}
+	return imported, nil
+}
+
+// quoteIdentifier quotes a table or column name read from an import file
+func quoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
 }
 
 func min(a, b int) int {
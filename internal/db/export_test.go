@@ -0,0 +1,324 @@
+package db
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupExportData(t testing.TB, ctx context.Context, manager *Manager) {
+	for i := 0; i < 20; i++ {
+		_, err := manager.GetDB().ExecContext(ctx,
+			`INSERT INTO templates (name, description, content) VALUES (?, ?, ?)`,
+			fmt.Sprintf("template-%02d", i), "Template, with \"quotes\"", "package main")
+		require.NoError(t, err)
+	}
+	_, err := manager.GetDB().ExecContext(ctx,
+		`INSERT INTO blueprints (name, stack, config_json) VALUES ('web-stack', 'web', '{}')`)
+	require.NoError(t, err)
+}
+
+func TestExportManager_ParallelExportIsDeterministic(t *testing.T) {
+	manager, dbPath, cleanup := setupTestManager(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	require.NoError(t, manager.Open(ctx, dbPath))
+	defer manager.Close()
+	setupExportData(t, ctx, manager)
+
+	exportManager := NewExportManager(manager)
+	exportManager.now = func() time.Time { return time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC) }
+
+	for _, format := range []ExportFormat{FormatSQL, FormatJSON} {
+		t.Run(string(format), func(t *testing.T) {
+			dir := t.TempDir()
+			sequential := filepath.Join(dir, "sequential."+string(format))
+			parallel := filepath.Join(dir, "parallel."+string(format))
+
+			require.NoError(t, exportManager.Export(ctx, ExportOptions{
+				OutputPath: sequential, Format: format, IncludeSchema: true, IncludeData: true,
+			}))
+			require.NoError(t, exportManager.Export(ctx, ExportOptions{
+				OutputPath: parallel, Format: format, IncludeSchema: true, IncludeData: true, Parallel: 4,
+			}))
+
+			expected, err := os.ReadFile(sequential)
+			require.NoError(t, err)
+			actual, err := os.ReadFile(parallel)
+			require.NoError(t, err)
+			assert.Equal(t, string(expected), string(actual))
+		})
+	}
+}
+
+func TestExportManager_ExportCSV(t *testing.T) {
+	manager, dbPath, cleanup := setupTestManager(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	require.NoError(t, manager.Open(ctx, dbPath))
+	defer manager.Close()
+	setupExportData(t, ctx, manager)
+
+	outputPath := filepath.Join(t.TempDir(), "export.csv")
+	require.NoError(t, NewExportManager(manager).Export(ctx, ExportOptions{
+		OutputPath: outputPath, Format: FormatCSV, Tables: []string{"templates", "blueprints"}, Parallel: 2,
+	}))
+
+	file, err := os.Open(filepath.Join(filepath.Dir(outputPath), "export", "templates.csv"))
+	require.NoError(t, err)
+	defer file.Close()
+
+	records, err := csv.NewReader(file).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 21)
+	assert.Contains(t, records[0], "description")
+	assert.Contains(t, records[1], "Template, with \"quotes\"")
+	assert.FileExists(t, filepath.Join(filepath.Dir(outputPath), "export", "blueprints.csv"))
+}
+
+func TestExportManager_Tables(t *testing.T) {
+	manager, dbPath, cleanup := setupTestManager(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	require.NoError(t, manager.Open(ctx, dbPath))
+	defer manager.Close()
+
+	tables, err := NewExportManager(manager).Tables(ctx)
+	require.NoError(t, err)
+	assert.Contains(t, tables, "templates")
+	assert.Contains(t, tables, "blueprints")
+	for _, table := range tables {
+		assert.NotContains(t, table, "search_index")
+	}
+}
+
+func TestExportManager_ParallelExportFailure(t *testing.T) {
+	manager, dbPath, cleanup := setupTestManager(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	require.NoError(t, manager.Open(ctx, dbPath))
+	defer manager.Close()
+
+	outputPath := filepath.Join(t.TempDir(), "export.json")
+	err := NewExportManager(manager).Export(ctx, ExportOptions{
+		OutputPath:  outputPath,
+		Format:      FormatJSON,
+		Tables:      []string{"templates", "missing_table", "blueprints"},
+		IncludeData: true,
+		Parallel:    3,
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "missing_table")
+	assert.NoFileExists(t, outputPath, "nothing is written when a table fails")
+}
+
+func TestExportManager_CancelledExport(t *testing.T) {
+	manager, dbPath, cleanup := setupTestManager(t)
+	defer cleanup()
+
+	require.NoError(t, manager.Open(context.Background(), dbPath))
+	defer manager.Close()
+	setupExportData(t, context.Background(), manager)
+
+	for _, format := range []ExportFormat{FormatSQL, FormatJSON} {
+		t.Run(string(format), func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			outputPath := filepath.Join(t.TempDir(), "export."+string(format))
+			err := NewExportManager(manager).Export(ctx, ExportOptions{
+				OutputPath:  outputPath,
+				Format:      format,
+				Tables:      []string{"templates", "blueprints"},
+				IncludeData: true,
+				Parallel:    2,
+			})
+			assert.ErrorIs(t, err, context.Canceled)
+			assert.NoFileExists(t, outputPath, "a cancelled export writes nothing")
+		})
+	}
+}
+
+func TestExportTablesParallel_CancelledMidExport(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	tables := []string{"a", "b", "c", "d"}
+	_, err := exportTablesParallel(ctx, tables, ExportOptions{Parallel: 1}, func(ctx context.Context, i int, table string) (int, error) {
+		// The caller cancels once the first table is done; feeding stops
+		// without any table failing
+		if i == 0 {
+			cancel()
+		}
+		return 1, nil
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func exportCSVForImport(t *testing.T, ctx context.Context) string {
+	manager, dbPath, cleanup := setupTestManager(t)
+	defer cleanup()
+	require.NoError(t, manager.Open(ctx, dbPath))
+	setupExportData(t, ctx, manager)
+
+	outputPath := filepath.Join(t.TempDir(), "export.csv")
+	require.NoError(t, NewExportManager(manager).Export(ctx, ExportOptions{
+		OutputPath: outputPath, Format: FormatCSV, Tables: []string{"templates", "blueprints"}, Parallel: 2,
+	}))
+	return filepath.Join(filepath.Dir(outputPath), "export")
+}
+
+func TestExportManager_CSVManifest(t *testing.T) {
+	ctx := context.Background()
+	dir := exportCSVForImport(t, ctx)
+
+	manifest, err := VerifyManifest(dir)
+	require.NoError(t, err)
+	assert.Equal(t, ManifestVersion, manifest.Version)
+	assert.Equal(t, "csv", manifest.Format)
+	require.Len(t, manifest.Files, 2)
+
+	templates := manifest.Files[0]
+	assert.Equal(t, "templates.csv", templates.Path)
+	assert.Equal(t, "templates", templates.Table)
+	assert.Equal(t, 20, templates.Rows)
+	assert.Contains(t, templates.Columns, "description")
+	assert.Len(t, templates.SHA256, 64)
+
+	info, err := os.Stat(filepath.Join(dir, templates.Path))
+	require.NoError(t, err)
+	assert.Equal(t, info.Size(), templates.Size)
+	assert.Equal(t, 1, manifest.Files[1].Rows)
+}
+
+func TestExportManager_ImportCSV(t *testing.T) {
+	ctx := context.Background()
+	dir := exportCSVForImport(t, ctx)
+
+	manager, dbPath, cleanup := setupTestManager(t)
+	defer cleanup()
+	require.NoError(t, manager.Open(ctx, dbPath))
+
+	require.NoError(t, NewExportManager(manager).Import(ctx, ImportOptions{
+		InputPath: dir + ".csv", Format: FormatCSV,
+	}))
+
+	var count int
+	require.NoError(t, manager.GetDB().QueryRowContext(ctx, `SELECT COUNT(*) FROM templates`).Scan(&count))
+	assert.Equal(t, 20, count)
+
+	var description string
+	var deletedAt *string
+	require.NoError(t, manager.GetDB().QueryRowContext(ctx,
+		`SELECT description, deleted_at FROM templates WHERE name = 'template-03'`).Scan(&description, &deletedAt))
+	assert.Equal(t, "Template, with \"quotes\"", description)
+	assert.Nil(t, deletedAt, "empty nullable fields import as NULL")
+}
+
+func TestExportManager_ImportCSVRejectsCorruptedExport(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		corrupt func(t *testing.T, dir string)
+		message string
+	}{
+		{
+			name: "truncated file",
+			corrupt: func(t *testing.T, dir string) {
+				path := filepath.Join(dir, "templates.csv")
+				info, err := os.Stat(path)
+				require.NoError(t, err)
+				require.NoError(t, os.Truncate(path, info.Size()/2))
+			},
+			message: "templates.csv",
+		},
+		{
+			name: "modified file",
+			corrupt: func(t *testing.T, dir string) {
+				path := filepath.Join(dir, "blueprints.csv")
+				data, err := os.ReadFile(path)
+				require.NoError(t, err)
+				data[len(data)-2] ^= 0x01
+				require.NoError(t, os.WriteFile(path, data, 0644))
+			},
+			message: "checksum",
+		},
+		{
+			name: "missing file",
+			corrupt: func(t *testing.T, dir string) {
+				require.NoError(t, os.Remove(filepath.Join(dir, "blueprints.csv")))
+			},
+			message: "file is missing",
+		},
+		{
+			name: "missing manifest",
+			corrupt: func(t *testing.T, dir string) {
+				require.NoError(t, os.Remove(filepath.Join(dir, ManifestFileName)))
+			},
+			message: ManifestFileName,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := exportCSVForImport(t, ctx)
+			tt.corrupt(t, dir)
+
+			manager, dbPath, cleanup := setupTestManager(t)
+			defer cleanup()
+			require.NoError(t, manager.Open(ctx, dbPath))
+
+			err := NewExportManager(manager).Import(ctx, ImportOptions{InputPath: dir, Format: FormatCSV})
+			require.Error(t, err)
+			assert.ErrorIs(t, err, ErrManifestMismatch)
+			assert.Contains(t, err.Error(), tt.message)
+
+			var count int
+			require.NoError(t, manager.GetDB().QueryRowContext(ctx, `SELECT COUNT(*) FROM templates`).Scan(&count))
+			assert.Zero(t, count, "nothing is loaded from a corrupted export")
+		})
+	}
+}
+
+func BenchmarkExportManager_Export(b *testing.B) {
+	manager, dbPath, cleanup := setupTestManager(b)
+	defer cleanup()
+
+	ctx := context.Background()
+	require.NoError(b, manager.Open(ctx, dbPath))
+	setupExportData(b, ctx, manager)
+	exportManager := NewExportManager(manager)
+	dir := b.TempDir()
+
+	for _, format := range []ExportFormat{FormatSQL, FormatJSON, FormatCSV} {
+		for _, parallel := range []int{1, 4} {
+			b.Run(fmt.Sprintf("%s/parallel=%d", format, parallel), func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					err := exportManager.Export(ctx, ExportOptions{
+						OutputPath:    filepath.Join(dir, "bench."+string(format)),
+						Format:        format,
+						IncludeSchema: true,
+						IncludeData:   true,
+						Parallel:      parallel,
+					})
+					if err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
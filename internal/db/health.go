@@ -139,6 +139,10 @@ func (h *HealthManager) CheckHealth(ctx context.Context, verbose bool) (*HealthS
 	perfCheck := h.checkPerformance(ctx)
 	checks = append(checks, perfCheck)
 
+	// Check 9: File permissions
+	permCheck := h.checkPermissions()
+	checks = append(checks, permCheck)
+
 	status.Checks = checks
 
 	// Generate recommendations
@@ -533,6 +537,32 @@ func (h *HealthManager) getTableStats(ctx context.Context) ([]TableStats, error)
 	return stats, nil
 }
 
+func (h *HealthManager) checkPermissions() HealthCheck {
+	start := time.Now()
+	check := HealthCheck{
+		Name:      "File Permissions",
+		CheckedAt: start,
+	}
+
+	issues, err := CheckPermissions(DatabaseFiles(h.path))
+	switch {
+	case err != nil:
+		check.Status = "WARNING"
+		check.Message = fmt.Sprintf("Could not check permissions: %v", err)
+	case len(issues) > 0:
+		check.Status = "WARNING"
+		check.Message = fmt.Sprintf("%s has permissions %04o, more open than %04o", issues[0].Path, issues[0].Mode, fileMode)
+		check.Value = fmt.Sprintf("%04o", issues[0].Mode)
+	default:
+		check.Status = "OK"
+		check.Message = fmt.Sprintf("Database files are restricted to %04o", fileMode)
+		check.Value = fmt.Sprintf("%04o", fileMode)
+	}
+
+	check.Duration = time.Since(start).String()
+	return check
+}
+
 func (h *HealthManager) generateRecommendations(status *HealthStatus) []string {
 	var recommendations []string
 
@@ -545,6 +575,9 @@ func (h *HealthManager) generateRecommendations(status *HealthStatus) []string {
 		if check.Name == "Free Space" && check.Status == "WARNING" {
 			recommendations = append(recommendations, "Run VACUUM to reclaim free space and optimize database")
 		}
+		if check.Name == "File Permissions" && check.Status == "WARNING" {
+			recommendations = append(recommendations, "Run gogo db chmod-fix to restrict access to the database files")
+		}
 	}
 
 	if status.DatabaseSize > 100*1024*1024 { // > 100MB
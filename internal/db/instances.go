@@ -0,0 +1,184 @@
+package db
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"syscall"
+	"time"
+
+	"github.com/user/gogo/internal/fsutil"
+)
+
+// InstancesSuffix names the instance registry kept next to a database
+const InstancesSuffix = ".instances"
+
+// registryLockTimeout bounds the wait for another process updating the
+// registry; a lock older than registryLockStale was left by a killed process
+const (
+	registryLockTimeout = 2 * time.Second
+	registryLockStale   = 10 * time.Second
+)
+
+// Instance is a running gogo process using a database
+type Instance struct {
+	PID       int       `json:"pid"`
+	Role      string    `json:"role"`                // Command it runs, e.g. "gogo db restore"
+	Exclusive bool      `json:"exclusive,omitempty"` // Replaces or rewrites the database, so nothing else should write to it
+	StartedAt time.Time `json:"started_at"`
+}
+
+// InstanceRegistry records the gogo processes using a database in a JSON
+// file next to it, so a command can tell it is not alone before it writes.
+// Entries of processes that died without unregistering are dropped on the
+// next update.
+type InstanceRegistry struct {
+	path string
+}
+
+// NewInstanceRegistry creates the registry of the database at dbPath
+func NewInstanceRegistry(dbPath string) *InstanceRegistry {
+	return &InstanceRegistry{
+		path: dbPath + InstancesSuffix,
+	}
+}
+
+// Path returns the path of the registry file
+func (r *InstanceRegistry) Path() string {
+	return r.path
+}
+
+// Register adds instance to the registry and returns the other live
+// instances. The returned release removes it again.
+func (r *InstanceRegistry) Register(instance Instance) ([]Instance, func() error, error) {
+	if instance.StartedAt.IsZero() {
+		instance.StartedAt = time.Now().UTC()
+	}
+
+	var others []Instance
+	err := r.update(func(instances []Instance) []Instance {
+		others = slices.Clone(instances)
+		return append(instances, instance)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	release := func() error {
+		return r.update(func(instances []Instance) []Instance {
+			return slices.DeleteFunc(instances, func(other Instance) bool {
+				return other.PID == instance.PID && other.StartedAt.Equal(instance.StartedAt)
+			})
+		})
+	}
+	return others, release, nil
+}
+
+// List returns the live instances, oldest first
+func (r *InstanceRegistry) List() ([]Instance, error) {
+	instances, err := r.read()
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(instances, func(instance Instance) bool {
+		return !processAlive(instance.PID)
+	}), nil
+}
+
+// update rewrites the registry under its lock with the live instances
+// passed through change; an empty registry is removed
+func (r *InstanceRegistry) update(change func([]Instance) []Instance) error {
+	unlock, err := r.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	instances, err := r.List()
+	if err != nil {
+		return err
+	}
+	instances = change(instances)
+
+	if len(instances) == 0 {
+		if err := os.Remove(r.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to remove instance registry: %w", err)
+		}
+		return nil
+	}
+	data, err := json.MarshalIndent(instances, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode instance registry: %w", err)
+	}
+	if err := fsutil.WriteFile(r.path, append(data, '\n'), fileMode); err != nil {
+		return fmt.Errorf("failed to write instance registry: %w", err)
+	}
+	return nil
+}
+
+func (r *InstanceRegistry) read() ([]Instance, error) {
+	data, err := os.ReadFile(r.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read instance registry: %w", err)
+	}
+
+	var instances []Instance
+	if err := json.Unmarshal(data, &instances); err != nil {
+		// A damaged registry only costs the warnings it would have given
+		return nil, nil
+	}
+	return instances, nil
+}
+
+// lock takes the registry's lock file, breaking a stale one
+func (r *InstanceRegistry) lock() (func(), error) {
+	lockPath := r.path + ".lock"
+	if _, err := os.Stat(filepath.Dir(lockPath)); err != nil {
+		return nil, fmt.Errorf("failed to lock instance registry: %w", err)
+	}
+
+	deadline := time.Now().Add(registryLockTimeout)
+	for {
+		f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, fileMode)
+		if err == nil {
+			f.Close()
+			return func() { os.Remove(lockPath) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("failed to lock instance registry: %w", err)
+		}
+		if info, err := os.Stat(lockPath); err == nil && time.Since(info.ModTime()) > registryLockStale {
+			os.Remove(lockPath)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("instance registry %s is locked", r.path)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+// processAlive reports whether a process with pid is running
+func processAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	// FindProcess opens the process on Windows and fails for dead ones
+	if runtime.GOOS == "windows" {
+		process.Release()
+		return true
+	}
+	err = process.Signal(syscall.Signal(0))
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
@@ -0,0 +1,84 @@
+package db
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// deadPID returns the PID of a process that has exited
+func deadPID(t *testing.T) int {
+	cmd := exec.Command(os.Args[0], "-test.run=^$")
+	require.NoError(t, cmd.Run())
+	return cmd.Process.Pid
+}
+
+func TestInstanceRegistry_Register(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "gogo.db")
+	registry := NewInstanceRegistry(dbPath)
+	assert.Equal(t, dbPath+InstancesSuffix, registry.Path())
+
+	others, releaseFirst, err := registry.Register(Instance{PID: os.Getpid(), Role: "gogo db restore", Exclusive: true})
+	require.NoError(t, err)
+	assert.Empty(t, others)
+
+	others, releaseSecond, err := registry.Register(Instance{PID: os.Getpid(), Role: "gogo template list"})
+	require.NoError(t, err)
+	require.Len(t, others, 1)
+	assert.Equal(t, "gogo db restore", others[0].Role)
+	assert.True(t, others[0].Exclusive)
+	assert.False(t, others[0].StartedAt.IsZero())
+
+	info, err := os.Stat(registry.Path())
+	require.NoError(t, err)
+	assert.Equal(t, FileMode(), info.Mode().Perm())
+
+	require.NoError(t, releaseFirst())
+	instances, err := registry.List()
+	require.NoError(t, err)
+	require.Len(t, instances, 1)
+	assert.Equal(t, "gogo template list", instances[0].Role)
+
+	require.NoError(t, releaseSecond())
+	assert.NoFileExists(t, registry.Path(), "an empty registry is removed")
+	assert.NoFileExists(t, registry.Path()+".lock")
+}
+
+func TestInstanceRegistry_DropsDeadProcesses(t *testing.T) {
+	registry := NewInstanceRegistry(filepath.Join(t.TempDir(), "gogo.db"))
+	_, _, err := registry.Register(Instance{PID: deadPID(t), Role: "gogo db migrate", Exclusive: true})
+	require.NoError(t, err)
+
+	others, release, err := registry.Register(Instance{PID: os.Getpid(), Role: "gogo db migrate", Exclusive: true})
+	require.NoError(t, err)
+	assert.Empty(t, others, "a killed process does not block the database")
+	require.NoError(t, release())
+	assert.NoFileExists(t, registry.Path())
+}
+
+func TestInstanceRegistry_StaleLock(t *testing.T) {
+	registry := NewInstanceRegistry(filepath.Join(t.TempDir(), "gogo.db"))
+	lockPath := registry.Path() + ".lock"
+	require.NoError(t, os.WriteFile(lockPath, nil, 0600))
+	old := time.Now().Add(-time.Minute)
+	require.NoError(t, os.Chtimes(lockPath, old, old))
+
+	_, release, err := registry.Register(Instance{PID: os.Getpid(), Role: "gogo init"})
+	require.NoError(t, err, "a lock left by a killed process is broken")
+	require.NoError(t, release())
+}
+
+func TestInstanceRegistry_MissingDirectory(t *testing.T) {
+	registry := NewInstanceRegistry(filepath.Join(t.TempDir(), "missing", "gogo.db"))
+	_, _, err := registry.Register(Instance{PID: os.Getpid(), Role: "gogo init"})
+	assert.Error(t, err)
+
+	instances, err := registry.List()
+	require.NoError(t, err)
+	assert.Empty(t, instances)
+}
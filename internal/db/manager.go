@@ -10,8 +10,9 @@ import (
 
 // Manager handles database operations
 type Manager struct {
-	db   *sql.DB
-	path string
+	db       *sql.DB
+	path     string
+	fullText bool // FTS5 search index is available
 }
 
 // NewManager creates a new database manager
@@ -19,8 +20,13 @@ func NewManager() *Manager {
 	return &Manager{}
 }
 
-// Open opens the database connection
+// Open opens the database connection, creating the database with the
+// configured file mode when it does not exist
 func (m *Manager) Open(ctx context.Context, path string) error {
+	if err := createDatabaseFile(path); err != nil {
+		return err
+	}
+
 	db, err := sql.Open("sqlite3", path+"?_journal_mode=WAL&_synchronous=NORMAL&_cache_size=1000")
 	if err != nil {
 		return fmt.Errorf("failed to open database: %w", err)
@@ -34,11 +40,13 @@ func (m *Manager) Open(ctx context.Context, path string) error {
 		return fmt.Errorf("failed to ping database: %w", err)
 	}
 
-	// Run migrations
-	if err := m.migrate(ctx); err != nil {
-		return fmt.Errorf("failed to run migrations: %w", err)
+	// Bring the schema up to date, upgrading databases of older versions
+	if err := m.upgrade(ctx); err != nil {
+		_ = m.db.Close()
+		return err
 	}
 
+	warnExposedDatabase(path)
 	return nil
 }
 
@@ -77,23 +85,74 @@ func (m *Manager) WithTx(ctx context.Context, fn func(ctx context.Context, tx *s
 	return tx.Commit()
 }
 
+// schemaStatements create the tables and indexes of the current schema
+var schemaStatements = []string{
+	createTemplatesTable,
+	createBlueprintsTable,
+	createConfigsTable,
+	createHooksTable,
+	createPluginsTable,
+	createAuditsTable,
+	createAuditLogTable,
+	createRegistryCacheTable,
+	createTagsTable,
+	createEntryTagsTable,
+	createAnnotationsTable,
+	createProjectStateTable,
+	createIndexes,
+}
+
 // migrate runs database migrations
 func (m *Manager) migrate(ctx context.Context) error {
-	migrations := []string{
-		createTemplatesTable,
-		createBlueprintsTable,
-		createConfigsTable,
-		createHooksTable,
-		createPluginsTable,
-		createAuditsTable,
-		createIndexes,
-	}
-
-	for i, migration := range migrations {
+	for i, migration := range schemaStatements {
 		if _, err := m.db.ExecContext(ctx, migration); err != nil {
 			return fmt.Errorf("migration %d failed: %w", i+1, err)
 		}
 	}
 
+	for _, col := range addedColumns {
+		if err := m.ensureColumn(ctx, col.table, col.column, col.definition); err != nil {
+			return err
+		}
+	}
+
+	if err := m.ensureSearchIndex(ctx); err != nil {
+		return err
+	}
+	if err := m.ensureAuditLog(ctx); err != nil {
+		return err
+	}
+
+	// Older versions stored a mix of CURRENT_TIMESTAMP and local times
+	for _, tc := range timestampColumns {
+		var exists int
+		if err := m.db.QueryRowContext(ctx,
+			`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?`, tc.table).Scan(&exists); err != nil {
+			return fmt.Errorf("failed to check table %s: %w", tc.table, err)
+		}
+		if exists == 0 {
+			continue
+		}
+		if _, err := m.db.ExecContext(ctx, normalizeTimestampsSQL(tc.table, tc.column)); err != nil {
+			return fmt.Errorf("failed to normalize %s.%s: %w", tc.table, tc.column, err)
+		}
+	}
+
+	return nil
+}
+
+// ensureColumn adds column to table unless it already exists
+func (m *Manager) ensureColumn(ctx context.Context, table, column, definition string) error {
+	existing, err := tableColumns(ctx, m.db, table)
+	if err != nil {
+		return err
+	}
+	if hasColumn(existing, column) {
+		return nil
+	}
+
+	if _, err := m.db.ExecContext(ctx, fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition)); err != nil {
+		return fmt.Errorf("failed to add %s.%s: %w", table, column, err)
+	}
 	return nil
 }
@@ -0,0 +1,158 @@
+package db
+
+import (
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/user/gogo/internal/fsutil"
+)
+
+// ManifestFileName is written next to the data files of multi-file exports
+const ManifestFileName = "MANIFEST.json"
+
+// ManifestVersion is the current manifest layout version
+const ManifestVersion = "1.0"
+
+// ErrManifestMismatch reports that exported files do not match their manifest
+var ErrManifestMismatch = errors.New("export does not match manifest")
+
+// ExportManifest describes every file of a multi-file export so that partial
+// or corrupted transfers are detected before anything is imported
+type ExportManifest struct {
+	Version    string         `json:"version"`
+	Format     string         `json:"format"`
+	ExportedAt time.Time      `json:"exported_at"`
+	Files      []ManifestFile `json:"files"`
+}
+
+// ManifestFile describes one exported file
+type ManifestFile struct {
+	Path    string   `json:"path"`
+	Table   string   `json:"table"`
+	SHA256  string   `json:"sha256"`
+	Size    int64    `json:"size"`
+	Rows    int      `json:"rows"`
+	Columns []string `json:"columns"`
+}
+
+// writeManifest stores the manifest in dir
+func writeManifest(dir string, manifest *ExportManifest) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	if err := fsutil.WriteFile(filepath.Join(dir, ManifestFileName), append(data, '\n'), FileMode()); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+	return nil
+}
+
+// ReadManifest loads the manifest of the export in dir
+func ReadManifest(dir string) (*ExportManifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, ManifestFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: %s is missing from %s", ErrManifestMismatch, ManifestFileName, dir)
+		}
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	var manifest ExportManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to decode manifest: %w", err)
+	}
+	if manifest.Version == "" {
+		return nil, fmt.Errorf("manifest missing version information")
+	}
+	return &manifest, nil
+}
+
+// VerifyManifest checks every file listed in the manifest of the CSV export
+// in dir against its recorded checksum, size, row count and columns. All
+// problems are reported together.
+func VerifyManifest(dir string) (*ExportManifest, error) {
+	manifest, err := ReadManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var problems []error
+	for _, file := range manifest.Files {
+		if err := verifyManifestFile(dir, file); err != nil {
+			problems = append(problems, fmt.Errorf("%s: %w", file.Path, err))
+		}
+	}
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("%w\n%w", ErrManifestMismatch, errors.Join(problems...))
+	}
+	return manifest, nil
+}
+
+func verifyManifestFile(dir string, file ManifestFile) error {
+	// Manifest paths are plain file names; anything else could escape dir
+	if file.Path == "" || filepath.Base(file.Path) != file.Path {
+		return fmt.Errorf("invalid file path")
+	}
+
+	f, err := os.Open(filepath.Join(dir, file.Path))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("file is missing")
+		}
+		return err
+	}
+	defer f.Close()
+
+	hash := sha256.New()
+	counter := &countingWriter{}
+	reader := csv.NewReader(io.TeeReader(f, io.MultiWriter(hash, counter)))
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return fmt.Errorf("failed to read header: %w", err)
+	}
+	rows := 0
+	for {
+		if _, err := reader.Read(); err == io.EOF {
+			break
+		} else if err != nil {
+			return fmt.Errorf("failed to read row %d: %w", rows+1, err)
+		}
+		rows++
+	}
+
+	var problems []error
+	if sum := hex.EncodeToString(hash.Sum(nil)); sum != file.SHA256 {
+		problems = append(problems, fmt.Errorf("checksum %s does not match manifest %s", sum, file.SHA256))
+	}
+	if counter.n != file.Size {
+		problems = append(problems, fmt.Errorf("size %d does not match manifest %d", counter.n, file.Size))
+	}
+	if rows != file.Rows {
+		problems = append(problems, fmt.Errorf("%d rows do not match manifest %d", rows, file.Rows))
+	}
+	if !slices.Equal(header, file.Columns) {
+		problems = append(problems, fmt.Errorf("columns %v do not match manifest %v", header, file.Columns))
+	}
+	return errors.Join(problems...)
+}
+
+// countingWriter counts the bytes written through it
+type countingWriter struct {
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	return len(p), nil
+}
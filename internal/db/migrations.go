@@ -3,12 +3,15 @@ package db
 import (
 	"context"
 	"database/sql"
+	"embed"
 	"fmt"
+	"io/fs"
 	"sort"
 	"strings"
 	"time"
 
 	"github.com/fatih/color"
+	"github.com/user/gogo/internal/events"
 )
 
 // Migration represents a database migration
@@ -25,6 +28,7 @@ type Migration struct {
 type MigrationManager struct {
 	db         *sql.DB
 	migrations map[string]*Migration
+	events     *events.Bus
 }
 
 // NewMigrationManager creates a new migration manager
@@ -35,6 +39,11 @@ func NewMigrationManager(db *sql.DB) *MigrationManager {
 	}
 }
 
+// SetEvents sets the bus MigrationApplied is published on
+func (m *MigrationManager) SetEvents(bus *events.Bus) {
+	m.events = bus
+}
+
 // RegisterMigration registers a migration
 func (m *MigrationManager) RegisterMigration(id, description, upSQL, downSQL string) {
 	m.migrations[id] = &Migration{
@@ -51,7 +60,7 @@ func (m *MigrationManager) InitMigrationTable(ctx context.Context) error {
 		CREATE TABLE IF NOT EXISTS schema_migrations (
 			id VARCHAR(255) PRIMARY KEY,
 			description TEXT NOT NULL,
-			applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			applied_at TIMESTAMP DEFAULT ` + timestampDefault + `,
 			checksum VARCHAR(64) NOT NULL
 		)`
 
@@ -138,8 +147,8 @@ func (m *MigrationManager) ApplyMigration(ctx context.Context, migration *Migrat
 
 	// Record migration as applied
 	checksum := generateChecksum(migration.UpSQL)
-	insertSQL := `INSERT INTO schema_migrations (id, description, checksum) VALUES (?, ?, ?)`
-	if _, err := tx.ExecContext(ctx, insertSQL, migration.ID, migration.Description, checksum); err != nil {
+	insertSQL := `INSERT INTO schema_migrations (id, description, applied_at, checksum) VALUES (?, ?, ?, ?)`
+	if _, err := tx.ExecContext(ctx, insertSQL, migration.ID, migration.Description, FormatTimestamp(time.Now()), checksum); err != nil {
 		return fmt.Errorf("failed to record migration %s: %w", migration.ID, err)
 	}
 
@@ -149,6 +158,11 @@ func (m *MigrationManager) ApplyMigration(ctx context.Context, migration *Migrat
 	}
 
 	color.Green("✓ Applied migration %s: %s", migration.ID, migration.Description)
+	m.events.Publish(ctx, events.Event{
+		Kind:    events.MigrationApplied,
+		Subject: migration.ID,
+		Details: map[string]any{"description": migration.Description, "checksum": checksum},
+	})
 	return nil
 }
 
@@ -305,59 +319,67 @@ func generateChecksum(content string) string {
 	return fmt.Sprintf("%d-%c%c", len(content), content[0], content[len(content)-1])
 }
 
+// migrationFS holds the core schema migrations as NNN_name.up.sql and
+// NNN_name.down.sql pairs. The first line of each up file is a comment
+// describing the migration.
+//
+//go:embed migrations/*.sql
+var migrationFS embed.FS
+
+// MigrationFS returns the embedded core migrations
+func MigrationFS() fs.FS {
+	sub, err := fs.Sub(migrationFS, "migrations")
+	if err != nil {
+		panic(err) // the directory is part of the embed pattern
+	}
+	return sub
+}
+
+// CoreMigrations reads the embedded core migrations in ID order
+func CoreMigrations() ([]*Migration, error) {
+	fsys := MigrationFS()
+	upFiles, err := fs.Glob(fsys, "*.up.sql")
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(upFiles)
+
+	migrations := make([]*Migration, 0, len(upFiles))
+	for _, upFile := range upFiles {
+		id := strings.TrimSuffix(upFile, ".up.sql")
+		up, err := fs.ReadFile(fsys, upFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", id, err)
+		}
+		down, err := fs.ReadFile(fsys, id+".down.sql")
+		if err != nil {
+			return nil, fmt.Errorf("migration %s has no down file: %w", id, err)
+		}
+
+		firstLine, _, _ := strings.Cut(string(up), "\n")
+		description, ok := strings.CutPrefix(firstLine, "-- ")
+		if !ok || strings.TrimSpace(description) == "" {
+			return nil, fmt.Errorf("migration %s must start with a description comment", id)
+		}
+
+		migrations = append(migrations, &Migration{
+			ID:          id,
+			Description: strings.TrimSpace(description),
+			UpSQL:       string(up),
+			DownSQL:     string(down),
+		})
+	}
+	return migrations, nil
+}
+
 // RegisterCoreSchemas registers the core database schema migrations
-func (m *MigrationManager) RegisterCoreSchemas() {
-	// Migration 001: Initial core tables (if not already created by schema.go)
-	m.RegisterMigration(
-		"001_initial_schema",
-		"Create initial core tables for templates and blueprints",
-		`-- This migration ensures core tables exist
-		 -- (Schema may already be created by schema.go, but this ensures consistency)`,
-		`-- Rollback would drop tables, but we don't want to accidentally destroy data
-		 -- SELECT 'Rollback not implemented for initial schema' as warning;`,
-	)
-
-	// Migration 002: Add indexes for performance
-	m.RegisterMigration(
-		"002_add_indexes",
-		"Add database indexes for improved query performance",
-		`CREATE INDEX IF NOT EXISTS idx_templates_name ON templates(name);
-		 CREATE INDEX IF NOT EXISTS idx_blueprints_name ON blueprints(name);
-		 CREATE INDEX IF NOT EXISTS idx_blueprints_stack ON blueprints(stack);`,
-		`DROP INDEX IF EXISTS idx_templates_name;
-		 DROP INDEX IF EXISTS idx_blueprints_name;
-		 DROP INDEX IF EXISTS idx_blueprints_stack;`,
-	)
-
-	// Migration 003: Add audit trail
-	m.RegisterMigration(
-		"003_add_audit_trail",
-		"Add audit trail tables for tracking changes",
-		`CREATE TABLE IF NOT EXISTS audit_log (
-			id INTEGER PRIMARY KEY AUTOINCREMENT,
-			table_name VARCHAR(50) NOT NULL,
-			record_id VARCHAR(255) NOT NULL,
-			action VARCHAR(10) NOT NULL, -- INSERT, UPDATE, DELETE
-			old_values TEXT,
-			new_values TEXT,
-			changed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-			changed_by VARCHAR(255)
-		);
-		CREATE INDEX IF NOT EXISTS idx_audit_log_table ON audit_log(table_name);
-		CREATE INDEX IF NOT EXISTS idx_audit_log_changed_at ON audit_log(changed_at);`,
-		`DROP TABLE IF EXISTS audit_log;`,
-	)
-
-	// Migration 004: Add metadata columns
-	m.RegisterMigration(
-		"004_add_metadata_columns",
-		"Add created_at and updated_at columns to core tables",
-		`-- Add metadata columns if they don't exist
-		 ALTER TABLE templates ADD COLUMN created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP;
-		 ALTER TABLE templates ADD COLUMN updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP;
-		 ALTER TABLE blueprints ADD COLUMN created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP;
-		 ALTER TABLE blueprints ADD COLUMN updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP;`,
-		`-- Note: SQLite doesn't support DROP COLUMN, so we can't easily roll this back
-		 SELECT 'Cannot rollback metadata columns in SQLite' as warning;`,
-	)
+func (m *MigrationManager) RegisterCoreSchemas() error {
+	migrations, err := CoreMigrations()
+	if err != nil {
+		return fmt.Errorf("failed to load core migrations: %w", err)
+	}
+	for _, migration := range migrations {
+		m.RegisterMigration(migration.ID, migration.Description, migration.UpSQL, migration.DownSQL)
+	}
+	return nil
 }
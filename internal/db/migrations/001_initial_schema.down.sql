@@ -0,0 +1,2 @@
+-- Rollback would drop tables, but we don't want to accidentally destroy data
+-- SELECT 'Rollback not implemented for initial schema' as warning;
@@ -0,0 +1,3 @@
+-- Create initial core tables for templates and blueprints
+-- This migration ensures core tables exist
+-- (Schema may already be created by schema.go, but this ensures consistency)
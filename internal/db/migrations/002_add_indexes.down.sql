@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_templates_name;
+DROP INDEX IF EXISTS idx_blueprints_name;
+DROP INDEX IF EXISTS idx_blueprints_stack;
@@ -0,0 +1,4 @@
+-- Add database indexes for improved query performance
+CREATE INDEX IF NOT EXISTS idx_templates_name ON templates(name);
+CREATE INDEX IF NOT EXISTS idx_blueprints_name ON blueprints(name);
+CREATE INDEX IF NOT EXISTS idx_blueprints_stack ON blueprints(stack);
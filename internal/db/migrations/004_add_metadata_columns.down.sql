@@ -0,0 +1,2 @@
+-- Note: SQLite doesn't support DROP COLUMN, so we can't easily roll this back
+SELECT 'Cannot rollback metadata columns in SQLite' as warning;
@@ -0,0 +1,9 @@
+-- Add cache table for conditional remote registry fetches
+CREATE TABLE IF NOT EXISTS registry_cache (
+    id              INTEGER PRIMARY KEY,
+    url             TEXT NOT NULL UNIQUE,
+    etag            TEXT NOT NULL DEFAULT '',
+    last_modified   TEXT NOT NULL DEFAULT '',
+    body            BLOB NOT NULL,
+    fetched_at      TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%SZ', 'now'))
+);
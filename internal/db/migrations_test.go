@@ -224,7 +224,7 @@ func TestMigrationManager_RegisterCoreSchemas(t *testing.T) {
 	defer db.Close()
 
 	migrationManager := NewMigrationManager(db)
-	migrationManager.RegisterCoreSchemas()
+	require.NoError(t, migrationManager.RegisterCoreSchemas())
 
 	// Check that core migrations were registered
 	expectedMigrations := []string{
@@ -232,13 +232,16 @@ func TestMigrationManager_RegisterCoreSchemas(t *testing.T) {
 		"002_add_indexes",
 		"003_add_audit_trail",
 		"004_add_metadata_columns",
+		"005_add_registry_cache",
 	}
 
 	for _, expectedID := range expectedMigrations {
 		migration, exists := migrationManager.migrations[expectedID]
 		assert.True(t, exists, "Migration %s should be registered", expectedID)
 		assert.NotEmpty(t, migration.Description, "Migration %s should have a description", expectedID)
+		assert.NotEmpty(t, migration.DownSQL, "Migration %s should have a rollback", expectedID)
 	}
+	assert.Equal(t, "Add database indexes for improved query performance", migrationManager.migrations["002_add_indexes"].Description)
 }
 
 func setupTestDB(t *testing.T) *sql.DB {
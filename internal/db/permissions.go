@@ -0,0 +1,162 @@
+package db
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/fatih/color"
+)
+
+// DefaultFileMode is the mode the database, its backups and its exports are
+// created with. They hold author emails and internal template code, so only
+// the owner may read them.
+const DefaultFileMode os.FileMode = 0600
+
+// EnvFileMode overrides DefaultFileMode, e.g. 0640 to share with a group
+const EnvFileMode = "GOGO_DB_FILE_MODE"
+
+// fileMode is the mode in effect, set once from the command line
+var fileMode = DefaultFileMode
+
+// permissionWarnings receives the warning printed when an open database is
+// readable by more than its owner should allow
+var permissionWarnings io.Writer = os.Stderr
+
+// sidecarSuffixes name the files SQLite keeps next to a WAL-mode database
+var sidecarSuffixes = []string{"-wal", "-shm"}
+
+// FileMode returns the mode database files are created with
+func FileMode() os.FileMode {
+	return fileMode
+}
+
+// SetFileMode changes the mode database files are created with. The owner
+// must keep read and write access.
+func SetFileMode(mode os.FileMode) error {
+	if mode&^fs.ModePerm != 0 {
+		return fmt.Errorf("file mode %04o has bits other than permissions", mode)
+	}
+	if mode&0600 != 0600 {
+		return fmt.Errorf("file mode %04o must let the owner read and write", mode)
+	}
+	fileMode = mode
+	return nil
+}
+
+// ParseFileMode parses an octal file mode such as 0600 or 640
+func ParseFileMode(value string) (os.FileMode, error) {
+	mode, err := strconv.ParseUint(value, 8, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid file mode %q (want octal, e.g. 0600)", value)
+	}
+	return os.FileMode(mode), nil
+}
+
+// PermissionIssue is a file readable or writable by more users than the
+// configured mode allows
+type PermissionIssue struct {
+	Path string
+	Mode os.FileMode
+}
+
+// DatabaseFiles returns the database at path and the WAL and shared memory
+// files next to it that exist
+func DatabaseFiles(path string) []string {
+	files := []string{path}
+	for _, suffix := range sidecarSuffixes {
+		if _, err := os.Stat(path + suffix); err == nil {
+			files = append(files, path+suffix)
+		}
+	}
+	return files
+}
+
+// CheckPermissions reports the files among paths whose permissions exceed
+// the configured mode. Missing files are skipped.
+func CheckPermissions(paths []string) ([]PermissionIssue, error) {
+	var issues []PermissionIssue
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to check permissions of %s: %w", path, err)
+		}
+		if mode := info.Mode().Perm(); mode&^fileMode != 0 {
+			issues = append(issues, PermissionIssue{Path: path, Mode: mode})
+		}
+	}
+	return issues, nil
+}
+
+// FixPermissions restricts every file among paths, and every file below
+// those that are directories, to the configured mode. It returns the files
+// it changed.
+func FixPermissions(paths []string) ([]PermissionIssue, error) {
+	var fixed []PermissionIssue
+	for _, root := range paths {
+		err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil || entry.IsDir() {
+				return err
+			}
+			info, err := entry.Info()
+			if err != nil {
+				return err
+			}
+			mode := info.Mode().Perm()
+			if mode&^fileMode == 0 {
+				return nil
+			}
+			// Only take permissions away; a stricter file stays as it is
+			if err := os.Chmod(path, mode&fileMode); err != nil {
+				return fmt.Errorf("failed to change permissions of %s: %w", path, err)
+			}
+			fixed = append(fixed, PermissionIssue{Path: path, Mode: mode})
+			return nil
+		})
+		if err != nil && !os.IsNotExist(err) {
+			return fixed, err
+		}
+	}
+	return fixed, nil
+}
+
+// createDatabaseFile creates an empty database file with the configured
+// mode, so SQLite does not create it, and its WAL files, under the umask
+func createDatabaseFile(path string) error {
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		return nil
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, fileMode)
+	if os.IsExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create database: %w", err)
+	}
+	// The umask may have removed bits the configured mode grants
+	if err := file.Chmod(fileMode); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to create database: %w", err)
+	}
+	return file.Close()
+}
+
+// warnExposedDatabase warns when the database files are readable or
+// writable by more users than the configured mode allows
+func warnExposedDatabase(path string) {
+	issues, err := CheckPermissions(DatabaseFiles(path))
+	if err != nil || len(issues) == 0 {
+		return
+	}
+	for _, issue := range issues {
+		color.New(color.FgYellow).Fprintf(permissionWarnings,
+			"Warning: %s has permissions %04o, more open than %04o; run gogo db chmod-fix\n",
+			issue.Path, issue.Mode, fileMode)
+	}
+}
@@ -0,0 +1,129 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// captureWarnings collects permission warnings for the rest of the test
+func captureWarnings(t *testing.T) *bytes.Buffer {
+	var buf bytes.Buffer
+	previous := permissionWarnings
+	permissionWarnings = &buf
+	t.Cleanup(func() { permissionWarnings = previous })
+	return &buf
+}
+
+func modeOf(t *testing.T, path string) os.FileMode {
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	return info.Mode().Perm()
+}
+
+func TestOpen_CreatesPrivateDatabase(t *testing.T) {
+	warnings := captureWarnings(t)
+	dbPath := filepath.Join(t.TempDir(), "gogo.db")
+
+	manager := NewManager()
+	require.NoError(t, manager.Open(context.Background(), dbPath))
+	defer manager.Close()
+
+	for _, path := range DatabaseFiles(dbPath) {
+		assert.Equal(t, DefaultFileMode, modeOf(t, path), path)
+	}
+	assert.Empty(t, warnings.String())
+}
+
+func TestOpen_WarnsAboutExposedDatabase(t *testing.T) {
+	warnings := captureWarnings(t)
+	dbPath := filepath.Join(t.TempDir(), "gogo.db")
+
+	manager := NewManager()
+	require.NoError(t, manager.Open(context.Background(), dbPath))
+	manager.Close()
+	require.NoError(t, os.Chmod(dbPath, 0644))
+
+	manager = NewManager()
+	require.NoError(t, manager.Open(context.Background(), dbPath))
+	defer manager.Close()
+
+	assert.Contains(t, warnings.String(), dbPath+" has permissions 0644")
+	assert.Contains(t, warnings.String(), "gogo db chmod-fix")
+	assert.Equal(t, os.FileMode(0644), modeOf(t, dbPath), "opening never changes permissions")
+}
+
+func TestBackup_CreatesPrivateFile(t *testing.T) {
+	captureWarnings(t)
+	manager, dbPath, cleanup := setupTestManager(t)
+	defer cleanup()
+	require.NoError(t, manager.Open(context.Background(), dbPath))
+
+	output := filepath.Join(t.TempDir(), "backup.db.gz")
+	require.NoError(t, NewBackupManager(manager, dbPath).Backup(context.Background(), BackupOptions{
+		OutputPath: output,
+		Compress:   true,
+	}))
+	assert.Equal(t, DefaultFileMode, modeOf(t, output))
+}
+
+func TestCheckAndFixPermissions(t *testing.T) {
+	dir := t.TempDir()
+	open := filepath.Join(dir, "gogo.db")
+	private := filepath.Join(dir, "private.db")
+	exports := filepath.Join(dir, "exports")
+	nested := filepath.Join(exports, "templates.json")
+	require.NoError(t, os.WriteFile(open, []byte("db"), 0644))
+	require.NoError(t, os.WriteFile(private, []byte("db"), 0400))
+	require.NoError(t, os.Mkdir(exports, 0755))
+	require.NoError(t, os.WriteFile(nested, []byte("{}"), 0666))
+	require.NoError(t, os.Chmod(nested, 0666))
+
+	issues, err := CheckPermissions([]string{open, private, filepath.Join(dir, "missing.db")})
+	require.NoError(t, err)
+	assert.Equal(t, []PermissionIssue{{Path: open, Mode: 0644}}, issues)
+
+	fixed, err := FixPermissions([]string{open, private, exports, filepath.Join(dir, "missing.db")})
+	require.NoError(t, err)
+	assert.Equal(t, []PermissionIssue{{Path: open, Mode: 0644}, {Path: nested, Mode: 0666}}, fixed)
+	assert.Equal(t, os.FileMode(0600), modeOf(t, open))
+	assert.Equal(t, os.FileMode(0400), modeOf(t, private), "permissions are only removed")
+	assert.Equal(t, os.FileMode(0600), modeOf(t, nested))
+	assert.Equal(t, os.FileMode(0755), modeOf(t, exports), "directories are left alone")
+
+	fixed, err = FixPermissions([]string{open, exports})
+	require.NoError(t, err)
+	assert.Empty(t, fixed)
+}
+
+func TestSetFileMode(t *testing.T) {
+	t.Cleanup(func() { fileMode = DefaultFileMode })
+
+	mode, err := ParseFileMode("0640")
+	require.NoError(t, err)
+	require.NoError(t, SetFileMode(mode))
+	assert.Equal(t, os.FileMode(0640), FileMode())
+
+	issues, err := CheckPermissions([]string{writeFile(t, 0640), writeFile(t, 0644)})
+	require.NoError(t, err)
+	assert.Len(t, issues, 1, "group read is allowed under 0640")
+
+	_, err = ParseFileMode("rw-------")
+	assert.Error(t, err)
+	assert.Error(t, SetFileMode(0400), "the owner must keep write access")
+	assert.Error(t, SetFileMode(os.ModeSetuid|0600))
+	assert.Equal(t, os.FileMode(0640), FileMode(), "invalid modes are not applied")
+}
+
+// writeFile creates a file with mode, bypassing the umask
+func writeFile(t *testing.T, mode os.FileMode) string {
+	path := filepath.Join(t.TempDir(), "file")
+	require.NoError(t, os.WriteFile(path, nil, mode))
+	require.NoError(t, os.Chmod(path, mode))
+	return path
+}
@@ -0,0 +1,71 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// DefaultProbeLatency is the default threshold for Probe
+const DefaultProbeLatency = 500 * time.Millisecond
+
+// ErrProbeTooSlow reports a probe that succeeded but exceeded its threshold
+var ErrProbeTooSlow = errors.New("database probe exceeded max latency")
+
+// ProbeResult describes a successful database probe
+type ProbeResult struct {
+	Path    string
+	Latency time.Duration
+}
+
+// Probe performs a minimal liveness check of the database at path: it opens
+// it read-only, pings it and runs PRAGMA quick_check. Unlike Open it never
+// creates the file or runs migrations, so it is cheap enough for container
+// health checks. A positive maxLatency bounds the whole probe.
+func Probe(ctx context.Context, path string, maxLatency time.Duration) (*ProbeResult, error) {
+	start := time.Now()
+	if maxLatency > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, maxLatency)
+		defer cancel()
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("database not available: %w", err)
+	}
+
+	conn, err := sql.Open("sqlite3", "file:"+path+"?mode=ro")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	defer conn.Close()
+
+	if err := conn.PingContext(ctx); err != nil {
+		return nil, probeError(ctx, start, maxLatency, fmt.Errorf("failed to ping database: %w", err))
+	}
+
+	var result string
+	if err := conn.QueryRowContext(ctx, "PRAGMA quick_check(1)").Scan(&result); err != nil {
+		return nil, probeError(ctx, start, maxLatency, fmt.Errorf("quick check failed: %w", err))
+	}
+	if result != "ok" {
+		return nil, fmt.Errorf("integrity issues found: %s", result)
+	}
+
+	latency := time.Since(start)
+	if maxLatency > 0 && latency > maxLatency {
+		return nil, fmt.Errorf("%w: %s > %s", ErrProbeTooSlow, latency, maxLatency)
+	}
+	return &ProbeResult{Path: path, Latency: latency}, nil
+}
+
+// probeError reports a probe cut short by its latency bound as too slow
+func probeError(ctx context.Context, start time.Time, maxLatency time.Duration, err error) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) && maxLatency > 0 {
+		return fmt.Errorf("%w: no answer after %s", ErrProbeTooSlow, time.Since(start).Round(time.Microsecond))
+	}
+	return err
+}
@@ -0,0 +1,44 @@
+package db
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProbe(t *testing.T) {
+	manager, dbPath, cleanup := setupTestManager(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	require.NoError(t, manager.Open(ctx, dbPath))
+	defer manager.Close()
+
+	result, err := Probe(ctx, dbPath, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, dbPath, result.Path)
+	assert.Positive(t, result.Latency)
+
+	_, err = Probe(ctx, dbPath, time.Nanosecond)
+	assert.ErrorIs(t, err, ErrProbeTooSlow)
+}
+
+func TestProbe_Failures(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	missing := filepath.Join(dir, "missing.db")
+	_, err := Probe(ctx, missing, DefaultProbeLatency)
+	assert.ErrorContains(t, err, "database not available")
+	assert.NoFileExists(t, missing, "probing never creates the database")
+
+	garbage := filepath.Join(dir, "garbage.db")
+	require.NoError(t, os.WriteFile(garbage, []byte("this is not a database file at all, just text"), 0644))
+	_, err = Probe(ctx, garbage, DefaultProbeLatency)
+	assert.Error(t, err)
+}
@@ -0,0 +1,343 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Rewind actions: what applying a RewindChange does to the current row
+const (
+	RewindRestore = "restore" // Recreate an entry deleted since
+	RewindRevert  = "revert"  // Put back the values an entry had
+	RewindRemove  = "remove"  // Delete an entry created since
+)
+
+// rewindTable is a table whose history audit_log records. Rows are
+// identified by name; binary columns are recorded hex encoded.
+type rewindTable struct {
+	kind, table string
+	columns     []string
+	binary      map[string]bool
+}
+
+var rewindTables = []rewindTable{
+	{KindTemplate, "templates",
+		[]string{"name", "kind", "description", "content", "metadata_json", "created_at", "updated_at", "deleted_at"},
+		map[string]bool{"content": true}},
+	{KindBlueprint, "blueprints",
+		[]string{"name", "stack", "description", "config_json", "metadata_json", "created_at", "updated_at", "deleted_at"},
+		nil},
+}
+
+// rewindStagingPrefix names the staging tables written by RewindManager.Stage
+const rewindStagingPrefix = "rewind_"
+
+// RewindChange is a change that takes one entry back to its earlier state
+type RewindChange struct {
+	Kind   string
+	Name   string
+	Action string
+
+	values map[string]any // The row at the target time; nil for RewindRemove
+}
+
+// RewindPlan lists the changes that take templates and blueprints back to
+// their state at a point in time
+type RewindPlan struct {
+	At      time.Time
+	Changes []RewindChange
+
+	// HistoryStart is when audit_log recorded its first change. Changes
+	// before then are unknown, so a plan for an earlier time is incomplete.
+	HistoryStart time.Time
+
+	states map[string]map[string]map[string]any // table -> name -> row
+}
+
+// Complete reports whether audit_log covers the whole period being undone
+func (p *RewindPlan) Complete() bool {
+	return p.HistoryStart.IsZero() || !p.At.Before(p.HistoryStart)
+}
+
+// RewindManager reconstructs templates and blueprints as they were at an
+// earlier time from the old and new values recorded in audit_log
+type RewindManager struct {
+	db    *Manager
+	actor string
+	now   func() time.Time
+}
+
+// NewRewindManager creates a new rewind manager
+func NewRewindManager(manager *Manager) *RewindManager {
+	return &RewindManager{
+		db:    manager,
+		actor: currentActor(),
+		now:   time.Now,
+	}
+}
+
+// Plan works out the state of every template and blueprint at the given
+// time by undoing the changes recorded since, newest first
+func (r *RewindManager) Plan(ctx context.Context, at time.Time) (*RewindPlan, error) {
+	if at.After(r.now()) {
+		return nil, fmt.Errorf("cannot rewind to %s, which is in the future", FormatTimestamp(at))
+	}
+	plan := &RewindPlan{At: at.UTC(), states: make(map[string]map[string]map[string]any)}
+
+	var start sql.NullString
+	if err := r.db.db.QueryRowContext(ctx, `SELECT MIN(changed_at) FROM audit_log`).Scan(&start); err != nil {
+		return nil, fmt.Errorf("failed to query audit log: %w", err)
+	}
+	if start.Valid {
+		var err error
+		if plan.HistoryStart, err = ParseTimestamp(start.String); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, source := range rewindTables {
+		current, err := r.currentRows(ctx, source)
+		if err != nil {
+			return nil, err
+		}
+		past := make(map[string]map[string]any, len(current))
+		for name, row := range current {
+			past[name] = row
+		}
+
+		// Changes within the target second happened before it ends
+		rows, err := r.db.db.QueryContext(ctx,
+			`SELECT record_id, old_values FROM audit_log WHERE table_name = ? AND changed_at > ? ORDER BY id DESC`,
+			source.table, FormatTimestamp(plan.At))
+		if err != nil {
+			return nil, fmt.Errorf("failed to query audit log: %w", err)
+		}
+		for rows.Next() {
+			var name string
+			var old sql.NullString
+			if err := rows.Scan(&name, &old); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("failed to scan audit log entry: %w", err)
+			}
+			if !old.Valid {
+				delete(past, name)
+				continue
+			}
+			var values map[string]any
+			if err := json.Unmarshal([]byte(old.String), &values); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("invalid audit log entry for %s '%s': %w", source.kind, name, err)
+			}
+			past[name] = values
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to query audit log: %w", err)
+		}
+		plan.states[source.table] = past
+
+		var names []string
+		for name := range current {
+			names = append(names, name)
+		}
+		for name := range past {
+			if _, ok := current[name]; !ok {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			then, existed := past[name]
+			now, exists := current[name]
+			change := RewindChange{Kind: source.kind, Name: name, values: then}
+			switch {
+			case !existed:
+				change.Action = RewindRemove
+			case !exists:
+				change.Action = RewindRestore
+			case !reflect.DeepEqual(then, now):
+				change.Action = RewindRevert
+			default:
+				continue
+			}
+			plan.Changes = append(plan.Changes, change)
+		}
+	}
+	return plan, nil
+}
+
+// Apply makes the changes of plan in one transaction, recording each in the
+// audits table. audit_log records them too, so a rewind can itself be undone.
+func (r *RewindManager) Apply(ctx context.Context, plan *RewindPlan) error {
+	return r.db.WithTx(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		now := FormatTimestamp(r.now())
+		for _, change := range plan.Changes {
+			source := rewindTables[0]
+			for _, candidate := range rewindTables {
+				if candidate.kind == change.Kind {
+					source = candidate
+				}
+			}
+
+			var err error
+			switch change.Action {
+			case RewindRemove:
+				_, err = tx.ExecContext(ctx, fmt.Sprintf(`DELETE FROM %s WHERE name = ?`, source.table), change.Name)
+			case RewindRestore:
+				args, argErr := rewindArgs(change.values, source.columns, source.binary)
+				if argErr != nil {
+					return fmt.Errorf("failed to restore %s '%s': %w", change.Kind, change.Name, argErr)
+				}
+				query := fmt.Sprintf(`INSERT INTO %s (%s) VALUES (?%s)`,
+					source.table, strings.Join(source.columns, ", "), strings.Repeat(", ?", len(source.columns)-1))
+				_, err = tx.ExecContext(ctx, query, args...)
+			case RewindRevert:
+				args, argErr := rewindArgs(change.values, source.columns, source.binary)
+				if argErr != nil {
+					return fmt.Errorf("failed to revert %s '%s': %w", change.Kind, change.Name, argErr)
+				}
+				query := fmt.Sprintf(`UPDATE %s SET %s = ? WHERE name = ?`, source.table, strings.Join(source.columns, " = ?, "))
+				_, err = tx.ExecContext(ctx, query, append(args, change.Name)...)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to %s %s '%s': %w", change.Action, change.Kind, change.Name, err)
+			}
+
+			details, err := json.Marshal(map[string]string{"kind": change.Kind, "name": change.Name, "change": change.Action, "at": FormatTimestamp(plan.At)})
+			if err != nil {
+				return err
+			}
+			query := `INSERT INTO audits (actor, action, entity, details_json, created_at) VALUES (?, ?, ?, ?, ?)`
+			if _, err := tx.ExecContext(ctx, query, r.actor, "rewind", change.Kind+":"+change.Name, string(details), now); err != nil {
+				return fmt.Errorf("failed to record audit entry: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// Stage writes the templates and blueprints of plan, as they were at its
+// time, to rewind_templates and rewind_blueprints for inspection, replacing
+// earlier staging tables. The live tables are left alone.
+func (r *RewindManager) Stage(ctx context.Context, plan *RewindPlan) ([]string, error) {
+	var tables []string
+	err := r.db.WithTx(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		for _, source := range rewindTables {
+			staging := rewindStagingPrefix + source.table
+			columns := strings.Join(source.columns, ", ")
+			statements := []string{
+				`DROP TABLE IF EXISTS ` + staging,
+				fmt.Sprintf(`CREATE TABLE %s AS SELECT %s FROM %s WHERE 0`, staging, columns, source.table),
+			}
+			for _, statement := range statements {
+				if _, err := tx.ExecContext(ctx, statement); err != nil {
+					return fmt.Errorf("failed to create %s: %w", staging, err)
+				}
+			}
+
+			insert := fmt.Sprintf(`INSERT INTO %s (%s) VALUES (?%s)`, staging, columns, strings.Repeat(", ?", len(source.columns)-1))
+			for name, values := range plan.states[source.table] {
+				args, err := rewindArgs(values, source.columns, source.binary)
+				if err != nil {
+					return fmt.Errorf("failed to stage %s '%s': %w", source.kind, name, err)
+				}
+				if _, err := tx.ExecContext(ctx, insert, args...); err != nil {
+					return fmt.Errorf("failed to stage %s '%s': %w", source.kind, name, err)
+				}
+			}
+			tables = append(tables, staging)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tables, nil
+}
+
+// currentRows reads every row of a table in the form audit_log records them
+func (r *RewindManager) currentRows(ctx context.Context, source rewindTable) (map[string]map[string]any, error) {
+	table := source.table
+	rows, err := r.db.db.QueryContext(ctx, fmt.Sprintf(`SELECT name, %s FROM %s`, auditLogRow("", source.columns, source.binary), table))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	result := make(map[string]map[string]any)
+	for rows.Next() {
+		var name, encoded string
+		if err := rows.Scan(&name, &encoded); err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", table, err)
+		}
+		var values map[string]any
+		if err := json.Unmarshal([]byte(encoded), &values); err != nil {
+			return nil, fmt.Errorf("failed to read %s '%s': %w", table, name, err)
+		}
+		result[name] = values
+	}
+	return result, rows.Err()
+}
+
+// rewindArgs orders the values of a recorded row as query arguments
+func rewindArgs(values map[string]any, columns []string, binary map[string]bool) ([]any, error) {
+	args := make([]any, len(columns))
+	for i, column := range columns {
+		value := values[column]
+		if text, ok := value.(string); ok && binary[column] {
+			decoded, err := hex.DecodeString(text)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s: %w", column, err)
+			}
+			value = decoded
+		}
+		args[i] = value
+	}
+	return args, nil
+}
+
+// auditLogRow is a SQL expression encoding the columns of a row as a JSON
+// object. prefix is "new." or "old." inside triggers.
+func auditLogRow(prefix string, columns []string, binary map[string]bool) string {
+	fields := make([]string, len(columns))
+	for i, column := range columns {
+		value := prefix + column
+		if binary[column] {
+			value = "hex(" + value + ")"
+		}
+		fields[i] = fmt.Sprintf("'%s', %s", column, value)
+	}
+	return "json_object(" + strings.Join(fields, ", ") + ")"
+}
+
+// ensureAuditLog creates the triggers that record templates and blueprints
+// changes in audit_log
+func (m *Manager) ensureAuditLog(ctx context.Context) error {
+	for _, source := range rewindTables {
+		insert := `INSERT INTO audit_log (table_name, record_id, action, old_values, new_values) VALUES ('%s', %s.name, '%s', %s, %s);`
+		newRow := auditLogRow("new.", source.columns, source.binary)
+		oldRow := auditLogRow("old.", source.columns, source.binary)
+		statements := []string{
+			fmt.Sprintf(`CREATE TRIGGER IF NOT EXISTS audit_log_%s_ai AFTER INSERT ON %s BEGIN %s END`,
+				source.table, source.table, fmt.Sprintf(insert, source.table, "new", "INSERT", "NULL", newRow)),
+			fmt.Sprintf(`CREATE TRIGGER IF NOT EXISTS audit_log_%s_au AFTER UPDATE ON %s BEGIN %s END`,
+				source.table, source.table, fmt.Sprintf(insert, source.table, "new", "UPDATE", oldRow, newRow)),
+			fmt.Sprintf(`CREATE TRIGGER IF NOT EXISTS audit_log_%s_ad AFTER DELETE ON %s BEGIN %s END`,
+				source.table, source.table, fmt.Sprintf(insert, source.table, "old", "DELETE", oldRow, "NULL")),
+		}
+		for _, statement := range statements {
+			if _, err := m.db.ExecContext(ctx, statement); err != nil {
+				return fmt.Errorf("failed to create audit log trigger for %s: %w", source.table, err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRewindManager(t *testing.T) {
+	manager, dbPath, cleanup := setupTestManager(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	require.NoError(t, manager.Open(ctx, dbPath))
+	defer manager.Close()
+
+	templates := NewTemplateManager(manager)
+	blueprints := NewBlueprintManager(manager)
+	trash := NewTrashManager(manager)
+
+	// stamp dates the audit_log entries written by step
+	stamp := func(at string, step func()) {
+		var last int
+		require.NoError(t, manager.GetDB().QueryRowContext(ctx, `SELECT COALESCE(MAX(id), 0) FROM audit_log`).Scan(&last))
+		step()
+		_, err := manager.GetDB().ExecContext(ctx, `UPDATE audit_log SET changed_at = ? WHERE id > ?`, at, last)
+		require.NoError(t, err)
+	}
+	save := func(name, content string) {
+		require.NoError(t, templates.Save(ctx, &StoredTemplate{Name: name, Files: []StoredFile{{Path: "main.go", Content: content}}}, true))
+	}
+
+	stamp("2024-06-01T10:00:00Z", func() {
+		save("api", "v1")
+		save("worker", "v1")
+		require.NoError(t, blueprints.Save(ctx, &StoredBlueprint{Name: "stack", Stack: "web", Config: `{"components":["gin"]}`}, false))
+	})
+	stamp("2024-06-01T12:30:00Z", func() {
+		save("api", "v2")
+		require.NoError(t, trash.Delete(ctx, KindTemplate, "worker"))
+		require.NoError(t, trash.Purge(ctx, KindTemplate, "worker"))
+		save("cli", "v1")
+		require.NoError(t, blueprints.Save(ctx, &StoredBlueprint{Name: "stack", Stack: "web", Config: `{"components":["chi"]}`}, true))
+	})
+
+	rewind := NewRewindManager(manager)
+	plan, err := rewind.Plan(ctx, time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.True(t, plan.Complete())
+
+	var changes []string
+	for _, change := range plan.Changes {
+		changes = append(changes, change.Action+" "+change.Kind+":"+change.Name)
+	}
+	assert.Equal(t, []string{
+		"revert template:api",
+		"remove template:cli",
+		"restore template:worker",
+		"revert blueprint:stack",
+	}, changes)
+
+	// Staging leaves the live tables alone
+	staged, err := rewind.Stage(ctx, plan)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"rewind_templates", "rewind_blueprints"}, staged)
+	var count int
+	require.NoError(t, manager.GetDB().QueryRowContext(ctx, `SELECT COUNT(*) FROM rewind_templates WHERE name IN ('api', 'worker')`).Scan(&count))
+	assert.Equal(t, 2, count)
+	current, err := templates.Get(ctx, "api")
+	require.NoError(t, err)
+	assert.Equal(t, "v2", current.Files[0].Content)
+
+	require.NoError(t, rewind.Apply(ctx, plan))
+	api, err := templates.Get(ctx, "api")
+	require.NoError(t, err)
+	assert.Equal(t, "v1", api.Files[0].Content)
+	worker, err := templates.Get(ctx, "worker")
+	require.NoError(t, err)
+	assert.Equal(t, "v1", worker.Files[0].Content)
+	_, err = templates.Get(ctx, "cli")
+	assert.Error(t, err)
+	stack, err := blueprints.Get(ctx, "stack")
+	require.NoError(t, err)
+	assert.Contains(t, stack.Config, "gin")
+
+	audits, err := NewBrowseManager(manager).Audits(ctx, KindTemplate+":worker", 1)
+	require.NoError(t, err)
+	require.Len(t, audits, 1)
+	assert.Equal(t, "rewind", audits[0].Action)
+
+	// Nothing is left to undo, and the rewind itself was recorded
+	plan, err = rewind.Plan(ctx, time.Now())
+	require.NoError(t, err)
+	assert.Empty(t, plan.Changes)
+	plan, err = rewind.Plan(ctx, time.Date(2024, 6, 1, 13, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Len(t, plan.Changes, 4, "rewinding to before the rewind undoes it")
+
+	// Before the history starts the plan is incomplete
+	plan, err = rewind.Plan(ctx, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.False(t, plan.Complete())
+
+	_, err = rewind.Plan(ctx, time.Now().Add(time.Hour))
+	assert.ErrorContains(t, err, "in the future")
+}
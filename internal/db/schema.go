@@ -6,11 +6,11 @@ const (
 CREATE TABLE IF NOT EXISTS templates (
     id              INTEGER PRIMARY KEY,
     name            TEXT NOT NULL UNIQUE,
-    kind            TEXT NOT NULL,
+    kind            TEXT NOT NULL DEFAULT 'project',
     content         BLOB NOT NULL,
     metadata_json   TEXT NOT NULL DEFAULT '{}',
-    created_at      TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP,
-    updated_at      TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP
+    created_at      TEXT NOT NULL DEFAULT ` + timestampDefault + `,
+    updated_at      TEXT NOT NULL DEFAULT ` + timestampDefault + `
 );`
 
 	createBlueprintsTable = `
@@ -19,8 +19,8 @@ CREATE TABLE IF NOT EXISTS blueprints (
     name            TEXT NOT NULL UNIQUE,
     stack           TEXT NOT NULL,
     config_json     TEXT NOT NULL,
-    created_at      TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP,
-    updated_at      TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP
+    created_at      TEXT NOT NULL DEFAULT ` + timestampDefault + `,
+    updated_at      TEXT NOT NULL DEFAULT ` + timestampDefault + `
 );`
 
 	createConfigsTable = `
@@ -39,7 +39,8 @@ CREATE TABLE IF NOT EXISTS hooks (
     event           TEXT NOT NULL,
     language        TEXT NOT NULL DEFAULT 'shell',
     script          TEXT NOT NULL,
-    enabled         INTEGER NOT NULL DEFAULT 1
+    enabled         INTEGER NOT NULL DEFAULT 1,
+    source          TEXT NOT NULL DEFAULT 'local'
 );`
 
 	createPluginsTable = `
@@ -58,7 +59,84 @@ CREATE TABLE IF NOT EXISTS audits (
     action          TEXT NOT NULL,
     entity          TEXT NOT NULL,
     details_json    TEXT NOT NULL DEFAULT '{}',
-    created_at      TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP
+    created_at      TEXT NOT NULL DEFAULT ` + timestampDefault + `
+);`
+
+	// createAuditLogTable matches migration 003_add_audit_trail. Triggers
+	// record the old and new values of every templates and blueprints row
+	// change in it; see ensureAuditLog.
+	createAuditLogTable = `
+CREATE TABLE IF NOT EXISTS audit_log (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    table_name VARCHAR(50) NOT NULL,
+    record_id VARCHAR(255) NOT NULL,
+    action VARCHAR(10) NOT NULL, -- INSERT, UPDATE, DELETE
+    old_values TEXT,
+    new_values TEXT,
+    changed_at TIMESTAMP DEFAULT ` + timestampDefault + `,
+    changed_by VARCHAR(255)
+);
+CREATE INDEX IF NOT EXISTS idx_audit_log_table ON audit_log(table_name);
+CREATE INDEX IF NOT EXISTS idx_audit_log_changed_at ON audit_log(changed_at);`
+
+	createRegistryCacheTable = `
+CREATE TABLE IF NOT EXISTS registry_cache (
+    id              INTEGER PRIMARY KEY,
+    url             TEXT NOT NULL UNIQUE,
+    etag            TEXT NOT NULL DEFAULT '',
+    last_modified   TEXT NOT NULL DEFAULT '',
+    body            BLOB NOT NULL,
+    fetched_at      TEXT NOT NULL DEFAULT ` + timestampDefault + `
+);`
+
+	createTagsTable = `
+CREATE TABLE IF NOT EXISTS tags (
+    id              INTEGER PRIMARY KEY,
+    name            TEXT NOT NULL UNIQUE
+);`
+
+	// createEntryTagsTable links tags to templates and blueprints by name, so
+	// built-in entries that have no row of their own can be tagged too
+	createEntryTagsTable = `
+CREATE TABLE IF NOT EXISTS entry_tags (
+    tag_id          INTEGER NOT NULL REFERENCES tags(id),
+    kind            TEXT NOT NULL,
+    entry_name      TEXT NOT NULL,
+    PRIMARY KEY (tag_id, kind, entry_name)
+);
+CREATE INDEX IF NOT EXISTS idx_entry_tags_entry ON entry_tags(kind, entry_name);`
+
+	createAnnotationsTable = `
+CREATE TABLE IF NOT EXISTS annotations (
+    id              INTEGER PRIMARY KEY,
+    kind            TEXT NOT NULL,
+    entry_name      TEXT NOT NULL,
+    note            TEXT NOT NULL DEFAULT '',
+    rating          INTEGER NOT NULL DEFAULT 0,
+    updated_at      TEXT NOT NULL DEFAULT ` + timestampDefault + `,
+    UNIQUE(kind, entry_name)
+);`
+
+	// createProjectStateTable holds the manifests and lockfiles of projects
+	// whose org policy keeps them out of their repositories
+	createProjectStateTable = `
+CREATE TABLE IF NOT EXISTS project_state (
+    module_path     TEXT NOT NULL,
+    name            TEXT NOT NULL,
+    content         BLOB NOT NULL,
+    updated_at      TEXT NOT NULL DEFAULT ` + timestampDefault + `,
+    PRIMARY KEY (module_path, name)
+);`
+
+	// createSearchIndex requires SQLite built with FTS5 (go build -tags sqlite_fts5)
+	createSearchIndex = `
+CREATE VIRTUAL TABLE IF NOT EXISTS search_index USING fts5(
+    kind UNINDEXED,
+    ref UNINDEXED,
+    name,
+    description,
+    body,
+    tokenize = 'porter unicode61'
 );`
 
 	createIndexes = `
@@ -69,3 +147,23 @@ CREATE INDEX IF NOT EXISTS idx_hooks_event ON hooks(event);
 CREATE INDEX IF NOT EXISTS idx_audits_action ON audits(action);
 CREATE INDEX IF NOT EXISTS idx_audits_created_at ON audits(created_at);`
 )
+
+// addedColumns are columns introduced after the original schema; they are
+// added to existing databases when missing
+var addedColumns = []struct{ table, column, definition string }{
+	{"templates", "deleted_at", "TEXT"},
+	{"templates", "description", "TEXT NOT NULL DEFAULT ''"},
+	{"blueprints", "deleted_at", "TEXT"},
+	{"blueprints", "metadata_json", "TEXT NOT NULL DEFAULT '{}'"},
+	{"blueprints", "description", "TEXT NOT NULL DEFAULT ''"},
+	{"hooks", "source", "TEXT NOT NULL DEFAULT '" + HookSourceLocal + "'"},
+}
+
+// searchSources describe how rows of each searchable table map onto search_index columns
+var searchSources = []struct {
+	kind, table, name, description, body string
+}{
+	{KindTemplate, "templates", "name", "description", "CAST(content AS TEXT)"},
+	{KindBlueprint, "blueprints", "name", "stack", "config_json"},
+	{KindAudit, "audits", "entity", "action", "actor || ' ' || details_json"},
+}
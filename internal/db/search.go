@@ -0,0 +1,356 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// KindAudit identifies audit entries in search results
+const KindAudit = "audit"
+
+// SearchOptions contains options for a full-text search
+type SearchOptions struct {
+	Kinds []string // Restrict results to these kinds; empty searches everything
+	Tags  []string // Only return templates and blueprints carrying all of these tags
+	Limit int
+}
+
+// SearchResult is a single search hit
+type SearchResult struct {
+	Kind    string
+	Name    string
+	Snippet string
+	Score   float64 // Higher is more relevant
+}
+
+// SearchManager searches stored templates, blueprints and audit entries
+type SearchManager struct {
+	db *Manager
+}
+
+// NewSearchManager creates a new search manager
+func NewSearchManager(manager *Manager) *SearchManager {
+	return &SearchManager{
+		db: manager,
+	}
+}
+
+// FullText reports whether searches use the FTS5 index rather than the LIKE fallback
+func (s *SearchManager) FullText() bool {
+	return s.db.fullText
+}
+
+// Search finds entries matching any term of query, best matches first
+func (s *SearchManager) Search(ctx context.Context, query string, opts SearchOptions) ([]SearchResult, error) {
+	terms := searchTerms(query)
+	if len(terms) == 0 {
+		return nil, fmt.Errorf("search query must contain at least one word")
+	}
+	if opts.Limit <= 0 {
+		opts.Limit = 20
+	}
+	tags := make([]string, len(opts.Tags))
+	for i, tag := range opts.Tags {
+		normalized, err := NormalizeTag(tag)
+		if err != nil {
+			return nil, err
+		}
+		tags[i] = normalized
+	}
+	opts.Tags = tags
+
+	if s.db.fullText {
+		return s.searchIndex(ctx, terms, opts)
+	}
+	return s.searchLike(ctx, terms, opts)
+}
+
+// Reindex rebuilds the full-text index from the source tables
+func (s *SearchManager) Reindex(ctx context.Context) error {
+	if !s.db.fullText {
+		return fmt.Errorf("full-text search is unavailable (gogo was built without FTS5)")
+	}
+	return s.db.WithTx(ctx, rebuildSearchIndex)
+}
+
+func (s *SearchManager) searchIndex(ctx context.Context, terms []string, opts SearchOptions) ([]SearchResult, error) {
+	quoted := make([]string, len(terms))
+	for i, term := range terms {
+		quoted[i] = `"` + term + `"`
+	}
+
+	query := `SELECT kind, name, snippet(search_index, -1, '[', ']', '…', 12), -bm25(search_index, 0, 0, 10.0, 5.0, 1.0)
+		FROM search_index
+		WHERE search_index MATCH ?
+		  AND NOT (kind = 'template' AND ref IN (SELECT id FROM templates WHERE deleted_at IS NOT NULL))
+		  AND NOT (kind = 'blueprint' AND ref IN (SELECT id FROM blueprints WHERE deleted_at IS NOT NULL))`
+	args := []any{strings.Join(quoted, " OR ")}
+	if len(opts.Kinds) > 0 {
+		query += ` AND kind IN (?` + strings.Repeat(", ?", len(opts.Kinds)-1) + `)`
+		for _, kind := range opts.Kinds {
+			args = append(args, kind)
+		}
+	}
+	if len(opts.Tags) > 0 {
+		query += ` AND (kind, name) IN (` + taggedEntriesQuery(len(opts.Tags)) + `)`
+		for _, tag := range opts.Tags {
+			args = append(args, tag)
+		}
+	}
+	query += ` ORDER BY 4 DESC LIMIT ?`
+	args = append(args, opts.Limit)
+
+	rows, err := s.db.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search: %w", err)
+	}
+	defer rows.Close()
+
+	var results []SearchResult
+	for rows.Next() {
+		var result SearchResult
+		if err := rows.Scan(&result.Kind, &result.Name, &result.Snippet, &result.Score); err != nil {
+			return nil, fmt.Errorf("failed to scan search result: %w", err)
+		}
+		results = append(results, result)
+	}
+	return results, rows.Err()
+}
+
+// searchLike scans the source tables when FTS5 is unavailable. Results are
+// scored by how many distinct terms match, with name matches weighted highest.
+func (s *SearchManager) searchLike(ctx context.Context, terms []string, opts SearchOptions) ([]SearchResult, error) {
+	var tagged map[string]bool
+	if len(opts.Tags) > 0 {
+		var err error
+		if tagged, err = s.taggedEntries(ctx, opts.Tags); err != nil {
+			return nil, err
+		}
+	}
+
+	var results []SearchResult
+	for _, source := range searchSources {
+		if len(opts.Kinds) > 0 && !containsString(opts.Kinds, source.kind) {
+			continue
+		}
+		if tagged != nil && source.kind == KindAudit {
+			continue
+		}
+
+		query := fmt.Sprintf(`SELECT %s, %s, %s FROM %s`, source.name, source.description, source.body, source.table)
+		if source.table != "audits" {
+			query += ` WHERE deleted_at IS NULL`
+		}
+		rows, err := s.db.db.QueryContext(ctx, query)
+		if err != nil {
+			return nil, fmt.Errorf("failed to search %s: %w", source.table, err)
+		}
+
+		for rows.Next() {
+			var name, description string
+			var body sql.NullString
+			if err := rows.Scan(&name, &description, &body); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("failed to scan search result: %w", err)
+			}
+			if tagged != nil && !tagged[source.kind+":"+name] {
+				continue
+			}
+			if score, snippet := scoreLike(terms, name, description, body.String); score > 0 {
+				results = append(results, SearchResult{Kind: source.kind, Name: name, Snippet: snippet, Score: score})
+			}
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to search %s: %w", source.table, err)
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+	if len(results) > opts.Limit {
+		results = results[:opts.Limit]
+	}
+	return results, nil
+}
+
+// taggedEntries returns "kind:name" keys of entries carrying every tag
+func (s *SearchManager) taggedEntries(ctx context.Context, tags []string) (map[string]bool, error) {
+	args := make([]any, len(tags))
+	for i, tag := range tags {
+		args[i] = tag
+	}
+	rows, err := s.db.db.QueryContext(ctx, taggedEntriesQuery(len(tags)), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tagged entries: %w", err)
+	}
+	defer rows.Close()
+
+	entries := make(map[string]bool)
+	for rows.Next() {
+		var kind, name string
+		if err := rows.Scan(&kind, &name); err != nil {
+			return nil, fmt.Errorf("failed to scan tagged entry: %w", err)
+		}
+		entries[kind+":"+name] = true
+	}
+	return entries, rows.Err()
+}
+
+func scoreLike(terms []string, name, description, body string) (float64, string) {
+	lowerName, lowerDesc, lowerBody := strings.ToLower(name), strings.ToLower(description), strings.ToLower(body)
+
+	var score float64
+	snippet := ""
+	for _, term := range terms {
+		switch {
+		case strings.Contains(lowerName, term):
+			score += 10
+		case strings.Contains(lowerDesc, term):
+			score += 5
+		case strings.Contains(lowerBody, term):
+			score++
+		default:
+			continue
+		}
+		if snippet == "" {
+			snippet = likeSnippet(description, lowerDesc, term)
+			if snippet == "" {
+				snippet = likeSnippet(body, lowerBody, term)
+			}
+		}
+	}
+	if snippet == "" {
+		snippet = description
+	}
+	return score, snippet
+}
+
+// likeSnippet returns the words surrounding the first occurrence of term
+func likeSnippet(text, lowerText, term string) string {
+	idx := strings.Index(lowerText, term)
+	if idx < 0 {
+		return ""
+	}
+
+	start, end := max(idx-40, 0), min(idx+len(term)+40, len(text))
+	snippet := strings.Join(strings.Fields(text[start:idx]+"["+text[idx:idx+len(term)]+"]"+text[idx+len(term):end]), " ")
+	if start > 0 {
+		snippet = "…" + snippet
+	}
+	if end < len(text) {
+		snippet += "…"
+	}
+	return snippet
+}
+
+// searchTerms splits a query into lower-case words
+func searchTerms(query string) []string {
+	seen := make(map[string]bool)
+	var terms []string
+	for _, field := range strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if !seen[field] {
+			seen[field] = true
+			terms = append(terms, field)
+		}
+	}
+	return terms
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// ensureSearchIndex creates the FTS5 index and its sync triggers when SQLite
+// supports FTS5. Otherwise any triggers left by an FTS5-enabled build are
+// dropped so writes keep working, and the index is rebuilt when next available.
+func (m *Manager) ensureSearchIndex(ctx context.Context) error {
+	var enabled int
+	if err := m.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM pragma_compile_options WHERE compile_options = 'ENABLE_FTS5'`).Scan(&enabled); err != nil {
+		return fmt.Errorf("failed to inspect SQLite features: %w", err)
+	}
+	m.fullText = enabled > 0
+
+	if !m.fullText {
+		for _, trigger := range searchTriggerNames() {
+			if _, err := m.db.ExecContext(ctx, `DROP TRIGGER IF EXISTS `+trigger); err != nil {
+				return fmt.Errorf("failed to drop trigger %s: %w", trigger, err)
+			}
+		}
+		return nil
+	}
+
+	if _, err := m.db.ExecContext(ctx, createSearchIndex); err != nil {
+		return fmt.Errorf("failed to create search index: %w", err)
+	}
+
+	var triggers int
+	if err := m.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM sqlite_master WHERE type = 'trigger' AND name LIKE 'search_%'`).Scan(&triggers); err != nil {
+		return fmt.Errorf("failed to inspect search triggers: %w", err)
+	}
+	if triggers == len(searchTriggerNames()) {
+		return nil
+	}
+
+	return m.WithTx(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		for _, source := range searchSources {
+			insert := fmt.Sprintf(`INSERT INTO search_index (kind, ref, name, description, body)
+				SELECT '%s', id, %s, %s, %s FROM %s WHERE id = new.id;`,
+				source.kind, source.name, source.description, source.body, source.table)
+			remove := fmt.Sprintf(`DELETE FROM search_index WHERE kind = '%s' AND ref = old.id;`, source.kind)
+
+			statements := []string{
+				fmt.Sprintf(`CREATE TRIGGER IF NOT EXISTS search_%s_ai AFTER INSERT ON %s BEGIN %s END`, source.table, source.table, insert),
+				fmt.Sprintf(`CREATE TRIGGER IF NOT EXISTS search_%s_ad AFTER DELETE ON %s BEGIN %s END`, source.table, source.table, remove),
+				fmt.Sprintf(`CREATE TRIGGER IF NOT EXISTS search_%s_au AFTER UPDATE ON %s BEGIN %s %s END`, source.table, source.table, remove, insert),
+			}
+			for _, statement := range statements {
+				if _, err := tx.ExecContext(ctx, statement); err != nil {
+					return fmt.Errorf("failed to create search trigger for %s: %w", source.table, err)
+				}
+			}
+		}
+		return rebuildSearchIndex(ctx, tx)
+	})
+}
+
+// rebuildSearchIndex repopulates search_index from the source tables
+func rebuildSearchIndex(ctx context.Context, tx *sql.Tx) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM search_index`); err != nil {
+		return fmt.Errorf("failed to clear search index: %w", err)
+	}
+	for _, source := range searchSources {
+		query := fmt.Sprintf(`INSERT INTO search_index (kind, ref, name, description, body)
+			SELECT '%s', id, %s, %s, %s FROM %s`,
+			source.kind, source.name, source.description, source.body, source.table)
+		if _, err := tx.ExecContext(ctx, query); err != nil {
+			return fmt.Errorf("failed to index %s: %w", source.table, err)
+		}
+	}
+	return nil
+}
+
+func searchTriggerNames() []string {
+	var names []string
+	for _, source := range searchSources {
+		for _, suffix := range []string{"ai", "ad", "au"} {
+			names = append(names, fmt.Sprintf("search_%s_%s", source.table, suffix))
+		}
+	}
+	return names
+}
@@ -0,0 +1,69 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchManager_Search(t *testing.T) {
+	manager, dbPath, cleanup := setupTestManager(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	require.NoError(t, manager.Open(ctx, dbPath))
+	defer manager.Close()
+
+	_, err := manager.GetDB().ExecContext(ctx,
+		`INSERT INTO templates (name, description, content) VALUES
+		 ('event-worker', 'Background worker that sets up Kafka consumers', 'package main // sarama consumer group'),
+		 ('rest-api', 'HTTP API with Gin', 'package main // gin router'),
+		 ('old-kafka', 'Deprecated Kafka producer', 'package main')`)
+	require.NoError(t, err)
+	_, err = manager.GetDB().ExecContext(ctx,
+		`INSERT INTO blueprints (name, stack, config_json) VALUES ('stream-stack', 'microservice', '{"messaging": "kafka"}')`)
+	require.NoError(t, err)
+
+	trash := NewTrashManager(manager)
+	require.NoError(t, trash.Delete(ctx, KindTemplate, "old-kafka"))
+
+	search := NewSearchManager(manager)
+
+	results, err := search.Search(ctx, "the template that sets up kafka consumers", SearchOptions{})
+	require.NoError(t, err)
+	require.NotEmpty(t, results)
+	assert.Equal(t, "event-worker", results[0].Name)
+	assert.Contains(t, results[0].Snippet, "[")
+
+	names := make([]string, 0, len(results))
+	for _, result := range results {
+		names = append(names, result.Kind+":"+result.Name)
+	}
+	assert.Contains(t, names, "blueprint:stream-stack")
+	assert.NotContains(t, names, "template:old-kafka", "trashed templates are hidden")
+	assert.Contains(t, names, "audit:template:old-kafka", "audit entries are searchable")
+
+	// Restricting kinds
+	results, err = search.Search(ctx, "kafka", SearchOptions{Kinds: []string{KindTemplate}})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "event-worker", results[0].Name)
+
+	// Updates are reflected in the results
+	_, err = manager.GetDB().ExecContext(ctx, `UPDATE templates SET description = 'NATS consumers' WHERE name = 'event-worker'`)
+	require.NoError(t, err)
+	results, err = search.Search(ctx, "nats", SearchOptions{})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "event-worker", results[0].Name)
+
+	_, err = search.Search(ctx, "  ...  ", SearchOptions{})
+	assert.Error(t, err)
+}
+
+func TestSearchTerms(t *testing.T) {
+	assert.Equal(t, []string{"kafka", "consumer", "v2"}, searchTerms(`Kafka "consumer" kafka-v2`))
+	assert.Empty(t, searchTerms("--"))
+}
@@ -0,0 +1,50 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ProjectStateManager stores the files gogo keeps about a project, such as
+// its manifest and lockfile, for org policies that keep them out of the
+// project's repository. Files are identified by module path and file name.
+type ProjectStateManager struct {
+	db  *Manager
+	now func() time.Time
+}
+
+// NewProjectStateManager creates a new project state manager
+func NewProjectStateManager(manager *Manager) *ProjectStateManager {
+	return &ProjectStateManager{
+		db:  manager,
+		now: time.Now,
+	}
+}
+
+// Get returns the content of a project's file, or nil if it has none
+func (p *ProjectStateManager) Get(ctx context.Context, modulePath, name string) ([]byte, error) {
+	var content []byte
+	err := p.db.GetDB().QueryRowContext(ctx,
+		`SELECT content FROM project_state WHERE module_path = ? AND name = ?`, modulePath, name).Scan(&content)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s of %s: %w", name, modulePath, err)
+	}
+	return content, nil
+}
+
+// Save creates or replaces a project's file
+func (p *ProjectStateManager) Save(ctx context.Context, modulePath, name string, content []byte) error {
+	query := `INSERT INTO project_state (module_path, name, content, updated_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT(module_path, name) DO UPDATE SET content = excluded.content, updated_at = excluded.updated_at`
+	if _, err := p.db.GetDB().ExecContext(ctx, query,
+		modulePath, name, content, FormatTimestamp(p.now().UTC().Truncate(time.Second))); err != nil {
+		return fmt.Errorf("failed to save %s of %s: %w", name, modulePath, err)
+	}
+	return nil
+}
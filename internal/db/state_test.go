@@ -0,0 +1,41 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectStateManager(t *testing.T) {
+	manager, dbPath, cleanup := setupTestManager(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	require.NoError(t, manager.Open(ctx, dbPath))
+	defer manager.Close()
+
+	state := NewProjectStateManager(manager)
+
+	content, err := state.Get(ctx, "github.com/acme/orders", ".gogo.yaml")
+	require.NoError(t, err)
+	assert.Nil(t, content)
+
+	require.NoError(t, state.Save(ctx, "github.com/acme/orders", ".gogo.yaml", []byte("template: api\n")))
+	require.NoError(t, state.Save(ctx, "github.com/acme/billing", ".gogo.yaml", []byte("template: grpc\n")))
+
+	content, err = state.Get(ctx, "github.com/acme/orders", ".gogo.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, "template: api\n", string(content))
+
+	// Saving again replaces the file
+	require.NoError(t, state.Save(ctx, "github.com/acme/orders", ".gogo.yaml", []byte("template: cli\n")))
+	content, err = state.Get(ctx, "github.com/acme/orders", ".gogo.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, "template: cli\n", string(content))
+
+	content, err = state.Get(ctx, "github.com/acme/orders", "gogo.lock")
+	require.NoError(t, err)
+	assert.Nil(t, content)
+}
@@ -0,0 +1,184 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var tagPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+
+// TagCount is a tag with the number of entries carrying it
+type TagCount struct {
+	Name  string
+	Count int
+}
+
+// TagManager tags templates and blueprints. Entries are identified by kind and
+// name, so built-in templates and blueprints can be tagged as well.
+type TagManager struct {
+	db *Manager
+}
+
+// NewTagManager creates a new tag manager
+func NewTagManager(manager *Manager) *TagManager {
+	return &TagManager{
+		db: manager,
+	}
+}
+
+// NormalizeTag lower-cases and validates a tag name
+func NormalizeTag(tag string) (string, error) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if !tagPattern.MatchString(tag) {
+		return "", fmt.Errorf("invalid tag '%s' (use letters, digits, '.', '_' and '-')", tag)
+	}
+	return tag, nil
+}
+
+// Tag adds tags to an entry
+func (t *TagManager) Tag(ctx context.Context, kind, name string, tags ...string) error {
+	if _, err := trashTable(kind); err != nil {
+		return err
+	}
+	normalized, err := normalizeTags(tags)
+	if err != nil {
+		return err
+	}
+
+	return t.db.WithTx(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		for _, tag := range normalized {
+			if _, err := tx.ExecContext(ctx, `INSERT OR IGNORE INTO tags (name) VALUES (?)`, tag); err != nil {
+				return fmt.Errorf("failed to create tag %s: %w", tag, err)
+			}
+			query := `INSERT OR IGNORE INTO entry_tags (tag_id, kind, entry_name)
+				SELECT id, ?, ? FROM tags WHERE name = ?`
+			if _, err := tx.ExecContext(ctx, query, kind, name, tag); err != nil {
+				return fmt.Errorf("failed to tag %s '%s': %w", kind, name, err)
+			}
+		}
+		return nil
+	})
+}
+
+// Untag removes tags from an entry; tags no longer in use are deleted
+func (t *TagManager) Untag(ctx context.Context, kind, name string, tags ...string) error {
+	normalized, err := normalizeTags(tags)
+	if err != nil {
+		return err
+	}
+
+	return t.db.WithTx(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		for _, tag := range normalized {
+			query := `DELETE FROM entry_tags WHERE kind = ? AND entry_name = ?
+				AND tag_id = (SELECT id FROM tags WHERE name = ?)`
+			if _, err := tx.ExecContext(ctx, query, kind, name, tag); err != nil {
+				return fmt.Errorf("failed to untag %s '%s': %w", kind, name, err)
+			}
+		}
+		return deleteUnusedTags(ctx, tx)
+	})
+}
+
+// TagsFor returns the sorted tags of an entry
+func (t *TagManager) TagsFor(ctx context.Context, kind, name string) ([]string, error) {
+	tagged, err := t.Tagged(ctx, kind)
+	if err != nil {
+		return nil, err
+	}
+	return tagged[name], nil
+}
+
+// Tagged returns the tags of every tagged entry of a kind, keyed by entry name
+func (t *TagManager) Tagged(ctx context.Context, kind string) (map[string][]string, error) {
+	query := `SELECT et.entry_name, tg.name FROM entry_tags et
+		JOIN tags tg ON tg.id = et.tag_id
+		WHERE et.kind = ?
+		ORDER BY et.entry_name, tg.name`
+	rows, err := t.db.GetDB().QueryContext(ctx, query, kind)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tags: %w", err)
+	}
+	defer rows.Close()
+
+	tagged := make(map[string][]string)
+	for rows.Next() {
+		var name, tag string
+		if err := rows.Scan(&name, &tag); err != nil {
+			return nil, fmt.Errorf("failed to scan tag row: %w", err)
+		}
+		tagged[name] = append(tagged[name], tag)
+	}
+	return tagged, rows.Err()
+}
+
+// List returns all tags with their usage counts
+func (t *TagManager) List(ctx context.Context) ([]TagCount, error) {
+	query := `SELECT tg.name, COUNT(et.tag_id) FROM tags tg
+		LEFT JOIN entry_tags et ON et.tag_id = tg.id
+		GROUP BY tg.id ORDER BY tg.name`
+	rows, err := t.db.GetDB().QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tags: %w", err)
+	}
+	defer rows.Close()
+
+	var tags []TagCount
+	for rows.Next() {
+		var tag TagCount
+		if err := rows.Scan(&tag.Name, &tag.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan tag row: %w", err)
+		}
+		tags = append(tags, tag)
+	}
+	return tags, rows.Err()
+}
+
+// HasAllTags reports whether entryTags contains every tag in required
+func HasAllTags(entryTags, required []string) bool {
+	for _, tag := range required {
+		if !containsString(entryTags, strings.ToLower(tag)) {
+			return false
+		}
+	}
+	return true
+}
+
+// taggedEntriesQuery selects (kind, entry_name) pairs carrying every one of n tags
+func taggedEntriesQuery(n int) string {
+	return `SELECT et.kind, et.entry_name FROM entry_tags et
+		JOIN tags tg ON tg.id = et.tag_id
+		WHERE tg.name IN (?` + strings.Repeat(", ?", n-1) + `)
+		GROUP BY et.kind, et.entry_name
+		HAVING COUNT(DISTINCT tg.name) = ` + fmt.Sprint(n)
+}
+
+func deleteUnusedTags(ctx context.Context, tx *sql.Tx) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM tags WHERE id NOT IN (SELECT tag_id FROM entry_tags)`); err != nil {
+		return fmt.Errorf("failed to delete unused tags: %w", err)
+	}
+	return nil
+}
+
+func normalizeTags(tags []string) ([]string, error) {
+	if len(tags) == 0 {
+		return nil, fmt.Errorf("at least one tag is required")
+	}
+	seen := make(map[string]bool)
+	var normalized []string
+	for _, tag := range tags {
+		tag, err := NormalizeTag(tag)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			normalized = append(normalized, tag)
+		}
+	}
+	sort.Strings(normalized)
+	return normalized, nil
+}
@@ -0,0 +1,84 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTagManager(t *testing.T) {
+	manager, dbPath, cleanup := setupTestManager(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	require.NoError(t, manager.Open(ctx, dbPath))
+	defer manager.Close()
+
+	tags := NewTagManager(manager)
+
+	require.NoError(t, tags.Tag(ctx, KindTemplate, "api", "Internal", "payment"))
+	require.NoError(t, tags.Tag(ctx, KindTemplate, "api", "internal"))
+	require.NoError(t, tags.Tag(ctx, KindTemplate, "worker", "internal"))
+	require.NoError(t, tags.Tag(ctx, KindBlueprint, "web-stack", "deprecated"))
+
+	assert.Error(t, tags.Tag(ctx, KindTemplate, "api", "not valid"))
+	assert.Error(t, tags.Tag(ctx, "plugin", "api", "internal"))
+	assert.Error(t, tags.Tag(ctx, KindTemplate, "api"))
+
+	entryTags, err := tags.TagsFor(ctx, KindTemplate, "api")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"internal", "payment"}, entryTags)
+
+	tagged, err := tags.Tagged(ctx, KindTemplate)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{
+		"api":    {"internal", "payment"},
+		"worker": {"internal"},
+	}, tagged)
+
+	counts, err := tags.List(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []TagCount{{"deprecated", 1}, {"internal", 2}, {"payment", 1}}, counts)
+
+	// Untagging removes tags nobody uses any more
+	require.NoError(t, tags.Untag(ctx, KindTemplate, "api", "payment"))
+	counts, err = tags.List(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []TagCount{{"deprecated", 1}, {"internal", 2}}, counts)
+
+	assert.True(t, HasAllTags([]string{"internal", "payment"}, []string{"Internal"}))
+	assert.False(t, HasAllTags([]string{"internal"}, []string{"internal", "payment"}))
+}
+
+func TestSearchManager_SearchByTag(t *testing.T) {
+	manager, dbPath, cleanup := setupTestManager(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	require.NoError(t, manager.Open(ctx, dbPath))
+	defer manager.Close()
+
+	_, err := manager.GetDB().ExecContext(ctx,
+		`INSERT INTO templates (name, description, content) VALUES
+		 ('payments-worker', 'Kafka consumer for payments', 'x'),
+		 ('audit-worker', 'Kafka consumer for audits', 'x')`)
+	require.NoError(t, err)
+
+	require.NoError(t, NewTagManager(manager).Tag(ctx, KindTemplate, "payments-worker", "payment", "internal"))
+
+	results, err := NewSearchManager(manager).Search(ctx, "kafka", SearchOptions{Tags: []string{"Payment"}})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "payments-worker", results[0].Name)
+
+	// Purging a trashed entry drops its tags
+	trash := NewTrashManager(manager)
+	require.NoError(t, trash.Delete(ctx, KindTemplate, "payments-worker"))
+	require.NoError(t, trash.Purge(ctx, KindTemplate, "payments-worker"))
+
+	counts, err := NewTagManager(manager).List(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, counts)
+}
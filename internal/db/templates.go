@@ -0,0 +1,198 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Errors returned by TemplateManager
+var (
+	ErrTemplateNotFound = errors.New("template not found")
+	ErrTemplateExists   = errors.New("template already exists")
+)
+
+// StoredFile is one file of a stored template
+type StoredFile struct {
+	Path    string `json:"path"`
+	Content string `json:"content"`
+}
+
+// InstallSource records where an installed template or blueprint came from
+type InstallSource struct {
+	URL         string    `json:"url"`
+	Ref         string    `json:"ref,omitempty"`
+	Commit      string    `json:"commit,omitempty"` // Git commit the install was pinned to
+	SHA256      string    `json:"sha256,omitempty"` // Checksum of the installed archive
+	InstalledAt time.Time `json:"installed_at"`
+}
+
+// StoredTemplate is a user template kept in the templates table. Its files
+// are stored as a JSON array in the content column.
+type StoredTemplate struct {
+	Name        string
+	Kind        string
+	Description string
+	Files       []StoredFile
+	Source      *InstallSource // Set for templates added with gogo template install
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// entryMetadata is the metadata_json of stored templates and blueprints
+type entryMetadata struct {
+	Source *InstallSource `json:"source,omitempty"`
+}
+
+// TemplateManager stores user templates added with gogo template add
+type TemplateManager struct {
+	db    *Manager
+	actor string
+	now   func() time.Time
+}
+
+// NewTemplateManager creates a new template manager
+func NewTemplateManager(manager *Manager) *TemplateManager {
+	return &TemplateManager{
+		db:    manager,
+		actor: currentActor(),
+		now:   time.Now,
+	}
+}
+
+// Save stores a template. An existing template of the same name, including
+// one in the trash, is only replaced when replace is set.
+func (t *TemplateManager) Save(ctx context.Context, template *StoredTemplate, replace bool) error {
+	if !tagPattern.MatchString(template.Name) {
+		return fmt.Errorf("invalid template name '%s' (use lower-case letters, digits, '.', '_' and '-')", template.Name)
+	}
+	if len(template.Files) == 0 {
+		return fmt.Errorf("template '%s' has no files", template.Name)
+	}
+	if template.Kind == "" {
+		template.Kind = "project"
+	}
+	content, err := json.Marshal(template.Files)
+	if err != nil {
+		return fmt.Errorf("failed to encode template '%s': %w", template.Name, err)
+	}
+	metadata, err := json.Marshal(entryMetadata{Source: template.Source})
+	if err != nil {
+		return fmt.Errorf("failed to encode template '%s': %w", template.Name, err)
+	}
+
+	return t.db.WithTx(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		var deletedAt sql.NullString
+		err := tx.QueryRowContext(ctx, `SELECT deleted_at FROM templates WHERE name = ?`, template.Name).Scan(&deletedAt)
+		exists := err == nil
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("failed to look up template '%s': %w", template.Name, err)
+		}
+		if exists && !replace {
+			if deletedAt.Valid {
+				return fmt.Errorf("%w in the trash: %s", ErrTemplateExists, template.Name)
+			}
+			return fmt.Errorf("%w: %s", ErrTemplateExists, template.Name)
+		}
+
+		now := FormatTimestamp(t.now())
+		action := "add"
+		if exists {
+			action = "replace"
+			query := `UPDATE templates SET kind = ?, description = ?, content = ?, metadata_json = ?, updated_at = ?, deleted_at = NULL WHERE name = ?`
+			_, err = tx.ExecContext(ctx, query, template.Kind, template.Description, content, string(metadata), now, template.Name)
+		} else {
+			query := `INSERT INTO templates (name, kind, description, content, metadata_json, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?)`
+			_, err = tx.ExecContext(ctx, query, template.Name, template.Kind, template.Description, content, string(metadata), now, now)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to save template '%s': %w", template.Name, err)
+		}
+
+		auditDetails := map[string]any{"kind": KindTemplate, "name": template.Name, "files": len(template.Files)}
+		if template.Source != nil {
+			auditDetails["source"] = template.Source.URL
+		}
+		details, err := json.Marshal(auditDetails)
+		if err != nil {
+			return err
+		}
+		query := `INSERT INTO audits (actor, action, entity, details_json, created_at) VALUES (?, ?, ?, ?, ?)`
+		if _, err := tx.ExecContext(ctx, query, t.actor, action, KindTemplate+":"+template.Name, string(details), now); err != nil {
+			return fmt.Errorf("failed to record audit entry: %w", err)
+		}
+		return nil
+	})
+}
+
+// Get returns a stored template that is not in the trash
+func (t *TemplateManager) Get(ctx context.Context, name string) (*StoredTemplate, error) {
+	query := `SELECT name, kind, description, content, metadata_json, created_at, updated_at
+		FROM templates WHERE name = ? AND deleted_at IS NULL`
+	template, err := scanTemplate(t.db.GetDB().QueryRowContext(ctx, query, name))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", ErrTemplateNotFound, name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load template '%s': %w", name, err)
+	}
+	if len(template.Files) == 0 {
+		return nil, fmt.Errorf("template '%s' has no files; re-add it with gogo template add", name)
+	}
+	return template, nil
+}
+
+// List returns stored templates that are not in the trash, by name
+func (t *TemplateManager) List(ctx context.Context) ([]*StoredTemplate, error) {
+	query := `SELECT name, kind, description, content, metadata_json, created_at, updated_at
+		FROM templates WHERE deleted_at IS NULL ORDER BY name`
+	rows, err := t.db.GetDB().QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query templates: %w", err)
+	}
+	defer rows.Close()
+
+	var templates []*StoredTemplate
+	for rows.Next() {
+		template, err := scanTemplate(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan template row: %w", err)
+		}
+		templates = append(templates, template)
+	}
+	return templates, rows.Err()
+}
+
+// scanTemplate reads a template row selected as name, kind, description,
+// content, metadata_json, created_at, updated_at
+func scanTemplate(row interface{ Scan(...any) error }) (*StoredTemplate, error) {
+	var (
+		template             StoredTemplate
+		content              []byte
+		metadataJSON         string
+		createdAt, updatedAt string
+	)
+	if err := row.Scan(&template.Name, &template.Kind, &template.Description, &content, &metadataJSON, &createdAt, &updatedAt); err != nil {
+		return nil, err
+	}
+	// Rows not written by Save have no files
+	if json.Unmarshal(content, &template.Files) != nil {
+		template.Files = nil
+	}
+	var metadata entryMetadata
+	if json.Unmarshal([]byte(metadataJSON), &metadata) == nil {
+		template.Source = metadata.Source
+	}
+
+	var err error
+	if template.CreatedAt, err = ParseTimestamp(createdAt); err != nil {
+		return nil, err
+	}
+	if template.UpdatedAt, err = ParseTimestamp(updatedAt); err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
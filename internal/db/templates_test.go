@@ -0,0 +1,106 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplateManager_SaveGetList(t *testing.T) {
+	manager, dbPath, cleanup := setupTestManager(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	require.NoError(t, manager.Open(ctx, dbPath))
+	defer manager.Close()
+
+	templates := NewTemplateManager(manager)
+	template := &StoredTemplate{
+		Name:        "worker",
+		Description: "Background worker",
+		Files:       []StoredFile{{Path: "cmd/{{ ProjectName }}/main.go", Content: "package main\n"}},
+	}
+	require.NoError(t, templates.Save(ctx, template, false))
+
+	loaded, err := templates.Get(ctx, "worker")
+	require.NoError(t, err)
+	assert.Equal(t, "project", loaded.Kind)
+	assert.Equal(t, "Background worker", loaded.Description)
+	assert.Equal(t, template.Files, loaded.Files)
+
+	// Adding again needs replace
+	assert.ErrorIs(t, templates.Save(ctx, template, false), ErrTemplateExists)
+	template.Files = append(template.Files, StoredFile{Path: "README.md", Content: "# {{ ProjectName }}\n"})
+	require.NoError(t, templates.Save(ctx, template, true))
+	loaded, err = templates.Get(ctx, "worker")
+	require.NoError(t, err)
+	assert.Len(t, loaded.Files, 2)
+
+	// Rows written elsewhere are listed without files and cannot be used
+	_, err = manager.GetDB().ExecContext(ctx, `INSERT INTO templates (name, kind, content) VALUES ('legacy', 'project', 'x')`)
+	require.NoError(t, err)
+	list, err := templates.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, "legacy", list[0].Name)
+	assert.Empty(t, list[0].Files)
+	_, err = templates.Get(ctx, "legacy")
+	assert.ErrorContains(t, err, "no files")
+
+	// Trashed templates are hidden and only replaced on request
+	require.NoError(t, NewTrashManager(manager).Delete(ctx, KindTemplate, "worker"))
+	_, err = templates.Get(ctx, "worker")
+	assert.ErrorIs(t, err, ErrTemplateNotFound)
+	assert.ErrorContains(t, templates.Save(ctx, template, false), "in the trash")
+	require.NoError(t, templates.Save(ctx, template, true))
+	_, err = templates.Get(ctx, "worker")
+	assert.NoError(t, err)
+
+	var audits int
+	require.NoError(t, manager.GetDB().QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM audits WHERE entity = 'template:worker' AND action IN ('add', 'replace')`).Scan(&audits))
+	assert.Equal(t, 3, audits)
+}
+
+func TestTemplateManager_SaveValidates(t *testing.T) {
+	manager, dbPath, cleanup := setupTestManager(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	require.NoError(t, manager.Open(ctx, dbPath))
+	defer manager.Close()
+
+	templates := NewTemplateManager(manager)
+	assert.ErrorContains(t, templates.Save(ctx, &StoredTemplate{Name: "My Template", Files: []StoredFile{{Path: "a"}}}, false), "invalid template name")
+	assert.ErrorContains(t, templates.Save(ctx, &StoredTemplate{Name: "empty"}, false), "no files")
+}
+
+func TestTemplateManager_SaveSource(t *testing.T) {
+	manager, dbPath, cleanup := setupTestManager(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	require.NoError(t, manager.Open(ctx, dbPath))
+	defer manager.Close()
+
+	templates := NewTemplateManager(manager)
+	source := &InstallSource{URL: "https://example.com/worker.tar.gz", SHA256: "abc", InstalledAt: time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)}
+	template := &StoredTemplate{Name: "worker", Files: []StoredFile{{Path: "main.go"}}, Source: source}
+	require.NoError(t, templates.Save(ctx, template, false))
+
+	loaded, err := templates.Get(ctx, "worker")
+	require.NoError(t, err)
+	require.NotNil(t, loaded.Source)
+	assert.Equal(t, source.URL, loaded.Source.URL)
+	assert.True(t, source.InstalledAt.Equal(loaded.Source.InstalledAt))
+
+	// Replacing with a local template drops the source
+	template.Source = nil
+	require.NoError(t, templates.Save(ctx, template, true))
+	loaded, err = templates.Get(ctx, "worker")
+	require.NoError(t, err)
+	assert.Nil(t, loaded.Source)
+}
@@ -0,0 +1,58 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TimestampFormat is how timestamps are stored: UTC, serialized as RFC3339
+const TimestampFormat = time.RFC3339
+
+// sqliteTimestampFormat is what SQLite's CURRENT_TIMESTAMP produces (always UTC)
+const sqliteTimestampFormat = "2006-01-02 15:04:05"
+
+// timestampDefault is the column default used for new timestamp columns
+const timestampDefault = `(strftime('%Y-%m-%dT%H:%M:%SZ', 'now'))`
+
+// FormatTimestamp serializes t for storage
+func FormatTimestamp(t time.Time) string {
+	return t.UTC().Format(TimestampFormat)
+}
+
+// ParseTimestamp parses a stored timestamp. Besides RFC3339 it accepts the
+// CURRENT_TIMESTAMP layout written by older databases, which is UTC.
+func ParseTimestamp(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t.UTC(), nil
+	}
+	for _, layout := range []string{sqliteTimestampFormat, "2006-01-02T15:04:05", "2006-01-02 15:04:05.999999999"} {
+		if t, err := time.ParseInLocation(layout, value, time.UTC); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid timestamp %q", value)
+}
+
+// timestampColumns lists the timestamp columns normalized when a database is
+// opened; tables created by optional migrations are skipped until they exist
+var timestampColumns = []struct{ table, column string }{
+	{"templates", "created_at"},
+	{"templates", "updated_at"},
+	{"blueprints", "created_at"},
+	{"blueprints", "updated_at"},
+	{"audits", "created_at"},
+	{"registry_cache", "fetched_at"},
+	{"annotations", "updated_at"},
+	{"project_state", "updated_at"},
+	{"audit_log", "changed_at"},
+	{"schema_migrations", "applied_at"},
+}
+
+// normalizeTimestampsSQL rewrites legacy timestamp values in column of table to
+// UTC RFC3339. Offsets are converted to UTC; unparseable values are left alone.
+func normalizeTimestampsSQL(table, column string) string {
+	return fmt.Sprintf(`UPDATE %[1]s SET %[2]s = COALESCE(strftime('%%Y-%%m-%%dT%%H:%%M:%%SZ', %[2]s), %[2]s)
+    WHERE %[2]s IS NOT NULL AND %[2]s NOT GLOB '[0-9][0-9][0-9][0-9]-[0-9][0-9]-[0-9][0-9]T[0-9][0-9]:[0-9][0-9]:[0-9][0-9]Z';`, table, column)
+}
@@ -0,0 +1,88 @@
+package db
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTimestamp(t *testing.T) {
+	expected := time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "RFC3339 UTC", input: "2024-03-01T12:30:00Z"},
+		{name: "RFC3339 with offset", input: "2024-03-01T14:30:00+02:00"},
+		{name: "SQLite CURRENT_TIMESTAMP", input: "2024-03-01 12:30:00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := ParseTimestamp(tt.input)
+			require.NoError(t, err)
+			assert.True(t, expected.Equal(parsed))
+			assert.Equal(t, time.UTC, parsed.Location())
+		})
+	}
+
+	_, err := ParseTimestamp("yesterday")
+	assert.Error(t, err)
+}
+
+func TestFormatTimestamp(t *testing.T) {
+	loc := time.FixedZone("UTC-5", -5*60*60)
+	assert.Equal(t, "2024-03-01T17:30:00Z", FormatTimestamp(time.Date(2024, 3, 1, 12, 30, 0, 0, loc)))
+}
+
+func TestManager_NormalizesLegacyTimestamps(t *testing.T) {
+	ctx := context.Background()
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+
+	manager := NewManager()
+	require.NoError(t, manager.Open(ctx, dbPath))
+	_, err := manager.GetDB().ExecContext(ctx,
+		`INSERT INTO templates (name, kind, content, created_at, updated_at) VALUES
+		 ('legacy', 'project', 'x', '2024-03-01 12:30:00', '2024-03-01T14:30:00+02:00'),
+		 ('fresh', 'project', 'x', '2024-03-01T12:30:00Z', '2024-03-01T12:30:00Z')`)
+	require.NoError(t, err)
+	_, err = manager.GetDB().ExecContext(ctx, `INSERT INTO templates (name, kind, content) VALUES ('default', 'project', 'x')`)
+	require.NoError(t, err)
+
+	migrationManager := NewMigrationManager(manager.GetDB())
+	require.NoError(t, migrationManager.InitMigrationTable(ctx))
+	_, err = manager.GetDB().ExecContext(ctx,
+		`INSERT INTO schema_migrations (id, description, applied_at, checksum) VALUES ('001', 'legacy', '2024-03-01 12:30:00', 'x')`)
+	require.NoError(t, err)
+	require.NoError(t, manager.Close())
+
+	// Reopening normalizes existing rows
+	require.NoError(t, manager.Open(ctx, dbPath))
+	defer manager.Close()
+
+	rows, err := manager.GetDB().QueryContext(ctx, `SELECT name, created_at, updated_at FROM templates ORDER BY name`)
+	require.NoError(t, err)
+	defer rows.Close()
+
+	for rows.Next() {
+		var name, createdAt, updatedAt string
+		require.NoError(t, rows.Scan(&name, &createdAt, &updatedAt))
+		assert.Regexp(t, `^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}Z$`, createdAt, name)
+		assert.Regexp(t, `^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}Z$`, updatedAt, name)
+		if name != "default" {
+			assert.Equal(t, "2024-03-01T12:30:00Z", createdAt, name)
+			assert.Equal(t, "2024-03-01T12:30:00Z", updatedAt, name)
+		}
+	}
+	require.NoError(t, rows.Err())
+
+	var appliedAt string
+	require.NoError(t, manager.GetDB().QueryRowContext(ctx,
+		`SELECT CAST(applied_at AS TEXT) FROM schema_migrations WHERE id = '001'`).Scan(&appliedAt))
+	assert.Equal(t, "2024-03-01T12:30:00Z", appliedAt)
+}
@@ -0,0 +1,246 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"syscall"
+	"time"
+
+	"github.com/user/gogo/internal/fsutil"
+)
+
+const (
+	// DefaultTransferChunkSize is how much is written between checkpoints
+	DefaultTransferChunkSize = 4 << 20
+	// DefaultTransferRetries is how many times a chunk failing with a transient error is attempted
+	DefaultTransferRetries = 3
+	// PartialSuffix is appended to a transfer's destination while it is incomplete
+	PartialSuffix = ".part"
+	// TransferStateSuffix is appended to the partial file to name its state file
+	TransferStateSuffix = ".json"
+)
+
+// transferRetryDelay is the wait before the first retry; it doubles with every attempt
+var transferRetryDelay = 500 * time.Millisecond
+
+// ErrSourceChanged is returned when resuming a transfer whose source was
+// modified since it was interrupted
+var ErrSourceChanged = errors.New("source changed since the transfer was interrupted")
+
+// TransferOptions contains options for a resumable transfer
+type TransferOptions struct {
+	Resume    bool                    // Continue an interrupted transfer from its state file
+	Retries   int                     // Attempts per chunk; defaults to DefaultTransferRetries
+	ChunkSize int64                   // Bytes per checkpoint; defaults to DefaultTransferChunkSize
+	Progress  func(done, total int64) // Called after each chunk is written
+}
+
+// TransferState is checkpointed next to the partial file after every chunk,
+// identifying the source so a resumed transfer never mixes two versions of it
+type TransferState struct {
+	Source    string    `json:"source"`
+	Size      int64     `json:"size"`
+	ModTime   time.Time `json:"mod_time"`
+	ChunkSize int64     `json:"chunk_size"`
+	Done      int64     `json:"done"` // Bytes written and synced to the partial file
+}
+
+// TransferResult contains the result of a transfer
+type TransferResult struct {
+	Size    int64
+	Resumed int64 // Bytes reused from an interrupted transfer
+}
+
+// TransferStatePath returns the state file of a transfer to dest
+func TransferStatePath(dest string) string {
+	return dest + PartialSuffix + TransferStateSuffix
+}
+
+// LoadTransferState reads the state of an interrupted transfer to dest,
+// returning nil when there is none
+func LoadTransferState(dest string) (*TransferState, error) {
+	data, err := os.ReadFile(TransferStatePath(dest))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read transfer state: %w", err)
+	}
+
+	var state TransferState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("invalid transfer state %s: %w", TransferStatePath(dest), err)
+	}
+	return &state, nil
+}
+
+// Transfer copies src to dest in chunks for targets such as network
+// filesystems. Data is written to dest.part, synced and checkpointed in
+// dest.part.json after every chunk, and renamed once complete. Chunks failing
+// with a transient error are retried; with Resume, an interrupted transfer
+// continues from its last checkpoint.
+func Transfer(ctx context.Context, src, dest string, opts TransferOptions) (*TransferResult, error) {
+	if opts.Retries <= 0 {
+		opts.Retries = DefaultTransferRetries
+	}
+	if opts.ChunkSize <= 0 {
+		opts.ChunkSize = DefaultTransferChunkSize
+	}
+
+	source, err := os.Open(src)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", src, err)
+	}
+	defer source.Close()
+
+	info, err := source.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat %s: %w", src, err)
+	}
+
+	state := &TransferState{Source: src, Size: info.Size(), ModTime: info.ModTime().UTC(), ChunkSize: opts.ChunkSize}
+	if opts.Resume {
+		previous, err := LoadTransferState(dest)
+		if err != nil {
+			return nil, err
+		}
+		// A state file left by a transfer of another source is started over
+		if previous != nil && previous.Source == src {
+			if previous.Size != state.Size || !previous.ModTime.Equal(state.ModTime) {
+				return nil, fmt.Errorf("cannot resume transfer to %s: %w", dest, ErrSourceChanged)
+			}
+			state = previous
+		}
+	}
+
+	partPath := dest + PartialSuffix
+	part, err := openPartial(partPath, state.Done)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { part.Close() }()
+
+	result := &TransferResult{Size: state.Size, Resumed: state.Done}
+	buf := make([]byte, state.ChunkSize)
+	for state.Done < state.Size {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		chunk := buf
+		if remaining := state.Size - state.Done; remaining < state.ChunkSize {
+			chunk = buf[:remaining]
+		}
+
+		for attempt := 1; ; attempt++ {
+			err = writeChunk(source, part, chunk, state.Done)
+			if err == nil || attempt >= opts.Retries || !IsTransient(err) {
+				break
+			}
+			if err := sleep(ctx, transferRetryDelay<<(attempt-1)); err != nil {
+				return nil, err
+			}
+			// A stale handle on a network filesystem only recovers by reopening
+			part.Close()
+			if part, err = openPartial(partPath, state.Done); err != nil {
+				return nil, err
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", partPath, err)
+		}
+
+		state.Done += int64(len(chunk))
+		if err := saveTransferState(dest, state); err != nil {
+			return nil, err
+		}
+		if opts.Progress != nil {
+			opts.Progress(state.Done, state.Size)
+		}
+	}
+
+	if err := part.Close(); err != nil {
+		return nil, fmt.Errorf("failed to close %s: %w", partPath, err)
+	}
+	if err := os.Rename(partPath, dest); err != nil {
+		return nil, fmt.Errorf("failed to move transfer into place: %w", err)
+	}
+	if err := os.Remove(TransferStatePath(dest)); err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to remove transfer state: %w", err)
+	}
+	return result, nil
+}
+
+// IsTransient reports whether err is an I/O error worth retrying, such as a
+// timeout or a dropped connection to a network filesystem
+func IsTransient(err error) bool {
+	if errors.Is(err, os.ErrDeadlineExceeded) {
+		return true
+	}
+	var errno syscall.Errno
+	if !errors.As(err, &errno) {
+		return false
+	}
+	switch errno {
+	case syscall.EIO, syscall.ESTALE, syscall.ECONNRESET, syscall.ECONNABORTED, syscall.ENETDOWN, syscall.ENETUNREACH, syscall.EHOSTUNREACH:
+		return true
+	}
+	return errno.Temporary()
+}
+
+// openPartial opens the partial file of a transfer, dropping anything
+// written after the last checkpoint
+func openPartial(path string, done int64) (*os.File, error) {
+	part, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, FileMode())
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	if err := part.Truncate(done); err != nil {
+		part.Close()
+		return nil, fmt.Errorf("failed to truncate %s: %w", path, err)
+	}
+	return part, nil
+}
+
+// writeChunk copies len(chunk) bytes at offset from source to part and syncs
+// them, so a checkpoint never records data that is not on the target
+func writeChunk(source io.ReaderAt, part *os.File, chunk []byte, offset int64) error {
+	n, err := source.ReadAt(chunk, offset)
+	if n < len(chunk) {
+		if err == nil || err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return err
+	}
+	if _, err := part.WriteAt(chunk, offset); err != nil {
+		return err
+	}
+	return part.Sync()
+}
+
+// saveTransferState checkpoints a transfer to dest
+func saveTransferState(dest string, state *TransferState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to encode transfer state: %w", err)
+	}
+	if err := fsutil.WriteFile(TransferStatePath(dest), data, FileMode()); err != nil {
+		return fmt.Errorf("failed to save transfer state: %w", err)
+	}
+	return nil
+}
+
+// sleep waits for d unless ctx is cancelled first
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,147 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransfer_Resume(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "backup.db.zst")
+	dest := filepath.Join(dir, "mnt", "backup.db.zst")
+	require.NoError(t, os.Mkdir(filepath.Dir(dest), 0755))
+	data := bytes.Repeat([]byte("0123456789"), 1000)
+	require.NoError(t, os.WriteFile(src, data, 0600))
+
+	// Interrupt the transfer after the second chunk
+	ctx, cancel := context.WithCancel(context.Background())
+	_, err := Transfer(ctx, src, dest, TransferOptions{
+		ChunkSize: 1024,
+		Progress: func(done, total int64) {
+			if done == 2048 {
+				cancel()
+			}
+		},
+	})
+	require.ErrorIs(t, err, context.Canceled)
+	assert.NoFileExists(t, dest)
+
+	state, err := LoadTransferState(dest)
+	require.NoError(t, err)
+	require.NotNil(t, state)
+	assert.Equal(t, int64(2048), state.Done)
+	assert.Equal(t, int64(len(data)), state.Size)
+
+	// Data written past the last checkpoint is discarded on resume
+	part, err := os.OpenFile(dest+PartialSuffix, os.O_WRONLY|os.O_APPEND, 0)
+	require.NoError(t, err)
+	_, err = part.WriteString("garbage")
+	require.NoError(t, err)
+	require.NoError(t, part.Close())
+
+	var progress []int64
+	result, err := Transfer(context.Background(), src, dest, TransferOptions{
+		Resume:   true,
+		Progress: func(done, total int64) { progress = append(progress, done) },
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2048), result.Resumed)
+	assert.Equal(t, int64(3072), progress[0], "the chunk size of the interrupted transfer is kept")
+
+	got, err := os.ReadFile(dest)
+	require.NoError(t, err)
+	assert.Equal(t, data, got)
+	assert.NoFileExists(t, dest+PartialSuffix)
+	assert.NoFileExists(t, TransferStatePath(dest))
+}
+
+func TestTransfer_WithoutResumeStartsOver(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "backup.db")
+	dest := filepath.Join(dir, "copy.db")
+	require.NoError(t, os.WriteFile(src, []byte("new backup"), 0600))
+	require.NoError(t, os.WriteFile(dest+PartialSuffix, []byte("old"), 0600))
+	require.NoError(t, saveTransferState(dest, &TransferState{Source: src, Size: 10, ChunkSize: 1024, Done: 3}))
+
+	result, err := Transfer(context.Background(), src, dest, TransferOptions{})
+	require.NoError(t, err)
+	assert.Zero(t, result.Resumed)
+
+	got, err := os.ReadFile(dest)
+	require.NoError(t, err)
+	assert.Equal(t, "new backup", string(got))
+}
+
+func TestTransfer_SourceChanged(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "backup.db")
+	dest := filepath.Join(dir, "copy.db")
+	require.NoError(t, os.WriteFile(src, []byte("backup taken later"), 0600))
+	require.NoError(t, saveTransferState(dest, &TransferState{
+		Source:    src,
+		Size:      10,
+		ModTime:   time.Now().Add(-time.Hour).UTC(),
+		ChunkSize: 1024,
+		Done:      5,
+	}))
+
+	_, err := Transfer(context.Background(), src, dest, TransferOptions{Resume: true})
+	assert.ErrorIs(t, err, ErrSourceChanged)
+}
+
+func TestIsTransient(t *testing.T) {
+	for _, tc := range []struct {
+		err       error
+		transient bool
+	}{
+		{&os.PathError{Op: "write", Path: "backup.db", Err: syscall.EIO}, true},
+		{&os.PathError{Op: "write", Path: "backup.db", Err: syscall.ESTALE}, true},
+		{fmt.Errorf("upload: %w", syscall.ETIMEDOUT), true},
+		{os.ErrDeadlineExceeded, true},
+		{&os.PathError{Op: "write", Path: "backup.db", Err: syscall.ENOSPC}, false},
+		{os.ErrPermission, false},
+		{errors.New("checksum mismatch"), false},
+	} {
+		assert.Equal(t, tc.transient, IsTransient(tc.err), "%v", tc.err)
+	}
+}
+
+func TestBackupAndRestore_Resume(t *testing.T) {
+	captureWarnings(t)
+	manager, dbPath, cleanup := setupTestManager(t)
+	defer cleanup()
+	require.NoError(t, manager.Open(context.Background(), dbPath))
+
+	output := filepath.Join(t.TempDir(), "backup.db.zst")
+	backups := NewBackupManager(manager, dbPath)
+	var uploaded int64
+	require.NoError(t, backups.Backup(context.Background(), BackupOptions{
+		OutputPath:  output,
+		Compression: CompressionZstd,
+		Verify:      true,
+		Resume:      true,
+		Progress:    func(done, total int64) { uploaded = done },
+	}))
+	assert.Equal(t, fileSize(t, output), uploaded)
+	assert.Equal(t, DefaultFileMode, modeOf(t, output))
+	assert.NoFileExists(t, TransferStatePath(output))
+
+	restored := filepath.Join(t.TempDir(), "restored.db")
+	require.NoError(t, NewBackupManager(NewManager(), restored).Restore(context.Background(), RestoreOptions{
+		BackupPath: output,
+		Verify:     true,
+		Resume:     true,
+	}))
+	assert.FileExists(t, restored)
+	assert.NoFileExists(t, restored+".restore")
+}
@@ -0,0 +1,211 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Trashable kinds of catalog entries
+const (
+	KindTemplate  = "template"
+	KindBlueprint = "blueprint"
+)
+
+// trashTables maps trashable kinds to their tables
+var trashTables = map[string]string{
+	KindTemplate:  "templates",
+	KindBlueprint: "blueprints",
+}
+
+// TrashedItem is a soft-deleted template or blueprint
+type TrashedItem struct {
+	Kind      string
+	Name      string
+	DeletedAt time.Time
+}
+
+// TrashManager soft-deletes, restores and purges templates and blueprints.
+// Removed entries keep their row with deleted_at set until purged.
+type TrashManager struct {
+	db    *Manager
+	actor string
+	now   func() time.Time
+}
+
+// NewTrashManager creates a new trash manager
+func NewTrashManager(manager *Manager) *TrashManager {
+	return &TrashManager{
+		db:    manager,
+		actor: currentActor(),
+		now:   time.Now,
+	}
+}
+
+// Delete moves an entry to the trash
+func (t *TrashManager) Delete(ctx context.Context, kind, name string) error {
+	table, err := trashTable(kind)
+	if err != nil {
+		return err
+	}
+
+	return t.db.WithTx(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		query := fmt.Sprintf(`UPDATE %s SET deleted_at = ? WHERE name = ? AND deleted_at IS NULL`, table)
+		err := expectOneRow(tx.ExecContext(ctx, query, FormatTimestamp(t.now()), name))
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%s '%s' not found", kind, name)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to delete %s '%s': %w", kind, name, err)
+		}
+		return t.audit(ctx, tx, "delete", kind, name)
+	})
+}
+
+// Restore moves an entry out of the trash
+func (t *TrashManager) Restore(ctx context.Context, kind, name string) error {
+	table, err := trashTable(kind)
+	if err != nil {
+		return err
+	}
+
+	return t.db.WithTx(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		query := fmt.Sprintf(`UPDATE %s SET deleted_at = NULL WHERE name = ? AND deleted_at IS NOT NULL`, table)
+		err := expectOneRow(tx.ExecContext(ctx, query, name))
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%s '%s' is not in the trash", kind, name)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to restore %s '%s': %w", kind, name, err)
+		}
+		return t.audit(ctx, tx, "restore", kind, name)
+	})
+}
+
+// Purge permanently deletes a trashed entry
+func (t *TrashManager) Purge(ctx context.Context, kind, name string) error {
+	table, err := trashTable(kind)
+	if err != nil {
+		return err
+	}
+
+	return t.db.WithTx(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		query := fmt.Sprintf(`DELETE FROM %s WHERE name = ? AND deleted_at IS NOT NULL`, table)
+		err := expectOneRow(tx.ExecContext(ctx, query, name))
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%s '%s' is not in the trash", kind, name)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to purge %s '%s': %w", kind, name, err)
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM entry_tags WHERE kind = ? AND entry_name = ?`, kind, name); err != nil {
+			return fmt.Errorf("failed to remove tags of %s '%s': %w", kind, name, err)
+		}
+		if err := deleteUnusedTags(ctx, tx); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM annotations WHERE kind = ? AND entry_name = ?`, kind, name); err != nil {
+			return fmt.Errorf("failed to remove annotation of %s '%s': %w", kind, name, err)
+		}
+		return t.audit(ctx, tx, "purge", kind, name)
+	})
+}
+
+// PurgeOlderThan permanently deletes entries trashed more than age ago and
+// returns the purged entries. An age of zero empties the trash.
+func (t *TrashManager) PurgeOlderThan(ctx context.Context, age time.Duration) ([]TrashedItem, error) {
+	items, err := t.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cutoff := t.now().Add(-age)
+	var purged []TrashedItem
+	for _, item := range items {
+		if item.DeletedAt.After(cutoff) {
+			continue
+		}
+		if err := t.Purge(ctx, item.Kind, item.Name); err != nil {
+			return purged, err
+		}
+		purged = append(purged, item)
+	}
+	return purged, nil
+}
+
+// List returns all trashed entries, most recently deleted first
+func (t *TrashManager) List(ctx context.Context) ([]TrashedItem, error) {
+	query := `SELECT 'template', name, deleted_at FROM templates WHERE deleted_at IS NOT NULL
+		UNION ALL
+		SELECT 'blueprint', name, deleted_at FROM blueprints WHERE deleted_at IS NOT NULL
+		ORDER BY 3 DESC, 2`
+	rows, err := t.db.GetDB().QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query trash: %w", err)
+	}
+	defer rows.Close()
+
+	var items []TrashedItem
+	for rows.Next() {
+		var item TrashedItem
+		var deletedAt string
+		if err := rows.Scan(&item.Kind, &item.Name, &deletedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan trash row: %w", err)
+		}
+		if item.DeletedAt, err = ParseTimestamp(deletedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// audit records a trash operation in the audits table
+func (t *TrashManager) audit(ctx context.Context, tx *sql.Tx, action, kind, name string) error {
+	details, err := json.Marshal(map[string]string{"kind": kind, "name": name})
+	if err != nil {
+		return err
+	}
+	query := `INSERT INTO audits (actor, action, entity, details_json, created_at) VALUES (?, ?, ?, ?, ?)`
+	if _, err := tx.ExecContext(ctx, query, t.actor, action, kind+":"+name, string(details), FormatTimestamp(t.now())); err != nil {
+		return fmt.Errorf("failed to record audit entry: %w", err)
+	}
+	return nil
+}
+
+func trashTable(kind string) (string, error) {
+	table, ok := trashTables[kind]
+	if !ok {
+		return "", fmt.Errorf("unsupported kind '%s' (expected %s or %s)", kind, KindTemplate, KindBlueprint)
+	}
+	return table, nil
+}
+
+// expectOneRow returns an error unless the statement affected exactly one row
+func expectOneRow(result sql.Result, err error) error {
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected != 1 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// currentActor identifies the local user for audit entries
+func currentActor() string {
+	for _, key := range []string{"USER", "USERNAME"} {
+		if user := os.Getenv(key); user != "" {
+			return user
+		}
+	}
+	return "unknown"
+}
@@ -0,0 +1,112 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrashManager_DeleteRestorePurge(t *testing.T) {
+	manager, dbPath, cleanup := setupTestManager(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	require.NoError(t, manager.Open(ctx, dbPath))
+	defer manager.Close()
+
+	_, err := manager.GetDB().ExecContext(ctx,
+		`INSERT INTO templates (name, kind, content) VALUES ('api', 'project', 'x'), ('cli', 'project', 'x')`)
+	require.NoError(t, err)
+	_, err = manager.GetDB().ExecContext(ctx,
+		`INSERT INTO blueprints (name, stack, config_json) VALUES ('web-stack', 'web', '{}')`)
+	require.NoError(t, err)
+
+	trash := NewTrashManager(manager)
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	trash.now = func() time.Time { return now }
+
+	require.NoError(t, trash.Delete(ctx, KindTemplate, "api"))
+	now = now.Add(time.Hour)
+	require.NoError(t, trash.Delete(ctx, KindBlueprint, "web-stack"))
+
+	// Deleting twice or deleting unknown entries fails
+	assert.Error(t, trash.Delete(ctx, KindTemplate, "api"))
+	assert.Error(t, trash.Delete(ctx, KindTemplate, "missing"))
+	assert.Error(t, trash.Delete(ctx, "plugin", "api"))
+
+	items, err := trash.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	assert.Equal(t, TrashedItem{Kind: KindBlueprint, Name: "web-stack", DeletedAt: now}, items[0])
+	assert.Equal(t, KindTemplate, items[1].Kind)
+	assert.Equal(t, "api", items[1].Name)
+
+	// Restore brings the template back
+	require.NoError(t, trash.Restore(ctx, KindTemplate, "api"))
+	assert.Error(t, trash.Restore(ctx, KindTemplate, "cli"))
+
+	var deletedAt *string
+	require.NoError(t, manager.GetDB().QueryRowContext(ctx,
+		`SELECT deleted_at FROM templates WHERE name = 'api'`).Scan(&deletedAt))
+	assert.Nil(t, deletedAt)
+
+	// Purge only removes trashed entries
+	assert.Error(t, trash.Purge(ctx, KindTemplate, "cli"))
+	require.NoError(t, trash.Purge(ctx, KindBlueprint, "web-stack"))
+
+	var count int
+	require.NoError(t, manager.GetDB().QueryRowContext(ctx, `SELECT COUNT(*) FROM blueprints`).Scan(&count))
+	assert.Equal(t, 0, count)
+
+	// Every operation is recorded in the audit trail
+	rows, err := manager.GetDB().QueryContext(ctx, `SELECT action, entity FROM audits ORDER BY id`)
+	require.NoError(t, err)
+	defer rows.Close()
+
+	var entries []string
+	for rows.Next() {
+		var action, entity string
+		require.NoError(t, rows.Scan(&action, &entity))
+		entries = append(entries, action+" "+entity)
+	}
+	assert.Equal(t, []string{
+		"delete template:api",
+		"delete blueprint:web-stack",
+		"restore template:api",
+		"purge blueprint:web-stack",
+	}, entries)
+}
+
+func TestTrashManager_PurgeOlderThan(t *testing.T) {
+	manager, dbPath, cleanup := setupTestManager(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	require.NoError(t, manager.Open(ctx, dbPath))
+	defer manager.Close()
+
+	_, err := manager.GetDB().ExecContext(ctx,
+		`INSERT INTO templates (name, kind, content) VALUES ('old', 'project', 'x'), ('recent', 'project', 'x')`)
+	require.NoError(t, err)
+
+	trash := NewTrashManager(manager)
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	trash.now = func() time.Time { return now }
+
+	require.NoError(t, trash.Delete(ctx, KindTemplate, "old"))
+	now = now.Add(30 * 24 * time.Hour)
+	require.NoError(t, trash.Delete(ctx, KindTemplate, "recent"))
+
+	purged, err := trash.PurgeOlderThan(ctx, 7*24*time.Hour)
+	require.NoError(t, err)
+	require.Len(t, purged, 1)
+	assert.Equal(t, "old", purged[0].Name)
+
+	items, err := trash.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, "recent", items[0].Name)
+}
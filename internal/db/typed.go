@@ -0,0 +1,186 @@
+package db
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// ColumnType records how a column's values are encoded in a JSON export.
+// JSON alone cannot tell an int64 from a float or a blob from text, so typed
+// exports carry a type for every column and import decodes values by it.
+type ColumnType string
+
+const (
+	ColumnInteger ColumnType = "integer" // JSON number, decoded as an exact int64
+	ColumnReal    ColumnType = "real"    // JSON number
+	ColumnText    ColumnType = "text"    // JSON string
+	ColumnBlob    ColumnType = "blob"    // standard base64 string
+	ColumnTime    ColumnType = "time"    // RFC 3339 string with nanoseconds
+	ColumnBoolean ColumnType = "boolean" // JSON boolean
+	ColumnNull    ColumnType = "null"    // every value is NULL
+	ColumnMixed   ColumnType = "mixed"   // every value is a TypedValue
+)
+
+// TypedValue carries its own type; used for columns whose values do not all
+// share a storage class, which SQLite allows
+type TypedValue struct {
+	Type  ColumnType  `json:"type"`
+	Value interface{} `json:"value"`
+}
+
+// valueType returns the column type of a value scanned from SQLite
+func valueType(v interface{}) ColumnType {
+	switch v.(type) {
+	case nil:
+		return ColumnNull
+	case int64:
+		return ColumnInteger
+	case float64:
+		return ColumnReal
+	case string:
+		return ColumnText
+	case []byte:
+		return ColumnBlob
+	case time.Time:
+		return ColumnTime
+	case bool:
+		return ColumnBoolean
+	default:
+		return ColumnMixed
+	}
+}
+
+// inferColumnTypes picks one type per column from the scanned values. NULLs
+// fit any type; columns with differing non-NULL types become ColumnMixed.
+func inferColumnTypes(columns []string, values [][]interface{}) map[string]ColumnType {
+	types := make(map[string]ColumnType, len(columns))
+	for i, col := range columns {
+		colType := ColumnNull
+		for _, row := range values {
+			switch t := valueType(row[i]); {
+			case t == ColumnNull || t == colType:
+			case colType == ColumnNull:
+				colType = t
+			default:
+				colType = ColumnMixed
+			}
+			if colType == ColumnMixed {
+				break
+			}
+		}
+		types[col] = colType
+	}
+	return types
+}
+
+// encodeTypedValue converts a scanned value into its JSON form for colType
+func encodeTypedValue(colType ColumnType, v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	switch colType {
+	case ColumnMixed:
+		t := valueType(v)
+		return TypedValue{Type: t, Value: encodeTypedValue(t, v)}
+	case ColumnBlob:
+		return base64.StdEncoding.EncodeToString(v.([]byte))
+	case ColumnTime:
+		return v.(time.Time).Format(time.RFC3339Nano)
+	default:
+		return v
+	}
+}
+
+// decodeTypedValue reconstructs the value of a column of colType from JSON
+// decoded with UseNumber
+func decodeTypedValue(colType ColumnType, raw interface{}) (interface{}, error) {
+	if raw == nil {
+		return nil, nil
+	}
+
+	switch colType {
+	case ColumnInteger:
+		if n, ok := raw.(json.Number); ok {
+			return n.Int64()
+		}
+	case ColumnReal:
+		if n, ok := raw.(json.Number); ok {
+			return n.Float64()
+		}
+	case ColumnText:
+		if s, ok := raw.(string); ok {
+			return s, nil
+		}
+	case ColumnBlob:
+		if s, ok := raw.(string); ok {
+			return base64.StdEncoding.DecodeString(s)
+		}
+	case ColumnTime:
+		// Stored as text, which is how the value was held before export
+		if s, ok := raw.(string); ok {
+			if _, err := time.Parse(time.RFC3339Nano, s); err != nil {
+				return nil, err
+			}
+			return s, nil
+		}
+	case ColumnBoolean:
+		if b, ok := raw.(bool); ok {
+			return b, nil
+		}
+	case ColumnMixed:
+		if m, ok := raw.(map[string]interface{}); ok {
+			t, _ := m["type"].(string)
+			if ColumnType(t) == ColumnMixed {
+				return nil, fmt.Errorf("nested mixed value")
+			}
+			return decodeTypedValue(ColumnType(t), m["value"])
+		}
+	case ColumnNull:
+	default:
+		return nil, fmt.Errorf("unknown column type %q", colType)
+	}
+	return nil, fmt.Errorf("expected %s value, got %T", colType, raw)
+}
+
+// decodeUntypedValue handles exports written without column types; numbers
+// are restored as int64 whenever they are integral
+func decodeUntypedValue(raw interface{}) interface{} {
+	if n, ok := raw.(json.Number); ok {
+		if i, err := n.Int64(); err == nil {
+			return i
+		}
+		if f, err := n.Float64(); err == nil {
+			return f
+		}
+		return n.String()
+	}
+	return raw
+}
+
+// decodeTableRows reconstructs the values of rows exported with types. A nil
+// types map means the export predates typed encoding.
+func decodeTableRows(rows []TableRow, types map[string]ColumnType) ([]TableRow, error) {
+	decoded := make([]TableRow, len(rows))
+	for i, row := range rows {
+		out := make(TableRow, len(row))
+		for col, raw := range row {
+			if types == nil {
+				out[col] = decodeUntypedValue(raw)
+				continue
+			}
+			colType, ok := types[col]
+			if !ok {
+				return nil, fmt.Errorf("row %d: column %s has no type", i+1, col)
+			}
+			value, err := decodeTypedValue(colType, raw)
+			if err != nil {
+				return nil, fmt.Errorf("row %d: column %s: %w", i+1, col, err)
+			}
+			out[col] = value
+		}
+		decoded[i] = out
+	}
+	return decoded, nil
+}
@@ -0,0 +1,172 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const createTypedTable = `CREATE TABLE typed (
+    id      INTEGER PRIMARY KEY,
+    big     INTEGER,
+    ratio   REAL,
+    label   TEXT,
+    payload BLOB,
+    seen_at TIMESTAMP,
+    active  BOOLEAN,
+    loose
+)`
+
+type typedRow struct {
+	id                   int64
+	big                  interface{}
+	ratio                interface{}
+	label                interface{}
+	payload              interface{}
+	seenAt               interface{}
+	active               interface{}
+	loose                interface{}
+	bigType, payloadType string
+	looseType            string
+}
+
+func insertTypedRows(t *testing.T, ctx context.Context, manager *Manager) {
+	_, err := manager.GetDB().ExecContext(ctx, createTypedTable)
+	require.NoError(t, err)
+
+	rows := [][]interface{}{
+		{int64(1), int64(math.MaxInt64), 0.1, "text", []byte{0x00, 0xff, 0xfe, '"'}, "2024-03-01T12:00:00.123456789Z", true, int64(7)},
+		{int64(2), int64(math.MinInt64), 3.0, "", []byte{}, "2024-03-01T12:00:00Z", false, "seven"},
+		{int64(3), nil, nil, nil, nil, nil, nil, []byte("7")},
+	}
+	for _, row := range rows {
+		_, err := manager.GetDB().ExecContext(ctx,
+			`INSERT INTO typed (id, big, ratio, label, payload, seen_at, active, loose) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`, row...)
+		require.NoError(t, err)
+	}
+}
+
+func readTypedRows(t *testing.T, ctx context.Context, manager *Manager) []typedRow {
+	rows, err := manager.GetDB().QueryContext(ctx, `SELECT id, big, ratio, label, payload, seen_at, active, loose,
+		typeof(big), typeof(payload), typeof(loose) FROM typed ORDER BY id`)
+	require.NoError(t, err)
+	defer rows.Close()
+
+	var result []typedRow
+	for rows.Next() {
+		var r typedRow
+		require.NoError(t, rows.Scan(&r.id, &r.big, &r.ratio, &r.label, &r.payload, &r.seenAt, &r.active, &r.loose,
+			&r.bigType, &r.payloadType, &r.looseType))
+		result = append(result, r)
+	}
+	require.NoError(t, rows.Err())
+	return result
+}
+
+func TestExportManager_JSONRoundTripPreservesTypes(t *testing.T) {
+	ctx := context.Background()
+
+	source, sourcePath, cleanupSource := setupTestManager(t)
+	defer cleanupSource()
+	require.NoError(t, source.Open(ctx, sourcePath))
+	insertTypedRows(t, ctx, source)
+
+	outputPath := filepath.Join(t.TempDir(), "typed.json")
+	require.NoError(t, NewExportManager(source).Export(ctx, ExportOptions{
+		OutputPath: outputPath, Format: FormatJSON, Tables: []string{"typed"}, IncludeData: true,
+	}))
+
+	target, targetPath, cleanupTarget := setupTestManager(t)
+	defer cleanupTarget()
+	require.NoError(t, target.Open(ctx, targetPath))
+	_, err := target.GetDB().ExecContext(ctx, createTypedTable)
+	require.NoError(t, err)
+
+	require.NoError(t, NewExportManager(target).Import(ctx, ImportOptions{InputPath: outputPath, Format: FormatJSON}))
+
+	expected := readTypedRows(t, ctx, source)
+	actual := readTypedRows(t, ctx, target)
+	require.Len(t, actual, 3)
+	assert.Equal(t, expected, actual)
+	assert.Equal(t, int64(math.MaxInt64), actual[0].big)
+	assert.Equal(t, []byte{0x00, 0xff, 0xfe, '"'}, actual[0].payload)
+	assert.Equal(t, "blob", actual[1].payloadType, "empty blobs stay blobs")
+	assert.Equal(t, []string{"integer", "text", "blob"},
+		[]string{actual[0].looseType, actual[1].looseType, actual[2].looseType})
+}
+
+func TestInferColumnTypes(t *testing.T) {
+	columns := []string{"id", "name", "empty", "loose"}
+	values := [][]interface{}{
+		{int64(1), "a", nil, int64(1)},
+		{int64(2), nil, nil, "one"},
+	}
+
+	assert.Equal(t, map[string]ColumnType{
+		"id":    ColumnInteger,
+		"name":  ColumnText,
+		"empty": ColumnNull,
+		"loose": ColumnMixed,
+	}, inferColumnTypes(columns, values))
+}
+
+func TestTypedValueRoundTrip(t *testing.T) {
+	seen := time.Date(2024, 3, 1, 12, 0, 0, 5, time.FixedZone("CET", 3600))
+
+	tests := []struct {
+		name     string
+		colType  ColumnType
+		value    interface{}
+		expected interface{}
+	}{
+		{"max int64", ColumnInteger, int64(math.MaxInt64), int64(math.MaxInt64)},
+		{"real", ColumnReal, 0.1, 0.1},
+		{"text", ColumnText, "héllo", "héllo"},
+		{"blob", ColumnBlob, []byte{0, 1, 2, 0xff}, []byte{0, 1, 2, 0xff}},
+		{"time keeps nanoseconds and offset", ColumnTime, seen, "2024-03-01T12:00:00.000000005+01:00"},
+		{"boolean", ColumnBoolean, true, true},
+		{"null", ColumnNull, nil, nil},
+		{"mixed blob", ColumnMixed, []byte("x"), []byte("x")},
+		{"mixed integer", ColumnMixed, int64(-3), int64(-3)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(encodeTypedValue(tt.colType, tt.value))
+			require.NoError(t, err)
+
+			decoder := json.NewDecoder(bytes.NewReader(data))
+			decoder.UseNumber()
+			var raw interface{}
+			require.NoError(t, decoder.Decode(&raw))
+
+			decoded, err := decodeTypedValue(tt.colType, raw)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, decoded)
+		})
+	}
+}
+
+func TestDecodeTypedValueRejectsMismatches(t *testing.T) {
+	_, err := decodeTypedValue(ColumnInteger, "12")
+	assert.ErrorContains(t, err, "expected integer value")
+
+	_, err = decodeTypedValue(ColumnBlob, "not base64!")
+	assert.Error(t, err)
+
+	_, err = decodeTypedValue(ColumnType("uuid"), "x")
+	assert.ErrorContains(t, err, "unknown column type")
+}
+
+func TestDecodeTableRowsWithoutTypes(t *testing.T) {
+	rows, err := decodeTableRows([]TableRow{{"id": json.Number("42"), "ratio": json.Number("1.5"), "name": "x"}}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, TableRow{"id": int64(42), "ratio": 1.5, "name": "x"}, rows[0])
+}
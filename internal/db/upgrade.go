@@ -0,0 +1,252 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// SchemaVersion is the version of the schema Open brings databases to,
+// recorded in their user_version. Databases of gogo releases from before it
+// was recorded have version 0. Increment it whenever older databases need
+// more than the CREATE ... IF NOT EXISTS statements of schema.go.
+const SchemaVersion = 1
+
+// LegacyDatabaseError reports a database created by an older gogo that was
+// not upgraded because the upgrade was declined
+type LegacyDatabaseError struct {
+	Path    string
+	Version int
+}
+
+func (e *LegacyDatabaseError) Error() string {
+	return fmt.Sprintf("database %s was created by an older gogo (schema version %d, current %d); run the command again with --yes to back it up and upgrade it",
+		e.Path, e.Version, SchemaVersion)
+}
+
+// confirmUpgrade decides whether Open upgrades a database created by an
+// older gogo; nil upgrades without asking
+var confirmUpgrade func(path string, version int) bool
+
+// SetUpgradeConfirm sets the function Open asks before upgrading the
+// database at path from schema version to SchemaVersion. When it declines,
+// Open fails with *LegacyDatabaseError; nil upgrades without asking.
+func SetUpgradeConfirm(confirm func(path string, version int) bool) {
+	confirmUpgrade = confirm
+}
+
+// schemaVersion returns the recorded schema version of the database, and
+// whether it holds tables, telling a legacy database from a new one
+func (m *Manager) schemaVersion(ctx context.Context) (version int, populated bool, err error) {
+	if err := m.db.QueryRowContext(ctx, "PRAGMA user_version").Scan(&version); err != nil {
+		return 0, false, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	var tables int
+	if err := m.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%'`).Scan(&tables); err != nil {
+		return 0, false, fmt.Errorf("failed to inspect database: %w", err)
+	}
+	return version, tables > 0, nil
+}
+
+// upgrade brings the database to SchemaVersion. A database created by an
+// older gogo is backed up next to it first, once confirmUpgrade agrees.
+func (m *Manager) upgrade(ctx context.Context) error {
+	version, populated, err := m.schemaVersion(ctx)
+	if err != nil {
+		return err
+	}
+	if version > SchemaVersion {
+		return fmt.Errorf("database %s has schema version %d, newer than this gogo supports (%d); upgrade gogo", m.path, version, SchemaVersion)
+	}
+
+	legacy := populated && version < SchemaVersion
+	var backup string
+	if legacy {
+		if confirmUpgrade != nil && !confirmUpgrade(m.path, version) {
+			return &LegacyDatabaseError{Path: m.path, Version: version}
+		}
+		if backup, err = m.backupLegacy(ctx, version); err != nil {
+			return err
+		}
+		if err := m.upgradeTables(ctx); err != nil {
+			return fmt.Errorf("failed to upgrade database from schema version %d (a backup is at %s): %w", version, backup, err)
+		}
+	}
+
+	if err := m.migrate(ctx); err != nil {
+		if legacy {
+			return fmt.Errorf("failed to upgrade database from schema version %d (a backup is at %s): %w", version, backup, err)
+		}
+		return fmt.Errorf("failed to run migrations: %w", err)
+	}
+	if version < SchemaVersion {
+		if _, err := m.db.ExecContext(ctx, fmt.Sprintf("PRAGMA user_version = %d", SchemaVersion)); err != nil {
+			return fmt.Errorf("failed to record schema version: %w", err)
+		}
+	}
+	if legacy {
+		color.Yellow("Upgraded %s from schema version %d to %d; the old database is backed up at %s", m.path, version, SchemaVersion, backup)
+	}
+	return nil
+}
+
+// backupLegacy copies the database, as of before its upgrade from version,
+// to <path>.v<version>.bak, or a timestamped name when that exists
+func (m *Manager) backupLegacy(ctx context.Context, version int) (string, error) {
+	backup := fmt.Sprintf("%s.v%d.bak", m.path, version)
+	if _, err := os.Stat(backup); err == nil {
+		backup = fmt.Sprintf("%s.v%d-%s.bak", m.path, version, time.Now().UTC().Format("20060102T150405Z"))
+	}
+	// VACUUM INTO writes a consistent copy, including pages still in the WAL
+	if _, err := m.db.ExecContext(ctx, "VACUUM INTO ?", backup); err != nil {
+		return "", fmt.Errorf("failed to back up database before upgrading it: %w", err)
+	}
+	if err := os.Chmod(backup, FileMode()); err != nil {
+		return "", fmt.Errorf("failed to back up database before upgrading it: %w", err)
+	}
+	return backup, nil
+}
+
+// upgradeTables adds the columns of the current schema that tables created
+// by older versions lack, so that the indexes and queries on them work. The
+// current schema is created in memory and compared column by column.
+func (m *Manager) upgradeTables(ctx context.Context) error {
+	reference, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		return fmt.Errorf("failed to open reference schema: %w", err)
+	}
+	defer reference.Close()
+	// Each connection would have a database of its own
+	reference.SetMaxOpenConns(1)
+	for _, statement := range schemaStatements {
+		if _, err := reference.ExecContext(ctx, statement); err != nil {
+			return fmt.Errorf("failed to create reference schema: %w", err)
+		}
+	}
+
+	rows, err := reference.QueryContext(ctx,
+		`SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' ORDER BY name`)
+	if err != nil {
+		return fmt.Errorf("failed to read reference schema: %w", err)
+	}
+	var tables []string
+	for rows.Next() {
+		var table string
+		if err := rows.Scan(&table); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to read reference schema: %w", err)
+		}
+		tables = append(tables, table)
+	}
+	rows.Close()
+
+	for _, table := range tables {
+		existing, err := tableColumns(ctx, m.db, table)
+		if err != nil {
+			return err
+		}
+		if len(existing) == 0 {
+			continue // created as it is now by migrate
+		}
+		wanted, err := tableColumns(ctx, reference, table)
+		if err != nil {
+			return err
+		}
+		for _, column := range wanted {
+			if !hasColumn(existing, column.name) {
+				if err := m.addColumn(ctx, table, column); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// columnInfo is a row of PRAGMA table_info
+type columnInfo struct {
+	name, kind string
+	notNull    bool
+	dflt       sql.NullString
+}
+
+func tableColumns(ctx context.Context, conn *sql.DB, table string) ([]columnInfo, error) {
+	rows, err := conn.QueryContext(ctx, fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect table %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	var columns []columnInfo
+	for rows.Next() {
+		var (
+			column  columnInfo
+			cid, pk int
+			notNull int
+		)
+		if err := rows.Scan(&cid, &column.name, &column.kind, &notNull, &column.dflt, &pk); err != nil {
+			return nil, fmt.Errorf("failed to inspect table %s: %w", table, err)
+		}
+		column.notNull = notNull != 0
+		columns = append(columns, column)
+	}
+	return columns, rows.Err()
+}
+
+// constantDefault reports whether a column default is a literal, which
+// ALTER TABLE ... ADD COLUMN accepts
+func constantDefault(value string) bool {
+	switch strings.ToUpper(value) {
+	case "NULL", "TRUE", "FALSE":
+		return true
+	}
+	return strings.HasPrefix(value, "'") || strings.TrimLeft(value, "+-0123456789.") == ""
+}
+
+func hasColumn(columns []columnInfo, name string) bool {
+	for _, column := range columns {
+		if strings.EqualFold(column.name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// addColumn adds column to table. SQLite only adds columns with constant
+// defaults, so columns defaulting to an expression, such as timestamps, are
+// added without one and filled in with it.
+func (m *Manager) addColumn(ctx context.Context, table string, column columnInfo) error {
+	definition := column.kind
+	expression := column.dflt.Valid && !constantDefault(column.dflt.String)
+	switch {
+	case expression:
+	case column.dflt.Valid:
+		definition += " DEFAULT " + column.dflt.String
+		if column.notNull {
+			definition += " NOT NULL"
+		}
+	case column.notNull:
+		// Existing rows need a value
+		zero := "''"
+		if strings.Contains(strings.ToUpper(column.kind), "INT") {
+			zero = "0"
+		}
+		definition += " NOT NULL DEFAULT " + zero
+	}
+
+	if _, err := m.db.ExecContext(ctx, fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column.name, definition)); err != nil {
+		return fmt.Errorf("failed to add %s.%s: %w", table, column.name, err)
+	}
+	if expression {
+		if _, err := m.db.ExecContext(ctx, fmt.Sprintf("UPDATE %s SET %s = %s", table, column.name, column.dflt.String)); err != nil {
+			return fmt.Errorf("failed to fill in %s.%s: %w", table, column.name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createLegacyDatabase writes a database as an early gogo release left it:
+// no schema version and tables lacking columns added since
+func createLegacyDatabase(t *testing.T, path string, version int) {
+	t.Helper()
+	conn, err := sql.Open("sqlite3", path)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	for _, statement := range []string{
+		`CREATE TABLE templates (id INTEGER PRIMARY KEY, name TEXT NOT NULL UNIQUE, content BLOB NOT NULL)`,
+		`CREATE TABLE hooks (id INTEGER PRIMARY KEY, name TEXT NOT NULL, event TEXT NOT NULL, script TEXT NOT NULL)`,
+		`INSERT INTO templates (name, content) VALUES ('legacy-api', 'package main')`,
+		`INSERT INTO hooks (name, event, script) VALUES ('notify', 'project.generated', 'echo done')`,
+	} {
+		_, err := conn.Exec(statement)
+		require.NoError(t, err)
+	}
+	_, err = conn.Exec(fmt.Sprintf("PRAGMA user_version = %d", version))
+	require.NoError(t, err)
+}
+
+func userVersion(t *testing.T, conn *sql.DB) int {
+	t.Helper()
+	var version int
+	require.NoError(t, conn.QueryRow("PRAGMA user_version").Scan(&version))
+	return version
+}
+
+func TestManager_UpgradeLegacyDatabase(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "gogo.db")
+	createLegacyDatabase(t, path, 0)
+
+	var asked []int
+	SetUpgradeConfirm(func(p string, version int) bool {
+		assert.Equal(t, path, p)
+		asked = append(asked, version)
+		return true
+	})
+	t.Cleanup(func() { SetUpgradeConfirm(nil) })
+
+	manager := NewManager()
+	require.NoError(t, manager.Open(ctx, path))
+	defer manager.Close()
+	assert.Equal(t, []int{0}, asked)
+	assert.Equal(t, SchemaVersion, userVersion(t, manager.GetDB()))
+
+	// The rows survive and the columns added since are filled in
+	var kind, metadata, createdAt string
+	require.NoError(t, manager.GetDB().QueryRow(
+		`SELECT kind, metadata_json, created_at FROM templates WHERE name = 'legacy-api'`).Scan(&kind, &metadata, &createdAt))
+	assert.Equal(t, "project", kind)
+	assert.Equal(t, "{}", metadata)
+	assert.NotEmpty(t, createdAt)
+	var language string
+	var enabled int
+	require.NoError(t, manager.GetDB().QueryRow(`SELECT language, enabled FROM hooks`).Scan(&language, &enabled))
+	assert.Equal(t, "shell", language)
+	assert.Equal(t, 1, enabled)
+
+	// The backup holds the database as it was
+	backup, err := sql.Open("sqlite3", path+".v0.bak")
+	require.NoError(t, err)
+	defer backup.Close()
+	columns, err := tableColumns(ctx, backup, "templates")
+	require.NoError(t, err)
+	assert.False(t, hasColumn(columns, "kind"))
+
+	// Upgraded databases are not asked about again
+	require.NoError(t, manager.Close())
+	require.NoError(t, manager.Open(ctx, path))
+	assert.Len(t, asked, 1)
+}
+
+func TestManager_UpgradeDeclined(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gogo.db")
+	createLegacyDatabase(t, path, 0)
+	SetUpgradeConfirm(func(string, int) bool { return false })
+	t.Cleanup(func() { SetUpgradeConfirm(nil) })
+
+	err := NewManager().Open(context.Background(), path)
+	var legacy *LegacyDatabaseError
+	require.ErrorAs(t, err, &legacy)
+	assert.Equal(t, 0, legacy.Version)
+	assert.ErrorContains(t, err, "--yes")
+	assert.NoFileExists(t, path+".v0.bak")
+}
+
+func TestManager_NewerSchema(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gogo.db")
+	createLegacyDatabase(t, path, SchemaVersion+1)
+
+	err := NewManager().Open(context.Background(), path)
+	assert.ErrorContains(t, err, "newer than this gogo supports")
+}
+
+func TestManager_NewDatabaseNotBackedUp(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gogo.db")
+	SetUpgradeConfirm(func(string, int) bool {
+		t.Error("a new database is not an upgrade")
+		return false
+	})
+	t.Cleanup(func() { SetUpgradeConfirm(nil) })
+
+	manager := NewManager()
+	require.NoError(t, manager.Open(context.Background(), path))
+	defer manager.Close()
+	assert.Equal(t, SchemaVersion, userVersion(t, manager.GetDB()))
+	assert.NoFileExists(t, path+".v0.bak")
+}
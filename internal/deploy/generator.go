@@ -0,0 +1,714 @@
+// Package deploy generates deployment files for long-running projects: a
+// systemd unit and a service wrapper built on github.com/kardianos/service
+// that installs the project as a Windows service (or a systemd/launchd
+// service on other systems), plus cloud-init user data or an Ansible role
+// that provision plain VMs with the systemd unit. For clusters it generates
+// Kubernetes manifests or a Helm chart running the project's container image.
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/user/gogo/internal/templates"
+)
+
+// Deployment targets selected by a blueprint's deployment.target
+const (
+	TargetSystemd    = "systemd"
+	TargetWindows    = "windows-service"
+	TargetKubernetes = "kubernetes"
+	TargetHelm       = "helm"
+)
+
+// Targets lists the supported deployment targets
+var Targets = []string{TargetSystemd, TargetWindows, TargetKubernetes, TargetHelm}
+
+// HostTargets are the targets that run the binary under a host's service
+// manager rather than a container in a cluster
+var HostTargets = []string{TargetSystemd, TargetWindows}
+
+// DefaultHealthPath is the endpoint cluster probes check unless a blueprint
+// sets deployment.health_path
+const DefaultHealthPath = "/health"
+
+// VM provisioners selected by a blueprint's deployment.provision
+const (
+	ProvisionCloudInit = "cloud-init"
+	ProvisionAnsible   = "ansible"
+)
+
+// Provisioners lists the supported VM provisioners
+var Provisioners = []string{ProvisionCloudInit, ProvisionAnsible}
+
+// envNamePattern matches environment variable names
+var envNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Config represents deployment file generation options
+type Config struct {
+	ProjectName  string
+	ModuleName   string
+	Description  string
+	Targets      []string
+	Provisioners []string
+	Env          map[string]string // Written to /etc/<project>/environment by provisioners, or a ConfigMap in clusters
+	BinaryURL    string            // Release binary downloaded by cloud-init
+
+	// Cluster targets
+	Image       string // Container image, ghcr.io/<owner>/<repo> for GitHub modules when empty
+	Port        int    // Container port, 8080 when zero
+	Replicas    int    // Pods to run and the autoscaler's minimum, 2 when zero
+	MaxReplicas int    // Autoscaler maximum, 5 when zero
+	CPUTarget   int    // Average CPU utilization percentage the autoscaler keeps, 80 when zero
+	HealthPath  string // HTTP readiness and liveness probe path; no probes when empty
+}
+
+// Generator handles deployment file generation
+type Generator struct {
+	templateEngine templates.TemplateRenderer
+}
+
+// NewGenerator creates a new deployment file generator
+func NewGenerator() *Generator {
+	return &Generator{
+		templateEngine: templates.NewEngine(),
+	}
+}
+
+// ParseConfig reads the deployment section of a blueprint: target,
+// provision, env and binary_url, and for cluster targets image, port,
+// replicas, max_replicas, cpu_target and health_path. Project details are
+// left for the caller.
+func ParseConfig(deployment map[string]any) (Config, error) {
+	config := Config{HealthPath: DefaultHealthPath}
+	var err error
+	if config.Targets, err = ParseTargets(deployment["target"]); err != nil {
+		return Config{}, err
+	}
+	if config.Provisioners, err = ParseProvisioners(deployment["provision"]); err != nil {
+		return Config{}, err
+	}
+	if len(config.Provisioners) > 0 && !slices.Contains(config.Targets, TargetSystemd) {
+		return Config{}, fmt.Errorf("deployment provision %s needs the %s target", strings.Join(config.Provisioners, ", "), TargetSystemd)
+	}
+	if config.Env, err = ParseEnv(deployment["env"]); err != nil {
+		return Config{}, err
+	}
+	if value, ok := deployment["binary_url"]; ok {
+		url, ok := value.(string)
+		if !ok || !strings.HasPrefix(url, "https://") {
+			return Config{}, fmt.Errorf("deployment binary_url must be an https URL")
+		}
+		config.BinaryURL = url
+	}
+
+	if value, ok := deployment["image"]; ok {
+		image, ok := value.(string)
+		if !ok || image == "" || strings.ContainsAny(image, " \t\r\n") {
+			return Config{}, fmt.Errorf("deployment image must be an image reference")
+		}
+		config.Image = image
+	}
+	if value, ok := deployment["health_path"]; ok {
+		path, ok := value.(string)
+		if !ok || (path != "" && !strings.HasPrefix(path, "/")) || strings.ContainsAny(path, " \t\r\n") {
+			return Config{}, fmt.Errorf("deployment health_path must be an absolute URL path, or empty for no probes")
+		}
+		config.HealthPath = path
+	}
+	for _, field := range []struct {
+		name     string
+		value    *int
+		min, max int
+	}{
+		{"port", &config.Port, 1, 65535},
+		{"replicas", &config.Replicas, 1, 1000},
+		{"max_replicas", &config.MaxReplicas, 1, 1000},
+		{"cpu_target", &config.CPUTarget, 1, 100},
+	} {
+		if err := parseInt(field.name, deployment[field.name], field.min, field.max, field.value); err != nil {
+			return Config{}, err
+		}
+	}
+	if config.MaxReplicas > 0 && config.MaxReplicas < max(config.Replicas, 1) {
+		return Config{}, fmt.Errorf("deployment max_replicas %d is below replicas %d", config.MaxReplicas, config.Replicas)
+	}
+	return config, nil
+}
+
+// parseInt reads an optional integer deployment field between min and max.
+// Blueprints stored in the database come back from JSON with float numbers.
+func parseInt(field string, value any, min, max int, target *int) error {
+	var n int
+	var ok bool
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case int:
+		n, ok = v, true
+	case float64:
+		n, ok = int(v), v == float64(int(v))
+	}
+	if !ok || n < min || n > max {
+		return fmt.Errorf("deployment %s must be a number from %d to %d", field, min, max)
+	}
+	*target = n
+	return nil
+}
+
+// ParseTargets reads deployment.target from a blueprint, which may be a
+// single target or a list of them
+func ParseTargets(value any) ([]string, error) {
+	return parseChoices("target", value, Targets, map[string]string{"windows": TargetWindows, "k8s": TargetKubernetes})
+}
+
+// ParseProvisioners reads deployment.provision from a blueprint, which may be
+// a single provisioner or a list of them
+func ParseProvisioners(value any) ([]string, error) {
+	return parseChoices("provision", value, Provisioners, map[string]string{"cloudinit": ProvisionCloudInit})
+}
+
+// ParseEnv reads deployment.env from a blueprint, a map of environment
+// variables for the service
+func ParseEnv(value any) (map[string]string, error) {
+	if value == nil {
+		return nil, nil
+	}
+	values, ok := value.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("deployment env must be a map, got %T", value)
+	}
+
+	env := make(map[string]string, len(values))
+	for name, value := range values {
+		if !envNamePattern.MatchString(name) {
+			return nil, fmt.Errorf("invalid deployment env name %q", name)
+		}
+		text := fmt.Sprint(value)
+		if strings.ContainsAny(text, "\r\n") {
+			return nil, fmt.Errorf("deployment env %s must be a single line", name)
+		}
+		env[name] = text
+	}
+	return env, nil
+}
+
+// parseChoices reads a deployment field holding one or more of valid,
+// lower-cased, with aliases resolved and duplicates removed
+func parseChoices(field string, value any, valid []string, aliases map[string]string) ([]string, error) {
+	var choices []string
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		choices = []string{v}
+	case []string:
+		choices = v
+	case []any:
+		for _, item := range v {
+			choice, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("deployment %s %v is not a string", field, item)
+			}
+			choices = append(choices, choice)
+		}
+	default:
+		return nil, fmt.Errorf("deployment %s must be a string or a list, got %T", field, value)
+	}
+
+	var parsed []string
+	for _, choice := range choices {
+		choice = strings.ToLower(strings.TrimSpace(choice))
+		if alias, ok := aliases[choice]; ok {
+			choice = alias
+		}
+		if !slices.Contains(valid, choice) {
+			return nil, fmt.Errorf("unknown deployment %s %q (valid: %s)", field, choice, strings.Join(valid, ", "))
+		}
+		if !slices.Contains(parsed, choice) {
+			parsed = append(parsed, choice)
+		}
+	}
+	return parsed, nil
+}
+
+// SupportsTemplate reports whether a project template runs as a long-lived
+// process that can be deployed as a service or in a cluster
+func SupportsTemplate(template string) bool {
+	return template != "cli" && template != "library"
+}
+
+// Files returns the project-relative paths written by GenerateAll for config
+func Files(config Config) []string {
+	var files []string
+	if slices.Contains(config.Targets, TargetSystemd) {
+		files = append(files, "deploy/systemd/"+config.ProjectName+".service")
+	}
+	if hasHostTarget(config) {
+		files = append(files, "cmd/"+config.ProjectName+"-service/main.go")
+	}
+	if slices.Contains(config.Targets, TargetKubernetes) {
+		for _, name := range kubernetesManifests {
+			files = append(files, "deploy/kubernetes/"+name)
+		}
+	}
+	if slices.Contains(config.Targets, TargetHelm) {
+		chart := "deploy/helm/" + config.ProjectName + "/"
+		files = append(files, chart+"Chart.yaml", chart+"values.yaml")
+		for _, name := range helmTemplateNames {
+			files = append(files, chart+"templates/"+name)
+		}
+	}
+	if slices.Contains(config.Provisioners, ProvisionCloudInit) {
+		files = append(files, "deploy/cloud-init/user-data.yaml")
+	}
+	if slices.Contains(config.Provisioners, ProvisionAnsible) {
+		role := "deploy/ansible/roles/" + config.ProjectName
+		files = append(files,
+			"deploy/ansible/site.yml",
+			role+"/defaults/main.yml",
+			role+"/handlers/main.yml",
+			role+"/tasks/main.yml",
+			role+"/templates/environment.j2",
+		)
+	}
+	return files
+}
+
+// GenerateAll generates the files of every target in config. The service
+// wrapper is shared by the host targets: kardianos/service installs it with
+// the service manager of the host it runs on.
+func (g *Generator) GenerateAll(ctx context.Context, outputDir string, config Config) error {
+	if slices.Contains(config.Targets, TargetSystemd) {
+		if err := g.GenerateSystemdUnit(ctx, outputDir, config); err != nil {
+			return fmt.Errorf("failed to generate systemd unit: %w", err)
+		}
+	}
+	if hasHostTarget(config) {
+		if err := g.GenerateServiceWrapper(ctx, outputDir, config); err != nil {
+			return fmt.Errorf("failed to generate service wrapper: %w", err)
+		}
+	}
+	if slices.Contains(config.Targets, TargetKubernetes) {
+		if err := g.GenerateKubernetes(ctx, outputDir, config); err != nil {
+			return fmt.Errorf("failed to generate Kubernetes manifests: %w", err)
+		}
+	}
+	if slices.Contains(config.Targets, TargetHelm) {
+		if err := g.GenerateHelmChart(ctx, outputDir, config); err != nil {
+			return fmt.Errorf("failed to generate Helm chart: %w", err)
+		}
+	}
+	if slices.Contains(config.Provisioners, ProvisionCloudInit) {
+		if err := g.GenerateCloudInit(ctx, outputDir, config); err != nil {
+			return fmt.Errorf("failed to generate cloud-init user data: %w", err)
+		}
+	}
+	if slices.Contains(config.Provisioners, ProvisionAnsible) {
+		if err := g.GenerateAnsibleRole(ctx, outputDir, config); err != nil {
+			return fmt.Errorf("failed to generate Ansible role: %w", err)
+		}
+	}
+	return nil
+}
+
+// hasHostTarget reports whether config deploys to a host's service manager
+func hasHostTarget(config Config) bool {
+	return slices.ContainsFunc(config.Targets, func(target string) bool {
+		return slices.Contains(HostTargets, target)
+	})
+}
+
+// systemdUnitTemplate is the systemd unit, also embedded in cloud-init user data
+const systemdUnitTemplate = `# systemd unit for {{ ProjectName }}.
+# Install the binary to /usr/local/bin/{{ ProjectName }}, copy this file to
+# /etc/systemd/system/ and run:
+#   systemctl daemon-reload && systemctl enable --now {{ ProjectName }}
+[Unit]
+Description={{ Description|safe }}
+After=network-online.target
+Wants=network-online.target
+
+[Service]
+Type=simple
+ExecStart=/usr/local/bin/{{ ProjectName }}
+EnvironmentFile=-/etc/{{ ProjectName }}/environment
+DynamicUser=yes
+StateDirectory={{ ProjectName }}
+Restart=on-failure
+RestartSec=5s
+TimeoutStopSec=30s
+NoNewPrivileges=yes
+ProtectSystem=strict
+ProtectHome=yes
+PrivateTmp=yes
+
+[Install]
+WantedBy=multi-user.target
+`
+
+// GenerateSystemdUnit generates deploy/systemd/<project>.service for
+// packaging the project binary
+func (g *Generator) GenerateSystemdUnit(ctx context.Context, outputDir string, config Config) error {
+	outputPath := filepath.Join(outputDir, "deploy", "systemd", config.ProjectName+".service")
+	return g.templateEngine.RenderToFile(ctx, systemdUnitTemplate, g.variables(config), outputPath)
+}
+
+// GenerateServiceWrapper generates cmd/<project>-service/main.go, which runs
+// the project binary under the host's service manager and has install,
+// uninstall, start, stop and run subcommands
+func (g *Generator) GenerateServiceWrapper(ctx context.Context, outputDir string, config Config) error {
+	template := `// Command {{ ProjectName }}-service runs {{ ProjectName }} as a system service:
+// a Windows service, or a systemd or launchd service elsewhere.
+//
+// Build it next to the {{ ProjectName }} binary and, from an elevated shell, run
+//
+//	{{ ProjectName }}-service install
+//	{{ ProjectName }}-service start
+//
+// It needs github.com/kardianos/service; run go mod tidy after generation.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sync/atomic"
+	"time"
+
+	"github.com/kardianos/service"
+)
+
+const serviceName = "{{ ProjectName }}"
+
+// stopTimeout is how long {{ ProjectName }} gets to shut down before it is killed
+const stopTimeout = 30 * time.Second
+
+// program supervises the {{ ProjectName }} binary
+type program struct {
+	binary   string
+	cmd      *exec.Cmd
+	done     chan error
+	stopping atomic.Bool
+}
+
+func (p *program) Start(s service.Service) error {
+	p.cmd = exec.Command(p.binary)
+	p.cmd.Stdout = os.Stdout
+	p.cmd.Stderr = os.Stderr
+	if err := p.cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start %s: %w", p.binary, err)
+	}
+
+	p.done = make(chan error, 1)
+	go func() {
+		err := p.cmd.Wait()
+		p.done <- err
+		// Exiting on its own is a failure, so the service manager restarts it
+		if !p.stopping.Load() && !service.Interactive() {
+			log.Printf("%s exited: %v", serviceName, err)
+			os.Exit(1)
+		}
+	}()
+	return nil
+}
+
+func (p *program) Stop(s service.Service) error {
+	if p.cmd == nil || p.cmd.Process == nil {
+		return nil
+	}
+	p.stopping.Store(true)
+
+	// Windows cannot deliver interrupts to other processes
+	if runtime.GOOS == "windows" {
+		return p.cmd.Process.Kill()
+	}
+	if err := p.cmd.Process.Signal(os.Interrupt); err != nil {
+		return p.cmd.Process.Kill()
+	}
+	select {
+	case <-p.done:
+		return nil
+	case <-time.After(stopTimeout):
+		return p.cmd.Process.Kill()
+	}
+}
+
+func main() {
+	binary := flag.String("binary", defaultBinary(), "Path to the {{ ProjectName }} binary")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] install|uninstall|start|stop|run\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	absBinary, err := filepath.Abs(*binary)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	config := &service.Config{
+		Name:        serviceName,
+		DisplayName: serviceName,
+		Description: {{ DescriptionLiteral|safe }},
+		Arguments:   []string{"-binary", absBinary, "run"},
+		Option: service.KeyValue{
+			"Restart":                "on-failure",
+			"OnFailure":              "restart",
+			"OnFailureDelayDuration": "5s",
+		},
+	}
+
+	svc, err := service.New(&program{binary: absBinary}, config)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	switch action := flag.Arg(0); action {
+	case "install", "uninstall", "start", "stop":
+		if err := service.Control(svc, action); err != nil {
+			log.Fatalf("failed to %s %s: %v", action, serviceName, err)
+		}
+		fmt.Printf("%s: %s done\n", serviceName, action)
+	case "run", "":
+		if err := svc.Run(); err != nil {
+			log.Fatal(err)
+		}
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+}
+
+// defaultBinary is the {{ ProjectName }} binary next to this executable
+func defaultBinary() string {
+	name := serviceName
+	if runtime.GOOS == "windows" {
+		name += ".exe"
+	}
+	self, err := os.Executable()
+	if err != nil {
+		return name
+	}
+	return filepath.Join(filepath.Dir(self), name)
+}
+`
+
+	outputPath := filepath.Join(outputDir, "cmd", config.ProjectName+"-service", "main.go")
+	return g.templateEngine.RenderToFile(ctx, template, g.variables(config), outputPath)
+}
+
+// GenerateCloudInit generates deploy/cloud-init/user-data.yaml, which
+// downloads the release binary, writes its environment file and enables the
+// systemd unit on first boot
+func (g *Generator) GenerateCloudInit(ctx context.Context, outputDir string, config Config) error {
+	variables := g.variables(config)
+	unit, err := g.templateEngine.RenderString(ctx, systemdUnitTemplate, variables)
+	if err != nil {
+		return err
+	}
+	variables["Unit"] = indent(unit, "      ")
+
+	template := `#cloud-config
+# Provisions {{ ProjectName }} on a plain VM: installs the release binary,
+# writes /etc/{{ ProjectName }}/environment and enables the systemd unit.
+# Pass this file as user data when creating the VM, e.g.
+#   aws ec2 run-instances --user-data file://deploy/cloud-init/user-data.yaml ...
+write_files:
+{% if EnvLines %}  - path: /etc/{{ ProjectName }}/environment
+    permissions: "0640"
+    content: |
+{% for line in EnvLines %}      {{ line|safe }}
+{% endfor %}{% endif %}  - path: /etc/systemd/system/{{ ProjectName }}.service
+    permissions: "0644"
+    content: |
+{{ Unit|safe }}
+runcmd:
+  # Set deployment.binary_url in the blueprint to change the download
+  - [curl, -fsSL, --retry, "3", -o, /usr/local/bin/{{ ProjectName }}, "{{ BinaryURL|safe }}"]
+  - [chmod, "0755", /usr/local/bin/{{ ProjectName }}]
+  - [systemctl, daemon-reload]
+  - [systemctl, enable, --now, {{ ProjectName }}.service]
+`
+
+	outputPath := filepath.Join(outputDir, "deploy", "cloud-init", "user-data.yaml")
+	return g.templateEngine.RenderToFile(ctx, template, variables, outputPath)
+}
+
+// GenerateAnsibleRole generates deploy/ansible/site.yml and a role that
+// copies the built binary and the systemd unit to the hosts, writes the
+// environment file and enables the unit
+func (g *Generator) GenerateAnsibleRole(ctx context.Context, outputDir string, config Config) error {
+	files := map[string]string{
+		"site.yml": `---
+# Provisions {{ ProjectName }} on the hosts of the {{ AnsibleName }} group:
+#   make build
+#   ansible-playbook -i inventory.ini deploy/ansible/site.yml
+- name: Deploy {{ ProjectName }}
+  hosts: {{ AnsibleName }}
+  become: true
+  roles:
+    - {{ ProjectName }}
+`,
+		"defaults/main.yml": `---
+# Binary built by make build
+{{ AnsibleName }}_binary_src: "{{ "{{" }} playbook_dir {{ "}}" }}/../../{{ ProjectName }}"
+# systemd unit generated by gogo
+{{ AnsibleName }}_unit_src: "{{ "{{" }} playbook_dir {{ "}}" }}/../systemd/{{ ProjectName }}.service"
+# Written to /etc/{{ ProjectName }}/environment
+{% if EnvYAML %}{{ AnsibleName }}_env:
+{% for line in EnvYAML %}  {{ line|safe }}
+{% endfor %}{% else %}{{ AnsibleName }}_env: {}
+{% endif %}`,
+		"handlers/main.yml": `---
+- name: Restart {{ ProjectName }}
+  ansible.builtin.systemd:
+    name: {{ ProjectName }}
+    state: restarted
+    daemon_reload: true
+`,
+		"tasks/main.yml": `---
+- name: Install the {{ ProjectName }} binary
+  ansible.builtin.copy:
+    src: "{{ "{{" }} {{ AnsibleName }}_binary_src {{ "}}" }}"
+    dest: /usr/local/bin/{{ ProjectName }}
+    mode: "0755"
+  notify: Restart {{ ProjectName }}
+
+- name: Create /etc/{{ ProjectName }}
+  ansible.builtin.file:
+    path: /etc/{{ ProjectName }}
+    state: directory
+    mode: "0755"
+
+- name: Write the {{ ProjectName }} environment
+  ansible.builtin.template:
+    src: environment.j2
+    dest: /etc/{{ ProjectName }}/environment
+    mode: "0640"
+  notify: Restart {{ ProjectName }}
+
+- name: Install the {{ ProjectName }} systemd unit
+  ansible.builtin.copy:
+    src: "{{ "{{" }} {{ AnsibleName }}_unit_src {{ "}}" }}"
+    dest: /etc/systemd/system/{{ ProjectName }}.service
+    mode: "0644"
+  notify: Restart {{ ProjectName }}
+
+- name: Enable and start {{ ProjectName }}
+  ansible.builtin.systemd:
+    name: {{ ProjectName }}
+    enabled: true
+    state: started
+    daemon_reload: true
+`,
+		"templates/environment.j2": `# Managed by Ansible
+{{ "{%" }} for name, value in {{ AnsibleName }}_env | dictsort {{ "%}" }}
+{{ "{{" }} name {{ "}}" }}={{ "{{" }} value | string | to_json {{ "}}" }}
+{{ "{%" }} endfor {{ "%}" }}
+`,
+	}
+
+	roleDir := filepath.Join(outputDir, "deploy", "ansible", "roles", config.ProjectName)
+	for name, template := range files {
+		outputPath := filepath.Join(roleDir, filepath.FromSlash(name))
+		if name == "site.yml" {
+			outputPath = filepath.Join(outputDir, "deploy", "ansible", name)
+		}
+		if err := g.templateEngine.RenderToFile(ctx, template, g.variables(config), outputPath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (g *Generator) variables(config Config) map[string]any {
+	description := config.Description
+	if description == "" {
+		description = config.ProjectName
+	}
+	binaryURL := config.BinaryURL
+	if binaryURL == "" {
+		binaryURL = defaultBinaryURL(config)
+	}
+
+	names := make([]string, 0, len(config.Env))
+	for name := range config.Env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	envLines := make([]string, len(names))
+	envYAML := make([]string, len(names))
+	for i, name := range names {
+		// Double-quoted Go strings are valid systemd and YAML values
+		value := strconv.Quote(config.Env[name])
+		envLines[i] = name + "=" + value
+		envYAML[i] = name + ": " + value
+	}
+
+	repository, tag := splitImage(config.Image)
+	if repository == "" {
+		repository, tag = defaultImage(config), "latest"
+	}
+
+	return map[string]any{
+		"ProjectName": config.ProjectName,
+		"ModuleName":  config.ModuleName,
+		"Description": description,
+		// Go string literal for the service wrapper
+		"DescriptionLiteral": strconv.Quote(description),
+		// Ansible variable and group names cannot contain dashes
+		"AnsibleName": strings.NewReplacer("-", "_", ".", "_").Replace(config.ProjectName),
+		"BinaryURL":   binaryURL,
+		"EnvLines":    envLines,
+		"EnvYAML":     envYAML,
+		// Cluster targets
+		"ImageRepository": repository,
+		"ImageTag":        tag,
+		"Port":            withDefault(config.Port, 8080),
+		"Replicas":        withDefault(config.Replicas, 2),
+		"MaxReplicas":     max(withDefault(config.MaxReplicas, 5), withDefault(config.Replicas, 2)),
+		"CPUTarget":       withDefault(config.CPUTarget, 80),
+		"HealthPath":      config.HealthPath,
+	}
+}
+
+// withDefault returns value, or fallback when value is zero
+func withDefault(value, fallback int) int {
+	if value == 0 {
+		return fallback
+	}
+	return value
+}
+
+// defaultBinaryURL is the latest GitHub release asset for modules hosted on
+// GitHub, and a placeholder otherwise
+func defaultBinaryURL(config Config) string {
+	asset := config.ProjectName + "-linux-amd64"
+	if repo, ok := strings.CutPrefix(config.ModuleName, "github.com/"); ok && strings.Count(repo, "/") >= 1 {
+		parts := strings.SplitN(repo, "/", 3)
+		return "https://github.com/" + parts[0] + "/" + parts[1] + "/releases/latest/download/" + asset
+	}
+	return "https://downloads.example.com/" + config.ProjectName + "/" + asset
+}
+
+// indent prefixes every non-empty line of text
+func indent(text, prefix string) string {
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = prefix + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,276 @@
+package deploy
+
+import (
+	"context"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestParseTargets(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   any
+		want    []string
+		wantErr string
+	}{
+		{name: "unset", value: nil},
+		{name: "single", value: "systemd", want: []string{TargetSystemd}},
+		{name: "windows alias", value: "Windows", want: []string{TargetWindows}},
+		{name: "k8s alias", value: "k8s", want: []string{TargetKubernetes}},
+		{name: "list", value: []any{"systemd", "windows-service", "systemd"}, want: []string{TargetSystemd, TargetWindows}},
+		{name: "unknown", value: "launchd", wantErr: "unknown deployment target"},
+		{name: "wrong type", value: 3, wantErr: "string or a list"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			targets, err := ParseTargets(tt.value)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, targets)
+		})
+	}
+}
+
+func TestGenerator_GenerateAll(t *testing.T) {
+	tempDir := t.TempDir()
+	config := Config{
+		ProjectName: "orders",
+		ModuleName:  "github.com/acme/orders",
+		Description: `Order "processing" worker`,
+		Targets:     []string{TargetSystemd, TargetWindows},
+	}
+
+	require.NoError(t, NewGenerator().GenerateAll(context.Background(), tempDir, config))
+	for _, file := range Files(config) {
+		assert.FileExists(t, filepath.Join(tempDir, file))
+	}
+
+	unit, err := os.ReadFile(filepath.Join(tempDir, "deploy", "systemd", "orders.service"))
+	require.NoError(t, err)
+	assert.Contains(t, string(unit), "ExecStart=/usr/local/bin/orders")
+
+	wrapper := filepath.Join(tempDir, "cmd", "orders-service", "main.go")
+	_, err = parser.ParseFile(token.NewFileSet(), wrapper, nil, parser.AllErrors)
+	require.NoError(t, err, "the service wrapper must be valid Go")
+	content, err := os.ReadFile(wrapper)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `Description: "Order \"processing\" worker",`)
+	assert.Contains(t, string(content), `case "install", "uninstall", "start", "stop":`)
+}
+
+func TestGenerator_GenerateAll_WindowsOnly(t *testing.T) {
+	tempDir := t.TempDir()
+	config := Config{ProjectName: "orders", Targets: []string{TargetWindows}}
+
+	require.NoError(t, NewGenerator().GenerateAll(context.Background(), tempDir, config))
+	assert.Equal(t, []string{"cmd/orders-service/main.go"}, Files(config))
+	assert.NoDirExists(t, filepath.Join(tempDir, "deploy"))
+}
+
+func TestSupportsTemplate(t *testing.T) {
+	assert.True(t, SupportsTemplate("microservice"))
+	assert.True(t, SupportsTemplate("worker"))
+	assert.False(t, SupportsTemplate("cli"))
+	assert.False(t, SupportsTemplate("library"))
+}
+
+func TestParseConfig(t *testing.T) {
+	config, err := ParseConfig(map[string]any{
+		"target":     "systemd",
+		"provision":  []any{"cloud-init", "ansible"},
+		"env":        map[string]any{"LOG_LEVEL": "info", "WORKERS": 4},
+		"binary_url": "https://example.com/orders",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{TargetSystemd}, config.Targets)
+	assert.Equal(t, []string{ProvisionCloudInit, ProvisionAnsible}, config.Provisioners)
+	assert.Equal(t, map[string]string{"LOG_LEVEL": "info", "WORKERS": "4"}, config.Env)
+	assert.Equal(t, "https://example.com/orders", config.BinaryURL)
+
+	for name, deployment := range map[string]map[string]any{
+		"needs the systemd target":     {"target": "windows", "provision": "ansible"},
+		"unknown deployment provision": {"target": "systemd", "provision": "puppet"},
+		"invalid deployment env name":  {"env": map[string]any{"LOG-LEVEL": "info"}},
+		"single line":                  {"env": map[string]any{"MOTD": "a\nb"}},
+		"https URL":                    {"binary_url": "http://example.com/orders"},
+	} {
+		_, err := ParseConfig(deployment)
+		assert.ErrorContains(t, err, name)
+	}
+}
+
+func TestGenerator_GenerateAll_Provisioners(t *testing.T) {
+	tempDir := t.TempDir()
+	config := Config{
+		ProjectName:  "order-svc",
+		ModuleName:   "github.com/acme/orders",
+		Targets:      []string{TargetSystemd},
+		Provisioners: []string{ProvisionCloudInit, ProvisionAnsible},
+		Env:          map[string]string{"APP_ENV": "prod", "GREETING": `say "hi"`},
+	}
+
+	require.NoError(t, NewGenerator().GenerateAll(context.Background(), tempDir, config))
+	for _, file := range Files(config) {
+		assert.FileExists(t, filepath.Join(tempDir, file))
+	}
+
+	data, err := os.ReadFile(filepath.Join(tempDir, "deploy", "cloud-init", "user-data.yaml"))
+	require.NoError(t, err)
+	var userData struct {
+		WriteFiles []struct {
+			Path    string `yaml:"path"`
+			Content string `yaml:"content"`
+		} `yaml:"write_files"`
+		RunCmd [][]string `yaml:"runcmd"`
+	}
+	require.NoError(t, yaml.Unmarshal(data, &userData), "user data must be valid YAML")
+	require.Len(t, userData.WriteFiles, 2)
+	assert.Equal(t, "APP_ENV=\"prod\"\nGREETING=\"say \\\"hi\\\"\"\n", userData.WriteFiles[0].Content)
+	assert.Contains(t, userData.WriteFiles[1].Content, "ExecStart=/usr/local/bin/order-svc\n")
+	assert.Contains(t, userData.RunCmd[0], "https://github.com/acme/orders/releases/latest/download/order-svc-linux-amd64")
+
+	data, err = os.ReadFile(filepath.Join(tempDir, "deploy", "ansible", "roles", "order-svc", "defaults", "main.yml"))
+	require.NoError(t, err)
+	var defaults map[string]any
+	require.NoError(t, yaml.Unmarshal(data, &defaults))
+	assert.Equal(t, map[string]any{"APP_ENV": "prod", "GREETING": `say "hi"`}, defaults["order_svc_env"])
+	assert.Equal(t, "{{ playbook_dir }}/../../order-svc", defaults["order_svc_binary_src"])
+
+	tasks, err := os.ReadFile(filepath.Join(tempDir, "deploy", "ansible", "roles", "order-svc", "tasks", "main.yml"))
+	require.NoError(t, err)
+	assert.Contains(t, string(tasks), `src: "{{ order_svc_unit_src }}"`)
+}
+
+func TestParseConfig_Cluster(t *testing.T) {
+	config, err := ParseConfig(map[string]any{
+		"target":       []any{"k8s", "helm"},
+		"image":        "ghcr.io/acme/orders:v1.2.0",
+		"port":         9090,
+		"replicas":     3,
+		"max_replicas": float64(10),
+		"cpu_target":   70,
+		"health_path":  "/healthz",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{TargetKubernetes, TargetHelm}, config.Targets)
+	assert.Equal(t, "ghcr.io/acme/orders:v1.2.0", config.Image)
+	assert.Equal(t, 9090, config.Port)
+	assert.Equal(t, 3, config.Replicas)
+	assert.Equal(t, 10, config.MaxReplicas)
+	assert.Equal(t, 70, config.CPUTarget)
+	assert.Equal(t, "/healthz", config.HealthPath)
+
+	config, err = ParseConfig(map[string]any{"target": "kubernetes"})
+	require.NoError(t, err)
+	assert.Equal(t, DefaultHealthPath, config.HealthPath)
+
+	config, err = ParseConfig(map[string]any{"target": "kubernetes", "health_path": ""})
+	require.NoError(t, err)
+	assert.Empty(t, config.HealthPath)
+
+	for name, deployment := range map[string]map[string]any{
+		"image reference":             {"image": "orders latest"},
+		"port must be a number":       {"port": "http"},
+		"cpu_target must be a number": {"cpu_target": 150},
+		"replicas must be a number":   {"replicas": 2.5},
+		"below replicas":              {"replicas": 4, "max_replicas": 2},
+		"absolute URL path":           {"health_path": "health"},
+	} {
+		_, err := ParseConfig(deployment)
+		assert.ErrorContains(t, err, name)
+	}
+}
+
+func TestGenerator_GenerateAll_Kubernetes(t *testing.T) {
+	tempDir := t.TempDir()
+	config := Config{
+		ProjectName: "orders",
+		ModuleName:  "github.com/Acme/orders",
+		Targets:     []string{TargetKubernetes},
+		Env:         map[string]string{"APP_ENV": "prod"},
+		Port:        9090,
+		HealthPath:  DefaultHealthPath,
+	}
+
+	require.NoError(t, NewGenerator().GenerateAll(context.Background(), tempDir, config))
+	assert.Equal(t, []string{
+		"deploy/kubernetes/kustomization.yaml",
+		"deploy/kubernetes/configmap.yaml",
+		"deploy/kubernetes/deployment.yaml",
+		"deploy/kubernetes/service.yaml",
+		"deploy/kubernetes/hpa.yaml",
+	}, Files(config))
+	assert.NoDirExists(t, filepath.Join(tempDir, "cmd"), "clusters need no service wrapper")
+
+	manifest := func(name string) map[string]any {
+		data, err := os.ReadFile(filepath.Join(tempDir, "deploy", "kubernetes", name))
+		require.NoError(t, err)
+		var manifest map[string]any
+		require.NoError(t, yaml.Unmarshal(data, &manifest), "%s must be valid YAML", name)
+		return manifest
+	}
+
+	kustomization := manifest("kustomization.yaml")
+	assert.Equal(t, []any{map[string]any{"name": "orders", "newName": "ghcr.io/acme/orders", "newTag": "latest"}}, kustomization["images"])
+	assert.Equal(t, map[string]any{"APP_ENV": "prod"}, manifest("configmap.yaml")["data"])
+
+	container := manifest("deployment.yaml")["spec"].(map[string]any)["template"].(map[string]any)["spec"].(map[string]any)["containers"].([]any)[0].(map[string]any)
+	assert.Equal(t, []any{map[string]any{"name": "http", "containerPort": 9090}}, container["ports"])
+	assert.Equal(t, "/health", container["readinessProbe"].(map[string]any)["httpGet"].(map[string]any)["path"])
+
+	hpa := manifest("hpa.yaml")["spec"].(map[string]any)
+	assert.Equal(t, 2, hpa["minReplicas"])
+	assert.Equal(t, 5, hpa["maxReplicas"])
+}
+
+func TestGenerator_GenerateAll_Helm(t *testing.T) {
+	tempDir := t.TempDir()
+	config := Config{
+		ProjectName: "orders",
+		Description: "Order service",
+		Targets:     []string{TargetHelm, TargetSystemd},
+		Image:       "registry.example.com:5000/orders:v2",
+		Replicas:    3,
+	}
+
+	require.NoError(t, NewGenerator().GenerateAll(context.Background(), tempDir, config))
+	for _, file := range Files(config) {
+		assert.FileExists(t, filepath.Join(tempDir, file))
+	}
+
+	chartDir := filepath.Join(tempDir, "deploy", "helm", "orders")
+	data, err := os.ReadFile(filepath.Join(chartDir, "Chart.yaml"))
+	require.NoError(t, err)
+	var chart map[string]any
+	require.NoError(t, yaml.Unmarshal(data, &chart))
+	assert.Equal(t, "orders", chart["name"])
+	assert.Equal(t, "Order service", chart["description"])
+	assert.Equal(t, "v2", chart["appVersion"])
+
+	data, err = os.ReadFile(filepath.Join(chartDir, "values.yaml"))
+	require.NoError(t, err)
+	var values map[string]any
+	require.NoError(t, yaml.Unmarshal(data, &values))
+	assert.Equal(t, "registry.example.com:5000/orders", values["image"].(map[string]any)["repository"])
+	assert.Equal(t, 3, values["replicaCount"])
+	assert.Equal(t, "", values["healthPath"])
+	assert.Equal(t, map[string]any{}, values["env"])
+
+	helpers, err := os.ReadFile(filepath.Join(chartDir, "templates", "_helpers.tpl"))
+	require.NoError(t, err)
+	assert.Contains(t, string(helpers), `{{- define "orders.fullname" -}}`)
+	deployment, err := os.ReadFile(filepath.Join(chartDir, "templates", "deployment.yaml"))
+	require.NoError(t, err)
+	assert.Contains(t, string(deployment), `{{- include "orders.selectorLabels" . | nindent 6 }}`)
+}
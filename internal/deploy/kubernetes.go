@@ -0,0 +1,369 @@
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/user/gogo/internal/fsutil"
+)
+
+// kubernetesManifests are the files of the kubernetes target under
+// deploy/kubernetes, in the order kustomize applies them
+var kubernetesManifests = []string{"kustomization.yaml", "configmap.yaml", "deployment.yaml", "service.yaml", "hpa.yaml"}
+
+// kubernetesTemplates are the manifests of the kubernetes target
+var kubernetesTemplates = map[string]string{
+	"kustomization.yaml": `# Kubernetes manifests for {{ ProjectName }}. Push the image, then run
+#   kubectl apply -k deploy/kubernetes
+apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+resources:
+  - configmap.yaml
+  - deployment.yaml
+  - service.yaml
+  - hpa.yaml
+images:
+  - name: {{ ProjectName }}
+    newName: {{ ImageRepository }}
+    newTag: "{{ ImageTag }}"
+`,
+	"configmap.yaml": `# Environment of the {{ ProjectName }} container
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ ProjectName }}-config
+  labels:
+    app.kubernetes.io/name: {{ ProjectName }}
+{% if EnvYAML %}data:
+{% for line in EnvYAML %}  {{ line|safe }}
+{% endfor %}{% else %}data: {}
+{% endif %}`,
+	"deployment.yaml": `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: {{ ProjectName }}
+  labels:
+    app.kubernetes.io/name: {{ ProjectName }}
+spec:
+  # The HorizontalPodAutoscaler takes over from here
+  replicas: {{ Replicas }}
+  selector:
+    matchLabels:
+      app.kubernetes.io/name: {{ ProjectName }}
+  template:
+    metadata:
+      labels:
+        app.kubernetes.io/name: {{ ProjectName }}
+    spec:
+      containers:
+        - name: {{ ProjectName }}
+          # Set in kustomization.yaml
+          image: {{ ProjectName }}
+          ports:
+            - name: http
+              containerPort: {{ Port }}
+          envFrom:
+            - configMapRef:
+                name: {{ ProjectName }}-config
+{% if HealthPath %}          readinessProbe:
+            httpGet:
+              path: {{ HealthPath }}
+              port: http
+            periodSeconds: 5
+          livenessProbe:
+            httpGet:
+              path: {{ HealthPath }}
+              port: http
+            initialDelaySeconds: 10
+            periodSeconds: 10
+{% endif %}          resources:
+            # The autoscaler's CPU target is a share of the request
+            requests:
+              cpu: 100m
+              memory: 128Mi
+            limits:
+              memory: 256Mi
+          securityContext:
+            allowPrivilegeEscalation: false
+`,
+	"service.yaml": `apiVersion: v1
+kind: Service
+metadata:
+  name: {{ ProjectName }}
+  labels:
+    app.kubernetes.io/name: {{ ProjectName }}
+spec:
+  type: ClusterIP
+  ports:
+    - name: http
+      port: 80
+      targetPort: http
+  selector:
+    app.kubernetes.io/name: {{ ProjectName }}
+`,
+	"hpa.yaml": `apiVersion: autoscaling/v2
+kind: HorizontalPodAutoscaler
+metadata:
+  name: {{ ProjectName }}
+  labels:
+    app.kubernetes.io/name: {{ ProjectName }}
+spec:
+  scaleTargetRef:
+    apiVersion: apps/v1
+    kind: Deployment
+    name: {{ ProjectName }}
+  minReplicas: {{ Replicas }}
+  maxReplicas: {{ MaxReplicas }}
+  metrics:
+    - type: Resource
+      resource:
+        name: cpu
+        target:
+          type: Utilization
+          averageUtilization: {{ CPUTarget }}
+`,
+}
+
+// GenerateKubernetes generates deploy/kubernetes: a Deployment of the
+// project's image, its Service, a HorizontalPodAutoscaler and a ConfigMap
+// with the environment, tied together by a kustomization
+func (g *Generator) GenerateKubernetes(ctx context.Context, outputDir string, config Config) error {
+	variables := g.variables(config)
+	for _, name := range kubernetesManifests {
+		outputPath := filepath.Join(outputDir, "deploy", "kubernetes", name)
+		if err := g.templateEngine.RenderToFile(ctx, kubernetesTemplates[name], variables, outputPath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// helmChartTemplates are the chart metadata and default values of the helm
+// target, rendered with the project's variables
+var helmChartTemplates = map[string]string{
+	"Chart.yaml": `apiVersion: v2
+name: {{ ProjectName }}
+description: {{ DescriptionLiteral|safe }}
+type: application
+version: 0.1.0
+appVersion: "{{ ImageTag }}"
+`,
+	"values.yaml": `# Default values for {{ ProjectName }}. Install with
+#   helm install {{ ProjectName }} deploy/helm/{{ ProjectName }}
+replicaCount: {{ Replicas }}
+
+image:
+  repository: {{ ImageRepository }}
+  # Defaults to the chart's appVersion
+  tag: ""
+  pullPolicy: IfNotPresent
+
+service:
+  type: ClusterIP
+  port: 80
+
+containerPort: {{ Port }}
+
+# Readiness and liveness probe path; empty for no probes
+healthPath: "{{ HealthPath }}"
+
+# Environment of the container, kept in a ConfigMap
+{% if EnvYAML %}env:
+{% for line in EnvYAML %}  {{ line|safe }}
+{% endfor %}{% else %}env: {}
+{% endif %}
+resources:
+  requests:
+    cpu: 100m
+    memory: 128Mi
+  limits:
+    memory: 256Mi
+
+autoscaling:
+  enabled: true
+  minReplicas: {{ Replicas }}
+  maxReplicas: {{ MaxReplicas }}
+  targetCPUUtilizationPercentage: {{ CPUTarget }}
+`,
+}
+
+// helmTemplateNames are the files of the chart's templates directory
+var helmTemplateNames = []string{"_helpers.tpl", "configmap.yaml", "deployment.yaml", "service.yaml", "hpa.yaml"}
+
+// helmTemplates are the chart's own templates. They are Go templates for
+// Helm, so they are written as they are rather than through the template
+// engine, with only CHART replaced by the chart name in the helpers' names.
+var helmTemplates = map[string]string{
+	"_helpers.tpl": `{{/* Name of the chart's resources */}}
+{{- define "CHART.fullname" -}}
+{{- if contains .Chart.Name .Release.Name -}}
+{{- .Release.Name | trunc 63 | trimSuffix "-" -}}
+{{- else -}}
+{{- printf "%s-%s" .Release.Name .Chart.Name | trunc 63 | trimSuffix "-" -}}
+{{- end -}}
+{{- end -}}
+
+{{- define "CHART.selectorLabels" -}}
+app.kubernetes.io/name: {{ .Chart.Name }}
+app.kubernetes.io/instance: {{ .Release.Name }}
+{{- end -}}
+
+{{- define "CHART.labels" -}}
+{{ include "CHART.selectorLabels" . }}
+helm.sh/chart: {{ printf "%s-%s" .Chart.Name .Chart.Version | replace "+" "_" }}
+app.kubernetes.io/version: {{ .Chart.AppVersion | quote }}
+app.kubernetes.io/managed-by: {{ .Release.Service }}
+{{- end -}}
+`,
+	"configmap.yaml": `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ include "CHART.fullname" . }}
+  labels:
+    {{- include "CHART.labels" . | nindent 4 }}
+data:
+  {{- range $name, $value := .Values.env }}
+  {{ $name }}: {{ $value | toString | quote }}
+  {{- end }}
+`,
+	"deployment.yaml": `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: {{ include "CHART.fullname" . }}
+  labels:
+    {{- include "CHART.labels" . | nindent 4 }}
+spec:
+  {{- if not .Values.autoscaling.enabled }}
+  replicas: {{ .Values.replicaCount }}
+  {{- end }}
+  selector:
+    matchLabels:
+      {{- include "CHART.selectorLabels" . | nindent 6 }}
+  template:
+    metadata:
+      labels:
+        {{- include "CHART.selectorLabels" . | nindent 8 }}
+      annotations:
+        # Roll the pods when the environment changes
+        checksum/config: {{ include (print $.Template.BasePath "/configmap.yaml") . | sha256sum }}
+    spec:
+      containers:
+        - name: {{ .Chart.Name }}
+          image: "{{ .Values.image.repository }}:{{ .Values.image.tag | default .Chart.AppVersion }}"
+          imagePullPolicy: {{ .Values.image.pullPolicy }}
+          ports:
+            - name: http
+              containerPort: {{ .Values.containerPort }}
+          envFrom:
+            - configMapRef:
+                name: {{ include "CHART.fullname" . }}
+          {{- with .Values.healthPath }}
+          readinessProbe:
+            httpGet:
+              path: {{ . }}
+              port: http
+            periodSeconds: 5
+          livenessProbe:
+            httpGet:
+              path: {{ . }}
+              port: http
+            initialDelaySeconds: 10
+            periodSeconds: 10
+          {{- end }}
+          resources:
+            {{- toYaml .Values.resources | nindent 12 }}
+          securityContext:
+            allowPrivilegeEscalation: false
+`,
+	"service.yaml": `apiVersion: v1
+kind: Service
+metadata:
+  name: {{ include "CHART.fullname" . }}
+  labels:
+    {{- include "CHART.labels" . | nindent 4 }}
+spec:
+  type: {{ .Values.service.type }}
+  ports:
+    - name: http
+      port: {{ .Values.service.port }}
+      targetPort: http
+  selector:
+    {{- include "CHART.selectorLabels" . | nindent 4 }}
+`,
+	"hpa.yaml": `{{- if .Values.autoscaling.enabled }}
+apiVersion: autoscaling/v2
+kind: HorizontalPodAutoscaler
+metadata:
+  name: {{ include "CHART.fullname" . }}
+  labels:
+    {{- include "CHART.labels" . | nindent 4 }}
+spec:
+  scaleTargetRef:
+    apiVersion: apps/v1
+    kind: Deployment
+    name: {{ include "CHART.fullname" . }}
+  minReplicas: {{ .Values.autoscaling.minReplicas }}
+  maxReplicas: {{ .Values.autoscaling.maxReplicas }}
+  metrics:
+    - type: Resource
+      resource:
+        name: cpu
+        target:
+          type: Utilization
+          averageUtilization: {{ .Values.autoscaling.targetCPUUtilizationPercentage }}
+{{- end }}
+`,
+}
+
+// GenerateHelmChart generates a Helm chart in deploy/helm/<project> with the
+// same resources as the kubernetes target, configured through values.yaml
+func (g *Generator) GenerateHelmChart(ctx context.Context, outputDir string, config Config) error {
+	chartDir := filepath.Join(outputDir, "deploy", "helm", config.ProjectName)
+	variables := g.variables(config)
+	for _, name := range []string{"Chart.yaml", "values.yaml"} {
+		if err := g.templateEngine.RenderToFile(ctx, helmChartTemplates[name], variables, filepath.Join(chartDir, name)); err != nil {
+			return err
+		}
+	}
+
+	templatesDir := filepath.Join(chartDir, "templates")
+	if err := os.MkdirAll(templatesDir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", templatesDir, err)
+	}
+	for _, name := range helmTemplateNames {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		content := strings.ReplaceAll(helmTemplates[name], `"CHART.`, `"`+config.ProjectName+`.`)
+		outputPath := filepath.Join(templatesDir, name)
+		if err := fsutil.WriteFile(outputPath, []byte(content), 0644); err != nil {
+			return fmt.Errorf("failed to write file %s: %w", outputPath, err)
+		}
+	}
+	return nil
+}
+
+// splitImage splits an image reference into its repository and tag; a
+// reference without a tag is the latest image
+func splitImage(image string) (string, string) {
+	if image == "" {
+		return "", ""
+	}
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		return image[:i], image[i+1:]
+	}
+	return image, "latest"
+}
+
+// defaultImage is the GitHub Container Registry image for modules hosted on
+// GitHub, and the project name otherwise
+func defaultImage(config Config) string {
+	if repo, ok := strings.CutPrefix(config.ModuleName, "github.com/"); ok && strings.Count(repo, "/") >= 1 {
+		parts := strings.SplitN(repo, "/", 3)
+		return strings.ToLower("ghcr.io/" + parts[0] + "/" + parts[1])
+	}
+	return config.ProjectName
+}
@@ -0,0 +1,264 @@
+// Package docker generates a project's Dockerfile: a multi-stage build that
+// copies the binary onto a distroless or alpine base, or a single alpine
+// stage, running as an unprivileged user with optional build arguments, an
+// exposed port and a health check. Blueprints configure it with their docker
+// section; gogo add docker adds it to an existing project.
+package docker
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/user/gogo/internal/templates"
+)
+
+// DockerfilePath is the project-relative path of the generated Dockerfile
+const DockerfilePath = "Dockerfile"
+
+// Runtime images of a multi-stage build, selected by docker.runtime
+const (
+	RuntimeAlpine     = "alpine"
+	RuntimeDistroless = "distroless"
+)
+
+// Runtimes lists the supported runtime images
+var Runtimes = []string{RuntimeAlpine, RuntimeDistroless}
+
+// DefaultHealthPath is the endpoint the health check requests unless a
+// blueprint sets docker.health_path
+const DefaultHealthPath = "/health"
+
+// argNamePattern matches build argument names
+var argNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Config represents Dockerfile generation options
+type Config struct {
+	ProjectName  string
+	GoVersion    string
+	BuilderImage string            // Go image the binary is built in, golang:<GoVersion> when empty; an -alpine variant is used
+	Runtime      string            // Image of the final stage (alpine or distroless), alpine when empty
+	MultiStage   bool              // Copy the binary onto the runtime image instead of running it in the builder
+	NonRoot      bool              // Run as an unprivileged user
+	BuildArgs    map[string]string // ARGs of the build stage with their defaults, set with docker build --build-arg
+	Port         int               // Port to EXPOSE; none when zero
+	HealthCheck  bool              // Add a HEALTHCHECK requesting HealthPath on Port
+	HealthPath   string
+}
+
+// Generator handles Dockerfile generation
+type Generator struct {
+	templateEngine templates.TemplateRenderer
+}
+
+// NewGenerator creates a new Dockerfile generator
+func NewGenerator() *Generator {
+	return &Generator{
+		templateEngine: templates.NewEngine(),
+	}
+}
+
+// ParseConfig reads the docker section of a blueprint: base_image, runtime,
+// multi_stage, non_root, build_args, expose, health_check and health_path.
+// Builds are multi-stage and run as an unprivileged user unless the section
+// says otherwise. Project details are left for the caller.
+func ParseConfig(docker map[string]any) (Config, error) {
+	config := Config{
+		Runtime:    RuntimeAlpine,
+		MultiStage: true,
+		NonRoot:    true,
+		HealthPath: DefaultHealthPath,
+	}
+
+	if value, ok := docker["base_image"]; ok {
+		image, ok := value.(string)
+		if !ok || image == "" || strings.ContainsAny(image, " \t\r\n") {
+			return Config{}, fmt.Errorf("docker base_image must be an image reference")
+		}
+		config.BuilderImage = image
+	}
+	if value, ok := docker["runtime"]; ok {
+		runtime, ok := value.(string)
+		runtime = strings.ToLower(strings.TrimSpace(runtime))
+		if !ok || !slices.Contains(Runtimes, runtime) {
+			return Config{}, fmt.Errorf("unknown docker runtime %v (valid: %s)", value, strings.Join(Runtimes, ", "))
+		}
+		config.Runtime = runtime
+	}
+	for _, field := range []struct {
+		name  string
+		value *bool
+	}{
+		{"multi_stage", &config.MultiStage},
+		{"non_root", &config.NonRoot},
+		{"health_check", &config.HealthCheck},
+	} {
+		if value, ok := docker[field.name]; ok {
+			flag, ok := value.(bool)
+			if !ok {
+				return Config{}, fmt.Errorf("docker %s must be true or false, not %v", field.name, value)
+			}
+			*field.value = flag
+		}
+	}
+	if value, ok := docker["health_path"]; ok {
+		path, ok := value.(string)
+		if !ok || !strings.HasPrefix(path, "/") || strings.ContainsAny(path, " \t\r\n") {
+			return Config{}, fmt.Errorf("docker health_path must be an absolute URL path")
+		}
+		config.HealthPath = path
+	}
+
+	if value, ok := docker["expose"]; ok {
+		port, ok := value.(int)
+		// Blueprints stored in the database come back from JSON with float numbers
+		if f, isFloat := value.(float64); isFloat && f == float64(int(f)) {
+			port, ok = int(f), true
+		}
+		if !ok || port < 1 || port > 65535 {
+			return Config{}, fmt.Errorf("docker expose must be a port between 1 and 65535, not %v", value)
+		}
+		config.Port = port
+	}
+
+	var err error
+	if config.BuildArgs, err = ParseBuildArgs(docker["build_args"]); err != nil {
+		return Config{}, err
+	}
+
+	if config.Runtime == RuntimeDistroless && !config.MultiStage {
+		return Config{}, fmt.Errorf("docker runtime %s needs multi_stage", RuntimeDistroless)
+	}
+	if config.HealthCheck {
+		if config.Port == 0 {
+			return Config{}, fmt.Errorf("docker health_check needs the port in expose")
+		}
+		if config.Runtime == RuntimeDistroless {
+			return Config{}, fmt.Errorf("docker health_check needs the %s runtime; %s images have no shell or wget to run it", RuntimeAlpine, RuntimeDistroless)
+		}
+	}
+	return config, nil
+}
+
+// ParseBuildArgs reads docker.build_args from a blueprint, a map of build
+// argument names to their defaults
+func ParseBuildArgs(value any) (map[string]string, error) {
+	if value == nil {
+		return nil, nil
+	}
+	values, ok := value.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("docker build_args must be a map, got %T", value)
+	}
+
+	args := make(map[string]string, len(values))
+	for name, value := range values {
+		if !argNamePattern.MatchString(name) {
+			return nil, fmt.Errorf("invalid docker build arg name %q", name)
+		}
+		text := ""
+		if value != nil {
+			text = fmt.Sprint(value)
+		}
+		if strings.ContainsAny(text, "\r\n") {
+			return nil, fmt.Errorf("docker build arg %s must be a single line", name)
+		}
+		args[name] = text
+	}
+	return args, nil
+}
+
+// Files returns the project-relative paths written by Generate
+func Files() []string {
+	return []string{DockerfilePath}
+}
+
+// dockerfileTemplate builds the binary in the alpine variant of the Go image
+// and runs it there, or on the runtime image of a multi-stage build
+const dockerfileTemplate = `{% if MultiStage %}# Build stage
+FROM {{ BuilderImage }} AS builder
+{% else %}FROM {{ BuilderImage }}
+{% endif %}{% for arg in BuildArgs %}ARG {{ arg.Name }}{% if arg.Value %}="{{ arg.Value|safe }}"{% endif %}
+{% endfor %}
+WORKDIR /app
+COPY go.mod go.sum ./
+RUN go mod download
+
+COPY . .
+RUN CGO_ENABLED=0 GOOS=linux go build -o {{ ProjectName }} ./cmd/{{ ProjectName }}
+{% if MultiStage %}
+# Final stage
+{% if IsDistroless %}FROM gcr.io/distroless/static-debian12{% if NonRoot %}:nonroot{% endif %}
+{% else %}FROM alpine:latest
+RUN apk --no-cache add ca-certificates
+{% endif %}WORKDIR /app
+
+COPY --from=builder /app/{{ ProjectName }} .
+{% endif %}{% if NonRoot %}{% if IsDistroless %}
+# The nonroot user of the distroless image
+USER 65532:65532
+{% else %}
+RUN addgroup -S -g 10001 app && adduser -S -D -H -u 10001 -G app app
+USER 10001:10001
+{% endif %}{% endif %}{% if Port %}
+EXPOSE {{ Port }}
+{% endif %}{% if HealthCheck %}
+HEALTHCHECK --interval=30s --timeout=3s --start-period=5s --retries=3 \
+  CMD wget -q -O /dev/null http://localhost:{{ Port }}{{ HealthPath }} || exit 1
+{% endif %}
+CMD ["./{{ ProjectName }}"]
+`
+
+// Generate generates the project's Dockerfile
+func (g *Generator) Generate(ctx context.Context, outputDir string, config Config) error {
+	outputPath := filepath.Join(outputDir, DockerfilePath)
+	return g.templateEngine.RenderToFile(ctx, dockerfileTemplate, g.variables(config), outputPath)
+}
+
+// variables returns the template variables of config, with its defaults
+func (g *Generator) variables(config Config) map[string]any {
+	goVersion := config.GoVersion
+	if goVersion == "" {
+		goVersion = "1.25.1"
+	}
+	builderImage := config.BuilderImage
+	if builderImage == "" {
+		builderImage = "golang:" + goVersion
+	}
+	// wget, adduser and the shell of the single stage come from alpine
+	if !strings.Contains(builderImage, "alpine") {
+		builderImage += "-alpine"
+	}
+
+	names := make([]string, 0, len(config.BuildArgs))
+	for name := range config.BuildArgs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	buildArgs := make([]map[string]string, 0, len(names))
+	for _, name := range names {
+		value := strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(config.BuildArgs[name])
+		buildArgs = append(buildArgs, map[string]string{"Name": name, "Value": value})
+	}
+
+	healthPath := config.HealthPath
+	if healthPath == "" {
+		healthPath = DefaultHealthPath
+	}
+
+	return map[string]any{
+		"ProjectName":  config.ProjectName,
+		"BuilderImage": builderImage,
+		"MultiStage":   config.MultiStage,
+		"IsDistroless": config.MultiStage && config.Runtime == RuntimeDistroless,
+		"NonRoot":      config.NonRoot,
+		"BuildArgs":    buildArgs,
+		"Port":         config.Port,
+		"HealthCheck":  config.HealthCheck && config.Port > 0,
+		"HealthPath":   healthPath,
+	}
+}
@@ -0,0 +1,132 @@
+package docker
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		docker  map[string]any
+		want    Config
+		wantErr string
+	}{
+		{
+			name:   "defaults",
+			docker: map[string]any{"base_image": "golang:1.25.1"},
+			want:   Config{BuilderImage: "golang:1.25.1", Runtime: RuntimeAlpine, MultiStage: true, NonRoot: true, HealthPath: DefaultHealthPath},
+		},
+		{
+			name: "all options",
+			docker: map[string]any{
+				"runtime":     "Distroless",
+				"non_root":    false,
+				"expose":      float64(9000),
+				"build_args":  map[string]any{"VERSION": "dev", "COMMIT": nil},
+				"health_path": "/ready",
+			},
+			want: Config{
+				Runtime:    RuntimeDistroless,
+				MultiStage: true,
+				Port:       9000,
+				BuildArgs:  map[string]string{"VERSION": "dev", "COMMIT": ""},
+				HealthPath: "/ready",
+			},
+		},
+		{name: "unknown runtime", docker: map[string]any{"runtime": "scratch"}, wantErr: "unknown docker runtime"},
+		{name: "flag not bool", docker: map[string]any{"multi_stage": "yes"}, wantErr: "multi_stage must be true or false"},
+		{name: "bad port", docker: map[string]any{"expose": 80.5}, wantErr: "docker expose must be a port"},
+		{name: "bad build arg", docker: map[string]any{"build_args": map[string]any{"1X": "a"}}, wantErr: "invalid docker build arg name"},
+		{name: "single stage distroless", docker: map[string]any{"runtime": "distroless", "multi_stage": false}, wantErr: "needs multi_stage"},
+		{name: "health check without port", docker: map[string]any{"health_check": true}, wantErr: "needs the port"},
+		{name: "distroless health check", docker: map[string]any{"runtime": "distroless", "expose": 8080, "health_check": true}, wantErr: "needs the alpine runtime"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, err := ParseConfig(tt.docker)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, config)
+		})
+	}
+}
+
+func TestGenerator_Generate(t *testing.T) {
+	tests := []struct {
+		name       string
+		config     Config
+		contains   []string
+		notContain []string
+	}{
+		{
+			name: "alpine with health check",
+			config: Config{
+				ProjectName: "orders",
+				GoVersion:   "1.25.1",
+				Runtime:     RuntimeAlpine,
+				MultiStage:  true,
+				NonRoot:     true,
+				BuildArgs:   map[string]string{"VERSION": `1.0 "beta"`, "COMMIT": ""},
+				Port:        8080,
+				HealthCheck: true,
+			},
+			contains: []string{
+				"FROM golang:1.25.1-alpine AS builder\nARG COMMIT\nARG VERSION=\"1.0 \\\"beta\\\"\"\n",
+				"go build -o orders ./cmd/orders",
+				"FROM alpine:latest",
+				"COPY --from=builder /app/orders .",
+				"USER 10001:10001",
+				"EXPOSE 8080",
+				"wget -q -O /dev/null http://localhost:8080/health",
+				`CMD ["./orders"]`,
+			},
+		},
+		{
+			name: "distroless",
+			config: Config{
+				ProjectName:  "orders",
+				BuilderImage: "golang:1.24-alpine",
+				Runtime:      RuntimeDistroless,
+				MultiStage:   true,
+				NonRoot:      true,
+			},
+			contains:   []string{"FROM golang:1.24-alpine AS builder", "FROM gcr.io/distroless/static-debian12:nonroot", "USER 65532:65532"},
+			notContain: []string{"EXPOSE", "HEALTHCHECK", "adduser"},
+		},
+		{
+			name:       "single stage as root",
+			config:     Config{ProjectName: "orders", GoVersion: "1.25.1", Runtime: RuntimeAlpine},
+			contains:   []string{"FROM golang:1.25.1-alpine\n"},
+			notContain: []string{"AS builder", "COPY --from", "USER"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			require.NoError(t, NewGenerator().Generate(context.Background(), tempDir, tt.config))
+			for _, file := range Files() {
+				assert.FileExists(t, filepath.Join(tempDir, file))
+			}
+
+			dockerfile, err := os.ReadFile(filepath.Join(tempDir, DockerfilePath))
+			require.NoError(t, err)
+			for _, want := range tt.contains {
+				assert.Contains(t, string(dockerfile), want)
+			}
+			for _, unwanted := range tt.notContain {
+				assert.NotContains(t, string(dockerfile), unwanted)
+			}
+		})
+	}
+}
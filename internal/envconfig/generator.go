@@ -0,0 +1,365 @@
+package envconfig
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/user/gogo/internal/templates"
+)
+
+// Config represents environment configuration generation options
+type Config struct {
+	ProjectName   string
+	ModuleName    string
+	Environments  []string
+	Port          int
+	HasDatabase   bool
+	DatabaseType  string
+	HasKubernetes bool
+	Replicas      map[string]int // Per-environment replica counts for kustomize overlays
+}
+
+// Generator handles environment-specific configuration generation
+type Generator struct {
+	templateEngine templates.TemplateRenderer
+}
+
+// NewGenerator creates a new environment configuration generator
+func NewGenerator() *Generator {
+	return &Generator{
+		templateEngine: templates.NewEngine(),
+	}
+}
+
+// DefaultEnvironments are used when a blueprint enables environments without listing them
+var DefaultEnvironments = []string{"dev", "staging", "prod"}
+
+// Files returns the project-relative paths written by GenerateAll for config
+func Files(config Config) []string {
+	config = withDefaults(config)
+
+	files := []string{"config/base.yaml", "internal/config/config.go"}
+	for _, env := range config.Environments {
+		files = append(files, "config/"+env+".yaml")
+	}
+	if config.HasKubernetes {
+		files = append(files,
+			"deploy/k8s/base/kustomization.yaml",
+			"deploy/k8s/base/deployment.yaml",
+			"deploy/k8s/base/service.yaml",
+		)
+		for _, env := range config.Environments {
+			files = append(files, "deploy/k8s/overlays/"+env+"/kustomization.yaml")
+		}
+	}
+	return files
+}
+
+// GenerateAll generates config overlays, the loader, and kustomize overlays when enabled
+func (g *Generator) GenerateAll(ctx context.Context, outputDir string, config Config) error {
+	config = withDefaults(config)
+
+	if err := g.GenerateConfigFiles(ctx, outputDir, config); err != nil {
+		return fmt.Errorf("failed to generate config overlays: %w", err)
+	}
+
+	if err := g.GenerateLoader(ctx, outputDir, config); err != nil {
+		return fmt.Errorf("failed to generate config loader: %w", err)
+	}
+
+	if config.HasKubernetes {
+		if err := g.GenerateKustomize(ctx, outputDir, config); err != nil {
+			return fmt.Errorf("failed to generate kustomize overlays: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// GenerateConfigFiles generates config/base.yaml and one overlay per environment
+func (g *Generator) GenerateConfigFiles(ctx context.Context, outputDir string, config Config) error {
+	baseTemplate := `# Base configuration shared by every environment.
+# Values in config/<environment>.yaml are merged on top of this file.
+server:
+  port: {{ Port }}
+  read_timeout: 15s
+  write_timeout: 15s
+
+log:
+  level: info
+  format: text
+{% if HasDatabase %}
+database:
+  driver: {{ DatabaseType }}
+  url: ""
+  max_open_conns: 10
+{% endif %}`
+
+	if err := g.templateEngine.RenderToFile(ctx, baseTemplate, g.variables(config, ""), filepath.Join(outputDir, "config", "base.yaml")); err != nil {
+		return err
+	}
+
+	overlayTemplate := `# {{ Environment }} overrides for config/base.yaml
+environment: {{ Environment }}
+
+log:
+  level: {{ LogLevel }}
+  format: {{ LogFormat }}
+{% if HasDatabase %}
+database:
+  max_open_conns: {{ MaxOpenConns }}
+{% endif %}`
+
+	for _, env := range config.Environments {
+		outputPath := filepath.Join(outputDir, "config", env+".yaml")
+		if err := g.templateEngine.RenderToFile(ctx, overlayTemplate, g.variables(config, env), outputPath); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GenerateLoader generates internal/config/config.go which merges base and environment overlays
+func (g *Generator) GenerateLoader(ctx context.Context, outputDir string, config Config) error {
+	template := `package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultEnvironment is used when APP_ENV is not set
+const DefaultEnvironment = "{{ DefaultEnvironment }}"
+
+// Config holds the application configuration
+type Config struct {
+	Environment string         ` + "`yaml:\"environment\"`" + `
+	Server      ServerConfig   ` + "`yaml:\"server\"`" + `
+	Log         LogConfig      ` + "`yaml:\"log\"`" + `
+{% if HasDatabase %}	Database    DatabaseConfig ` + "`yaml:\"database\"`" + `
+{% endif %}}
+
+// ServerConfig holds HTTP server settings
+type ServerConfig struct {
+	Port         int           ` + "`yaml:\"port\"`" + `
+	ReadTimeout  time.Duration ` + "`yaml:\"read_timeout\"`" + `
+	WriteTimeout time.Duration ` + "`yaml:\"write_timeout\"`" + `
+}
+
+// LogConfig holds logging settings
+type LogConfig struct {
+	Level  string ` + "`yaml:\"level\"`" + `
+	Format string ` + "`yaml:\"format\"`" + `
+}
+{% if HasDatabase %}
+// DatabaseConfig holds database settings
+type DatabaseConfig struct {
+	Driver       string ` + "`yaml:\"driver\"`" + `
+	URL          string ` + "`yaml:\"url\"`" + `
+	MaxOpenConns int    ` + "`yaml:\"max_open_conns\"`" + `
+}
+{% endif %}
+// Load reads dir/base.yaml and merges dir/<env>.yaml on top of it.
+// When env is empty, APP_ENV is used, falling back to DefaultEnvironment.
+func Load(dir, env string) (*Config, error) {
+	if env == "" {
+		env = os.Getenv("APP_ENV")
+	}
+	if env == "" {
+		env = DefaultEnvironment
+	}
+
+	base, err := readYAML(filepath.Join(dir, "base.yaml"))
+	if err != nil {
+		return nil, err
+	}
+
+	overlay, err := readYAML(filepath.Join(dir, env+".yaml"))
+	if err != nil {
+		return nil, err
+	}
+
+	merged, err := yaml.Marshal(merge(base, overlay))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode merged config: %w", err)
+	}
+
+	cfg := &Config{Environment: env}
+	if err := yaml.Unmarshal(merged, cfg); err != nil {
+		return nil, fmt.Errorf("failed to decode config for %s: %w", env, err)
+	}
+
+	return cfg, nil
+}
+
+func readYAML(path string) (map[string]any, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	values := map[string]any{}
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return values, nil
+}
+
+// merge deep-merges overlay into base, with overlay values taking precedence
+func merge(base, overlay map[string]any) map[string]any {
+	for key, value := range overlay {
+		if overlayMap, ok := value.(map[string]any); ok {
+			if baseMap, ok := base[key].(map[string]any); ok {
+				base[key] = merge(baseMap, overlayMap)
+				continue
+			}
+		}
+		base[key] = value
+	}
+	return base
+}
+`
+
+	outputPath := filepath.Join(outputDir, "internal", "config", "config.go")
+	return g.templateEngine.RenderToFile(ctx, template, g.variables(config, ""), outputPath)
+}
+
+// GenerateKustomize generates a kustomize base and one overlay per environment
+func (g *Generator) GenerateKustomize(ctx context.Context, outputDir string, config Config) error {
+	baseDir := filepath.Join(outputDir, "deploy", "k8s", "base")
+
+	baseFiles := map[string]string{
+		"kustomization.yaml": `apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+
+labels:
+  - pairs:
+      app: {{ ProjectName }}
+    includeSelectors: true
+
+resources:
+  - deployment.yaml
+  - service.yaml
+`,
+		"deployment.yaml": `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: {{ ProjectName }}
+spec:
+  replicas: 1
+  selector:
+    matchLabels:
+      app: {{ ProjectName }}
+  template:
+    metadata:
+      labels:
+        app: {{ ProjectName }}
+    spec:
+      containers:
+        - name: {{ ProjectName }}
+          image: {{ ProjectName }}:latest
+          ports:
+            - containerPort: {{ Port }}
+          envFrom:
+            - configMapRef:
+                name: {{ ProjectName }}-env
+`,
+		"service.yaml": `apiVersion: v1
+kind: Service
+metadata:
+  name: {{ ProjectName }}
+spec:
+  selector:
+    app: {{ ProjectName }}
+  ports:
+    - port: 80
+      targetPort: {{ Port }}
+`,
+	}
+
+	for name, template := range baseFiles {
+		if err := g.templateEngine.RenderToFile(ctx, template, g.variables(config, ""), filepath.Join(baseDir, name)); err != nil {
+			return err
+		}
+	}
+
+	overlayTemplate := `apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+
+namespace: {{ ProjectName }}-{{ Environment }}
+
+resources:
+  - ../../base
+
+configMapGenerator:
+  - name: {{ ProjectName }}-env
+    literals:
+      - APP_ENV={{ Environment }}
+
+replicas:
+  - name: {{ ProjectName }}
+    count: {{ Replicas }}
+`
+
+	for _, env := range config.Environments {
+		outputPath := filepath.Join(outputDir, "deploy", "k8s", "overlays", env, "kustomization.yaml")
+		if err := g.templateEngine.RenderToFile(ctx, overlayTemplate, g.variables(config, env), outputPath); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// variables builds template variables for an environment (empty for shared files)
+func (g *Generator) variables(config Config, env string) map[string]any {
+	variables := map[string]any{
+		"ProjectName":        config.ProjectName,
+		"ModuleName":         config.ModuleName,
+		"Port":               config.Port,
+		"HasDatabase":        config.HasDatabase,
+		"DatabaseType":       config.DatabaseType,
+		"DefaultEnvironment": config.Environments[0],
+		"Environment":        env,
+		"LogLevel":           "info",
+		"LogFormat":          "json",
+		"MaxOpenConns":       10,
+		"Replicas":           1,
+	}
+
+	switch env {
+	case "dev", "development", "local":
+		variables["LogLevel"] = "debug"
+		variables["LogFormat"] = "text"
+		variables["MaxOpenConns"] = 5
+	case "prod", "production":
+		variables["LogLevel"] = "warn"
+		variables["MaxOpenConns"] = 25
+		variables["Replicas"] = 3
+	}
+
+	if replicas, ok := config.Replicas[env]; ok {
+		variables["Replicas"] = replicas
+	}
+
+	return variables
+}
+
+func withDefaults(config Config) Config {
+	if len(config.Environments) == 0 {
+		config.Environments = DefaultEnvironments
+	}
+	if config.Port == 0 {
+		config.Port = 8080
+	}
+	if config.DatabaseType == "" {
+		config.DatabaseType = "postgres"
+	}
+	return config
+}
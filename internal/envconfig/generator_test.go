@@ -0,0 +1,66 @@
+package envconfig
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerator_GenerateAll(t *testing.T) {
+	tempDir := t.TempDir()
+	config := Config{
+		ProjectName:   "orders",
+		ModuleName:    "github.com/acme/orders",
+		Environments:  []string{"dev", "staging", "prod"},
+		HasDatabase:   true,
+		HasKubernetes: true,
+	}
+
+	require.NoError(t, NewGenerator().GenerateAll(context.Background(), tempDir, config))
+
+	for _, file := range Files(config) {
+		assert.FileExists(t, filepath.Join(tempDir, file))
+	}
+
+	prod, err := os.ReadFile(filepath.Join(tempDir, "config", "prod.yaml"))
+	require.NoError(t, err)
+	assert.Contains(t, string(prod), "environment: prod")
+	assert.Contains(t, string(prod), "level: warn")
+
+	loader, err := os.ReadFile(filepath.Join(tempDir, "internal", "config", "config.go"))
+	require.NoError(t, err)
+	assert.Contains(t, string(loader), `const DefaultEnvironment = "dev"`)
+	assert.Contains(t, string(loader), "DatabaseConfig")
+
+	overlay, err := os.ReadFile(filepath.Join(tempDir, "deploy", "k8s", "overlays", "prod", "kustomization.yaml"))
+	require.NoError(t, err)
+	assert.Contains(t, string(overlay), "namespace: orders-prod")
+	assert.Contains(t, string(overlay), "count: 3")
+}
+
+func TestGenerator_GenerateAll_WithoutKubernetes(t *testing.T) {
+	tempDir := t.TempDir()
+	config := Config{ProjectName: "cli", Environments: []string{"local", "ci"}}
+
+	require.NoError(t, NewGenerator().GenerateAll(context.Background(), tempDir, config))
+
+	assert.FileExists(t, filepath.Join(tempDir, "config", "local.yaml"))
+	assert.FileExists(t, filepath.Join(tempDir, "config", "ci.yaml"))
+	assert.NoDirExists(t, filepath.Join(tempDir, "deploy"))
+
+	loader, err := os.ReadFile(filepath.Join(tempDir, "internal", "config", "config.go"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(loader), "DatabaseConfig")
+}
+
+func TestFiles_DefaultEnvironments(t *testing.T) {
+	files := Files(Config{ProjectName: "svc"})
+	assert.Contains(t, files, "config/dev.yaml")
+	assert.Contains(t, files, "config/staging.yaml")
+	assert.Contains(t, files, "config/prod.yaml")
+	assert.NotContains(t, files, "deploy/k8s/base/kustomization.yaml")
+}
@@ -0,0 +1,122 @@
+// Package events is gogo's internal event bus. Managers publish lifecycle
+// events to it, and cross-cutting features such as the audit log and hooks
+// subscribe to them instead of being called from each manager.
+package events
+
+import (
+	"context"
+	"log/slog"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Kind identifies a lifecycle event as <entity>.<what happened>
+type Kind string
+
+// Lifecycle events published by gogo's managers
+const (
+	ProjectGenerated   Kind = "project.generated"   // gogo init wrote a project
+	ComponentGenerated Kind = "component.generated" // gogo generate or add wrote a component
+	MigrationApplied   Kind = "migration.applied"   // gogo db migrate applied a migration
+	BackupCompleted    Kind = "backup.completed"    // gogo db backup wrote a backup
+)
+
+// Kinds lists every event kind, in lifecycle order
+var Kinds = []Kind{ProjectGenerated, ComponentGenerated, MigrationApplied, BackupCompleted}
+
+// Event is a lifecycle event
+type Event struct {
+	Kind    Kind
+	Subject string         // What the event is about: a project, component, migration ID or backup path
+	Dir     string         // Project directory of project and component events; made absolute by Publish
+	Details map[string]any // Event-specific values
+	Time    time.Time      // Set by Publish when zero
+}
+
+// Entity names what the event is about the way the audit log does, as in
+// project:shop
+func (e Event) Entity() string {
+	entity, _, _ := strings.Cut(string(e.Kind), ".")
+	return entity + ":" + e.Subject
+}
+
+// Action is what happened, the part of the kind after the entity
+func (e Event) Action() string {
+	_, action, _ := strings.Cut(string(e.Kind), ".")
+	return action
+}
+
+// Handler reacts to an event
+type Handler func(ctx context.Context, event Event) error
+
+type subscription struct {
+	kinds   []Kind
+	handler Handler
+}
+
+// Bus delivers published events to their subscribers. A nil Bus drops
+// events, so managers publish unconditionally.
+type Bus struct {
+	mu            sync.RWMutex
+	subscriptions []subscription
+	onError       func(event Event, err error)
+}
+
+// NewBus creates an event bus. Handler errors are logged until OnError
+// sets another way to report them.
+func NewBus() *Bus {
+	return &Bus{
+		onError: func(event Event, err error) {
+			slog.Warn("event handler failed", "event", event.Kind, "subject", event.Subject, "error", err)
+		},
+	}
+}
+
+// Subscribe calls handler for events of the given kinds, or for every event
+// when no kind is given. Handlers run in the order they subscribed.
+func (b *Bus) Subscribe(handler Handler, kinds ...Kind) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subscriptions = append(b.subscriptions, subscription{kinds: kinds, handler: handler})
+}
+
+// OnError sets how handler errors are reported
+func (b *Bus) OnError(fn func(event Event, err error)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.onError = fn
+}
+
+// Publish delivers event to its subscribers synchronously. A failing
+// handler is reported and does not stop the others: what the event
+// announces has already happened.
+func (b *Bus) Publish(ctx context.Context, event Event) {
+	if b == nil {
+		return
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	if event.Dir != "" {
+		if dir, err := filepath.Abs(event.Dir); err == nil {
+			event.Dir = dir
+		}
+	}
+
+	b.mu.RLock()
+	subscriptions := slices.Clone(b.subscriptions)
+	onError := b.onError
+	b.mu.RUnlock()
+
+	for _, sub := range subscriptions {
+		if len(sub.kinds) > 0 && !slices.Contains(sub.kinds, event.Kind) {
+			continue
+		}
+		if err := sub.handler(ctx, event); err != nil && onError != nil {
+			onError(event, err)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBus_Publish(t *testing.T) {
+	bus := NewBus()
+	var got []string
+	bus.Subscribe(func(ctx context.Context, event Event) error {
+		got = append(got, "all:"+event.Subject)
+		return nil
+	})
+	bus.Subscribe(func(ctx context.Context, event Event) error {
+		got = append(got, "backup:"+event.Subject)
+		return nil
+	}, BackupCompleted)
+
+	bus.Publish(context.Background(), Event{Kind: ProjectGenerated, Subject: "shop"})
+	bus.Publish(context.Background(), Event{Kind: BackupCompleted, Subject: "backup.db"})
+
+	assert.Equal(t, []string{"all:shop", "all:backup.db", "backup:backup.db"}, got)
+}
+
+func TestBus_PublishReportsErrors(t *testing.T) {
+	bus := NewBus()
+	var reported []error
+	bus.OnError(func(event Event, err error) {
+		reported = append(reported, err)
+	})
+	failure := errors.New("hook failed")
+	bus.Subscribe(func(ctx context.Context, event Event) error {
+		return failure
+	})
+	called := false
+	bus.Subscribe(func(ctx context.Context, event Event) error {
+		called = true
+		assert.False(t, event.Time.IsZero())
+		assert.True(t, filepath.IsAbs(event.Dir))
+		return nil
+	})
+
+	bus.Publish(context.Background(), Event{Kind: ComponentGenerated, Subject: "handler/user", Dir: "."})
+
+	assert.Equal(t, []error{failure}, reported)
+	assert.True(t, called, "a failing handler must not stop the others")
+}
+
+func TestBus_NilDropsEvents(t *testing.T) {
+	var bus *Bus
+	assert.NotPanics(t, func() {
+		bus.Publish(context.Background(), Event{Kind: ProjectGenerated})
+	})
+}
+
+func TestEvent_EntityAndAction(t *testing.T) {
+	event := Event{Kind: ComponentGenerated, Subject: "handler/user"}
+	assert.Equal(t, "component:handler/user", event.Entity())
+	assert.Equal(t, "generated", event.Action())
+}
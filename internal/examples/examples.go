@@ -0,0 +1,140 @@
+// Package examples renders canonical example projects for each template and
+// blueprint so users can inspect what gogo would generate before answering
+// the init wizard.
+package examples
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/user/gogo/internal/generator"
+	"github.com/user/gogo/internal/templates"
+	"gopkg.in/yaml.v3"
+)
+
+// ExamplesFile is the embedded list of examples
+const ExamplesFile = "examples.yaml"
+
+//go:embed examples.yaml
+var examplesFS embed.FS
+
+// Example describes the answers used to render one example project
+type Example struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+	Template    string `yaml:"template"`
+	Blueprint   string `yaml:"blueprint,omitempty"`
+}
+
+// ProjectName is the project name the example is rendered with
+func (e Example) ProjectName() string {
+	return "example-" + e.Name
+}
+
+// ModuleName is the module path the example is rendered with
+func (e Example) ModuleName() string {
+	return "github.com/example/" + e.ProjectName()
+}
+
+// Result describes a materialized example
+type Result struct {
+	Example Example
+	Dir     string
+	Files   []string // paths relative to Dir, sorted
+}
+
+// FS returns the embedded example definitions
+func FS() fs.FS {
+	return examplesFS
+}
+
+// List returns every example sorted by name
+func List() ([]Example, error) {
+	data, err := fs.ReadFile(examplesFS, ExamplesFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var list []Example
+	if err := yaml.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", ExamplesFile, err)
+	}
+
+	seen := make(map[string]bool, len(list))
+	for _, example := range list {
+		if example.Name == "" || example.Template == "" {
+			return nil, fmt.Errorf("%s: every example needs a name and a template", ExamplesFile)
+		}
+		if seen[example.Name] {
+			return nil, fmt.Errorf("%s: duplicate example %q", ExamplesFile, example.Name)
+		}
+		seen[example.Name] = true
+	}
+
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list, nil
+}
+
+// Get returns the example with the given name
+func Get(name string) (Example, error) {
+	list, err := List()
+	if err != nil {
+		return Example{}, err
+	}
+	for _, example := range list {
+		if example.Name == name {
+			return example, nil
+		}
+	}
+	return Example{}, fmt.Errorf("example not found: %s", name)
+}
+
+// Materialize renders the named example into dir, which must be empty or
+// not exist yet, exactly as gogo init would with the example's answers
+func Materialize(ctx context.Context, name, dir string) (*Result, error) {
+	example, err := Get(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if entries, err := os.ReadDir(dir); err == nil && len(entries) > 0 {
+		return nil, fmt.Errorf("output directory %s is not empty", dir)
+	}
+
+	gen := generator.NewProjectGenerator(templates.NewEngine(), templates.NewRepository())
+	_, err = gen.InitProject(ctx, generator.InitOptions{
+		ProjectName: example.ProjectName(),
+		ModuleName:  example.ModuleName(),
+		Template:    example.Template,
+		Blueprint:   example.Blueprint,
+		Author:      "Example Author",
+		Description: example.Description,
+		OutputDir:   dir,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render example %s: %w", name, err)
+	}
+
+	result := &Result{Example: example, Dir: dir}
+	err = filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		result.Files = append(result.Files, filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list example files: %w", err)
+	}
+	sort.Strings(result.Files)
+	return result, nil
+}
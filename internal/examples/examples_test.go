@@ -0,0 +1,55 @@
+package examples
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestList(t *testing.T) {
+	list, err := List()
+	require.NoError(t, err)
+	require.NotEmpty(t, list)
+
+	for i := 1; i < len(list); i++ {
+		assert.Less(t, list[i-1].Name, list[i].Name, "examples are sorted by name")
+	}
+
+	_, err = Get("api")
+	assert.NoError(t, err)
+	_, err = Get("nonexistent")
+	assert.ErrorContains(t, err, "example not found")
+}
+
+func TestMaterialize_AllExamples(t *testing.T) {
+	list, err := List()
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	for _, example := range list {
+		t.Run(example.Name, func(t *testing.T) {
+			dir := filepath.Join(t.TempDir(), example.Name)
+			result, err := Materialize(ctx, example.Name, dir)
+			require.NoError(t, err)
+
+			assert.Equal(t, dir, result.Dir)
+			assert.Contains(t, result.Files, "go.mod")
+
+			goMod, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+			require.NoError(t, err)
+			assert.Contains(t, string(goMod), example.ModuleName())
+		})
+	}
+}
+
+func TestMaterialize_NonEmptyDir(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "keep.txt"), []byte("keep"), 0644))
+
+	_, err := Materialize(context.Background(), "cli", dir)
+	assert.ErrorContains(t, err, "not empty")
+}
@@ -0,0 +1,201 @@
+// Package fixtures runs template test fixtures. A fixtures file lists the
+// variable combinations a template source must render and build with, such
+// as gin with postgres, chi with sqlite and no database at all, so the
+// conditional branches a single golden render never takes are exercised in
+// the CI of a template repository.
+package fixtures
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Case is one combination of template, blueprint and variables
+type Case struct {
+	Name      string         `yaml:"name"`
+	Template  string         `yaml:"template,omitempty"`
+	Blueprint string         `yaml:"blueprint,omitempty"`
+	Variables map[string]any `yaml:"variables,omitempty"`
+	GoVersion string         `yaml:"go_version,omitempty"` // Go version of the rendered go.mod; gogo's default when empty
+	Build     *bool          `yaml:"build,omitempty"`      // defaults to true
+}
+
+// ShouldBuild reports whether the rendered project is built
+func (c Case) ShouldBuild() bool {
+	return c.Build == nil || *c.Build
+}
+
+// Matrix expands into one case per combination of variable values
+type Matrix struct {
+	Template  string           `yaml:"template,omitempty"`
+	Blueprint string           `yaml:"blueprint,omitempty"`
+	Variables map[string][]any `yaml:"variables"`
+	Exclude   []map[string]any `yaml:"exclude,omitempty"` // combinations to skip; unlisted variables match any value
+}
+
+// Fixtures is the content of a fixtures file
+type Fixtures struct {
+	Template  string         `yaml:"template,omitempty"`  // default template of every case
+	Variables map[string]any `yaml:"variables,omitempty"` // shared by every case; case values win
+	Cases     []Case         `yaml:"cases,omitempty"`
+	Matrix    *Matrix        `yaml:"matrix,omitempty"`
+}
+
+// Load reads a fixtures file
+func Load(path string) (*Fixtures, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixtures: %w", err)
+	}
+	fixtures, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return fixtures, nil
+}
+
+// Parse parses a fixtures file
+func Parse(data []byte) (*Fixtures, error) {
+	var fixtures Fixtures
+	decoder := yaml.NewDecoder(strings.NewReader(string(data)))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&fixtures); err != nil {
+		return nil, fmt.Errorf("failed to parse fixtures: %w", err)
+	}
+	return &fixtures, nil
+}
+
+// Expand returns the explicit cases followed by the matrix combinations,
+// with the shared template and variables applied. defaultTemplate is used
+// for cases naming no template, e.g. the only template of a source.
+func (f *Fixtures) Expand(defaultTemplate string) ([]Case, error) {
+	cases := make([]Case, 0, len(f.Cases))
+	cases = append(cases, f.Cases...)
+	if f.Matrix != nil {
+		combinations, err := f.Matrix.combinations()
+		if err != nil {
+			return nil, err
+		}
+		cases = append(cases, combinations...)
+	}
+	if len(cases) == 0 {
+		return nil, fmt.Errorf("fixtures list no cases")
+	}
+
+	seen := make(map[string]bool, len(cases))
+	for i, c := range cases {
+		if c.Name == "" {
+			return nil, fmt.Errorf("case %d has no name", i+1)
+		}
+		if seen[c.Name] {
+			return nil, fmt.Errorf("duplicate case %q", c.Name)
+		}
+		seen[c.Name] = true
+
+		if c.Template == "" {
+			c.Template = f.Template
+		}
+		if c.Template == "" {
+			c.Template = defaultTemplate
+		}
+		if c.Template == "" {
+			return nil, fmt.Errorf("case %q names no template", c.Name)
+		}
+
+		variables := make(map[string]any, len(f.Variables)+len(c.Variables))
+		for name, value := range f.Variables {
+			variables[name] = value
+		}
+		for name, value := range c.Variables {
+			variables[name] = value
+		}
+		c.Variables = variables
+		cases[i] = c
+	}
+	return cases, nil
+}
+
+// Select returns the cases whose names match pattern, a regular expression
+func Select(cases []Case, pattern string) ([]Case, error) {
+	if pattern == "" {
+		return cases, nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid case pattern: %w", err)
+	}
+	var selected []Case
+	for _, c := range cases {
+		if re.MatchString(c.Name) {
+			selected = append(selected, c)
+		}
+	}
+	return selected, nil
+}
+
+// combinations expands the matrix in the sorted order of its variables.
+// Cases are named after their values, e.g. Database=sqlite,Framework=chi.
+func (m *Matrix) combinations() ([]Case, error) {
+	names := make([]string, 0, len(m.Variables))
+	for name, values := range m.Variables {
+		if len(values) == 0 {
+			return nil, fmt.Errorf("matrix variable %s has no values", name)
+		}
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("matrix has no variables")
+	}
+	sort.Strings(names)
+
+	var cases []Case
+	var expand func(depth int, variables map[string]any, labels []string)
+	expand = func(depth int, variables map[string]any, labels []string) {
+		if depth == len(names) {
+			if m.excluded(variables) {
+				return
+			}
+			combination := make(map[string]any, len(variables))
+			for name, value := range variables {
+				combination[name] = value
+			}
+			cases = append(cases, Case{
+				Name:      strings.Join(labels, ","),
+				Template:  m.Template,
+				Blueprint: m.Blueprint,
+				Variables: combination,
+			})
+			return
+		}
+		name := names[depth]
+		for _, value := range m.Variables[name] {
+			variables[name] = value
+			expand(depth+1, variables, append(labels, fmt.Sprintf("%s=%v", name, value)))
+		}
+	}
+	expand(0, make(map[string]any, len(names)), nil)
+	return cases, nil
+}
+
+// excluded reports whether an exclude entry matches every one of its
+// variables in the combination
+func (m *Matrix) excluded(variables map[string]any) bool {
+	for _, exclude := range m.Exclude {
+		matches := true
+		for name, value := range exclude {
+			if fmt.Sprint(variables[name]) != fmt.Sprint(value) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,106 @@
+package fixtures
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sampleFixtures = `
+template: api
+variables:
+  Author: CI
+  HasDatabase: true
+cases:
+  - name: gin-postgres
+    variables: {Components: [gin], DatabaseType: postgres}
+  - name: no-db
+    template: cli
+    variables: {HasDatabase: false}
+    build: false
+matrix:
+  variables:
+    Framework: [gin, chi]
+    DatabaseType: [postgres, sqlite]
+  exclude:
+    - {Framework: chi, DatabaseType: postgres}
+`
+
+func TestFixtures_Expand(t *testing.T) {
+	fixtures, err := Parse([]byte(sampleFixtures))
+	require.NoError(t, err)
+
+	cases, err := fixtures.Expand("")
+	require.NoError(t, err)
+
+	var names []string
+	for _, c := range cases {
+		names = append(names, c.Name)
+	}
+	assert.Equal(t, []string{
+		"gin-postgres",
+		"no-db",
+		"DatabaseType=postgres,Framework=gin",
+		"DatabaseType=sqlite,Framework=gin",
+		"DatabaseType=sqlite,Framework=chi",
+	}, names)
+
+	assert.Equal(t, "api", cases[0].Template, "the shared template applies")
+	assert.Equal(t, map[string]any{"Author": "CI", "HasDatabase": true, "Components": []any{"gin"}, "DatabaseType": "postgres"}, cases[0].Variables)
+	assert.True(t, cases[0].ShouldBuild())
+
+	assert.Equal(t, "cli", cases[1].Template)
+	assert.Equal(t, false, cases[1].Variables["HasDatabase"], "case values win over shared ones")
+	assert.False(t, cases[1].ShouldBuild())
+
+	assert.Equal(t, map[string]any{"Author": "CI", "HasDatabase": true, "Framework": "chi", "DatabaseType": "sqlite"}, cases[4].Variables)
+}
+
+func TestFixtures_ExpandErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		fixtures string
+		want     string
+	}{
+		{"no cases", "template: api\n", "no cases"},
+		{"unnamed case", "template: api\ncases:\n  - variables: {A: 1}\n", "case 1 has no name"},
+		{"duplicate case", "template: api\ncases:\n  - name: a\n  - name: a\n", `duplicate case "a"`},
+		{"no template", "cases:\n  - name: a\n", `case "a" names no template`},
+		{"empty matrix variable", "template: api\nmatrix:\n  variables:\n    A: []\n", "matrix variable A has no values"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fixtures, err := Parse([]byte(tt.fixtures))
+			require.NoError(t, err)
+			_, err = fixtures.Expand("")
+			assert.ErrorContains(t, err, tt.want)
+		})
+	}
+
+	_, err := Parse([]byte("cases:\n  - name: a\n    vars: {A: 1}\n"))
+	assert.ErrorContains(t, err, "field vars not found", "typos are reported")
+}
+
+func TestFixtures_ExpandDefaultTemplate(t *testing.T) {
+	fixtures, err := Parse([]byte("cases:\n  - name: a\n"))
+	require.NoError(t, err)
+	cases, err := fixtures.Expand("worker")
+	require.NoError(t, err)
+	assert.Equal(t, "worker", cases[0].Template)
+}
+
+func TestSelect(t *testing.T) {
+	cases := []Case{{Name: "gin-postgres"}, {Name: "chi-sqlite"}, {Name: "no-db"}}
+
+	selected, err := Select(cases, "postgres|sqlite")
+	require.NoError(t, err)
+	assert.Len(t, selected, 2)
+
+	selected, err = Select(cases, "")
+	require.NoError(t, err)
+	assert.Len(t, selected, 3)
+
+	_, err = Select(cases, "(")
+	assert.ErrorContains(t, err, "invalid case pattern")
+}
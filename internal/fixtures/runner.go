@@ -0,0 +1,185 @@
+package fixtures
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/user/gogo/internal/blueprints"
+	"github.com/user/gogo/internal/generator"
+	"github.com/user/gogo/internal/templates"
+)
+
+// Project name and module path every case is rendered with
+const (
+	ProjectName = "fixture"
+	ModuleName  = "example.com/fixture"
+)
+
+// buildCommands check that a rendered project compiles
+var buildCommands = [][]string{
+	{"go", "mod", "tidy"},
+	{"go", "build", "./..."},
+}
+
+// Result describes one case run
+type Result struct {
+	Case     Case
+	Dir      string // where the case was rendered
+	Files    int
+	Built    bool   // false when the case skips the build or has no go.mod
+	Output   string // output of the failed build command
+	Err      error
+	Duration time.Duration
+}
+
+// Runner renders cases from a template source: a single template, or a pack
+// with templates/<name>/ directories and blueprints/<name>.yaml files. The
+// source's templates and blueprints take precedence over built-in ones of
+// the same name, as they would once installed.
+type Runner struct {
+	generator       *generator.Generator
+	defaultTemplate string
+	goCommand       string // go binary builds run with; go on PATH when empty
+}
+
+// NewRunner creates a runner for the template source at dir
+func NewRunner(dir string) (*Runner, error) {
+	files, err := templates.ReadPackFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	store := sourceStore{}
+	blueprintRepo := blueprints.NewRepository()
+	for _, file := range files {
+		top, rest, _ := strings.Cut(file.Path, "/")
+		switch top {
+		case templates.PackTemplatesDir:
+			name, filePath, ok := strings.Cut(rest, "/")
+			if !ok {
+				continue
+			}
+			store[name] = append(store[name], templates.TemplateFile{Name: path.Base(filePath), Path: filePath, Content: file.Content})
+		case templates.PackBlueprintsDir:
+			ext := path.Ext(rest)
+			if strings.Contains(rest, "/") || (ext != ".yaml" && ext != ".yml" && ext != ".json") {
+				continue
+			}
+			blueprint, err := blueprints.Parse([]byte(file.Content))
+			if err != nil {
+				return nil, fmt.Errorf("invalid blueprint %s: %w", file.Path, err)
+			}
+			if err := blueprints.NewValidator().Validate(blueprint); err != nil {
+				return nil, fmt.Errorf("invalid blueprint %s: %w", file.Path, err)
+			}
+			blueprintRepo.Register(blueprint)
+		}
+	}
+
+	runner := &Runner{}
+	if len(store) == 0 {
+		// A single template, named after its directory
+		runner.defaultTemplate = filepath.Base(filepath.Clean(dir))
+		store[runner.defaultTemplate] = files
+	} else if len(store) == 1 {
+		for name := range store {
+			runner.defaultTemplate = name
+		}
+	}
+
+	repo := templates.NewRepository()
+	repo.SetStore(store)
+	runner.generator = generator.NewProjectGenerator(templates.NewEngine(), repo)
+	runner.generator.SetBlueprintRepository(blueprintRepo)
+	return runner, nil
+}
+
+// DefaultTemplate returns the template of cases naming none: the only
+// template of the source, or empty for packs with several
+func (r *Runner) DefaultTemplate() string {
+	return r.defaultTemplate
+}
+
+// WithGo returns a runner building with the go binary at goCommand, such as
+// the go of an older release, instead of go on PATH. The toolchain is used
+// as is: it does not switch to the release the go.mod asks for.
+func (r *Runner) WithGo(goCommand string) *Runner {
+	runner := *r
+	runner.goCommand = goCommand
+	return &runner
+}
+
+// Run renders c into a new directory under workDir and builds it unless the
+// case opts out or build is false
+func (r *Runner) Run(ctx context.Context, c Case, workDir string, build bool) (result Result) {
+	start := time.Now()
+	result = Result{Case: c, Dir: filepath.Join(workDir, caseDir(c.Name))}
+	defer func() { result.Duration = time.Since(start) }()
+
+	generated, err := r.generator.InitProject(ctx, generator.InitOptions{
+		ProjectName: ProjectName,
+		ModuleName:  ModuleName,
+		Template:    c.Template,
+		Blueprint:   c.Blueprint,
+		Author:      "Fixture Author",
+		GoVersion:   c.GoVersion,
+		OutputDir:   result.Dir,
+		Variables:   c.Variables,
+	})
+	if err != nil {
+		result.Err = fmt.Errorf("render failed: %w", err)
+		return result
+	}
+	result.Files = generated.FilesCreated
+
+	if !build || !c.ShouldBuild() {
+		return result
+	}
+	if _, err := os.Stat(filepath.Join(result.Dir, "go.mod")); err != nil {
+		return result
+	}
+	for _, args := range buildCommands {
+		name := args[0]
+		if r.goCommand != "" && name == "go" {
+			name = r.goCommand
+		}
+		cmd := exec.CommandContext(ctx, name, args[1:]...)
+		cmd.Dir = result.Dir
+		// The rendered project stands alone, even inside a workspace
+		cmd.Env = append(os.Environ(), "GOWORK=off")
+		if r.goCommand != "" {
+			cmd.Env = append(cmd.Env, "GOTOOLCHAIN=local")
+		}
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			result.Output = string(out)
+			result.Err = fmt.Errorf("%s failed: %w", strings.Join(args, " "), err)
+			return result
+		}
+	}
+	result.Built = true
+	return result
+}
+
+// unsafeDirChars are replaced in directory names derived from case names
+var unsafeDirChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+func caseDir(name string) string {
+	return strings.Trim(unsafeDirChars.ReplaceAllString(name, "-"), "-.")
+}
+
+// sourceStore serves the templates of the source under test
+type sourceStore map[string][]templates.TemplateFile
+
+// StoredTemplateFiles implements templates.Store
+func (s sourceStore) StoredTemplateFiles(ctx context.Context, name string) ([]templates.TemplateFile, bool, error) {
+	files, ok := s[name]
+	return files, ok, nil
+}
@@ -0,0 +1,107 @@
+package fixtures
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/user/gogo/internal/templates"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+}
+
+// workerTemplate only compiles with a database; the else branch is broken
+const workerTemplate = `package main
+
+import "fmt"
+
+func main() {
+{% if HasDatabase %}
+	fmt.Println("{{ DatabaseType }}")
+{% else %}
+	fmt.Println("none"
+{% endif %}
+}
+`
+
+func TestRunner_SingleTemplate(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go is not installed")
+	}
+
+	source := filepath.Join(t.TempDir(), "worker")
+	writeFiles(t, source, map[string]string{
+		"go.mod.tmpl":          "module {{ ModuleName }}\n\ngo 1.22\n",
+		"main.go.tmpl":         workerTemplate,
+		templates.FixturesFile: "cases:\n  - name: db\n    variables: {HasDatabase: true, DatabaseType: sqlite}\n  - name: no-db\n",
+	})
+
+	runner, err := NewRunner(source)
+	require.NoError(t, err)
+	assert.Equal(t, "worker", runner.DefaultTemplate())
+
+	fixtures, err := Load(filepath.Join(source, templates.FixturesFile))
+	require.NoError(t, err)
+	cases, err := fixtures.Expand(runner.DefaultTemplate())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	workDir := t.TempDir()
+
+	result := runner.Run(ctx, cases[0], workDir, true)
+	require.NoError(t, result.Err)
+	assert.True(t, result.Built)
+	assert.Equal(t, filepath.Join(workDir, "db"), result.Dir)
+	assert.NoFileExists(t, filepath.Join(result.Dir, templates.FixturesFile), "the fixtures are not a template file")
+
+	result = runner.Run(ctx, cases[1], workDir, true)
+	assert.ErrorContains(t, result.Err, "go build ./... failed")
+	assert.Contains(t, result.Output, "syntax error")
+
+	// Rendering alone does not catch it
+	result = runner.Run(ctx, cases[1], t.TempDir(), false)
+	assert.NoError(t, result.Err)
+	assert.False(t, result.Built)
+}
+
+func TestRunner_Pack(t *testing.T) {
+	source := t.TempDir()
+	writeFiles(t, source, map[string]string{
+		"templates/worker/main.go.tmpl": "package main // {{ Queue }}\n",
+		"templates/cron/main.go.tmpl":   "package main\n",
+		"blueprints/queue-stack.yaml":   "name: queue-stack\nstack: custom\nconfig:\n  components: [nats]\n",
+	})
+
+	runner, err := NewRunner(source)
+	require.NoError(t, err)
+	assert.Empty(t, runner.DefaultTemplate(), "a pack with several templates has no default")
+
+	result := runner.Run(context.Background(), Case{
+		Name:      "worker nats",
+		Template:  "worker",
+		Blueprint: "queue-stack",
+		Variables: map[string]any{"Queue": "orders"},
+	}, t.TempDir(), true)
+	require.NoError(t, result.Err)
+	assert.False(t, result.Built, "nothing to build without a go.mod")
+	assert.Equal(t, "worker-nats", filepath.Base(result.Dir))
+
+	content, err := os.ReadFile(filepath.Join(result.Dir, "main.go"))
+	require.NoError(t, err)
+	assert.Equal(t, "package main // orders\n", string(content))
+
+	writeFiles(t, source, map[string]string{"blueprints/broken.yaml": "name: broken\nstack: custom\nconfig:\n  components: [gni]\n"})
+	_, err = NewRunner(source)
+	assert.ErrorContains(t, err, `did you mean "gin"?`)
+}
@@ -0,0 +1,118 @@
+// Package fsutil writes files atomically. Content goes to a temporary file
+// next to the destination, is flushed to disk and then renamed over it, so a
+// reader, or a later run after the process was killed, sees either the old
+// file or the complete new one and never a half-written file.
+package fsutil
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// tempPattern names the temporary files; the leading dot keeps them out of
+// globs and directory listings most tools show
+const tempPattern = ".%s.tmp-*"
+
+// File is a file being written atomically. Nothing is visible at its path
+// until Commit; Close without Commit discards what was written, so
+//
+//	f, err := fsutil.Create(path, 0644)
+//	if err != nil { ... }
+//	defer f.Close()
+//	... write to f ...
+//	return f.Commit()
+//
+// leaves the destination untouched on every error path.
+type File struct {
+	*os.File
+	path      string
+	perm      os.FileMode
+	committed bool
+	closed    bool
+}
+
+// Create starts an atomic write of path. The directory of path must exist.
+func Create(path string, perm os.FileMode) (*File, error) {
+	temp, err := os.CreateTemp(filepath.Dir(path), fmt.Sprintf(tempPattern, filepath.Base(path)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	return &File{File: temp, path: path, perm: perm}, nil
+}
+
+// Path returns the destination path
+func (f *File) Path() string {
+	return f.path
+}
+
+// Commit flushes the content to disk and renames it over the destination
+func (f *File) Commit() error {
+	if f.closed {
+		return fmt.Errorf("failed to write %s: file already closed", f.path)
+	}
+	f.closed = true
+
+	err := f.File.Chmod(f.perm)
+	if err == nil {
+		err = f.File.Sync()
+	}
+	if closeErr := f.File.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(f.File.Name(), f.path)
+	}
+	if err != nil {
+		os.Remove(f.File.Name())
+		return fmt.Errorf("failed to write %s: %w", f.path, err)
+	}
+	f.committed = true
+
+	if err := SyncDir(filepath.Dir(f.path)); err != nil {
+		return fmt.Errorf("failed to write %s: %w", f.path, err)
+	}
+	return nil
+}
+
+// Close discards the content unless Commit succeeded. It is safe to call
+// more than once and after Commit.
+func (f *File) Close() error {
+	if f.closed {
+		return nil
+	}
+	f.closed = true
+	err := f.File.Close()
+	os.Remove(f.File.Name())
+	return err
+}
+
+// WriteFile atomically replaces path with data, like os.WriteFile. The
+// directory of path must exist.
+func WriteFile(path string, data []byte, perm os.FileMode) error {
+	f, err := Create(path, perm)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if _, err := f.Write(data); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return f.Commit()
+}
+
+// SyncDir flushes a directory entry to disk so a rename into it survives a
+// crash. Windows cannot sync directories; there it does nothing.
+func SyncDir(dir string) error {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
@@ -0,0 +1,90 @@
+package fsutil
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// entries lists the names in dir
+func entries(t *testing.T, dir string) []string {
+	list, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	names := make([]string, len(list))
+	for i, entry := range list {
+		names[i] = entry.Name()
+	}
+	return names
+}
+
+func TestWriteFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yml")
+	require.NoError(t, os.WriteFile(path, []byte("old\n"), 0600))
+
+	require.NoError(t, WriteFile(path, []byte("new\n"), 0644))
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "new\n", string(content))
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0644), info.Mode().Perm())
+	assert.Equal(t, []string{"config.yml"}, entries(t, dir), "no temporary files are left")
+}
+
+func TestWriteFile_MissingDirectory(t *testing.T) {
+	err := WriteFile(filepath.Join(t.TempDir(), "missing", "file"), []byte("x"), 0644)
+	assert.Error(t, err)
+}
+
+func TestFile_CloseWithoutCommit(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "export.sql")
+	require.NoError(t, os.WriteFile(path, []byte("previous export\n"), 0644))
+
+	write := func() error {
+		f, err := Create(path, 0644)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		fmt.Fprintln(f, "-- half an export")
+		return errors.New("query failed")
+	}
+	require.Error(t, write())
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "previous export\n", string(content), "the destination is untouched")
+	assert.Equal(t, []string{"export.sql"}, entries(t, dir))
+}
+
+func TestFile_Commit(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "backup.db")
+
+	f, err := Create(path, 0600)
+	require.NoError(t, err)
+	defer f.Close()
+	_, err = f.Write([]byte("data"))
+	require.NoError(t, err)
+
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err), "nothing is visible before Commit")
+
+	require.NoError(t, f.Commit())
+	require.NoError(t, f.Close(), "Close after Commit is a no-op")
+	assert.Error(t, f.Commit(), "a file commits once")
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "data", string(content))
+	assert.Equal(t, path, f.Path())
+}
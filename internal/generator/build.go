@@ -0,0 +1,116 @@
+package generator
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/user/gogo/internal/project"
+)
+
+// buildCommands check that a generated project compiles. go generate
+// produces code some stacks do not ship, such as gqlgen's server code.
+var buildCommands = [][]string{
+	{"go", "mod", "tidy"},
+	{"go", "generate", "./..."},
+	{"go", "build", "./..."},
+}
+
+// BuildError reports a generated project that does not build
+type BuildError struct {
+	Command string      // the command that failed, such as "go build ./..."
+	Files   []BuildFile // files the go command reported errors in
+	Output  string      // output of the failed command, with paths relative to the project
+	Err     error
+}
+
+// BuildFile is a generated file the go command reported an error in
+type BuildFile struct {
+	Path     string // relative to the project, slash-separated
+	Template string // the template file it was rendered from, if any
+}
+
+func (e *BuildError) Error() string {
+	msg := e.Command + " failed"
+	if len(e.Files) > 0 {
+		files := make([]string, len(e.Files))
+		for i, file := range e.Files {
+			files[i] = file.Path
+			if file.Template != "" && file.Template != file.Path {
+				files[i] += " (from template " + file.Template + ")"
+			}
+		}
+		msg += " in " + strings.Join(files, ", ")
+	}
+	if e.Output != "" {
+		msg += ":\n" + strings.TrimRight(e.Output, "\n")
+	}
+	return msg
+}
+
+func (e *BuildError) Unwrap() error { return e.Err }
+
+// verifyBuild runs go mod tidy, go generate and go build in dir. sources maps generated
+// paths to the template files they were rendered from, so failures name the
+// template to fix. Each command run is recorded in report.
+func verifyBuild(ctx context.Context, dir string, sources map[string]string, report *project.Report) error {
+	if _, err := os.Stat(filepath.Join(dir, "go.mod")); err != nil {
+		return fmt.Errorf("cannot verify build: the template generates no go.mod")
+	}
+	if _, err := exec.LookPath("go"); err != nil {
+		return fmt.Errorf("cannot verify build: go is not installed")
+	}
+
+	for _, args := range buildCommands {
+		start := time.Now()
+		cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+		cmd.Dir = dir
+		// The generated project stands alone, even inside a workspace
+		cmd.Env = append(os.Environ(), "GOWORK=off")
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			output := relativeOutput(string(out), dir)
+			return &BuildError{
+				Command: strings.Join(args, " "),
+				Files:   buildErrorFiles(output, sources),
+				Output:  output,
+				Err:     err,
+			}
+		}
+		report.Hook(strings.Join(args, " "), start)
+	}
+	return nil
+}
+
+// relativeOutput strips dir from the paths in go command output
+func relativeOutput(output, dir string) string {
+	for _, prefix := range []string{dir + string(filepath.Separator), dir} {
+		output = strings.ReplaceAll(output, prefix, "")
+	}
+	return output
+}
+
+// buildErrorPattern matches go command errors such as
+// ./internal/app/app.go:12:3: undefined: Run
+var buildErrorPattern = regexp.MustCompile(`(?m)^(?:\./)?([^\s:]+\.(?:go|mod)):\d+(?::\d+)?: `)
+
+// buildErrorFiles lists the files go command output reports errors in, in
+// the order they first appear
+func buildErrorFiles(output string, sources map[string]string) []BuildFile {
+	var files []BuildFile
+	seen := make(map[string]bool)
+	for _, match := range buildErrorPattern.FindAllStringSubmatch(output, -1) {
+		path := filepath.ToSlash(match[1])
+		if seen[path] {
+			continue
+		}
+		seen[path] = true
+		files = append(files, BuildFile{Path: path, Template: sources[path]})
+	}
+	return files
+}
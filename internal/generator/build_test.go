@@ -0,0 +1,89 @@
+package generator
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/user/gogo/internal/templates"
+)
+
+// buildStore serves a template that compiles and one that does not
+type buildStore struct{}
+
+func (buildStore) StoredTemplateFiles(ctx context.Context, name string) ([]templates.TemplateFile, bool, error) {
+	files := []templates.TemplateFile{
+		{Name: "go.mod", Path: "go.mod", Content: "module {{ ModuleName }}\n\ngo 1.21\n"},
+		{Name: "main.go", Path: "cmd/{{ ProjectName }}/main.go", Content: "package main\n\nfunc main() {}\n"},
+	}
+	switch name {
+	case "builds":
+		return files, true, nil
+	case "fails":
+		return append(files, templates.TemplateFile{
+			Name:    "app.go",
+			Path:    "internal/app/{{ ProjectName }}.go",
+			Content: "package app\n\nfunc Run() error {\n\treturn undefinedError\n}\n",
+		}), true, nil
+	}
+	return nil, false, nil
+}
+
+func newBuildGenerator() *Generator {
+	repo := templates.NewRepository()
+	repo.SetStore(buildStore{})
+	return NewProjectGenerator(templates.NewEngine(), repo)
+}
+
+func TestProjectGenerator_VerifyBuild(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go is not installed")
+	}
+	tempDir := t.TempDir()
+
+	result, err := newBuildGenerator().InitProject(context.Background(), InitOptions{
+		ProjectName: "svc",
+		ModuleName:  "example.com/svc",
+		Template:    "builds",
+		OutputDir:   filepath.Join(tempDir, "builds"),
+		VerifyBuild: true,
+	})
+	require.NoError(t, err)
+	assert.Contains(t, result.Message, "Verified the project builds")
+
+	opts := InitOptions{
+		ProjectName: "svc",
+		ModuleName:  "example.com/svc",
+		Template:    "fails",
+		OutputDir:   filepath.Join(tempDir, "fails"),
+		VerifyBuild: true,
+	}
+	_, err = newBuildGenerator().InitProject(context.Background(), opts)
+	var buildErr *BuildError
+	require.True(t, errors.As(err, &buildErr), "got %v", err)
+	assert.Equal(t, "go build ./...", buildErr.Command)
+	assert.Equal(t, []BuildFile{{Path: "internal/app/svc.go", Template: "internal/app/{{ ProjectName }}.go"}}, buildErr.Files)
+	assert.Contains(t, buildErr.Output, "undefined: undefinedError")
+	assert.NotContains(t, buildErr.Output, ".gogo-stage-", "paths are relative to the project")
+	assert.NoDirExists(t, opts.OutputDir, "a project that does not build is rolled back")
+}
+
+func TestBuildErrorFiles(t *testing.T) {
+	output := `# example.com/svc/internal/app
+internal/app/app.go:4:9: undefined: undefinedError
+internal/app/app.go:7:2: declared and not used: x
+./main.go:3:8: "fmt" imported and not used
+go: errors parsing go.mod:
+go.mod:3: unknown directive: requires
+`
+	files := buildErrorFiles(output, map[string]string{"main.go": "main.go.tmpl"})
+	assert.Equal(t, []BuildFile{
+		{Path: "internal/app/app.go"},
+		{Path: "main.go", Template: "main.go.tmpl"},
+		{Path: "go.mod"},
+	}, files)
+}
@@ -0,0 +1,154 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/user/gogo/internal/fsutil"
+	"github.com/user/gogo/internal/project"
+)
+
+// ConflictPolicy says what to do with a generated file when the output
+// directory already has a different file at the same path
+type ConflictPolicy string
+
+// Conflict policies
+const (
+	ConflictFail      ConflictPolicy = "fail"      // stop without writing anything
+	ConflictSkip      ConflictPolicy = "skip"      // keep the existing file
+	ConflictOverwrite ConflictPolicy = "overwrite" // replace the existing file
+	ConflictBackup    ConflictPolicy = "backup"    // replace it, keeping the old one as <file>.orig
+)
+
+// BackupSuffix is appended to existing files replaced under ConflictBackup
+const BackupSuffix = ".orig"
+
+// ConflictPolicies lists the valid policies
+var ConflictPolicies = []ConflictPolicy{ConflictFail, ConflictSkip, ConflictOverwrite, ConflictBackup}
+
+// ParseConflictPolicy parses a policy name; empty means ConflictFail
+func ParseConflictPolicy(name string) (ConflictPolicy, error) {
+	if name == "" {
+		return ConflictFail, nil
+	}
+	for _, policy := range ConflictPolicies {
+		if string(policy) == name {
+			return policy, nil
+		}
+	}
+	names := make([]string, len(ConflictPolicies))
+	for i, policy := range ConflictPolicies {
+		names[i] = string(policy)
+	}
+	return "", fmt.Errorf("unknown conflict policy %q (want %s)", name, strings.Join(names, ", "))
+}
+
+// Conflict is a generated file that differed from an existing one
+type Conflict struct {
+	Path   string         `json:"path"`   // slash-separated, relative to the output directory
+	Action ConflictPolicy `json:"action"` // what was done about it: skip, overwrite or backup
+}
+
+// ConflictError reports the conflicts that stopped a generation under
+// ConflictFail
+type ConflictError struct {
+	Paths []string
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("refusing to overwrite %d existing files: %s (use --force or --on-conflict)",
+		len(e.Paths), strings.Join(e.Paths, ", "))
+}
+
+// conflictPolicy returns the policy in effect for opts. Force without an
+// explicit policy overwrites, as it always has.
+func conflictPolicy(opts InitOptions) ConflictPolicy {
+	if opts.OnConflict != "" {
+		return opts.OnConflict
+	}
+	if opts.Force {
+		return ConflictOverwrite
+	}
+	return ConflictFail
+}
+
+// resolveConflicts compares every staged file with the one at the same path
+// in the output directory and applies the policy to those that differ.
+// Merged files and the manifest are gogo's to rewrite and never conflict.
+// Skipped files are removed from the stage.
+func resolveConflicts(s *stage, policy ConflictPolicy, merged []string) ([]Conflict, error) {
+	if s.fresh {
+		return nil, nil
+	}
+
+	exempt := map[string]bool{project.ManifestFile: true}
+	for _, path := range merged {
+		exempt[filepath.ToSlash(path)] = true
+	}
+
+	staged, err := s.files()
+	if err != nil {
+		return nil, err
+	}
+
+	var conflicts []Conflict
+	for _, rel := range staged {
+		slashed := filepath.ToSlash(rel)
+		if exempt[slashed] {
+			continue
+		}
+		existing, err := os.ReadFile(filepath.Join(s.outputDir, rel))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", rel, err)
+		}
+		generated, err := os.ReadFile(filepath.Join(s.dir, rel))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read staged %s: %w", rel, err)
+		}
+		if bytes.Equal(existing, generated) {
+			continue
+		}
+
+		switch policy {
+		case ConflictSkip:
+			if err := os.Remove(filepath.Join(s.dir, rel)); err != nil {
+				return nil, fmt.Errorf("failed to skip %s: %w", rel, err)
+			}
+		case ConflictBackup:
+			if err := fsutil.WriteFile(filepath.Join(s.dir, rel+BackupSuffix), existing, 0644); err != nil {
+				return nil, fmt.Errorf("failed to back up %s: %w", rel, err)
+			}
+		}
+		conflicts = append(conflicts, Conflict{Path: slashed, Action: policy})
+	}
+
+	sort.Slice(conflicts, func(i, j int) bool {
+		return conflicts[i].Path < conflicts[j].Path
+	})
+	if policy == ConflictFail && len(conflicts) > 0 {
+		paths := make([]string, len(conflicts))
+		for i, conflict := range conflicts {
+			paths[i] = conflict.Path
+		}
+		return conflicts, &ConflictError{Paths: paths}
+	}
+	return conflicts, nil
+}
+
+// skippedPaths returns the conflicts resolved by keeping the existing file
+func skippedPaths(conflicts []Conflict) map[string]bool {
+	skipped := make(map[string]bool)
+	for _, conflict := range conflicts {
+		if conflict.Action == ConflictSkip {
+			skipped[conflict.Path] = true
+		}
+	}
+	return skipped
+}
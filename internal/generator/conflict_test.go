@@ -0,0 +1,107 @@
+package generator
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/user/gogo/internal/project"
+	"github.com/user/gogo/internal/templates"
+)
+
+// conflictingProject generates a cli project, edits its main.go and returns
+// the options to generate it again
+func conflictingProject(t *testing.T) (InitOptions, string) {
+	opts := InitOptions{
+		ProjectName: "conflict",
+		ModuleName:  "github.com/user/conflict",
+		Template:    "cli",
+		OutputDir:   filepath.Join(t.TempDir(), "conflict"),
+	}
+	_, err := NewProjectGenerator(templates.NewEngine(), templates.NewRepository()).InitProject(context.Background(), opts)
+	require.NoError(t, err)
+
+	mainPath := filepath.Join(opts.OutputDir, "cmd", "conflict", "main.go")
+	require.NoError(t, os.WriteFile(mainPath, []byte("package edited\n"), 0644))
+	return opts, mainPath
+}
+
+func TestProjectGenerator_ConflictPolicies(t *testing.T) {
+	ctx := context.Background()
+	generator := NewProjectGenerator(templates.NewEngine(), templates.NewRepository())
+
+	t.Run("fail", func(t *testing.T) {
+		opts, mainPath := conflictingProject(t)
+		_, err := generator.InitProject(ctx, opts)
+
+		var conflictErr *ConflictError
+		require.True(t, errors.As(err, &conflictErr))
+		assert.Equal(t, []string{"cmd/conflict/main.go"}, conflictErr.Paths, "identical files do not conflict")
+		content, err := os.ReadFile(mainPath)
+		require.NoError(t, err)
+		assert.Equal(t, "package edited\n", string(content))
+	})
+
+	t.Run("skip", func(t *testing.T) {
+		opts, mainPath := conflictingProject(t)
+		opts.OnConflict = ConflictSkip
+		result, err := generator.InitProject(ctx, opts)
+		require.NoError(t, err)
+		assert.Equal(t, []Conflict{{Path: "cmd/conflict/main.go", Action: ConflictSkip}}, result.Conflicts)
+
+		content, err := os.ReadFile(mainPath)
+		require.NoError(t, err)
+		assert.Equal(t, "package edited\n", string(content))
+
+		manifest, err := project.LoadManifest(opts.OutputDir)
+		require.NoError(t, err)
+		for _, file := range manifest.Files {
+			assert.NotEqual(t, "cmd/conflict/main.go", file.Path, "skipped files are not tracked")
+		}
+		assert.NotEmpty(t, manifest.Files)
+	})
+
+	t.Run("overwrite with force", func(t *testing.T) {
+		opts, mainPath := conflictingProject(t)
+		opts.Force = true
+		result, err := generator.InitProject(ctx, opts)
+		require.NoError(t, err)
+		assert.Equal(t, []Conflict{{Path: "cmd/conflict/main.go", Action: ConflictOverwrite}}, result.Conflicts)
+
+		content, err := os.ReadFile(mainPath)
+		require.NoError(t, err)
+		assert.Contains(t, string(content), "package main")
+	})
+
+	t.Run("backup", func(t *testing.T) {
+		opts, mainPath := conflictingProject(t)
+		opts.OnConflict = ConflictBackup
+		result, err := generator.InitProject(ctx, opts)
+		require.NoError(t, err)
+		assert.Equal(t, []Conflict{{Path: "cmd/conflict/main.go", Action: ConflictBackup}}, result.Conflicts)
+
+		content, err := os.ReadFile(mainPath)
+		require.NoError(t, err)
+		assert.Contains(t, string(content), "package main")
+		backup, err := os.ReadFile(mainPath + BackupSuffix)
+		require.NoError(t, err)
+		assert.Equal(t, "package edited\n", string(backup))
+	})
+}
+
+func TestParseConflictPolicy(t *testing.T) {
+	policy, err := ParseConflictPolicy("")
+	require.NoError(t, err)
+	assert.Equal(t, ConflictFail, policy)
+
+	policy, err = ParseConflictPolicy("backup")
+	require.NoError(t, err)
+	assert.Equal(t, ConflictBackup, policy)
+
+	_, err = ParseConflictPolicy("merge")
+	assert.ErrorContains(t, err, `unknown conflict policy "merge"`)
+}
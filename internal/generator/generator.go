@@ -4,40 +4,91 @@ import (
 	"context"
 	"fmt"
 	"path/filepath"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/user/gogo/internal/blueprints"
 	"github.com/user/gogo/internal/cicd"
+	"github.com/user/gogo/internal/components"
+	"github.com/user/gogo/internal/deploy"
+	"github.com/user/gogo/internal/docker"
+	"github.com/user/gogo/internal/envconfig"
+	"github.com/user/gogo/internal/events"
 	"github.com/user/gogo/internal/git"
+	"github.com/user/gogo/internal/licenses"
+	"github.com/user/gogo/internal/monitoring"
+	"github.com/user/gogo/internal/policy"
+	"github.com/user/gogo/internal/project"
+	"github.com/user/gogo/internal/sdk"
+	"github.com/user/gogo/internal/state"
+	"github.com/user/gogo/internal/taskrunner"
 	"github.com/user/gogo/internal/templates"
 	"github.com/user/gogo/internal/validate"
 )
 
-// InitOptions contains options for project initialization
+// InitOptions contains options for project initialization. They are
+// recorded as JSON in the plans of gogo plan.
 type InitOptions struct {
-	ProjectName          string
-	ModuleName           string
-	Template             string
-	Blueprint            string // Blueprint name for enhanced stack support
-	Author               string
-	Email                string // Author email for git configuration
-	License              string
-	GoVersion            string
-	OutputDir            string
-	Description          string
-	GitInit              bool
-	GenerateCI           bool    // Generate CI/CD configurations
-	CoverageMin          float64 // Minimum test coverage percentage
-	InitialCommitMessage string  // Custom initial commit message
-	Force                bool
-	DryRun               bool
+	ProjectName          string              `json:"project_name,omitempty"`
+	ModuleName           string              `json:"module_name,omitempty"`
+	Template             string              `json:"template,omitempty"`
+	Blueprint            string              `json:"blueprint,omitempty"` // Blueprint name for enhanced stack support
+	Author               string              `json:"author,omitempty"`
+	Email                string              `json:"email,omitempty"` // Author email for git configuration
+	License              string              `json:"license,omitempty"`
+	GoVersion            string              `json:"go_version,omitempty"`
+	LanguageLevel        string              `json:"language_level,omitempty"` // Go version whose constructs generated code may use, such as 1.21; GoVersion's when empty
+	OutputDir            string              `json:"output_dir,omitempty"`
+	Description          string              `json:"description,omitempty"`
+	GitInit              bool                `json:"git_init,omitempty"`
+	GenerateCI           bool                `json:"generate_ci,omitempty"`            // Generate CI/CD configurations
+	CIProvider           string              `json:"ci_provider,omitempty"`            // CI provider of the pipeline (github, gitlab, circleci); github when empty
+	DependencyUpdates    string              `json:"dependency_updates,omitempty"`     // Dependency update bot configured with CI (dependabot, renovate); none when empty
+	GenerateTSClient     bool                `json:"generate_ts_client,omitempty"`     // Generate a TypeScript client SDK under clients/ts
+	CoverageMin          float64             `json:"coverage_min,omitempty"`           // Minimum test coverage percentage
+	CoverageExclude      []string            `json:"coverage_exclude,omitempty"`       // Code CI leaves out of coverage (generated, cmd, mocks or path patterns); the blueprint's when empty
+	LicenseReport        bool                `json:"license_report,omitempty"`         // Generate a third-party license check and report
+	AllowedLicenses      []string            `json:"allowed_licenses,omitempty"`       // SPDX identifiers the license check allows; defaults when empty
+	InitialCommitMessage string              `json:"initial_commit_message,omitempty"` // Custom initial commit message
+	CommitPolicy         policy.CommitPolicy `json:"commit_policy,omitempty"`          // Commit message template and trailers from the org policy
+	GogoVersion          string              `json:"gogo_version,omitempty"`           // Version of gogo recorded in the project manifest
+	Into                 bool                `json:"into,omitempty"`                   // Generate into the existing git repository at OutputDir
+	Branch               string              `json:"branch,omitempty"`                 // With Into, commit the generated files on this new branch
+	Variables            map[string]any      `json:"variables,omitempty"`              // Extra template variables, overriding blueprint values
+	Force                bool                `json:"force,omitempty"`
+	DryRun               bool                `json:"-"`
+	KeepPartial          bool                `json:"keep_partial,omitempty"` // On failure, keep the files rendered so far instead of rolling back
+	OnConflict           ConflictPolicy      `json:"on_conflict,omitempty"`  // What to do with existing files; defaults to fail, or overwrite with Force
+	VerifyBuild          bool                `json:"verify_build,omitempty"` // Run go mod tidy, go generate ./... and go build ./... on the generated project
+	Framework            string              `json:"framework,omitempty"`    // HTTP framework (gin, echo or chi), replacing the one among the blueprint's components
+	Omit                 []string            `json:"omit,omitempty"`         // Generated files to leave out, as slash-separated paths such as OptionalFiles
+	Report               bool                `json:"report,omitempty"`       // Write a machine-readable report of the run into .gogo/report.json
+	TaskRunner           string              `json:"task_runner,omitempty"`  // Task runner of the generated targets (make for a Makefile, task for a Taskfile.yml); make when empty
+	StoreState           bool                `json:"store_state,omitempty"`  // Move the manifest to the state store in use (see state.Use) instead of keeping it in the project
 }
 
+// Optional files a template may generate that projects can leave out
+const (
+	DockerfilePath = docker.DockerfilePath
+	ComposePath    = "docker-compose.yml"
+)
+
+// OptionalFiles lists the files InitOptions.Omit is meant for
+var OptionalFiles = []string{DockerfilePath, ComposePath}
+
 // Result contains the result of a generation operation
 type Result struct {
-	Success      bool
-	ProjectPath  string
-	FilesCreated int
-	Message      string
+	Success      bool       `json:"success"`
+	ProjectPath  string     `json:"project_path"`
+	FilesCreated int        `json:"files_created"`
+	FilesMerged  []string   `json:"files_merged,omitempty"` // existing files the generated content was merged into
+	Conflicts    []Conflict `json:"conflicts,omitempty"`    // existing files that differed, and what was done about them
+	Preview      *Preview   `json:"preview,omitempty"`      // with DryRun, the files that would be written
+	Message      string     `json:"message"`
+	NextSteps    []string   `json:"next_steps,omitempty"`  // what to do with the project, declared by the template and blueprint
+	ReportPath   string     `json:"report_path,omitempty"` // with Report, where the generation report was written
+	Warnings     []string   `json:"warnings,omitempty"`    // variables the templates never use and conditions never true, for verbose output
 }
 
 // ProjectGenerator interface for generating projects
@@ -51,6 +102,7 @@ type Generator struct {
 	templateRepository  *templates.Repository
 	blueprintRepository *blueprints.Repository
 	blueprintResolver   blueprints.BlueprintResolver
+	events              *events.Bus
 }
 
 // NewProjectGenerator creates a new project generator
@@ -63,6 +115,17 @@ func NewProjectGenerator(engine templates.TemplateRenderer, repo *templates.Repo
 	}
 }
 
+// SetBlueprintRepository sets the repository blueprints are resolved from,
+// such as one holding installed blueprints
+func (g *Generator) SetBlueprintRepository(repo *blueprints.Repository) {
+	g.blueprintRepository = repo
+}
+
+// SetEvents sets the bus ProjectGenerated is published on
+func (g *Generator) SetEvents(bus *events.Bus) {
+	g.events = bus
+}
+
 // InitProject initializes a new Go project
 func (g *Generator) InitProject(ctx context.Context, opts InitOptions) (Result, error) {
 	// Validate options
@@ -77,24 +140,51 @@ func (g *Generator) InitProject(ctx context.Context, opts InitOptions) (Result,
 	if opts.GoVersion == "" {
 		opts.GoVersion = "1.25.1"
 	}
+	if opts.LanguageLevel == "" {
+		opts.LanguageLevel = opts.GoVersion
+	}
+	// Validated above
+	opts.LanguageLevel, _ = templates.ParseLanguageLevel(opts.LanguageLevel)
 	if opts.License == "" {
 		opts.License = "MIT"
 	}
+	// Validated above
+	opts.TaskRunner, _ = taskrunner.ParseRunner(opts.TaskRunner)
 	if opts.Description == "" {
 		opts.Description = fmt.Sprintf("A %s project", opts.Template)
 	}
+	var report *project.Report
+	if opts.Report && !opts.DryRun {
+		report = project.NewReport("init")
+		report.GogoVersion = opts.GogoVersion
+		report.Template = opts.Template
+		report.Blueprint = opts.Blueprint
+	}
+	if opts.Into && !opts.DryRun {
+		if err := g.prepareInto(ctx, &opts); err != nil {
+			return Result{}, err
+		}
+	}
 
 	// Prepare base template variables
 	variables := map[string]any{
-		"ProjectName": opts.ProjectName,
-		"ModuleName":  opts.ModuleName,
-		"Author":      opts.Author,
-		"License":     opts.License,
-		"GoVersion":   opts.GoVersion,
-		"Description": opts.Description,
+		"ProjectName":   opts.ProjectName,
+		"ModuleName":    opts.ModuleName,
+		"Author":        opts.Author,
+		"License":       opts.License,
+		"GoVersion":     opts.GoVersion,
+		"Description":   opts.Description,
+		"LicenseReport": opts.LicenseReport,
+		"TaskRunner":    opts.TaskRunner,
+	}
+	for name, value := range templates.LanguageVariables(opts.LanguageLevel) {
+		variables[name] = value
 	}
 
 	var templateFiles []templates.TemplateFile
+	// Next steps of the template only apply when its own files are used
+	var blueprintSteps string
+	templateSteps := false
 
 	// Use blueprint if specified
 	if opts.Blueprint != "" {
@@ -103,12 +193,31 @@ func (g *Generator) InitProject(ctx context.Context, opts InitOptions) (Result,
 			return Result{}, fmt.Errorf("failed to get blueprint: %w", err)
 		}
 
+		// Reject malformed blueprints before anything is rendered
+		if err := blueprints.NewValidator().Validate(blueprint); err != nil {
+			return Result{}, fmt.Errorf("invalid blueprint %s: %w", blueprint.Name, err)
+		}
+
+		// Services need a long-running template
+		deployConfig, err := deploy.ParseConfig(blueprint.Config.Deployment)
+		if err != nil {
+			return Result{}, fmt.Errorf("invalid blueprint %s: %w", blueprint.Name, err)
+		}
+		if len(deployConfig.Targets) > 0 && !deploy.SupportsTemplate(opts.Template) {
+			return Result{}, fmt.Errorf("blueprint %s deploys as a service (%s), which the %s template does not support",
+				blueprint.Name, strings.Join(deployConfig.Targets, ", "), opts.Template)
+		}
+		if _, err := docker.ParseConfig(blueprint.Config.Docker); err != nil {
+			return Result{}, fmt.Errorf("invalid blueprint %s: %w", blueprint.Name, err)
+		}
+
 		// Resolve blueprint variables
 		resolvedVars, err := g.blueprintResolver.Resolve(ctx, blueprint, variables)
 		if err != nil {
 			return Result{}, fmt.Errorf("failed to resolve blueprint variables: %w", err)
 		}
 		variables = resolvedVars
+		blueprintSteps = blueprint.NextSteps
 
 		// Get blueprint-specific template files
 		blueprintTemplates := templates.GetBlueprintTemplates()
@@ -129,6 +238,7 @@ func (g *Generator) InitProject(ctx context.Context, opts InitOptions) (Result,
 				return Result{}, fmt.Errorf("failed to get template files: %w", err)
 			}
 			templateFiles = files
+			templateSteps = true
 		}
 	} else {
 		// Get regular template files
@@ -137,56 +247,455 @@ func (g *Generator) InitProject(ctx context.Context, opts InitOptions) (Result,
 			return Result{}, fmt.Errorf("failed to get template files: %w", err)
 		}
 		templateFiles = files
+		templateSteps = true
+	}
+
+	// Extra variables, such as those of template fixtures, override resolved ones
+	for name, value := range opts.Variables {
+		variables[name] = value
+	}
+
+	// Templates branch on the HTTP framework like component templates do
+	if opts.Framework != "" {
+		variables["Components"] = withFramework(componentList(variables), opts.Framework)
+	}
+	framework := pickComponent(variables, components.Frameworks)
+	variables["IsGin"] = framework == "gin"
+	variables["IsEcho"] = framework == "echo"
+	variables["IsChi"] = framework == "chi"
+	queue := pickComponent(variables, queues)
+	variables["IsAsynq"] = queue == "asynq"
+	variables["IsNATS"] = queue == "nats"
+	variables["IsKafka"] = queue == "kafka"
+
+	var stepSources []string
+	if templateSteps {
+		steps, err := g.templateRepository.GetNextSteps(ctx, opts.Template)
+		if err != nil {
+			return Result{}, fmt.Errorf("failed to get next steps: %w", err)
+		}
+		stepSources = append(stepSources, steps)
+	}
+	nextSteps, err := g.renderNextSteps(ctx, append(stepSources, blueprintSteps), variables)
+	if err != nil {
+		return Result{}, err
+	}
+
+	// Variables the templates never use and branches they never take, to help
+	// blueprint authors trim them. The generator uses a few variables itself
+	// and sets the framework and queue ones for every template.
+	usage := templates.NewUsage(variables)
+	usage.Refer("Components", "HasAudit", "HasPrometheus", "HasDatabase", "DatabaseType", "MigrationType", "CoverageMin", "CoverageExclude", "HasDocker", "IsGin", "IsEcho", "IsChi", "IsAsynq", "IsNATS", "IsKafka")
+	// Not every stack has code that depends on the language level
+	usage.Refer("LanguageLevel")
+	usage.Refer(templates.LanguageFeatures()...)
+	for _, templateFile := range templateFiles {
+		usage.Track(templateFile.Name, templateFile.Path)
+		usage.Track(templateFile.Name, templateFile.Content)
+	}
+	for _, source := range append(stepSources, blueprintSteps) {
+		usage.Track("next steps", source)
 	}
 
 	result := Result{
 		ProjectPath:  opts.OutputDir,
 		FilesCreated: len(templateFiles),
 		Success:      true,
+		Warnings:     usage.Warnings(),
 	}
 
-	// Dry run - just validate and return
+	// Dry run - render everything aside and compare it with the output directory
 	if opts.DryRun {
-		result.Message = fmt.Sprintf("Would create %d files in %s", len(templateFiles), opts.OutputDir)
+		preview, err := g.preview(ctx, opts)
+		if err != nil {
+			return Result{}, fmt.Errorf("failed to preview project: %w", err)
+		}
+		result.FilesCreated = len(preview.Files)
+		result.Preview = preview
+		result.Message = fmt.Sprintf("Would write %d files in %s (%d new, %d modified, %d merged, %d unchanged)",
+			len(preview.Files), opts.OutputDir, preview.Count(ChangeNew), preview.Count(ChangeModified),
+			preview.Count(ChangeMerged), preview.Count(ChangeUnchanged))
 		return result, nil
 	}
 
+	// Render everything into a staging directory first so a failure part way
+	// through leaves the output directory as it was
+	stage, err := newStage(opts.OutputDir)
+	if err != nil {
+		return Result{}, err
+	}
+	staged := opts
+	staged.OutputDir = stage.dir
+	renderStart := time.Now()
+
 	// Render and write each template file
+	renderedPaths := make([]string, 0, len(templateFiles))
+	sources := make(map[string]string, len(templateFiles))
 	for _, templateFile := range templateFiles {
 		// Render the file path template
 		renderedPath, err := g.templateEngine.RenderString(ctx, templateFile.Path, variables)
 		if err != nil {
-			return Result{}, fmt.Errorf("failed to render path template for %s: %w", templateFile.Name, err)
+			return Result{}, g.abort(stage, opts, fmt.Errorf("failed to render path template for %s: %w", templateFile.Name, templates.Named(err, templateFile.Path)))
+		}
+		if slices.Contains(opts.Omit, filepath.ToSlash(renderedPath)) {
+			result.FilesCreated--
+			continue
 		}
 
-		outputPath := filepath.Join(opts.OutputDir, renderedPath)
+		outputPath := filepath.Join(staged.OutputDir, renderedPath)
 
 		// Render the file content
-		err = g.templateEngine.RenderToFile(ctx, templateFile.Content, variables, outputPath)
+		if opts.Into {
+			merged, err := g.renderInto(ctx, templateFile, variables, filepath.Join(opts.OutputDir, renderedPath), outputPath)
+			if err != nil {
+				return Result{}, g.abort(stage, opts, fmt.Errorf("failed to render file %s: %w", templateFile.Name, templates.Named(err, templateFile.Name)))
+			}
+			if merged {
+				result.FilesMerged = append(result.FilesMerged, renderedPath)
+			}
+		} else {
+			err = g.templateEngine.RenderToFile(ctx, templateFile.Content, variables, outputPath)
+			if err != nil {
+				return Result{}, g.abort(stage, opts, fmt.Errorf("failed to render file %s: %w", templateFile.Name, templates.Named(err, templateFile.Name)))
+			}
+		}
+		renderedPaths = append(renderedPaths, renderedPath)
+		sources[filepath.ToSlash(renderedPath)] = templateFile.Path
+	}
+
+	// Generate CI/CD configurations if requested; git init brings them too
+	var cicdConfig *cicd.Config
+	if opts.GenerateCI || opts.GitInit {
+		config, err := g.generateCICD(ctx, staged, variables)
 		if err != nil {
-			return Result{}, fmt.Errorf("failed to render file %s: %w", templateFile.Name, err)
+			return Result{}, g.abort(stage, opts, fmt.Errorf("failed to generate CI/CD configurations: %w", err))
+		}
+		cicdConfig = config
+		renderedPaths = append(renderedPaths, cicd.Files(*config)...)
+		result.FilesCreated += len(cicd.Files(*config))
+	}
+
+	// Generate the audit trail when the blueprint enables observability.audit
+	auditFiles, err := g.generateAudit(ctx, staged, variables)
+	if err != nil {
+		return Result{}, g.abort(stage, opts, fmt.Errorf("failed to generate audit trail: %w", err))
+	}
+	renderedPaths = append(renderedPaths, auditFiles...)
+	result.FilesCreated += len(auditFiles)
+
+	// Generate environment config overlays when the blueprint declares environments
+	envFiles, err := g.generateEnvironments(ctx, staged)
+	if err != nil {
+		return Result{}, g.abort(stage, opts, fmt.Errorf("failed to generate environment configurations: %w", err))
+	}
+	renderedPaths = append(renderedPaths, envFiles...)
+	result.FilesCreated += len(envFiles)
+
+	// Generate the systemd unit and service wrapper for the blueprint's deployment targets
+	deployFiles, err := g.generateDeployment(ctx, staged)
+	if err != nil {
+		return Result{}, g.abort(stage, opts, fmt.Errorf("failed to generate deployment files: %w", err))
+	}
+	renderedPaths = append(renderedPaths, deployFiles...)
+	result.FilesCreated += len(deployFiles)
+
+	// Generate the Dockerfile when the blueprint has a docker section
+	dockerFiles, err := g.generateDocker(ctx, staged)
+	if err != nil {
+		return Result{}, g.abort(stage, opts, fmt.Errorf("failed to generate Dockerfile: %w", err))
+	}
+	renderedPaths = append(renderedPaths, dockerFiles...)
+	result.FilesCreated += len(dockerFiles)
+
+	// Generate the Makefile or Taskfile.yml with the targets of the stack
+	runnerFiles, err := g.generateTaskRunner(ctx, staged, variables, renderedPaths)
+	if err != nil {
+		return Result{}, g.abort(stage, opts, fmt.Errorf("failed to generate %s: %w", taskrunner.File(opts.TaskRunner), err))
+	}
+	renderedPaths = append(renderedPaths, runnerFiles...)
+	result.FilesCreated += len(runnerFiles)
+
+	// Generate the observability profile config for services that expose Prometheus metrics
+	monitoringFiles, err := g.generateMonitoring(ctx, staged, variables)
+	if err != nil {
+		return Result{}, g.abort(stage, opts, fmt.Errorf("failed to generate monitoring configuration: %w", err))
+	}
+	renderedPaths = append(renderedPaths, monitoringFiles...)
+	result.FilesCreated += len(monitoringFiles)
+
+	// Generate the third-party license check if requested or required by the org policy
+	if opts.LicenseReport {
+		licenseConfig := licenses.Config{
+			ProjectName: opts.ProjectName,
+			ModuleName:  opts.ModuleName,
+			Allowed:     opts.AllowedLicenses,
+		}
+		if err := licenses.NewGenerator().GenerateAll(ctx, staged.OutputDir, licenseConfig); err != nil {
+			return Result{}, g.abort(stage, opts, fmt.Errorf("failed to generate license check: %w", err))
 		}
+		renderedPaths = append(renderedPaths, licenses.Files()...)
+		result.FilesCreated += len(licenses.Files())
 	}
 
-	// Generate CI/CD configurations if requested
-	if opts.GenerateCI {
-		if err := g.generateCICD(ctx, opts, variables); err != nil {
-			return Result{}, fmt.Errorf("failed to generate CI/CD configurations: %w", err)
+	// Generate the TypeScript client SDK if requested
+	if opts.GenerateTSClient {
+		clientConfig := sdk.Config{
+			ProjectName: opts.ProjectName,
+			ModuleName:  opts.ModuleName,
+			Source:      sdk.SourceForTemplate(opts.Template),
 		}
-		result.FilesCreated += 3 // .golangci.yml, ci.yml, .pre-commit-config.yaml
+		if err := sdk.NewGenerator().GenerateTypeScript(ctx, staged.OutputDir, clientConfig); err != nil {
+			return Result{}, g.abort(stage, opts, fmt.Errorf("failed to generate TypeScript client: %w", err))
+		}
+		renderedPaths = append(renderedPaths, sdk.Files(clientConfig)...)
+		result.FilesCreated += len(sdk.Files(clientConfig))
+	}
+
+	report.Phase("render", renderStart)
+
+	// Decide what happens to the files the output directory already has
+	conflicts, err := resolveConflicts(stage, conflictPolicy(opts), result.FilesMerged)
+	if err != nil {
+		// Keeping partial files would overwrite the very files in conflict
+		stage.discard()
+		return Result{}, err
+	}
+	result.Conflicts = conflicts
+	for _, conflict := range conflicts {
+		switch conflict.Action {
+		case ConflictSkip:
+			report.Warn("kept existing %s", conflict.Path)
+		case ConflictBackup:
+			report.Warn("overwrote %s (previous version in %s%s)", conflict.Path, conflict.Path, BackupSuffix)
+		default:
+			report.Warn("overwrote %s", conflict.Path)
+		}
+	}
+	if skipped := skippedPaths(conflicts); len(skipped) > 0 {
+		kept := renderedPaths[:0]
+		for _, path := range renderedPaths {
+			if !skipped[filepath.ToSlash(path)] {
+				kept = append(kept, path)
+			}
+		}
+		renderedPaths = kept
+		result.FilesCreated -= len(skipped)
+	}
+
+	// Catch templates that render code which does not compile. Projects
+	// generated into an existing repository build with its other files, so
+	// they are checked once in place.
+	if opts.VerifyBuild && !opts.Into {
+		verifyStart := time.Now()
+		if err := verifyBuild(ctx, staged.OutputDir, sources, report); err != nil {
+			return Result{}, g.abort(stage, opts, err)
+		}
+		report.Phase("verify_build", verifyStart)
+	}
+
+	// Record how the project was generated so `gogo status` can report on it later
+	if err := g.writeManifest(staged, variables, cicdConfig, renderedPaths, nextSteps); err != nil {
+		return Result{}, g.abort(stage, opts, fmt.Errorf("failed to write project manifest: %w", err))
+	}
+
+	// Move the staged files into place
+	if err := stage.commit(); err != nil {
+		return Result{}, err
 	}
 
-	// Initialize git repository if requested
-	if opts.GitInit {
+	if opts.VerifyBuild && opts.Into {
+		verifyStart := time.Now()
+		if err := verifyBuild(ctx, opts.OutputDir, sources, report); err != nil {
+			return Result{}, fmt.Errorf("%w (generated files left uncommitted in %s)", err, opts.OutputDir)
+		}
+		report.Phase("verify_build", verifyStart)
+	}
+
+	// The org policy may keep the manifest out of the repository
+	tracked := append(renderedPaths, project.ManifestFile)
+	if opts.StoreState {
+		if err := state.Detach(opts.OutputDir, opts.ModuleName, project.ManifestFile); err != nil {
+			return Result{}, fmt.Errorf("failed to store project manifest: %w", err)
+		}
+		tracked = renderedPaths
+	}
+	if err := report.TrackFiles(opts.OutputDir, tracked); err != nil {
+		return Result{}, err
+	}
+
+	// Commit into the existing repository, or initialize one if requested
+	gitStart := time.Now()
+	if opts.Into {
+		if err := g.commitInto(ctx, opts, result); err != nil {
+			return Result{}, fmt.Errorf("failed to commit generated files: %w", err)
+		}
+		report.Hook("git commit", gitStart)
+		report.Phase("git", gitStart)
+	} else if opts.GitInit {
 		if err := g.initializeGit(ctx, opts); err != nil {
 			return Result{}, fmt.Errorf("failed to initialize git repository: %w", err)
 		}
+		report.Hook("git init", gitStart)
+		report.Phase("git", gitStart)
 	}
 
-	result.Message = g.buildResultMessage(opts, len(templateFiles))
+	g.events.Publish(ctx, events.Event{
+		Kind:    events.ProjectGenerated,
+		Subject: opts.ProjectName,
+		Dir:     opts.OutputDir,
+		Details: map[string]any{
+			"template":  opts.Template,
+			"blueprint": opts.Blueprint,
+			"module":    opts.ModuleName,
+			"files":     len(renderedPaths),
+		},
+	})
+
+	result.Message = g.buildResultMessage(opts, result.FilesCreated)
+	result.NextSteps = nextSteps
+	if len(monitoringFiles) > 0 {
+		result.Message += fmt.Sprintf("\nRun docker compose --profile %s up to see metrics and logs in Grafana at http://localhost:3000", monitoring.Profile)
+	}
+
+	// The report is written last so it covers the whole run, and is left out
+	// of the manifest and of any commit made above
+	if report != nil {
+		report.Variables = variables
+		path, err := report.Save(opts.OutputDir)
+		if err != nil {
+			return Result{}, err
+		}
+		result.ReportPath = path
+	}
 	return result, nil
 }
 
+// abort handles a failure after rendering has started. The staged files are
+// discarded, leaving the output directory as it was, unless opts.KeepPartial
+// asks for them to be moved into place for inspection.
+func (g *Generator) abort(s *stage, opts InitOptions, err error) error {
+	if !opts.KeepPartial {
+		s.discard()
+		return err
+	}
+	if commitErr := s.commit(); commitErr != nil {
+		return fmt.Errorf("%w (failed to keep partial files: %v)", err, commitErr)
+	}
+	return fmt.Errorf("%w (partial files kept in %s)", err, opts.OutputDir)
+}
+
+// generateDeployment generates a systemd unit and a service wrapper with
+// install/uninstall subcommands, Kubernetes manifests or a Helm chart when
+// the blueprint sets deployment.target, and cloud-init user data or an
+// Ansible role when it sets deployment.provision, returning the files written
+func (g *Generator) generateDeployment(ctx context.Context, opts InitOptions) ([]string, error) {
+	if opts.Blueprint == "" {
+		return nil, nil
+	}
+
+	blueprint, err := g.blueprintRepository.GetBlueprint(ctx, opts.Blueprint)
+	if err != nil {
+		return nil, nil
+	}
+	deployConfig, err := deploy.ParseConfig(blueprint.Config.Deployment)
+	if err != nil || len(deployConfig.Targets) == 0 {
+		return nil, err
+	}
+	deployConfig.ProjectName = opts.ProjectName
+	deployConfig.ModuleName = opts.ModuleName
+	deployConfig.Description = opts.Description
+
+	// Services run the last (production) environment of the config overlays
+	// unless the blueprint says otherwise
+	if environments := blueprint.Config.Environments; len(environments) > 0 && deployConfig.Env["APP_ENV"] == "" {
+		if deployConfig.Env == nil {
+			deployConfig.Env = make(map[string]string)
+		}
+		deployConfig.Env["APP_ENV"] = environments[len(environments)-1]
+	}
+	// Containers listen on the port the Dockerfile exposes
+	if deployConfig.Port == 0 {
+		switch port := blueprint.Config.Docker["expose"].(type) {
+		case int:
+			deployConfig.Port = port
+		case float64:
+			deployConfig.Port = int(port)
+		}
+	}
+	if err := deploy.NewGenerator().GenerateAll(ctx, opts.OutputDir, deployConfig); err != nil {
+		return nil, err
+	}
+	return deploy.Files(deployConfig), nil
+}
+
+// generateDocker generates the Dockerfile configured by the blueprint's
+// docker section unless it is omitted, returning the files written
+func (g *Generator) generateDocker(ctx context.Context, opts InitOptions) ([]string, error) {
+	if opts.Blueprint == "" || slices.Contains(opts.Omit, DockerfilePath) {
+		return nil, nil
+	}
+
+	blueprint, err := g.blueprintRepository.GetBlueprint(ctx, opts.Blueprint)
+	if err != nil || len(blueprint.Config.Docker) == 0 {
+		return nil, nil
+	}
+	dockerConfig, err := docker.ParseConfig(blueprint.Config.Docker)
+	if err != nil {
+		return nil, err
+	}
+	dockerConfig.ProjectName = opts.ProjectName
+	dockerConfig.GoVersion = opts.GoVersion
+	if err := docker.NewGenerator().Generate(ctx, opts.OutputDir, dockerConfig); err != nil {
+		return nil, err
+	}
+	return docker.Files(), nil
+}
+
+// generateTaskRunner generates the Makefile, or Taskfile.yml, with targets
+// for the project's stack unless it is omitted or the template ships its
+// own, returning the files written
+func (g *Generator) generateTaskRunner(ctx context.Context, opts InitOptions, variables map[string]any, renderedPaths []string) ([]string, error) {
+	runner, err := taskrunner.ParseRunner(opts.TaskRunner)
+	if err != nil {
+		return nil, err
+	}
+	path := taskrunner.File(runner)
+	if slices.Contains(opts.Omit, path) || slices.Contains(renderedPaths, path) {
+		return nil, nil
+	}
+
+	config := taskrunner.Config{
+		Runner:        runner,
+		ProjectName:   opts.ProjectName,
+		HasMain:       slices.Contains(renderedPaths, "cmd/"+opts.ProjectName+"/main.go"),
+		Proto:         opts.Template == "grpc" || slices.Contains(componentList(variables), "grpc"),
+		LicenseReport: opts.LicenseReport,
+		CoverageMin:   opts.CoverageMin,
+	}
+	config.HasDatabase, _ = variables["HasDatabase"].(bool)
+	config.DatabaseType, _ = variables["DatabaseType"].(string)
+	config.MigrationType, _ = variables["MigrationType"].(string)
+	if config.CoverageMin == 0 {
+		config.CoverageMin, _ = variables["CoverageMin"].(float64)
+	}
+
+	// Docker targets build the Dockerfile the blueprint's docker section generates
+	if hasDocker, _ := variables["HasDocker"].(bool); hasDocker && opts.Blueprint != "" && !slices.Contains(opts.Omit, DockerfilePath) {
+		blueprint, err := g.blueprintRepository.GetBlueprint(ctx, opts.Blueprint)
+		if err == nil {
+			if dockerConfig, err := docker.ParseConfig(blueprint.Config.Docker); err == nil {
+				config.Docker = true
+				config.DockerPort = dockerConfig.Port
+			}
+		}
+	}
+
+	if err := taskrunner.NewGenerator().Generate(ctx, opts.OutputDir, config); err != nil {
+		return nil, err
+	}
+	return taskrunner.Files(config), nil
+}
+
 // validateOptions validates the initialization options
 func (g *Generator) validateOptions(opts InitOptions) error {
 	if opts.ProjectName == "" {
@@ -211,6 +720,41 @@ func (g *Generator) validateOptions(opts InitOptions) error {
 		return fmt.Errorf("invalid module name: %w", err)
 	}
 
+	if opts.Framework != "" && !slices.Contains(components.Frameworks, opts.Framework) {
+		return fmt.Errorf("unsupported framework '%s', supported frameworks: %s", opts.Framework, strings.Join(components.Frameworks, ", "))
+	}
+
+	// Client SDKs need a network API to describe
+	if opts.GenerateTSClient && sdk.SourceForTemplate(opts.Template) == "" {
+		return fmt.Errorf("TypeScript client generation requires the api, grpc, or microservice template")
+	}
+
+	if _, err := cicd.ParseProvider(opts.CIProvider); err != nil {
+		return err
+	}
+	if _, err := cicd.ParseUpdateBot(opts.DependencyUpdates); err != nil {
+		return err
+	}
+	if _, err := taskrunner.ParseRunner(opts.TaskRunner); err != nil {
+		return err
+	}
+	if opts.CoverageMin < 0 || opts.CoverageMin > 1 {
+		return fmt.Errorf("coverage minimum must be between 0 and 1, not %v", opts.CoverageMin)
+	}
+	if _, err := cicd.CoveragePatterns(opts.CoverageExclude); err != nil {
+		return err
+	}
+
+	if opts.OnConflict != "" {
+		if _, err := ParseConflictPolicy(string(opts.OnConflict)); err != nil {
+			return err
+		}
+	}
+
+	if err := licenses.ValidateAllowed(opts.AllowedLicenses); err != nil {
+		return fmt.Errorf("invalid allowed licenses: %w", err)
+	}
+
 	// Validate Go version if provided
 	if opts.GoVersion != "" {
 		if err := validate.ValidateGoVersion(opts.GoVersion); err != nil {
@@ -218,6 +762,12 @@ func (g *Generator) validateOptions(opts InitOptions) error {
 		}
 	}
 
+	if opts.LanguageLevel != "" {
+		if err := validateLanguageLevel(opts.LanguageLevel, opts.GoVersion); err != nil {
+			return err
+		}
+	}
+
 	// Validate output directory if provided
 	if opts.OutputDir != "" {
 		// For validation, we only check if the parent directory exists and is writable
@@ -233,8 +783,26 @@ func (g *Generator) validateOptions(opts InitOptions) error {
 	return nil
 }
 
-// generateCICD generates CI/CD configuration files
-func (g *Generator) generateCICD(ctx context.Context, opts InitOptions, variables map[string]any) error {
+// validateLanguageLevel checks that code of the language level can be
+// generated and compiles with the go directive of goVersion
+func validateLanguageLevel(level, goVersion string) error {
+	if _, err := templates.ParseLanguageLevel(level); err != nil {
+		return err
+	}
+	if older, _ := templates.CompareLanguageLevels(level, templates.MinLanguageLevel); older < 0 {
+		return fmt.Errorf("language level %s is older than the oldest supported, %s", level, templates.MinLanguageLevel)
+	}
+	if goVersion == "" {
+		goVersion = "1.25.1"
+	}
+	if newer, err := templates.CompareLanguageLevels(level, goVersion); err == nil && newer > 0 {
+		return fmt.Errorf("language level %s is newer than Go version %s", level, goVersion)
+	}
+	return nil
+}
+
+// generateCICD generates CI/CD configuration files and returns the configuration used
+func (g *Generator) generateCICD(ctx context.Context, opts InitOptions, variables map[string]any) (*cicd.Config, error) {
 	// Set defaults for CI/CD generation
 	generateCI := opts.GenerateCI
 	if !generateCI && opts.GitInit {
@@ -243,7 +811,7 @@ func (g *Generator) generateCICD(ctx context.Context, opts InitOptions, variable
 	}
 
 	if !generateCI {
-		return nil
+		return nil, nil
 	}
 
 	// Determine if project has database based on blueprint
@@ -267,22 +835,313 @@ func (g *Generator) generateCICD(ctx context.Context, opts InitOptions, variable
 		coverageMin = 0.80 // Default to 80%
 	}
 
+	coverageExclude := opts.CoverageExclude
+	if len(coverageExclude) == 0 {
+		coverageExclude = stringList(variables["CoverageExclude"])
+	}
+
+	provider, err := cicd.ParseProvider(opts.CIProvider)
+	if err != nil {
+		return nil, err
+	}
+	updateBot, err := cicd.ParseUpdateBot(opts.DependencyUpdates)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create CI/CD configuration
 	cicdConfig := cicd.Config{
-		ProjectName:   opts.ProjectName,
-		GoVersion:     opts.GoVersion,
-		CoverageMin:   coverageMin,
-		TestFramework: "testify", // Default framework
-		HasDatabase:   hasDatabase,
-		DatabaseType:  databaseType,
-		HasDocker:     false, // TODO: Determine from blueprint in future
-		LintTimeout:   "5m",
-		BuildTargets:  []string{"linux", "darwin", "windows"},
+		Provider:          provider,
+		DependencyUpdates: updateBot,
+		ProjectName:       opts.ProjectName,
+		ModuleName:        opts.ModuleName,
+		GoVersion:         opts.GoVersion,
+		GogoVersion:       opts.GogoVersion,
+		CoverageMin:       coverageMin,
+		CoverageExclude:   coverageExclude,
+		TestFramework:     "testify", // Default framework
+		HasDatabase:       hasDatabase,
+		DatabaseType:      databaseType,
+		LicenseReport:     opts.LicenseReport,
+		HasDocker:         false, // TODO: Determine from blueprint in future
+		LintTimeout:       "5m",
+		BuildTargets:      []string{"linux", "darwin", "windows"},
 	}
 
 	// Generate CI/CD files
 	cicdGenerator := cicd.NewGenerator()
-	return cicdGenerator.GenerateAll(ctx, opts.OutputDir, cicdConfig)
+	if err := cicdGenerator.GenerateAll(ctx, opts.OutputDir, cicdConfig); err != nil {
+		return nil, err
+	}
+	return &cicdConfig, nil
+}
+
+// generateAudit generates the audit middleware, store, model, and migration
+func (g *Generator) generateAudit(ctx context.Context, opts InitOptions, variables map[string]any) ([]string, error) {
+	if hasAudit, _ := variables["HasAudit"].(bool); !hasAudit {
+		return nil, nil
+	}
+
+	framework := pickComponent(variables, components.Frameworks)
+	if framework == "" {
+		framework = "chi"
+	}
+
+	result, err := components.NewGenerator().Generate(ctx, components.GenerateOptions{
+		Type:          "middleware",
+		Variant:       "audit",
+		OutputDir:     opts.OutputDir,
+		ProjectName:   opts.ProjectName,
+		ModuleName:    opts.ModuleName,
+		GoVersion:     opts.GoVersion,
+		LanguageLevel: opts.LanguageLevel,
+		Framework:     framework,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.Files, nil
+}
+
+// generateMonitoring generates the configuration of the observability
+// docker-compose profile (Prometheus alerting rules, an SLO definition,
+// promtail and a Grafana dashboard) for blueprints with
+// observability.prometheus enabled, returning the files written
+func (g *Generator) generateMonitoring(ctx context.Context, opts InitOptions, variables map[string]any) ([]string, error) {
+	if hasPrometheus, _ := variables["HasPrometheus"].(bool); !hasPrometheus || opts.Blueprint == "" {
+		return nil, nil
+	}
+	// The files are mounted by services of the docker-compose.yml
+	if slices.Contains(opts.Omit, ComposePath) {
+		return nil, nil
+	}
+
+	blueprint, err := g.blueprintRepository.GetBlueprint(ctx, opts.Blueprint)
+	if err != nil || !monitoring.SupportsStack(blueprint.Stack) {
+		return nil, nil
+	}
+
+	config := monitoring.Config{ProjectName: opts.ProjectName}
+	if err := monitoring.NewGenerator().GenerateAll(ctx, opts.OutputDir, config); err != nil {
+		return nil, err
+	}
+	return monitoring.Files(config), nil
+}
+
+// generateEnvironments generates per-environment config overlays, the config
+// loader, and kustomize overlays, returning the files written
+func (g *Generator) generateEnvironments(ctx context.Context, opts InitOptions) ([]string, error) {
+	if opts.Blueprint == "" {
+		return nil, nil
+	}
+
+	blueprint, err := g.blueprintRepository.GetBlueprint(ctx, opts.Blueprint)
+	if err != nil || len(blueprint.Config.Environments) == 0 {
+		return nil, nil
+	}
+
+	envConfig := envconfig.Config{
+		ProjectName:   opts.ProjectName,
+		ModuleName:    opts.ModuleName,
+		Environments:  blueprint.Config.Environments,
+		HasKubernetes: len(blueprint.Config.Kubernetes) > 0,
+		Replicas:      make(map[string]int),
+	}
+	if port, ok := blueprint.Config.Docker["expose"].(int); ok {
+		envConfig.Port = port
+	}
+	if len(blueprint.Config.Database) > 0 {
+		envConfig.HasDatabase = true
+		if dbType, ok := blueprint.Config.Database["type"].(string); ok {
+			envConfig.DatabaseType = dbType
+		}
+	}
+	if replicas, ok := blueprint.Config.Kubernetes["replicas"].(map[string]any); ok {
+		for env, count := range replicas {
+			if n, ok := count.(int); ok {
+				envConfig.Replicas[env] = n
+			}
+		}
+	}
+
+	if err := envconfig.NewGenerator().GenerateAll(ctx, opts.OutputDir, envConfig); err != nil {
+		return nil, err
+	}
+	return envconfig.Files(envConfig), nil
+}
+
+// manifestFields are the template variables the manifest records in fields
+// of their own rather than under variables
+var manifestFields = map[string]bool{
+	"ProjectName": true, "ModuleName": true, "GoVersion": true, "Components": true, "TaskRunner": true,
+	// Derived from the components
+	"IsGin": true, "IsEcho": true, "IsChi": true,
+	// Derived from LanguageLevel
+	"HasSlog": true, "HasSlices": true, "HasWithoutCancel": true, "HasRangeInt": true, "HasRandV2": true,
+}
+
+// writeManifest writes the project manifest with the components and variables
+// the project was generated with and hashes of every generated file
+func (g *Generator) writeManifest(opts InitOptions, variables map[string]any, cicdConfig *cicd.Config, renderedPaths []string, nextSteps []string) error {
+	manifest := &project.Manifest{
+		GogoVersion: opts.GogoVersion,
+		Template:    opts.Template,
+		Blueprint:   opts.Blueprint,
+		ProjectName: opts.ProjectName,
+		ModuleName:  opts.ModuleName,
+		GoVersion:   opts.GoVersion,
+		GeneratedAt: time.Now().UTC().Truncate(time.Second),
+		Framework:   pickComponent(variables, components.Frameworks),
+		Database:    pickComponent(variables, components.Databases),
+		Components:  componentList(variables),
+		Variables:   make(map[string]any),
+		Omit:        opts.Omit,
+		TaskRunner:  opts.TaskRunner,
+		NextSteps:   nextSteps,
+		DriftIgnore: append([]string(nil), project.DefaultDriftIgnore...),
+	}
+	for name, value := range variables {
+		if !manifestFields[name] {
+			manifest.Variables[name] = value
+		}
+	}
+	if cicdConfig != nil {
+		manifest.CI = &project.CISettings{
+			Provider:          cicdConfig.Provider,
+			DependencyUpdates: cicdConfig.DependencyUpdates,
+			CoverageMin:       cicdConfig.CoverageMin,
+			CoverageExclude:   cicdConfig.CoverageExclude,
+			HasDatabase:       cicdConfig.HasDatabase,
+			DatabaseType:      cicdConfig.DatabaseType,
+			LicenseReport:     cicdConfig.LicenseReport,
+		}
+	}
+
+	if err := manifest.TrackFiles(opts.OutputDir, renderedPaths); err != nil {
+		return err
+	}
+
+	return manifest.SaveFile(opts.OutputDir)
+}
+
+// OptionsFromManifest returns the options that regenerate the project a
+// manifest describes into outputDir
+func OptionsFromManifest(manifest *project.Manifest, outputDir string) InitOptions {
+	opts := InitOptions{
+		ProjectName: manifest.ProjectName,
+		ModuleName:  manifest.ModuleName,
+		Template:    manifest.Template,
+		Blueprint:   manifest.Blueprint,
+		GoVersion:   manifest.GoVersion,
+		GogoVersion: manifest.GogoVersion,
+		OutputDir:   outputDir,
+		Variables:   manifest.Variables,
+		Omit:        manifest.Omit,
+		Framework:   manifest.Framework,
+		TaskRunner:  manifest.TaskRunner,
+	}
+	// Options that reach generators other than the templates are recorded
+	// as variables too
+	opts.Author, _ = manifest.Variables["Author"].(string)
+	opts.License, _ = manifest.Variables["License"].(string)
+	opts.Description, _ = manifest.Variables["Description"].(string)
+	opts.LanguageLevel, _ = manifest.Variables["LanguageLevel"].(string)
+
+	if manifest.CI != nil {
+		opts.GenerateCI = true
+		opts.CIProvider = manifest.CI.Provider
+		opts.DependencyUpdates = manifest.CI.DependencyUpdates
+		opts.CoverageMin = manifest.CI.CoverageMin
+		opts.CoverageExclude = manifest.CI.CoverageExclude
+		opts.LicenseReport = manifest.CI.LicenseReport
+	}
+	for _, file := range manifest.Files {
+		if strings.HasPrefix(file.Path, sdk.ClientDir+"/") {
+			opts.GenerateTSClient = true
+			break
+		}
+	}
+	return opts
+}
+
+// componentList returns the Components variable set by the blueprint, or by
+// extra variables as a YAML list
+func componentList(variables map[string]any) []string {
+	return stringList(variables["Components"])
+}
+
+// stringList returns the strings of a variable holding a list, as set in Go
+// or read from YAML
+func stringList(value any) []string {
+	switch list := value.(type) {
+	case []string:
+		return list
+	case []any:
+		names := make([]string, 0, len(list))
+		for _, item := range list {
+			if name, ok := item.(string); ok {
+				names = append(names, name)
+			}
+		}
+		return names
+	}
+	return nil
+}
+
+// queues are the components picking the message queue of worker stack
+// projects, which default to asynq
+var queues = []string{"asynq", "nats", "kafka"}
+
+// pickComponent returns the first component that is one of choices, or ""
+func pickComponent(variables map[string]any, choices []string) string {
+	for _, component := range componentList(variables) {
+		if slices.Contains(choices, component) {
+			return component
+		}
+	}
+	return ""
+}
+
+// withFramework returns the components with framework in place of the HTTP
+// framework among them, or added when there is none
+func withFramework(list []string, framework string) []string {
+	result := make([]string, 0, len(list)+1)
+	replaced := false
+	for _, component := range list {
+		if slices.Contains(components.Frameworks, component) {
+			if !replaced {
+				result = append(result, framework)
+				replaced = true
+			}
+			continue
+		}
+		result = append(result, component)
+	}
+	if !replaced {
+		result = append(result, framework)
+	}
+	return result
+}
+
+// renderNextSteps renders the next steps declared by the template and
+// blueprint, returning their non-blank lines
+func (g *Generator) renderNextSteps(ctx context.Context, sources []string, variables map[string]any) ([]string, error) {
+	var steps []string
+	for _, source := range sources {
+		if strings.TrimSpace(source) == "" {
+			continue
+		}
+		rendered, err := g.templateEngine.RenderString(ctx, source, variables)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render next steps: %w", err)
+		}
+		for _, line := range strings.Split(rendered, "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				steps = append(steps, line)
+			}
+		}
+	}
+	return steps, nil
 }
 
 // initializeGit initializes a git repository with initial commit
@@ -311,9 +1170,15 @@ func (g *Generator) initializeGit(ctx context.Context, opts InitOptions) error {
 	// Initialize git repository
 	gitOpts := git.InitOptions{
 		ProjectName:          opts.ProjectName,
+		ModuleName:           opts.ModuleName,
+		Template:             opts.Template,
+		Blueprint:            opts.Blueprint,
+		GogoVersion:          opts.GogoVersion,
 		Author:               opts.Author,
 		Email:                email,
 		InitialCommitMessage: opts.InitialCommitMessage,
+		MessageTemplate:      opts.CommitPolicy.MessageTemplate,
+		Trailers:             opts.CommitPolicy.AllTrailers(),
 	}
 
 	if err := gitManager.Init(ctx, gitOpts); err != nil {
@@ -329,14 +1194,27 @@ func (g *Generator) initializeGit(ctx context.Context, opts InitOptions) error {
 }
 
 // buildResultMessage builds the result message based on what was generated
-func (g *Generator) buildResultMessage(opts InitOptions, templateFilesCount int) string {
-	message := fmt.Sprintf("Created %d files in %s", templateFilesCount, opts.OutputDir)
+func (g *Generator) buildResultMessage(opts InitOptions, filesCreated int) string {
+	message := fmt.Sprintf("Created %d files in %s", filesCreated, opts.OutputDir)
 
-	if opts.GenerateCI {
-		message += "\nGenerated CI/CD configurations (.golangci.yml, GitHub Actions, pre-commit hooks)"
+	if opts.GenerateCI || opts.GitInit {
+		provider, _ := cicd.ParseProvider(opts.CIProvider)
+		message += fmt.Sprintf("\nGenerated CI/CD configurations (.golangci.yml, %s, pre-commit hooks)", cicd.ProviderName(provider))
+		if bot, _ := cicd.ParseUpdateBot(opts.DependencyUpdates); bot != "" {
+			message += fmt.Sprintf("\nConfigured %s to update dependencies (%s)", cicd.UpdateBotName(bot), cicd.UpdateBotFile(bot))
+		}
 	}
 
-	if opts.GitInit {
+	if opts.VerifyBuild {
+		message += "\nVerified the project builds (go mod tidy, go generate ./..., go build ./...)"
+	}
+
+	if opts.Into {
+		message += "\nCommitted to the existing git repository"
+		if opts.Branch != "" {
+			message += " on branch " + opts.Branch
+		}
+	} else if opts.GitInit {
 		message += "\nInitialized git repository with initial commit"
 	}
 
@@ -2,12 +2,21 @@ package generator
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/user/gogo/internal/blueprints"
+	"github.com/user/gogo/internal/components"
+	"github.com/user/gogo/internal/monitoring"
+	"github.com/user/gogo/internal/project"
+	"github.com/user/gogo/internal/state"
 	"github.com/user/gogo/internal/templates"
 )
 
@@ -84,6 +93,38 @@ func TestProjectGenerator_InitProject(t *testing.T) {
 			},
 			expectError: false,
 		},
+		{
+			name: "API project with TypeScript client",
+			opts: InitOptions{
+				ProjectName:      "myclientapi",
+				ModuleName:       "github.com/user/myclientapi",
+				Template:         "api",
+				GoVersion:        "1.25.1",
+				OutputDir:        filepath.Join(tempDir, "api-ts-test"),
+				GenerateTSClient: true,
+			},
+			expectFiles: []string{
+				"cmd/myclientapi/main.go",
+				".gogo.yaml",
+				"api/openapi.yaml",
+				"clients/ts/package.json",
+				"clients/ts/src/index.ts",
+				".github/workflows/publish-ts-client.yml",
+			},
+			expectError: false,
+		},
+		{
+			name: "TypeScript client requires an API template",
+			opts: InitOptions{
+				ProjectName:      "mycli",
+				ModuleName:       "github.com/user/mycli",
+				Template:         "cli",
+				OutputDir:        filepath.Join(tempDir, "cli-ts-test"),
+				GenerateTSClient: true,
+			},
+			expectFiles: nil,
+			expectError: true,
+		},
 		{
 			name: "invalid template",
 			opts: InitOptions{
@@ -237,8 +278,876 @@ func TestProjectGenerator_DryRun(t *testing.T) {
 	// Should return success but not create files
 	assert.True(t, result.Success)
 	assert.Greater(t, result.FilesCreated, 0) // Should report files that would be created
+	require.NotNil(t, result.Preview)
+	assert.Equal(t, result.FilesCreated, result.Preview.Count(ChangeNew), "every file is new")
 
 	// Verify no files were actually created
 	_, err = os.Stat(opts.OutputDir)
 	assert.True(t, os.IsNotExist(err), "output directory should not exist in dry run")
 }
+
+func TestProjectGenerator_Warnings(t *testing.T) {
+	generator := NewProjectGenerator(templates.NewEngine(), templates.NewRepository())
+
+	result, err := generator.InitProject(context.Background(), InitOptions{
+		ProjectName: "orders",
+		ModuleName:  "github.com/user/orders",
+		Template:    "microservice",
+		Blueprint:   "microservice-stack",
+		OutputDir:   filepath.Join(t.TempDir(), "orders"),
+		DryRun:      true,
+	})
+	require.NoError(t, err)
+	assert.Contains(t, result.Warnings, `go.mod:8: condition "IsEcho" is never true`)
+	assert.Contains(t, result.Warnings, "variable TracingType is never used by a template")
+	assert.NotContains(t, result.Warnings, "variable IsAsynq is never used by a template", "the generator sets it for every template")
+}
+
+func TestProjectGenerator_DeploymentTargets(t *testing.T) {
+	tempDir := t.TempDir()
+	generator := NewProjectGenerator(templates.NewEngine(), templates.NewRepository())
+	generator.blueprintRepository.Register(blueprints.Blueprint{
+		Name:  "worker-host",
+		Stack: "microservice",
+		Config: blueprints.BlueprintConfig{
+			Deployment: map[string]any{"target": []any{"systemd", "windows"}},
+		},
+	})
+	ctx := context.Background()
+
+	opts := InitOptions{
+		ProjectName: "orders",
+		ModuleName:  "github.com/user/orders",
+		Template:    "microservice",
+		Blueprint:   "worker-host",
+		OutputDir:   filepath.Join(tempDir, "orders"),
+	}
+	_, err := generator.InitProject(ctx, opts)
+	require.NoError(t, err)
+	assert.FileExists(t, filepath.Join(opts.OutputDir, "deploy", "systemd", "orders.service"))
+	assert.FileExists(t, filepath.Join(opts.OutputDir, "cmd", "orders-service", "main.go"))
+
+	manifest, err := project.LoadManifest(opts.OutputDir)
+	require.NoError(t, err)
+	var tracked []string
+	for _, file := range manifest.Files {
+		tracked = append(tracked, file.Path)
+	}
+	assert.Contains(t, tracked, "cmd/orders-service/main.go")
+
+	// Short-lived templates cannot be deployed as services
+	opts.Template = "cli"
+	opts.OutputDir = filepath.Join(tempDir, "cli")
+	_, err = generator.InitProject(ctx, opts)
+	assert.ErrorContains(t, err, "does not support")
+	assert.NoDirExists(t, opts.OutputDir)
+}
+
+func TestProjectGenerator_Dockerfile(t *testing.T) {
+	tempDir := t.TempDir()
+	generator := NewProjectGenerator(templates.NewEngine(), templates.NewRepository())
+	generator.blueprintRepository.Register(blueprints.Blueprint{
+		Name:  "distroless",
+		Stack: "microservice",
+		Config: blueprints.BlueprintConfig{
+			Docker: map[string]any{"runtime": "distroless", "expose": 9000, "build_args": map[string]any{"VERSION": "dev"}},
+		},
+	})
+	ctx := context.Background()
+
+	// Every stack with a docker section gets a Dockerfile
+	opts := InitOptions{
+		ProjectName: "orders",
+		ModuleName:  "github.com/user/orders",
+		Template:    "microservice",
+		Blueprint:   "microservice-stack",
+		OutputDir:   filepath.Join(tempDir, "orders"),
+	}
+	_, err := generator.InitProject(ctx, opts)
+	require.NoError(t, err)
+	dockerfile, err := os.ReadFile(filepath.Join(opts.OutputDir, DockerfilePath))
+	require.NoError(t, err)
+	assert.Contains(t, string(dockerfile), "HEALTHCHECK")
+	assert.Contains(t, string(dockerfile), "USER 10001:10001")
+
+	manifest, err := project.LoadManifest(opts.OutputDir)
+	require.NoError(t, err)
+	var tracked []string
+	for _, file := range manifest.Files {
+		tracked = append(tracked, file.Path)
+	}
+	assert.Contains(t, tracked, DockerfilePath)
+
+	opts.Blueprint = "distroless"
+	opts.OutputDir = filepath.Join(tempDir, "distroless")
+	_, err = generator.InitProject(ctx, opts)
+	require.NoError(t, err)
+	dockerfile, err = os.ReadFile(filepath.Join(opts.OutputDir, DockerfilePath))
+	require.NoError(t, err)
+	assert.Contains(t, string(dockerfile), "FROM gcr.io/distroless/static-debian12:nonroot")
+	assert.Contains(t, string(dockerfile), "ARG VERSION=\"dev\"")
+	assert.Contains(t, string(dockerfile), "EXPOSE 9000")
+}
+
+func TestProjectGenerator_ManifestRecordsStack(t *testing.T) {
+	generator := NewProjectGenerator(templates.NewEngine(), templates.NewRepository())
+	generator.blueprintRepository.Register(blueprints.Blueprint{
+		Name:  "echo-sqlx",
+		Stack: "web",
+		Config: blueprints.BlueprintConfig{
+			Components: []string{"viper", "echo", "sqlx"},
+			Database:   map[string]any{"type": "postgres"},
+		},
+	})
+
+	opts := InitOptions{
+		ProjectName: "shop",
+		ModuleName:  "github.com/user/shop",
+		Template:    "api",
+		Blueprint:   "echo-sqlx",
+		Author:      "Jane Doe",
+		OutputDir:   filepath.Join(t.TempDir(), "shop"),
+		Variables:   map[string]any{"Port": 9090},
+	}
+	_, err := generator.InitProject(context.Background(), opts)
+	require.NoError(t, err)
+
+	manifest, err := project.LoadManifest(opts.OutputDir)
+	require.NoError(t, err)
+	assert.Equal(t, "echo", manifest.Framework)
+	assert.Equal(t, "sqlx", manifest.Database)
+	assert.Equal(t, []string{"viper", "echo", "sqlx"}, manifest.Components)
+	assert.Equal(t, "Jane Doe", manifest.Variables["Author"])
+	assert.Equal(t, "postgres", manifest.Variables["DatabaseType"])
+	assert.Equal(t, 9090, manifest.Variables["Port"])
+	assert.NotContains(t, manifest.Variables, "ProjectName", "recorded in its own field")
+}
+
+// stateStore is a state.Store keeping files in a map
+type stateStore map[string][]byte
+
+func (s stateStore) Get(modulePath, name string) ([]byte, error) {
+	return s[modulePath+"/"+name], nil
+}
+
+func (s stateStore) Save(modulePath, name string, content []byte) error {
+	s[modulePath+"/"+name] = content
+	return nil
+}
+
+func TestProjectGenerator_StoreState(t *testing.T) {
+	store := stateStore{}
+	state.Use(store)
+	defer state.Use(nil)
+
+	generator := NewProjectGenerator(templates.NewEngine(), templates.NewRepository())
+	opts := InitOptions{
+		ProjectName: "shop",
+		ModuleName:  "github.com/user/shop",
+		Template:    "cli",
+		OutputDir:   filepath.Join(t.TempDir(), "shop"),
+		StoreState:  true,
+	}
+
+	opts.DryRun = true
+	result, err := generator.InitProject(context.Background(), opts)
+	require.NoError(t, err)
+	for _, file := range result.Preview.Files {
+		assert.NotEqual(t, project.ManifestFile, file.Path)
+	}
+	assert.Empty(t, store, "a dry run stores nothing")
+
+	opts.DryRun = false
+	_, err = generator.InitProject(context.Background(), opts)
+	require.NoError(t, err)
+	assert.NoFileExists(t, filepath.Join(opts.OutputDir, project.ManifestFile))
+	assert.Contains(t, store, "github.com/user/shop/"+project.ManifestFile)
+
+	manifest, err := project.LoadManifest(opts.OutputDir)
+	require.NoError(t, err)
+	assert.Equal(t, "cli", manifest.Template)
+}
+
+func TestProjectGenerator_Framework(t *testing.T) {
+	generator := NewProjectGenerator(templates.NewEngine(), templates.NewRepository())
+	tempDir := t.TempDir()
+
+	for _, tc := range []struct {
+		template, blueprint, framework string
+		router                         string
+		require                        string
+	}{
+		{"api", "", "echo", "e := echo.New()", "github.com/labstack/echo/v4 v4.11.4"},
+		{"api", "", "chi", "r := chi.NewRouter()", "github.com/go-chi/chi/v5 v5.0.12"},
+		{"microservice", "", "gin", "r := gin.Default()", "github.com/gin-gonic/gin v1.9.1"},
+		{"api", "web-stack", "echo", "e := echo.New()", "github.com/labstack/echo/v4 v4.11.4"},
+		{"microservice", "microservice-stack", "chi", "r := chi.NewRouter()", "github.com/go-chi/chi/v5 v5.0.12"},
+	} {
+		name := tc.template + "-" + tc.blueprint + "-" + tc.framework
+		opts := InitOptions{
+			ProjectName: "svc",
+			ModuleName:  "github.com/user/svc",
+			Template:    tc.template,
+			Blueprint:   tc.blueprint,
+			Framework:   tc.framework,
+			OutputDir:   filepath.Join(tempDir, name),
+		}
+		_, err := generator.InitProject(context.Background(), opts)
+		require.NoError(t, err, name)
+
+		main, err := os.ReadFile(filepath.Join(opts.OutputDir, "cmd", "svc", "main.go"))
+		require.NoError(t, err, name)
+		assert.Contains(t, string(main), tc.router, name)
+		assert.Contains(t, string(main), ".Shutdown(ctx)", name)
+		goMod, err := os.ReadFile(filepath.Join(opts.OutputDir, "go.mod"))
+		require.NoError(t, err, name)
+		assert.Contains(t, string(goMod), tc.require, name)
+
+		manifest, err := project.LoadManifest(opts.OutputDir)
+		require.NoError(t, err, name)
+		assert.Equal(t, tc.framework, manifest.Framework, name)
+		assert.Equal(t, tc.framework, OptionsFromManifest(manifest, opts.OutputDir).Framework, name)
+		for _, other := range components.Frameworks {
+			if other != tc.framework {
+				assert.NotContains(t, manifest.Components, other, "%s: the blueprint's framework is replaced", name)
+			}
+		}
+	}
+
+	_, err := generator.InitProject(context.Background(), InitOptions{
+		ProjectName: "svc",
+		ModuleName:  "github.com/user/svc",
+		Template:    "api",
+		Framework:   "fiber",
+		OutputDir:   filepath.Join(tempDir, "fiber"),
+	})
+	assert.ErrorContains(t, err, "unsupported framework 'fiber'")
+}
+
+func TestProjectGenerator_NextSteps(t *testing.T) {
+	generator := NewProjectGenerator(templates.NewEngine(), templates.NewRepository())
+	tempDir := t.TempDir()
+
+	result, err := generator.InitProject(context.Background(), InitOptions{
+		ProjectName: "shop",
+		ModuleName:  "github.com/user/shop",
+		Template:    "api",
+		OutputDir:   filepath.Join(tempDir, "api"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Start the API: make dev", "Check it responds: curl http://localhost:8080/health"}, result.NextSteps)
+
+	// The stack's files replace the template's, and so do its next steps
+	opts := InitOptions{
+		ProjectName: "shop",
+		ModuleName:  "github.com/user/shop",
+		Template:    "api",
+		Blueprint:   "web-stack",
+		OutputDir:   filepath.Join(tempDir, "web"),
+	}
+	result, err = generator.InitProject(context.Background(), opts)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"Start the service and its dependencies: docker compose up --build",
+		"To run it outside Docker, set DATABASE_URL (default postgres://localhost/shop?sslmode=disable)",
+		"Check it responds: curl http://localhost:8080/health",
+	}, result.NextSteps)
+
+	manifest, err := project.LoadManifest(opts.OutputDir)
+	require.NoError(t, err)
+	assert.Equal(t, result.NextSteps, manifest.NextSteps)
+}
+
+func TestProjectGenerator_Report(t *testing.T) {
+	generator := NewProjectGenerator(templates.NewEngine(), templates.NewRepository())
+	opts := InitOptions{
+		ProjectName: "shop",
+		ModuleName:  "github.com/user/shop",
+		Template:    "cli",
+		OutputDir:   filepath.Join(t.TempDir(), "shop"),
+		OnConflict:  ConflictSkip,
+		Report:      true,
+	}
+	require.NoError(t, os.MkdirAll(opts.OutputDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(opts.OutputDir, "README.md"), []byte("ours\n"), 0644))
+
+	result, err := generator.InitProject(context.Background(), opts)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(opts.OutputDir, project.ReportDir, project.ReportFile), result.ReportPath)
+
+	data, err := os.ReadFile(result.ReportPath)
+	require.NoError(t, err)
+	var report project.Report
+	require.NoError(t, json.Unmarshal(data, &report))
+
+	assert.Equal(t, "init", report.Command)
+	assert.Equal(t, "cli", report.Template)
+	assert.Equal(t, "shop", report.Variables["ProjectName"])
+	assert.Equal(t, []string{"kept existing README.md"}, report.Warnings)
+	assert.Equal(t, "render", report.Phases[0].Name)
+	assert.Empty(t, report.Hooks)
+
+	paths := make([]string, len(report.Files))
+	for i, file := range report.Files {
+		paths[i] = file.Path
+		sum, err := project.HashFile(filepath.Join(opts.OutputDir, file.Path))
+		require.NoError(t, err)
+		assert.Equal(t, sum, file.SHA256, file.Path)
+	}
+	assert.Contains(t, paths, "cmd/shop/main.go")
+	assert.Contains(t, paths, project.ManifestFile)
+	assert.NotContains(t, paths, "README.md", "skipped files were not written")
+
+	// Without the option no report is written
+	opts.OutputDir = filepath.Join(t.TempDir(), "plain")
+	opts.Report = false
+	result, err = generator.InitProject(context.Background(), opts)
+	require.NoError(t, err)
+	assert.Empty(t, result.ReportPath)
+	assert.NoDirExists(t, filepath.Join(opts.OutputDir, project.ReportDir))
+}
+
+func TestProjectGenerator_Omit(t *testing.T) {
+	generator := NewProjectGenerator(templates.NewEngine(), templates.NewRepository())
+	opts := InitOptions{
+		ProjectName: "shop",
+		ModuleName:  "github.com/user/shop",
+		Template:    "api",
+		Blueprint:   "web-stack",
+		OutputDir:   filepath.Join(t.TempDir(), "shop"),
+	}
+	full, err := generator.InitProject(context.Background(), InitOptions{
+		ProjectName: opts.ProjectName,
+		ModuleName:  opts.ModuleName,
+		Template:    opts.Template,
+		Blueprint:   opts.Blueprint,
+		OutputDir:   opts.OutputDir,
+		DryRun:      true,
+	})
+	require.NoError(t, err)
+	generated := make(map[string]bool)
+	for _, file := range full.Preview.Files {
+		generated[file.Path] = true
+	}
+	require.True(t, generated[DockerfilePath] && generated[ComposePath])
+
+	opts.Omit = OptionalFiles
+	_, err = generator.InitProject(context.Background(), opts)
+	require.NoError(t, err)
+	assert.NoFileExists(t, filepath.Join(opts.OutputDir, DockerfilePath))
+	assert.NoFileExists(t, filepath.Join(opts.OutputDir, ComposePath))
+	assert.FileExists(t, filepath.Join(opts.OutputDir, "go.mod"))
+
+	manifest, err := project.LoadManifest(opts.OutputDir)
+	require.NoError(t, err)
+	assert.Equal(t, OptionalFiles, manifest.Omit)
+	assert.Equal(t, OptionalFiles, OptionsFromManifest(manifest, opts.OutputDir).Omit, "regenerating leaves them out too")
+}
+
+func TestProjectGenerator_DeploymentProvisioners(t *testing.T) {
+	tempDir := t.TempDir()
+	generator := NewProjectGenerator(templates.NewEngine(), templates.NewRepository())
+	generator.blueprintRepository.Register(blueprints.Blueprint{
+		Name:  "vm-host",
+		Stack: "microservice",
+		Config: blueprints.BlueprintConfig{
+			Environments: []string{"staging", "production"},
+			Deployment: map[string]any{
+				"target":    "systemd",
+				"provision": []any{"cloud-init", "ansible"},
+				"env":       map[string]any{"LOG_LEVEL": "info"},
+			},
+		},
+	})
+
+	opts := InitOptions{
+		ProjectName: "orders",
+		ModuleName:  "github.com/user/orders",
+		Template:    "microservice",
+		Blueprint:   "vm-host",
+		OutputDir:   filepath.Join(tempDir, "orders"),
+	}
+	_, err := generator.InitProject(context.Background(), opts)
+	require.NoError(t, err)
+
+	userData, err := os.ReadFile(filepath.Join(opts.OutputDir, "deploy", "cloud-init", "user-data.yaml"))
+	require.NoError(t, err)
+	assert.Contains(t, string(userData), `APP_ENV="production"`)
+	assert.Contains(t, string(userData), `LOG_LEVEL="info"`)
+	assert.FileExists(t, filepath.Join(opts.OutputDir, "deploy", "ansible", "roles", "orders", "tasks", "main.yml"))
+
+	manifest, err := project.LoadManifest(opts.OutputDir)
+	require.NoError(t, err)
+	var tracked []string
+	for _, file := range manifest.Files {
+		tracked = append(tracked, file.Path)
+	}
+	assert.Contains(t, tracked, "deploy/cloud-init/user-data.yaml")
+	assert.Contains(t, tracked, "deploy/ansible/site.yml")
+
+	// Provisioners install the systemd unit
+	opts.Blueprint = "worker-only"
+	generator.blueprintRepository.Register(blueprints.Blueprint{
+		Name:   "worker-only",
+		Stack:  "microservice",
+		Config: blueprints.BlueprintConfig{Deployment: map[string]any{"provision": "ansible"}},
+	})
+	opts.OutputDir = filepath.Join(tempDir, "worker")
+	_, err = generator.InitProject(context.Background(), opts)
+	assert.ErrorContains(t, err, "needs the systemd target")
+}
+
+func TestProjectGenerator_LicenseReport(t *testing.T) {
+	tempDir := t.TempDir()
+	generator := NewProjectGenerator(templates.NewEngine(), templates.NewRepository())
+	ctx := context.Background()
+
+	opts := InitOptions{
+		ProjectName:     "tool",
+		ModuleName:      "github.com/user/tool",
+		Template:        "cli",
+		OutputDir:       filepath.Join(tempDir, "tool"),
+		LicenseReport:   true,
+		AllowedLicenses: []string{"MIT"},
+	}
+	_, err := generator.InitProject(ctx, opts)
+	require.NoError(t, err)
+	assert.FileExists(t, filepath.Join(opts.OutputDir, "scripts", "licenses.sh"))
+	makefile, err := os.ReadFile(filepath.Join(opts.OutputDir, "Makefile"))
+	require.NoError(t, err)
+	assert.Contains(t, string(makefile), "licenses:\n\tsh scripts/licenses.sh")
+
+	manifest, err := project.LoadManifest(opts.OutputDir)
+	require.NoError(t, err)
+	var tracked []string
+	for _, file := range manifest.Files {
+		tracked = append(tracked, file.Path)
+	}
+	assert.Contains(t, tracked, "scripts/licenses.sh")
+
+	// The Makefile target is only added with the license check
+	opts.LicenseReport = false
+	opts.OutputDir = filepath.Join(tempDir, "plain")
+	_, err = generator.InitProject(ctx, opts)
+	require.NoError(t, err)
+	makefile, err = os.ReadFile(filepath.Join(opts.OutputDir, "Makefile"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(makefile), "licenses")
+
+	opts.AllowedLicenses = []string{"MIT,GPL-3.0"}
+	opts.OutputDir = filepath.Join(tempDir, "invalid")
+	_, err = generator.InitProject(ctx, opts)
+	assert.ErrorContains(t, err, "invalid license identifier")
+}
+
+func TestProjectGenerator_CIProvider(t *testing.T) {
+	tempDir := t.TempDir()
+	generator := NewProjectGenerator(templates.NewEngine(), templates.NewRepository())
+	ctx := context.Background()
+
+	opts := InitOptions{
+		ProjectName: "tool",
+		ModuleName:  "github.com/user/tool",
+		Template:    "cli",
+		OutputDir:   filepath.Join(tempDir, "tool"),
+		GenerateCI:  true,
+		CIProvider:  "gitlab",
+	}
+	result, err := generator.InitProject(ctx, opts)
+	require.NoError(t, err)
+	assert.Contains(t, result.Message, "GitLab CI")
+	assert.FileExists(t, filepath.Join(opts.OutputDir, ".gitlab-ci.yml"))
+	assert.NoDirExists(t, filepath.Join(opts.OutputDir, ".github"))
+
+	manifest, err := project.LoadManifest(opts.OutputDir)
+	require.NoError(t, err)
+	require.NotNil(t, manifest.CI)
+	assert.Equal(t, "gitlab", manifest.CI.Provider)
+	assert.Equal(t, "gitlab", OptionsFromManifest(manifest, tempDir).CIProvider)
+
+	opts.CIProvider = "jenkins"
+	opts.OutputDir = filepath.Join(tempDir, "jenkins")
+	_, err = generator.InitProject(ctx, opts)
+	assert.ErrorContains(t, err, "unknown CI provider")
+}
+
+func TestProjectGenerator_DependencyUpdates(t *testing.T) {
+	tempDir := t.TempDir()
+	generator := NewProjectGenerator(templates.NewEngine(), templates.NewRepository())
+	ctx := context.Background()
+
+	opts := InitOptions{
+		ProjectName:       "tool",
+		ModuleName:        "github.com/user/tool",
+		Template:          "cli",
+		OutputDir:         filepath.Join(tempDir, "tool"),
+		GenerateCI:        true,
+		DependencyUpdates: "renovate",
+	}
+	result, err := generator.InitProject(ctx, opts)
+	require.NoError(t, err)
+	assert.Contains(t, result.Message, "Configured Renovate")
+	assert.FileExists(t, filepath.Join(opts.OutputDir, "renovate.json"))
+
+	manifest, err := project.LoadManifest(opts.OutputDir)
+	require.NoError(t, err)
+	require.NotNil(t, manifest.CI)
+	assert.Equal(t, "renovate", manifest.CI.DependencyUpdates)
+	assert.Equal(t, "renovate", OptionsFromManifest(manifest, tempDir).DependencyUpdates)
+	var tracked []string
+	for _, file := range manifest.Files {
+		tracked = append(tracked, file.Path)
+	}
+	assert.Contains(t, tracked, "renovate.json")
+
+	opts.DependencyUpdates = "none"
+	opts.OutputDir = filepath.Join(tempDir, "none")
+	_, err = generator.InitProject(ctx, opts)
+	require.NoError(t, err)
+	assert.NoFileExists(t, filepath.Join(opts.OutputDir, "renovate.json"))
+	assert.NoFileExists(t, filepath.Join(opts.OutputDir, ".github", "dependabot.yml"))
+}
+
+func TestProjectGenerator_Monitoring(t *testing.T) {
+	tempDir := t.TempDir()
+	generator := NewProjectGenerator(templates.NewEngine(), templates.NewRepository())
+	ctx := context.Background()
+
+	opts := InitOptions{
+		ProjectName: "orders",
+		ModuleName:  "github.com/user/orders",
+		Template:    "microservice",
+		Blueprint:   "microservice-stack",
+		OutputDir:   filepath.Join(tempDir, "orders"),
+	}
+	_, err := generator.InitProject(ctx, opts)
+	require.NoError(t, err)
+
+	compose, err := os.ReadFile(filepath.Join(opts.OutputDir, "docker-compose.yml"))
+	require.NoError(t, err)
+	assert.Contains(t, string(compose), "./monitoring/rules:/etc/prometheus/rules:ro")
+	assert.Contains(t, string(compose), `profiles: ["observability"]`)
+	assert.Contains(t, string(compose), "./monitoring/grafana/dashboards:/var/lib/grafana/dashboards:ro")
+
+	main, err := os.ReadFile(filepath.Join(opts.OutputDir, "cmd", "orders", "main.go"))
+	require.NoError(t, err)
+	assert.Contains(t, string(main), "r.Use(metricsMiddleware())", "the request metrics are recorded")
+
+	manifest, err := project.LoadManifest(opts.OutputDir)
+	require.NoError(t, err)
+	var tracked []string
+	for _, file := range manifest.Files {
+		tracked = append(tracked, file.Path)
+	}
+	for _, file := range monitoring.Files(monitoring.Config{ProjectName: "orders"}) {
+		assert.FileExists(t, filepath.Join(opts.OutputDir, file))
+		assert.Contains(t, tracked, file)
+	}
+
+	// The web stack gets the same observability profile
+	opts.Template, opts.Blueprint = "api", "web-stack"
+	opts.OutputDir = filepath.Join(tempDir, "web")
+	result, err := generator.InitProject(ctx, opts)
+	require.NoError(t, err)
+	assert.Contains(t, result.Message, "docker compose --profile observability up")
+	compose, err = os.ReadFile(filepath.Join(opts.OutputDir, "docker-compose.yml"))
+	require.NoError(t, err)
+	assert.Contains(t, string(compose), "promtail:")
+	assert.FileExists(t, filepath.Join(opts.OutputDir, "monitoring", "promtail.yml"))
+
+	// Without Prometheus there is nothing to scrape
+	opts.Template, opts.Blueprint = "grpc", "grpc-stack"
+	opts.OutputDir = filepath.Join(tempDir, "grpc")
+	result, err = generator.InitProject(ctx, opts)
+	require.NoError(t, err)
+	assert.NotContains(t, result.Message, "--profile observability")
+	assert.NoDirExists(t, filepath.Join(opts.OutputDir, "monitoring"))
+}
+
+func BenchmarkProjectGenerator_InitProject(b *testing.B) {
+	generator := NewProjectGenerator(templates.NewEngine(), templates.NewRepository())
+	ctx := context.Background()
+	dir := b.TempDir()
+
+	for _, bench := range []struct {
+		name      string
+		template  string
+		blueprint string
+	}{
+		{"cli", "cli", ""},
+		{"api-web-stack", "api", "web-stack"},
+		{"microservice-stack", "microservice", "microservice-stack"},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_, err := generator.InitProject(ctx, InitOptions{
+					ProjectName: "bench",
+					ModuleName:  "github.com/user/bench",
+					Template:    bench.template,
+					Blueprint:   bench.blueprint,
+					Author:      "Bench",
+					GoVersion:   "1.25.1",
+					OutputDir:   filepath.Join(dir, fmt.Sprintf("%s-%d", bench.name, i)),
+				})
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestProjectGenerator_MessageCountsEveryFile(t *testing.T) {
+	generator := NewProjectGenerator(templates.NewEngine(), templates.NewRepository())
+	opts := InitOptions{
+		ProjectName: "orders",
+		ModuleName:  "github.com/user/orders",
+		Template:    "microservice",
+		Blueprint:   "microservice-stack",
+		OutputDir:   filepath.Join(t.TempDir(), "orders"),
+	}
+	result, err := generator.InitProject(context.Background(), opts)
+	require.NoError(t, err)
+
+	// The blueprint adds the Dockerfile, compose and monitoring files to the
+	// template's; only the manifest is not counted
+	assert.FileExists(t, filepath.Join(opts.OutputDir, "Dockerfile"))
+	assert.FileExists(t, filepath.Join(opts.OutputDir, "docker-compose.yml"))
+	assert.DirExists(t, filepath.Join(opts.OutputDir, "monitoring"))
+	onDisk := 0
+	require.NoError(t, filepath.WalkDir(opts.OutputDir, func(path string, entry fs.DirEntry, err error) error {
+		if err == nil && !entry.IsDir() && entry.Name() != project.ManifestFile {
+			onDisk++
+		}
+		return err
+	}))
+	assert.Equal(t, onDisk, result.FilesCreated)
+	assert.True(t, strings.HasPrefix(result.Message, fmt.Sprintf("Created %d files in ", onDisk)), result.Message)
+}
+
+func TestProjectGenerator_OpenAPI(t *testing.T) {
+	generator := NewProjectGenerator(templates.NewEngine(), templates.NewRepository())
+	generator.blueprintRepository.Register(blueprints.Blueprint{
+		Name:   "documented-api",
+		Stack:  "api",
+		Config: blueprints.BlueprintConfig{Components: []string{"chi", "swagger"}},
+	})
+	tempDir := t.TempDir()
+
+	opts := InitOptions{
+		ProjectName: "shop",
+		ModuleName:  "github.com/user/shop",
+		Template:    "api",
+		OutputDir:   filepath.Join(tempDir, "plain"),
+	}
+	_, err := generator.InitProject(context.Background(), opts)
+	require.NoError(t, err)
+	spec, err := os.ReadFile(filepath.Join(opts.OutputDir, "api", "openapi.yaml"))
+	require.NoError(t, err)
+	assert.Contains(t, string(spec), "title: shop API")
+	assert.Contains(t, string(spec), "  /health:")
+	main, err := os.ReadFile(filepath.Join(opts.OutputDir, "cmd", "shop", "main.go"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(main), "/docs", "Swagger UI is only served with the swagger component")
+
+	opts.Blueprint = "documented-api"
+	opts.OutputDir = filepath.Join(tempDir, "swagger")
+	result, err := generator.InitProject(context.Background(), opts)
+	require.NoError(t, err)
+	main, err = os.ReadFile(filepath.Join(opts.OutputDir, "cmd", "shop", "main.go"))
+	require.NoError(t, err)
+	assert.Contains(t, string(main), `"github.com/user/shop/api"`)
+	assert.Contains(t, string(main), `r.Get("/docs", api.DocsHandler)`)
+	handlers, err := os.ReadFile(filepath.Join(opts.OutputDir, "api", "openapi.go"))
+	require.NoError(t, err)
+	assert.Contains(t, string(handlers), "func DocsHandler(")
+	assert.Contains(t, result.NextSteps, "Browse the API docs: http://localhost:8080/docs")
+}
+
+func TestProjectGenerator_GraphQLStack(t *testing.T) {
+	generator := NewProjectGenerator(templates.NewEngine(), templates.NewRepository())
+
+	opts := InitOptions{
+		ProjectName: "shop",
+		ModuleName:  "github.com/user/shop",
+		Template:    "api",
+		Blueprint:   "graphql-stack",
+		OutputDir:   t.TempDir(),
+	}
+	result, err := generator.InitProject(context.Background(), opts)
+	require.NoError(t, err)
+
+	for _, file := range []string{"graph/schema.graphqls", "graph/schema.resolvers.go", "gqlgen.yml", "tools.go"} {
+		assert.FileExists(t, filepath.Join(opts.OutputDir, filepath.FromSlash(file)))
+	}
+	main, err := os.ReadFile(filepath.Join(opts.OutputDir, "cmd", "shop", "main.go"))
+	require.NoError(t, err)
+	assert.Contains(t, string(main), `"github.com/user/shop/graph"`)
+	assert.Contains(t, string(main), `mux.Handle("/query", srv)`)
+	resolver, err := os.ReadFile(filepath.Join(opts.OutputDir, "graph", "resolver.go"))
+	require.NoError(t, err)
+	assert.Contains(t, string(resolver), "//go:generate go run github.com/99designs/gqlgen generate")
+	assert.Contains(t, result.NextSteps, "Fetch gqlgen and generate the server code from graph/*.graphqls: go mod tidy && go generate ./...")
+
+	// Types scaffolded by gogo generate resolver get their store on Resolver
+	_, err = components.NewGenerator().Generate(context.Background(), components.GenerateOptions{
+		Type:       "resolver",
+		Name:       "product",
+		OutputDir:  opts.OutputDir,
+		ModuleName: opts.ModuleName,
+	})
+	require.NoError(t, err)
+	resolver, err = os.ReadFile(filepath.Join(opts.OutputDir, "graph", "resolver.go"))
+	require.NoError(t, err)
+	assert.Contains(t, string(resolver), "type Resolver struct {\n\tproductStore ProductStore\n}")
+}
+
+func TestProjectGenerator_WorkerStack(t *testing.T) {
+	generator := NewProjectGenerator(templates.NewEngine(), templates.NewRepository())
+
+	opts := InitOptions{
+		ProjectName: "mailer",
+		ModuleName:  "github.com/user/mailer",
+		Template:    "microservice",
+		Blueprint:   "worker-stack",
+		OutputDir:   t.TempDir(),
+	}
+	result, err := generator.InitProject(context.Background(), opts)
+	require.NoError(t, err)
+
+	for _, file := range []string{"internal/worker/worker.go", "internal/worker/retry.go", "internal/worker/retry_test.go", "Dockerfile"} {
+		assert.FileExists(t, filepath.Join(opts.OutputDir, filepath.FromSlash(file)))
+	}
+	main, err := os.ReadFile(filepath.Join(opts.OutputDir, "cmd", "mailer", "main.go"))
+	require.NoError(t, err)
+	assert.Contains(t, string(main), "jobs.Register(mux)")
+	assert.Contains(t, string(main), "signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)")
+
+	// The blueprint's asynq component picks the queue
+	consumer, err := os.ReadFile(filepath.Join(opts.OutputDir, "internal", "worker", "consumer.go"))
+	require.NoError(t, err)
+	assert.Contains(t, string(consumer), `"github.com/hibiken/asynq"`)
+	assert.NotContains(t, string(consumer), "nats")
+	compose, err := os.ReadFile(filepath.Join(opts.OutputDir, "docker-compose.yml"))
+	require.NoError(t, err)
+	assert.Contains(t, string(compose), "REDIS_ADDR=redis:6379")
+	assert.Contains(t, result.NextSteps, "Start the worker and Redis: docker compose up --build")
+
+	// Jobs scaffolded by gogo generate job are registered with the worker
+	_, err = components.NewGenerator().Generate(context.Background(), components.GenerateOptions{
+		Type:       "job",
+		Name:       "send_email",
+		OutputDir:  opts.OutputDir,
+		ModuleName: opts.ModuleName,
+	})
+	require.NoError(t, err)
+	jobs, err := os.ReadFile(filepath.Join(opts.OutputDir, "internal", "jobs", "jobs.go"))
+	require.NoError(t, err)
+	assert.Contains(t, string(jobs), "func Register(mux *worker.Mux) {\n\tmux.Handle(SendEmailJobType, HandleSendEmail)\n}")
+}
+
+func TestProjectGenerator_LanguageLevel(t *testing.T) {
+	tempDir := t.TempDir()
+	generator := NewProjectGenerator(templates.NewEngine(), templates.NewRepository())
+	ctx := context.Background()
+
+	opts := InitOptions{
+		ProjectName: "mailer",
+		ModuleName:  "github.com/user/mailer",
+		Template:    "microservice",
+		Blueprint:   "worker-stack",
+		GoVersion:   "1.22.5",
+		OutputDir:   filepath.Join(tempDir, "modern"),
+	}
+	_, err := generator.InitProject(ctx, opts)
+	require.NoError(t, err)
+	retry, err := os.ReadFile(filepath.Join(opts.OutputDir, "internal", "worker", "retry.go"))
+	require.NoError(t, err)
+	assert.Contains(t, string(retry), `"math/rand/v2"`)
+	main, err := os.ReadFile(filepath.Join(opts.OutputDir, "cmd", "mailer", "main.go"))
+	require.NoError(t, err)
+	assert.Contains(t, string(main), `"log/slog"`)
+
+	// Code of an older language level falls back to the constructs it has
+	opts.LanguageLevel = "1.20"
+	opts.OutputDir = filepath.Join(tempDir, "fallback")
+	_, err = generator.InitProject(ctx, opts)
+	require.NoError(t, err)
+	retry, err = os.ReadFile(filepath.Join(opts.OutputDir, "internal", "worker", "retry.go"))
+	require.NoError(t, err)
+	assert.Contains(t, string(retry), "\t\"math/rand\"\n")
+	assert.Contains(t, string(retry), "rand.Int63n(")
+	main, err = os.ReadFile(filepath.Join(opts.OutputDir, "cmd", "mailer", "main.go"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(main), "slog")
+	assert.Contains(t, string(main), `log.Printf("worker failed: %v", err)`)
+	worker, err := os.ReadFile(filepath.Join(opts.OutputDir, "internal", "worker", "worker.go"))
+	require.NoError(t, err)
+	assert.Contains(t, string(worker), "sort.Strings(types)")
+	assert.Contains(t, string(worker), "return handler(context.Background(), payload)")
+
+	manifest, err := project.LoadManifest(opts.OutputDir)
+	require.NoError(t, err)
+	assert.Equal(t, "1.20", manifest.Variables["LanguageLevel"])
+	assert.NotContains(t, manifest.Variables, "HasSlog", "feature variables derive from the language level")
+	assert.Equal(t, "1.20", OptionsFromManifest(manifest, tempDir).LanguageLevel)
+
+	opts.LanguageLevel = "1.19"
+	opts.OutputDir = filepath.Join(tempDir, "old")
+	_, err = generator.InitProject(ctx, opts)
+	assert.ErrorContains(t, err, "older than the oldest supported")
+
+	opts.LanguageLevel = "1.23"
+	_, err = generator.InitProject(ctx, opts)
+	assert.ErrorContains(t, err, "newer than Go version 1.22.5")
+}
+
+func TestProjectGenerator_TaskRunner(t *testing.T) {
+	tempDir := t.TempDir()
+	generator := NewProjectGenerator(templates.NewEngine(), templates.NewRepository())
+	ctx := context.Background()
+
+	opts := InitOptions{
+		ProjectName: "orders",
+		ModuleName:  "github.com/user/orders",
+		Template:    "grpc",
+		Blueprint:   "grpc-stack",
+		OutputDir:   filepath.Join(tempDir, "orders"),
+	}
+	result, err := generator.InitProject(ctx, opts)
+	require.NoError(t, err)
+	makefile, err := os.ReadFile(filepath.Join(opts.OutputDir, "Makefile"))
+	require.NoError(t, err)
+	assert.Contains(t, string(makefile), "\nproto:\n")
+	assert.NotContains(t, string(makefile), "migrate-up", "the blueprint has no database")
+	assert.Contains(t, result.NextSteps[0], "then make proto")
+
+	opts.Template = "api"
+	opts.Blueprint = "web-stack"
+	opts.TaskRunner = "task"
+	opts.OutputDir = filepath.Join(tempDir, "web")
+	result, err = generator.InitProject(ctx, opts)
+	require.NoError(t, err)
+	assert.NoFileExists(t, filepath.Join(opts.OutputDir, "Makefile"))
+	taskfile, err := os.ReadFile(filepath.Join(opts.OutputDir, "Taskfile.yml"))
+	require.NoError(t, err)
+	assert.Contains(t, string(taskfile), "\n  migrate-up:\n")
+	assert.Contains(t, string(taskfile), "\n  docker-build:\n")
+	assert.NotContains(t, string(taskfile), "\n  proto:\n")
+
+	manifest, err := project.LoadManifest(opts.OutputDir)
+	require.NoError(t, err)
+	assert.Equal(t, "task", manifest.TaskRunner)
+	assert.Equal(t, "task", OptionsFromManifest(manifest, tempDir).TaskRunner)
+	var tracked []string
+	for _, file := range manifest.Files {
+		tracked = append(tracked, file.Path)
+	}
+	assert.Contains(t, tracked, "Taskfile.yml")
+
+	opts.TaskRunner = "just"
+	opts.OutputDir = filepath.Join(tempDir, "just")
+	_, err = generator.InitProject(ctx, opts)
+	assert.ErrorContains(t, err, "unknown task runner")
+}
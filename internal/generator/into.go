@@ -0,0 +1,223 @@
+package generator
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/user/gogo/internal/fsutil"
+	"github.com/user/gogo/internal/git"
+	"github.com/user/gogo/internal/templates"
+)
+
+// DefaultIntoBranch is the branch gogo init --into commits to
+const DefaultIntoBranch = "gogo/init"
+
+// ExistingRepo describes a git repository gogo init --into generates into
+type ExistingRepo struct {
+	ProjectName string
+	ModuleName  string // derived from the origin remote, empty without one
+	License     string // detected from an existing LICENSE file
+	HasCommits  bool
+	HasGoMod    bool
+}
+
+// DetectExistingRepo inspects the git repository at dir so init can fill in
+// the project name, module path and license the repository already implies
+func DetectExistingRepo(ctx context.Context, dir string) (*ExistingRepo, error) {
+	if !git.IsGitInstalled() {
+		return nil, fmt.Errorf("git is not installed or not available in PATH")
+	}
+
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", dir, err)
+	}
+
+	gitManager := git.NewGitManager(absDir)
+	if !gitManager.IsGitRepository(ctx) {
+		return nil, fmt.Errorf("%s is not a git repository", dir)
+	}
+
+	repo := &ExistingRepo{
+		ProjectName: filepath.Base(absDir),
+		HasCommits:  gitManager.HasCommits(ctx),
+	}
+	if remote, err := gitManager.RemoteURL(ctx, "origin"); err == nil {
+		if module, err := git.ModulePathFromRemote(remote); err == nil {
+			repo.ModuleName = module
+			repo.ProjectName = filepath.Base(module)
+		}
+	}
+	if license, err := os.ReadFile(filepath.Join(absDir, "LICENSE")); err == nil {
+		repo.License = detectLicense(string(license))
+	}
+	if _, err := os.Stat(filepath.Join(absDir, "go.mod")); err == nil {
+		repo.HasGoMod = true
+	}
+
+	return repo, nil
+}
+
+// detectLicense names the license in a LICENSE file, or returns ""
+func detectLicense(content string) string {
+	switch {
+	case strings.Contains(content, "MIT License") || strings.Contains(content, "Permission is hereby granted, free of charge"):
+		return "MIT"
+	case strings.Contains(content, "Apache License"):
+		return "Apache"
+	case strings.Contains(content, "GNU GENERAL PUBLIC LICENSE"):
+		return "GPL"
+	case strings.Contains(content, "BSD"):
+		return "BSD"
+	}
+	return ""
+}
+
+// prepareInto checks that the repository at opts.OutputDir can be generated
+// into and adopts the license it already carries
+func (g *Generator) prepareInto(ctx context.Context, opts *InitOptions) error {
+	repo, err := DetectExistingRepo(ctx, opts.OutputDir)
+	if err != nil {
+		return err
+	}
+	if repo.HasGoMod && !opts.Force {
+		return fmt.Errorf("%s already contains a go.mod (use --force to generate anyway)", opts.OutputDir)
+	}
+	if repo.License != "" {
+		opts.License = repo.License
+	}
+	return nil
+}
+
+// renderInto renders a template file into an existing repository. Files the
+// repository already has are merged where gogo knows how (README.md,
+// .gitignore); anything else is left to the conflict policy. The file at
+// existingPath is merged and the result written to outputPath. It reports
+// whether the file was merged.
+func (g *Generator) renderInto(ctx context.Context, file templates.TemplateFile, variables map[string]any, existingPath, outputPath string) (bool, error) {
+	rendered, err := g.templateEngine.RenderString(ctx, file.Content, variables)
+	if err != nil {
+		return false, err
+	}
+
+	merged := false
+	existing, err := os.ReadFile(existingPath)
+	switch {
+	case err == nil:
+		if content, ok := mergeExisting(filepath.Base(existingPath), string(existing), rendered); ok {
+			rendered, merged = content, true
+		}
+	case !os.IsNotExist(err):
+		return false, fmt.Errorf("failed to read %s: %w", existingPath, err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+		return false, fmt.Errorf("failed to create directory %s: %w", filepath.Dir(outputPath), err)
+	}
+	if err := fsutil.WriteFile(outputPath, []byte(rendered), 0644); err != nil {
+		return false, fmt.Errorf("failed to write file %s: %w", outputPath, err)
+	}
+	return merged, nil
+}
+
+// mergeExisting combines a file the repository already has with the
+// generated one. The existing content always comes first so choices made
+// when the repository was created survive.
+func mergeExisting(name, existing, generated string) (string, bool) {
+	switch name {
+	case ".gitignore":
+		present := make(map[string]bool)
+		for _, line := range strings.Split(existing, "\n") {
+			present[strings.TrimSpace(line)] = true
+		}
+		var missing []string
+		for _, line := range strings.Split(generated, "\n") {
+			if trimmed := strings.TrimSpace(line); !present[trimmed] {
+				present[trimmed] = true
+				missing = append(missing, line)
+			}
+		}
+		if len(missing) == 0 {
+			return existing, true
+		}
+		return strings.TrimRight(existing, "\n") + "\n\n# Added by gogo\n" + strings.Join(missing, "\n") + "\n", true
+	case "README.md":
+		// The repository's README already has a title, so drop the generated one
+		body := generated
+		if title, rest, ok := strings.Cut(body, "\n"); ok && strings.HasPrefix(title, "# ") {
+			body = rest
+		}
+		body = strings.TrimLeft(body, "\n")
+		if strings.Contains(existing, strings.TrimSpace(body)) {
+			return existing, true
+		}
+		return strings.TrimRight(existing, "\n") + "\n\n" + body, true
+	}
+	return "", false
+}
+
+// commitInto commits the generated files on a new branch of the existing
+// repository, leaving the branch that was checked out untouched
+func (g *Generator) commitInto(ctx context.Context, opts InitOptions, result Result) error {
+	gitManager := git.NewGitManager(opts.OutputDir)
+
+	if opts.Branch != "" {
+		if err := gitManager.CreateBranch(ctx, opts.Branch); err != nil {
+			return err
+		}
+	}
+	if err := gitManager.AddAll(ctx); err != nil {
+		return fmt.Errorf("failed to add files to git: %w", err)
+	}
+
+	message, err := intoCommitMessage(opts, result)
+	if err != nil {
+		return err
+	}
+	return gitManager.Commit(ctx, message)
+}
+
+// intoCommitMessage picks the explicit message, then the policy's message
+// template, then IntoCommitMessage, and adds the policy's trailers
+func intoCommitMessage(opts InitOptions, result Result) (string, error) {
+	gitOpts := git.InitOptions{
+		ProjectName:          opts.ProjectName,
+		ModuleName:           opts.ModuleName,
+		Template:             opts.Template,
+		Blueprint:            opts.Blueprint,
+		GogoVersion:          opts.GogoVersion,
+		InitialCommitMessage: opts.InitialCommitMessage,
+		MessageTemplate:      opts.CommitPolicy.MessageTemplate,
+		Trailers:             opts.CommitPolicy.AllTrailers(),
+	}
+	if gitOpts.InitialCommitMessage == "" && gitOpts.MessageTemplate == "" {
+		gitOpts.InitialCommitMessage = IntoCommitMessage(opts, result)
+	}
+	return gitOpts.CommitMessage()
+}
+
+// IntoCommitMessage is the default commit message for gogo init --into. It
+// is written to double as a pull request title and description.
+func IntoCommitMessage(opts InitOptions, result Result) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Scaffold %s with gogo\n\n", opts.ProjectName)
+	fmt.Fprintf(&b, "Generates the %s template", opts.Template)
+	if opts.Blueprint != "" {
+		fmt.Fprintf(&b, " with the %s blueprint", opts.Blueprint)
+	}
+	fmt.Fprintf(&b, " for module %s (%d files).\n", opts.ModuleName, result.FilesCreated)
+
+	if len(result.FilesMerged) > 0 {
+		b.WriteString("\nMerged into existing files:\n")
+		for _, file := range result.FilesMerged {
+			fmt.Fprintf(&b, "- %s\n", file)
+		}
+	}
+	if opts.GogoVersion != "" {
+		fmt.Fprintf(&b, "\nGenerated by gogo %s\n", opts.GogoVersion)
+	}
+	return b.String()
+}
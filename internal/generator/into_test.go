@@ -0,0 +1,138 @@
+package generator
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/user/gogo/internal/git"
+	"github.com/user/gogo/internal/policy"
+	"github.com/user/gogo/internal/templates"
+)
+
+// setupClonedRepo creates a repository that looks like a fresh GitHub clone
+// with a README, LICENSE and .gitignore
+func setupClonedRepo(t *testing.T) string {
+	t.Helper()
+	if !git.IsGitInstalled() {
+		t.Skip("Git is not installed")
+	}
+
+	dir := filepath.Join(t.TempDir(), "service")
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	run := func(args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(output))
+	}
+	run("init", "-q", "-b", "main")
+	run("config", "user.name", "Test Author")
+	run("config", "user.email", "test@example.com")
+	run("remote", "add", "origin", "git@github.com:acme/service.git")
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("# service\n\nPayments service owned by team acme.\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "LICENSE"), []byte("Apache License\nVersion 2.0, January 2004\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".gitignore"), []byte("*.log\n"), 0644))
+	run("add", ".")
+	run("commit", "-q", "-m", "Initial commit")
+	return dir
+}
+
+func TestDetectExistingRepo(t *testing.T) {
+	dir := setupClonedRepo(t)
+
+	repo, err := DetectExistingRepo(context.Background(), dir)
+	require.NoError(t, err)
+	assert.Equal(t, "github.com/acme/service", repo.ModuleName)
+	assert.Equal(t, "service", repo.ProjectName)
+	assert.Equal(t, "Apache", repo.License)
+	assert.True(t, repo.HasCommits)
+	assert.False(t, repo.HasGoMod)
+
+	_, err = DetectExistingRepo(context.Background(), t.TempDir())
+	assert.ErrorContains(t, err, "not a git repository")
+}
+
+func TestProjectGenerator_InitInto(t *testing.T) {
+	dir := setupClonedRepo(t)
+	ctx := context.Background()
+	generator := NewProjectGenerator(templates.NewEngine(), templates.NewRepository())
+
+	opts := InitOptions{
+		ProjectName: "service",
+		ModuleName:  "github.com/acme/service",
+		Template:    "cli",
+		OutputDir:   dir,
+		Into:        true,
+		Branch:      DefaultIntoBranch,
+	}
+	result, err := generator.InitProject(ctx, opts)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"README.md", ".gitignore"}, result.FilesMerged)
+
+	readme, err := os.ReadFile(filepath.Join(dir, "README.md"))
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(readme), "# service\n\nPayments service owned by team acme.\n"), "existing README content comes first")
+	assert.Len(t, regexp.MustCompile(`(?m)^# `).FindAllString(string(readme), -1), 1, "only the original title is kept")
+
+	gitignore, err := os.ReadFile(filepath.Join(dir, ".gitignore"))
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(gitignore), "*.log\n"))
+
+	license, err := os.ReadFile(filepath.Join(dir, "LICENSE"))
+	require.NoError(t, err)
+	assert.Contains(t, string(license), "Apache License", "the LICENSE is never replaced")
+
+	gitManager := git.NewGitManager(dir)
+	branch, err := gitManager.CurrentBranch(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultIntoBranch, branch)
+
+	cmd := exec.Command("git", "status", "--porcelain")
+	cmd.Dir = dir
+	status, err := cmd.Output()
+	require.NoError(t, err)
+	assert.Empty(t, string(status), "everything generated is committed")
+
+	// A second run finds the go.mod and refuses
+	_, err = generator.InitProject(ctx, opts)
+	assert.ErrorContains(t, err, "already contains a go.mod")
+}
+
+func TestMergeExisting(t *testing.T) {
+	content, ok := mergeExisting(".gitignore", "*.log\nbin/\n", "bin/\ncoverage.out\n")
+	assert.True(t, ok)
+	assert.Equal(t, "*.log\nbin/\n\n# Added by gogo\ncoverage.out\n", content)
+
+	content, ok = mergeExisting(".gitignore", "bin/\n", "bin/\n")
+	assert.True(t, ok)
+	assert.Equal(t, "bin/\n", content)
+
+	_, ok = mergeExisting("main.go", "package main\n", "package main\n")
+	assert.False(t, ok)
+}
+
+func TestIntoCommitMessage_Policy(t *testing.T) {
+	opts := InitOptions{ProjectName: "service", ModuleName: "github.com/acme/service", Template: "api", GogoVersion: "1.4.0"}
+	result := Result{FilesCreated: 5}
+
+	message, err := intoCommitMessage(opts, result)
+	require.NoError(t, err)
+	assert.Equal(t, strings.TrimRight(IntoCommitMessage(opts, result), "\n"), message)
+
+	opts.CommitPolicy = policy.CommitPolicy{
+		MessageTemplate: "chore: scaffold {{ ProjectName }} ({{ Template }})",
+		Trailers:        []string{"Generated-by: gogo v{{ GogoVersion }}"},
+		CoAuthors:       []string{"Platform Team <platform@example.com>"},
+	}
+	message, err = intoCommitMessage(opts, result)
+	require.NoError(t, err)
+	assert.Equal(t, "chore: scaffold service (api)\n\nGenerated-by: gogo v1.4.0\nCo-authored-by: Platform Team <platform@example.com>", message)
+}
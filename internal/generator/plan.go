@@ -0,0 +1,221 @@
+package generator
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/user/gogo/internal/fsutil"
+	"github.com/user/gogo/internal/git"
+	"github.com/user/gogo/internal/project"
+)
+
+// PlanVersion is the version of the plan file format
+const PlanVersion = 1
+
+// volatileFiles change with every run, such as the manifest's generation
+// time, so their content is not pinned by a plan
+var volatileFiles = []string{project.ManifestFile, project.ReportDir + "/" + project.ReportFile}
+
+// Plan describes everything gogo init would do with a set of options: the
+// files it would write, the commands and hooks it would run and what it
+// would do with git. It is written by gogo plan for review and carried out
+// by gogo apply, which refuses plans whose files no longer render the same.
+type Plan struct {
+	Version     int          `json:"version"`
+	GogoVersion string       `json:"gogo_version,omitempty"`
+	CreatedAt   time.Time    `json:"created_at"`
+	Options     InitOptions  `json:"options"`
+	Files       []FileChange `json:"files"`              // sorted by path
+	Commands    []string     `json:"commands,omitempty"` // run in the project after its files are written
+	Hooks       []PlanHook   `json:"hooks,omitempty"`    // run on the events of the generation
+	Git         *GitPlan     `json:"git,omitempty"`
+}
+
+// PlanHook is a hook a plan runs, as stored for its event
+type PlanHook struct {
+	Name     string `json:"name"`
+	Event    string `json:"event"`
+	Language string `json:"language"`
+	Script   string `json:"script"`
+}
+
+// GitPlan is what a plan does with git once the files are written
+type GitPlan struct {
+	Init          bool   `json:"init,omitempty"`   // initialize a new repository
+	Branch        string `json:"branch,omitempty"` // commit on this new branch of the existing repository
+	CommitMessage string `json:"commit_message"`
+}
+
+// Count returns the number of planned files of the given kind
+func (p *Plan) Count(kind ChangeKind) int {
+	return (&Preview{Files: p.Files}).Count(kind)
+}
+
+// Plan renders the project of opts aside, as a dry run does, and returns the
+// plan that generates it into opts.OutputDir. Hooks are left for the caller,
+// which knows where they are stored.
+func (g *Generator) Plan(ctx context.Context, opts InitOptions) (*Plan, error) {
+	outputDir, err := filepath.Abs(cmp.Or(opts.OutputDir, "."))
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve output directory: %w", err)
+	}
+	opts.OutputDir = outputDir
+	// The repository's license is the one applied, so plan with it
+	if opts.Into {
+		if err := g.prepareInto(ctx, &opts); err != nil {
+			return nil, err
+		}
+	}
+	opts.DryRun = true
+
+	result, err := g.InitProject(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	opts.DryRun = false
+
+	plan := &Plan{
+		Version:     PlanVersion,
+		GogoVersion: opts.GogoVersion,
+		CreatedAt:   time.Now().UTC().Truncate(time.Second),
+		Options:     opts,
+		Files:       result.Preview.Files,
+	}
+	for i := range plan.Files {
+		if slices.Contains(volatileFiles, plan.Files[i].Path) {
+			plan.Files[i].SHA256 = ""
+		}
+	}
+	if opts.VerifyBuild {
+		for _, args := range buildCommands {
+			plan.Commands = append(plan.Commands, strings.Join(args, " "))
+		}
+	}
+	if plan.Git, err = planGit(opts, result.Preview); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// Apply carries out plan. The project is rendered again first and the plan
+// refused when any file would differ from the planned one, because the
+// templates, blueprint or output directory changed since it was made.
+func (g *Generator) Apply(ctx context.Context, plan *Plan) (Result, error) {
+	if plan.Version != PlanVersion {
+		return Result{}, fmt.Errorf("unsupported plan version %d (this gogo applies version %d)", plan.Version, PlanVersion)
+	}
+	current, err := g.Plan(ctx, plan.Options)
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to check the plan: %w", err)
+	}
+	if stale := StaleFiles(plan.Files, current.Files); len(stale) > 0 {
+		return Result{}, fmt.Errorf("the plan is stale: %s would no longer be generated as planned (run gogo plan again)", strings.Join(stale, ", "))
+	}
+
+	opts := plan.Options
+	opts.DryRun = false
+	// Commit with the reviewed message
+	if plan.Git != nil {
+		opts.InitialCommitMessage = plan.Git.CommitMessage
+	}
+	return g.InitProject(ctx, opts)
+}
+
+// StaleFiles returns the paths whose planned change differs from the
+// current one, or that only one of planned and current has
+func StaleFiles(planned, current []FileChange) []string {
+	changes := make(map[string]FileChange, len(current))
+	for _, change := range current {
+		changes[change.Path] = change
+	}
+	var stale []string
+	for _, change := range planned {
+		now, ok := changes[change.Path]
+		delete(changes, change.Path)
+		if !ok || now.Kind != change.Kind || (change.SHA256 != "" && now.SHA256 != change.SHA256) {
+			stale = append(stale, change.Path)
+		}
+	}
+	for path := range changes {
+		stale = append(stale, path)
+	}
+	slices.Sort(stale)
+	return stale
+}
+
+// SavePlan writes plan to path as indented JSON
+func SavePlan(path string, plan *Plan) error {
+	data, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode plan: %w", err)
+	}
+	if err := fsutil.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write plan: %w", err)
+	}
+	return nil
+}
+
+// LoadPlan reads the plan at path
+func LoadPlan(path string) (*Plan, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plan: %w", err)
+	}
+	var plan Plan
+	if err := json.Unmarshal(data, &plan); err != nil {
+		return nil, fmt.Errorf("failed to parse plan %s: %w", path, err)
+	}
+	if plan.Version == 0 {
+		return nil, fmt.Errorf("%s is not a gogo plan", path)
+	}
+	// JSON numbers decode as floats; templates compare whole numbers as ints
+	for name, value := range plan.Options.Variables {
+		if number, ok := value.(float64); ok && number == math.Trunc(number) {
+			plan.Options.Variables[name] = int(number)
+		}
+	}
+	return &plan, nil
+}
+
+// planGit returns what a generation with opts does with git, nil for nothing
+func planGit(opts InitOptions, preview *Preview) (*GitPlan, error) {
+	switch {
+	case opts.Into:
+		result := Result{FilesCreated: len(preview.Files) - preview.Count(ChangeUnchanged)}
+		for _, file := range preview.Files {
+			if file.Kind == ChangeMerged {
+				result.FilesMerged = append(result.FilesMerged, file.Path)
+			}
+		}
+		message, err := intoCommitMessage(opts, result)
+		if err != nil {
+			return nil, err
+		}
+		return &GitPlan{Branch: opts.Branch, CommitMessage: message}, nil
+	case opts.GitInit:
+		gitOpts := git.InitOptions{
+			ProjectName:          opts.ProjectName,
+			ModuleName:           opts.ModuleName,
+			Template:             opts.Template,
+			Blueprint:            opts.Blueprint,
+			GogoVersion:          opts.GogoVersion,
+			InitialCommitMessage: opts.InitialCommitMessage,
+			MessageTemplate:      opts.CommitPolicy.MessageTemplate,
+			Trailers:             opts.CommitPolicy.AllTrailers(),
+		}
+		message, err := gitOpts.CommitMessage()
+		if err != nil {
+			return nil, err
+		}
+		return &GitPlan{Init: true, CommitMessage: message}, nil
+	}
+	return nil, nil
+}
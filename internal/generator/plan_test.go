@@ -0,0 +1,99 @@
+package generator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/user/gogo/internal/project"
+	"github.com/user/gogo/internal/templates"
+)
+
+func TestProjectGenerator_PlanApply(t *testing.T) {
+	generator := NewProjectGenerator(templates.NewEngine(), templates.NewRepository())
+	ctx := context.Background()
+
+	opts := InitOptions{
+		ProjectName: "planned",
+		ModuleName:  "github.com/user/planned",
+		Template:    "cli",
+		OutputDir:   filepath.Join(t.TempDir(), "planned"),
+		Variables:   map[string]any{"Port": 8080},
+	}
+	plan, err := generator.Plan(ctx, opts)
+	require.NoError(t, err)
+	assert.Equal(t, PlanVersion, plan.Version)
+	assert.NoDirExists(t, opts.OutputDir, "planning writes nothing")
+	assert.Nil(t, plan.Git)
+
+	paths := make(map[string]FileChange)
+	for _, file := range plan.Files {
+		paths[file.Path] = file
+	}
+	require.Contains(t, paths, "go.mod")
+	assert.Equal(t, ChangeNew, paths["go.mod"].Kind)
+	assert.Len(t, paths["go.mod"].SHA256, 64)
+	assert.Empty(t, paths[project.ManifestFile].SHA256, "the manifest changes with every run")
+
+	// The plan survives the round trip through its file
+	planPath := filepath.Join(t.TempDir(), "plan.json")
+	require.NoError(t, SavePlan(planPath, plan))
+	loaded, err := LoadPlan(planPath)
+	require.NoError(t, err)
+	assert.Equal(t, plan.Files, loaded.Files)
+	assert.Equal(t, 8080, loaded.Options.Variables["Port"])
+
+	result, err := generator.Apply(ctx, loaded)
+	require.NoError(t, err)
+	assert.True(t, result.Success)
+	assert.FileExists(t, filepath.Join(opts.OutputDir, "go.mod"))
+
+	// Once applied, the plan no longer describes what would be written
+	_, err = generator.Apply(ctx, loaded)
+	assert.ErrorContains(t, err, "the plan is stale")
+}
+
+func TestProjectGenerator_PlanGit(t *testing.T) {
+	generator := NewProjectGenerator(templates.NewEngine(), templates.NewRepository())
+
+	plan, err := generator.Plan(context.Background(), InitOptions{
+		ProjectName:          "planned",
+		ModuleName:           "github.com/user/planned",
+		Template:             "cli",
+		OutputDir:            filepath.Join(t.TempDir(), "planned"),
+		GitInit:              true,
+		InitialCommitMessage: "chore: scaffold planned",
+	})
+	require.NoError(t, err)
+	require.NotNil(t, plan.Git)
+	assert.True(t, plan.Git.Init)
+	assert.Equal(t, "chore: scaffold planned", plan.Git.CommitMessage)
+}
+
+func TestStaleFiles(t *testing.T) {
+	planned := []FileChange{
+		{Path: ".gogo.yaml", Kind: ChangeNew},
+		{Path: "Makefile", Kind: ChangeNew, SHA256: "a"},
+		{Path: "go.mod", Kind: ChangeNew, SHA256: "b"},
+		{Path: "main.go", Kind: ChangeNew, SHA256: "c"},
+	}
+	current := []FileChange{
+		{Path: ".gogo.yaml", Kind: ChangeNew, SHA256: "d"},
+		{Path: "Makefile", Kind: ChangeNew, SHA256: "a"},
+		{Path: "go.mod", Kind: ChangeModified, SHA256: "b"},
+		{Path: "main.go", Kind: ChangeNew, SHA256: "e"},
+		{Path: "README.md", Kind: ChangeNew, SHA256: "f"},
+	}
+	assert.Equal(t, []string{"README.md", "go.mod", "main.go"}, StaleFiles(planned, current))
+	assert.Empty(t, StaleFiles(planned, planned))
+}
+
+func TestLoadPlan_NotAPlan(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plan.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"files": []}`), 0644))
+	_, err := LoadPlan(path)
+	assert.ErrorContains(t, err, "is not a gogo plan")
+}
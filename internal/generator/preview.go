@@ -0,0 +1,221 @@
+package generator
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/user/gogo/internal/project"
+)
+
+// ChangeKind says what a generation would do to one file
+type ChangeKind string
+
+// Kinds of file changes in a preview
+const (
+	ChangeNew       ChangeKind = "new"
+	ChangeModified  ChangeKind = "modified"
+	ChangeMerged    ChangeKind = "merged"
+	ChangeUnchanged ChangeKind = "unchanged"
+)
+
+// FileChange is one file a generation would write
+type FileChange struct {
+	Path string     `json:"path"` // slash-separated, relative to the output directory
+	Kind ChangeKind `json:"kind"`
+	Diff string     `json:"diff,omitempty"` // unified diff against the existing file, for modified and merged files
+	// SHA256 is the hex digest of the content that would be written
+	SHA256 string `json:"sha256,omitempty"`
+}
+
+// Preview lists every file a generation would write, compared with what the
+// output directory holds now
+type Preview struct {
+	Files []FileChange `json:"files"` // sorted by path
+}
+
+// Count returns the number of files of the given kind
+func (p *Preview) Count(kind ChangeKind) int {
+	n := 0
+	for _, file := range p.Files {
+		if file.Kind == kind {
+			n++
+		}
+	}
+	return n
+}
+
+// Tree renders the files as a directory tree rooted at root, each file
+// labelled with its change kind
+func (p *Preview) Tree(root string) string {
+	return p.tree(root, true)
+}
+
+// FileTree renders the files as a directory tree rooted at root, names only
+func (p *Preview) FileTree(root string) string {
+	return p.tree(root, false)
+}
+
+func (p *Preview) tree(root string, kinds bool) string {
+	var b strings.Builder
+	b.WriteString(strings.TrimSuffix(root, "/") + "/\n")
+
+	var previous []string
+	for _, file := range p.Files {
+		parts := strings.Split(file.Path, "/")
+		dirs := parts[:len(parts)-1]
+
+		// Skip the directories shared with the previous file
+		common := 0
+		for common < len(dirs) && common < len(previous) && dirs[common] == previous[common] {
+			common++
+		}
+		for depth := common; depth < len(dirs); depth++ {
+			fmt.Fprintf(&b, "%s%s/\n", strings.Repeat("  ", depth+1), dirs[depth])
+		}
+		fmt.Fprintf(&b, "%s%s", strings.Repeat("  ", len(dirs)+1), parts[len(parts)-1])
+		if kinds {
+			fmt.Fprintf(&b, "  (%s)", file.Kind)
+		}
+		b.WriteString("\n")
+		previous = dirs
+	}
+	return b.String()
+}
+
+// preview generates the project into a scratch directory and compares every
+// file with the one at the same path under opts.OutputDir. Nothing under
+// opts.OutputDir is written.
+func (g *Generator) preview(ctx context.Context, opts InitOptions) (*Preview, error) {
+	scratch, err := os.MkdirTemp("", "gogo-preview-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create preview directory: %w", err)
+	}
+	defer os.RemoveAll(scratch)
+
+	into := opts.Into
+	rendered := opts
+	rendered.OutputDir = filepath.Join(scratch, filepath.Base(opts.OutputDir))
+	rendered.DryRun = false
+	rendered.Into = false
+	rendered.Branch = ""
+	// Keep the CI files git init would bring without creating a repository
+	rendered.GenerateCI = opts.GenerateCI || opts.GitInit
+	rendered.GitInit = false
+	rendered.StoreState = false
+	if _, err := g.InitProject(ctx, rendered); err != nil {
+		return nil, err
+	}
+
+	preview := &Preview{}
+	err = filepath.WalkDir(rendered.OutputDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(rendered.OutputDir, path)
+		if err != nil {
+			return err
+		}
+		if opts.StoreState && rel == project.ManifestFile {
+			return nil
+		}
+		generated, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		change, err := compareFile(filepath.Join(opts.OutputDir, rel), filepath.ToSlash(rel), string(generated), into)
+		if err != nil {
+			return err
+		}
+		preview.Files = append(preview.Files, change)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare generated files: %w", err)
+	}
+
+	sort.Slice(preview.Files, func(i, j int) bool {
+		return preview.Files[i].Path < preview.Files[j].Path
+	})
+	return preview, nil
+}
+
+// compareFile compares generated content with the file at existingPath. When
+// generating into an existing repository the files gogo merges are compared
+// after merging, as they would be written.
+func compareFile(existingPath, rel, generated string, into bool) (FileChange, error) {
+	change := FileChange{Path: rel, Kind: ChangeNew, SHA256: digest(generated)}
+
+	existing, err := os.ReadFile(existingPath)
+	if os.IsNotExist(err) {
+		return change, nil
+	}
+	if err != nil {
+		return change, fmt.Errorf("failed to read %s: %w", existingPath, err)
+	}
+
+	change.Kind = ChangeModified
+	if into {
+		if merged, ok := mergeExisting(filepath.Base(existingPath), string(existing), generated); ok {
+			generated, change.Kind = merged, ChangeMerged
+			change.SHA256 = digest(generated)
+		}
+	}
+	if string(existing) == generated {
+		change.Kind = ChangeUnchanged
+		return change, nil
+	}
+
+	change.Diff, err = difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(existing)),
+		B:        difflib.SplitLines(generated),
+		FromFile: "a/" + rel,
+		ToFile:   "b/" + rel,
+		Context:  3,
+	})
+	if err != nil {
+		return change, fmt.Errorf("failed to diff %s: %w", rel, err)
+	}
+	return change, nil
+}
+
+// CompareFiles compares the files at paths, slash-separated and relative to
+// generatedDir, with those at the same paths under outputDir, as a dry run
+// of gogo init compares a whole project
+func CompareFiles(outputDir, generatedDir string, paths []string) (*Preview, error) {
+	preview := &Preview{}
+	seen := make(map[string]bool)
+	for _, rel := range paths {
+		if seen[rel] {
+			continue
+		}
+		seen[rel] = true
+		generated, err := os.ReadFile(filepath.Join(generatedDir, filepath.FromSlash(rel)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read generated %s: %w", rel, err)
+		}
+		change, err := compareFile(filepath.Join(outputDir, filepath.FromSlash(rel)), rel, string(generated), false)
+		if err != nil {
+			return nil, err
+		}
+		preview.Files = append(preview.Files, change)
+	}
+
+	sort.Slice(preview.Files, func(i, j int) bool {
+		return preview.Files[i].Path < preview.Files[j].Path
+	})
+	return preview, nil
+}
+
+// digest returns the hex SHA-256 of content
+func digest(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
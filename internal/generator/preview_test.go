@@ -0,0 +1,100 @@
+package generator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/user/gogo/internal/templates"
+)
+
+func TestProjectGenerator_DryRunPreview(t *testing.T) {
+	generator := NewProjectGenerator(templates.NewEngine(), templates.NewRepository())
+	ctx := context.Background()
+
+	opts := InitOptions{
+		ProjectName: "preview",
+		ModuleName:  "github.com/user/preview",
+		Template:    "cli",
+		OutputDir:   filepath.Join(t.TempDir(), "preview"),
+	}
+	_, err := generator.InitProject(ctx, opts)
+	require.NoError(t, err)
+
+	mainPath := filepath.Join(opts.OutputDir, "cmd", "preview", "main.go")
+	require.NoError(t, os.WriteFile(mainPath, []byte("package edited\n"), 0644))
+	require.NoError(t, os.Remove(filepath.Join(opts.OutputDir, "Makefile")))
+
+	opts.DryRun = true
+	result, err := generator.InitProject(ctx, opts)
+	require.NoError(t, err)
+	require.NotNil(t, result.Preview)
+
+	kinds := make(map[string]ChangeKind)
+	for _, file := range result.Preview.Files {
+		kinds[file.Path] = file.Kind
+		if file.Path == "cmd/preview/main.go" {
+			assert.Contains(t, file.Diff, "--- a/cmd/preview/main.go\n+++ b/cmd/preview/main.go\n")
+			assert.Contains(t, file.Diff, "-package edited\n+package main\n")
+		}
+	}
+	assert.Equal(t, ChangeModified, kinds["cmd/preview/main.go"])
+	assert.Equal(t, ChangeNew, kinds["Makefile"])
+	assert.Equal(t, ChangeUnchanged, kinds["go.mod"])
+	assert.Equal(t, len(result.Preview.Files), result.FilesCreated)
+
+	// The dry run leaves the output directory alone
+	content, err := os.ReadFile(mainPath)
+	require.NoError(t, err)
+	assert.Equal(t, "package edited\n", string(content))
+	assert.NoFileExists(t, filepath.Join(opts.OutputDir, "Makefile"))
+
+	tree := result.Preview.Tree("preview")
+	assert.Contains(t, tree, "preview/\n")
+	assert.Contains(t, tree, "  cmd/\n    preview/\n      main.go  (modified)\n")
+	assert.Contains(t, tree, "  Makefile  (new)\n")
+	assert.Contains(t, result.Preview.FileTree("preview"), "  cmd/\n    preview/\n      main.go\n")
+}
+
+func TestCompareFile_Into(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".gitignore")
+	require.NoError(t, os.WriteFile(path, []byte("*.log\n"), 0644))
+
+	change, err := compareFile(path, ".gitignore", "bin/\n", true)
+	require.NoError(t, err)
+	assert.Equal(t, ChangeMerged, change.Kind, "generating into a repository merges .gitignore")
+	assert.Contains(t, change.Diff, "+bin/\n")
+	assert.NotContains(t, change.Diff, "-*.log")
+
+	change, err = compareFile(path, ".gitignore", "bin/\n", false)
+	require.NoError(t, err)
+	assert.Equal(t, ChangeModified, change.Kind)
+	assert.Contains(t, change.Diff, "-*.log\n")
+}
+
+func TestCompareFiles(t *testing.T) {
+	outputDir, generatedDir := t.TempDir(), t.TempDir()
+	for dir, files := range map[string]map[string]string{
+		outputDir:    {"Makefile": "build:\n", "go.mod": "module shop\n"},
+		generatedDir: {"Makefile": "build:\n\nproto:\n", "go.mod": "module shop\n", "proto/shop.proto": "syntax = \"proto3\";\n"},
+	} {
+		for name, content := range files {
+			require.NoError(t, os.MkdirAll(filepath.Join(dir, filepath.Dir(name)), 0755))
+			require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+		}
+	}
+
+	preview, err := CompareFiles(outputDir, generatedDir, []string{"proto/shop.proto", "Makefile", "go.mod", "Makefile"})
+	require.NoError(t, err)
+	require.Len(t, preview.Files, 3)
+	assert.Equal(t, FileChange{Path: "Makefile", Kind: ChangeModified, Diff: preview.Files[0].Diff, SHA256: digest("build:\n\nproto:\n")}, preview.Files[0])
+	assert.Contains(t, preview.Files[0].Diff, "+proto:\n")
+	assert.Equal(t, FileChange{Path: "go.mod", Kind: ChangeUnchanged, SHA256: digest("module shop\n")}, preview.Files[1])
+	assert.Equal(t, FileChange{Path: "proto/shop.proto", Kind: ChangeNew, SHA256: digest("syntax = \"proto3\";\n")}, preview.Files[2])
+
+	_, err = CompareFiles(outputDir, generatedDir, []string{"missing.go"})
+	assert.ErrorContains(t, err, "failed to read generated missing.go")
+}
@@ -0,0 +1,164 @@
+package generator
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// stage collects the files of one generation in a staging directory on the
+// same filesystem as the output directory. Nothing reaches the output
+// directory until commit moves every staged file into place, so a failure
+// halfway through rendering leaves it untouched.
+type stage struct {
+	outputDir string
+	root      string // holds files/ and backup/
+	dir       string // staged files, laid out like the output directory
+	fresh     bool   // the output directory does not exist yet
+}
+
+// newStage creates a staging directory for outputDir: inside it when it
+// exists, next to it otherwise so the whole tree can be renamed into place
+func newStage(outputDir string) (*stage, error) {
+	s := &stage{outputDir: outputDir}
+
+	parent := outputDir
+	if _, err := os.Stat(outputDir); os.IsNotExist(err) {
+		s.fresh = true
+		parent = filepath.Dir(outputDir)
+		if err := os.MkdirAll(parent, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create directory %s: %w", parent, err)
+		}
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read output directory: %w", err)
+	}
+
+	root, err := os.MkdirTemp(parent, ".gogo-stage-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create staging directory: %w", err)
+	}
+	s.root = root
+	s.dir = filepath.Join(root, "files")
+	if err := os.Mkdir(s.dir, 0755); err != nil {
+		os.RemoveAll(root)
+		return nil, fmt.Errorf("failed to create staging directory: %w", err)
+	}
+	return s, nil
+}
+
+// discard removes the staging directory and everything staged in it
+func (s *stage) discard() {
+	os.RemoveAll(s.root)
+}
+
+// commit moves the staged files into the output directory and removes the
+// staging directory. Files the output directory already has are replaced;
+// when a move fails, every file moved so far is put back as it was.
+func (s *stage) commit() error {
+	defer s.discard()
+
+	if s.fresh {
+		if err := os.Rename(s.dir, s.outputDir); err != nil {
+			return fmt.Errorf("failed to move files into %s: %w", s.outputDir, err)
+		}
+		return nil
+	}
+
+	files, err := s.files()
+	if err != nil {
+		return err
+	}
+
+	backupDir := filepath.Join(s.root, "backup")
+	var (
+		moved       []string
+		backedUp    = make(map[string]bool)
+		createdDirs []string
+	)
+	rollback := func() {
+		for i := len(moved) - 1; i >= 0; i-- {
+			rel := moved[i]
+			target := filepath.Join(s.outputDir, rel)
+			os.Remove(target)
+			if backedUp[rel] {
+				os.Rename(filepath.Join(backupDir, rel), target)
+			}
+		}
+		for i := len(createdDirs) - 1; i >= 0; i-- {
+			os.Remove(createdDirs[i]) // only succeeds while empty
+		}
+	}
+
+	for _, rel := range files {
+		target := filepath.Join(s.outputDir, rel)
+		created, err := mkdirAllTracked(filepath.Dir(target))
+		createdDirs = append(createdDirs, created...)
+		if err != nil {
+			rollback()
+			return fmt.Errorf("failed to create directory for %s: %w", rel, err)
+		}
+
+		if _, err := os.Lstat(target); err == nil {
+			backup := filepath.Join(backupDir, rel)
+			if err := os.MkdirAll(filepath.Dir(backup), 0755); err != nil {
+				rollback()
+				return fmt.Errorf("failed to back up %s: %w", rel, err)
+			}
+			if err := os.Rename(target, backup); err != nil {
+				rollback()
+				return fmt.Errorf("failed to back up %s: %w", rel, err)
+			}
+			backedUp[rel] = true
+		}
+
+		if err := os.Rename(filepath.Join(s.dir, rel), target); err != nil {
+			if backedUp[rel] {
+				os.Rename(filepath.Join(backupDir, rel), target)
+			}
+			rollback()
+			return fmt.Errorf("failed to move %s into place: %w", rel, err)
+		}
+		moved = append(moved, rel)
+	}
+	return nil
+}
+
+// files lists the staged files relative to the staging directory
+func (s *stage) files() ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(s.dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(s.dir, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, rel)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list staged files: %w", err)
+	}
+	return files, nil
+}
+
+// mkdirAllTracked creates dir and any missing parents, returning the
+// directories it created from the outermost in
+func mkdirAllTracked(dir string) ([]string, error) {
+	var missing []string
+	for d := dir; ; d = filepath.Dir(d) {
+		if _, err := os.Stat(d); err == nil {
+			break
+		}
+		missing = append([]string{d}, missing...)
+		if filepath.Dir(d) == d {
+			break
+		}
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return missing, nil
+}
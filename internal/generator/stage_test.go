@@ -0,0 +1,123 @@
+package generator
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/user/gogo/internal/templates"
+)
+
+// brokenStore serves a template whose second file fails to render
+type brokenStore struct{}
+
+func (brokenStore) StoredTemplateFiles(ctx context.Context, name string) ([]templates.TemplateFile, bool, error) {
+	if name != "broken" {
+		return nil, false, nil
+	}
+	return []templates.TemplateFile{
+		{Name: "README.md", Path: "README.md", Content: "# {{ ProjectName }}\n"},
+		{Name: "main.go", Path: "cmd/{{ ProjectName }}/main.go", Content: "{% if ProjectName %}package main\n"},
+	}, true, nil
+}
+
+func newBrokenGenerator() *Generator {
+	repo := templates.NewRepository()
+	repo.SetStore(brokenStore{})
+	return NewProjectGenerator(templates.NewEngine(), repo)
+}
+
+// stagingDirs returns the staging directories left in dir
+func stagingDirs(t *testing.T, dir string) []string {
+	matches, err := filepath.Glob(filepath.Join(dir, ".gogo-stage-*"))
+	require.NoError(t, err)
+	return matches
+}
+
+func TestProjectGenerator_RollbackNewDirectory(t *testing.T) {
+	tempDir := t.TempDir()
+	opts := InitOptions{
+		ProjectName: "broken",
+		ModuleName:  "github.com/user/broken",
+		Template:    "broken",
+		OutputDir:   filepath.Join(tempDir, "broken"),
+	}
+
+	_, err := newBrokenGenerator().InitProject(context.Background(), opts)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to render file main.go")
+
+	assert.NoDirExists(t, opts.OutputDir)
+	assert.Empty(t, stagingDirs(t, tempDir))
+}
+
+func TestProjectGenerator_RollbackExistingDirectory(t *testing.T) {
+	outputDir := t.TempDir()
+	readme := filepath.Join(outputDir, "README.md")
+	require.NoError(t, os.WriteFile(readme, []byte("# mine\n"), 0644))
+
+	opts := InitOptions{
+		ProjectName: "broken",
+		ModuleName:  "github.com/user/broken",
+		Template:    "broken",
+		OutputDir:   outputDir,
+	}
+	_, err := newBrokenGenerator().InitProject(context.Background(), opts)
+	require.Error(t, err)
+
+	content, err := os.ReadFile(readme)
+	require.NoError(t, err)
+	assert.Equal(t, "# mine\n", string(content))
+
+	entries, err := os.ReadDir(outputDir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1, "nothing but the existing file is left")
+	assert.Equal(t, "README.md", entries[0].Name())
+}
+
+func TestProjectGenerator_KeepPartial(t *testing.T) {
+	tempDir := t.TempDir()
+	opts := InitOptions{
+		ProjectName: "broken",
+		ModuleName:  "github.com/user/broken",
+		Template:    "broken",
+		OutputDir:   filepath.Join(tempDir, "broken"),
+		KeepPartial: true,
+	}
+
+	_, err := newBrokenGenerator().InitProject(context.Background(), opts)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "partial files kept in "+opts.OutputDir)
+
+	content, err := os.ReadFile(filepath.Join(opts.OutputDir, "README.md"))
+	require.NoError(t, err)
+	assert.Equal(t, "# broken\n", string(content))
+	assert.Empty(t, stagingDirs(t, tempDir))
+}
+
+func TestStage_CommitIntoExistingDirectory(t *testing.T) {
+	outputDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(outputDir, "keep.txt"), []byte("keep\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(outputDir, "replace.txt"), []byte("old\n"), 0644))
+
+	s, err := newStage(outputDir)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(s.dir, "replace.txt"), []byte("new\n"), 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(s.dir, "sub"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(s.dir, "sub", "added.txt"), []byte("added\n"), 0644))
+	require.NoError(t, s.commit())
+
+	for name, want := range map[string]string{
+		"keep.txt":      "keep\n",
+		"replace.txt":   "new\n",
+		"sub/added.txt": "added\n",
+	} {
+		content, err := os.ReadFile(filepath.Join(outputDir, name))
+		require.NoError(t, err)
+		assert.Equal(t, want, string(content), name)
+	}
+	assert.Empty(t, stagingDirs(t, outputDir))
+}
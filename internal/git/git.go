@@ -26,9 +26,43 @@ func NewGitManager(workingDir string) *GitManager {
 // InitOptions contains options for git initialization
 type InitOptions struct {
 	ProjectName          string
+	ModuleName           string
+	Template             string
+	Blueprint            string
+	GogoVersion          string
 	Author               string
 	Email                string
 	InitialCommitMessage string
+	// MessageTemplate is a pongo2 template for the initial commit message,
+	// used when InitialCommitMessage is empty (default DefaultMessageTemplate)
+	MessageTemplate string
+	// Trailers are appended to the initial commit message; they are
+	// templates too, e.g. "Generated-by: gogo {{ GogoVersion }}"
+	Trailers []string
+}
+
+// CommitVars returns the variables commit templates are rendered with
+func (o InitOptions) CommitVars() CommitVars {
+	return CommitVars{
+		ProjectName: o.ProjectName,
+		ModuleName:  o.ModuleName,
+		Template:    o.Template,
+		Blueprint:   o.Blueprint,
+		GogoVersion: o.GogoVersion,
+	}
+}
+
+// CommitMessage builds the initial commit message from the configured
+// message or template and trailers
+func (o InitOptions) CommitMessage() (string, error) {
+	if o.InitialCommitMessage != "" {
+		return AppendTrailers(o.InitialCommitMessage, o.Trailers, o.CommitVars())
+	}
+	template := o.MessageTemplate
+	if template == "" {
+		template = DefaultMessageTemplate
+	}
+	return RenderCommitMessage(template, o.Trailers, o.CommitVars())
 }
 
 // IsGitInstalled checks if git is available in the system
@@ -107,9 +141,9 @@ func (g *GitManager) InitialCommit(ctx context.Context, opts InitOptions) error
 	}
 
 	// Create initial commit
-	message := opts.InitialCommitMessage
-	if message == "" {
-		message = fmt.Sprintf("Initial commit: %s project created with gogo", opts.ProjectName)
+	message, err := opts.CommitMessage()
+	if err != nil {
+		return err
 	}
 
 	if err := g.Commit(ctx, message); err != nil {
@@ -119,6 +153,156 @@ func (g *GitManager) InitialCommit(ctx context.Context, opts InitOptions) error
 	return nil
 }
 
+// RemoteURL returns the URL of the named remote
+func (g *GitManager) RemoteURL(ctx context.Context, remote string) (string, error) {
+	output, err := g.gitOutput(ctx, "remote", "get-url", remote)
+	if err != nil {
+		return "", fmt.Errorf("failed to read remote %s: %w", remote, err)
+	}
+	return output, nil
+}
+
+// HasCommits reports whether the current branch has any commits; a freshly
+// cloned empty repository has none
+func (g *GitManager) HasCommits(ctx context.Context) bool {
+	_, err := g.gitOutput(ctx, "rev-parse", "--verify", "--quiet", "HEAD")
+	return err == nil
+}
+
+// CurrentBranch returns the name of the checked out branch
+func (g *GitManager) CurrentBranch(ctx context.Context) (string, error) {
+	output, err := g.gitOutput(ctx, "symbolic-ref", "--short", "HEAD")
+	if err != nil {
+		return "", fmt.Errorf("failed to determine current branch: %w", err)
+	}
+	return output, nil
+}
+
+// CreateBranch creates and checks out a new branch
+func (g *GitManager) CreateBranch(ctx context.Context, name string) error {
+	if err := g.runGitCommand(ctx, "checkout", "-b", name); err != nil {
+		return fmt.Errorf("failed to create branch %s: %w", name, err)
+	}
+	return nil
+}
+
+// BranchExists reports whether a local branch exists
+func (g *GitManager) BranchExists(ctx context.Context, name string) bool {
+	_, err := g.gitOutput(ctx, "rev-parse", "--verify", "--quiet", "refs/heads/"+name)
+	return err == nil
+}
+
+// IsClean reports whether the working tree has no staged, unstaged or
+// untracked changes
+func (g *GitManager) IsClean(ctx context.Context) (bool, error) {
+	output, err := g.gitOutput(ctx, "status", "--porcelain")
+	if err != nil {
+		return false, fmt.Errorf("failed to read git status: %w", err)
+	}
+	return output == "", nil
+}
+
+// BranchSession applies changes on a new branch and switches back to the
+// branch that was checked out, so the changes can be reviewed separately
+type BranchSession struct {
+	manager  *GitManager
+	original string
+	Branch   string
+}
+
+// StartBranch checks that the working tree is clean and checks out a new
+// branch named name. Finish the session with Commit or Abort.
+func (g *GitManager) StartBranch(ctx context.Context, name string) (*BranchSession, error) {
+	if !IsGitInstalled() {
+		return nil, fmt.Errorf("git is not installed or not available in PATH")
+	}
+	if !g.IsGitRepository(ctx) {
+		return nil, fmt.Errorf("%s is not a git repository", g.workingDir)
+	}
+	if !g.HasCommits(ctx) {
+		return nil, fmt.Errorf("the repository has no commits to branch from")
+	}
+	clean, err := g.IsClean(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !clean {
+		return nil, fmt.Errorf("the working tree has uncommitted changes; commit or stash them first")
+	}
+
+	original, err := g.CurrentBranch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := g.CreateBranch(ctx, name); err != nil {
+		return nil, err
+	}
+	return &BranchSession{manager: g, original: original, Branch: name}, nil
+}
+
+// Commit commits every change on the session branch and switches back
+func (s *BranchSession) Commit(ctx context.Context, message string) error {
+	if err := s.manager.runGitCommand(ctx, "add", "-A"); err != nil {
+		return fmt.Errorf("failed to add files to git: %w", err)
+	}
+	if err := s.manager.Commit(ctx, message); err != nil {
+		return fmt.Errorf("failed to commit on %s: %w", s.Branch, err)
+	}
+	if err := s.manager.runGitCommand(ctx, "checkout", "-q", s.original); err != nil {
+		return fmt.Errorf("failed to switch back to %s: %w", s.original, err)
+	}
+	return nil
+}
+
+// Abort discards the changes made on the session branch, switches back and
+// deletes the branch. The tree was clean when the session started, so only
+// the session's changes are lost.
+func (s *BranchSession) Abort(ctx context.Context) error {
+	for _, args := range [][]string{
+		{"reset", "-q", "--hard"},
+		{"clean", "-fdq"},
+		{"checkout", "-q", s.original},
+		{"branch", "-q", "-D", s.Branch},
+	} {
+		if err := s.manager.runGitCommand(ctx, args...); err != nil {
+			return fmt.Errorf("failed to abandon branch %s: %w", s.Branch, err)
+		}
+	}
+	return nil
+}
+
+// ModulePathFromRemote derives a Go module path from a git remote URL, e.g.
+// git@github.com:user/repo.git and https://github.com/user/repo both give
+// github.com/user/repo
+func ModulePathFromRemote(remote string) (string, error) {
+	path := strings.TrimSpace(remote)
+	path = strings.TrimSuffix(strings.TrimSuffix(path, "/"), ".git")
+
+	if scheme, rest, ok := strings.Cut(path, "://"); ok {
+		if scheme == "file" {
+			return "", fmt.Errorf("cannot derive a module path from local remote %s", remote)
+		}
+		path = rest
+		if _, host, ok := strings.Cut(path, "@"); ok {
+			path = host
+		}
+		// Drop a port from the host
+		if host, rest, ok := strings.Cut(path, "/"); ok {
+			host, _, _ = strings.Cut(host, ":")
+			path = host + "/" + rest
+		}
+	} else if user, rest, ok := strings.Cut(path, "@"); ok && !strings.Contains(user, "/") {
+		// scp-like syntax: git@host:owner/repo
+		path = strings.Replace(rest, ":", "/", 1)
+	}
+
+	host, _, _ := strings.Cut(path, "/")
+	if !strings.Contains(host, ".") || strings.Count(path, "/") < 1 {
+		return "", fmt.Errorf("cannot derive a module path from remote %s", remote)
+	}
+	return path, nil
+}
+
 // GetUserInfo retrieves git user information
 func GetUserInfo(ctx context.Context) (name string, email string) {
 	// Try to get user name
@@ -152,6 +336,19 @@ func (g *GitManager) runGitCommand(ctx context.Context, args ...string) error {
 	return nil
 }
 
+// gitOutput runs a git command in the working directory and returns its
+// trimmed standard output
+func (g *GitManager) gitOutput(ctx context.Context, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = g.workingDir
+
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git command failed: %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
 // setGitConfig sets a git configuration value
 func (g *GitManager) setGitConfig(ctx context.Context, key, value string) error {
 	return g.runGitCommand(ctx, "config", key, value)
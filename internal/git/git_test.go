@@ -206,3 +206,133 @@ func TestGetUserInfo(t *testing.T) {
 	assert.IsType(t, "", email)
 	t.Logf("Git user info - Name: %s, Email: %s", name, email)
 }
+
+func TestModulePathFromRemote(t *testing.T) {
+	tests := []struct {
+		remote   string
+		expected string
+		wantErr  bool
+	}{
+		{remote: "git@github.com:user/repo.git", expected: "github.com/user/repo"},
+		{remote: "https://github.com/user/repo", expected: "github.com/user/repo"},
+		{remote: "https://github.com/user/repo.git\n", expected: "github.com/user/repo"},
+		{remote: "ssh://git@gitlab.example.com:2222/group/sub/repo.git", expected: "gitlab.example.com/group/sub/repo"},
+		{remote: "https://token@github.com/user/repo/", expected: "github.com/user/repo"},
+		{remote: "file:///srv/git/repo.git", wantErr: true},
+		{remote: "/srv/git/repo.git", wantErr: true},
+		{remote: "localhost:repo", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.remote, func(t *testing.T) {
+			path, err := ModulePathFromRemote(tt.remote)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, path)
+		})
+	}
+}
+
+func TestGitManager_Branches(t *testing.T) {
+	if !IsGitInstalled() {
+		t.Skip("Git is not installed")
+	}
+
+	ctx := context.Background()
+	tmpDir := t.TempDir()
+	manager := NewGitManager(tmpDir)
+	require.NoError(t, manager.Init(ctx, InitOptions{ProjectName: "test"}))
+	assert.False(t, manager.HasCommits(ctx), "a fresh repository has no commits")
+
+	require.NoError(t, manager.setGitConfig(ctx, "remote.origin.url", "git@github.com:user/test.git"))
+	remote, err := manager.RemoteURL(ctx, "origin")
+	require.NoError(t, err)
+	assert.Equal(t, "git@github.com:user/test.git", remote)
+
+	require.NoError(t, manager.CreateBranch(ctx, "gogo/scaffold"))
+	branch, err := manager.CurrentBranch(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "gogo/scaffold", branch)
+}
+
+func TestGitManager_BranchSession(t *testing.T) {
+	if !IsGitInstalled() {
+		t.Skip("Git is not installed")
+	}
+
+	ctx := context.Background()
+	tmpDir := t.TempDir()
+	manager := NewGitManager(tmpDir)
+	require.NoError(t, manager.runGitCommand(ctx, "init", "-q", "-b", "main"))
+	require.NoError(t, manager.setGitConfig(ctx, "user.name", "Test Author"))
+	require.NoError(t, manager.setGitConfig(ctx, "user.email", "test@example.com"))
+
+	_, err := manager.StartBranch(ctx, "gogo/update")
+	assert.ErrorContains(t, err, "no commits")
+
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "README.md"), []byte("# Test\n"), 0644))
+	require.NoError(t, manager.InitialCommit(ctx, InitOptions{ProjectName: "test"}))
+
+	// Committed changes land on the new branch only
+	session, err := manager.StartBranch(ctx, "gogo/update")
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "handler.go"), []byte("package main\n"), 0644))
+	require.NoError(t, session.Commit(ctx, "Add handler"))
+
+	branch, err := manager.CurrentBranch(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "main", branch)
+	assert.NoFileExists(t, filepath.Join(tmpDir, "handler.go"))
+	assert.True(t, manager.BranchExists(ctx, "gogo/update"))
+
+	// Aborted sessions leave nothing behind
+	session, err = manager.StartBranch(ctx, "gogo/aborted")
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "model.go"), []byte("package main\n"), 0644))
+	require.NoError(t, session.Abort(ctx))
+	assert.NoFileExists(t, filepath.Join(tmpDir, "model.go"))
+	assert.False(t, manager.BranchExists(ctx, "gogo/aborted"))
+
+	// Sessions refuse to start over uncommitted work
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "wip.go"), []byte("package main\n"), 0644))
+	_, err = manager.StartBranch(ctx, "gogo/dirty")
+	assert.ErrorContains(t, err, "uncommitted changes")
+}
+
+func TestGitManager_FileHistory(t *testing.T) {
+	if !IsGitInstalled() {
+		t.Skip("Git is not installed")
+	}
+
+	ctx := context.Background()
+	tmpDir := t.TempDir()
+	manager := NewGitManager(tmpDir)
+	require.NoError(t, manager.runGitCommand(ctx, "init", "-q", "-b", "main"))
+	require.NoError(t, manager.setGitConfig(ctx, "user.name", "Test Author"))
+	require.NoError(t, manager.setGitConfig(ctx, "user.email", "test@example.com"))
+
+	revisions, err := manager.FileRevisions(ctx, "Makefile")
+	assert.Error(t, err, "a repository without commits has no history")
+	assert.Empty(t, revisions)
+
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "Makefile"), []byte("build:\n\tgo build\n\n"), 0644))
+	require.NoError(t, manager.InitialCommit(ctx, InitOptions{ProjectName: "test"}))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "Makefile"), []byte("build:\n\tgo build ./...\n"), 0644))
+	require.NoError(t, manager.AddAll(ctx))
+	require.NoError(t, manager.Commit(ctx, "Build all packages"))
+
+	revisions, err = manager.FileRevisions(ctx, "Makefile")
+	require.NoError(t, err)
+	require.Len(t, revisions, 2)
+
+	content, err := manager.ShowFile(ctx, revisions[1], "Makefile")
+	require.NoError(t, err)
+	assert.Equal(t, "build:\n\tgo build\n\n", string(content), "content is not trimmed")
+
+	revisions, err = manager.FileRevisions(ctx, "Dockerfile")
+	require.NoError(t, err)
+	assert.Empty(t, revisions)
+}
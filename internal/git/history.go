@@ -0,0 +1,35 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// FileRevisions returns the commits that changed path, newest first. The path
+// is relative to the working directory.
+func (g *GitManager) FileRevisions(ctx context.Context, path string) ([]string, error) {
+	output, err := g.gitOutput(ctx, "log", "--format=%H", "--", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list revisions of %s: %w", path, err)
+	}
+	if output == "" {
+		return nil, nil
+	}
+	return strings.Split(output, "\n"), nil
+}
+
+// ShowFile returns the content of path, relative to the working directory,
+// at a revision
+func (g *GitManager) ShowFile(ctx context.Context, rev, path string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "git", "show", rev+":./"+path)
+	cmd.Dir = g.workingDir
+
+	// Not gitOutput: the content must not be trimmed
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s at %s: %w", path, rev, err)
+	}
+	return output, nil
+}
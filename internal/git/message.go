@@ -0,0 +1,107 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/flosch/pongo2/v6"
+)
+
+// DefaultMessageTemplate is the initial commit message used when neither a
+// message nor a message template is configured
+const DefaultMessageTemplate = "Initial commit: {{ ProjectName }} project created with gogo"
+
+// CommitVars are the variables available to commit message templates and
+// trailers, e.g. "Generated-by: gogo {{ GogoVersion }}"
+type CommitVars struct {
+	ProjectName string
+	ModuleName  string
+	Template    string
+	Blueprint   string
+	GogoVersion string
+}
+
+func (v CommitVars) context() pongo2.Context {
+	return pongo2.Context{
+		"ProjectName": v.ProjectName,
+		"ModuleName":  v.ModuleName,
+		"Template":    v.Template,
+		"Blueprint":   v.Blueprint,
+		"GogoVersion": v.GogoVersion,
+	}
+}
+
+// RenderCommitMessage renders a commit message template and appends the
+// rendered trailers
+func RenderCommitMessage(template string, trailers []string, vars CommitVars) (string, error) {
+	message, err := renderCommitTemplate(template, vars)
+	if err != nil {
+		return "", fmt.Errorf("invalid commit message template: %w", err)
+	}
+	return AppendTrailers(message, trailers, vars)
+}
+
+// AppendTrailers renders trailer templates such as "Co-authored-by: {{ ... }}"
+// and appends them to message as a trailer block. Trailers the message
+// already carries are not repeated.
+func AppendTrailers(message string, trailers []string, vars CommitVars) (string, error) {
+	message = strings.TrimRight(message, "\n")
+
+	var block []string
+	for _, trailer := range trailers {
+		rendered, err := renderCommitTemplate(trailer, vars)
+		if err != nil {
+			return "", fmt.Errorf("invalid commit trailer %q: %w", trailer, err)
+		}
+		rendered = strings.TrimSpace(rendered)
+		if !isTrailer(rendered) {
+			return "", fmt.Errorf("invalid commit trailer %q: expected \"Token: value\"", rendered)
+		}
+		if containsLine(message, rendered) || containsLine(strings.Join(block, "\n"), rendered) {
+			continue
+		}
+		block = append(block, rendered)
+	}
+	if len(block) == 0 {
+		return message, nil
+	}
+
+	// Extend an existing trailer block rather than starting a second one
+	separator := "\n\n"
+	if paragraphs := strings.Split(message, "\n\n"); len(paragraphs) > 1 && isTrailerBlock(paragraphs[len(paragraphs)-1]) {
+		separator = "\n"
+	}
+	return message + separator + strings.Join(block, "\n"), nil
+}
+
+func renderCommitTemplate(template string, vars CommitVars) (string, error) {
+	tpl, err := pongo2.FromString(template)
+	if err != nil {
+		return "", err
+	}
+	return tpl.Execute(vars.context())
+}
+
+// isTrailer reports whether line has the git trailer form "Token: value"
+func isTrailer(line string) bool {
+	token, value, ok := strings.Cut(line, ": ")
+	return ok && token != "" && strings.TrimSpace(value) != "" && !strings.ContainsAny(token, " \t\n")
+}
+
+func isTrailerBlock(paragraph string) bool {
+	for _, line := range strings.Split(paragraph, "\n") {
+		if !isTrailer(line) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsLine(text, line string) bool {
+	for _, existing := range strings.Split(text, "\n") {
+		if existing == line {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,69 @@
+package git
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderCommitMessage(t *testing.T) {
+	vars := CommitVars{ProjectName: "myapi", Template: "api", GogoVersion: "1.4.0"}
+
+	tests := []struct {
+		name     string
+		template string
+		trailers []string
+		expected string
+		wantErr  bool
+	}{
+		{
+			name:     "default template",
+			template: DefaultMessageTemplate,
+			expected: "Initial commit: myapi project created with gogo",
+		},
+		{
+			name:     "conventional commit with trailers",
+			template: "chore: scaffold {{ ProjectName }} from the {{ Template }} template",
+			trailers: []string{"Generated-by: gogo v{{ GogoVersion }}", "Co-authored-by: Jane Doe <jane@example.com>"},
+			expected: "chore: scaffold myapi from the api template\n\nGenerated-by: gogo v1.4.0\nCo-authored-by: Jane Doe <jane@example.com>",
+		},
+		{
+			name:     "duplicate trailers are dropped",
+			template: "chore: scaffold",
+			trailers: []string{"Generated-by: gogo", "Generated-by: gogo"},
+			expected: "chore: scaffold\n\nGenerated-by: gogo",
+		},
+		{
+			name:     "invalid trailer",
+			template: "chore: scaffold",
+			trailers: []string{"not a trailer"},
+			wantErr:  true,
+		},
+		{
+			name:     "invalid template",
+			template: "chore: {{ ProjectName",
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message, err := RenderCommitMessage(tt.template, tt.trailers, vars)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, message)
+		})
+	}
+}
+
+func TestAppendTrailers_ExtendsExistingBlock(t *testing.T) {
+	message := "Scaffold myapi\n\nBody text.\n\nSigned-off-by: Jane Doe <jane@example.com>\n"
+
+	result, err := AppendTrailers(message, []string{"Generated-by: gogo", "Signed-off-by: Jane Doe <jane@example.com>"}, CommitVars{})
+	require.NoError(t, err)
+	assert.Equal(t, "Scaffold myapi\n\nBody text.\n\nSigned-off-by: Jane Doe <jane@example.com>\nGenerated-by: gogo", result)
+}
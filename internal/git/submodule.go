@@ -0,0 +1,140 @@
+package git
+
+import (
+	"context"
+	"fmt"
+)
+
+// TopLevel returns the root directory of the repository
+func (g *GitManager) TopLevel(ctx context.Context) (string, error) {
+	output, err := g.gitOutput(ctx, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", fmt.Errorf("failed to find repository root: %w", err)
+	}
+	return output, nil
+}
+
+// RevParse resolves a revision to a commit hash
+func (g *GitManager) RevParse(ctx context.Context, rev string) (string, error) {
+	output, err := g.gitOutput(ctx, "rev-parse", "--verify", rev+"^{commit}")
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", rev, err)
+	}
+	return output, nil
+}
+
+// Fetch fetches ref, or the remote's HEAD when ref is empty, from a URL or
+// remote name and returns the fetched commit
+func (g *GitManager) Fetch(ctx context.Context, url, ref string) (string, error) {
+	if err := g.runGitCommand(ctx, "fetch", "--quiet", url, refOrHead(ref)); err != nil {
+		return "", fmt.Errorf("failed to fetch %s from %s: %w", refOrHead(ref), url, err)
+	}
+	return g.RevParse(ctx, "FETCH_HEAD")
+}
+
+// Checkout checks out a revision, detaching HEAD for commits and tags
+func (g *GitManager) Checkout(ctx context.Context, rev string) error {
+	if err := g.runGitCommand(ctx, "checkout", "--quiet", rev); err != nil {
+		return fmt.Errorf("failed to check out %s: %w", rev, err)
+	}
+	return nil
+}
+
+// Add stages the given paths
+func (g *GitManager) Add(ctx context.Context, paths ...string) error {
+	return g.runGitCommand(ctx, append([]string{"add", "--"}, paths...)...)
+}
+
+// AmendWith stages paths and folds them into the last commit
+func (g *GitManager) AmendWith(ctx context.Context, paths ...string) error {
+	if err := g.Add(ctx, paths...); err != nil {
+		return err
+	}
+	if err := g.runGitCommand(ctx, "commit", "--quiet", "--amend", "--no-edit"); err != nil {
+		return fmt.Errorf("failed to amend commit: %w", err)
+	}
+	return nil
+}
+
+// SubmoduleAdd adds url as a submodule at path, tracking branch when set
+func (g *GitManager) SubmoduleAdd(ctx context.Context, url, path, branch string) error {
+	args := []string{"submodule", "add", "--quiet"}
+	if branch != "" {
+		args = append(args, "-b", branch)
+	}
+	if err := g.runGitCommand(ctx, append(args, url, path)...); err != nil {
+		return fmt.Errorf("failed to add submodule %s: %w", path, err)
+	}
+	return nil
+}
+
+// SubmoduleUpdate checks out the recorded commit of the submodule at path,
+// cloning it first if needed
+func (g *GitManager) SubmoduleUpdate(ctx context.Context, path string) error {
+	if err := g.runGitCommand(ctx, "submodule", "update", "--quiet", "--init", "--", path); err != nil {
+		return fmt.Errorf("failed to update submodule %s: %w", path, err)
+	}
+	return nil
+}
+
+// SubtreeAdd imports commit, which must already be fetched, as a squashed
+// subtree at prefix
+func (g *GitManager) SubtreeAdd(ctx context.Context, prefix, commit, message string) error {
+	if err := g.runGitCommand(ctx, "subtree", "add", "--prefix="+prefix, "--squash", "-m", message, commit); err != nil {
+		return fmt.Errorf("failed to add subtree %s: %w", prefix, err)
+	}
+	return nil
+}
+
+// SubtreeMerge merges commit, which must already be fetched, into the
+// squashed subtree at prefix
+func (g *GitManager) SubtreeMerge(ctx context.Context, prefix, commit, message string) error {
+	if err := g.runGitCommand(ctx, "subtree", "merge", "--prefix="+prefix, "--squash", "-m", message, commit); err != nil {
+		return fmt.Errorf("failed to update subtree %s: %w", prefix, err)
+	}
+	return nil
+}
+
+// CloneAt initializes a repository in the manager's directory and checks out
+// ref, or the remote's HEAD when ref is empty, from url. It returns the
+// checked out commit.
+func (g *GitManager) CloneAt(ctx context.Context, url, ref string) (string, error) {
+	if err := g.runGitCommand(ctx, "init", "--quiet"); err != nil {
+		return "", fmt.Errorf("failed to initialize repository: %w", err)
+	}
+	commit, err := g.Fetch(ctx, url, ref)
+	if err != nil {
+		return "", err
+	}
+	if err := g.Checkout(ctx, commit); err != nil {
+		return "", err
+	}
+	return commit, nil
+}
+
+// ShallowCloneAt is CloneAt fetching only the commit ref points to, without
+// history. A commit must be given in full, since a shallow fetch cannot
+// resolve an abbreviated one.
+func (g *GitManager) ShallowCloneAt(ctx context.Context, url, ref string) (string, error) {
+	if err := g.runGitCommand(ctx, "init", "--quiet"); err != nil {
+		return "", fmt.Errorf("failed to initialize repository: %w", err)
+	}
+	if err := g.runGitCommand(ctx, "fetch", "--quiet", "--depth", "1", url, refOrHead(ref)); err != nil {
+		return "", fmt.Errorf("failed to fetch %s from %s: %w", refOrHead(ref), url, err)
+	}
+	commit, err := g.RevParse(ctx, "FETCH_HEAD")
+	if err != nil {
+		return "", err
+	}
+	if err := g.Checkout(ctx, commit); err != nil {
+		return "", err
+	}
+	return commit, nil
+}
+
+func refOrHead(ref string) string {
+	if ref == "" {
+		return "HEAD"
+	}
+	return ref
+}
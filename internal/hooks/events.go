@@ -0,0 +1,64 @@
+package hooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/user/gogo/internal/events"
+	"github.com/user/gogo/internal/policy"
+)
+
+// ListFunc returns the hooks attached to an event kind
+type ListFunc func(ctx context.Context, event string) ([]Hook, error)
+
+// Subscribe runs the hooks attached to each event published on bus, in the
+// root of the event's project, or in an empty temporary directory for events
+// without one, never where gogo happened to run. Hooks see the event in
+// GOGO_EVENT and its subject in GOGO_EVENT_SUBJECT. Third-party hooks run
+// once confirm agrees. A failing hook does not stop the hooks after it.
+func Subscribe(bus *events.Bus, list ListFunc, p policy.HookPolicy, confirm ConfirmFunc) {
+	bus.Subscribe(func(ctx context.Context, event events.Event) error {
+		hooks, err := list(ctx, string(event.Kind))
+		if err != nil || len(hooks) == 0 {
+			return err
+		}
+
+		workDir := projectRoot(event.Dir)
+		if workDir == "" {
+			if workDir, err = os.MkdirTemp("", "gogo-hook-"); err != nil {
+				return fmt.Errorf("failed to create directory for hooks: %w", err)
+			}
+			defer os.RemoveAll(workDir)
+		}
+		runner := NewRunner(workDir, p)
+		runner.SetConfirm(confirm)
+		runner.env = []string{"GOGO_EVENT=" + string(event.Kind), "GOGO_EVENT_SUBJECT=" + event.Subject}
+
+		var errs []error
+		for _, hook := range hooks {
+			if _, err := runner.Run(ctx, hook); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return errors.Join(errs...)
+	})
+}
+
+// projectRoot returns the module root at or above dir, the nearest directory
+// with a go.mod, or dir itself outside a module; empty without dir
+func projectRoot(dir string) string {
+	if dir == "" {
+		return ""
+	}
+	for current := dir; ; current = filepath.Dir(current) {
+		if _, err := os.Stat(filepath.Join(current, "go.mod")); err == nil {
+			return current
+		}
+		if filepath.Dir(current) == current {
+			return dir
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package hooks
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/user/gogo/internal/events"
+)
+
+func TestSubscribe(t *testing.T) {
+	projectDir, workingDir := t.TempDir(), t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, "go.mod"), []byte("module example.com/shop\n"), 0644))
+	componentDir := filepath.Join(projectDir, "internal", "handlers")
+	require.NoError(t, os.MkdirAll(componentDir, 0755))
+	t.Chdir(workingDir)
+
+	attached := map[string][]Hook{
+		string(events.ComponentGenerated): {
+			{Name: "mark", Event: string(events.ComponentGenerated), Script: "touch component.done", Source: SourceLocal},
+			{Name: "denied", Event: string(events.ComponentGenerated), Script: "curl https://example.com", Source: SourceLocal},
+			{Name: "after", Event: string(events.ComponentGenerated), Script: "touch after.done", Source: SourceLocal},
+		},
+		string(events.BackupCompleted): {
+			{Name: "mark", Event: string(events.BackupCompleted), Script: "touch backup.done", Source: SourceLocal},
+		},
+		string(events.ProjectGenerated): {
+			{Name: "imported", Event: string(events.ProjectGenerated), Script: "touch imported.done", Source: SourceThirdParty},
+		},
+	}
+	list := func(ctx context.Context, event string) ([]Hook, error) {
+		return attached[event], nil
+	}
+
+	bus := events.NewBus()
+	var failures []error
+	bus.OnError(func(event events.Event, err error) {
+		failures = append(failures, err)
+	})
+	var asked []string
+	Subscribe(bus, list, testPolicy(), func(hook Hook) (bool, error) {
+		asked = append(asked, hook.Name)
+		return false, nil
+	})
+
+	ctx := context.Background()
+	bus.Publish(ctx, events.Event{Kind: events.ComponentGenerated, Subject: "handler/user", Dir: componentDir})
+	bus.Publish(ctx, events.Event{Kind: events.BackupCompleted, Subject: "backup.db"})
+	bus.Publish(ctx, events.Event{Kind: events.ProjectGenerated, Subject: "shop", Dir: projectDir})
+
+	// Hooks run in the project root, and a hook the policy rejects does not
+	// stop the next
+	assert.FileExists(t, filepath.Join(projectDir, "component.done"))
+	assert.FileExists(t, filepath.Join(projectDir, "after.done"))
+	assert.NoFileExists(t, filepath.Join(componentDir, "component.done"))
+	require.Len(t, failures, 2)
+	assert.Contains(t, failures[0].Error(), "hook denied line 1")
+
+	// Only third-party hooks are confirmed, and do not run when declined
+	assert.Equal(t, []string{"imported"}, asked)
+	assert.ErrorIs(t, failures[1], ErrDeclined)
+	assert.NoFileExists(t, filepath.Join(projectDir, "imported.done"))
+
+	// Hooks of events without a project run in a directory of their own,
+	// not where gogo ran
+	assert.NoFileExists(t, filepath.Join(workingDir, "backup.done"))
+}
@@ -0,0 +1,267 @@
+package hooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/user/gogo/internal/policy"
+)
+
+const (
+	// SourceBuiltin marks hooks shipped with gogo's own templates
+	SourceBuiltin = "builtin"
+	// SourceThirdParty marks hooks from user-installed or remote templates
+	SourceThirdParty = "third-party"
+	// SourceLocal marks hooks the user stored in the gogo database
+	SourceLocal = "local"
+)
+
+// ErrDeclined is returned when the user declines to run a third-party hook
+var ErrDeclined = errors.New("hook execution declined")
+
+// Hook represents a script attached to a lifecycle event
+type Hook struct {
+	Name     string
+	Event    string
+	Language string
+	Script   string
+	Source   string
+}
+
+// Result contains the outcome of a hook execution
+type Result struct {
+	Hook     string
+	Commands int
+	Output   string
+	Duration time.Duration
+}
+
+// ConfirmFunc asks the user whether a hook may run
+type ConfirmFunc func(hook Hook) (bool, error)
+
+// Runner executes hooks inside a sandbox policy
+type Runner struct {
+	workDir string
+	policy  policy.HookPolicy
+	confirm ConfirmFunc
+	env     []string // Extra variables for hook processes
+}
+
+// NewRunner creates a hook runner confined to workDir
+func NewRunner(workDir string, p policy.HookPolicy) *Runner {
+	return &Runner{
+		workDir: workDir,
+		policy:  p,
+	}
+}
+
+// SetConfirm sets the callback used to confirm third-party hooks
+func (r *Runner) SetConfirm(fn ConfirmFunc) {
+	r.confirm = fn
+}
+
+// Check validates a hook against the sandbox policy without running it
+func (r *Runner) Check(hook Hook) error {
+	_, err := r.parse(hook)
+	return err
+}
+
+// Run validates and executes a hook
+func (r *Runner) Run(ctx context.Context, hook Hook) (*Result, error) {
+	commands, err := r.parse(hook)
+	if err != nil {
+		return nil, err
+	}
+
+	if hook.Source != SourceBuiltin && hook.Source != SourceLocal && r.policy.ConfirmThirdPartyHooks {
+		if r.confirm == nil {
+			return nil, fmt.Errorf("hook %s requires confirmation: %w", hook.Name, ErrDeclined)
+		}
+		ok, err := r.confirm(hook)
+		if err != nil {
+			return nil, fmt.Errorf("hook confirmation failed: %w", err)
+		}
+		if !ok {
+			return nil, fmt.Errorf("hook %s: %w", hook.Name, ErrDeclined)
+		}
+	}
+
+	timeout := r.policy.Timeout
+	if timeout <= 0 {
+		timeout = policy.Default().Hooks.Timeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	var output strings.Builder
+	for _, args := range commands {
+		cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+		cmd.Dir = r.workDir
+		cmd.Env = r.environment()
+
+		out, err := cmd.CombinedOutput()
+		output.Write(out)
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("hook %s timed out after %v", hook.Name, timeout)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("hook %s failed running %q: %w\nOutput: %s", hook.Name, strings.Join(args, " "), err, string(out))
+		}
+	}
+
+	return &Result{
+		Hook:     hook.Name,
+		Commands: len(commands),
+		Output:   output.String(),
+		Duration: time.Since(start),
+	}, nil
+}
+
+// parse splits a hook script into commands and enforces the policy on each
+func (r *Runner) parse(hook Hook) ([][]string, error) {
+	if hook.Language != "" && hook.Language != "shell" {
+		return nil, fmt.Errorf("hook %s: unsupported language '%s'", hook.Name, hook.Language)
+	}
+
+	var commands [][]string
+	for i, line := range strings.Split(hook.Script, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if strings.ContainsAny(line, "|;&><`") || strings.Contains(line, "$(") {
+			return nil, fmt.Errorf("hook %s line %d: shell operators are not allowed in sandboxed hooks", hook.Name, i+1)
+		}
+
+		args, err := splitArgs(line)
+		if err != nil {
+			return nil, fmt.Errorf("hook %s line %d: %w", hook.Name, i+1, err)
+		}
+
+		if !r.isAllowed(args[0]) {
+			return nil, fmt.Errorf("hook %s line %d: command '%s' is not in the allowed command list", hook.Name, i+1, args[0])
+		}
+
+		for _, arg := range args[1:] {
+			if err := r.checkConfined(arg); err != nil {
+				return nil, fmt.Errorf("hook %s line %d: %w", hook.Name, i+1, err)
+			}
+		}
+
+		commands = append(commands, args)
+	}
+
+	return commands, nil
+}
+
+func (r *Runner) isAllowed(command string) bool {
+	for _, allowed := range r.policy.AllowedCommands {
+		if command == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// checkConfined rejects path arguments that escape the working directory
+func (r *Runner) checkConfined(arg string) error {
+	// Flags like -o=../x still carry a path
+	if idx := strings.Index(arg, "="); idx >= 0 && strings.HasPrefix(arg, "-") {
+		arg = arg[idx+1:]
+	}
+
+	if filepath.IsAbs(arg) {
+		return fmt.Errorf("absolute path '%s' is outside the working directory", arg)
+	}
+
+	cleaned := filepath.Clean(arg)
+	if cleaned == ".." || strings.HasPrefix(cleaned, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("path '%s' escapes the working directory", arg)
+	}
+
+	return nil
+}
+
+// environment builds a minimal environment for hook processes
+func (r *Runner) environment() []string {
+	env := []string{
+		"PATH=" + os.Getenv("PATH"),
+		"HOME=" + r.workDir,
+		"GOGO_HOOK=1",
+	}
+	env = append(env, r.env...)
+
+	if gocache := os.Getenv("GOCACHE"); gocache != "" {
+		env = append(env, "GOCACHE="+gocache)
+	}
+	if gomodcache := os.Getenv("GOMODCACHE"); gomodcache != "" {
+		env = append(env, "GOMODCACHE="+gomodcache)
+	}
+
+	if !r.policy.AllowNetwork {
+		// Advisory network denial: route proxy-aware tools to a closed port
+		// and keep the Go toolchain offline. Commands that ignore the proxy
+		// variables still reach the network.
+		env = append(env,
+			"GOPROXY=off",
+			"GOFLAGS=-mod=mod",
+			"HTTP_PROXY=http://127.0.0.1:9",
+			"HTTPS_PROXY=http://127.0.0.1:9",
+			"ALL_PROXY=http://127.0.0.1:9",
+			"NO_PROXY=",
+		)
+	}
+
+	return env
+}
+
+// splitArgs splits a command line into arguments honoring single and double quotes
+func splitArgs(line string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	var quote rune
+	inArg := false
+
+	for _, r := range line {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inArg = true
+		case r == ' ' || r == '\t':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated quote")
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	if len(args) == 0 {
+		return nil, fmt.Errorf("empty command")
+	}
+
+	return args, nil
+}
@@ -0,0 +1,119 @@
+package hooks
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/user/gogo/internal/policy"
+)
+
+func testPolicy() policy.HookPolicy {
+	p := policy.Default().Hooks
+	p.AllowedCommands = append(p.AllowedCommands, "sleep")
+	return p
+}
+
+func TestRunner_Check(t *testing.T) {
+	runner := NewRunner(t.TempDir(), testPolicy())
+
+	tests := []struct {
+		name        string
+		script      string
+		expectError bool
+	}{
+		{
+			name:   "allowed commands",
+			script: "# format code\ngofmt -l .\nmkdir -p build/out",
+		},
+		{
+			name:        "disallowed command",
+			script:      "curl https://example.com",
+			expectError: true,
+		},
+		{
+			name:        "shell operators",
+			script:      "echo hi > /etc/passwd",
+			expectError: true,
+		},
+		{
+			name:        "command substitution",
+			script:      "echo $(whoami)",
+			expectError: true,
+		},
+		{
+			name:        "absolute path",
+			script:      "touch /tmp/escape",
+			expectError: true,
+		},
+		{
+			name:        "parent directory escape",
+			script:      "mkdir ../outside",
+			expectError: true,
+		},
+		{
+			name:        "unterminated quote",
+			script:      `echo "hello`,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := runner.Check(Hook{Name: "test", Script: tt.script, Source: SourceBuiltin})
+			if tt.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRunner_Run(t *testing.T) {
+	workDir := t.TempDir()
+	runner := NewRunner(workDir, testPolicy())
+
+	result, err := runner.Run(context.Background(), Hook{
+		Name:   "scaffold",
+		Script: "mkdir -p 'generated dir'\ntouch 'generated dir/marker'",
+		Source: SourceBuiltin,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Commands)
+
+	_, err = os.Stat(filepath.Join(workDir, "generated dir", "marker"))
+	assert.NoError(t, err)
+}
+
+func TestRunner_ThirdPartyConfirmation(t *testing.T) {
+	runner := NewRunner(t.TempDir(), testPolicy())
+	hook := Hook{Name: "remote", Script: "echo hello", Source: SourceThirdParty}
+
+	// Without a confirmation callback third-party hooks are refused
+	_, err := runner.Run(context.Background(), hook)
+	assert.ErrorIs(t, err, ErrDeclined)
+
+	runner.SetConfirm(func(h Hook) (bool, error) { return false, nil })
+	_, err = runner.Run(context.Background(), hook)
+	assert.ErrorIs(t, err, ErrDeclined)
+
+	runner.SetConfirm(func(h Hook) (bool, error) { return true, nil })
+	result, err := runner.Run(context.Background(), hook)
+	require.NoError(t, err)
+	assert.Contains(t, result.Output, "hello")
+}
+
+func TestRunner_Timeout(t *testing.T) {
+	p := testPolicy()
+	p.Timeout = 50 * time.Millisecond
+	runner := NewRunner(t.TempDir(), p)
+
+	_, err := runner.Run(context.Background(), Hook{Name: "slow", Script: "sleep 5", Source: SourceBuiltin})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "timed out")
+}
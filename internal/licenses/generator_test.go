@@ -0,0 +1,45 @@
+package licenses
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerator_GenerateAll(t *testing.T) {
+	tempDir := t.TempDir()
+	config := Config{ProjectName: "orders", ModuleName: "github.com/acme/orders"}
+
+	require.NoError(t, NewGenerator().GenerateAll(context.Background(), tempDir, config))
+	for _, file := range Files() {
+		assert.FileExists(t, filepath.Join(tempDir, file))
+	}
+
+	path := filepath.Join(tempDir, ScriptPath)
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0755), info.Mode().Perm(), "the script is executable")
+
+	script, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(script), `ALLOWED_LICENSES="Apache-2.0,BSD-2-Clause,BSD-3-Clause,ISC,MIT"`)
+	assert.Contains(t, string(script), `REPORT="${REPORT:-third_party_licenses.csv}"`)
+	assert.Contains(t, string(script), "go-licenses check ./... --ignore github.com/acme/orders")
+}
+
+func TestGenerator_GenerateAll_Allowed(t *testing.T) {
+	tempDir := t.TempDir()
+	config := Config{ProjectName: "orders", ModuleName: "github.com/acme/orders", Allowed: []string{"MIT", "MPL-2.0"}}
+
+	require.NoError(t, NewGenerator().GenerateAll(context.Background(), tempDir, config))
+	script, err := os.ReadFile(filepath.Join(tempDir, ScriptPath))
+	require.NoError(t, err)
+	assert.Contains(t, string(script), `ALLOWED_LICENSES="MIT,MPL-2.0"`)
+
+	config.Allowed = []string{"MIT", "GPL-3.0 OR MIT"}
+	assert.ErrorContains(t, NewGenerator().GenerateAll(context.Background(), t.TempDir(), config), "invalid license identifier")
+}
@@ -0,0 +1,148 @@
+package merge
+
+import (
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// Chunk is a region of a three-way merge
+type Chunk struct {
+	Base     []string
+	Mine     []string
+	Template []string
+	Conflict bool
+	Merged   []string // Result for non-conflicting chunks
+	Line     int      // 1-based line in the user's file where the chunk starts
+}
+
+// Merge is the result of merging template changes into a user's file
+type Merge struct {
+	Chunks []*Chunk
+}
+
+// ThreeWay merges changes between base (the originally generated content) and
+// template (the newly generated content) into mine (the user's current file).
+func ThreeWay(base, mine, template string) *Merge {
+	baseLines := splitLines(base)
+	mineLines := splitLines(mine)
+	templateLines := splitLines(template)
+
+	mineMap := matchLines(baseLines, mineLines)
+	templateMap := matchLines(baseLines, templateLines)
+
+	m := &Merge{}
+	i, j, k := 0, 0, 0
+	var stable []string
+	stableLine := 1
+
+	flushStable := func() {
+		if len(stable) > 0 {
+			m.Chunks = append(m.Chunks, &Chunk{
+				Base: stable, Mine: stable, Template: stable, Merged: stable, Line: stableLine,
+			})
+			stable = nil
+		}
+	}
+
+	for i < len(baseLines) || j < len(mineLines) || k < len(templateLines) {
+		if i < len(baseLines) && mineMap[i] == j && templateMap[i] == k {
+			if len(stable) == 0 {
+				stableLine = j + 1
+			}
+			stable = append(stable, baseLines[i])
+			i, j, k = i+1, j+1, k+1
+			continue
+		}
+
+		// Find the next base line present, in order, in both versions
+		next := i
+		for next < len(baseLines) && (mineMap[next] < j || templateMap[next] < k) {
+			next++
+		}
+
+		mineEnd, templateEnd := len(mineLines), len(templateLines)
+		if next < len(baseLines) {
+			mineEnd, templateEnd = mineMap[next], templateMap[next]
+		}
+
+		flushStable()
+		m.Chunks = append(m.Chunks, classify(baseLines[i:next], mineLines[j:mineEnd], templateLines[k:templateEnd], j+1))
+		i, j, k = next, mineEnd, templateEnd
+	}
+	flushStable()
+
+	return m
+}
+
+// HasConflicts reports whether any chunk needs resolution
+func (m *Merge) HasConflicts() bool {
+	for _, chunk := range m.Chunks {
+		if chunk.Conflict {
+			return true
+		}
+	}
+	return false
+}
+
+// Conflicts returns the conflicting chunks
+func (m *Merge) Conflicts() []*Chunk {
+	var conflicts []*Chunk
+	for _, chunk := range m.Chunks {
+		if chunk.Conflict {
+			conflicts = append(conflicts, chunk)
+		}
+	}
+	return conflicts
+}
+
+func classify(base, mine, template []string, line int) *Chunk {
+	chunk := &Chunk{Base: base, Mine: mine, Template: template, Line: line}
+
+	switch {
+	case equalLines(mine, base):
+		chunk.Merged = template
+	case equalLines(template, base), equalLines(mine, template):
+		chunk.Merged = mine
+	default:
+		chunk.Conflict = true
+	}
+
+	return chunk
+}
+
+// matchLines maps each line of a to its matching line index in b, or -1
+func matchLines(a, b []string) []int {
+	mapping := make([]int, len(a))
+	for i := range mapping {
+		mapping[i] = -1
+	}
+
+	matcher := difflib.NewMatcher(a, b)
+	for _, block := range matcher.GetMatchingBlocks() {
+		for n := 0; n < block.Size; n++ {
+			mapping[block.A+n] = block.B + n
+		}
+	}
+
+	return mapping
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.SplitAfter(s, "\n")
+}
+
+func equalLines(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,117 @@
+package merge
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const baseContent = `run:
+  timeout: 5m
+linters:
+  enable:
+    - govet
+    - errcheck
+`
+
+func TestThreeWay(t *testing.T) {
+	tests := []struct {
+		name            string
+		mine            string
+		template        string
+		expectConflict  bool
+		expectedContent string
+	}{
+		{
+			name:            "no changes",
+			mine:            baseContent,
+			template:        baseContent,
+			expectedContent: baseContent,
+		},
+		{
+			name:            "template change only",
+			mine:            baseContent,
+			template:        "run:\n  timeout: 10m\nlinters:\n  enable:\n    - govet\n    - errcheck\n",
+			expectedContent: "run:\n  timeout: 10m\nlinters:\n  enable:\n    - govet\n    - errcheck\n",
+		},
+		{
+			name:            "user change only",
+			mine:            baseContent + "    - staticcheck\n",
+			template:        baseContent,
+			expectedContent: baseContent + "    - staticcheck\n",
+		},
+		{
+			name:            "non-overlapping changes",
+			mine:            baseContent + "    - staticcheck\n",
+			template:        "run:\n  timeout: 10m\nlinters:\n  enable:\n    - govet\n    - errcheck\n",
+			expectedContent: "run:\n  timeout: 10m\nlinters:\n  enable:\n    - govet\n    - errcheck\n    - staticcheck\n",
+		},
+		{
+			name:           "overlapping changes",
+			mine:           "run:\n  timeout: 2m\nlinters:\n  enable:\n    - govet\n    - errcheck\n",
+			template:       "run:\n  timeout: 10m\nlinters:\n  enable:\n    - govet\n    - errcheck\n",
+			expectConflict: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := ThreeWay(baseContent, tt.mine, tt.template)
+			assert.Equal(t, tt.expectConflict, m.HasConflicts())
+
+			if !tt.expectConflict {
+				applied, err := m.Apply("config.yml", StaticResolver{Resolution: KeepMine})
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectedContent, applied.Content)
+			}
+		})
+	}
+}
+
+func TestMerge_ApplyResolutions(t *testing.T) {
+	mine := "run:\n  timeout: 2m\nlinters:\n  enable:\n    - govet\n    - errcheck\n"
+	template := "run:\n  timeout: 10m\nlinters:\n  enable:\n    - govet\n    - errcheck\n"
+
+	tests := []struct {
+		name           string
+		resolution     Resolution
+		expectedLine   string
+		expectRejected int
+	}{
+		{name: "keep mine", resolution: KeepMine, expectedLine: "  timeout: 2m\n"},
+		{name: "take template", resolution: TakeTemplate, expectedLine: "  timeout: 10m\n"},
+		{name: "skip", resolution: Skip, expectedLine: "  timeout: 2m\n", expectRejected: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := ThreeWay(baseContent, mine, template)
+			applied, err := m.Apply("config.yml", StaticResolver{Resolution: tt.resolution})
+			require.NoError(t, err)
+			assert.Contains(t, applied.Content, tt.expectedLine)
+			assert.Len(t, applied.Rejected, tt.expectRejected)
+		})
+	}
+}
+
+func TestWriteRejects(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ci.yml")
+
+	m := ThreeWay("a\nb\n", "a\nmine\n", "a\ntemplate\n")
+	require.True(t, m.HasConflicts())
+
+	require.NoError(t, WriteRejects(path, m.Conflicts()))
+	data, err := os.ReadFile(path + ".rej")
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "<<<<<<< mine\nmine\n")
+	assert.Contains(t, string(data), "=======\ntemplate\n>>>>>>> template\n")
+
+	// Nothing rejected means no file
+	other := filepath.Join(t.TempDir(), "other.yml")
+	require.NoError(t, WriteRejects(other, nil))
+	_, err = os.Stat(other + ".rej")
+	assert.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,195 @@
+package merge
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/manifoldco/promptui"
+)
+
+// Resolution is the choice made for a conflicting chunk
+type Resolution int
+
+const (
+	// KeepMine keeps the user's version of the chunk
+	KeepMine Resolution = iota
+	// TakeTemplate replaces the chunk with the template's version
+	TakeTemplate
+	// Edit replaces the chunk with manually edited content
+	Edit
+	// Skip keeps the user's version and records the template's change in a .rej file
+	Skip
+)
+
+// Resolver decides how to resolve conflicting chunks
+type Resolver interface {
+	Resolve(path string, chunk *Chunk) (Resolution, []string, error)
+}
+
+// StaticResolver resolves every conflict the same way, for non-interactive use
+type StaticResolver struct {
+	Resolution Resolution
+}
+
+// Resolve returns the configured resolution
+func (s StaticResolver) Resolve(path string, chunk *Chunk) (Resolution, []string, error) {
+	return s.Resolution, nil, nil
+}
+
+// InteractiveResolver prompts the user for each conflicting chunk
+type InteractiveResolver struct{}
+
+// NewInteractiveResolver creates a resolver that prompts on the terminal
+func NewInteractiveResolver() *InteractiveResolver {
+	return &InteractiveResolver{}
+}
+
+// Resolve shows a conflict and asks how to resolve it
+func (r *InteractiveResolver) Resolve(path string, chunk *Chunk) (Resolution, []string, error) {
+	fmt.Println()
+	color.Yellow("Conflict in %s at line %d", path, chunk.Line)
+	fmt.Print(FormatConflict(chunk))
+
+	prompt := promptui.Select{
+		Label: "Resolve conflict",
+		Items: []string{"Keep mine", "Take template", "Edit", "Skip (write .rej)"},
+	}
+
+	i, _, err := prompt.Run()
+	if err != nil {
+		return Skip, nil, fmt.Errorf("conflict resolution prompt failed: %w", err)
+	}
+
+	switch Resolution(i) {
+	case Edit:
+		lines, err := editChunk(chunk)
+		if err != nil {
+			return Skip, nil, err
+		}
+		return Edit, lines, nil
+	default:
+		return Resolution(i), nil, nil
+	}
+}
+
+// Applied is the outcome of resolving a merge
+type Applied struct {
+	Content  string
+	Rejected []*Chunk
+	Resolved int
+}
+
+// Apply resolves all conflicts and assembles the merged content
+func (m *Merge) Apply(path string, resolver Resolver) (*Applied, error) {
+	applied := &Applied{}
+	var out strings.Builder
+
+	for _, chunk := range m.Chunks {
+		if !chunk.Conflict {
+			writeLines(&out, chunk.Merged)
+			continue
+		}
+
+		resolution, edited, err := resolver.Resolve(path, chunk)
+		if err != nil {
+			return nil, err
+		}
+
+		switch resolution {
+		case KeepMine:
+			writeLines(&out, chunk.Mine)
+		case TakeTemplate:
+			writeLines(&out, chunk.Template)
+		case Edit:
+			writeLines(&out, edited)
+		case Skip:
+			writeLines(&out, chunk.Mine)
+			applied.Rejected = append(applied.Rejected, chunk)
+			continue
+		}
+		applied.Resolved++
+	}
+
+	applied.Content = out.String()
+	return applied, nil
+}
+
+// FormatConflict renders a chunk with conflict markers
+func FormatConflict(chunk *Chunk) string {
+	var b strings.Builder
+	b.WriteString("<<<<<<< mine\n")
+	writeLines(&b, chunk.Mine)
+	b.WriteString("||||||| base\n")
+	writeLines(&b, chunk.Base)
+	b.WriteString("=======\n")
+	writeLines(&b, chunk.Template)
+	b.WriteString(">>>>>>> template\n")
+	return b.String()
+}
+
+// WriteRejects writes skipped chunks to path.rej; it is a no-op when nothing was rejected
+func WriteRejects(path string, rejected []*Chunk) error {
+	if len(rejected) == 0 {
+		return nil
+	}
+
+	var b strings.Builder
+	for _, chunk := range rejected {
+		fmt.Fprintf(&b, "@@ line %d @@\n", chunk.Line)
+		b.WriteString(FormatConflict(chunk))
+	}
+
+	if err := os.WriteFile(path+".rej", []byte(b.String()), 0644); err != nil {
+		return fmt.Errorf("failed to write reject file for %s: %w", path, err)
+	}
+	return nil
+}
+
+// editChunk opens the conflict in $EDITOR and returns the edited lines
+func editChunk(chunk *Chunk) ([]string, error) {
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		editor = "vi"
+	}
+
+	tmp, err := os.CreateTemp("", "gogo-merge-*.txt")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.WriteString(FormatConflict(chunk)); err != nil {
+		tmp.Close()
+		return nil, fmt.Errorf("failed to write temp file: %w", err)
+	}
+	tmp.Close()
+
+	cmd := exec.Command(editor, tmp.Name())
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("editor failed: %w", err)
+	}
+
+	data, err := os.ReadFile(tmp.Name())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read edited file: %w", err)
+	}
+
+	content := string(data)
+	if strings.Contains(content, "<<<<<<< ") || strings.Contains(content, ">>>>>>> ") {
+		return nil, fmt.Errorf("edited content still contains conflict markers")
+	}
+
+	return splitLines(content), nil
+}
+
+func writeLines(b *strings.Builder, lines []string) {
+	for _, line := range lines {
+		b.WriteString(line)
+	}
+}
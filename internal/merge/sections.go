@@ -0,0 +1,189 @@
+package merge
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Markers delimit the sections of a generated file gogo owns. Everything
+// outside them belongs to the project and survives regeneration. A marker
+// may name its section, as in "# gogo:start test", so that sections are
+// matched by name rather than by position.
+const (
+	MarkerStart = "# gogo:start"
+	MarkerEnd   = "# gogo:end"
+)
+
+// block is either text outside the markers or a managed section, markers
+// included
+type block struct {
+	key     string // Section name, or its position among unnamed sections
+	lines   []string
+	managed bool
+}
+
+// HasSections reports whether content has marked sections
+func HasSections(content string) bool {
+	for _, line := range splitLines(content) {
+		if _, ok := markerName(line, MarkerStart); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Sections returns the managed sections of content, markers included and in
+// order, without the text around them
+func Sections(content string) (string, error) {
+	blocks, err := parseSections(content)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	for _, block := range blocks {
+		if block.managed {
+			writeLines(&b, block.lines)
+		}
+	}
+	return b.String(), nil
+}
+
+// ReplaceSections regenerates the managed sections of current with those of
+// template and keeps the rest of current as it is. Sections the template no
+// longer has are dropped; new ones are inserted after the section preceding
+// them in the template.
+func ReplaceSections(current, template string) (string, error) {
+	mine, err := parseSections(current)
+	if err != nil {
+		return "", fmt.Errorf("invalid gogo markers: %w", err)
+	}
+	theirs, err := parseSections(template)
+	if err != nil {
+		return "", fmt.Errorf("invalid gogo markers in the template: %w", err)
+	}
+
+	present := make(map[string]bool)
+	for _, block := range mine {
+		if block.managed {
+			present[block.key] = true
+		}
+	}
+	// New sections follow the template section before them, or lead the
+	// first section when none is before them
+	var order []*block
+	index := make(map[string]int)
+	for i := range theirs {
+		if theirs[i].managed {
+			index[theirs[i].key] = len(order)
+			order = append(order, &theirs[i])
+		}
+	}
+	following := func(from int) []*block {
+		var added []*block
+		for _, section := range order[from:] {
+			if present[section.key] {
+				break
+			}
+			added = append(added, section)
+		}
+		return added
+	}
+
+	var b strings.Builder
+	leading := true
+	for _, block := range mine {
+		if !block.managed {
+			writeLines(&b, block.lines)
+			continue
+		}
+		if leading {
+			for _, section := range following(0) {
+				writeSection(&b, section.lines)
+			}
+			leading = false
+		}
+		i, ok := index[block.key]
+		if !ok {
+			continue
+		}
+		writeSection(&b, order[i].lines)
+		for _, section := range following(i + 1) {
+			writeSection(&b, section.lines)
+		}
+	}
+	return b.String(), nil
+}
+
+// writeSection writes the lines of a section, ending the last one so that
+// text after the section stays on its own line
+func writeSection(b *strings.Builder, lines []string) {
+	writeLines(b, lines)
+	if last := lines[len(lines)-1]; !strings.HasSuffix(last, "\n") {
+		b.WriteString("\n")
+	}
+}
+
+// parseSections splits content into the text outside markers and the
+// sections within them
+func parseSections(content string) ([]block, error) {
+	var blocks []block
+	var text, section []string
+	var key, startName string
+	inSection := false
+	unnamed := 0
+	seen := make(map[string]bool)
+
+	for i, line := range splitLines(content) {
+		if name, ok := markerName(line, MarkerStart); ok {
+			if inSection {
+				return nil, fmt.Errorf("line %d: %s inside the section %q", i+1, MarkerStart, key)
+			}
+			key, startName = name, name
+			if key == "" {
+				unnamed++
+				key = fmt.Sprintf("#%d", unnamed)
+			}
+			if seen[key] {
+				return nil, fmt.Errorf("line %d: duplicate section %q", i+1, key)
+			}
+			seen[key] = true
+			if len(text) > 0 {
+				blocks = append(blocks, block{lines: text})
+				text = nil
+			}
+			section = []string{line}
+			inSection = true
+			continue
+		}
+		if name, ok := markerName(line, MarkerEnd); ok {
+			if !inSection || (name != "" && name != startName) {
+				return nil, fmt.Errorf("line %d: %s without a matching %s", i+1, MarkerEnd, MarkerStart)
+			}
+			blocks = append(blocks, block{key: key, lines: append(section, line), managed: true})
+			section = nil
+			inSection = false
+			continue
+		}
+		if inSection {
+			section = append(section, line)
+		} else {
+			text = append(text, line)
+		}
+	}
+	if inSection {
+		return nil, fmt.Errorf("section %q has no %s", key, MarkerEnd)
+	}
+	if len(text) > 0 {
+		blocks = append(blocks, block{lines: text})
+	}
+	return blocks, nil
+}
+
+// markerName reports whether line is the marker, and the section it names
+func markerName(line, marker string) (string, bool) {
+	rest, ok := strings.CutPrefix(strings.TrimSpace(line), marker)
+	if !ok || (rest != "" && rest[0] != ' ' && rest[0] != '\t') {
+		return "", false
+	}
+	return strings.TrimSpace(rest), true
+}
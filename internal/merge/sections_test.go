@@ -0,0 +1,91 @@
+package merge
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sectionsTemplate = `name: CI
+jobs:
+  # gogo:start test
+  test:
+    run: go test ./...
+  # gogo:end test
+
+  # gogo:start lint
+  lint:
+    run: golangci-lint run
+  # gogo:end lint
+`
+
+func TestReplaceSections(t *testing.T) {
+	current := `name: CI
+on: [push, workflow_dispatch]
+jobs:
+  # gogo:start test
+  test:
+    run: go test
+  # gogo:end test
+
+  # gogo:start scaffold
+  scaffold:
+    run: gogo drift
+  # gogo:end scaffold
+
+  deploy:
+    run: ./deploy.sh
+`
+	replaced, err := ReplaceSections(current, sectionsTemplate)
+	require.NoError(t, err)
+	assert.Equal(t, `name: CI
+on: [push, workflow_dispatch]
+jobs:
+  # gogo:start test
+  test:
+    run: go test ./...
+  # gogo:end test
+  # gogo:start lint
+  lint:
+    run: golangci-lint run
+  # gogo:end lint
+
+
+  deploy:
+    run: ./deploy.sh
+`, replaced, "the test section is regenerated, lint added after it and scaffold dropped")
+
+	// Regenerating again changes nothing
+	again, err := ReplaceSections(replaced, sectionsTemplate)
+	require.NoError(t, err)
+	assert.Equal(t, replaced, again)
+}
+
+func TestReplaceSections_Unnamed(t *testing.T) {
+	current := "# mine\n# gogo:start\nold\n# gogo:end\n# also mine\n"
+	replaced, err := ReplaceSections(current, "# gogo:start\nnew\n# gogo:end")
+	require.NoError(t, err)
+	assert.Equal(t, "# mine\n# gogo:start\nnew\n# gogo:end\n# also mine\n", replaced)
+}
+
+func TestSections(t *testing.T) {
+	assert.True(t, HasSections(sectionsTemplate))
+	assert.False(t, HasSections("# gogo:started\nname: CI\n"))
+
+	sections, err := Sections(sectionsTemplate)
+	require.NoError(t, err)
+	assert.NotContains(t, sections, "name: CI")
+	assert.Contains(t, sections, "  # gogo:start lint\n  lint:\n")
+
+	for _, invalid := range []string{
+		"# gogo:start a\n",
+		"# gogo:end a\n",
+		"# gogo:start a\n# gogo:start b\n# gogo:end b\n# gogo:end a\n",
+		"# gogo:start a\n# gogo:end b\n",
+		"# gogo:start a\n# gogo:end\n# gogo:start a\n# gogo:end\n",
+	} {
+		_, err := Sections(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
@@ -0,0 +1,477 @@
+// Package monitoring generates starter monitoring configuration for services
+// that expose Prometheus metrics: a Prometheus scrape configuration, alerting
+// rules, an SLO definition in sloth format, a promtail configuration shipping
+// container logs to Loki, and a provisioned Grafana dashboard. The files are
+// mounted by the services of the observability profile of the generated
+// docker-compose.yml:
+//
+//	docker compose --profile observability up
+package monitoring
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strconv"
+
+	"github.com/user/gogo/internal/templates"
+)
+
+// Starting objectives of the generated SLOs. Teams are expected to tune them
+// once they know the service's real traffic.
+const (
+	AvailabilityObjective = 99.9 // percent of requests answered without a 5xx
+	LatencyObjective      = 99.0 // percent of requests faster than LatencyThreshold
+	LatencyThreshold      = 0.5  // seconds; a bucket of the default histogram
+)
+
+// Config represents monitoring configuration generation options
+type Config struct {
+	ProjectName string // prefix of the request metrics and the scrape job name
+	Port        int    // port serving /metrics; 8080 when zero
+}
+
+// Generator handles monitoring configuration generation
+type Generator struct {
+	templateEngine templates.TemplateRenderer
+}
+
+// NewGenerator creates a new monitoring configuration generator
+func NewGenerator() *Generator {
+	return &Generator{
+		templateEngine: templates.NewEngine(),
+	}
+}
+
+type monitoringFile struct {
+	path    string
+	content string
+}
+
+// Profile is the docker-compose profile holding Prometheus, Grafana, Loki and
+// promtail
+const Profile = "observability"
+
+// SupportsStack reports whether the docker-compose.yml of a blueprint stack
+// has the observability profile the generated files are mounted into
+func SupportsStack(stack string) bool {
+	return stack == "web" || stack == "microservice"
+}
+
+func monitoringFiles(config Config) []monitoringFile {
+	return []monitoringFile{
+		{"monitoring/prometheus.yml", prometheusTemplate},
+		{"monitoring/rules/alerts.yml", alertsTemplate},
+		{"monitoring/slo.yml", sloTemplate},
+		{"monitoring/promtail.yml", promtailTemplate},
+		{"monitoring/grafana/provisioning/datasources/prometheus.yml", datasourceTemplate},
+		{"monitoring/grafana/provisioning/dashboards/dashboards.yml", dashboardProviderTemplate},
+		{"monitoring/grafana/dashboards/" + config.ProjectName + ".json", dashboardTemplate},
+	}
+}
+
+// Files returns the project-relative paths written by GenerateAll for config
+func Files(config Config) []string {
+	files := monitoringFiles(config)
+	paths := make([]string, len(files))
+	for i, file := range files {
+		paths[i] = file.path
+	}
+	return paths
+}
+
+// GenerateAll generates the Prometheus, alerting, SLO and Grafana files
+func (g *Generator) GenerateAll(ctx context.Context, outputDir string, config Config) error {
+	if config.Port == 0 {
+		config.Port = 8080
+	}
+
+	variables := map[string]any{
+		"ProjectName":           config.ProjectName,
+		"Port":                  config.Port,
+		"AvailabilityObjective": formatFloat(AvailabilityObjective),
+		"ErrorBudget":           formatFloat((100 - AvailabilityObjective) / 100),
+		"LatencyObjective":      formatFloat(LatencyObjective),
+		"LatencyThreshold":      formatFloat(LatencyThreshold),
+	}
+
+	for _, file := range monitoringFiles(config) {
+		outputPath := filepath.Join(outputDir, filepath.FromSlash(file.path))
+		if err := g.templateEngine.RenderToFile(ctx, file.content, variables, outputPath); err != nil {
+			return fmt.Errorf("failed to generate %s: %w", file.path, err)
+		}
+	}
+	return nil
+}
+
+// formatFloat formats f for YAML and PromQL; the template engine would print
+// six decimals, and the error budget needs rounding
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', 6, 64)
+}
+
+const prometheusTemplate = `# Prometheus configuration for {{ ProjectName }}, generated by gogo.
+global:
+  scrape_interval: 15s
+  evaluation_interval: 15s
+
+rule_files:
+  # alerts.yml, plus the recording and alerting rules generated from slo.yml:
+  #   sloth generate -i monitoring/slo.yml -o monitoring/rules/slo.yml
+  - /etc/prometheus/rules/*.yml
+
+scrape_configs:
+  - job_name: {{ ProjectName }}
+    metrics_path: /metrics
+    static_configs:
+      - targets: ["{{ ProjectName }}:{{ Port }}"]
+`
+
+const alertsTemplate = `# Starter alerting rules for {{ ProjectName }}, generated by gogo.
+#
+# The burn rate alerts follow the multiwindow approach of the Google SRE
+# workbook for a {{ AvailabilityObjective }}% availability objective: a burn rate of 14.4
+# spends 2% of a 30 day error budget in one hour, a burn rate of 6 spends 5%
+# in six hours.
+groups:
+  - name: {{ ProjectName }}
+    rules:
+      - alert: {{ ProjectName }}Down
+        expr: up{job="{{ ProjectName }}"} == 0
+        for: 1m
+        labels:
+          severity: critical
+        annotations:
+          summary: "{{ ProjectName }} is not being scraped"
+          description: "Prometheus has not reached the {{ ProjectName }} metrics endpoint for a minute."
+
+      - alert: {{ ProjectName }}ErrorBudgetFastBurn
+        expr: |
+          (
+            sum(rate({{ ProjectName }}_requests_total{status=~"5.."}[1h]))
+              / sum(rate({{ ProjectName }}_requests_total[1h])) > (14.4 * {{ ErrorBudget }})
+          )
+          and
+          (
+            sum(rate({{ ProjectName }}_requests_total{status=~"5.."}[5m]))
+              / sum(rate({{ ProjectName }}_requests_total[5m])) > (14.4 * {{ ErrorBudget }})
+          )
+        labels:
+          severity: critical
+        annotations:
+          summary: "{{ ProjectName }} is burning its error budget fast"
+          description: "At the current 5xx rate the monthly error budget is spent in about two days."
+
+      - alert: {{ ProjectName }}ErrorBudgetSlowBurn
+        expr: |
+          (
+            sum(rate({{ ProjectName }}_requests_total{status=~"5.."}[6h]))
+              / sum(rate({{ ProjectName }}_requests_total[6h])) > (6 * {{ ErrorBudget }})
+          )
+          and
+          (
+            sum(rate({{ ProjectName }}_requests_total{status=~"5.."}[30m]))
+              / sum(rate({{ ProjectName }}_requests_total[30m])) > (6 * {{ ErrorBudget }})
+          )
+        labels:
+          severity: warning
+        annotations:
+          summary: "{{ ProjectName }} is burning its error budget"
+          description: "At the current 5xx rate the monthly error budget is spent in about five days."
+
+      - alert: {{ ProjectName }}HighLatency
+        expr: |
+          histogram_quantile(0.99, sum by (le) (rate({{ ProjectName }}_request_duration_seconds_bucket[5m]))) > {{ LatencyThreshold }}
+        for: 10m
+        labels:
+          severity: warning
+        annotations:
+          summary: "{{ ProjectName }} p99 latency is above {{ LatencyThreshold }}s"
+          description: "The 99th percentile request duration has been above {{ LatencyThreshold }}s for 10 minutes."
+`
+
+const sloTemplate = `# Service level objectives for {{ ProjectName }}, generated by gogo.
+#
+# The spec is in sloth format (https://sloth.dev). Generate the Prometheus
+# recording and alerting rules with:
+#   sloth generate -i monitoring/slo.yml -o monitoring/rules/slo.yml
+version: "prometheus/v1"
+service: "{{ ProjectName }}"
+labels:
+  owner: "{{ ProjectName }}"
+slos:
+  - name: "requests-availability"
+    objective: {{ AvailabilityObjective }}
+    description: "Requests answered without a server error."
+    sli:
+      events:
+        error_query: sum(rate({{ ProjectName }}_requests_total{status=~"5.."}[{{ "{{" }}.window{{ "}}" }}]))
+        total_query: sum(rate({{ ProjectName }}_requests_total[{{ "{{" }}.window{{ "}}" }}]))
+    alerting:
+      name: {{ ProjectName }}AvailabilitySLO
+      labels:
+        category: availability
+      annotations:
+        summary: "{{ ProjectName }} is answering too many requests with server errors"
+      page_alert:
+        labels:
+          severity: critical
+      ticket_alert:
+        labels:
+          severity: warning
+
+  - name: "requests-latency"
+    objective: {{ LatencyObjective }}
+    description: "Requests served in under {{ LatencyThreshold }}s."
+    sli:
+      events:
+        error_query: |
+          sum(rate({{ ProjectName }}_request_duration_seconds_count[{{ "{{" }}.window{{ "}}" }}]))
+          -
+          sum(rate({{ ProjectName }}_request_duration_seconds_bucket{le="{{ LatencyThreshold }}"}[{{ "{{" }}.window{{ "}}" }}]))
+        total_query: sum(rate({{ ProjectName }}_request_duration_seconds_count[{{ "{{" }}.window{{ "}}" }}]))
+    alerting:
+      name: {{ ProjectName }}LatencySLO
+      labels:
+        category: latency
+      annotations:
+        summary: "{{ ProjectName }} is serving too many slow requests"
+      page_alert:
+        labels:
+          severity: critical
+      ticket_alert:
+        labels:
+          severity: warning
+`
+
+const promtailTemplate = `# promtail configuration for {{ ProjectName }}, generated by gogo.
+#
+# Ships the logs of the docker-compose containers to Loki, labelled with
+# their compose service.
+server:
+  http_listen_port: 9080
+  grpc_listen_port: 0
+
+positions:
+  filename: /tmp/positions.yaml
+
+clients:
+  - url: http://loki:3100/loki/api/v1/push
+
+scrape_configs:
+  - job_name: docker
+    docker_sd_configs:
+      - host: unix:///var/run/docker.sock
+        refresh_interval: 5s
+    relabel_configs:
+      - source_labels: ["__meta_docker_container_label_com_docker_compose_project"]
+        target_label: project
+      - source_labels: ["__meta_docker_container_label_com_docker_compose_service"]
+        target_label: service
+      - source_labels: ["__meta_docker_container_name"]
+        regex: "/(.*)"
+        target_label: container
+`
+
+const datasourceTemplate = `# Grafana data sources for the docker-compose Prometheus and Loki, generated by gogo.
+apiVersion: 1
+
+datasources:
+  - name: Prometheus
+    uid: prometheus
+    type: prometheus
+    access: proxy
+    url: http://prometheus:9090
+    isDefault: true
+
+  - name: Loki
+    uid: loki
+    type: loki
+    access: proxy
+    url: http://loki:3100
+`
+
+const dashboardProviderTemplate = `# Grafana dashboard provider, generated by gogo.
+apiVersion: 1
+
+providers:
+  - name: {{ ProjectName }}
+    folder: {{ ProjectName }}
+    type: file
+    options:
+      path: /var/lib/grafana/dashboards
+`
+
+const dashboardTemplate = `{
+  "uid": "{{ ProjectName }}-requests",
+  "title": "{{ ProjectName }} requests",
+  "tags": ["{{ ProjectName }}", "gogo"],
+  "timezone": "browser",
+  "schemaVersion": 39,
+  "refresh": "30s",
+  "time": { "from": "now-6h", "to": "now" },
+  "panels": [
+    {
+      "id": 1,
+      "type": "stat",
+      "title": "Availability (30d)",
+      "gridPos": { "h": 6, "w": 8, "x": 0, "y": 0 },
+      "datasource": { "type": "prometheus", "uid": "prometheus" },
+      "fieldConfig": {
+        "defaults": {
+          "unit": "percentunit",
+          "decimals": 3,
+          "thresholds": {
+            "mode": "absolute",
+            "steps": [
+              { "color": "red", "value": null },
+              { "color": "green", "value": {{ AvailabilityObjective }} }
+            ]
+          }
+        }
+      },
+      "targets": [
+        {
+          "refId": "A",
+          "expr": "1 - (sum(increase({{ ProjectName }}_requests_total{status=~\"5..\"}[30d])) / sum(increase({{ ProjectName }}_requests_total[30d])))"
+        }
+      ]
+    },
+    {
+      "id": 2,
+      "type": "stat",
+      "title": "Error budget remaining (30d)",
+      "gridPos": { "h": 6, "w": 8, "x": 8, "y": 0 },
+      "datasource": { "type": "prometheus", "uid": "prometheus" },
+      "fieldConfig": {
+        "defaults": {
+          "unit": "percentunit",
+          "thresholds": {
+            "mode": "absolute",
+            "steps": [
+              { "color": "red", "value": null },
+              { "color": "yellow", "value": 0 },
+              { "color": "green", "value": 0.25 }
+            ]
+          }
+        }
+      },
+      "targets": [
+        {
+          "refId": "A",
+          "expr": "1 - ((sum(increase({{ ProjectName }}_requests_total{status=~\"5..\"}[30d])) / sum(increase({{ ProjectName }}_requests_total[30d]))) / {{ ErrorBudget }})"
+        }
+      ]
+    },
+    {
+      "id": 3,
+      "type": "stat",
+      "title": "p99 latency",
+      "gridPos": { "h": 6, "w": 8, "x": 16, "y": 0 },
+      "datasource": { "type": "prometheus", "uid": "prometheus" },
+      "fieldConfig": {
+        "defaults": {
+          "unit": "s",
+          "thresholds": {
+            "mode": "absolute",
+            "steps": [
+              { "color": "green", "value": null },
+              { "color": "red", "value": {{ LatencyThreshold }} }
+            ]
+          }
+        }
+      },
+      "targets": [
+        {
+          "refId": "A",
+          "expr": "histogram_quantile(0.99, sum by (le) (rate({{ ProjectName }}_request_duration_seconds_bucket[5m])))"
+        }
+      ]
+    },
+    {
+      "id": 4,
+      "type": "timeseries",
+      "title": "Requests per second by status",
+      "gridPos": { "h": 8, "w": 12, "x": 0, "y": 6 },
+      "datasource": { "type": "prometheus", "uid": "prometheus" },
+      "fieldConfig": { "defaults": { "unit": "reqps" } },
+      "targets": [
+        {
+          "refId": "A",
+          "expr": "sum by (status) (rate({{ ProjectName }}_requests_total[5m]))",
+          "legendFormat": "{{ "{{" }}status{{ "}}" }}"
+        }
+      ]
+    },
+    {
+      "id": 5,
+      "type": "timeseries",
+      "title": "Error ratio",
+      "gridPos": { "h": 8, "w": 12, "x": 12, "y": 6 },
+      "datasource": { "type": "prometheus", "uid": "prometheus" },
+      "fieldConfig": { "defaults": { "unit": "percentunit" } },
+      "targets": [
+        {
+          "refId": "A",
+          "expr": "sum(rate({{ ProjectName }}_requests_total{status=~\"5..\"}[5m])) / sum(rate({{ ProjectName }}_requests_total[5m]))",
+          "legendFormat": "5xx ratio"
+        }
+      ]
+    },
+    {
+      "id": 6,
+      "type": "timeseries",
+      "title": "Request duration",
+      "gridPos": { "h": 8, "w": 12, "x": 0, "y": 14 },
+      "datasource": { "type": "prometheus", "uid": "prometheus" },
+      "fieldConfig": { "defaults": { "unit": "s" } },
+      "targets": [
+        {
+          "refId": "A",
+          "expr": "histogram_quantile(0.50, sum by (le) (rate({{ ProjectName }}_request_duration_seconds_bucket[5m])))",
+          "legendFormat": "p50"
+        },
+        {
+          "refId": "B",
+          "expr": "histogram_quantile(0.95, sum by (le) (rate({{ ProjectName }}_request_duration_seconds_bucket[5m])))",
+          "legendFormat": "p95"
+        },
+        {
+          "refId": "C",
+          "expr": "histogram_quantile(0.99, sum by (le) (rate({{ ProjectName }}_request_duration_seconds_bucket[5m])))",
+          "legendFormat": "p99"
+        }
+      ]
+    },
+    {
+      "id": 7,
+      "type": "timeseries",
+      "title": "Requests per second by endpoint",
+      "gridPos": { "h": 8, "w": 12, "x": 12, "y": 14 },
+      "datasource": { "type": "prometheus", "uid": "prometheus" },
+      "fieldConfig": { "defaults": { "unit": "reqps" } },
+      "targets": [
+        {
+          "refId": "A",
+          "expr": "sum by (method, endpoint) (rate({{ ProjectName }}_requests_total[5m]))",
+          "legendFormat": "{{ "{{" }}method{{ "}}" }} {{ "{{" }}endpoint{{ "}}" }}"
+        }
+      ]
+    },
+    {
+      "id": 8,
+      "type": "logs",
+      "title": "Logs",
+      "gridPos": { "h": 10, "w": 24, "x": 0, "y": 22 },
+      "datasource": { "type": "loki", "uid": "loki" },
+      "options": { "showTime": true, "wrapLogMessage": true, "sortOrder": "Descending" },
+      "targets": [
+        {
+          "refId": "A",
+          "expr": "{service=\"{{ ProjectName }}\"}"
+        }
+      ]
+    }
+  ]
+}
+`
@@ -0,0 +1,55 @@
+package monitoring
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestGenerator_GenerateAll(t *testing.T) {
+	tempDir := t.TempDir()
+	config := Config{ProjectName: "orders"}
+
+	require.NoError(t, NewGenerator().GenerateAll(context.Background(), tempDir, config))
+
+	read := func(path string) string {
+		content, err := os.ReadFile(filepath.Join(tempDir, filepath.FromSlash(path)))
+		require.NoError(t, err)
+		return string(content)
+	}
+	for _, file := range Files(config) {
+		content := read(file)
+		if filepath.Ext(file) == ".json" {
+			var dashboard map[string]any
+			assert.NoError(t, json.Unmarshal([]byte(content), &dashboard), "%s is valid JSON", file)
+			continue
+		}
+		var document map[string]any
+		assert.NoError(t, yaml.Unmarshal([]byte(content), &document), "%s is valid YAML", file)
+	}
+
+	assert.Contains(t, read("monitoring/prometheus.yml"), `targets: ["orders:8080"]`)
+
+	alerts := read("monitoring/rules/alerts.yml")
+	assert.Contains(t, alerts, `orders_requests_total{status=~"5.."}[1h]`)
+	assert.Contains(t, alerts, "(14.4 * 0.001)")
+	assert.Contains(t, alerts, "orders_request_duration_seconds_bucket[5m]))) > 0.5")
+
+	slo := read("monitoring/slo.yml")
+	assert.Contains(t, slo, "objective: 99.9\n")
+	assert.Contains(t, slo, "[{{.window}}]", "sloth placeholders are kept")
+
+	assert.Contains(t, read("monitoring/promtail.yml"), "url: http://loki:3100/loki/api/v1/push")
+	assert.Contains(t, read("monitoring/grafana/provisioning/datasources/prometheus.yml"), "url: http://loki:3100")
+
+	dashboard := read("monitoring/grafana/dashboards/orders.json")
+	assert.Contains(t, dashboard, `"expr": "{service=\"orders\"}"`)
+	assert.Contains(t, dashboard, `"legendFormat": "{{status}}"`)
+	assert.Contains(t, dashboard, "sum by (status) (rate(orders_requests_total[5m]))")
+}
@@ -0,0 +1,136 @@
+package policy
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EnvPolicyPath overrides the location of the org policy file
+const EnvPolicyPath = "GOGO_POLICY"
+
+// Policy contains organization-wide rules that constrain what gogo may do
+type Policy struct {
+	Hooks    HookPolicy    `yaml:"hooks"`
+	Commits  CommitPolicy  `yaml:"commits"`
+	Licenses LicensePolicy `yaml:"licenses"`
+	State    StatePolicy   `yaml:"state"`
+	Signing  SigningPolicy `yaml:"signing"`
+}
+
+// HookPolicy constrains execution of template hooks. The network denial is
+// advisory: without AllowNetwork hooks get GOPROXY=off and proxy variables
+// that lead nowhere, which a command ignoring them can bypass.
+type HookPolicy struct {
+	AllowedCommands        []string      `yaml:"allowed_commands"`
+	AllowNetwork           bool          `yaml:"allow_network"`
+	Timeout                time.Duration `yaml:"timeout"`
+	ConfirmThirdPartyHooks bool          `yaml:"confirm_third_party_hooks"`
+}
+
+// CommitPolicy shapes the commits gogo creates so they pass commit linting.
+// Templates may use ProjectName, ModuleName, Template, Blueprint and
+// GogoVersion.
+type CommitPolicy struct {
+	MessageTemplate string   `yaml:"message_template" json:"message_template,omitempty"` // e.g. "chore: scaffold {{ ProjectName }}"
+	Trailers        []string `yaml:"trailers" json:"trailers,omitempty"`                 // e.g. "Generated-by: gogo v{{ GogoVersion }}"
+	CoAuthors       []string `yaml:"co_authors" json:"co_authors,omitempty"`             // "Name <email>", added as Co-authored-by trailers
+}
+
+// LicensePolicy requires third-party license compliance in new projects
+type LicensePolicy struct {
+	Required bool     `yaml:"required"` // Generate the license check and report in every new project
+	Allowed  []string `yaml:"allowed"`  // SPDX identifiers dependencies may use, e.g. "MIT"
+}
+
+// SigningPolicy decides whether templates must be signed. In strict mode
+// gogo refuses templates and bundles without a signature by a trusted key.
+type SigningPolicy struct {
+	Strict bool `yaml:"strict"`
+}
+
+// Where gogo keeps project state
+const (
+	StoreRepo     = "repo"     // .gogo.yaml and gogo.lock committed in the repository
+	StoreDatabase = "database" // the shared gogo database, keyed by module path
+)
+
+// StatePolicy decides where gogo keeps the state it records about projects:
+// their manifest, from which replay records are made, and the lockfile of
+// their workspace
+type StatePolicy struct {
+	Store string `yaml:"store"` // StoreRepo or StoreDatabase; StoreRepo when empty
+}
+
+// Remote reports whether project state is kept out of repositories
+func (s StatePolicy) Remote() bool {
+	return s.Store == StoreDatabase
+}
+
+// AllTrailers returns the configured trailers followed by a Co-authored-by
+// trailer for each co-author
+func (c CommitPolicy) AllTrailers() []string {
+	trailers := append([]string(nil), c.Trailers...)
+	for _, author := range c.CoAuthors {
+		trailers = append(trailers, "Co-authored-by: "+author)
+	}
+	return trailers
+}
+
+// Default returns the policy used when no policy file exists. Hooks may only
+// run commands that cannot execute code of their own; go, git and make run
+// code from the project (go generate, git hooks, Makefile targets), so an
+// org allows them in allowed_commands.
+func Default() *Policy {
+	return &Policy{
+		Hooks: HookPolicy{
+			AllowedCommands:        []string{"gofmt", "echo", "mkdir", "touch"},
+			AllowNetwork:           false,
+			Timeout:                2 * time.Minute,
+			ConfirmThirdPartyHooks: true,
+		},
+	}
+}
+
+// DefaultPath returns the location of the org policy file
+func DefaultPath() string {
+	if path := os.Getenv(EnvPolicyPath); path != "" {
+		return path
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return ".gogo-policy.yaml"
+	}
+	return filepath.Join(homeDir, ".gogo", "policy.yaml")
+}
+
+// Load reads a policy file, falling back to defaults for missing files and fields
+func Load(path string) (*Policy, error) {
+	p := Default()
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return p, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy file %s: %w", path, err)
+	}
+
+	if err := yaml.Unmarshal(data, p); err != nil {
+		return nil, fmt.Errorf("failed to parse policy file %s: %w", path, err)
+	}
+
+	if p.Hooks.Timeout <= 0 {
+		p.Hooks.Timeout = Default().Hooks.Timeout
+	}
+	switch p.State.Store {
+	case "", StoreRepo, StoreDatabase:
+	default:
+		return nil, fmt.Errorf("invalid state store %q in policy file %s (use %s or %s)", p.State.Store, path, StoreRepo, StoreDatabase)
+	}
+
+	return p, nil
+}
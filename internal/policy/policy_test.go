@@ -0,0 +1,115 @@
+package policy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		validate func(t *testing.T, p *Policy)
+	}{
+		{
+			name: "missing file uses defaults",
+			validate: func(t *testing.T, p *Policy) {
+				assert.Equal(t, Default(), p)
+				// Commands that run code of the project are opt-in
+				assert.NotContains(t, p.Hooks.AllowedCommands, "go")
+				assert.NotContains(t, p.Hooks.AllowedCommands, "git")
+				assert.NotContains(t, p.Hooks.AllowedCommands, "make")
+			},
+		},
+		{
+			name: "hook overrides",
+			content: `hooks:
+  allowed_commands: [go, buf]
+  allow_network: true
+  timeout: 30s
+`,
+			validate: func(t *testing.T, p *Policy) {
+				assert.Equal(t, []string{"go", "buf"}, p.Hooks.AllowedCommands)
+				assert.True(t, p.Hooks.AllowNetwork)
+				assert.Equal(t, 30*time.Second, p.Hooks.Timeout)
+				assert.True(t, p.Hooks.ConfirmThirdPartyHooks)
+			},
+		},
+		{
+			name: "commit templates and trailers",
+			content: `commits:
+  message_template: "chore: scaffold {{ ProjectName }}"
+  trailers: ["Generated-by: gogo v{{ GogoVersion }}"]
+  co_authors: ["Platform Team <platform@example.com>"]
+`,
+			validate: func(t *testing.T, p *Policy) {
+				assert.Equal(t, "chore: scaffold {{ ProjectName }}", p.Commits.MessageTemplate)
+				assert.Equal(t, []string{
+					"Generated-by: gogo v{{ GogoVersion }}",
+					"Co-authored-by: Platform Team <platform@example.com>",
+				}, p.Commits.AllTrailers())
+				assert.Equal(t, Default().Hooks, p.Hooks, "hook defaults are kept")
+			},
+		},
+		{
+			name: "license compliance",
+			content: `licenses:
+  required: true
+  allowed: [MIT, Apache-2.0]
+`,
+			validate: func(t *testing.T, p *Policy) {
+				assert.True(t, p.Licenses.Required)
+				assert.Equal(t, []string{"MIT", "Apache-2.0"}, p.Licenses.Allowed)
+			},
+		},
+		{
+			name:    "state in the database",
+			content: "state:\n  store: database\n",
+			validate: func(t *testing.T, p *Policy) {
+				assert.True(t, p.State.Remote())
+			},
+		},
+		{
+			name:    "strict signing",
+			content: "signing:\n  strict: true\n",
+			validate: func(t *testing.T, p *Policy) {
+				assert.True(t, p.Signing.Strict)
+				assert.False(t, Default().Signing.Strict)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "policy.yaml")
+			if tt.content != "" {
+				require.NoError(t, os.WriteFile(path, []byte(tt.content), 0644))
+			}
+
+			p, err := Load(path)
+			require.NoError(t, err)
+			tt.validate(t, p)
+		})
+	}
+}
+
+func TestLoad_InvalidYAML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("hooks: [unclosed"), 0644))
+
+	_, err := Load(path)
+	assert.Error(t, err)
+}
+
+func TestLoad_InvalidStateStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("state:\n  store: s3\n"), 0644))
+
+	_, err := Load(path)
+	assert.ErrorContains(t, err, `invalid state store "s3"`)
+}
@@ -0,0 +1,307 @@
+// Package preset keeps named combinations of gogo init options, such as a
+// template, blueprint, CI provider and coverage minimum, so a team's usual
+// project is one --preset flag. Presets are stored in a YAML file, by
+// default ~/.gogo/presets.yaml, and shared by exporting them to a file of
+// the same format that others import.
+package preset
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/user/gogo/internal/cicd"
+	"github.com/user/gogo/internal/components"
+	"github.com/user/gogo/internal/fsutil"
+	"github.com/user/gogo/internal/taskrunner"
+	"gopkg.in/yaml.v3"
+)
+
+// EnvPresetsPath overrides the location of the presets file
+const EnvPresetsPath = "GOGO_PRESETS"
+
+// namePattern matches preset names
+var namePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+
+// Preset is a named set of gogo init options. Each field but Name,
+// Description and ModulePrefix is the value of the init flag of the same
+// name; empty fields leave the flag alone.
+type Preset struct {
+	Name              string   `yaml:"name"`
+	Description       string   `yaml:"description,omitempty"`
+	Template          string   `yaml:"template,omitempty"`
+	Blueprint         string   `yaml:"blueprint,omitempty"`
+	Framework         string   `yaml:"framework,omitempty"`
+	ModulePrefix      string   `yaml:"module_prefix,omitempty"` // Module path the project name is appended to, e.g. github.com/acme
+	Author            string   `yaml:"author,omitempty"`
+	License           string   `yaml:"license,omitempty"`
+	GoVersion         string   `yaml:"go_version,omitempty"`
+	LanguageLevel     string   `yaml:"language_level,omitempty"`
+	GitInit           bool     `yaml:"git_init,omitempty"`
+	CIProvider        string   `yaml:"ci_provider,omitempty"`
+	DependencyUpdates string   `yaml:"dependency_updates,omitempty"`
+	CoverageMin       float64  `yaml:"coverage_min,omitempty"` // Percentage of code tests must cover, e.g. 85
+	CoverageExclude   []string `yaml:"coverage_exclude,omitempty"`
+	TaskRunner        string   `yaml:"task_runner,omitempty"`
+	TSClient          bool     `yaml:"ts_client,omitempty"`
+	Licenses          bool     `yaml:"licenses,omitempty"`
+	VerifyBuild       bool     `yaml:"verify_build,omitempty"`
+	Report            bool     `yaml:"report,omitempty"`
+	Omit              []string `yaml:"omit,omitempty"`
+}
+
+// File is the format of the presets file and of exports
+type File struct {
+	Presets []Preset `yaml:"presets"`
+}
+
+// flag pairs an init flag with the field of a preset holding its value, a
+// *string, *bool, *float64 or *[]string
+type flag struct {
+	name  string
+	field any
+}
+
+// flags returns the init flags p sets, in the order they are listed
+func (p *Preset) flags() []flag {
+	return []flag{
+		{"template", &p.Template},
+		{"blueprint", &p.Blueprint},
+		{"framework", &p.Framework},
+		{"author", &p.Author},
+		{"license", &p.License},
+		{"go-version", &p.GoVersion},
+		{"language-level", &p.LanguageLevel},
+		{"git-init", &p.GitInit},
+		{"ci-provider", &p.CIProvider},
+		{"dependency-updates", &p.DependencyUpdates},
+		{"coverage-min", &p.CoverageMin},
+		{"coverage-exclude", &p.CoverageExclude},
+		{"task-runner", &p.TaskRunner},
+		{"ts-client", &p.TSClient},
+		{"licenses", &p.Licenses},
+		{"verify-build", &p.VerifyBuild},
+		{"report", &p.Report},
+		{"omit", &p.Omit},
+	}
+}
+
+// FlagNames lists the init flags a preset can set
+func FlagNames() []string {
+	var names []string
+	for _, f := range (&Preset{}).flags() {
+		names = append(names, f.name)
+	}
+	return names
+}
+
+// Flags returns the init flags p sets, by name, with values as the command
+// line gives them; lists are comma-separated
+func (p Preset) Flags() map[string]string {
+	values := make(map[string]string)
+	for _, f := range p.flags() {
+		switch field := f.field.(type) {
+		case *string:
+			if *field != "" {
+				values[f.name] = *field
+			}
+		case *bool:
+			if *field {
+				values[f.name] = "true"
+			}
+		case *float64:
+			if *field != 0 {
+				values[f.name] = strconv.FormatFloat(*field, 'f', -1, 64)
+			}
+		case *[]string:
+			if len(*field) > 0 {
+				values[f.name] = strings.Join(*field, ",")
+			}
+		}
+	}
+	return values
+}
+
+// SetFlag sets the field of the init flag name from its command line value
+func (p *Preset) SetFlag(name, value string) error {
+	for _, f := range p.flags() {
+		if f.name != name {
+			continue
+		}
+		switch field := f.field.(type) {
+		case *string:
+			*field = value
+		case *bool:
+			flag, err := strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("--%s must be true or false, not %q", name, value)
+			}
+			*field = flag
+		case *float64:
+			number, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return fmt.Errorf("--%s must be a number, not %q", name, value)
+			}
+			*field = number
+		case *[]string:
+			*field = nil
+			for _, item := range strings.Split(value, ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					*field = append(*field, item)
+				}
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("presets cannot set --%s", name)
+}
+
+// Validate checks the preset's name and the options gogo can check without
+// the template and blueprint repositories
+func (p Preset) Validate() error {
+	if !namePattern.MatchString(p.Name) {
+		return fmt.Errorf("invalid preset name '%s' (use lower-case letters, digits, '.', '_' and '-')", p.Name)
+	}
+	if p.Framework != "" && !slices.Contains(components.Frameworks, p.Framework) {
+		return fmt.Errorf("preset %s: unsupported framework '%s', supported frameworks: %s", p.Name, p.Framework, strings.Join(components.Frameworks, ", "))
+	}
+	if _, err := cicd.ParseProvider(p.CIProvider); err != nil {
+		return fmt.Errorf("preset %s: %w", p.Name, err)
+	}
+	if _, err := cicd.ParseUpdateBot(p.DependencyUpdates); err != nil {
+		return fmt.Errorf("preset %s: %w", p.Name, err)
+	}
+	if _, err := taskrunner.ParseRunner(p.TaskRunner); err != nil {
+		return fmt.Errorf("preset %s: %w", p.Name, err)
+	}
+	if p.CoverageMin < 0 || p.CoverageMin > 100 {
+		return fmt.Errorf("preset %s: coverage_min must be a percentage between 0 and 100, not %v", p.Name, p.CoverageMin)
+	}
+	if _, err := cicd.CoveragePatterns(p.CoverageExclude); err != nil {
+		return fmt.Errorf("preset %s: %w", p.Name, err)
+	}
+	if strings.HasSuffix(p.ModulePrefix, "/") || strings.ContainsAny(p.ModulePrefix, " \t") {
+		return fmt.Errorf("preset %s: module_prefix must be a module path without a trailing slash", p.Name)
+	}
+	return nil
+}
+
+// Module returns the module path of project under ModulePrefix, or nothing
+// without a prefix
+func (p Preset) Module(project string) string {
+	if p.ModulePrefix == "" || project == "" {
+		return ""
+	}
+	return p.ModulePrefix + "/" + project
+}
+
+// DefaultPath returns the location of the presets file
+func DefaultPath() string {
+	if path := os.Getenv(EnvPresetsPath); path != "" {
+		return path
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return ".gogo-presets.yaml"
+	}
+	return filepath.Join(homeDir, ".gogo", "presets.yaml")
+}
+
+// Load reads the presets of a presets file or export, sorted by name; a
+// missing file has none
+func Load(path string) ([]Preset, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read presets file %s: %w", path, err)
+	}
+
+	var file File
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse presets file %s: %w", path, err)
+	}
+	seen := make(map[string]bool, len(file.Presets))
+	for i, p := range file.Presets {
+		// Presets saved before coverage_min was a percentage hold a fraction
+		if p.CoverageMin > 0 && p.CoverageMin <= 1 {
+			p.CoverageMin = math.Round(p.CoverageMin*1000) / 10
+			file.Presets[i] = p
+		}
+		if err := p.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid presets file %s: %w", path, err)
+		}
+		if seen[p.Name] {
+			return nil, fmt.Errorf("invalid presets file %s: preset %s is defined twice", path, p.Name)
+		}
+		seen[p.Name] = true
+	}
+	sortByName(file.Presets)
+	return file.Presets, nil
+}
+
+// Save writes presets to path, sorted by name, creating its directory
+func Save(path string, presets []Preset) error {
+	presets = slices.Clone(presets)
+	sortByName(presets)
+	data, err := yaml.Marshal(File{Presets: presets})
+	if err != nil {
+		return fmt.Errorf("failed to encode presets: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", path, err)
+	}
+	if err := fsutil.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write presets file %s: %w", path, err)
+	}
+	return nil
+}
+
+// Find returns the preset called name
+func Find(presets []Preset, name string) (Preset, error) {
+	for _, p := range presets {
+		if p.Name == name {
+			return p, nil
+		}
+	}
+	var names []string
+	for _, p := range presets {
+		names = append(names, p.Name)
+	}
+	if len(names) == 0 {
+		return Preset{}, fmt.Errorf("unknown preset %s (none saved; save one with gogo init --save-preset)", name)
+	}
+	return Preset{}, fmt.Errorf("unknown preset %s (available: %s)", name, strings.Join(names, ", "))
+}
+
+// Put adds p to presets, replacing the preset of the same name only when
+// replace is set
+func Put(presets []Preset, p Preset, replace bool) ([]Preset, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	for i, existing := range presets {
+		if existing.Name == p.Name {
+			if !replace {
+				return nil, fmt.Errorf("preset %s already exists (use --force to replace it)", p.Name)
+			}
+			presets = slices.Clone(presets)
+			presets[i] = p
+			return presets, nil
+		}
+	}
+	return append(slices.Clone(presets), p), nil
+}
+
+func sortByName(presets []Preset) {
+	sort.Slice(presets, func(i, j int) bool {
+		return presets[i].Name < presets[j].Name
+	})
+}
@@ -0,0 +1,125 @@
+package preset
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreset_Flags(t *testing.T) {
+	p := Preset{
+		Name:        "org-api",
+		Template:    "api",
+		Blueprint:   "web-stack",
+		GitInit:     true,
+		CoverageMin: 85,
+		Omit:        []string{"Dockerfile", "docker-compose.yml"},
+	}
+	flags := p.Flags()
+	assert.Equal(t, map[string]string{
+		"template":     "api",
+		"blueprint":    "web-stack",
+		"git-init":     "true",
+		"coverage-min": "85",
+		"omit":         "Dockerfile,docker-compose.yml",
+	}, flags)
+
+	restored := Preset{Name: "org-api"}
+	for name, value := range flags {
+		require.NoError(t, restored.SetFlag(name, value))
+	}
+	assert.Equal(t, p, restored)
+
+	assert.ErrorContains(t, restored.SetFlag("git-init", "maybe"), "must be true or false")
+	assert.ErrorContains(t, restored.SetFlag("into", "."), "presets cannot set --into")
+	assert.Contains(t, FlagNames(), "task-runner")
+}
+
+func TestPreset_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		preset  Preset
+		wantErr string
+	}{
+		{name: "valid", preset: Preset{Name: "org-api", CIProvider: "gitlab", TaskRunner: "task", ModulePrefix: "github.com/acme"}},
+		{name: "bad name", preset: Preset{Name: "Org API"}, wantErr: "invalid preset name"},
+		{name: "bad framework", preset: Preset{Name: "a", Framework: "fiber"}, wantErr: "unsupported framework"},
+		{name: "bad provider", preset: Preset{Name: "a", CIProvider: "jenkins"}, wantErr: "unknown CI provider"},
+		{name: "bad runner", preset: Preset{Name: "a", TaskRunner: "just"}, wantErr: "unknown task runner"},
+		{name: "bad coverage", preset: Preset{Name: "a", CoverageMin: 185}, wantErr: "coverage_min must be a percentage between 0 and 100"},
+		{name: "bad module prefix", preset: Preset{Name: "a", ModulePrefix: "github.com/acme/"}, wantErr: "module_prefix"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.preset.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+
+	assert.Equal(t, "github.com/acme/orders", Preset{ModulePrefix: "github.com/acme"}.Module("orders"))
+	assert.Empty(t, Preset{}.Module("orders"))
+}
+
+func TestSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gogo", "presets.yaml")
+
+	presets, err := Load(path)
+	require.NoError(t, err)
+	assert.Empty(t, presets, "a missing file has no presets")
+
+	presets, err = Put(presets, Preset{Name: "worker", Template: "microservice"}, false)
+	require.NoError(t, err)
+	presets, err = Put(presets, Preset{Name: "api", Template: "api"}, false)
+	require.NoError(t, err)
+	_, err = Put(presets, Preset{Name: "api", Template: "cli"}, false)
+	assert.ErrorContains(t, err, "already exists")
+	replaced, err := Put(presets, Preset{Name: "api", Template: "cli"}, true)
+	require.NoError(t, err)
+	assert.Equal(t, "api", presets[1].Template, "the original list is left alone")
+	require.NoError(t, Save(path, replaced))
+
+	loaded, err := Load(path)
+	require.NoError(t, err)
+	require.Len(t, loaded, 2)
+	assert.Equal(t, "api", loaded[0].Name, "presets are sorted by name")
+	assert.Equal(t, "cli", loaded[0].Template)
+
+	found, err := Find(loaded, "worker")
+	require.NoError(t, err)
+	assert.Equal(t, "microservice", found.Template)
+	_, err = Find(loaded, "web")
+	assert.ErrorContains(t, err, "available: api, worker")
+
+	require.NoError(t, os.WriteFile(path, []byte("presets:\n  - name: api\n  - name: api\n"), 0644))
+	_, err = Load(path)
+	assert.ErrorContains(t, err, "defined twice")
+}
+
+func TestLoad_CoverageMinPercentage(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "presets.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`presets:
+  - name: current
+    coverage_min: 85
+  - name: legacy
+    coverage_min: 0.9
+`), 0644))
+
+	presets, err := Load(path)
+	require.NoError(t, err)
+	require.Len(t, presets, 2)
+	assert.Equal(t, 85.0, presets[0].CoverageMin)
+	assert.Equal(t, 90.0, presets[1].CoverageMin, "fractions of older presets become percentages")
+
+	// Saved again, both are percentages
+	require.NoError(t, Save(path, presets))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "coverage_min: 90")
+}
@@ -0,0 +1,273 @@
+package project
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/user/gogo/internal/assetsync"
+	"github.com/user/gogo/internal/db"
+	"github.com/user/gogo/internal/state"
+)
+
+// Files written next to the project tree in an archive
+const (
+	ArchiveIndexFile  = "ARCHIVE.json"
+	ArchiveReplayFile = "replay.json"
+	ArchiveDBFile     = "db.json"
+	ArchiveVersion    = "1"
+)
+
+// ArchiveOptions controls Archive
+type ArchiveOptions struct {
+	Dir         string // project directory
+	Output      string // .tar.gz file to write
+	GogoVersion string
+	// Entries are the database records of the project's template and
+	// blueprint; nil leaves db.json out of the archive
+	Entries []*db.EntryRecord
+}
+
+// ArchiveIndex describes an archive's contents; it is stored as ARCHIVE.json
+type ArchiveIndex struct {
+	Version     string         `json:"version"`
+	CreatedAt   time.Time      `json:"created_at"`
+	GogoVersion string         `json:"gogo_version"`
+	Project     string         `json:"project"`
+	Template    string         `json:"template"`
+	Blueprint   string         `json:"blueprint,omitempty"`
+	Lockfile    bool           `json:"lockfile"`
+	Database    bool           `json:"database"`
+	Files       []ArchivedFile `json:"files"`
+}
+
+// ArchivedFile is a project file stored in an archive
+type ArchivedFile struct {
+	Path   string `json:"path"` // relative to the project directory
+	SHA256 string `json:"sha256"`
+	Size   int64  `json:"size"`
+}
+
+// Replay records how to regenerate the project from scratch
+type Replay struct {
+	Command     []string  `json:"command"`
+	GogoVersion string    `json:"gogo_version"`
+	GeneratedAt time.Time `json:"generated_at"`
+}
+
+// ReplayFor returns the gogo init invocation that generated a project
+func ReplayFor(manifest *Manifest) Replay {
+	command := []string{"gogo", "init", manifest.ProjectName,
+		"--module=" + manifest.ModuleName,
+		"--template=" + manifest.Template,
+	}
+	if manifest.Blueprint != "" {
+		command = append(command, "--blueprint="+manifest.Blueprint)
+	}
+	if manifest.GoVersion != "" {
+		command = append(command, "--go-version="+manifest.GoVersion)
+	}
+	command = append(command, "--no-wizard")
+
+	return Replay{Command: command, GogoVersion: manifest.GogoVersion, GeneratedAt: manifest.GeneratedAt}
+}
+
+// Archive writes a gzipped tarball holding the project tree (without .git),
+// its manifest, the workspace lockfile, a replay record and the database
+// entries of its template and blueprint, so the project can be audited or
+// reconstructed without the shared database
+func Archive(opts ArchiveOptions) (*ArchiveIndex, error) {
+	manifest, err := LoadManifest(opts.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	absOutput, err := filepath.Abs(opts.Output)
+	if err != nil {
+		return nil, fmt.Errorf("invalid output path %s: %w", opts.Output, err)
+	}
+	file, err := os.Create(absOutput)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create archive: %w", err)
+	}
+	defer file.Close()
+
+	gz := gzip.NewWriter(file)
+	tw := tar.NewWriter(gz)
+	root := manifest.ProjectName
+	now := time.Now().UTC().Truncate(time.Second)
+
+	index := &ArchiveIndex{
+		Version:     ArchiveVersion,
+		CreatedAt:   now,
+		GogoVersion: opts.GogoVersion,
+		Project:     manifest.ProjectName,
+		Template:    manifest.Template,
+		Blueprint:   manifest.Blueprint,
+	}
+
+	index.Files, err = archiveTree(tw, opts.Dir, path.Join(root, "project"), absOutput)
+	if err != nil {
+		return nil, err
+	}
+	// A manifest kept in the gogo database goes with the project files, so the
+	// archive restores a project gogo knows
+	if _, err := os.Stat(filepath.Join(opts.Dir, ManifestFile)); os.IsNotExist(err) {
+		data, err := state.Read(opts.Dir, ManifestFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read manifest: %w", err)
+		}
+		if err := writeTarFile(tw, path.Join(root, "project", ManifestFile), data, now); err != nil {
+			return nil, err
+		}
+		sum := sha256.Sum256(data)
+		index.Files = append(index.Files, ArchivedFile{Path: ManifestFile, SHA256: hex.EncodeToString(sum[:]), Size: int64(len(data))})
+	}
+
+	lockfile, err := readLockfile(opts.Dir)
+	if err != nil {
+		return nil, err
+	}
+	if lockfile != nil {
+		if err := writeTarFile(tw, path.Join(root, assetsync.LockFile), lockfile, now); err != nil {
+			return nil, err
+		}
+		index.Lockfile = true
+	}
+
+	if err := writeTarJSON(tw, path.Join(root, ArchiveReplayFile), ReplayFor(manifest), now); err != nil {
+		return nil, err
+	}
+	if opts.Entries != nil {
+		if err := writeTarJSON(tw, path.Join(root, ArchiveDBFile), opts.Entries, now); err != nil {
+			return nil, err
+		}
+		index.Database = true
+	}
+	if err := writeTarJSON(tw, path.Join(root, ArchiveIndexFile), index, now); err != nil {
+		return nil, err
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to finish archive: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return nil, fmt.Errorf("failed to finish archive: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write archive: %w", err)
+	}
+	return index, nil
+}
+
+// archiveTree adds every file under dir except .git and the archive itself
+func archiveTree(tw *tar.Writer, dir, prefix, skip string) ([]ArchivedFile, error) {
+	var files []ArchivedFile
+	err := filepath.WalkDir(dir, func(p string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() && entry.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		if abs, err := filepath.Abs(p); err == nil && abs == skip {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil || rel == "." {
+			return err
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		link := ""
+		if info.Mode()&fs.ModeSymlink != 0 {
+			if link, err = os.Readlink(p); err != nil {
+				return err
+			}
+		}
+		header, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		header.Name = path.Join(prefix, filepath.ToSlash(rel))
+		if entry.IsDir() {
+			header.Name += "/"
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		src, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer src.Close()
+		hash := sha256.New()
+		if _, err := io.Copy(io.MultiWriter(tw, hash), src); err != nil {
+			return err
+		}
+		files = append(files, ArchivedFile{Path: filepath.ToSlash(rel), SHA256: hex.EncodeToString(hash.Sum(nil)), Size: info.Size()})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to archive project files: %w", err)
+	}
+	return files, nil
+}
+
+// readLockfile returns the gogo sync lockfile of dir or its parents, from
+// the state store when the org policy keeps it there, or nil when none has one
+func readLockfile(dir string) ([]byte, error) {
+	current, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, nil
+	}
+	for {
+		data, err := state.Read(current, assetsync.LockFile)
+		if err == nil {
+			return data, nil
+		}
+		if !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to read %s: %w", assetsync.LockFile, err)
+		}
+		parent := filepath.Dir(current)
+		if parent == current {
+			return nil, nil
+		}
+		current = parent
+	}
+}
+
+func writeTarJSON(tw *tar.Writer, name string, v any, modTime time.Time) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", name, err)
+	}
+	return writeTarFile(tw, name, append(data, '\n'), modTime)
+}
+
+func writeTarFile(tw *tar.Writer, name string, data []byte, modTime time.Time) error {
+	header := &tar.Header{Name: name, Mode: 0644, Size: int64(len(data)), ModTime: modTime, Typeflag: tar.TypeReg}
+	if err := tw.WriteHeader(header); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package project
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/user/gogo/internal/db"
+)
+
+// readArchive returns the regular files in a .tar.gz by name
+func readArchive(t *testing.T, path string) map[string]string {
+	t.Helper()
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	require.NoError(t, err)
+	tr := tar.NewReader(gz)
+
+	files := make(map[string]string)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		content, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		files[header.Name] = string(content)
+	}
+	return files
+}
+
+func TestArchive(t *testing.T) {
+	base := t.TempDir()
+	dir := filepath.Join(base, "demo")
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".git"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "cmd"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".git", "HEAD"), []byte("ref: refs/heads/main\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "cmd", "main.go"), []byte("package main\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(base, "gogo.lock"), []byte("sources: []\n"), 0644))
+
+	manifest := &Manifest{
+		GogoVersion: "v1.2.0",
+		Template:    "cli",
+		Blueprint:   "observability",
+		ProjectName: "demo",
+		ModuleName:  "github.com/user/demo",
+		GoVersion:   "1.25.1",
+		GeneratedAt: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+	require.NoError(t, manifest.TrackFiles(dir, []string{"cmd/main.go"}))
+	require.NoError(t, manifest.Save(dir))
+
+	// The archive may live inside the project it archives
+	output := filepath.Join(dir, "demo.tar.gz")
+	entries := []*db.EntryRecord{{Kind: db.KindTemplate, Name: "cli", Tags: []string{"stable"}}}
+	index, err := Archive(ArchiveOptions{Dir: dir, Output: output, GogoVersion: "v1.3.0", Entries: entries})
+	require.NoError(t, err)
+	assert.True(t, index.Lockfile)
+	assert.True(t, index.Database)
+
+	paths := make([]string, 0, len(index.Files))
+	for _, file := range index.Files {
+		paths = append(paths, file.Path)
+	}
+	assert.ElementsMatch(t, []string{ManifestFile, "cmd/main.go"}, paths)
+
+	files := readArchive(t, output)
+	assert.Equal(t, "package main\n", files["demo/project/cmd/main.go"])
+	assert.Contains(t, files, "demo/project/"+ManifestFile)
+	assert.Equal(t, "sources: []\n", files["demo/gogo.lock"])
+	assert.NotContains(t, files, "demo/project/.git/HEAD")
+	assert.NotContains(t, files, "demo/project/demo.tar.gz")
+
+	var replay Replay
+	require.NoError(t, json.Unmarshal([]byte(files["demo/"+ArchiveReplayFile]), &replay))
+	assert.Equal(t, []string{"gogo", "init", "demo", "--module=github.com/user/demo", "--template=cli",
+		"--blueprint=observability", "--go-version=1.25.1", "--no-wizard"}, replay.Command)
+	assert.Equal(t, "v1.2.0", replay.GogoVersion)
+
+	var records []*db.EntryRecord
+	require.NoError(t, json.Unmarshal([]byte(files["demo/"+ArchiveDBFile]), &records))
+	require.Len(t, records, 1)
+	assert.Equal(t, []string{"stable"}, records[0].Tags)
+
+	var stored ArchiveIndex
+	require.NoError(t, json.Unmarshal([]byte(files["demo/"+ArchiveIndexFile]), &stored))
+	assert.Equal(t, "v1.3.0", stored.GogoVersion)
+	assert.Len(t, stored.Files, 2)
+}
+
+func TestArchive_NotAProject(t *testing.T) {
+	_, err := Archive(ArchiveOptions{Dir: t.TempDir(), Output: filepath.Join(t.TempDir(), "out.tar.gz")})
+	assert.Error(t, err)
+}
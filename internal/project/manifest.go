@@ -0,0 +1,195 @@
+package project
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/user/gogo/internal/fsutil"
+	"github.com/user/gogo/internal/merge"
+	"github.com/user/gogo/internal/state"
+	"gopkg.in/yaml.v3"
+)
+
+// ManifestFile is the name of the manifest written into generated projects
+const ManifestFile = ".gogo.yaml"
+
+// Manifest records how a project was generated
+type Manifest struct {
+	GogoVersion string         `yaml:"gogo_version"`
+	Template    string         `yaml:"template"`
+	Blueprint   string         `yaml:"blueprint,omitempty"`
+	ProjectName string         `yaml:"project_name"`
+	ModuleName  string         `yaml:"module_name"`
+	GoVersion   string         `yaml:"go_version"`
+	GeneratedAt time.Time      `yaml:"generated_at"`
+	Framework   string         `yaml:"framework,omitempty"` // HTTP framework among the components; gogo add's default
+	Database    string         `yaml:"database,omitempty"`  // database library among the components; gogo add's default
+	Components  []string       `yaml:"components,omitempty"`
+	Variables   map[string]any `yaml:"variables,omitempty"` // other values the templates were rendered with
+	CI          *CISettings    `yaml:"ci,omitempty"`
+	Omit        []string       `yaml:"omit,omitempty"`        // generated files left out at generation; gogo upgrade does not add them
+	TaskRunner  string         `yaml:"task_runner,omitempty"` // make or task, the runner of the generated targets; make when empty
+	NextSteps   []string       `yaml:"next_steps,omitempty"`  // declared by the template and blueprint, as shown after gogo init
+	DriftIgnore []string       `yaml:"drift_ignore,omitempty"`
+	Files       []ManagedFile  `yaml:"files"`
+}
+
+// DefaultDriftIgnore lists the managed files teams are expected to edit after
+// generation. gogo drift does not report them; projects adjust the list in
+// the drift_ignore field of their manifest.
+var DefaultDriftIgnore = []string{"*.go", "go.mod", "go.sum", "README.md"}
+
+// CISettings records the CI/CD options used during generation
+type CISettings struct {
+	Provider          string   `yaml:"provider,omitempty"`           // CI provider of the pipeline; GitHub Actions when empty
+	DependencyUpdates string   `yaml:"dependency_updates,omitempty"` // Dependency update bot; none when empty
+	CoverageMin       float64  `yaml:"coverage_min"`
+	CoverageExclude   []string `yaml:"coverage_exclude,omitempty"` // Code left out of coverage: names such as generated, or path patterns
+	HasDatabase       bool     `yaml:"has_database,omitempty"`
+	DatabaseType      string   `yaml:"database_type,omitempty"`
+	LicenseReport     bool     `yaml:"license_report,omitempty"`
+}
+
+// ManagedFile is a file written by gogo along with its content hash at generation time
+type ManagedFile struct {
+	Path   string `yaml:"path"`
+	SHA256 string `yaml:"sha256"`
+	// Sections is the hash of the gogo:start/gogo:end sections of a file
+	// with gogo markers, the only content of it gogo owns
+	Sections string `yaml:"sections_sha256,omitempty"`
+}
+
+// LoadManifest reads the manifest of the project in dir, from the state
+// store when the org policy keeps it out of the repository
+func LoadManifest(dir string) (*Manifest, error) {
+	return loadManifest(dir, state.Read)
+}
+
+// HasManifest reports whether the project in dir has a manifest, that is
+// whether gogo generated it
+func HasManifest(dir string) bool {
+	_, err := state.Read(dir, ManifestFile)
+	return err == nil
+}
+
+// LoadManifestFile reads the manifest file in dir, such as that of a project
+// rendered aside, wherever project state is kept
+func LoadManifestFile(dir string) (*Manifest, error) {
+	return loadManifest(dir, func(dir, name string) ([]byte, error) {
+		return os.ReadFile(filepath.Join(dir, name))
+	})
+}
+
+func loadManifest(dir string, read func(dir, name string) ([]byte, error)) (*Manifest, error) {
+	path := filepath.Join(dir, ManifestFile)
+	data, err := read(dir, ManifestFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("no %s found in %s (was this project generated by gogo?)", ManifestFile, dir)
+		}
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	var manifest Manifest
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest %s: %w", path, err)
+	}
+
+	return &manifest, nil
+}
+
+// Save writes the manifest of the project in dir, into the state store when
+// the org policy keeps it out of the repository
+func (m *Manifest) Save(dir string) error {
+	data, err := m.encode()
+	if err != nil {
+		return err
+	}
+	if err := state.Write(dir, m.ModuleName, ManifestFile, data); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+	return nil
+}
+
+// SaveFile writes the manifest file into dir wherever project state is
+// kept; generation moves it to the state store once the project is in place
+func (m *Manifest) SaveFile(dir string) error {
+	data, err := m.encode()
+	if err != nil {
+		return err
+	}
+	if err := fsutil.WriteFile(filepath.Join(dir, ManifestFile), data, 0644); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+	return nil
+}
+
+func (m *Manifest) encode() ([]byte, error) {
+	sort.Slice(m.Files, func(i, j int) bool {
+		return m.Files[i].Path < m.Files[j].Path
+	})
+
+	data, err := yaml.Marshal(m)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode manifest: %w", err)
+	}
+
+	header := []byte("# Generated by gogo. Used by `gogo status` and `gogo upgrade`; only edit drift_ignore by hand.\n")
+	return append(header, data...), nil
+}
+
+// TrackFiles hashes the given project-relative files and records them as managed
+func (m *Manifest) TrackFiles(dir string, paths []string) error {
+	for _, path := range paths {
+		sum, err := HashFile(filepath.Join(dir, path))
+		if err != nil {
+			return err
+		}
+		sections, err := HashSections(filepath.Join(dir, path))
+		if err != nil {
+			return err
+		}
+		m.Files = append(m.Files, ManagedFile{Path: filepath.ToSlash(path), SHA256: sum, Sections: sections})
+	}
+	return nil
+}
+
+// HashFile returns the hex-encoded SHA-256 of a file
+func HashFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", fmt.Errorf("failed to hash %s: %w", path, err)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// HashSections returns the hex-encoded SHA-256 of the gogo:start/gogo:end
+// sections of a file, or nothing when it has no gogo markers
+func HashSections(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if !merge.HasSections(string(data)) {
+		return "", nil
+	}
+	sections, err := merge.Sections(string(data))
+	if err != nil {
+		return "", fmt.Errorf("invalid gogo markers in %s: %w", path, err)
+	}
+	sum := sha256.Sum256([]byte(sections))
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,128 @@
+package project
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManifest_SaveAndLoad(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0644))
+
+	manifest := &Manifest{
+		GogoVersion: "v1.2.0",
+		Template:    "cli",
+		ProjectName: "demo",
+		ModuleName:  "github.com/user/demo",
+		GoVersion:   "1.25.1",
+		GeneratedAt: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		Framework:   "chi",
+		Components:  []string{"chi", "viper"},
+		Variables:   map[string]any{"License": "MIT", "HasDocker": true},
+	}
+	require.NoError(t, manifest.TrackFiles(dir, []string{"main.go"}))
+	require.NoError(t, manifest.Save(dir))
+
+	loaded, err := LoadManifest(dir)
+	require.NoError(t, err)
+	assert.Equal(t, manifest.GogoVersion, loaded.GogoVersion)
+	assert.Equal(t, manifest.Template, loaded.Template)
+	assert.True(t, manifest.GeneratedAt.Equal(loaded.GeneratedAt))
+	assert.Equal(t, "chi", loaded.Framework)
+	assert.Equal(t, manifest.Components, loaded.Components)
+	assert.Equal(t, manifest.Variables, loaded.Variables)
+	require.Len(t, loaded.Files, 1)
+	assert.Equal(t, "main.go", loaded.Files[0].Path)
+	assert.Len(t, loaded.Files[0].SHA256, 64)
+}
+
+func TestLoadManifest_Missing(t *testing.T) {
+	_, err := LoadManifest(t.TempDir())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), ManifestFile)
+}
+
+func TestDetectDrift(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a.go", "b.go", "c.go"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("package a\n"), 0644))
+	}
+
+	manifest := &Manifest{}
+	require.NoError(t, manifest.TrackFiles(dir, []string{"a.go", "b.go", "c.go"}))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "b.go"), []byte("package b\n"), 0644))
+	require.NoError(t, os.Remove(filepath.Join(dir, "c.go")))
+
+	drift, err := DetectDrift(dir, manifest)
+	require.NoError(t, err)
+	assert.Equal(t, []FileDrift{
+		{Path: "b.go", State: DriftModified},
+		{Path: "c.go", State: DriftMissing},
+	}, drift)
+}
+
+func TestIgnoreDrift(t *testing.T) {
+	drift := []FileDrift{
+		{Path: "cmd/demo/main.go", State: DriftModified},
+		{Path: "go.sum", State: DriftModified},
+		{Path: "config/prod.yaml", State: DriftModified},
+		{Path: "deploy/systemd/demo.service", State: DriftMissing},
+		{Path: "Makefile", State: DriftModified},
+	}
+
+	kept, ignored := IgnoreDrift(drift, []string{"*.go", "go.sum", "config/", "deploy/*/demo.service"})
+	assert.Equal(t, []FileDrift{{Path: "Makefile", State: DriftModified}}, kept)
+	assert.Len(t, ignored, 4)
+
+	kept, ignored = IgnoreDrift(drift, []string{"main.go/", "cmd/*.go"})
+	assert.Equal(t, drift, kept)
+	assert.Empty(t, ignored)
+}
+
+func TestCheckStatus(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".golangci.yml"), []byte("outdated\n"), 0644))
+
+	manifest := &Manifest{
+		GogoVersion: "v1.0.0",
+		ProjectName: "demo",
+		GoVersion:   "1.25.1",
+		CI:          &CISettings{CoverageMin: 0.8},
+	}
+	require.NoError(t, manifest.TrackFiles(dir, []string{".golangci.yml"}))
+	require.NoError(t, manifest.Save(dir))
+
+	status, err := CheckStatus(context.Background(), dir, "v1.1.0")
+	require.NoError(t, err)
+	assert.True(t, status.UpgradeAvailable)
+	assert.Empty(t, status.Drift)
+	assert.Equal(t, []string{".golangci.yml"}, status.StaleCIFiles)
+}
+
+func TestIsNewerVersion(t *testing.T) {
+	tests := []struct {
+		generated string
+		current   string
+		expected  bool
+	}{
+		{"v1.0.0", "v1.1.0", true},
+		{"v1.2.0", "v1.1.9", false},
+		{"v1.0.0", "v1.0.0", false},
+		{"1.0", "1.0.1", true},
+		{"v1.0.0", "dev", false},
+		{"", "v1.0.0", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.generated+"->"+tt.current, func(t *testing.T) {
+			assert.Equal(t, tt.expected, isNewerVersion(tt.generated, tt.current))
+		})
+	}
+}
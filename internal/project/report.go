@@ -0,0 +1,122 @@
+package project
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/user/gogo/internal/fsutil"
+)
+
+// Where the generation report is written, relative to the project
+const (
+	ReportDir  = ".gogo"
+	ReportFile = "report.json"
+)
+
+// Report is a machine-readable record of one gogo init or generate run, for
+// CI pipelines to archive and assert on
+type Report struct {
+	Command     string         `json:"command"` // init or generate
+	GogoVersion string         `json:"gogo_version,omitempty"`
+	Template    string         `json:"template,omitempty"`
+	Blueprint   string         `json:"blueprint,omitempty"`
+	Component   string         `json:"component,omitempty"`
+	StartedAt   time.Time      `json:"started_at"`
+	DurationMS  int64          `json:"duration_ms"`
+	Files       []ReportedFile `json:"files"`
+	Variables   map[string]any `json:"variables,omitempty"`
+	Hooks       []ReportedStep `json:"hooks"`  // commands run on the generated files, such as go build for --verify-build
+	Phases      []ReportedStep `json:"phases"` // how long each part of the run took
+	Warnings    []string       `json:"warnings"`
+}
+
+// ReportedFile is a file written by the run
+type ReportedFile struct {
+	Path   string `json:"path"` // relative to the project directory
+	SHA256 string `json:"sha256"`
+}
+
+// ReportedStep is a timed command or part of a run
+type ReportedStep struct {
+	Name       string `json:"name"`
+	DurationMS int64  `json:"duration_ms"`
+}
+
+// NewReport starts the report of a run
+func NewReport(command string) *Report {
+	return &Report{
+		Command:   command,
+		StartedAt: time.Now(),
+		Files:     []ReportedFile{},
+		Hooks:     []ReportedStep{},
+		Phases:    []ReportedStep{},
+		Warnings:  []string{},
+	}
+}
+
+// Hook records a command that ran since start. A nil report records nothing,
+// so runs without a report can call it unconditionally.
+func (r *Report) Hook(name string, start time.Time) {
+	if r == nil {
+		return
+	}
+	r.Hooks = append(r.Hooks, ReportedStep{Name: name, DurationMS: time.Since(start).Milliseconds()})
+}
+
+// Phase records a part of the run that began at start
+func (r *Report) Phase(name string, start time.Time) {
+	if r == nil {
+		return
+	}
+	r.Phases = append(r.Phases, ReportedStep{Name: name, DurationMS: time.Since(start).Milliseconds()})
+}
+
+// Warn records a warning
+func (r *Report) Warn(format string, args ...any) {
+	if r == nil {
+		return
+	}
+	r.Warnings = append(r.Warnings, fmt.Sprintf(format, args...))
+}
+
+// TrackFiles hashes the given project-relative files and records them as written
+func (r *Report) TrackFiles(dir string, paths []string) error {
+	if r == nil {
+		return nil
+	}
+	for _, path := range paths {
+		sum, err := HashFile(filepath.Join(dir, path))
+		if err != nil {
+			return err
+		}
+		r.Files = append(r.Files, ReportedFile{Path: filepath.ToSlash(path), SHA256: sum})
+	}
+	return nil
+}
+
+// Save completes the report and writes it into dir, returning the path written
+func (r *Report) Save(dir string) (string, error) {
+	sort.Slice(r.Files, func(i, j int) bool {
+		return r.Files[i].Path < r.Files[j].Path
+	})
+	r.DurationMS = time.Since(r.StartedAt).Milliseconds()
+
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode report: %w", err)
+	}
+
+	reportDir := filepath.Join(dir, ReportDir)
+	if err := os.MkdirAll(reportDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", reportDir, err)
+	}
+	path := filepath.Join(reportDir, ReportFile)
+	if err := fsutil.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return "", fmt.Errorf("failed to write report: %w", err)
+	}
+	return path, nil
+}
@@ -0,0 +1,51 @@
+package project
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReport_Save(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module demo\n"), 0644))
+
+	report := NewReport("generate")
+	report.Component = "handler"
+	report.Hook("go build ./...", time.Now())
+	report.Warn("kept existing %s", "go.mod")
+	require.NoError(t, report.TrackFiles(dir, []string{"main.go", "go.mod"}))
+
+	path, err := report.Save(dir)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, ReportDir, ReportFile), path)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var saved Report
+	require.NoError(t, json.Unmarshal(data, &saved))
+	assert.Equal(t, "handler", saved.Component)
+	assert.Equal(t, []string{"go.mod", "main.go"}, []string{saved.Files[0].Path, saved.Files[1].Path})
+	assert.Equal(t, "go build ./...", saved.Hooks[0].Name)
+	assert.Equal(t, []string{"kept existing go.mod"}, saved.Warnings)
+	assert.Empty(t, saved.Phases)
+
+	sum, err := HashFile(filepath.Join(dir, "main.go"))
+	require.NoError(t, err)
+	assert.Equal(t, sum, saved.Files[1].SHA256)
+}
+
+func TestReport_Nil(t *testing.T) {
+	// Runs without a report record into a nil one
+	var report *Report
+	report.Hook("git init", time.Now())
+	report.Phase("render", time.Now())
+	report.Warn("ignored")
+	assert.NoError(t, report.TrackFiles(t.TempDir(), []string{"missing.go"}))
+}
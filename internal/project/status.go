@@ -0,0 +1,211 @@
+package project
+
+import (
+	"context"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/user/gogo/internal/cicd"
+)
+
+// Drift states for managed files
+const (
+	DriftModified = "modified"
+	DriftMissing  = "missing"
+)
+
+// FileDrift describes a managed file that no longer matches what gogo generated
+type FileDrift struct {
+	Path  string
+	State string
+}
+
+// Status summarizes the state of a generated project
+type Status struct {
+	Manifest         *Manifest
+	CurrentVersion   string
+	UpgradeAvailable bool
+	Drift            []FileDrift
+	StaleCIFiles     []string
+}
+
+// CheckStatus inspects a generated project against its manifest
+func CheckStatus(ctx context.Context, dir, currentVersion string) (*Status, error) {
+	manifest, err := LoadManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &Status{
+		Manifest:         manifest,
+		CurrentVersion:   currentVersion,
+		UpgradeAvailable: isNewerVersion(manifest.GogoVersion, currentVersion),
+	}
+
+	status.Drift, err = DetectDrift(dir, manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	if manifest.CI != nil {
+		status.StaleCIFiles, err = staleCIFiles(ctx, manifest)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return status, nil
+}
+
+// DetectDrift compares managed files on disk with the hashes recorded at generation time
+func DetectDrift(dir string, manifest *Manifest) ([]FileDrift, error) {
+	var drift []FileDrift
+	for _, file := range manifest.Files {
+		sum, err := HashFile(filepath.Join(dir, filepath.FromSlash(file.Path)))
+		if err != nil {
+			if _, statErr := os.Stat(filepath.Join(dir, filepath.FromSlash(file.Path))); os.IsNotExist(statErr) {
+				drift = append(drift, FileDrift{Path: file.Path, State: DriftMissing})
+				continue
+			}
+			return nil, err
+		}
+		if sum == file.SHA256 {
+			continue
+		}
+		// Edits outside the sections gogo owns are not drift
+		if file.Sections != "" {
+			if sections, err := HashSections(filepath.Join(dir, filepath.FromSlash(file.Path))); err == nil && sections == file.Sections {
+				continue
+			}
+		}
+		drift = append(drift, FileDrift{Path: file.Path, State: DriftModified})
+	}
+	return drift, nil
+}
+
+// IgnoreDrift splits drift into the files not matched by any ignore pattern
+// and those that are. Like .gitignore, a pattern without a slash matches file
+// names in any directory, a pattern ending in a slash matches everything
+// below that directory and any other pattern matches the whole path.
+func IgnoreDrift(drift []FileDrift, patterns []string) (kept, ignored []FileDrift) {
+	for _, file := range drift {
+		if matchesAny(file.Path, patterns) {
+			ignored = append(ignored, file)
+		} else {
+			kept = append(kept, file)
+		}
+	}
+	return kept, ignored
+}
+
+// matchesAny reports whether a slash-separated path matches an ignore pattern
+func matchesAny(filePath string, patterns []string) bool {
+	for _, pattern := range patterns {
+		var matched bool
+		switch {
+		case strings.HasSuffix(pattern, "/"):
+			matched = strings.HasPrefix(filePath, pattern)
+		case !strings.Contains(pattern, "/"):
+			matched, _ = path.Match(pattern, path.Base(filePath))
+		default:
+			matched, _ = path.Match(pattern, filePath)
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// staleCIFiles re-renders the CI configuration with the running gogo and reports
+// managed CI files whose generated content has changed since the project was created
+func staleCIFiles(ctx context.Context, manifest *Manifest) ([]string, error) {
+	tmpDir, err := os.MkdirTemp("", "gogo-status-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	config := cicd.Config{
+		Provider:          manifest.CI.Provider,
+		DependencyUpdates: manifest.CI.DependencyUpdates,
+		ProjectName:       manifest.ProjectName,
+		ModuleName:        manifest.ModuleName,
+		GoVersion:         manifest.GoVersion,
+		GogoVersion:       manifest.GogoVersion,
+		CoverageMin:       manifest.CI.CoverageMin,
+		CoverageExclude:   manifest.CI.CoverageExclude,
+		HasDatabase:       manifest.CI.HasDatabase,
+		DatabaseType:      manifest.CI.DatabaseType,
+		LicenseReport:     manifest.CI.LicenseReport,
+	}
+	if err := cicd.NewGenerator().GenerateAll(ctx, tmpDir, config); err != nil {
+		return nil, err
+	}
+
+	recorded := make(map[string]string, len(manifest.Files))
+	for _, file := range manifest.Files {
+		recorded[file.Path] = file.SHA256
+	}
+
+	var stale []string
+	err = filepath.Walk(tmpDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(tmpDir, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		sum, err := HashFile(path)
+		if err != nil {
+			return err
+		}
+		if recordedSum, ok := recorded[rel]; ok && recordedSum != sum {
+			stale = append(stale, rel)
+		}
+		return nil
+	})
+
+	return stale, err
+}
+
+// isNewerVersion reports whether current is a newer release than generated
+func isNewerVersion(generated, current string) bool {
+	if generated == "" || current == "" || current == "dev" || generated == current {
+		return false
+	}
+
+	g := versionParts(generated)
+	c := versionParts(current)
+	for i := 0; i < len(g) && i < len(c); i++ {
+		if c[i] != g[i] {
+			return c[i] > g[i]
+		}
+	}
+	return len(c) > len(g)
+}
+
+func versionParts(version string) []int {
+	version = strings.TrimPrefix(version, "v")
+	if idx := strings.IndexAny(version, "-+"); idx >= 0 {
+		version = version[:idx]
+	}
+
+	var parts []int
+	for _, part := range strings.Split(version, ".") {
+		n := 0
+		for _, r := range part {
+			if r < '0' || r > '9' {
+				break
+			}
+			n = n*10 + int(r-'0')
+		}
+		parts = append(parts, n)
+	}
+	return parts
+}
@@ -0,0 +1,193 @@
+package prompt
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/user/gogo/internal/fsutil"
+	"gopkg.in/yaml.v3"
+)
+
+// EnvPrefix starts the environment variables answering wizard questions,
+// such as GOGO_WIZARD_PROJECT_NAME. A question with an answer there, or in
+// an answers file, is not asked, with or without a terminal.
+const EnvPrefix = "GOGO_WIZARD_"
+
+// Questions of the wizard, named as their environment variables after
+// EnvPrefix and, in lower case, as the keys of an answers file
+const (
+	QuestionProjectName       = "PROJECT_NAME"
+	QuestionModule            = "MODULE"
+	QuestionTemplate          = "TEMPLATE"
+	QuestionBlueprint         = "BLUEPRINT" // "none" for none
+	QuestionAuthor            = "AUTHOR"
+	QuestionEmail             = "EMAIL"
+	QuestionLicense           = "LICENSE"
+	QuestionGoVersion         = "GO_VERSION" // "auto-detect" to detect it
+	QuestionOutputDir         = "OUTPUT_DIR"
+	QuestionGitInit           = "GIT_INIT"           // true or false
+	QuestionCI                = "CI"                 // true or false
+	QuestionCIProvider        = "CI_PROVIDER"        // github, gitlab or circleci
+	QuestionDependencyUpdates = "DEPENDENCY_UPDATES" // none, dependabot or renovate
+	QuestionCoverageMin       = "COVERAGE_MIN"       // percentage, such as 85
+	QuestionOnConflict        = "ON_CONFLICT"        // fail, skip, overwrite or backup
+	QuestionOmit              = "OMIT"               // comma-separated generated files left out, or "none"
+)
+
+// Questions lists the questions that can be answered from the environment
+var Questions = []string{
+	QuestionProjectName, QuestionModule, QuestionTemplate, QuestionBlueprint, QuestionAuthor,
+	QuestionEmail, QuestionLicense, QuestionGoVersion, QuestionOutputDir, QuestionGitInit,
+	QuestionCI, QuestionCIProvider, QuestionDependencyUpdates, QuestionCoverageMin, QuestionOnConflict,
+	QuestionOmit,
+}
+
+// IsTerminal reports whether stdin and stdout are terminals, which the
+// wizard's prompts need; in CI or with output piped they would hang
+func IsTerminal() bool {
+	for _, file := range []*os.File{os.Stdin, os.Stdout} {
+		info, err := file.Stat()
+		if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// MissingAnswersError reports the questions a wizard that cannot prompt had
+// no answer for
+type MissingAnswersError struct {
+	Missing []string // each with the flag or variable answering it
+}
+
+func (e *MissingAnswersError) Error() string {
+	return fmt.Sprintf("cannot prompt in non-interactive mode; missing %s", strings.Join(e.Missing, ", "))
+}
+
+// answer returns the answer to question from its environment variable, or
+// else the wizard's answers, where an empty answer is one
+func (w *Wizard) answer(question string) (string, bool) {
+	if value := strings.TrimSpace(os.Getenv(EnvPrefix + question)); value != "" {
+		return value, true
+	}
+	value, ok := w.answers[question]
+	return value, ok
+}
+
+// boolAnswer returns the yes or no answer to question
+func (w *Wizard) boolAnswer(question string) (value, ok bool, err error) {
+	text, ok := w.answer(question)
+	if !ok {
+		return false, false, nil
+	}
+	value, err = strconv.ParseBool(text)
+	if err != nil {
+		return false, false, answerError(question, fmt.Errorf("expected true or false, got %q", text))
+	}
+	return value, true, nil
+}
+
+// parseOmit returns the files an OMIT answer leaves out
+func parseOmit(value string) []string {
+	if value == "none" {
+		return nil
+	}
+	var omit []string
+	for _, path := range strings.Split(value, ",") {
+		omit = append(omit, strings.TrimSpace(path))
+	}
+	return omit
+}
+
+// answerError names the question of an invalid answer, as its variable
+func answerError(question string, err error) error {
+	return fmt.Errorf("invalid %s%s: %w", EnvPrefix, question, err)
+}
+
+// Answers returns the answers that replay the wizard run that chose o
+func (o *WizardOptions) Answers() map[string]string {
+	answers := map[string]string{
+		QuestionProjectName: o.ProjectName,
+		QuestionModule:      o.ModuleName,
+		QuestionTemplate:    o.Template,
+		QuestionBlueprint:   cmp.Or(o.Blueprint, "none"),
+		QuestionAuthor:      o.Author,
+		QuestionEmail:       o.Email,
+		QuestionLicense:     o.License,
+		QuestionGoVersion:   cmp.Or(o.GoVersion, "auto-detect"),
+		QuestionOutputDir:   o.OutputDir,
+		QuestionGitInit:     strconv.FormatBool(o.GitInit),
+		QuestionCI:          strconv.FormatBool(o.GenerateCI),
+		QuestionOmit:        cmp.Or(strings.Join(o.Omit, ","), "none"),
+	}
+	if o.CIProvider != "" {
+		answers[QuestionCIProvider] = o.CIProvider
+	}
+	if o.DependencyUpdates != "" {
+		answers[QuestionDependencyUpdates] = o.DependencyUpdates
+	}
+	if o.CoverageMin > 0 {
+		answers[QuestionCoverageMin] = strconv.FormatFloat(math.Round(o.CoverageMin*1000)/10, 'f', -1, 64)
+	}
+	if o.OnConflict != "" {
+		answers[QuestionOnConflict] = string(o.OnConflict)
+	}
+	return answers
+}
+
+// LoadAnswers reads the answers file at path, a YAML mapping of the
+// questions in lower case to their answers, as written by SaveAnswers
+func LoadAnswers(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read answers: %w", err)
+	}
+	var file map[string]string
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse answers %s: %w", path, err)
+	}
+	answers := make(map[string]string, len(file))
+	for key, value := range file {
+		question := strings.ToUpper(key)
+		if !slices.Contains(Questions, question) {
+			return nil, fmt.Errorf("unknown question %q in %s", key, path)
+		}
+		answers[question] = strings.TrimSpace(value)
+	}
+	return answers, nil
+}
+
+// SaveAnswers writes the answers replaying the wizard run that chose options
+// to path, in the order the wizard asks them
+func SaveAnswers(path string, options *WizardOptions) error {
+	answers := options.Answers()
+	file := &yaml.Node{Kind: yaml.MappingNode}
+	for _, question := range Questions {
+		value, ok := answers[question]
+		if !ok {
+			continue
+		}
+		file.Content = append(file.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Value: strings.ToLower(question)},
+			&yaml.Node{Kind: yaml.ScalarNode, Value: value})
+	}
+	data, err := yaml.Marshal(file)
+	if err != nil {
+		return fmt.Errorf("failed to encode answers: %w", err)
+	}
+	if err := fsutil.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write answers: %w", err)
+	}
+	return nil
+}
+
+// require records a question a wizard that cannot prompt has no answer for,
+// with the flag and variable that answer it
+func (w *Wizard) require(what, flag, question string) {
+	w.missing = append(w.missing, fmt.Sprintf("%s (%s or %s%s)", what, flag, EnvPrefix, question))
+}
@@ -0,0 +1,138 @@
+package prompt
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/user/gogo/internal/cicd"
+	"github.com/user/gogo/internal/generator"
+)
+
+func TestWizard_NonInteractiveAnswers(t *testing.T) {
+	outputDir := filepath.Join(t.TempDir(), "svc")
+	t.Setenv(EnvPrefix+QuestionProjectName, "svc")
+	t.Setenv(EnvPrefix+QuestionModule, "github.com/acme/svc")
+	t.Setenv(EnvPrefix+QuestionTemplate, "api")
+	t.Setenv(EnvPrefix+QuestionBlueprint, "none")
+	t.Setenv(EnvPrefix+QuestionAuthor, "Jane Smith")
+	t.Setenv(EnvPrefix+QuestionOutputDir, outputDir)
+	t.Setenv(EnvPrefix+QuestionGitInit, "true")
+	t.Setenv(EnvPrefix+QuestionCI, "true")
+	t.Setenv(EnvPrefix+QuestionCIProvider, "gitlab")
+	t.Setenv(EnvPrefix+QuestionCoverageMin, "85")
+	t.Setenv(EnvPrefix+QuestionOnConflict, "backup")
+
+	wizard := NewWizard()
+	wizard.SetInteractive(false)
+	options, err := wizard.RunInitWizard(context.Background(), generator.InitOptions{})
+	require.NoError(t, err)
+
+	assert.Equal(t, "svc", options.ProjectName)
+	assert.Equal(t, "github.com/acme/svc", options.ModuleName)
+	assert.Equal(t, "api", options.Template)
+	assert.Empty(t, options.Blueprint)
+	assert.Equal(t, "Jane Smith", options.Author)
+	assert.Equal(t, "MIT", options.License, "the default answers questions without a variable")
+	assert.Equal(t, outputDir, options.OutputDir)
+	assert.True(t, options.GitInit)
+	assert.True(t, options.GenerateCI)
+	assert.Equal(t, cicd.ProviderGitLab, options.CIProvider)
+	assert.Equal(t, "none", options.DependencyUpdates)
+	assert.InDelta(t, 0.85, options.CoverageMin, 0.0001)
+	assert.Equal(t, generator.ConflictBackup, options.OnConflict)
+}
+
+func TestWizard_NonInteractiveMissingAnswers(t *testing.T) {
+	wizard := NewWizard()
+	wizard.SetInteractive(false)
+	_, err := wizard.RunInitWizard(context.Background(), generator.InitOptions{})
+
+	var missing *MissingAnswersError
+	require.ErrorAs(t, err, &missing)
+	assert.Equal(t, []string{
+		"project name (the project-name argument or GOGO_WIZARD_PROJECT_NAME)",
+		"module name (--module or GOGO_WIZARD_MODULE)",
+	}, missing.Missing)
+
+	// Flags answer them as well
+	options, err := wizard.RunInitWizard(context.Background(), generator.InitOptions{
+		ProjectName: "tool",
+		ModuleName:  "github.com/acme/tool",
+		OutputDir:   filepath.Join(t.TempDir(), "tool"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "cli", options.Template)
+}
+
+func TestWizard_InvalidAnswer(t *testing.T) {
+	tests := []struct {
+		question string
+		value    string
+	}{
+		{QuestionProjectName, "not a name!"},
+		{QuestionGitInit, "maybe"},
+		{QuestionCoverageMin, "150"},
+		{QuestionOnConflict, "merge"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.question, func(t *testing.T) {
+			t.Setenv(EnvPrefix+QuestionModule, "github.com/acme/svc")
+			t.Setenv(EnvPrefix+QuestionProjectName, "svc")
+			t.Setenv(EnvPrefix+QuestionGitInit, "true")
+			t.Setenv(EnvPrefix+QuestionCI, "true")
+			t.Setenv(EnvPrefix+tt.question, tt.value)
+
+			wizard := NewWizard()
+			wizard.SetInteractive(false)
+			_, err := wizard.RunInitWizard(context.Background(), generator.InitOptions{
+				OutputDir:  filepath.Join(t.TempDir(), "svc"),
+				CIProvider: cicd.ProviderGitHub,
+			})
+			assert.ErrorContains(t, err, "invalid "+EnvPrefix+tt.question)
+		})
+	}
+}
+
+func TestSaveAnswers_Replay(t *testing.T) {
+	chosen := &WizardOptions{
+		ProjectName:       "orders",
+		ModuleName:        "github.com/acme/orders",
+		Template:          "api",
+		Blueprint:         "web-stack",
+		License:           "Apache-2.0",
+		GoVersion:         "1.24",
+		OutputDir:         filepath.Join(t.TempDir(), "orders"),
+		GitInit:           true,
+		GenerateCI:        true,
+		CIProvider:        cicd.ProviderGitHub,
+		DependencyUpdates: "renovate",
+		CoverageMin:       0.85,
+		Omit:              []string{generator.DockerfilePath},
+	}
+	path := filepath.Join(t.TempDir(), "answers.yaml")
+	require.NoError(t, SaveAnswers(path, chosen))
+
+	answers, err := LoadAnswers(path)
+	require.NoError(t, err)
+	assert.Equal(t, chosen.Answers(), answers)
+	assert.Equal(t, "85", answers[QuestionCoverageMin])
+
+	wizard := NewWizard()
+	wizard.SetInteractive(false)
+	wizard.SetAnswers(answers)
+	replayed, err := wizard.RunInitWizard(context.Background(), generator.InitOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, chosen, replayed)
+}
+
+func TestLoadAnswers_UnknownQuestion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "answers.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("project_name: svc\nframework: chi\n"), 0644))
+	_, err := LoadAnswers(path)
+	assert.ErrorContains(t, err, `unknown question "framework"`)
+}
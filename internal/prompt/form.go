@@ -0,0 +1,652 @@
+package prompt
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/user/gogo/internal/blueprints"
+	"github.com/user/gogo/internal/cicd"
+	"github.com/user/gogo/internal/db"
+	"github.com/user/gogo/internal/generator"
+	"github.com/user/gogo/internal/templates"
+	"github.com/user/gogo/internal/validate"
+)
+
+var (
+	formTitleStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("10"))
+	formLabelStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("14"))
+	focusedStyle   = lipgloss.NewStyle().Reverse(true)
+	formHelpStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+	formErrorStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("9"))
+	detailStyle    = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Padding(0, 1).MarginLeft(2).Width(48)
+)
+
+// choice is an option of a choice field
+type choice struct {
+	value  string
+	label  string
+	detail string // shown beside the form while the choice is highlighted
+}
+
+// formField is one question of the form: typed text, or one of choices
+// picked with the arrow keys
+type formField struct {
+	question string // the Question it answers
+	label    string
+	text     string
+	choices  []choice // nil for a text field
+	index    int
+	validate func(value string) error
+	err      error
+	shown    func(m *formModel) bool // nil for always
+}
+
+func (f *formField) value() string {
+	if f.choices == nil {
+		return strings.TrimSpace(f.text)
+	}
+	if len(f.choices) == 0 {
+		return ""
+	}
+	return f.choices[f.index].value
+}
+
+// choose highlights the choice of value, if there is one
+func (f *formField) choose(value string) {
+	for i, c := range f.choices {
+		if c.value == value {
+			f.index = i
+			return
+		}
+	}
+}
+
+func (f *formField) check() {
+	f.err = nil
+	if f.validate != nil {
+		f.err = f.validate(f.value())
+	}
+}
+
+// formModel is the Bubble Tea model of the wizard's full-screen form: every
+// question on one screen, then a confirmation screen with the files that
+// will be generated, where optional pieces can be switched on or off
+type formModel struct {
+	ctx     context.Context
+	wizard  *Wizard
+	initial *WizardOptions
+	height  int
+
+	fields []*formField
+	focus  int
+
+	// The module path and output directory follow the project name until
+	// they are edited
+	moduleEdited, dirEdited bool
+
+	confirming bool
+	result     *WizardOptions // the options being confirmed
+	preview    *generator.Preview
+	previewErr error
+	pieces     []optionalPiece
+
+	done, cancelled bool
+}
+
+// runForm asks the wizard's questions in a full-screen form
+func (w *Wizard) runForm(ctx context.Context, options *WizardOptions) (*WizardOptions, error) {
+	m, err := w.newForm(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tea.NewProgram(m, tea.WithAltScreen(), tea.WithContext(ctx)).Run(); err != nil {
+		return nil, fmt.Errorf("wizard form failed: %w", err)
+	}
+	if !m.done {
+		return nil, fmt.Errorf("project creation cancelled by user")
+	}
+	return m.result, nil
+}
+
+// newForm builds the form's fields, filled in from options, then answers
+// and defaults
+func (w *Wizard) newForm(ctx context.Context, options *WizardOptions) (*formModel, error) {
+	m := &formModel{ctx: ctx, wizard: w, initial: options, height: 24}
+	if value, ok := w.answer(QuestionOmit); ok {
+		options.Omit = parseOmit(value)
+	}
+	initial := func(value, question string) string {
+		if value != "" {
+			return value
+		}
+		answer, _ := w.answer(question)
+		return answer
+	}
+
+	name := &formField{
+		question: QuestionProjectName,
+		label:    "Project name",
+		text:     initial(options.ProjectName, QuestionProjectName),
+		validate: func(value string) error {
+			if value == "" {
+				return fmt.Errorf("project name cannot be empty")
+			}
+			return validate.ValidateProjectName(value)
+		},
+	}
+	module := &formField{
+		question: QuestionModule,
+		label:    "Go module",
+		text:     initial(options.ModuleName, QuestionModule),
+		validate: func(value string) error {
+			if value == "" {
+				return fmt.Errorf("module name cannot be empty")
+			}
+			return validate.ValidateModuleName(value)
+		},
+	}
+	m.moduleEdited = module.text != ""
+
+	template, err := w.templateField(ctx, initial(options.Template, QuestionTemplate))
+	if err != nil {
+		return nil, err
+	}
+	blueprint := &formField{
+		question: QuestionBlueprint,
+		label:    "Blueprint",
+		shown: func(m *formModel) bool {
+			return len(m.field(QuestionBlueprint).choices) > 1
+		},
+	}
+
+	author := &formField{question: QuestionAuthor, label: "Author", text: initial(options.Author, QuestionAuthor)}
+	if author.text == "" {
+		author.text = w.getGitUserName()
+	}
+	email := &formField{question: QuestionEmail, label: "Email", text: initial(options.Email, QuestionEmail)}
+	if email.text == "" {
+		email.text = w.getGitUserEmail()
+	}
+
+	license := &formField{question: QuestionLicense, label: "License"}
+	for _, name := range []string{"MIT", "Apache-2.0", "GPL-3.0", "BSD-3-Clause", "ISC", "Other"} {
+		license.choices = append(license.choices, choice{value: name, label: name})
+	}
+	if value := initial(options.License, QuestionLicense); value != "" {
+		if !slices.ContainsFunc(license.choices, func(c choice) bool { return c.value == value }) {
+			license.choices = append(license.choices, choice{value: value, label: value})
+		}
+		license.choose(value)
+	}
+
+	goVersion := &formField{question: QuestionGoVersion, label: "Go version"}
+	for _, version := range []string{"1.25.1", "1.25", "auto-detect", "1.24", "1.23"} {
+		value := version
+		if version == "auto-detect" {
+			value = ""
+		}
+		goVersion.choices = append(goVersion.choices, choice{value: value, label: version})
+	}
+	if value := initial(options.GoVersion, QuestionGoVersion); value != "" {
+		if value == "auto-detect" {
+			value = ""
+		}
+		if !slices.ContainsFunc(goVersion.choices, func(c choice) bool { return c.value == value }) {
+			goVersion.choices = append(goVersion.choices, choice{value: value, label: value})
+		}
+		goVersion.choose(value)
+	}
+
+	dir := options.OutputDir
+	if dir == "." {
+		dir = ""
+	}
+	outputDir := &formField{
+		question: QuestionOutputDir,
+		label:    "Output directory",
+		text:     initial(dir, QuestionOutputDir),
+		validate: func(value string) error {
+			if value == "" {
+				return fmt.Errorf("output directory cannot be empty")
+			}
+			return nil
+		},
+	}
+	m.dirEdited = outputDir.text != ""
+
+	gitInit := yesNoField(QuestionGitInit, "Initialize git", w)
+	ci := yesNoField(QuestionCI, "Generate CI/CD", w)
+	ci.shown = func(m *formModel) bool { return m.yes(QuestionGitInit) }
+	withCI := func(m *formModel) bool { return m.yes(QuestionGitInit) && m.yes(QuestionCI) }
+
+	provider := &formField{question: QuestionCIProvider, label: "CI provider", shown: withCI}
+	for _, p := range cicd.Providers {
+		provider.choices = append(provider.choices, choice{value: p, label: cicd.ProviderName(p)})
+	}
+	provider.choose(initial(options.CIProvider, QuestionCIProvider))
+
+	updates := &formField{question: QuestionDependencyUpdates, label: "Dependency updates", shown: withCI,
+		choices: []choice{{value: "none", label: "None"}}}
+	for _, bot := range cicd.UpdateBots {
+		updates.choices = append(updates.choices, choice{value: bot, label: cicd.UpdateBotName(bot)})
+	}
+	updates.choose(initial(options.DependencyUpdates, QuestionDependencyUpdates))
+
+	coverage := &formField{question: QuestionCoverageMin, label: "Coverage minimum %", text: "80", shown: withCI,
+		validate: w.validateCoveragePercentage}
+	if options.CoverageMin > 0 {
+		coverage.text = strconv.FormatFloat(options.CoverageMin*100, 'f', -1, 64)
+	} else if answer, ok := w.answer(QuestionCoverageMin); ok {
+		coverage.text = answer
+	}
+
+	conflict := &formField{question: QuestionOnConflict, label: "Existing files", shown: func(m *formModel) bool {
+		entries, err := os.ReadDir(m.field(QuestionOutputDir).value())
+		return err == nil && len(entries) > 0
+	}}
+	labels := []string{"Stop if any would change", "Keep them", "Overwrite them", "Overwrite, keeping " + generator.BackupSuffix + " copies"}
+	for i, policy := range generator.ConflictPolicies {
+		conflict.choices = append(conflict.choices, choice{value: string(policy), label: labels[i]})
+	}
+	conflict.choose(initial(string(options.OnConflict), QuestionOnConflict))
+
+	m.fields = []*formField{name, module, template, blueprint, author, email, license, goVersion,
+		outputDir, gitInit, ci, provider, updates, coverage, conflict}
+	m.followName()
+	m.refreshBlueprints(initial(options.Blueprint, QuestionBlueprint))
+	for _, field := range m.fields {
+		if field.text != "" {
+			field.check()
+		}
+	}
+	return m, nil
+}
+
+// yesNoField is a choice between yes, the default as in the prompts, and no
+func yesNoField(question, label string, w *Wizard) *formField {
+	field := &formField{question: question, label: label, choices: []choice{{value: "true", label: "Yes"}, {value: "false", label: "No"}}}
+	if value, ok, _ := w.boolAnswer(question); ok && !value {
+		field.index = 1
+	}
+	return field
+}
+
+// templateField offers the templates carrying the required tags, with the
+// files each generates
+func (w *Wizard) templateField(ctx context.Context, selected string) (*formField, error) {
+	available, err := w.templateChoices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	field := &formField{question: QuestionTemplate, label: "Template"}
+	for _, tmpl := range available {
+		if !db.HasAllTags(w.entryTags[db.KindTemplate][tmpl.Kind], w.requiredTags) {
+			continue
+		}
+		field.choices = append(field.choices, choice{
+			value:  tmpl.Kind,
+			label:  w.choiceLabel(db.KindTemplate, tmpl.Kind, tmpl.Kind),
+			detail: w.templateDetail(ctx, tmpl),
+		})
+	}
+	if len(field.choices) == 0 {
+		return nil, fmt.Errorf("no templates tagged %s", strings.Join(w.requiredTags, ", "))
+	}
+	field.choose(cmp.Or(selected, "cli"))
+	return field, nil
+}
+
+func (w *Wizard) templateDetail(ctx context.Context, tmpl templates.Template) string {
+	detail := formTitleStyle.Render(tmpl.Name)
+	files, err := w.templateRepo.GetTemplateFiles(ctx, tmpl.Kind)
+	if err != nil || len(files) == 0 {
+		return detail
+	}
+	detail += "\n\nFiles:"
+	for _, file := range files {
+		detail += "\n  " + file.Path
+	}
+	return detail
+}
+
+func blueprintDetail(bp blueprints.Blueprint) string {
+	detail := formTitleStyle.Render(bp.Name) + fmt.Sprintf(" (%s stack)", bp.Stack)
+	if bp.Description != "" {
+		detail += "\n\n" + bp.Description
+	}
+	if len(bp.Config.Components) > 0 {
+		detail += "\n\nComponents:"
+		for _, component := range bp.Config.Components {
+			detail += "\n  " + component
+		}
+	}
+	return detail
+}
+
+// refreshBlueprints offers the blueprints suited to the selected template,
+// keeping the selected one, or selecting selected, when still offered
+func (m *formModel) refreshBlueprints(selected string) {
+	field := m.field(QuestionBlueprint)
+	if selected == "" {
+		selected = field.value()
+	}
+	field.choices = []choice{{value: "", label: "None", detail: "The template alone, without a stack of components"}}
+	field.index = 0
+
+	template := m.field(QuestionTemplate).value()
+	if !m.wizard.shouldPromptBlueprint(template) {
+		return
+	}
+	available, err := m.wizard.blueprintRepo.ListBlueprints(m.ctx)
+	if err != nil {
+		return
+	}
+	for _, bp := range available {
+		if m.wizard.isBlueprintSuitableForTemplate(bp, template) &&
+			db.HasAllTags(m.wizard.entryTags[db.KindBlueprint][bp.Name], m.wizard.requiredTags) {
+			field.choices = append(field.choices, choice{
+				value:  bp.Name,
+				label:  m.wizard.choiceLabel(db.KindBlueprint, bp.Name, bp.Name),
+				detail: blueprintDetail(bp),
+			})
+		}
+	}
+	if selected != "none" {
+		field.choose(selected)
+	}
+}
+
+// followName derives the module path and output directory from the project
+// name until they are edited
+func (m *formModel) followName() {
+	name := m.field(QuestionProjectName).value()
+	if name == "" {
+		return
+	}
+	if !m.moduleEdited {
+		module := m.field(QuestionModule)
+		module.text = "github.com/user/" + name
+		module.check()
+	}
+	if !m.dirEdited {
+		dir := m.field(QuestionOutputDir)
+		dir.text = name
+		dir.check()
+	}
+}
+
+func (m *formModel) field(question string) *formField {
+	for _, field := range m.fields {
+		if field.question == question {
+			return field
+		}
+	}
+	panic("no form field for " + question)
+}
+
+func (m *formModel) yes(question string) bool {
+	return m.field(question).value() == "true"
+}
+
+func (m *formModel) visible() []*formField {
+	var fields []*formField
+	for _, field := range m.fields {
+		if field.shown == nil || field.shown(m) {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// focused returns the focused field, moving the focus onto a visible one
+// when fields were hidden
+func (m *formModel) focused() *formField {
+	visible := m.visible()
+	m.focus = min(m.focus, len(visible)-1)
+	return visible[m.focus]
+}
+
+// Init implements tea.Model
+func (m *formModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update implements tea.Model
+func (m *formModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.height = msg.Height
+	case tea.KeyMsg:
+		if msg.Type == tea.KeyCtrlC {
+			m.cancelled = true
+			return m, tea.Quit
+		}
+		if m.confirming {
+			return m, m.confirmKey(msg)
+		}
+		return m, m.formKey(msg)
+	}
+	return m, nil
+}
+
+func (m *formModel) formKey(msg tea.KeyMsg) tea.Cmd {
+	field := m.focused()
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.cancelled = true
+		return tea.Quit
+	case tea.KeyTab, tea.KeyDown:
+		m.focus = min(m.focus+1, len(m.visible())-1)
+	case tea.KeyShiftTab, tea.KeyUp:
+		m.focus = max(m.focus-1, 0)
+	case tea.KeyEnter:
+		if m.focus < len(m.visible())-1 {
+			m.focus++
+		} else {
+			m.submit()
+		}
+	case tea.KeyCtrlS:
+		m.submit()
+	case tea.KeyLeft, tea.KeyRight:
+		if len(field.choices) > 0 {
+			step := 1
+			if msg.Type == tea.KeyLeft {
+				step = len(field.choices) - 1
+			}
+			field.index = (field.index + step) % len(field.choices)
+			if field.question == QuestionTemplate {
+				m.refreshBlueprints("")
+			}
+		}
+	case tea.KeyBackspace:
+		if runes := []rune(field.text); field.choices == nil && len(runes) > 0 {
+			m.edit(field, string(runes[:len(runes)-1]))
+		}
+	case tea.KeySpace:
+		if field.choices == nil {
+			m.edit(field, field.text+" ")
+		}
+	case tea.KeyRunes:
+		if field.choices == nil {
+			m.edit(field, field.text+string(msg.Runes))
+		}
+	}
+	return nil
+}
+
+// edit changes the text of field and validates it as it is typed
+func (m *formModel) edit(field *formField, text string) {
+	field.text = text
+	field.check()
+	switch field.question {
+	case QuestionProjectName:
+		m.followName()
+	case QuestionModule:
+		m.moduleEdited = true
+	case QuestionOutputDir:
+		m.dirEdited = true
+	}
+}
+
+// submit validates the visible fields and moves to the confirmation screen,
+// or to the first field in error
+func (m *formModel) submit() {
+	for i, field := range m.visible() {
+		field.check()
+		if field.err != nil {
+			m.focus = i
+			return
+		}
+	}
+	m.result = m.options()
+	m.confirming = true
+	m.refreshPreview()
+}
+
+func (m *formModel) refreshPreview() {
+	m.preview, m.previewErr = nil, nil
+	if m.wizard.previewer != nil {
+		m.preview, m.previewErr = m.wizard.preview(m.ctx, m.result)
+	}
+	m.pieces = optionalPieces(m.result, m.preview)
+}
+
+func (m *formModel) confirmKey(msg tea.KeyMsg) tea.Cmd {
+	switch key := msg.String(); key {
+	case "enter", "y":
+		m.done = true
+		return tea.Quit
+	case "esc", "n":
+		// Toggled pieces carry over to the form
+		m.initial.Omit = m.result.Omit
+		m.field(QuestionGitInit).choose(strconv.FormatBool(m.result.GitInit))
+		m.field(QuestionCI).choose(strconv.FormatBool(m.result.GenerateCI))
+		m.confirming = false
+	default:
+		if i, err := strconv.Atoi(key); err == nil && i >= 1 && i <= len(m.pieces) {
+			m.pieces[i-1].toggle(m.result)
+			if m.pieces[i-1].name == "CI/CD" {
+				m.result.GitInit = m.result.GitInit || m.result.GenerateCI
+			}
+			m.refreshPreview()
+		}
+	}
+	return nil
+}
+
+// options returns the answers of the form as wizard options
+func (m *formModel) options() *WizardOptions {
+	options := *m.initial
+	options.Omit = slices.Clone(m.initial.Omit)
+	options.ProjectName = m.field(QuestionProjectName).value()
+	options.ModuleName = m.field(QuestionModule).value()
+	options.Template = m.field(QuestionTemplate).value()
+	options.Blueprint = ""
+	if field := m.field(QuestionBlueprint); field.shown(m) {
+		options.Blueprint = field.value()
+	}
+	options.Author = m.field(QuestionAuthor).value()
+	options.Email = m.field(QuestionEmail).value()
+	options.License = m.field(QuestionLicense).value()
+	options.GoVersion = m.field(QuestionGoVersion).value()
+	options.OutputDir = m.field(QuestionOutputDir).value()
+	options.GitInit = m.yes(QuestionGitInit)
+	options.GenerateCI = options.GitInit && m.yes(QuestionCI)
+	if options.GenerateCI {
+		options.CIProvider = m.field(QuestionCIProvider).value()
+		options.DependencyUpdates = m.field(QuestionDependencyUpdates).value()
+		if percentage, err := strconv.ParseFloat(m.field(QuestionCoverageMin).value(), 64); err == nil {
+			options.CoverageMin = percentage / 100
+		}
+	}
+	if field := m.field(QuestionOnConflict); field.shown(m) {
+		options.OnConflict = generator.ConflictPolicy(field.value())
+		options.Force = options.OnConflict == generator.ConflictOverwrite
+	}
+	return &options
+}
+
+// View implements tea.Model
+func (m *formModel) View() string {
+	if m.confirming {
+		return m.confirmView()
+	}
+
+	focused := m.focused()
+	var b strings.Builder
+	for i, field := range m.visible() {
+		value := field.text
+		if field.choices != nil {
+			value = field.choices[field.index].label
+			if field == focused {
+				value = "‹ " + value + " ›"
+			}
+		} else if field == focused {
+			value += "█"
+		}
+		if field == focused {
+			value = focusedStyle.Render(value)
+		}
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		b.WriteString(formLabelStyle.Render(fmt.Sprintf("%-20s", field.label)) + value)
+		if field.err != nil {
+			b.WriteString("\n" + strings.Repeat(" ", 20) + formErrorStyle.Render(field.err.Error()))
+		}
+	}
+
+	form := b.String()
+	if focused.choices != nil && focused.choices[focused.index].detail != "" {
+		form = lipgloss.JoinHorizontal(lipgloss.Top, form, detailStyle.Render(focused.choices[focused.index].detail))
+	}
+	help := "↑/↓ tab move • ←/→ choose • enter next • ctrl+s review • esc cancel"
+	return formTitleStyle.Render("New Go project") + "\n\n" + form + "\n\n" + formHelpStyle.Render(help)
+}
+
+func (m *formModel) confirmView() string {
+	var b strings.Builder
+	b.WriteString(formTitleStyle.Render("Create this project?") + "\n\n")
+	for _, line := range m.wizard.summary(m.result) {
+		b.WriteString("  " + line + "\n")
+	}
+
+	if m.result.Blueprint != "" {
+		if bp, err := m.wizard.blueprintRepo.GetBlueprint(m.ctx, m.result.Blueprint); err == nil && len(bp.Config.Components) > 0 {
+			b.WriteString("\n" + formLabelStyle.Render("Components:") + "\n")
+			for _, component := range bp.Config.Components {
+				b.WriteString("  - " + component + "\n")
+			}
+		}
+	}
+
+	switch {
+	case m.previewErr != nil:
+		b.WriteString("\n" + formErrorStyle.Render("Could not preview the files: "+m.previewErr.Error()) + "\n")
+	case m.preview != nil:
+		tree := strings.Split(strings.TrimRight(m.preview.FileTree(filepath.Base(m.result.OutputDir)), "\n"), "\n")
+		// Leave room for the summary, the pieces and the help
+		if room := max(m.height-len(m.wizard.summary(m.result))-len(m.pieces)-10, 3); len(tree) > room {
+			tree = append(tree[:room], fmt.Sprintf("… %d more", len(tree)-room))
+		}
+		b.WriteString("\n" + formLabelStyle.Render(fmt.Sprintf("Files (%d):", len(m.preview.Files))) + "\n")
+		b.WriteString(strings.Join(tree, "\n") + "\n")
+	}
+
+	if len(m.pieces) > 0 {
+		b.WriteString("\n")
+		for i, piece := range m.pieces {
+			b.WriteString(fmt.Sprintf("  %d  %s\n", i+1, piece.label(m.result)))
+		}
+	}
+	help := "enter create • 1-9 toggle a piece • esc back to the form • ctrl+c cancel"
+	return b.String() + "\n" + formHelpStyle.Render(help)
+}
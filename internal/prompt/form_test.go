@@ -0,0 +1,149 @@
+package prompt
+
+import (
+	"context"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/user/gogo/internal/generator"
+)
+
+func newTestForm(t *testing.T, options *WizardOptions) *formModel {
+	t.Helper()
+	m, err := NewWizard().newForm(context.Background(), options)
+	require.NoError(t, err)
+	return m
+}
+
+func pressKeys(m *formModel, keys ...tea.KeyType) {
+	for _, key := range keys {
+		m.Update(tea.KeyMsg{Type: key})
+	}
+}
+
+func typeText(m *formModel, text string) {
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(text)})
+}
+
+// focusOn moves the focus down to the field of question
+func focusOn(t *testing.T, m *formModel, question string) {
+	t.Helper()
+	m.focus = 0
+	for m.focused().question != question {
+		before := m.focus
+		pressKeys(m, tea.KeyTab)
+		require.NotEqual(t, before, m.focus, "%s is not shown", question)
+	}
+}
+
+func TestForm_FillAndConfirm(t *testing.T) {
+	m := newTestForm(t, &WizardOptions{})
+	assert.Equal(t, QuestionProjectName, m.focused().question)
+
+	// The answer is checked as it is typed
+	typeText(m, "my svc")
+	assert.Error(t, m.field(QuestionProjectName).err)
+	pressKeys(m, tea.KeyBackspace, tea.KeyBackspace, tea.KeyBackspace, tea.KeyBackspace)
+	typeText(m, "-svc")
+	assert.NoError(t, m.field(QuestionProjectName).err)
+	assert.Equal(t, "github.com/user/my-svc", m.field(QuestionModule).value(), "the module follows the name")
+	assert.Equal(t, "my-svc", m.field(QuestionOutputDir).value())
+
+	// Once edited, the module no longer follows
+	pressKeys(m, tea.KeyTab)
+	pressKeys(m, tea.KeyBackspace, tea.KeyBackspace, tea.KeyBackspace, tea.KeyBackspace, tea.KeyBackspace, tea.KeyBackspace)
+	typeText(m, "orders")
+	focusOn(t, m, QuestionProjectName)
+	typeText(m, "x")
+	assert.Equal(t, "github.com/user/orders", m.field(QuestionModule).value())
+	assert.Equal(t, "my-svcx", m.field(QuestionOutputDir).value())
+
+	// Hidden questions are skipped
+	focusOn(t, m, QuestionGitInit)
+	pressKeys(m, tea.KeyRight)
+	assert.False(t, m.yes(QuestionGitInit))
+	pressKeys(m, tea.KeyTab)
+	assert.NotEqual(t, QuestionCI, m.focused().question)
+
+	pressKeys(m, tea.KeyCtrlS)
+	require.True(t, m.confirming)
+	assert.Contains(t, m.View(), "Create this project?")
+
+	// Back to the form, and then create
+	pressKeys(m, tea.KeyEsc)
+	assert.False(t, m.confirming)
+	pressKeys(m, tea.KeyCtrlS, tea.KeyEnter)
+	assert.True(t, m.done)
+	assert.Equal(t, "my-svcx", m.result.ProjectName)
+	assert.Equal(t, "github.com/user/orders", m.result.ModuleName)
+	assert.Equal(t, "cli", m.result.Template)
+	assert.Equal(t, "MIT", m.result.License)
+	assert.False(t, m.result.GitInit)
+	assert.False(t, m.result.GenerateCI)
+}
+
+func TestForm_SubmitFocusesInvalidField(t *testing.T) {
+	m := newTestForm(t, &WizardOptions{ProjectName: "svc"})
+	focusOn(t, m, QuestionModule)
+	for range m.field(QuestionModule).text {
+		pressKeys(m, tea.KeyBackspace)
+	}
+	focusOn(t, m, QuestionLicense)
+
+	pressKeys(m, tea.KeyCtrlS)
+	assert.False(t, m.confirming)
+	assert.Equal(t, QuestionModule, m.focused().question)
+	assert.Contains(t, m.View(), m.field(QuestionModule).err.Error())
+}
+
+func TestForm_TemplatePreview(t *testing.T) {
+	m := newTestForm(t, &WizardOptions{ProjectName: "svc", Template: "api"})
+	focusOn(t, m, QuestionTemplate)
+	assert.Contains(t, m.View(), "Files:", "the highlighted template's files are shown")
+
+	// The blueprints follow the template
+	focusOn(t, m, QuestionBlueprint)
+	assert.Greater(t, len(m.field(QuestionBlueprint).choices), 1)
+	pressKeys(m, tea.KeyRight)
+	assert.Contains(t, m.View(), "Components:")
+	blueprint := m.field(QuestionBlueprint).value()
+
+	focusOn(t, m, QuestionTemplate)
+	m.field(QuestionTemplate).choose("library")
+	m.refreshBlueprints("")
+	assert.Empty(t, m.field(QuestionBlueprint).value())
+	assert.Empty(t, m.options().Blueprint)
+
+	m.field(QuestionTemplate).choose("api")
+	m.refreshBlueprints(blueprint)
+	assert.Equal(t, blueprint, m.options().Blueprint)
+}
+
+func TestForm_Answers(t *testing.T) {
+	t.Setenv(EnvPrefix+QuestionCIProvider, "gitlab")
+	wizard := NewWizard()
+	wizard.SetAnswers(map[string]string{
+		QuestionModule:      "github.com/acme/svc",
+		QuestionGitInit:     "true",
+		QuestionCoverageMin: "85",
+		QuestionOnConflict:  string(generator.ConflictBackup),
+	})
+	m, err := wizard.newForm(context.Background(), &WizardOptions{ProjectName: "svc"})
+	require.NoError(t, err)
+
+	options := m.options()
+	assert.Equal(t, "github.com/acme/svc", options.ModuleName)
+	assert.True(t, options.GenerateCI)
+	assert.Equal(t, "gitlab", options.CIProvider)
+	assert.InDelta(t, 0.85, options.CoverageMin, 0.0001)
+	assert.Empty(t, options.OnConflict, "the output directory is empty")
+}
+
+func TestForm_Cancel(t *testing.T) {
+	m := newTestForm(t, &WizardOptions{})
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	assert.True(t, m.cancelled)
+	assert.NotNil(t, cmd)
+}
@@ -4,10 +4,15 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 
 	"github.com/fatih/color"
 	"github.com/manifoldco/promptui"
 	"github.com/user/gogo/internal/blueprints"
+	"github.com/user/gogo/internal/cicd"
+	"github.com/user/gogo/internal/db"
 	"github.com/user/gogo/internal/generator"
 	"github.com/user/gogo/internal/templates"
 	"github.com/user/gogo/internal/validate"
@@ -26,15 +31,28 @@ type WizardOptions struct {
 	OutputDir            string
 	GitInit              bool
 	GenerateCI           bool
+	CIProvider           string
+	DependencyUpdates    string // dependency update bot, or none
 	CoverageMin          float64
 	InitialCommitMessage string
 	Force                bool
+	OnConflict           generator.ConflictPolicy
+	Omit                 []string // optional generated files left out, such as generator.DockerfilePath
 }
 
 // Wizard provides interactive prompts for project initialization
 type Wizard struct {
-	templateRepo  *templates.Repository
-	blueprintRepo *blueprints.Repository
+	templateRepo    *templates.Repository
+	blueprintRepo   *blueprints.Repository
+	storedTemplates []*db.StoredTemplate
+	entryTags       map[string]map[string][]string // kind -> entry name -> tags
+	annotations     map[string]map[string]db.Annotation
+	requiredTags    []string
+	previewer       generator.ProjectGenerator
+	interactive     bool
+	form            bool              // ask in the full-screen form rather than prompt by prompt
+	answers         map[string]string // question -> answer, from an answers file
+	missing         []string          // questions left without an answer when not interactive
 }
 
 // NewWizard creates a new wizard instance
@@ -42,25 +60,102 @@ func NewWizard() *Wizard {
 	return &Wizard{
 		templateRepo:  templates.NewRepository(),
 		blueprintRepo: blueprints.NewRepository(),
+		interactive:   true,
+		form:          os.Getenv("TERM") != "dumb",
 	}
 }
 
+// SetAnswers answers the wizard's questions, keyed by Question, such as
+// those of an answers file read by LoadAnswers. GOGO_WIZARD_* variables take
+// precedence over them.
+func (w *Wizard) SetAnswers(answers map[string]string) {
+	w.answers = answers
+}
+
+// SetInteractive sets whether the wizard prompts. Without prompts, questions
+// are answered from the GOGO_WIZARD_* variables, then the options given and
+// defaults; RunInitWizard fails listing those that have no answer.
+func (w *Wizard) SetInteractive(interactive bool) {
+	w.interactive = interactive
+}
+
+// SetForm sets whether an interactive wizard asks its questions in a
+// full-screen form, with a confirmation screen, or one prompt after another.
+// The form is used unless TERM is dumb.
+func (w *Wizard) SetForm(form bool) {
+	w.form = form
+}
+
+// SetStoredTemplates offers templates added with gogo template add or
+// install alongside the built-in ones, replacing built-ins of the same name
+func (w *Wizard) SetStoredTemplates(stored []*db.StoredTemplate) {
+	w.storedTemplates = stored
+}
+
+// SetBlueprintRepository sets the repository blueprints are offered from,
+// such as one holding installed blueprints
+func (w *Wizard) SetBlueprintRepository(repo *blueprints.Repository) {
+	w.blueprintRepo = repo
+}
+
+// SetTags supplies the tags of templates or blueprints (db.KindTemplate or
+// db.KindBlueprint), shown next to each choice and matched when filtering
+func (w *Wizard) SetTags(kind string, tagged map[string][]string) {
+	if w.entryTags == nil {
+		w.entryTags = make(map[string]map[string][]string)
+	}
+	w.entryTags[kind] = tagged
+}
+
+// SetAnnotations supplies local ratings of templates or blueprints, shown next to each choice
+func (w *Wizard) SetAnnotations(kind string, annotations map[string]db.Annotation) {
+	if w.annotations == nil {
+		w.annotations = make(map[string]map[string]db.Annotation)
+	}
+	w.annotations[kind] = annotations
+}
+
+// SetRequiredTags limits template and blueprint choices to entries carrying every tag
+func (w *Wizard) SetRequiredTags(tags []string) {
+	w.requiredTags = tags
+}
+
+// SetPreviewer sets the generator the review step renders the file tree
+// with; without one the review shows no files
+func (w *Wizard) SetPreviewer(gen generator.ProjectGenerator) {
+	w.previewer = gen
+}
+
 // RunInitWizard runs the interactive wizard for project initialization
 func (w *Wizard) RunInitWizard(ctx context.Context, initialOptions generator.InitOptions) (*WizardOptions, error) {
-	color.Cyan("Welcome to gogo project initialization wizard!")
+	if w.interactive {
+		color.Cyan("Welcome to gogo project initialization wizard!")
+	} else {
+		color.Cyan("Answering the wizard from flags and %s* variables", EnvPrefix)
+	}
 	fmt.Println()
+	w.missing = nil
 
 	options := &WizardOptions{
-		ProjectName: initialOptions.ProjectName,
-		ModuleName:  initialOptions.ModuleName,
-		Template:    initialOptions.Template,
-		Blueprint:   initialOptions.Blueprint,
-		Author:      initialOptions.Author,
-		License:     initialOptions.License,
-		GoVersion:   initialOptions.GoVersion,
-		OutputDir:   initialOptions.OutputDir,
-		GitInit:     initialOptions.GitInit,
-		Force:       initialOptions.Force,
+		ProjectName:       initialOptions.ProjectName,
+		ModuleName:        initialOptions.ModuleName,
+		Template:          initialOptions.Template,
+		Blueprint:         initialOptions.Blueprint,
+		Author:            initialOptions.Author,
+		Email:             initialOptions.Email,
+		License:           initialOptions.License,
+		GoVersion:         initialOptions.GoVersion,
+		OutputDir:         initialOptions.OutputDir,
+		GitInit:           initialOptions.GitInit,
+		GenerateCI:        initialOptions.GenerateCI,
+		CIProvider:        initialOptions.CIProvider,
+		DependencyUpdates: initialOptions.DependencyUpdates,
+		CoverageMin:       initialOptions.CoverageMin,
+		Force:             initialOptions.Force,
+		Omit:              initialOptions.Omit,
+	}
+	if w.interactive && w.form {
+		return w.runForm(ctx, options)
 	}
 
 	// Project name
@@ -135,8 +230,18 @@ func (w *Wizard) RunInitWizard(ctx context.Context, initialOptions generator.Ini
 			return nil, err
 		}
 
-		// Coverage minimum (if CI/CD is enabled)
+		// CI provider and coverage minimum (if CI/CD is enabled)
 		if options.GenerateCI {
+			if options.CIProvider == "" {
+				if err := w.promptCIProvider(options); err != nil {
+					return nil, err
+				}
+			}
+			if options.DependencyUpdates == "" {
+				if err := w.promptDependencyUpdates(options); err != nil {
+					return nil, err
+				}
+			}
 			if err := w.promptCoverageMin(options); err != nil {
 				return nil, err
 			}
@@ -147,12 +252,12 @@ func (w *Wizard) RunInitWizard(ctx context.Context, initialOptions generator.Ini
 	if err := w.promptForce(options); err != nil {
 		return nil, err
 	}
+	if len(w.missing) > 0 {
+		return nil, &MissingAnswersError{Missing: w.missing}
+	}
 
-	// Summary
-	w.showSummary(options)
-
-	// Confirmation
-	if err := w.promptConfirmation(); err != nil {
+	// Review the components and files, toggling optional pieces, until confirmed
+	if err := w.review(ctx, options); err != nil {
 		return nil, err
 	}
 
@@ -160,6 +265,18 @@ func (w *Wizard) RunInitWizard(ctx context.Context, initialOptions generator.Ini
 }
 
 func (w *Wizard) promptProjectName(options *WizardOptions) error {
+	if value, ok := w.answer(QuestionProjectName); ok {
+		if err := validate.ValidateProjectName(value); err != nil {
+			return answerError(QuestionProjectName, err)
+		}
+		options.ProjectName = value
+		return nil
+	}
+	if !w.interactive {
+		w.require("project name", "the project-name argument", QuestionProjectName)
+		return nil
+	}
+
 	validate := func(input string) error {
 		if input == "" {
 			return fmt.Errorf("project name cannot be empty")
@@ -182,6 +299,18 @@ func (w *Wizard) promptProjectName(options *WizardOptions) error {
 }
 
 func (w *Wizard) promptModuleName(options *WizardOptions) error {
+	if value, ok := w.answer(QuestionModule); ok {
+		if err := validate.ValidateModuleName(value); err != nil {
+			return answerError(QuestionModule, err)
+		}
+		options.ModuleName = value
+		return nil
+	}
+	if !w.interactive {
+		w.require("module name", "--module", QuestionModule)
+		return nil
+	}
+
 	// Suggest a module name based on project name
 	var defaultModule string
 	if options.ProjectName != "" {
@@ -211,19 +340,44 @@ func (w *Wizard) promptModuleName(options *WizardOptions) error {
 }
 
 func (w *Wizard) promptTemplate(ctx context.Context, options *WizardOptions) error {
-	templates, err := w.templateRepo.ListPredefinedTemplates(ctx)
+	available, err := w.templateChoices(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to list templates: %w", err)
+		return err
+	}
+	if value, ok := w.answer(QuestionTemplate); ok {
+		for _, tmpl := range available {
+			if tmpl.Kind == value {
+				options.Template = value
+				return nil
+			}
+		}
+		return answerError(QuestionTemplate, fmt.Errorf("unknown template %q", value))
+	}
+	if !w.interactive {
+		if options.Template == "" {
+			options.Template = "cli"
+		}
+		return nil
 	}
 
-	items := make([]string, len(templates))
-	for i, tmpl := range templates {
-		items[i] = fmt.Sprintf("%s - %s", tmpl.Name, tmpl.Kind)
+	var choices []templates.Template
+	var items []string
+	for _, tmpl := range available {
+		tags := w.entryTags[db.KindTemplate][tmpl.Kind]
+		if !db.HasAllTags(tags, w.requiredTags) {
+			continue
+		}
+		choices = append(choices, tmpl)
+		items = append(items, w.choiceLabel(db.KindTemplate, tmpl.Kind, fmt.Sprintf("%s - %s", tmpl.Name, tmpl.Kind)))
+	}
+	if len(choices) == 0 {
+		return fmt.Errorf("no templates tagged %s", strings.Join(w.requiredTags, ", "))
 	}
 
 	prompt := promptui.Select{
-		Label: "Select project template",
-		Items: items,
+		Label:    "Select project template (type / to filter by name or tag)",
+		Items:    items,
+		Searcher: itemSearcher(items),
 	}
 
 	i, _, err := prompt.Run()
@@ -231,10 +385,42 @@ func (w *Wizard) promptTemplate(ctx context.Context, options *WizardOptions) err
 		return fmt.Errorf("template selection failed: %w", err)
 	}
 
-	options.Template = templates[i].Kind
+	options.Template = choices[i].Kind
 	return nil
 }
 
+// templateChoices lists the built-in templates followed by stored ones. Name
+// holds the label and Kind the template name, as for built-in templates.
+func (w *Wizard) templateChoices(ctx context.Context) ([]templates.Template, error) {
+	predefined, err := w.templateRepo.ListPredefinedTemplates(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list templates: %w", err)
+	}
+
+	stored := make(map[string]bool, len(w.storedTemplates))
+	for _, tmpl := range w.storedTemplates {
+		stored[tmpl.Name] = true
+	}
+	var choices []templates.Template
+	for _, tmpl := range predefined {
+		if !stored[tmpl.Kind] {
+			choices = append(choices, tmpl)
+		}
+	}
+	for _, tmpl := range w.storedTemplates {
+		label := tmpl.Description
+		switch {
+		case label != "":
+		case tmpl.Source != nil:
+			label = "Installed from " + tmpl.Source.URL
+		default:
+			label = "Stored template"
+		}
+		choices = append(choices, templates.Template{Name: label, Kind: tmpl.Name})
+	}
+	return choices, nil
+}
+
 func (w *Wizard) shouldPromptBlueprint(template string) bool {
 	// Only prompt for blueprints for certain templates that benefit from stacks
 	switch template {
@@ -246,6 +432,21 @@ func (w *Wizard) shouldPromptBlueprint(template string) bool {
 }
 
 func (w *Wizard) promptBlueprint(ctx context.Context, options *WizardOptions) error {
+	if value, ok := w.answer(QuestionBlueprint); ok {
+		if value == "none" {
+			options.Blueprint = ""
+			return nil
+		}
+		if _, err := w.blueprintRepo.GetBlueprint(ctx, value); err != nil {
+			return answerError(QuestionBlueprint, err)
+		}
+		options.Blueprint = value
+		return nil
+	}
+	if !w.interactive {
+		return nil
+	}
+
 	availableBlueprints, err := w.blueprintRepo.ListBlueprints(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to list blueprints: %w", err)
@@ -258,7 +459,8 @@ func (w *Wizard) promptBlueprint(ctx context.Context, options *WizardOptions) er
 	// Filter blueprints suitable for the selected template
 	var suitableBlueprints []blueprints.Blueprint
 	for _, bp := range availableBlueprints {
-		if w.isBlueprintSuitableForTemplate(bp, options.Template) {
+		if w.isBlueprintSuitableForTemplate(bp, options.Template) &&
+			db.HasAllTags(w.entryTags[db.KindBlueprint][bp.Name], w.requiredTags) {
 			suitableBlueprints = append(suitableBlueprints, bp)
 		}
 	}
@@ -270,12 +472,13 @@ func (w *Wizard) promptBlueprint(ctx context.Context, options *WizardOptions) er
 	// Add "None" option
 	items := []string{"None (basic template only)"}
 	for _, bp := range suitableBlueprints {
-		items = append(items, fmt.Sprintf("%s - %s stack", bp.Name, bp.Stack))
+		items = append(items, w.choiceLabel(db.KindBlueprint, bp.Name, fmt.Sprintf("%s - %s stack", bp.Name, bp.Stack)))
 	}
 
 	prompt := promptui.Select{
-		Label: "Select stack blueprint (optional)",
-		Items: items,
+		Label:    "Select stack blueprint (optional)",
+		Items:    items,
+		Searcher: itemSearcher(items),
 	}
 
 	i, _, err := prompt.Run()
@@ -289,14 +492,32 @@ func (w *Wizard) promptBlueprint(ctx context.Context, options *WizardOptions) er
 	return nil
 }
 
+// choiceLabel appends an entry's rating and tags to its selection label
+func (w *Wizard) choiceLabel(kind, name, label string) string {
+	if stars := w.annotations[kind][name].Stars(); stars != "" {
+		label += " " + stars
+	}
+	if tags := w.entryTags[kind][name]; len(tags) > 0 {
+		label += fmt.Sprintf(" [%s]", strings.Join(tags, ", "))
+	}
+	return label
+}
+
+// itemSearcher matches selection items, including their tags, case-insensitively
+func itemSearcher(items []string) func(input string, index int) bool {
+	return func(input string, index int) bool {
+		return strings.Contains(strings.ToLower(items[index]), strings.ToLower(strings.TrimSpace(input)))
+	}
+}
+
 func (w *Wizard) isBlueprintSuitableForTemplate(bp blueprints.Blueprint, template string) bool {
 	switch template {
 	case "api":
-		return bp.Stack == "web"
+		return bp.Stack == "web" || bp.Stack == "graphql"
 	case "grpc":
 		return bp.Stack == "grpc"
 	case "microservice":
-		return bp.Stack == "microservice" || bp.Stack == "web"
+		return bp.Stack == "microservice" || bp.Stack == "web" || bp.Stack == "worker"
 	default:
 		return false
 	}
@@ -304,7 +525,14 @@ func (w *Wizard) isBlueprintSuitableForTemplate(bp blueprints.Blueprint, templat
 
 func (w *Wizard) promptAuthor(options *WizardOptions) error {
 	// Try to get default from git config
-	defaultAuthor := w.getGitUserName()
+	defaultAuthor, answered := w.answer(QuestionAuthor)
+	if !answered {
+		defaultAuthor = w.getGitUserName()
+	}
+	if answered || !w.interactive {
+		options.Author = defaultAuthor
+		return nil
+	}
 
 	prompt := promptui.Prompt{
 		Label:   "Author name",
@@ -321,6 +549,17 @@ func (w *Wizard) promptAuthor(options *WizardOptions) error {
 }
 
 func (w *Wizard) promptLicense(options *WizardOptions) error {
+	if value, ok := w.answer(QuestionLicense); ok {
+		options.License = value
+		return nil
+	}
+	if !w.interactive {
+		if options.License == "" {
+			options.License = "MIT"
+		}
+		return nil
+	}
+
 	licenses := []string{"MIT", "Apache-2.0", "GPL-3.0", "BSD-3-Clause", "ISC", "Other"}
 
 	prompt := promptui.Select{
@@ -338,6 +577,17 @@ func (w *Wizard) promptLicense(options *WizardOptions) error {
 }
 
 func (w *Wizard) promptGoVersion(options *WizardOptions) error {
+	if value, ok := w.answer(QuestionGoVersion); ok {
+		if value == "auto-detect" {
+			value = ""
+		}
+		options.GoVersion = value
+		return nil
+	}
+	if !w.interactive {
+		return nil
+	}
+
 	versions := []string{"1.25.1", "1.25", "auto-detect", "1.24", "1.23"}
 
 	prompt := promptui.Select{
@@ -363,6 +613,14 @@ func (w *Wizard) promptOutputDir(options *WizardOptions) error {
 	if defaultDir == "" {
 		defaultDir = "."
 	}
+	if value, ok := w.answer(QuestionOutputDir); ok {
+		options.OutputDir = value
+		return nil
+	}
+	if !w.interactive {
+		options.OutputDir = defaultDir
+		return nil
+	}
 
 	prompt := promptui.Prompt{
 		Label:   "Output directory",
@@ -379,6 +637,18 @@ func (w *Wizard) promptOutputDir(options *WizardOptions) error {
 }
 
 func (w *Wizard) promptGitInit(options *WizardOptions) error {
+	value, ok, err := w.boolAnswer(QuestionGitInit)
+	if err != nil {
+		return err
+	}
+	if ok {
+		options.GitInit = value
+		return nil
+	}
+	if !w.interactive {
+		return nil
+	}
+
 	prompt := promptui.Select{
 		Label: "Initialize git repository",
 		Items: []string{"Yes", "No"},
@@ -394,6 +664,19 @@ func (w *Wizard) promptGitInit(options *WizardOptions) error {
 }
 
 func (w *Wizard) promptForce(options *WizardOptions) error {
+	if value, ok := w.answer(QuestionOnConflict); ok {
+		policy, err := generator.ParseConflictPolicy(value)
+		if err != nil {
+			return answerError(QuestionOnConflict, err)
+		}
+		options.OnConflict = policy
+		options.Force = policy == generator.ConflictOverwrite
+		return nil
+	}
+	if !w.interactive {
+		return nil
+	}
+
 	// Check if output directory exists and is not empty
 	if options.OutputDir != "." {
 		if _, err := os.Stat(options.OutputDir); err == nil {
@@ -405,8 +688,13 @@ func (w *Wizard) promptForce(options *WizardOptions) error {
 
 			if len(entries) > 0 {
 				prompt := promptui.Select{
-					Label: fmt.Sprintf("Directory '%s' is not empty. Overwrite existing files?", options.OutputDir),
-					Items: []string{"No", "Yes"},
+					Label: fmt.Sprintf("Directory '%s' is not empty. What should happen to existing files?", options.OutputDir),
+					Items: []string{
+						"Stop if any would change",
+						"Keep them (skip generated files)",
+						"Overwrite them",
+						"Overwrite them, keeping a " + generator.BackupSuffix + " copy",
+					},
 				}
 
 				i, _, err := prompt.Run()
@@ -414,7 +702,9 @@ func (w *Wizard) promptForce(options *WizardOptions) error {
 					return fmt.Errorf("force overwrite prompt failed: %w", err)
 				}
 
-				options.Force = i == 1 // 0 = "No", 1 = "Yes"
+				// Items are in the order of generator.ConflictPolicies
+				options.OnConflict = generator.ConflictPolicies[i]
+				options.Force = options.OnConflict == generator.ConflictOverwrite
 			}
 		}
 	}
@@ -425,30 +715,53 @@ func (w *Wizard) promptForce(options *WizardOptions) error {
 func (w *Wizard) showSummary(options *WizardOptions) {
 	fmt.Println()
 	color.Yellow("Project Configuration Summary:")
-	fmt.Printf("  Project Name: %s\n", options.ProjectName)
-	fmt.Printf("  Module Name:  %s\n", options.ModuleName)
-	fmt.Printf("  Template:     %s\n", options.Template)
+	for _, line := range w.summary(options) {
+		fmt.Printf("  %s\n", line)
+	}
+	fmt.Println()
+}
+
+// summary returns the lines of the configuration summary
+func (w *Wizard) summary(options *WizardOptions) []string {
+	lines := []string{
+		fmt.Sprintf("Project Name: %s", options.ProjectName),
+		fmt.Sprintf("Module Name:  %s", options.ModuleName),
+		fmt.Sprintf("Template:     %s", options.Template),
+	}
 	if options.Blueprint != "" {
-		fmt.Printf("  Blueprint:    %s\n", options.Blueprint)
+		lines = append(lines, fmt.Sprintf("Blueprint:    %s", options.Blueprint))
 	}
-	fmt.Printf("  Author:       %s\n", options.Author)
+	lines = append(lines, fmt.Sprintf("Author:       %s", options.Author))
 	if options.Email != "" {
-		fmt.Printf("  Email:        %s\n", options.Email)
-	}
-	fmt.Printf("  License:      %s\n", options.License)
-	fmt.Printf("  Go Version:   %s\n", w.displayGoVersion(options.GoVersion))
-	fmt.Printf("  Output Dir:   %s\n", options.OutputDir)
-	fmt.Printf("  Git Init:     %t\n", options.GitInit)
-	if options.GitInit && options.GenerateCI {
-		fmt.Printf("  Generate CI:  %t\n", options.GenerateCI)
+		lines = append(lines, fmt.Sprintf("Email:        %s", options.Email))
+	}
+	lines = append(lines,
+		fmt.Sprintf("License:      %s", options.License),
+		fmt.Sprintf("Go Version:   %s", w.displayGoVersion(options.GoVersion)),
+		fmt.Sprintf("Output Dir:   %s", options.OutputDir),
+		fmt.Sprintf("Git Init:     %t", options.GitInit))
+	if options.GenerateCI {
+		lines = append(lines, fmt.Sprintf("Generate CI:  %t", options.GenerateCI))
+		if options.CIProvider != "" {
+			lines = append(lines, fmt.Sprintf("CI Provider:  %s", cicd.ProviderName(options.CIProvider)))
+		}
+		if bot, _ := cicd.ParseUpdateBot(options.DependencyUpdates); bot != "" {
+			lines = append(lines, fmt.Sprintf("Dep Updates:  %s", cicd.UpdateBotName(bot)))
+		}
 		if options.CoverageMin > 0 {
-			fmt.Printf("  Coverage Min: %.0f%%\n", options.CoverageMin*100)
+			lines = append(lines, fmt.Sprintf("Coverage Min: %.0f%%", options.CoverageMin*100))
 		}
 	}
 	if options.Force {
-		fmt.Printf("  Force:        %t\n", options.Force)
+		lines = append(lines, fmt.Sprintf("Force:        %t", options.Force))
 	}
-	fmt.Println()
+	if options.OnConflict != "" {
+		lines = append(lines, fmt.Sprintf("On Conflict:  %s", options.OnConflict))
+	}
+	if len(options.Omit) > 0 {
+		lines = append(lines, fmt.Sprintf("Left Out:     %s", strings.Join(options.Omit, ", ")))
+	}
+	return lines
 }
 
 func (w *Wizard) displayGoVersion(version string) string {
@@ -458,22 +771,163 @@ func (w *Wizard) displayGoVersion(version string) string {
 	return version
 }
 
-func (w *Wizard) promptConfirmation() error {
-	prompt := promptui.Select{
-		Label: "Proceed with project creation",
-		Items: []string{"Yes", "No"},
+// review shows the configuration, the blueprint's components and the files
+// that will be generated, and lets optional pieces be switched on and off
+// until the user creates the project or cancels
+func (w *Wizard) review(ctx context.Context, options *WizardOptions) error {
+	if value, ok := w.answer(QuestionOmit); ok {
+		options.Omit = parseOmit(value)
+		w.showSummary(options)
+		return nil
 	}
+	if !w.interactive {
+		w.showSummary(options)
+		return nil
+	}
+	for {
+		w.showSummary(options)
+		w.showComponents(ctx, options)
+		preview := w.showFileTree(ctx, options)
 
-	i, _, err := prompt.Run()
+		pieces := optionalPieces(options, preview)
+		items := []string{"Create project"}
+		for _, piece := range pieces {
+			items = append(items, piece.label(options))
+		}
+		items = append(items, "Cancel")
+
+		prompt := promptui.Select{
+			Label: "Create the project, or toggle an optional piece",
+			Items: items,
+		}
+
+		i, _, err := prompt.Run()
+		if err != nil {
+			return fmt.Errorf("confirmation prompt failed: %w", err)
+		}
+
+		switch i {
+		case 0:
+			return nil
+		case len(items) - 1:
+			return fmt.Errorf("project creation cancelled by user")
+		default:
+			pieces[i-1].toggle(options)
+		}
+	}
+}
+
+// showComponents lists the components the selected blueprint resolves to
+func (w *Wizard) showComponents(ctx context.Context, options *WizardOptions) {
+	if options.Blueprint == "" {
+		return
+	}
+	blueprint, err := w.blueprintRepo.GetBlueprint(ctx, options.Blueprint)
+	if err != nil || len(blueprint.Config.Components) == 0 {
+		return
+	}
+
+	color.Yellow("Components:")
+	for _, component := range blueprint.Config.Components {
+		fmt.Printf("  - %s\n", component)
+	}
+	fmt.Println()
+}
+
+// showFileTree renders the project without writing it and prints the names
+// of the files it would generate, returning the preview
+func (w *Wizard) showFileTree(ctx context.Context, options *WizardOptions) *generator.Preview {
+	if w.previewer == nil {
+		return nil
+	}
+
+	result, err := w.preview(ctx, options)
 	if err != nil {
-		return fmt.Errorf("confirmation prompt failed: %w", err)
+		color.Red("Could not preview the files: %v", err)
+		fmt.Println()
+		return nil
 	}
 
-	if i != 0 { // 0 = "Yes", 1 = "No"
-		return fmt.Errorf("project creation cancelled by user")
+	color.Yellow("Files (%d):", len(result.Files))
+	fmt.Print(result.FileTree(filepath.Base(options.OutputDir)))
+	fmt.Println()
+	return result
+}
+
+// preview renders the project of options aside and returns its files
+func (w *Wizard) preview(ctx context.Context, options *WizardOptions) (*generator.Preview, error) {
+	opts := options.ConvertToInitOptions()
+	opts.DryRun = true
+	// git init adds no files, and the preview would otherwise assume CI files
+	opts.GitInit = false
+	result, err := w.previewer.InitProject(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	if result.Preview == nil {
+		return nil, fmt.Errorf("no preview")
 	}
+	return result.Preview, nil
+}
 
-	return nil
+// optionalPiece is a part of the project the review step can switch on and off
+type optionalPiece struct {
+	name    string
+	enabled func(options *WizardOptions) bool
+	toggle  func(options *WizardOptions)
+}
+
+func (p optionalPiece) label(options *WizardOptions) string {
+	state := "off"
+	if p.enabled(options) {
+		state = "on"
+	}
+	return fmt.Sprintf("%s: %s (toggle)", p.name, state)
+}
+
+// optionalPieces lists what the review step offers to toggle: the optional
+// files the template generates, or would without being left out, and CI/CD
+func optionalPieces(options *WizardOptions, preview *generator.Preview) []optionalPiece {
+	generated := make(map[string]bool)
+	if preview != nil {
+		for _, file := range preview.Files {
+			generated[file.Path] = true
+		}
+	}
+
+	var pieces []optionalPiece
+	for _, path := range generator.OptionalFiles {
+		if !generated[path] && !slices.Contains(options.Omit, path) {
+			continue
+		}
+		pieces = append(pieces, optionalPiece{
+			name: path,
+			enabled: func(options *WizardOptions) bool {
+				return !slices.Contains(options.Omit, path)
+			},
+			toggle: func(options *WizardOptions) {
+				if i := slices.Index(options.Omit, path); i >= 0 {
+					options.Omit = slices.Delete(options.Omit, i, i+1)
+				} else {
+					options.Omit = append(options.Omit, path)
+				}
+			},
+		})
+	}
+
+	pieces = append(pieces, optionalPiece{
+		name: "CI/CD",
+		enabled: func(options *WizardOptions) bool {
+			return options.GenerateCI
+		},
+		toggle: func(options *WizardOptions) {
+			options.GenerateCI = !options.GenerateCI
+			if options.GenerateCI && options.CoverageMin == 0 {
+				options.CoverageMin = 0.80
+			}
+		},
+	})
+	return pieces
 }
 
 func (w *Wizard) getGitUserName() string {
@@ -489,8 +943,19 @@ func (w *Wizard) getGitUserName() string {
 }
 
 func (w *Wizard) promptEmail(options *WizardOptions) error {
-	// Try to get default from git config
-	defaultEmail := w.getGitUserEmail()
+	// Default to the configured email, else git's
+	defaultEmail := options.Email
+	if defaultEmail == "" {
+		defaultEmail = w.getGitUserEmail()
+	}
+	if value, ok := w.answer(QuestionEmail); ok {
+		options.Email = value
+		return nil
+	}
+	if !w.interactive {
+		options.Email = defaultEmail
+		return nil
+	}
 
 	prompt := promptui.Prompt{
 		Label:   "Author email (optional)",
@@ -507,8 +972,20 @@ func (w *Wizard) promptEmail(options *WizardOptions) error {
 }
 
 func (w *Wizard) promptCICD(options *WizardOptions) error {
+	value, ok, err := w.boolAnswer(QuestionCI)
+	if err != nil {
+		return err
+	}
+	if ok {
+		options.GenerateCI = value
+		return nil
+	}
+	if !w.interactive {
+		return nil
+	}
+
 	prompt := promptui.Select{
-		Label: "Generate CI/CD configurations (.golangci.yml, GitHub Actions, pre-commit hooks)?",
+		Label: "Generate CI/CD configurations (.golangci.yml, a CI pipeline, pre-commit hooks)?",
 		Items: []string{"Yes", "No"},
 	}
 
@@ -521,7 +998,86 @@ func (w *Wizard) promptCICD(options *WizardOptions) error {
 	return nil
 }
 
+func (w *Wizard) promptCIProvider(options *WizardOptions) error {
+	if value, ok := w.answer(QuestionCIProvider); ok {
+		provider, err := cicd.ParseProvider(value)
+		if err != nil {
+			return answerError(QuestionCIProvider, err)
+		}
+		options.CIProvider = provider
+		return nil
+	}
+	if !w.interactive {
+		return nil
+	}
+
+	items := make([]string, len(cicd.Providers))
+	for i, provider := range cicd.Providers {
+		items[i] = cicd.ProviderName(provider)
+	}
+	prompt := promptui.Select{
+		Label: "Select CI provider",
+		Items: items,
+	}
+
+	i, _, err := prompt.Run()
+	if err != nil {
+		return fmt.Errorf("CI provider prompt failed: %w", err)
+	}
+
+	options.CIProvider = cicd.Providers[i]
+	return nil
+}
+
+func (w *Wizard) promptDependencyUpdates(options *WizardOptions) error {
+	if value, ok := w.answer(QuestionDependencyUpdates); ok {
+		if _, err := cicd.ParseUpdateBot(value); err != nil {
+			return answerError(QuestionDependencyUpdates, err)
+		}
+		options.DependencyUpdates = value
+		return nil
+	}
+	if !w.interactive {
+		options.DependencyUpdates = "none"
+		return nil
+	}
+
+	bots := append([]string{"none"}, cicd.UpdateBots...)
+	items := []string{"None"}
+	for _, bot := range cicd.UpdateBots {
+		items = append(items, cicd.UpdateBotName(bot))
+	}
+	prompt := promptui.Select{
+		Label: "Keep Go modules and CI actions up to date with",
+		Items: items,
+	}
+
+	i, _, err := prompt.Run()
+	if err != nil {
+		return fmt.Errorf("dependency updates prompt failed: %w", err)
+	}
+
+	options.DependencyUpdates = bots[i]
+	return nil
+}
+
 func (w *Wizard) promptCoverageMin(options *WizardOptions) error {
+	if value, ok := w.answer(QuestionCoverageMin); ok {
+		if err := w.validateCoveragePercentage(value); err != nil {
+			return answerError(QuestionCoverageMin, err)
+		}
+		var percentage float64
+		fmt.Sscanf(value, "%f", &percentage)
+		options.CoverageMin = percentage / 100.0
+		return nil
+	}
+	if !w.interactive {
+		if options.CoverageMin == 0 {
+			options.CoverageMin = 0.80
+		}
+		return nil
+	}
+
 	coverageOptions := []string{"80%", "75%", "85%", "90%", "Custom"}
 
 	prompt := promptui.Select{
@@ -587,22 +1143,31 @@ func (w *Wizard) getGitUserEmail() string {
 
 // ConvertToInitOptions converts wizard options to generator InitOptions
 func (w *WizardOptions) ConvertToInitOptions() generator.InitOptions {
-	return generator.InitOptions{
-		ProjectName:          w.ProjectName,
-		ModuleName:           w.ModuleName,
-		Template:             w.Template,
-		Blueprint:            w.Blueprint,
-		Author:               w.Author,
-		Email:                w.Email,
-		License:              w.License,
-		GoVersion:            w.GoVersion,
-		OutputDir:            w.OutputDir,
-		Description:          fmt.Sprintf("A %s project", w.Template),
-		GitInit:              w.GitInit,
-		GenerateCI:           w.GenerateCI,
-		CoverageMin:          w.CoverageMin,
-		InitialCommitMessage: w.InitialCommitMessage,
-		Force:                w.Force,
-		DryRun:               false, // Wizard doesn't support dry-run mode
-	}
+	return w.ApplyTo(generator.InitOptions{})
+}
+
+// ApplyTo returns opts with the wizard's answers in place of the options it
+// asks about. The others, such as the framework or the coverage exclusions
+// given as flags, are kept.
+func (w *WizardOptions) ApplyTo(opts generator.InitOptions) generator.InitOptions {
+	opts.ProjectName = w.ProjectName
+	opts.ModuleName = w.ModuleName
+	opts.Template = w.Template
+	opts.Blueprint = w.Blueprint
+	opts.Author = w.Author
+	opts.Email = w.Email
+	opts.License = w.License
+	opts.GoVersion = w.GoVersion
+	opts.OutputDir = w.OutputDir
+	opts.Description = fmt.Sprintf("A %s project", w.Template)
+	opts.GitInit = w.GitInit
+	opts.GenerateCI = w.GenerateCI
+	opts.CIProvider = w.CIProvider
+	opts.DependencyUpdates = w.DependencyUpdates
+	opts.CoverageMin = w.CoverageMin
+	opts.InitialCommitMessage = w.InitialCommitMessage
+	opts.Force = w.Force
+	opts.OnConflict = w.OnConflict
+	opts.Omit = w.Omit
+	return opts
 }
@@ -1,9 +1,12 @@
 package prompt
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/user/gogo/internal/db"
 	"github.com/user/gogo/internal/generator"
 )
 
@@ -111,6 +114,25 @@ func TestWizard_shouldPromptBlueprint(t *testing.T) {
 	}
 }
 
+func TestWizard_choiceLabel(t *testing.T) {
+	wizard := NewWizard()
+	assert.Equal(t, "API Service - api", wizard.choiceLabel(db.KindTemplate, "api", "API Service - api"))
+
+	wizard.SetTags(db.KindTemplate, map[string][]string{"api": {"internal", "payment"}})
+	wizard.SetAnnotations(db.KindTemplate, map[string]db.Annotation{"api": {Rating: 3}})
+	assert.Equal(t, "API Service - api ★★★☆☆ [internal, payment]", wizard.choiceLabel(db.KindTemplate, "api", "API Service - api"))
+	assert.Equal(t, "web-stack - web stack", wizard.choiceLabel(db.KindBlueprint, "web-stack", "web-stack - web stack"))
+}
+
+func TestItemSearcher(t *testing.T) {
+	items := []string{"API Service - api [payment]", "CLI Application - cli"}
+	search := itemSearcher(items)
+
+	assert.True(t, search("PAYMENT", 0))
+	assert.False(t, search("payment", 1))
+	assert.True(t, search(" cli ", 1))
+}
+
 func TestWizard_displayGoVersion(t *testing.T) {
 	wizard := NewWizard()
 
@@ -139,3 +161,58 @@ func TestWizard_getGitUserName(t *testing.T) {
 	// Should return either empty string or current user
 	assert.True(t, len(result) >= 0)
 }
+
+func TestWizard_templateChoices(t *testing.T) {
+	wizard := NewWizard()
+	wizard.SetStoredTemplates([]*db.StoredTemplate{
+		{Name: "api", Description: "Company API"},
+		{Name: "worker", Source: &db.InstallSource{URL: "https://github.com/acme/templates"}},
+	})
+
+	choices, err := wizard.templateChoices(context.Background())
+	require.NoError(t, err)
+	labels := make(map[string]string)
+	for _, choice := range choices {
+		labels[choice.Kind] = choice.Name
+	}
+	assert.Equal(t, "Company API", labels["api"])
+	assert.Equal(t, "Installed from https://github.com/acme/templates", labels["worker"])
+	assert.Contains(t, labels, "cli")
+	assert.Equal(t, "worker", choices[len(choices)-1].Kind)
+}
+
+func TestOptionalPieces(t *testing.T) {
+	options := &WizardOptions{Omit: []string{generator.ComposePath}}
+	preview := &generator.Preview{Files: []generator.FileChange{{Path: generator.DockerfilePath}, {Path: "go.mod"}}}
+
+	pieces := optionalPieces(options, preview)
+	require.Len(t, pieces, 3, "files the template generates, ones left out, and CI/CD")
+	assert.Equal(t, "Dockerfile: on (toggle)", pieces[0].label(options))
+	assert.Equal(t, "docker-compose.yml: off (toggle)", pieces[1].label(options))
+	assert.Equal(t, "CI/CD: off (toggle)", pieces[2].label(options))
+
+	pieces[0].toggle(options)
+	pieces[1].toggle(options)
+	pieces[2].toggle(options)
+	assert.Equal(t, []string{generator.DockerfilePath}, options.Omit)
+	assert.True(t, options.GenerateCI)
+	assert.Equal(t, 0.80, options.CoverageMin)
+	assert.Equal(t, options.Omit, options.ConvertToInitOptions().Omit)
+
+	pieces = optionalPieces(&WizardOptions{}, &generator.Preview{Files: []generator.FileChange{{Path: "go.mod"}}})
+	assert.Len(t, pieces, 1, "templates without a Dockerfile only offer CI/CD")
+}
+
+func TestWizardOptions_ApplyTo(t *testing.T) {
+	options := &WizardOptions{ProjectName: "svc", Template: "api", CoverageMin: 0.93}
+	opts := options.ApplyTo(generator.InitOptions{
+		ProjectName: "other",
+		Framework:   "chi",
+		DryRun:      true,
+		CoverageMin: 0.80,
+	})
+	assert.Equal(t, "svc", opts.ProjectName)
+	assert.Equal(t, 0.93, opts.CoverageMin, "answers replace the options the wizard asks about")
+	assert.Equal(t, "chi", opts.Framework, "options it does not ask about are kept")
+	assert.True(t, opts.DryRun)
+}
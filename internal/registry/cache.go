@@ -0,0 +1,103 @@
+package registry
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/user/gogo/internal/db"
+)
+
+// CacheEntry represents a cached registry response
+type CacheEntry struct {
+	URL          string
+	ETag         string
+	LastModified string
+	Body         []byte
+	FetchedAt    time.Time
+}
+
+// Cache stores registry responses in the gogo database
+type Cache struct {
+	db *db.Manager
+}
+
+// NewCache creates a new registry cache backed by the database
+func NewCache(manager *db.Manager) *Cache {
+	return &Cache{
+		db: manager,
+	}
+}
+
+// Get returns the cached entry for a URL, or nil if nothing is cached
+func (c *Cache) Get(ctx context.Context, url string) (*CacheEntry, error) {
+	query := `SELECT url, etag, last_modified, body, fetched_at FROM registry_cache WHERE url = ?`
+
+	var entry CacheEntry
+	var fetchedAt string
+	err := c.db.GetDB().QueryRowContext(ctx, query, url).Scan(
+		&entry.URL, &entry.ETag, &entry.LastModified, &entry.Body, &fetchedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cache entry for %s: %w", url, err)
+	}
+
+	entry.FetchedAt, err = time.Parse(time.RFC3339, fetchedAt)
+	if err != nil {
+		// Unparseable timestamps are treated as expired
+		entry.FetchedAt = time.Time{}
+	}
+
+	return &entry, nil
+}
+
+// Put stores or replaces the cached entry for a URL
+func (c *Cache) Put(ctx context.Context, entry *CacheEntry) error {
+	query := `INSERT INTO registry_cache (url, etag, last_modified, body, fetched_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(url) DO UPDATE SET
+			etag = excluded.etag,
+			last_modified = excluded.last_modified,
+			body = excluded.body,
+			fetched_at = excluded.fetched_at`
+
+	_, err := c.db.GetDB().ExecContext(ctx, query,
+		entry.URL, entry.ETag, entry.LastModified, entry.Body, entry.FetchedAt.UTC().Format(time.RFC3339))
+	if err != nil {
+		return fmt.Errorf("failed to store cache entry for %s: %w", entry.URL, err)
+	}
+
+	return nil
+}
+
+// Touch marks a cached entry as freshly validated
+func (c *Cache) Touch(ctx context.Context, url string, fetchedAt time.Time) error {
+	query := `UPDATE registry_cache SET fetched_at = ? WHERE url = ?`
+	if _, err := c.db.GetDB().ExecContext(ctx, query, fetchedAt.UTC().Format(time.RFC3339), url); err != nil {
+		return fmt.Errorf("failed to update cache entry for %s: %w", url, err)
+	}
+	return nil
+}
+
+// URLs returns the URLs of all cached entries
+func (c *Cache) URLs(ctx context.Context) ([]string, error) {
+	rows, err := c.db.GetDB().QueryContext(ctx, `SELECT url FROM registry_cache ORDER BY url`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query cache entries: %w", err)
+	}
+	defer rows.Close()
+
+	var urls []string
+	for rows.Next() {
+		var url string
+		if err := rows.Scan(&url); err != nil {
+			return nil, fmt.Errorf("failed to scan cache entry: %w", err)
+		}
+		urls = append(urls, url)
+	}
+
+	return urls, rows.Err()
+}
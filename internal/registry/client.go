@@ -0,0 +1,171 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	// DefaultTTL is how long a cached response is used without revalidation
+	DefaultTTL = time.Hour
+	// DefaultTimeout bounds each registry request
+	DefaultTimeout = 10 * time.Second
+)
+
+// ErrNotFound is returned when the registry has no resource at a URL
+var ErrNotFound = errors.New("not found in registry")
+
+// FetchOptions contains options for a registry fetch
+type FetchOptions struct {
+	Force bool // Skip the cache and perform an unconditional request
+}
+
+// FetchResult contains the result of a registry fetch
+type FetchResult struct {
+	Body        []byte
+	FromCache   bool // Body was served from the local cache
+	NotModified bool // The registry confirmed the cached body is current
+	Stale       bool // The registry was unreachable and an expired entry was used
+}
+
+// Client fetches registry resources using conditional requests and a local cache
+type Client struct {
+	httpClient *http.Client
+	cache      *Cache
+	ttl        time.Duration
+	now        func() time.Time
+	token      func(ctx context.Context, host string) string
+}
+
+// NewClient creates a new registry client
+func NewClient(cache *Cache) *Client {
+	return &Client{
+		httpClient: &http.Client{Timeout: DefaultTimeout},
+		cache:      cache,
+		ttl:        DefaultTTL,
+		now:        time.Now,
+	}
+}
+
+// SetTTL sets how long cached responses are used without revalidation
+func (c *Client) SetTTL(ttl time.Duration) {
+	c.ttl = ttl
+}
+
+// SetHTTPClient overrides the HTTP client used for requests
+func (c *Client) SetHTTPClient(httpClient *http.Client) {
+	c.httpClient = httpClient
+}
+
+// SetTokenSource sets the function supplying bearer tokens for registry
+// hosts; tokens are only ever sent over HTTPS
+func (c *Client) SetTokenSource(token func(ctx context.Context, host string) string) {
+	c.token = token
+}
+
+// Fetch retrieves a registry resource, consulting the cache first
+func (c *Client) Fetch(ctx context.Context, url string, opts FetchOptions) (*FetchResult, error) {
+	cached, err := c.cache.Get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	// Serve fresh entries without touching the network
+	if cached != nil && !opts.Force && c.now().Sub(cached.FetchedAt) < c.ttl {
+		return &FetchResult{Body: cached.Body, FromCache: true}, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request for %s: %w", url, err)
+	}
+
+	if c.token != nil && req.URL.Scheme == "https" {
+		if token := c.token(ctx, req.URL.Host); token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+	}
+
+	if cached != nil && !opts.Force {
+		if cached.ETag != "" {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+		if cached.LastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		// Fall back to an expired entry rather than blocking on the network
+		if cached != nil && !opts.Force {
+			return &FetchResult{Body: cached.Body, FromCache: true, Stale: true}, nil
+		}
+		return nil, fmt.Errorf("failed to fetch %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusNotModified:
+		if cached == nil {
+			return nil, fmt.Errorf("registry returned 304 for %s without a cached entry", url)
+		}
+		if err := c.cache.Touch(ctx, url, c.now()); err != nil {
+			return nil, err
+		}
+		return &FetchResult{Body: cached.Body, FromCache: true, NotModified: true}, nil
+
+	case http.StatusOK:
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response from %s: %w", url, err)
+		}
+
+		entry := &CacheEntry{
+			URL:          url,
+			ETag:         resp.Header.Get("ETag"),
+			LastModified: resp.Header.Get("Last-Modified"),
+			Body:         body,
+			FetchedAt:    c.now(),
+		}
+		if err := c.cache.Put(ctx, entry); err != nil {
+			return nil, err
+		}
+		return &FetchResult{Body: body}, nil
+
+	case http.StatusNotFound:
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, url)
+
+	default:
+		return nil, fmt.Errorf("registry returned %s for %s", resp.Status, url)
+	}
+}
+
+// FetchSignature fetches the detached signature published next to a bundle
+// at <url>.sig, or nil when the registry publishes none
+func (c *Client) FetchSignature(ctx context.Context, bundleURL string) (*Signature, error) {
+	result, err := c.Fetch(ctx, signatureURL(bundleURL), FetchOptions{Force: true})
+	if errors.Is(err, ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ParseSignature(result.Body)
+}
+
+// signatureURL returns the URL of the detached signature of a bundle,
+// keeping any query string such as an access token
+func signatureURL(bundleURL string) string {
+	parsed, err := url.Parse(bundleURL)
+	if err != nil {
+		return bundleURL + SignatureExtension
+	}
+	parsed.Path += SignatureExtension
+	return parsed.String()
+}
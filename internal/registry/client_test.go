@@ -0,0 +1,144 @@
+package registry
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/user/gogo/internal/db"
+)
+
+func setupTestCache(t *testing.T) *Cache {
+	manager := db.NewManager()
+	require.NoError(t, manager.Open(context.Background(), filepath.Join(t.TempDir(), "test.db")))
+	t.Cleanup(func() {
+		manager.Close()
+	})
+	return NewCache(manager)
+}
+
+func newTestRegistry(t *testing.T, hits *int32) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(hits, 1)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Last-Modified", "Mon, 02 Jan 2006 15:04:05 GMT")
+		_, _ = w.Write([]byte(`{"templates": []}`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestClient_Fetch(t *testing.T) {
+	ctx := context.Background()
+	var hits int32
+	server := newTestRegistry(t, &hits)
+
+	client := NewClient(setupTestCache(t))
+	now := time.Now()
+	client.now = func() time.Time { return now }
+
+	// First fetch goes to the network
+	result, err := client.Fetch(ctx, server.URL, FetchOptions{})
+	require.NoError(t, err)
+	assert.False(t, result.FromCache)
+	assert.Equal(t, `{"templates": []}`, string(result.Body))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&hits))
+
+	// Second fetch within TTL is served from cache
+	result, err = client.Fetch(ctx, server.URL, FetchOptions{})
+	require.NoError(t, err)
+	assert.True(t, result.FromCache)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&hits))
+
+	// After TTL expiry the client revalidates with a conditional request
+	now = now.Add(2 * DefaultTTL)
+	result, err = client.Fetch(ctx, server.URL, FetchOptions{})
+	require.NoError(t, err)
+	assert.True(t, result.NotModified)
+	assert.Equal(t, `{"templates": []}`, string(result.Body))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&hits))
+
+	// Force always performs an unconditional request
+	result, err = client.Fetch(ctx, server.URL, FetchOptions{Force: true})
+	require.NoError(t, err)
+	assert.False(t, result.FromCache)
+	assert.False(t, result.NotModified)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&hits))
+}
+
+func TestClient_FetchStaleOnNetworkError(t *testing.T) {
+	ctx := context.Background()
+	var hits int32
+	server := newTestRegistry(t, &hits)
+
+	client := NewClient(setupTestCache(t))
+	now := time.Now()
+	client.now = func() time.Time { return now }
+
+	_, err := client.Fetch(ctx, server.URL, FetchOptions{})
+	require.NoError(t, err)
+
+	// Registry goes away and the cached entry expires
+	server.Close()
+	now = now.Add(2 * DefaultTTL)
+
+	result, err := client.Fetch(ctx, server.URL, FetchOptions{})
+	require.NoError(t, err)
+	assert.True(t, result.Stale)
+	assert.Equal(t, `{"templates": []}`, string(result.Body))
+
+	// Forced refresh surfaces the error instead
+	_, err = client.Fetch(ctx, server.URL, FetchOptions{Force: true})
+	assert.Error(t, err)
+}
+
+func TestClient_FetchSendsTokenOverHTTPS(t *testing.T) {
+	ctx := context.Background()
+	var authorization atomic.Value
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization.Store(r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte(`{}`))
+	})
+	tlsServer := httptest.NewTLSServer(handler)
+	t.Cleanup(tlsServer.Close)
+	plainServer := httptest.NewServer(handler)
+	t.Cleanup(plainServer.Close)
+
+	client := NewClient(setupTestCache(t))
+	client.SetHTTPClient(tlsServer.Client())
+	client.SetTokenSource(func(ctx context.Context, host string) string { return "registry-token" })
+
+	_, err := client.Fetch(ctx, tlsServer.URL, FetchOptions{Force: true})
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer registry-token", authorization.Load())
+
+	_, err = client.Fetch(ctx, plainServer.URL, FetchOptions{Force: true})
+	require.NoError(t, err)
+	assert.Equal(t, "", authorization.Load(), "tokens are never sent over plain HTTP")
+}
+
+func TestCache_URLs(t *testing.T) {
+	ctx := context.Background()
+	cache := setupTestCache(t)
+
+	require.NoError(t, cache.Put(ctx, &CacheEntry{URL: "https://b.example.com", Body: []byte("b"), FetchedAt: time.Now()}))
+	require.NoError(t, cache.Put(ctx, &CacheEntry{URL: "https://a.example.com", Body: []byte("a"), FetchedAt: time.Now()}))
+
+	urls, err := cache.URLs(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://a.example.com", "https://b.example.com"}, urls)
+
+	entry, err := cache.Get(ctx, "https://missing.example.com")
+	require.NoError(t, err)
+	assert.Nil(t, entry)
+}
@@ -0,0 +1,386 @@
+package registry
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultChunkSize is the range size requested when the registry publishes no chunk manifest
+	DefaultChunkSize = 4 << 20
+	// DefaultRetries is how many times a failed or corrupt chunk is re-requested
+	DefaultRetries = 3
+	// ChunkManifestSuffix is appended to a bundle URL to locate its chunk manifest
+	ChunkManifestSuffix = ".chunks"
+	// PartialSuffix is appended to the destination while a download is incomplete
+	PartialSuffix = ".part"
+)
+
+// ErrChecksumMismatch is returned when downloaded data does not match the registry's checksums
+var ErrChecksumMismatch = errors.New("checksum mismatch")
+
+// ChunkManifest describes a bundle split into fixed-size chunks with per-chunk checksums
+type ChunkManifest struct {
+	Size      int64    `json:"size"`
+	ChunkSize int64    `json:"chunk_size"`
+	SHA256    string   `json:"sha256"`
+	Chunks    []string `json:"chunks"` // Hex SHA-256 of each chunk, in order
+}
+
+// NewChunkManifest builds the chunk manifest for data, as published by a registry
+func NewChunkManifest(data []byte, chunkSize int64) *ChunkManifest {
+	manifest := &ChunkManifest{Size: int64(len(data)), ChunkSize: chunkSize}
+	sum := sha256.Sum256(data)
+	manifest.SHA256 = hex.EncodeToString(sum[:])
+
+	for start := int64(0); start < manifest.Size; start += chunkSize {
+		end := min(start+chunkSize, manifest.Size)
+		chunkSum := sha256.Sum256(data[start:end])
+		manifest.Chunks = append(manifest.Chunks, hex.EncodeToString(chunkSum[:]))
+	}
+	return manifest
+}
+
+// DownloadOptions contains options for a bundle download
+type DownloadOptions struct {
+	RateLimit int64                   // Maximum bytes per second; 0 means unlimited
+	Retries   int                     // Attempts per chunk; defaults to DefaultRetries
+	Progress  func(done, total int64) // Called after each chunk is written
+}
+
+// DownloadResult contains the result of a bundle download
+type DownloadResult struct {
+	Path     string
+	Size     int64
+	Resumed  int64 // Bytes reused from a previous partial download
+	Verified bool  // Every chunk matched the registry's chunk manifest
+}
+
+// Downloader fetches large bundles in ranged chunks, resuming partial downloads
+type Downloader struct {
+	httpClient *http.Client
+}
+
+// NewDownloader creates a new bundle downloader
+func NewDownloader() *Downloader {
+	// No overall timeout: large bundles on slow links legitimately take a long time
+	return &Downloader{httpClient: &http.Client{}}
+}
+
+// SetHTTPClient overrides the HTTP client used for requests
+func (d *Downloader) SetHTTPClient(httpClient *http.Client) {
+	d.httpClient = httpClient
+}
+
+// Download fetches url into dest. Data is written to dest.part and renamed once
+// complete, so an interrupted download resumes from the last verified chunk.
+func (d *Downloader) Download(ctx context.Context, url, dest string, opts DownloadOptions) (*DownloadResult, error) {
+	if opts.Retries <= 0 {
+		opts.Retries = DefaultRetries
+	}
+
+	manifest, err := d.fetchManifest(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	total, err := d.contentLength(ctx, url, manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	chunkSize := int64(DefaultChunkSize)
+	if manifest != nil {
+		chunkSize = manifest.ChunkSize
+	}
+
+	partPath := dest + PartialSuffix
+	part, err := os.OpenFile(partPath, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", partPath, err)
+	}
+	defer part.Close()
+
+	offset, err := resumeOffset(part, manifest, chunkSize)
+	if err != nil {
+		return nil, err
+	}
+	result := &DownloadResult{Path: dest, Size: total, Resumed: offset, Verified: manifest != nil}
+
+	limiter := newRateLimiter(opts.RateLimit)
+	for offset < total {
+		end := min(offset+chunkSize, total)
+		index := int(offset / chunkSize)
+
+		var chunk []byte
+		for attempt := 1; ; attempt++ {
+			chunk, err = d.fetchRange(ctx, url, offset, end, limiter)
+			if err == nil && manifest != nil && !chunkMatches(chunk, manifest.Chunks[index]) {
+				err = fmt.Errorf("chunk %d: %w", index, ErrChecksumMismatch)
+			}
+			if err == nil || attempt >= opts.Retries || ctx.Err() != nil {
+				break
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := part.WriteAt(chunk, offset); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", partPath, err)
+		}
+		offset = end
+
+		if opts.Progress != nil {
+			opts.Progress(offset, total)
+		}
+	}
+
+	if manifest != nil && manifest.SHA256 != "" {
+		if err := verifyFile(part, manifest.SHA256); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := part.Close(); err != nil {
+		return nil, fmt.Errorf("failed to close %s: %w", partPath, err)
+	}
+	if err := os.Rename(partPath, dest); err != nil {
+		return nil, fmt.Errorf("failed to move download into place: %w", err)
+	}
+
+	return result, nil
+}
+
+// fetchManifest retrieves the bundle's chunk manifest, returning nil when the registry has none
+func (d *Downloader) fetchManifest(ctx context.Context, url string) (*ChunkManifest, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url+ChunkManifestSuffix, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request for %s: %w", url, err)
+	}
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch chunk manifest for %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("registry returned %s for chunk manifest of %s", resp.Status, url)
+	}
+
+	var manifest ChunkManifest
+	if err := json.NewDecoder(resp.Body).Decode(&manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse chunk manifest for %s: %w", url, err)
+	}
+	if manifest.ChunkSize <= 0 || int64(len(manifest.Chunks)) != (manifest.Size+manifest.ChunkSize-1)/manifest.ChunkSize {
+		return nil, fmt.Errorf("invalid chunk manifest for %s", url)
+	}
+	return &manifest, nil
+}
+
+// contentLength determines the bundle size from the manifest or a HEAD request
+func (d *Downloader) contentLength(ctx context.Context, url string, manifest *ChunkManifest) (int64, error) {
+	if manifest != nil {
+		return manifest.Size, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request for %s: %w", url, err)
+	}
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch %s: %w", url, err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("registry returned %s for %s", resp.Status, url)
+	}
+	if resp.ContentLength < 0 {
+		return 0, fmt.Errorf("registry did not report a size for %s", url)
+	}
+	return resp.ContentLength, nil
+}
+
+// fetchRange downloads bytes [start, end) of url
+func (d *Downloader) fetchRange(ctx context.Context, url string, start, end int64, limiter *rateLimiter) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request for %s: %w", url, err)
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", start, end-1))
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	var body io.Reader = resp.Body
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+	case http.StatusOK:
+		// The server ignored the range; skip to the requested offset
+		if _, err := io.CopyN(io.Discard, resp.Body, start); err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", url, err)
+		}
+	default:
+		return nil, fmt.Errorf("registry returned %s for %s", resp.Status, url)
+	}
+
+	chunk := make([]byte, end-start)
+	if _, err := io.ReadFull(limiter.reader(ctx, body), chunk); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", url, err)
+	}
+	return chunk, nil
+}
+
+// resumeOffset returns where a partial download should continue. With a manifest,
+// existing chunks are re-verified and the file is truncated after the last good one;
+// without one, the offset is rounded down to a chunk boundary.
+func resumeOffset(part *os.File, manifest *ChunkManifest, chunkSize int64) (int64, error) {
+	info, err := part.Stat()
+	if err != nil {
+		return 0, fmt.Errorf("failed to stat partial download: %w", err)
+	}
+
+	offset := info.Size() / chunkSize * chunkSize
+	if manifest != nil {
+		offset = min(offset, manifest.Size)
+		buf := make([]byte, chunkSize)
+		for verified := int64(0); verified < offset; verified += chunkSize {
+			n := min(chunkSize, manifest.Size-verified)
+			if _, err := part.ReadAt(buf[:n], verified); err != nil {
+				return 0, fmt.Errorf("failed to read partial download: %w", err)
+			}
+			if !chunkMatches(buf[:n], manifest.Chunks[verified/chunkSize]) {
+				offset = verified
+				break
+			}
+		}
+	}
+
+	if err := part.Truncate(offset); err != nil {
+		return 0, fmt.Errorf("failed to truncate partial download: %w", err)
+	}
+	return offset, nil
+}
+
+func chunkMatches(chunk []byte, expected string) bool {
+	sum := sha256.Sum256(chunk)
+	return hex.EncodeToString(sum[:]) == expected
+}
+
+func verifyFile(file *os.File, expected string) error {
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return fmt.Errorf("failed to verify download: %w", err)
+	}
+	if hex.EncodeToString(hash.Sum(nil)) != expected {
+		return fmt.Errorf("bundle: %w", ErrChecksumMismatch)
+	}
+	return nil
+}
+
+// ParseByteSize parses sizes such as "512", "500K", "2M" or "1G" (binary multiples)
+func ParseByteSize(value string) (int64, error) {
+	s := strings.ToUpper(strings.TrimSpace(value))
+	s = strings.TrimSuffix(strings.TrimSuffix(s, "B"), "I")
+
+	multiplier := int64(1)
+	if s != "" {
+		switch s[len(s)-1] {
+		case 'K':
+			multiplier = 1 << 10
+		case 'M':
+			multiplier = 1 << 20
+		case 'G':
+			multiplier = 1 << 30
+		}
+		if multiplier > 1 {
+			s = s[:len(s)-1]
+		}
+	}
+
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size %q", value)
+	}
+	return int64(n * float64(multiplier)), nil
+}
+
+// rateLimiter caps average throughput across an entire download
+type rateLimiter struct {
+	bytesPerSecond int64
+	start          time.Time
+	transferred    int64
+}
+
+func newRateLimiter(bytesPerSecond int64) *rateLimiter {
+	return &rateLimiter{bytesPerSecond: bytesPerSecond, start: time.Now()}
+}
+
+// reader wraps r so reads are delayed to stay under the limit
+func (l *rateLimiter) reader(ctx context.Context, r io.Reader) io.Reader {
+	if l.bytesPerSecond <= 0 {
+		return r
+	}
+	return &limitedReader{ctx: ctx, r: r, limiter: l}
+}
+
+// wait blocks until n more bytes fit within the limit
+func (l *rateLimiter) wait(ctx context.Context, n int) error {
+	l.transferred += int64(n)
+	allowedAt := l.start.Add(time.Duration(float64(l.transferred) / float64(l.bytesPerSecond) * float64(time.Second)))
+	delay := time.Until(allowedAt)
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+type limitedReader struct {
+	ctx     context.Context
+	r       io.Reader
+	limiter *rateLimiter
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	// Read in small slices so throughput stays smooth on slow limits
+	if step := int(l.limiter.bytesPerSecond / 10); step > 0 && len(p) > step {
+		p = p[:step]
+	}
+	n, err := l.r.Read(p)
+	if n > 0 {
+		if waitErr := l.limiter.wait(l.ctx, n); waitErr != nil {
+			return n, waitErr
+		}
+	}
+	return n, err
+}
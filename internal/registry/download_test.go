@@ -0,0 +1,172 @@
+package registry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestBundleServer(t *testing.T, data []byte, manifest *ChunkManifest, ranges *int32) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, ChunkManifestSuffix) {
+			if manifest == nil {
+				http.NotFound(w, r)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(manifest)
+			return
+		}
+		if r.Header.Get("Range") != "" {
+			atomic.AddInt32(ranges, 1)
+		}
+		http.ServeContent(w, r, "bundle.tar.gz", time.Time{}, bytes.NewReader(data))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func testBundle(size int) []byte {
+	data := make([]byte, size)
+	for i := range data {
+		data[i] = byte(i % 251)
+	}
+	return data
+}
+
+func TestDownloader_Download(t *testing.T) {
+	ctx := context.Background()
+	data := testBundle(10_000)
+	var ranges int32
+	server := newTestBundleServer(t, data, NewChunkManifest(data, 1024), &ranges)
+
+	dest := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	var lastDone int64
+	result, err := NewDownloader().Download(ctx, server.URL+"/bundle.tar.gz", dest, DownloadOptions{
+		Progress: func(done, total int64) { lastDone = done },
+	})
+	require.NoError(t, err)
+
+	assert.True(t, result.Verified)
+	assert.Equal(t, int64(len(data)), result.Size)
+	assert.Equal(t, int64(len(data)), lastDone)
+	assert.Equal(t, int32(10), atomic.LoadInt32(&ranges))
+
+	written, err := os.ReadFile(dest)
+	require.NoError(t, err)
+	assert.Equal(t, data, written)
+	assert.NoFileExists(t, dest+PartialSuffix)
+}
+
+func TestDownloader_Resume(t *testing.T) {
+	ctx := context.Background()
+	data := testBundle(10_000)
+	var ranges int32
+	server := newTestBundleServer(t, data, NewChunkManifest(data, 1024), &ranges)
+
+	dest := filepath.Join(t.TempDir(), "bundle.tar.gz")
+
+	// Three good chunks, a corrupt fourth and a trailing fragment
+	partial := append([]byte{}, data[:4*1024+100]...)
+	partial[3*1024+10] ^= 0xff
+	require.NoError(t, os.WriteFile(dest+PartialSuffix, partial, 0644))
+
+	result, err := NewDownloader().Download(ctx, server.URL+"/bundle.tar.gz", dest, DownloadOptions{})
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(3*1024), result.Resumed)
+	assert.Equal(t, int32(7), atomic.LoadInt32(&ranges))
+
+	written, err := os.ReadFile(dest)
+	require.NoError(t, err)
+	assert.Equal(t, data, written)
+}
+
+func TestDownloader_ChecksumMismatch(t *testing.T) {
+	ctx := context.Background()
+	data := testBundle(4096)
+	manifest := NewChunkManifest(data, 1024)
+	manifest.Chunks[2] = strings.Repeat("0", 64)
+	var ranges int32
+	server := newTestBundleServer(t, data, manifest, &ranges)
+
+	dest := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	_, err := NewDownloader().Download(ctx, server.URL+"/bundle.tar.gz", dest, DownloadOptions{Retries: 2})
+	require.ErrorIs(t, err, ErrChecksumMismatch)
+
+	// Two verified chunks plus two attempts at the bad one; verified data is kept for resume
+	assert.Equal(t, int32(4), atomic.LoadInt32(&ranges))
+	assert.NoFileExists(t, dest)
+	info, err := os.Stat(dest + PartialSuffix)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2048), info.Size())
+}
+
+func TestDownloader_WithoutManifest(t *testing.T) {
+	ctx := context.Background()
+	data := testBundle(3000)
+	var ranges int32
+	server := newTestBundleServer(t, data, nil, &ranges)
+
+	dest := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	result, err := NewDownloader().Download(ctx, server.URL+"/bundle.tar.gz", dest, DownloadOptions{})
+	require.NoError(t, err)
+	assert.False(t, result.Verified)
+
+	written, err := os.ReadFile(dest)
+	require.NoError(t, err)
+	assert.Equal(t, data, written)
+}
+
+func TestDownloader_RateLimit(t *testing.T) {
+	ctx := context.Background()
+	data := testBundle(2000)
+	var ranges int32
+	server := newTestBundleServer(t, data, NewChunkManifest(data, 512), &ranges)
+
+	dest := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	start := time.Now()
+	_, err := NewDownloader().Download(ctx, server.URL+"/bundle.tar.gz", dest, DownloadOptions{RateLimit: 10_000})
+	require.NoError(t, err)
+
+	// 2000 bytes at 10KB/s takes at least ~200ms
+	assert.GreaterOrEqual(t, time.Since(start), 150*time.Millisecond)
+}
+
+func TestParseByteSize(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+		wantErr  bool
+	}{
+		{input: "512", expected: 512},
+		{input: "500K", expected: 500 << 10},
+		{input: "1.5M", expected: 3 << 19},
+		{input: "2MiB", expected: 2 << 20},
+		{input: "1g", expected: 1 << 30},
+		{input: "fast", wantErr: true},
+		{input: "-1K", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			size, err := ParseByteSize(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, size)
+		})
+	}
+}
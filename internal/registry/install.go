@@ -0,0 +1,313 @@
+package registry
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/user/gogo/internal/blueprints"
+	"github.com/user/gogo/internal/db"
+	"github.com/user/gogo/internal/git"
+	"github.com/user/gogo/internal/templates"
+)
+
+// InstallOptions contains options for installing templates
+type InstallOptions struct {
+	Ref    string // Git branch, tag or commit; may also be given as <url>@<ref>
+	SHA256 string // Expected SHA-256 of an archive
+	Name   string // Template name for sources holding a single template
+	Force  bool   // Replace installed templates and blueprints of the same name
+	Strict bool   // Refuse sources without a signature by a trusted key
+}
+
+// InstallResult describes an installation
+type InstallResult struct {
+	Source     db.InstallSource
+	Templates  []string
+	Blueprints []string
+	Signature  *VerifyResult // How the source was signed
+}
+
+// Installer installs templates and blueprints from git repositories and HTTPS
+// archives into the gogo database
+type Installer struct {
+	client     *Client
+	templates  *db.TemplateManager
+	blueprints *db.BlueprintManager
+	trust      *TrustStore
+	now        func() time.Time
+}
+
+// NewInstaller creates an installer fetching archives with client
+func NewInstaller(manager *db.Manager, client *Client) *Installer {
+	return &Installer{
+		client:     client,
+		templates:  db.NewTemplateManager(manager),
+		blueprints: db.NewBlueprintManager(manager),
+		trust:      NewTrustStore(manager),
+		now:        time.Now,
+	}
+}
+
+// Install installs the templates and blueprints of source, a git URL or an
+// HTTPS .tar.gz, .tgz or .zip archive. A source with templates/<name>/
+// directories or blueprints/<name>.yaml files is a pack installing each of
+// them; any other source is a single template.
+//
+// Git sources are pinned to the commit ref resolved to; archives are checked
+// against opts.SHA256 when set. Both are recorded with the installed entries.
+//
+// An archive is verified against the detached signature published next to
+// it at <url>.sig before anything is stored: a signature that does not verify
+// with a trusted key fails the install. Git sources carry no signature, so
+// opts.Strict refuses them along with unsigned archives.
+func (i *Installer) Install(ctx context.Context, source string, opts InstallOptions) (*InstallResult, error) {
+	sourceURL, ref := splitRef(source)
+	if opts.Ref != "" {
+		if ref != "" && ref != opts.Ref {
+			return nil, fmt.Errorf("%s conflicts with --ref %s", source, opts.Ref)
+		}
+		ref = opts.Ref
+	}
+	isGit, err := isGitSource(sourceURL)
+	if err != nil {
+		return nil, err
+	}
+
+	dir, err := os.MkdirTemp("", "gogo-install-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	trusted, err := i.trust.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	installed := db.InstallSource{URL: sourceURL, Ref: ref, InstalledAt: i.now().UTC()}
+	var (
+		root      string
+		signature *VerifyResult
+	)
+	if isGit {
+		if opts.SHA256 != "" {
+			return nil, fmt.Errorf("--sha256 verifies archives; pin git sources to a commit with --ref")
+		}
+		if signature, err = Verify(nil, nil, trusted, VerifyOptions{Strict: opts.Strict}); err != nil {
+			return nil, fmt.Errorf("%s: %w (git sources cannot be signed; install a signed archive)", sourceURL, err)
+		}
+		root = filepath.Join(dir, "repo")
+		if err := os.Mkdir(root, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create temporary directory: %w", err)
+		}
+		commit, err := git.NewGitManager(root).CloneAt(ctx, strings.TrimPrefix(sourceURL, "git+"), ref)
+		if err != nil {
+			return nil, err
+		}
+		installed.Commit = commit
+	} else {
+		if ref != "" {
+			return nil, fmt.Errorf("--ref only applies to git sources")
+		}
+		result, err := i.client.Fetch(ctx, sourceURL, FetchOptions{Force: true})
+		if err != nil {
+			return nil, err
+		}
+		sum := sha256.Sum256(result.Body)
+		installed.SHA256 = hex.EncodeToString(sum[:])
+		if opts.SHA256 != "" && !strings.EqualFold(opts.SHA256, installed.SHA256) {
+			return nil, fmt.Errorf("checksum mismatch for %s: expected %s, got %s", sourceURL, opts.SHA256, installed.SHA256)
+		}
+		sig, err := i.client.FetchSignature(ctx, sourceURL)
+		if err != nil {
+			return nil, err
+		}
+		if signature, err = Verify(result.Body, sig, trusted, VerifyOptions{Strict: opts.Strict}); err != nil {
+			return nil, fmt.Errorf("%s: %w", sourceURL, err)
+		}
+		root = filepath.Join(dir, "source"+archiveExt(sourceURL))
+		if err := os.WriteFile(root, result.Body, 0600); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", root, err)
+		}
+	}
+
+	files, err := templates.ReadPackFiles(root)
+	if err != nil {
+		return nil, err
+	}
+	storedTemplates, storedBlueprints, err := unpack(files, opts.Name, defaultTemplateName(sourceURL))
+	if err != nil {
+		return nil, err
+	}
+
+	// Check every entry first so a conflict installs nothing
+	if !opts.Force {
+		for _, template := range storedTemplates {
+			if _, err := i.templates.Get(ctx, template.Name); err == nil {
+				return nil, fmt.Errorf("%w: %s", db.ErrTemplateExists, template.Name)
+			}
+		}
+		for _, blueprint := range storedBlueprints {
+			if _, err := i.blueprints.Get(ctx, blueprint.Name); err == nil {
+				return nil, fmt.Errorf("%w: %s", db.ErrBlueprintExists, blueprint.Name)
+			}
+		}
+	}
+
+	result := &InstallResult{Source: installed, Signature: signature}
+	for _, template := range storedTemplates {
+		template.Source = &installed
+		if err := i.templates.Save(ctx, template, opts.Force); err != nil {
+			return nil, err
+		}
+		result.Templates = append(result.Templates, template.Name)
+	}
+	for _, blueprint := range storedBlueprints {
+		blueprint.Source = &installed
+		if err := i.blueprints.Save(ctx, blueprint, opts.Force); err != nil {
+			return nil, err
+		}
+		result.Blueprints = append(result.Blueprints, blueprint.Name)
+	}
+	return result, nil
+}
+
+// unpack splits the files of a source into templates and blueprints
+func unpack(files []templates.TemplateFile, name, defaultName string) ([]*db.StoredTemplate, []*db.StoredBlueprint, error) {
+	var (
+		byName       = make(map[string]*db.StoredTemplate)
+		blueprintSet []*db.StoredBlueprint
+		pack         bool
+	)
+	for _, file := range files {
+		dir, rest, _ := strings.Cut(file.Path, "/")
+		switch dir {
+		case templates.PackTemplatesDir:
+			templateName, templatePath, ok := strings.Cut(rest, "/")
+			if !ok {
+				continue
+			}
+			pack = true
+			if byName[templateName] == nil {
+				byName[templateName] = &db.StoredTemplate{Name: templateName}
+			}
+			byName[templateName].Files = append(byName[templateName].Files, db.StoredFile{Path: templatePath, Content: file.Content})
+		case templates.PackBlueprintsDir:
+			ext := path.Ext(rest)
+			if strings.Contains(rest, "/") || (ext != ".yaml" && ext != ".yml" && ext != ".json") {
+				continue
+			}
+			pack = true
+			blueprint, err := blueprints.Parse([]byte(file.Content))
+			if err != nil {
+				return nil, nil, fmt.Errorf("invalid blueprint %s: %w", file.Path, err)
+			}
+			if blueprint.Name != strings.TrimSuffix(rest, ext) {
+				return nil, nil, fmt.Errorf("blueprint %s is named %q", file.Path, blueprint.Name)
+			}
+			if err := blueprints.NewValidator().Validate(blueprint); err != nil {
+				return nil, nil, fmt.Errorf("invalid blueprint %s: %w", file.Path, err)
+			}
+			stored, err := blueprints.ToStored(blueprint)
+			if err != nil {
+				return nil, nil, err
+			}
+			blueprintSet = append(blueprintSet, stored)
+		}
+	}
+
+	if !pack {
+		if name == "" {
+			name = defaultName
+		}
+		template := &db.StoredTemplate{Name: name}
+		for _, file := range files {
+			template.Files = append(template.Files, db.StoredFile{Path: file.Path, Content: file.Content})
+		}
+		return []*db.StoredTemplate{template}, nil, nil
+	}
+	if name != "" {
+		return nil, nil, fmt.Errorf("--name only applies to sources holding a single template")
+	}
+
+	templateSet := make([]*db.StoredTemplate, 0, len(byName))
+	for _, template := range byName {
+		templateSet = append(templateSet, template)
+	}
+	sort.Slice(templateSet, func(i, j int) bool { return templateSet[i].Name < templateSet[j].Name })
+	return templateSet, blueprintSet, nil
+}
+
+// splitRef splits a trailing @ref off a source URL. The @ of user@host in
+// SSH URLs is left alone because a path follows it.
+func splitRef(source string) (string, string) {
+	at := strings.LastIndex(source, "@")
+	if at < 0 || at < strings.LastIndexAny(source, "/:") {
+		return source, ""
+	}
+	return source[:at], source[at+1:]
+}
+
+// isGitSource reports whether a source URL names a git repository rather
+// than an archive. Plain HTTP is refused because installed templates end up
+// in generated code.
+func isGitSource(sourceURL string) (bool, error) {
+	lower := strings.ToLower(sourceURL)
+	switch {
+	case strings.HasPrefix(lower, "git+"), strings.HasPrefix(lower, "git@"), strings.HasPrefix(lower, "ssh://"):
+		return true, nil
+	case strings.HasPrefix(lower, "https://"):
+		return archiveExt(sourceURL) == "", nil
+	case strings.HasPrefix(lower, "http://"):
+		return false, fmt.Errorf("refusing to install %s over plain HTTP", sourceURL)
+	default:
+		return false, fmt.Errorf("unsupported template source %s (use a git URL or an HTTPS .tar.gz, .tgz or .zip URL)", sourceURL)
+	}
+}
+
+// archiveExt returns the archive extension of a URL path, or ""
+func archiveExt(sourceURL string) string {
+	p := sourceURL
+	if parsed, err := url.Parse(sourceURL); err == nil {
+		p = parsed.Path
+	}
+	p = strings.ToLower(p)
+	for _, ext := range []string{".tar.gz", ".tgz", ".zip"} {
+		if strings.HasSuffix(p, ext) {
+			return ext
+		}
+	}
+	return ""
+}
+
+// defaultTemplateName derives a template name from the last URL path segment
+func defaultTemplateName(sourceURL string) string {
+	name := sourceURL
+	if parsed, err := url.Parse(sourceURL); err == nil && parsed.Path != "" {
+		name = parsed.Path
+	}
+	name = strings.TrimSuffix(name, "/")
+	if i := strings.LastIndexAny(name, "/:"); i >= 0 {
+		name = name[i+1:]
+	}
+	name = strings.TrimSuffix(name, ".git")
+	if ext := archiveExt(name); ext != "" {
+		name = name[:len(name)-len(ext)]
+	}
+	return strings.ToLower(name)
+}
+
+// IsConflict reports whether an install failed because an entry exists
+func IsConflict(err error) bool {
+	return errors.Is(err, db.ErrTemplateExists) || errors.Is(err, db.ErrBlueprintExists)
+}
@@ -0,0 +1,232 @@
+package registry
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/user/gogo/internal/db"
+	"github.com/user/gogo/internal/git"
+)
+
+func setupTestInstaller(t *testing.T) (*Installer, *db.Manager) {
+	manager := db.NewManager()
+	require.NoError(t, manager.Open(context.Background(), filepath.Join(t.TempDir(), "test.db")))
+	t.Cleanup(func() {
+		manager.Close()
+	})
+	return NewInstaller(manager, NewClient(NewCache(manager))), manager
+}
+
+func tarball(t *testing.T, entries map[string]string) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range entries {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+	return buf.Bytes()
+}
+
+func TestInstaller_Archive(t *testing.T) {
+	ctx := context.Background()
+	archive := tarball(t, map[string]string{
+		"worker-1.0/README.md.tmpl":   "# {{ ProjectName }}\n",
+		"worker-1.0/cmd/main.go.tmpl": "package main\n",
+	})
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, SignatureExtension) {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write(archive)
+	}))
+	t.Cleanup(server.Close)
+
+	installer, manager := setupTestInstaller(t)
+	installer.client.SetHTTPClient(server.Client())
+	url := server.URL + "/packs/worker-1.0.tar.gz"
+
+	_, err := installer.Install(ctx, url, InstallOptions{SHA256: strings.Repeat("0", 64)})
+	assert.ErrorContains(t, err, "checksum mismatch")
+
+	sum := sha256.Sum256(archive)
+	result, err := installer.Install(ctx, url, InstallOptions{SHA256: hex.EncodeToString(sum[:])})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"worker-1.0"}, result.Templates)
+
+	stored, err := db.NewTemplateManager(manager).Get(ctx, "worker-1.0")
+	require.NoError(t, err)
+	assert.Len(t, stored.Files, 2)
+	require.NotNil(t, stored.Source)
+	assert.Equal(t, url, stored.Source.URL)
+	assert.Equal(t, hex.EncodeToString(sum[:]), stored.Source.SHA256)
+
+	// Reinstalling needs force
+	_, err = installer.Install(ctx, url, InstallOptions{})
+	assert.True(t, IsConflict(err))
+	_, err = installer.Install(ctx, url, InstallOptions{Name: "worker", Force: true})
+	require.NoError(t, err)
+
+	_, err = installer.Install(ctx, url, InstallOptions{Ref: "v1"})
+	assert.ErrorContains(t, err, "only applies to git")
+}
+
+func TestInstaller_Signature(t *testing.T) {
+	ctx := context.Background()
+	archive := tarball(t, map[string]string{"worker/main.go.tmpl": "package main\n"})
+	pub, priv, err := GenerateKey()
+	require.NoError(t, err)
+	_, otherPriv, err := GenerateKey()
+	require.NoError(t, err)
+
+	var sig []byte
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case !strings.HasSuffix(r.URL.Path, SignatureExtension):
+			_, _ = w.Write(archive)
+		case sig == nil:
+			http.NotFound(w, r)
+		default:
+			_, _ = w.Write(sig)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	installer, manager := setupTestInstaller(t)
+	installer.client.SetHTTPClient(server.Client())
+	require.NoError(t, NewTrustStore(manager).Add(ctx, "acme", pub))
+	url := server.URL + "/packs/worker.tar.gz"
+	signWith := func(data []byte, key ed25519.PrivateKey) {
+		sig, err = Sign(data, key).Marshal()
+		require.NoError(t, err)
+	}
+
+	// Unsigned archives install unless strict
+	_, err = installer.Install(ctx, url, InstallOptions{Strict: true})
+	assert.ErrorIs(t, err, ErrUnsigned)
+	result, err := installer.Install(ctx, url, InstallOptions{})
+	require.NoError(t, err)
+	assert.False(t, result.Signature.Signed)
+
+	// A bad signature fails closed even when not strict
+	signWith([]byte("other bundle"), priv)
+	_, err = installer.Install(ctx, url, InstallOptions{Force: true})
+	assert.ErrorContains(t, err, "signature verification failed")
+	signWith(archive, otherPriv)
+	_, err = installer.Install(ctx, url, InstallOptions{Force: true})
+	assert.ErrorIs(t, err, ErrUntrustedKey)
+
+	signWith(archive, priv)
+	result, err = installer.Install(ctx, url, InstallOptions{Force: true, Strict: true})
+	require.NoError(t, err)
+	assert.True(t, result.Signature.Signed)
+	assert.Equal(t, "acme", result.Signature.KeyName)
+
+	_, err = installer.Install(ctx, "git+file:///nonexistent", InstallOptions{Strict: true})
+	assert.ErrorIs(t, err, ErrUnsigned)
+}
+
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(output))
+	return strings.TrimSpace(string(output))
+}
+
+func TestInstaller_GitPack(t *testing.T) {
+	if !git.IsGitInstalled() {
+		t.Skip("Git is not installed")
+	}
+	t.Setenv("GIT_AUTHOR_NAME", "Test Author")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Test Author")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+
+	ctx := context.Background()
+	repo := t.TempDir()
+	runGit(t, repo, "init", "-q", "-b", "main")
+	files := map[string]string{
+		"README.md":                     "Shared templates\n",
+		"templates/worker/main.go.tmpl": "package main // v1\n",
+		"templates/cron/main.go":        "package main\n",
+		"blueprints/queue-stack.yaml":   "name: queue-stack\nstack: microservice\nconfig:\n  components: [nats]\n",
+	}
+	for name, content := range files {
+		require.NoError(t, os.MkdirAll(filepath.Join(repo, filepath.Dir(name)), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(repo, name), []byte(content), 0644))
+	}
+	runGit(t, repo, "add", ".")
+	runGit(t, repo, "commit", "-q", "-m", "v1")
+	runGit(t, repo, "tag", "v1")
+	v1 := runGit(t, repo, "rev-parse", "HEAD")
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "templates", "worker", "main.go.tmpl"), []byte("package main // v2\n"), 0644))
+	runGit(t, repo, "commit", "-q", "-am", "v2")
+
+	installer, manager := setupTestInstaller(t)
+	result, err := installer.Install(ctx, "git+file://"+repo+"@v1", InstallOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"cron", "worker"}, result.Templates)
+	assert.Equal(t, []string{"queue-stack"}, result.Blueprints)
+	assert.Equal(t, v1, result.Source.Commit)
+	assert.Equal(t, "v1", result.Source.Ref)
+
+	worker, err := db.NewTemplateManager(manager).Get(ctx, "worker")
+	require.NoError(t, err)
+	assert.Equal(t, []db.StoredFile{{Path: "main.go", Content: "package main // v1\n"}}, worker.Files)
+	blueprint, err := db.NewBlueprintManager(manager).Get(ctx, "queue-stack")
+	require.NoError(t, err)
+	assert.Equal(t, "microservice", blueprint.Stack)
+	assert.Equal(t, v1, blueprint.Source.Commit)
+
+	_, err = installer.Install(ctx, "git+file://"+repo, InstallOptions{SHA256: "abc"})
+	assert.ErrorContains(t, err, "--ref")
+	_, err = installer.Install(ctx, "git+file://"+repo, InstallOptions{Name: "shared", Force: true})
+	assert.ErrorContains(t, err, "single template")
+}
+
+func TestInstallSources(t *testing.T) {
+	for source, want := range map[string][2]string{
+		"https://github.com/acme/templates@v1.2.0":  {"https://github.com/acme/templates", "v1.2.0"},
+		"git@github.com:acme/templates.git":         {"git@github.com:acme/templates.git", ""},
+		"git@github.com:acme/templates.git@main":    {"git@github.com:acme/templates.git", "main"},
+		"https://example.com/packs/web.tar.gz":      {"https://example.com/packs/web.tar.gz", ""},
+		"ssh://git@example.com/acme/templates@v2.0": {"ssh://git@example.com/acme/templates", "v2.0"},
+	} {
+		url, ref := splitRef(source)
+		assert.Equal(t, want, [2]string{url, ref}, source)
+	}
+
+	isGit, err := isGitSource("https://example.com/packs/web.tgz?token=x")
+	require.NoError(t, err)
+	assert.False(t, isGit)
+	isGit, err = isGitSource("https://github.com/acme/templates")
+	require.NoError(t, err)
+	assert.True(t, isGit)
+	_, err = isGitSource("http://example.com/packs/web.tar.gz")
+	assert.ErrorContains(t, err, "plain HTTP")
+	_, err = isGitSource("./templates")
+	assert.ErrorContains(t, err, "unsupported")
+
+	assert.Equal(t, "templates", defaultTemplateName("git@github.com:acme/templates.git"))
+	assert.Equal(t, "web", defaultTemplateName("https://example.com/packs/web.tar.gz?token=x"))
+}
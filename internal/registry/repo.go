@@ -0,0 +1,126 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/user/gogo/internal/db"
+	"github.com/user/gogo/internal/git"
+	"github.com/user/gogo/internal/templates"
+)
+
+// RepoSource is a template kept in a directory of a git repository, written
+// <repo>//<dir>@<ref> as in github.com/acme/templates//go/api@v1.2.0. The
+// repository is a git URL or a host/owner/repo path, fetched over HTTPS.
+type RepoSource struct {
+	URL string // Clone URL of the repository
+	Dir string // Slash-separated template directory, "" for the repository root
+	Ref string // Branch, tag or full commit; the remote's HEAD when empty
+}
+
+// ParseRepoSource parses a template source of the form <repo>[//<dir>][@<ref>]
+func ParseRepoSource(source string) (RepoSource, error) {
+	repo, ref := splitRef(source)
+	scheme := ""
+	if i := strings.Index(repo, "://"); i >= 0 {
+		scheme, repo = repo[:i+3], repo[i+3:]
+	}
+
+	var dir string
+	if i := strings.Index(repo, "//"); i >= 0 {
+		repo, dir = repo[:i], strings.Trim(repo[i+2:], "/")
+		if dir == "" || path.Clean(dir) != dir || dir == ".." || strings.HasPrefix(dir, "../") {
+			return RepoSource{}, fmt.Errorf("invalid template directory in %s", source)
+		}
+	}
+	repo = strings.TrimSuffix(repo, "/")
+
+	// host/owner/repo paths name an HTTPS repository, as in go get
+	if scheme == "" && !strings.HasPrefix(repo, "git@") {
+		host, _, _ := strings.Cut(repo, "/")
+		if !strings.Contains(strings.Trim(host, "."), ".") || strings.Count(repo, "/") < 2 {
+			return RepoSource{}, fmt.Errorf("unsupported template source %s (use <host>/<owner>/<repo>//<dir>@<ref> or a git URL)", source)
+		}
+		scheme = "https://"
+	}
+
+	url := scheme + repo
+	isGit, err := isGitSource(url)
+	if err != nil {
+		return RepoSource{}, err
+	}
+	if !isGit {
+		return RepoSource{}, fmt.Errorf("%s is an archive, not a git repository", url)
+	}
+	return RepoSource{URL: url, Dir: dir, Ref: ref}, nil
+}
+
+// IsRepoSource reports whether source reads as a template in a git
+// repository rather than a local directory or archive
+func IsRepoSource(source string) bool {
+	if _, err := os.Stat(source); err == nil {
+		return false
+	}
+	_, err := ParseRepoSource(source)
+	return err == nil
+}
+
+// String returns the source without its ref, as recorded with the template
+func (s RepoSource) String() string {
+	if s.Dir == "" {
+		return s.URL
+	}
+	return s.URL + "//" + s.Dir
+}
+
+// Name returns the default name of the template: its directory's name, or
+// the repository's for a template at the root
+func (s RepoSource) Name() string {
+	if s.Dir != "" {
+		return path.Base(s.Dir)
+	}
+	return defaultTemplateName(s.URL)
+}
+
+// FetchRepoTemplate shallow-clones the repository of source at its ref and
+// reads the template in its directory. The template's template.yaml is
+// checked, and the returned install source pins the commit the ref resolved
+// to.
+func FetchRepoTemplate(ctx context.Context, source RepoSource) ([]templates.TemplateFile, *db.InstallSource, error) {
+	dir, err := os.MkdirTemp("", "gogo-template-*")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	commit, err := git.NewGitManager(dir).ShallowCloneAt(ctx, strings.TrimPrefix(source.URL, "git+"), source.Ref)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root := filepath.Join(dir, filepath.FromSlash(source.Dir))
+	info, err := os.Stat(root)
+	if err != nil || !info.IsDir() {
+		return nil, nil, fmt.Errorf("%s has no directory %s at %s", source.URL, source.Dir, commit[:min(len(commit), 12)])
+	}
+	files, err := templates.ReadTemplateFiles(root)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, err := templates.ParseTemplateConfig(files); err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", source, err)
+	}
+
+	installed := &db.InstallSource{
+		URL:         source.String(),
+		Ref:         source.Ref,
+		Commit:      commit,
+		InstalledAt: time.Now().UTC(),
+	}
+	return files, installed, nil
+}
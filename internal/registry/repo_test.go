@@ -0,0 +1,107 @@
+package registry
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/user/gogo/internal/git"
+	"github.com/user/gogo/internal/templates"
+)
+
+func TestParseRepoSource(t *testing.T) {
+	for source, want := range map[string]RepoSource{
+		"github.com/acme/templates//templates/my-api@v1.2.0": {URL: "https://github.com/acme/templates", Dir: "templates/my-api", Ref: "v1.2.0"},
+		"github.com/acme/templates@main":                     {URL: "https://github.com/acme/templates", Ref: "main"},
+		"https://gitlab.com/acme/templates//api/":            {URL: "https://gitlab.com/acme/templates", Dir: "api"},
+		"git@github.com:acme/templates.git//api@v2":          {URL: "git@github.com:acme/templates.git", Dir: "api", Ref: "v2"},
+		"git+file:///srv/templates//go/api@3f2c1e9":          {URL: "git+file:///srv/templates", Dir: "go/api", Ref: "3f2c1e9"},
+	} {
+		got, err := ParseRepoSource(source)
+		require.NoError(t, err, source)
+		assert.Equal(t, want, got, source)
+	}
+
+	for source, message := range map[string]string{
+		"./templates/api":                         "unsupported",
+		"github.com/acme":                         "unsupported",
+		"github.com/acme/templates//../etc@v1":    "invalid template directory",
+		"github.com/acme/templates//a/./b":        "invalid template directory",
+		"http://github.com/acme/templates//api":   "plain HTTP",
+		"https://example.com/packs/web.tar.gz//x": "archive",
+	} {
+		_, err := ParseRepoSource(source)
+		assert.ErrorContains(t, err, message, source)
+	}
+
+	source := RepoSource{URL: "https://github.com/acme/templates", Dir: "templates/my-api"}
+	assert.Equal(t, "https://github.com/acme/templates//templates/my-api", source.String())
+	assert.Equal(t, "my-api", source.Name())
+	assert.Equal(t, "templates", RepoSource{URL: "https://github.com/acme/templates.git"}.Name())
+	assert.False(t, IsRepoSource(t.TempDir()))
+}
+
+func TestFetchRepoTemplate(t *testing.T) {
+	if !git.IsGitInstalled() {
+		t.Skip("Git is not installed")
+	}
+	t.Setenv("GIT_AUTHOR_NAME", "Test Author")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Test Author")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+
+	ctx := context.Background()
+	repo := t.TempDir()
+	runGit(t, repo, "init", "-q", "-b", "main")
+	files := map[string]string{
+		"README.md":                         "Shared templates\n",
+		"templates/my-api/template.yaml":    "base: api\n",
+		"templates/my-api/internal/log.go":  "package internal // v1\n",
+		"templates/broken/template.yaml":    "bsae: api\n",
+		"templates/broken/cmd/main.go.tmpl": "package main\n",
+	}
+	for name, content := range files {
+		require.NoError(t, os.MkdirAll(filepath.Join(repo, filepath.Dir(name)), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(repo, name), []byte(content), 0644))
+	}
+	runGit(t, repo, "add", ".")
+	runGit(t, repo, "commit", "-q", "-m", "v1")
+	runGit(t, repo, "tag", "-a", "v1.2.0", "-m", "v1.2.0")
+	v1 := runGit(t, repo, "rev-parse", "HEAD")
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "templates", "my-api", "internal", "log.go"), []byte("package internal // v2\n"), 0644))
+	runGit(t, repo, "commit", "-q", "-am", "v2")
+
+	// An annotated tag pins the commit it points to
+	source, err := ParseRepoSource("git+file://" + repo + "//templates/my-api@v1.2.0")
+	require.NoError(t, err)
+	got, installed, err := FetchRepoTemplate(ctx, source)
+	require.NoError(t, err)
+	assert.Equal(t, []templates.TemplateFile{
+		{Name: "log.go", Path: "internal/log.go", Content: "package internal // v1\n"},
+		{Name: "template.yaml", Path: "template.yaml", Content: "base: api\n"},
+	}, got)
+	assert.Equal(t, v1, installed.Commit)
+	assert.Equal(t, "v1.2.0", installed.Ref)
+	assert.Equal(t, "git+file://"+repo+"//templates/my-api", installed.URL)
+
+	// So does a full commit; without a ref the default branch is used
+	source.Ref = v1
+	_, installed, err = FetchRepoTemplate(ctx, source)
+	require.NoError(t, err)
+	assert.Equal(t, v1, installed.Commit)
+	source.Ref = ""
+	got, _, err = FetchRepoTemplate(ctx, source)
+	require.NoError(t, err)
+	assert.Equal(t, "package internal // v2\n", got[0].Content)
+
+	source.Dir = "templates/missing"
+	_, _, err = FetchRepoTemplate(ctx, source)
+	assert.ErrorContains(t, err, "has no directory templates/missing")
+	source.Dir = "templates/broken"
+	_, _, err = FetchRepoTemplate(ctx, source)
+	assert.ErrorContains(t, err, "invalid template.yaml")
+}
@@ -0,0 +1,238 @@
+package registry
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/user/gogo/internal/db"
+)
+
+const (
+	// SignatureAlgorithm is the only supported signature algorithm
+	SignatureAlgorithm = "ed25519"
+	// SignatureExtension is appended to a bundle path to locate its detached signature
+	SignatureExtension = ".sig"
+
+	trustedKeysScope = "trusted_keys"
+)
+
+var (
+	// ErrUnsigned is returned in strict mode when a bundle has no signature
+	ErrUnsigned = errors.New("bundle is not signed")
+	// ErrUntrustedKey is returned when a signature was made by a key that is not trusted
+	ErrUntrustedKey = errors.New("bundle is signed by an untrusted key")
+)
+
+// Signature is a detached signature for a template bundle
+type Signature struct {
+	Algorithm string `json:"algorithm"`
+	KeyID     string `json:"key_id"`
+	Signature string `json:"signature"`
+}
+
+// TrustedKey is a public key allowed to sign template bundles
+type TrustedKey struct {
+	Name      string
+	PublicKey ed25519.PublicKey
+}
+
+// VerifyOptions contains options for bundle verification
+type VerifyOptions struct {
+	Strict bool // Refuse unsigned bundles
+}
+
+// VerifyResult contains the outcome of a bundle verification
+type VerifyResult struct {
+	Signed  bool
+	KeyID   string
+	KeyName string
+}
+
+// GenerateKey generates a new signing key pair
+func GenerateKey() (ed25519.PublicKey, ed25519.PrivateKey, error) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate key: %w", err)
+	}
+	return pub, priv, nil
+}
+
+// KeyID returns a short stable identifier for a public key
+func KeyID(pub ed25519.PublicKey) string {
+	sum := sha256.Sum256(pub)
+	return hex.EncodeToString(sum[:8])
+}
+
+// EncodeKey encodes a public or private key for storage in a key file
+func EncodeKey(key []byte) string {
+	return base64.StdEncoding.EncodeToString(key)
+}
+
+// ParsePublicKey decodes a base64-encoded public key
+func ParsePublicKey(encoded string) (ed25519.PublicKey, error) {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key encoding: %w", err)
+	}
+	if len(raw) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid public key length: %d", len(raw))
+	}
+	return ed25519.PublicKey(raw), nil
+}
+
+// ParsePrivateKey decodes a base64-encoded private key
+func ParsePrivateKey(encoded string) (ed25519.PrivateKey, error) {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key encoding: %w", err)
+	}
+	if len(raw) != ed25519.PrivateKeySize {
+		return nil, fmt.Errorf("invalid private key length: %d", len(raw))
+	}
+	return ed25519.PrivateKey(raw), nil
+}
+
+// Sign creates a detached signature for bundle data
+func Sign(data []byte, priv ed25519.PrivateKey) *Signature {
+	pub := priv.Public().(ed25519.PublicKey)
+	return &Signature{
+		Algorithm: SignatureAlgorithm,
+		KeyID:     KeyID(pub),
+		Signature: base64.StdEncoding.EncodeToString(ed25519.Sign(priv, data)),
+	}
+}
+
+// Marshal encodes a signature for writing to a .sig file
+func (s *Signature) Marshal() ([]byte, error) {
+	return json.MarshalIndent(s, "", "  ")
+}
+
+// ParseSignature decodes the contents of a .sig file
+func ParseSignature(data []byte) (*Signature, error) {
+	var sig Signature
+	if err := json.Unmarshal(data, &sig); err != nil {
+		return nil, fmt.Errorf("invalid signature file: %w", err)
+	}
+	if sig.Algorithm != SignatureAlgorithm {
+		return nil, fmt.Errorf("unsupported signature algorithm: %s", sig.Algorithm)
+	}
+	return &sig, nil
+}
+
+// Verify checks bundle data against an optional signature and a set of trusted keys
+func Verify(data []byte, sig *Signature, trusted []TrustedKey, opts VerifyOptions) (*VerifyResult, error) {
+	if sig == nil {
+		if opts.Strict {
+			return nil, ErrUnsigned
+		}
+		return &VerifyResult{Signed: false}, nil
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(sig.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature encoding: %w", err)
+	}
+
+	for _, key := range trusted {
+		if KeyID(key.PublicKey) != sig.KeyID {
+			continue
+		}
+		if !ed25519.Verify(key.PublicKey, data, raw) {
+			return nil, fmt.Errorf("signature verification failed for key %s (%s)", key.Name, sig.KeyID)
+		}
+		return &VerifyResult{Signed: true, KeyID: sig.KeyID, KeyName: key.Name}, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrUntrustedKey, sig.KeyID)
+}
+
+// VerifyFile verifies a bundle on disk using its adjacent .sig file if present
+func VerifyFile(bundlePath string, trusted []TrustedKey, opts VerifyOptions) (*VerifyResult, error) {
+	data, err := os.ReadFile(bundlePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bundle: %w", err)
+	}
+
+	var sig *Signature
+	sigData, err := os.ReadFile(bundlePath + SignatureExtension)
+	switch {
+	case err == nil:
+		sig, err = ParseSignature(sigData)
+		if err != nil {
+			return nil, err
+		}
+	case !os.IsNotExist(err):
+		return nil, fmt.Errorf("failed to read signature: %w", err)
+	}
+
+	return Verify(data, sig, trusted, opts)
+}
+
+// TrustStore manages trusted signing keys in the gogo database
+type TrustStore struct {
+	db *db.Manager
+}
+
+// NewTrustStore creates a new trust store
+func NewTrustStore(manager *db.Manager) *TrustStore {
+	return &TrustStore{
+		db: manager,
+	}
+}
+
+// Add trusts a public key under a name
+func (t *TrustStore) Add(ctx context.Context, name string, pub ed25519.PublicKey) error {
+	query := `INSERT INTO configs (scope, key, value) VALUES (?, ?, ?)
+		ON CONFLICT(scope, key) DO UPDATE SET value = excluded.value`
+	if _, err := t.db.GetDB().ExecContext(ctx, query, trustedKeysScope, name, EncodeKey(pub)); err != nil {
+		return fmt.Errorf("failed to trust key %s: %w", name, err)
+	}
+	return nil
+}
+
+// Remove stops trusting the named key
+func (t *TrustStore) Remove(ctx context.Context, name string) error {
+	result, err := t.db.GetDB().ExecContext(ctx,
+		`DELETE FROM configs WHERE scope = ? AND key = ?`, trustedKeysScope, name)
+	if err != nil {
+		return fmt.Errorf("failed to remove key %s: %w", name, err)
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return fmt.Errorf("trusted key '%s' not found", name)
+	}
+	return nil
+}
+
+// List returns all trusted keys
+func (t *TrustStore) List(ctx context.Context) ([]TrustedKey, error) {
+	rows, err := t.db.GetDB().QueryContext(ctx,
+		`SELECT key, value FROM configs WHERE scope = ? ORDER BY key`, trustedKeysScope)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query trusted keys: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []TrustedKey
+	for rows.Next() {
+		var name, value string
+		if err := rows.Scan(&name, &value); err != nil {
+			return nil, fmt.Errorf("failed to scan trusted key: %w", err)
+		}
+		pub, err := ParsePublicKey(value)
+		if err != nil {
+			return nil, fmt.Errorf("trusted key %s is corrupted: %w", name, err)
+		}
+		keys = append(keys, TrustedKey{Name: name, PublicKey: pub})
+	}
+
+	return keys, rows.Err()
+}
@@ -0,0 +1,128 @@
+package registry
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	pub, priv, err := GenerateKey()
+	require.NoError(t, err)
+	otherPub, _, err := GenerateKey()
+	require.NoError(t, err)
+
+	data := []byte("template bundle contents")
+	sig := Sign(data, priv)
+	trusted := []TrustedKey{{Name: "author", PublicKey: pub}}
+
+	tests := []struct {
+		name        string
+		data        []byte
+		sig         *Signature
+		trusted     []TrustedKey
+		opts        VerifyOptions
+		expectError error
+		expectSign  bool
+	}{
+		{
+			name:       "valid signature",
+			data:       data,
+			sig:        sig,
+			trusted:    trusted,
+			expectSign: true,
+		},
+		{
+			name:        "tampered data",
+			data:        []byte("tampered bundle contents"),
+			sig:         sig,
+			trusted:     trusted,
+			expectError: assert.AnError,
+		},
+		{
+			name:        "untrusted key",
+			data:        data,
+			sig:         sig,
+			trusted:     []TrustedKey{{Name: "other", PublicKey: otherPub}},
+			expectError: ErrUntrustedKey,
+		},
+		{
+			name:    "unsigned bundle in permissive mode",
+			data:    data,
+			trusted: trusted,
+		},
+		{
+			name:        "unsigned bundle in strict mode",
+			data:        data,
+			trusted:     trusted,
+			opts:        VerifyOptions{Strict: true},
+			expectError: ErrUnsigned,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Verify(tt.data, tt.sig, tt.trusted, tt.opts)
+			if tt.expectError != nil {
+				require.Error(t, err)
+				if tt.expectError != assert.AnError {
+					assert.ErrorIs(t, err, tt.expectError)
+				}
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectSign, result.Signed)
+			if tt.expectSign {
+				assert.Equal(t, "author", result.KeyName)
+			}
+		})
+	}
+}
+
+func TestVerifyFile(t *testing.T) {
+	pub, priv, err := GenerateKey()
+	require.NoError(t, err)
+
+	bundlePath := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	data := []byte("bundle")
+	require.NoError(t, os.WriteFile(bundlePath, data, 0644))
+
+	trusted := []TrustedKey{{Name: "author", PublicKey: pub}}
+
+	_, err = VerifyFile(bundlePath, trusted, VerifyOptions{Strict: true})
+	assert.ErrorIs(t, err, ErrUnsigned)
+
+	sigData, err := Sign(data, priv).Marshal()
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(bundlePath+SignatureExtension, sigData, 0644))
+
+	result, err := VerifyFile(bundlePath, trusted, VerifyOptions{Strict: true})
+	require.NoError(t, err)
+	assert.True(t, result.Signed)
+	assert.Equal(t, KeyID(pub), result.KeyID)
+}
+
+func TestTrustStore(t *testing.T) {
+	ctx := context.Background()
+	cache := setupTestCache(t)
+	store := NewTrustStore(cache.db)
+
+	pub, _, err := GenerateKey()
+	require.NoError(t, err)
+
+	require.NoError(t, store.Add(ctx, "security-team", pub))
+
+	keys, err := store.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	assert.Equal(t, "security-team", keys[0].Name)
+	assert.Equal(t, pub, keys[0].PublicKey)
+
+	require.NoError(t, store.Remove(ctx, "security-team"))
+	assert.Error(t, store.Remove(ctx, "security-team"))
+}
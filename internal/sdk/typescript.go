@@ -0,0 +1,302 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/user/gogo/internal/templates"
+)
+
+// Spec source kinds for client generation
+const (
+	SourceOpenAPI = "openapi"
+	SourceProto   = "proto"
+)
+
+// ClientDir is where the TypeScript client package is written, relative to the project root
+const ClientDir = "clients/ts"
+
+// Config represents TypeScript client SDK generation options
+type Config struct {
+	ProjectName string
+	ModuleName  string
+	Source      string // openapi or proto
+	PackageName string // npm package name; defaults to @<owner>/<project>-client
+	SpecPath    string // spec location relative to the project root
+}
+
+// Generator handles TypeScript client SDK generation
+type Generator struct {
+	templateEngine templates.TemplateRenderer
+}
+
+// NewGenerator creates a new client SDK generator
+func NewGenerator() *Generator {
+	return &Generator{
+		templateEngine: templates.NewEngine(),
+	}
+}
+
+// SourceForTemplate returns the spec source used for a project template, or
+// an empty string when the template does not expose a network API
+func SourceForTemplate(template string) string {
+	switch template {
+	case "api", "microservice":
+		return SourceOpenAPI
+	case "grpc":
+		return SourceProto
+	default:
+		return ""
+	}
+}
+
+// Files returns the project-relative paths written by GenerateTypeScript for config
+func Files(config Config) []string {
+	config = withDefaults(config)
+	return []string{
+		config.SpecPath,
+		ClientDir + "/package.json",
+		ClientDir + "/tsconfig.json",
+		ClientDir + "/src/index.ts",
+		ClientDir + "/README.md",
+		".github/workflows/publish-ts-client.yml",
+	}
+}
+
+// GenerateTypeScript generates a TypeScript client package, a starter spec when
+// none exists, and a CI job that publishes the package on client release tags
+func (g *Generator) GenerateTypeScript(ctx context.Context, outputDir string, config Config) error {
+	config = withDefaults(config)
+	if config.Source != SourceOpenAPI && config.Source != SourceProto {
+		return fmt.Errorf("unsupported client source %q (expected %s or %s)", config.Source, SourceOpenAPI, SourceProto)
+	}
+
+	variables := map[string]any{
+		"ProjectName":  config.ProjectName,
+		"ModuleName":   config.ModuleName,
+		"PackageName":  config.PackageName,
+		"SpecPath":     config.SpecPath,
+		"IsOpenAPI":    config.Source == SourceOpenAPI,
+		"IsProto":      config.Source == SourceProto,
+		"ProtoPackage": strings.ReplaceAll(config.ProjectName, "-", "_"),
+	}
+
+	if err := g.generateSpec(ctx, outputDir, config, variables); err != nil {
+		return fmt.Errorf("failed to generate API spec: %w", err)
+	}
+
+	files := map[string]string{
+		"package.json":  packageJSONTemplate,
+		"tsconfig.json": tsconfigTemplate,
+		"src/index.ts":  indexTemplate,
+		"README.md":     readmeTemplate,
+	}
+	for name, template := range files {
+		outputPath := filepath.Join(outputDir, filepath.FromSlash(ClientDir), filepath.FromSlash(name))
+		if err := g.templateEngine.RenderToFile(ctx, template, variables, outputPath); err != nil {
+			return fmt.Errorf("failed to generate %s: %w", name, err)
+		}
+	}
+
+	workflowPath := filepath.Join(outputDir, ".github", "workflows", "publish-ts-client.yml")
+	if err := g.templateEngine.RenderToFile(ctx, publishWorkflowTemplate, variables, workflowPath); err != nil {
+		return fmt.Errorf("failed to generate publish workflow: %w", err)
+	}
+
+	return nil
+}
+
+// generateSpec writes a starter spec unless the project already has one
+func (g *Generator) generateSpec(ctx context.Context, outputDir string, config Config, variables map[string]any) error {
+	specPath := filepath.Join(outputDir, filepath.FromSlash(config.SpecPath))
+	if _, err := os.Stat(specPath); err == nil {
+		return nil
+	}
+
+	template := openAPISpecTemplate
+	if config.Source == SourceProto {
+		template = protoSpecTemplate
+	}
+	return g.templateEngine.RenderToFile(ctx, template, variables, specPath)
+}
+
+func withDefaults(config Config) Config {
+	if config.PackageName == "" {
+		config.PackageName = defaultPackageName(config.ProjectName, config.ModuleName)
+	}
+	if config.SpecPath == "" {
+		if config.Source == SourceProto {
+			name := strings.ReplaceAll(config.ProjectName, "-", "_")
+			config.SpecPath = fmt.Sprintf("proto/%s/v1/%s.proto", name, name)
+		} else {
+			config.SpecPath = "api/openapi.yaml"
+		}
+	}
+	return config
+}
+
+// defaultPackageName derives an npm package name, scoping it by the module owner when known
+func defaultPackageName(projectName, moduleName string) string {
+	name := strings.ToLower(projectName) + "-client"
+	parts := strings.Split(moduleName, "/")
+	if len(parts) >= 3 {
+		return "@" + strings.ToLower(parts[1]) + "/" + name
+	}
+	return name
+}
+
+const openAPISpecTemplate = `openapi: 3.0.3
+info:
+  title: {{ ProjectName }} API
+  version: 0.1.0
+paths:
+  /health:
+    get:
+      operationId: getHealth
+      summary: Service health
+      responses:
+        "200":
+          description: Service is healthy
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/Health"
+components:
+  schemas:
+    Health:
+      type: object
+      required: [status]
+      properties:
+        status:
+          type: string
+`
+
+const protoSpecTemplate = `syntax = "proto3";
+
+package {{ ProtoPackage }}.v1;
+
+option go_package = "{{ ModuleName }}/gen/{{ ProtoPackage }}/v1;{{ ProtoPackage }}v1";
+
+service HealthService {
+  rpc Check(CheckRequest) returns (CheckResponse);
+}
+
+message CheckRequest {}
+
+message CheckResponse {
+  string status = 1;
+}
+`
+
+const packageJSONTemplate = `{
+  "name": "{{ PackageName }}",
+  "version": "0.1.0",
+  "description": "TypeScript client for {{ ProjectName }}",
+  "type": "module",
+  "main": "dist/index.js",
+  "types": "dist/index.d.ts",
+  "files": ["dist"],
+  "scripts": {
+{% if IsOpenAPI %}    "generate": "openapi-typescript ../../{{ SpecPath }} -o src/schema.ts",
+{% else %}    "generate": "protoc --plugin=./node_modules/.bin/protoc-gen-ts_proto --ts_proto_out=src/gen --ts_proto_opt=esModuleInterop=true,outputServices=generic-definitions -I ../../proto ../../{{ SpecPath }}",
+{% endif %}    "build": "npm run generate && tsc",
+    "prepublishOnly": "npm run build"
+  },
+  "dependencies": {
+{% if IsOpenAPI %}    "openapi-fetch": "^0.12.0"
+{% else %}    "@bufbuild/protobuf": "^2.2.0"
+{% endif %}  },
+  "devDependencies": {
+{% if IsOpenAPI %}    "openapi-typescript": "^7.4.0",
+{% else %}    "ts-proto": "^2.2.0",
+{% endif %}    "typescript": "^5.6.0"
+  },
+  "publishConfig": {
+    "access": "public"
+  }
+}
+`
+
+const tsconfigTemplate = `{
+  "compilerOptions": {
+    "target": "ES2020",
+    "module": "ES2020",
+    "moduleResolution": "bundler",
+    "declaration": true,
+    "outDir": "dist",
+    "rootDir": "src",
+    "strict": true,
+    "skipLibCheck": true
+  },
+  "include": ["src"]
+}
+`
+
+const indexTemplate = `// TypeScript client for {{ ProjectName }}.
+// Types are generated from {{ SpecPath }} by "npm run generate".
+{% if IsOpenAPI %}import createClient, { type ClientOptions } from "openapi-fetch";
+import type { paths, components } from "./schema";
+
+export type { paths, components };
+export type Schemas = components["schemas"];
+
+/** Creates a typed client for the {{ ProjectName }} API. */
+export function create{{ ProjectName|capfirst }}Client(baseUrl: string, options: Omit<ClientOptions, "baseUrl"> = {}) {
+  return createClient<paths>({ ...options, baseUrl });
+}
+{% else %}export * from "./gen/{{ ProtoPackage }}/v1/{{ ProtoPackage }}";
+{% endif %}`
+
+const readmeTemplate = `# {{ PackageName }}
+
+TypeScript client for {{ ProjectName }}, generated from ` + "`{{ SpecPath }}`" + `.
+
+## Development
+
+` + "```sh" + `
+npm install
+npm run build
+` + "```" + `
+
+Regenerate the client whenever the spec changes. Releases are published to npm
+by the ` + "`publish-ts-client`" + ` workflow when a ` + "`ts-client-v*`" + ` tag is pushed.
+`
+
+const publishWorkflowTemplate = `name: Publish TypeScript client
+
+on:
+  push:
+    tags:
+      - "ts-client-v*"
+
+jobs:
+  publish:
+    runs-on: ubuntu-latest
+    defaults:
+      run:
+        working-directory: clients/ts
+    steps:
+      - uses: actions/checkout@v4
+{% if IsProto %}
+      - name: Install protoc
+        uses: arduino/setup-protoc@v3
+{% endif %}
+      - uses: actions/setup-node@v4
+        with:
+          node-version: 20
+          registry-url: https://registry.npmjs.org
+
+      - name: Install dependencies
+        run: npm install
+
+      - name: Build
+        run: npm run build
+
+      - name: Publish
+        run: npm publish
+        env:
+          NODE_AUTH_TOKEN: ${{ "{{" }} secrets.NPM_TOKEN {{ "}}" }}
+`
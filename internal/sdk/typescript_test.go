@@ -0,0 +1,73 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerator_GenerateTypeScript_OpenAPI(t *testing.T) {
+	tempDir := t.TempDir()
+	config := Config{ProjectName: "orders", ModuleName: "github.com/acme/orders", Source: SourceOpenAPI}
+
+	require.NoError(t, NewGenerator().GenerateTypeScript(context.Background(), tempDir, config))
+
+	for _, file := range Files(config) {
+		assert.FileExists(t, filepath.Join(tempDir, file))
+	}
+
+	data, err := os.ReadFile(filepath.Join(tempDir, "clients", "ts", "package.json"))
+	require.NoError(t, err)
+	var pkg map[string]any
+	require.NoError(t, json.Unmarshal(data, &pkg), "package.json must be valid JSON")
+	assert.Equal(t, "@acme/orders-client", pkg["name"])
+	assert.Contains(t, pkg["dependencies"], "openapi-fetch")
+
+	index, err := os.ReadFile(filepath.Join(tempDir, "clients", "ts", "src", "index.ts"))
+	require.NoError(t, err)
+	assert.Contains(t, string(index), "createOrdersClient")
+
+	workflow, err := os.ReadFile(filepath.Join(tempDir, ".github", "workflows", "publish-ts-client.yml"))
+	require.NoError(t, err)
+	assert.Contains(t, string(workflow), "${{ secrets.NPM_TOKEN }}")
+}
+
+func TestGenerator_GenerateTypeScript_Proto(t *testing.T) {
+	tempDir := t.TempDir()
+	config := Config{ProjectName: "billing-api", ModuleName: "github.com/acme/billing", Source: SourceProto}
+
+	require.NoError(t, NewGenerator().GenerateTypeScript(context.Background(), tempDir, config))
+
+	assert.FileExists(t, filepath.Join(tempDir, "proto", "billing_api", "v1", "billing_api.proto"))
+
+	data, err := os.ReadFile(filepath.Join(tempDir, "clients", "ts", "package.json"))
+	require.NoError(t, err)
+	var pkg map[string]any
+	require.NoError(t, json.Unmarshal(data, &pkg), "package.json must be valid JSON")
+	assert.Contains(t, pkg["devDependencies"], "ts-proto")
+}
+
+func TestGenerator_KeepsExistingSpec(t *testing.T) {
+	tempDir := t.TempDir()
+	specPath := filepath.Join(tempDir, "api", "openapi.yaml")
+	require.NoError(t, os.MkdirAll(filepath.Dir(specPath), 0755))
+	require.NoError(t, os.WriteFile(specPath, []byte("openapi: 3.1.0\n"), 0644))
+
+	config := Config{ProjectName: "orders", Source: SourceOpenAPI}
+	require.NoError(t, NewGenerator().GenerateTypeScript(context.Background(), tempDir, config))
+
+	data, err := os.ReadFile(specPath)
+	require.NoError(t, err)
+	assert.Equal(t, "openapi: 3.1.0\n", string(data))
+}
+
+func TestSourceForTemplate(t *testing.T) {
+	assert.Equal(t, SourceOpenAPI, SourceForTemplate("api"))
+	assert.Equal(t, SourceProto, SourceForTemplate("grpc"))
+	assert.Empty(t, SourceForTemplate("cli"))
+}
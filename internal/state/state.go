@@ -0,0 +1,87 @@
+// Package state decides where gogo keeps the files it records about a
+// project, such as its manifest and lockfile: in the project's directory, to
+// be committed with it, or in a shared store keyed by the project's module
+// path, when the org policy keeps them out of repositories.
+package state
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/user/gogo/internal/fsutil"
+	"github.com/user/gogo/internal/workspace"
+)
+
+// Store keeps project files outside of their repositories
+type Store interface {
+	Get(modulePath, name string) ([]byte, error) // nil when the project has no such file
+	Save(modulePath, name string, content []byte) error
+}
+
+var remote Store
+
+// Use keeps project files in store instead of project directories; nil
+// keeps them in project directories again
+func Use(store Store) {
+	remote = store
+}
+
+// Remote reports whether project files are kept in a store
+func Remote() bool {
+	return remote != nil
+}
+
+// Read returns the file name of the project in dir. With a store in use it
+// comes from the store, keyed by the module path of dir/go.mod; a project
+// the store has nothing for, such as one generated before the policy
+// changed, has its file read from dir. Missing files are reported with an
+// error satisfying os.IsNotExist.
+func Read(dir, name string) ([]byte, error) {
+	if remote != nil {
+		if modulePath, err := workspace.ModulePath(dir); err == nil {
+			content, err := remote.Get(modulePath, name)
+			if err != nil {
+				return nil, err
+			}
+			if content != nil {
+				return content, nil
+			}
+		}
+	}
+	return os.ReadFile(filepath.Join(dir, name))
+}
+
+// Write stores the file name of the project in dir, whose module path is
+// modulePath, or that of dir/go.mod when empty: in the store in use, or
+// else in dir
+func Write(dir, modulePath, name string, content []byte) error {
+	if remote == nil {
+		return fsutil.WriteFile(filepath.Join(dir, name), content, 0644)
+	}
+	if modulePath == "" {
+		var err error
+		if modulePath, err = workspace.ModulePath(dir); err != nil {
+			return fmt.Errorf("%s is kept in the gogo database by module path: %w", name, err)
+		}
+	}
+	return remote.Save(modulePath, name, content)
+}
+
+// Detach moves the file name of the project in dir to the store in use,
+// leaving no copy to be committed with the project. Without a store, the
+// file stays where it is.
+func Detach(dir, modulePath, name string) error {
+	if remote == nil {
+		return nil
+	}
+	path := filepath.Join(dir, name)
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if err := Write(dir, modulePath, name, content); err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
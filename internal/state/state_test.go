@@ -0,0 +1,74 @@
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryStore is a Store keeping files in a map
+type memoryStore map[string][]byte
+
+func (m memoryStore) Get(modulePath, name string) ([]byte, error) {
+	return m[modulePath+"/"+name], nil
+}
+
+func (m memoryStore) Save(modulePath, name string, content []byte) error {
+	m[modulePath+"/"+name] = content
+	return nil
+}
+
+func writeGoMod(t *testing.T, dir, modulePath string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module "+modulePath+"\n"), 0644))
+}
+
+func TestReadWrite_Files(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := Read(dir, "gogo.lock")
+	assert.True(t, os.IsNotExist(err))
+
+	require.NoError(t, Write(dir, "", "gogo.lock", []byte("sources: []\n")))
+	assert.FileExists(t, filepath.Join(dir, "gogo.lock"))
+	content, err := Read(dir, "gogo.lock")
+	require.NoError(t, err)
+	assert.Equal(t, "sources: []\n", string(content))
+
+	// Without a store, files stay in the project
+	require.NoError(t, Detach(dir, "", "gogo.lock"))
+	assert.FileExists(t, filepath.Join(dir, "gogo.lock"))
+}
+
+func TestReadWrite_Store(t *testing.T) {
+	store := memoryStore{}
+	Use(store)
+	defer Use(nil)
+	assert.True(t, Remote())
+
+	dir := t.TempDir()
+	writeGoMod(t, dir, "github.com/acme/orders")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".gogo.yaml"), []byte("template: api\n"), 0644))
+
+	// Files the store does not have yet are read from the project
+	content, err := Read(dir, ".gogo.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, "template: api\n", string(content))
+
+	require.NoError(t, Detach(dir, "", ".gogo.yaml"))
+	assert.NoFileExists(t, filepath.Join(dir, ".gogo.yaml"))
+	assert.Equal(t, "template: api\n", string(store["github.com/acme/orders/.gogo.yaml"]))
+
+	require.NoError(t, Write(dir, "", ".gogo.yaml", []byte("template: cli\n")))
+	content, err = Read(dir, ".gogo.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, "template: cli\n", string(content))
+	assert.NoFileExists(t, filepath.Join(dir, ".gogo.yaml"))
+
+	// Files are keyed by module path, which a project without go.mod lacks
+	err = Write(t.TempDir(), "", "gogo.lock", []byte("sources: []\n"))
+	assert.ErrorContains(t, err, "kept in the gogo database by module path")
+}
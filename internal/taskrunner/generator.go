@@ -0,0 +1,304 @@
+// Package taskrunner generates a project's Makefile, or Taskfile.yml for
+// Task, with targets matching its stack: build, test, lint and cover for
+// every project, run and dev for those with a main package, proto for gRPC
+// services, migrate-up and migrate-down for those with a database, and
+// docker-build and docker-run for those with a Dockerfile.
+package taskrunner
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/user/gogo/internal/templates"
+)
+
+// Task runners the targets are generated for
+const (
+	RunnerMake = "make"
+	RunnerTask = "task"
+)
+
+// Runners lists the supported task runners
+var Runners = []string{RunnerMake, RunnerTask}
+
+// Project-relative paths of the file of each task runner
+const (
+	MakefilePath = "Makefile"
+	TaskfilePath = "Taskfile.yml"
+)
+
+// Migration tools migrate-up and migrate-down run, selected by the
+// blueprint's database.migrations
+const (
+	MigrateGolangMigrate = "golang-migrate"
+	MigrateGoose         = "goose"
+	MigrateAtlas         = "atlas"
+)
+
+// MigrationsDir holds the SQL migrations, as written by gogo add migration
+const MigrationsDir = "migrations"
+
+// Config represents task runner file generation options
+type Config struct {
+	Runner        string // make or task, make when empty
+	ProjectName   string
+	HasMain       bool    // The project builds a binary from cmd/<ProjectName>
+	Proto         bool    // Generate Go code from proto/ with buf or protoc
+	HasDatabase   bool    // Run migrations against $DATABASE_URL
+	DatabaseType  string  // postgres, mysql or sqlite; postgres when empty
+	MigrationType string  // golang-migrate, goose or atlas; golang-migrate when empty
+	Docker        bool    // The project has a Dockerfile
+	DockerPort    int     // Port docker-run publishes; none when zero
+	LicenseReport bool    // Run scripts/licenses.sh
+	CoverageMin   float64 // Minimum test coverage cover enforces, as a fraction; 0.80 when zero
+}
+
+// Target is a make target or task: what it does, the targets it runs first
+// and its shell commands
+type Target struct {
+	Name        string
+	Description string
+	Deps        []string
+	Commands    []string
+}
+
+// Generator handles Makefile and Taskfile generation
+type Generator struct {
+	templateEngine templates.TemplateRenderer
+}
+
+// NewGenerator creates a new task runner file generator
+func NewGenerator() *Generator {
+	return &Generator{
+		templateEngine: templates.NewEngine(),
+	}
+}
+
+// ParseRunner validates a task runner name; empty means make
+func ParseRunner(name string) (string, error) {
+	runner := strings.ToLower(strings.TrimSpace(name))
+	if runner == "" {
+		return RunnerMake, nil
+	}
+	if !slices.Contains(Runners, runner) {
+		return "", fmt.Errorf("unknown task runner %q (valid: %s)", name, strings.Join(Runners, ", "))
+	}
+	return runner, nil
+}
+
+// File returns the project-relative path of the file of runner
+func File(runner string) string {
+	if runner == RunnerTask {
+		return TaskfilePath
+	}
+	return MakefilePath
+}
+
+// Files returns the project-relative paths written by Generate
+func Files(config Config) []string {
+	return []string{File(config.Runner)}
+}
+
+// Targets returns the targets of the project config describes, in the order
+// they are written
+func Targets(config Config) []Target {
+	binary := config.ProjectName
+	mainPath := "./cmd/" + config.ProjectName
+	coverage := config.CoverageMin
+	if coverage == 0 {
+		coverage = 0.80
+	}
+	percent := strconv.FormatFloat(coverage*100, 'f', -1, 64)
+
+	var targets []Target
+	if config.HasMain {
+		targets = append(targets, Target{
+			Name:        "build",
+			Description: "Build the binary",
+			Commands:    []string{fmt.Sprintf("go build -o %s %s", binary, mainPath)},
+		})
+	} else {
+		targets = append(targets, Target{
+			Name:        "build",
+			Description: "Build every package",
+			Commands:    []string{"go build ./..."},
+		})
+	}
+	targets = append(targets,
+		Target{
+			Name:        "test",
+			Description: "Run the tests",
+			Commands:    []string{"go test -v ./..."},
+		},
+		Target{
+			Name:        "lint",
+			Description: "Run golangci-lint",
+			Commands:    []string{"golangci-lint run ./..."},
+		},
+		Target{
+			Name:        "cover",
+			Description: fmt.Sprintf("Run the tests with coverage, failing below %s%%", percent),
+			Commands: []string{
+				"go test -coverprofile=coverage.out ./...",
+				fmt.Sprintf(`go tool cover -func=coverage.out | awk '/^total:/ { sub("%%", "", $3); print "total coverage: " $3 "%%"; if ($3 + 0 < %s) { print "below %s%%"; exit 1 } }'`, percent, percent),
+			},
+		},
+		Target{
+			Name:        "clean",
+			Description: "Remove build artifacts",
+			Commands:    []string{"go clean", fmt.Sprintf("rm -f %s coverage.out", binary)},
+		},
+	)
+	if config.HasMain {
+		targets = append(targets,
+			Target{
+				Name:        "run",
+				Description: "Build and run the binary",
+				Deps:        []string{"build"},
+				Commands:    []string{"./" + binary},
+			},
+			Target{
+				Name:        "dev",
+				Description: "Run from source",
+				Commands:    []string{"go run " + mainPath},
+			},
+		)
+	}
+	if config.Proto {
+		targets = append(targets, Target{
+			Name:        "proto",
+			Description: "Generate Go code next to the .proto files under proto/ with buf, or with protoc when buf is not installed",
+			Commands: []string{
+				"if command -v buf >/dev/null 2>&1; then buf generate; " +
+					"else protoc -I proto --go_out=proto --go_opt=paths=source_relative " +
+					"--go-grpc_out=proto --go-grpc_opt=paths=source_relative $(find proto -name '*.proto'); fi",
+			},
+		})
+	}
+	if config.HasDatabase {
+		up, down := migrateCommands(config)
+		targets = append(targets,
+			Target{
+				Name:        "migrate-up",
+				Description: "Apply the migrations under " + MigrationsDir + "/ to $DATABASE_URL",
+				Commands:    []string{up},
+			},
+			Target{
+				Name:        "migrate-down",
+				Description: "Roll back the last migration applied to $DATABASE_URL",
+				Commands:    []string{down},
+			},
+		)
+	}
+	if config.Docker {
+		run := "docker run --rm "
+		if config.DockerPort > 0 {
+			run += fmt.Sprintf("-p %d:%d ", config.DockerPort, config.DockerPort)
+		}
+		targets = append(targets,
+			Target{
+				Name:        "docker-build",
+				Description: "Build the container image",
+				Commands:    []string{fmt.Sprintf("docker build -t %s .", binary)},
+			},
+			Target{
+				Name:        "docker-run",
+				Description: "Run the container image",
+				Deps:        []string{"docker-build"},
+				Commands:    []string{run + binary},
+			},
+		)
+	}
+	targets = append(targets, Target{
+		Name:        "scaffold-check",
+		Description: "Fail when template-managed files were edited by hand; see drift_ignore in .gogo.yaml",
+		Commands:    []string{"gogo drift --fail-on-change"},
+	})
+	if config.LicenseReport {
+		targets = append(targets, Target{
+			Name:        "licenses",
+			Description: "Check third-party licenses and write third_party_licenses.csv",
+			Commands:    []string{"sh scripts/licenses.sh"},
+		})
+	}
+	return targets
+}
+
+// migrateCommands returns the commands applying and rolling back migrations
+// with the tool of config
+func migrateCommands(config Config) (string, string) {
+	switch config.MigrationType {
+	case MigrateGoose:
+		driver := config.DatabaseType
+		switch driver {
+		case "":
+			driver = "postgres"
+		case "sqlite":
+			driver = "sqlite3"
+		}
+		base := fmt.Sprintf(`goose -dir %s %s "$DATABASE_URL"`, MigrationsDir, driver)
+		return base + " up", base + " down"
+	case MigrateAtlas:
+		base := fmt.Sprintf(`atlas migrate %%s --dir file://%s --url "$DATABASE_URL"`, MigrationsDir)
+		return fmt.Sprintf(base, "apply"), fmt.Sprintf(base, "down") + ` --dev-url "$ATLAS_DEV_URL"`
+	default:
+		base := fmt.Sprintf(`migrate -path %s -database "$DATABASE_URL"`, MigrationsDir)
+		return base + " up", base + " down 1"
+	}
+}
+
+// makefileTemplate writes each target with its description as a comment;
+// commands are escaped for make
+const makefileTemplate = `.PHONY:{% for target in Targets %} {{ target.Name }}{% endfor %}
+{% for target in Targets %}
+# {{ target.Description|safe }}
+{{ target.Name }}:{% for dep in target.Deps %} {{ dep }}{% endfor %}
+{% for command in target.Commands %}	{{ command|safe }}
+{% endfor %}{% endfor %}`
+
+// taskfileTemplate writes each target as a task of a version 3 Taskfile;
+// descriptions and commands are quoted for YAML
+const taskfileTemplate = `version: '3'
+
+tasks:{% for target in Targets %}
+  {{ target.Name }}:
+    desc: {{ target.Description|safe }}{% if target.Deps %}
+    deps: [{% for dep in target.Deps %}{{ dep }}{% if not forloop.Last %}, {% endif %}{% endfor %}]{% endif %}
+    cmds:{% for command in target.Commands %}
+      - {{ command|safe }}{% endfor %}
+{% endfor %}`
+
+// Generate generates the Makefile or Taskfile.yml of the project
+func (g *Generator) Generate(ctx context.Context, outputDir string, config Config) error {
+	template := makefileTemplate
+	if config.Runner == RunnerTask {
+		template = taskfileTemplate
+	}
+	outputPath := filepath.Join(outputDir, File(config.Runner))
+	return g.templateEngine.RenderToFile(ctx, template, g.variables(config), outputPath)
+}
+
+// variables returns the template variables of config, its targets with the
+// commands escaped for the runner
+func (g *Generator) variables(config Config) map[string]any {
+	targets := Targets(config)
+	for i := range targets {
+		commands := make([]string, len(targets[i].Commands))
+		for j, command := range targets[i].Commands {
+			if config.Runner == RunnerTask {
+				commands[j] = strconv.Quote(command)
+			} else {
+				commands[j] = strings.ReplaceAll(command, "$", "$$")
+			}
+		}
+		targets[i].Commands = commands
+		if config.Runner == RunnerTask {
+			targets[i].Description = strconv.Quote(targets[i].Description)
+		}
+	}
+	return map[string]any{"Targets": targets}
+}
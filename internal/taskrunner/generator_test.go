@@ -0,0 +1,105 @@
+package taskrunner
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestParseRunner(t *testing.T) {
+	runner, err := ParseRunner("")
+	require.NoError(t, err)
+	assert.Equal(t, RunnerMake, runner)
+
+	runner, err = ParseRunner(" Task ")
+	require.NoError(t, err)
+	assert.Equal(t, RunnerTask, runner)
+	assert.Equal(t, TaskfilePath, File(runner))
+
+	_, err = ParseRunner("just")
+	assert.ErrorContains(t, err, "unknown task runner")
+}
+
+func TestTargets(t *testing.T) {
+	names := func(config Config) []string {
+		var names []string
+		for _, target := range Targets(config) {
+			names = append(names, target.Name)
+		}
+		return names
+	}
+
+	assert.Equal(t, []string{"build", "test", "lint", "cover", "clean", "scaffold-check"}, names(Config{ProjectName: "lib"}))
+	assert.Equal(t,
+		[]string{"build", "test", "lint", "cover", "clean", "run", "dev", "proto", "migrate-up", "migrate-down", "docker-build", "docker-run", "scaffold-check", "licenses"},
+		names(Config{ProjectName: "orders", HasMain: true, Proto: true, HasDatabase: true, Docker: true, LicenseReport: true}))
+
+	tests := []struct {
+		config   Config
+		up, down string
+	}{
+		{Config{}, `migrate -path migrations -database "$DATABASE_URL" up`, `migrate -path migrations -database "$DATABASE_URL" down 1`},
+		{Config{MigrationType: MigrateGoose, DatabaseType: "sqlite"}, `goose -dir migrations sqlite3 "$DATABASE_URL" up`, `goose -dir migrations sqlite3 "$DATABASE_URL" down`},
+		{Config{MigrationType: MigrateAtlas}, `atlas migrate apply --dir file://migrations --url "$DATABASE_URL"`, `atlas migrate down --dir file://migrations --url "$DATABASE_URL" --dev-url "$ATLAS_DEV_URL"`},
+	}
+	for _, tt := range tests {
+		up, down := migrateCommands(tt.config)
+		assert.Equal(t, tt.up, up)
+		assert.Equal(t, tt.down, down)
+	}
+}
+
+func TestGenerator_Generate(t *testing.T) {
+	config := Config{
+		ProjectName: "orders",
+		HasMain:     true,
+		HasDatabase: true,
+		Docker:      true,
+		DockerPort:  8080,
+		CoverageMin: 0.85,
+	}
+
+	t.Run("make", func(t *testing.T) {
+		tempDir := t.TempDir()
+		require.NoError(t, NewGenerator().Generate(context.Background(), tempDir, config))
+		assert.Equal(t, []string{MakefilePath}, Files(config))
+
+		makefile, err := os.ReadFile(filepath.Join(tempDir, MakefilePath))
+		require.NoError(t, err)
+		assert.Contains(t, string(makefile), ".PHONY: build test lint cover clean run dev migrate-up migrate-down docker-build docker-run scaffold-check\n")
+		assert.Contains(t, string(makefile), "\n# Build and run the binary\nrun: build\n\t./orders\n")
+		assert.Contains(t, string(makefile), "\tmigrate -path migrations -database \"$$DATABASE_URL\" up\n", "make needs $ escaped")
+		assert.Contains(t, string(makefile), "if ($$3 + 0 < 85)")
+		assert.Contains(t, string(makefile), "docker run --rm -p 8080:8080 orders")
+	})
+
+	t.Run("task", func(t *testing.T) {
+		tempDir := t.TempDir()
+		config := config
+		config.Runner = RunnerTask
+		require.NoError(t, NewGenerator().Generate(context.Background(), tempDir, config))
+		assert.Equal(t, []string{TaskfilePath}, Files(config))
+
+		data, err := os.ReadFile(filepath.Join(tempDir, TaskfilePath))
+		require.NoError(t, err)
+		var taskfile struct {
+			Version string
+			Tasks   map[string]struct {
+				Desc string
+				Deps []string
+				Cmds []string
+			}
+		}
+		require.NoError(t, yaml.Unmarshal(data, &taskfile), string(data))
+		assert.Equal(t, "3", taskfile.Version)
+		assert.Len(t, taskfile.Tasks, len(Targets(config)))
+		assert.Equal(t, []string{"build"}, taskfile.Tasks["run"].Deps)
+		assert.Equal(t, []string{`migrate -path migrations -database "$DATABASE_URL" up`}, taskfile.Tasks["migrate-up"].Cmds)
+		assert.Equal(t, "Run the tests with coverage, failing below 85%", taskfile.Tasks["cover"].Desc)
+	})
+}
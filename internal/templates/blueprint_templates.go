@@ -8,6 +8,56 @@ type BlueprintTemplateFile struct {
 	Requires []string // Required blueprint features/components
 }
 
+// observabilityServices are the docker-compose services of the observability
+// profile, started with docker compose --profile observability up. Their
+// configuration is generated under monitoring/ by the monitoring package.
+const observabilityServices = `{% if HasPrometheus %}
+
+  prometheus:
+    image: prom/prometheus:latest
+    profiles: ["observability"]
+    ports:
+      - "9090:9090"
+    volumes:
+      - ./monitoring/prometheus.yml:/etc/prometheus/prometheus.yml:ro
+      - ./monitoring/rules:/etc/prometheus/rules:ro
+    depends_on:
+      - {{ ProjectName }}
+
+  loki:
+    image: grafana/loki:latest
+    profiles: ["observability"]
+    command: -config.file=/etc/loki/local-config.yaml
+    ports:
+      - "3100:3100"
+
+  promtail:
+    image: grafana/promtail:latest
+    profiles: ["observability"]
+    command: -config.file=/etc/promtail/config.yml
+    volumes:
+      - ./monitoring/promtail.yml:/etc/promtail/config.yml:ro
+      - /var/run/docker.sock:/var/run/docker.sock:ro
+    depends_on:
+      - loki
+
+  grafana:
+    image: grafana/grafana:latest
+    profiles: ["observability"]
+    ports:
+      - "3000:3000"
+    environment:
+      - GF_AUTH_ANONYMOUS_ENABLED=true
+      - GF_AUTH_ANONYMOUS_ORG_ROLE=Viewer
+      - GF_DASHBOARDS_DEFAULT_HOME_DASHBOARD_PATH=/var/lib/grafana/dashboards/{{ ProjectName }}.json
+    volumes:
+      - ./monitoring/grafana/provisioning:/etc/grafana/provisioning:ro
+      - ./monitoring/grafana/dashboards:/var/lib/grafana/dashboards:ro
+    depends_on:
+      - prometheus
+      - loki
+{% endif %}`
+
 // GetBlueprintTemplates returns blueprint-aware template files for different stacks
 func GetBlueprintTemplates() map[string][]BlueprintTemplateFile {
 	templates := make(map[string][]BlueprintTemplateFile)
@@ -26,23 +76,116 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+{% if HasPrometheus and (IsGin or IsEcho or IsChi) %}
+	"strconv"
+{% endif %}
 	"syscall"
 	"time"
 {% if HasDatabase %}
 	"database/sql"
 	_ "github.com/lib/pq"
 {% endif %}
-{% if "gin" in Components %}
+{% if IsGin %}
 	"github.com/gin-gonic/gin"
+{% elif IsEcho %}
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+{% elif IsChi %}
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
 {% endif %}
 {% if "viper" in Components %}
 	"github.com/spf13/viper"
 {% endif %}
 {% if HasPrometheus %}
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 {% endif %}
 )
 
+{% if HasPrometheus %}
+var (
+	requestsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "{{ ProjectName }}_requests_total",
+			Help: "Total number of requests",
+		},
+		[]string{"method", "endpoint", "status"},
+	)
+
+	requestDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name: "{{ ProjectName }}_request_duration_seconds",
+			Help: "Request duration in seconds",
+		},
+		[]string{"method", "endpoint"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(requestsTotal)
+	prometheus.MustRegister(requestDuration)
+}
+{% if IsGin %}
+
+// metricsMiddleware records every request in requestsTotal and requestDuration
+func metricsMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		endpoint := c.FullPath()
+		if endpoint == "" {
+			endpoint = "unmatched"
+		}
+		requestsTotal.WithLabelValues(c.Request.Method, endpoint, strconv.Itoa(c.Writer.Status())).Inc()
+		requestDuration.WithLabelValues(c.Request.Method, endpoint).Observe(time.Since(start).Seconds())
+	}
+}
+{% elif IsEcho %}
+
+// metricsMiddleware records every request in requestsTotal and requestDuration
+func metricsMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		start := time.Now()
+		if err := next(c); err != nil {
+			// Let the error handler write the response so its status is recorded
+			c.Error(err)
+		}
+
+		endpoint := c.Path()
+		if endpoint == "" {
+			endpoint = "unmatched"
+		}
+		requestsTotal.WithLabelValues(c.Request().Method, endpoint, strconv.Itoa(c.Response().Status)).Inc()
+		requestDuration.WithLabelValues(c.Request().Method, endpoint).Observe(time.Since(start).Seconds())
+		return nil
+	}
+}
+{% elif IsChi %}
+
+// metricsMiddleware records every request in requestsTotal and requestDuration
+func metricsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(ww, r)
+
+		endpoint := chi.RouteContext(r.Context()).RoutePattern()
+		if endpoint == "" {
+			endpoint = "unmatched"
+		}
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+		requestsTotal.WithLabelValues(r.Method, endpoint, strconv.Itoa(status)).Inc()
+		requestDuration.WithLabelValues(r.Method, endpoint).Observe(time.Since(start).Seconds())
+	})
+}
+{% endif %}
+{% endif %}
+
 func main() {
 {% if "viper" in Components %}
 	// Load configuration
@@ -69,9 +212,12 @@ func main() {
 	}
 {% endif %}
 
-{% if "gin" in Components %}
+{% if IsGin %}
 	// Setup Gin router
 	r := gin.Default()
+{% if HasPrometheus %}
+	r.Use(metricsMiddleware())
+{% endif %}
 	
 	// Health check endpoint
 	r.GET("/health", func(c *gin.Context) {
@@ -96,6 +242,80 @@ func main() {
 		Addr:    fmt.Sprintf("%s:%s", viper.GetString("host"), viper.GetString("port")),
 		Handler: r,
 	}
+{% elif IsEcho %}
+	// Setup Echo router
+	e := echo.New()
+	e.HideBanner = true
+	e.Use(middleware.Logger())
+	e.Use(middleware.Recover())
+{% if HasPrometheus %}
+	e.Use(metricsMiddleware)
+{% endif %}
+
+	// Health check endpoint
+	e.GET("/health", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, map[string]string{"status": "ok", "service": "{{ ProjectName }}"})
+	})
+
+{% if HasPrometheus %}
+	// Prometheus metrics endpoint
+	e.GET("/metrics", echo.WrapHandler(promhttp.Handler()))
+{% endif %}
+
+	// API routes
+	v1 := e.Group("/api/v1")
+	v1.GET("/ping", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, map[string]string{"message": "pong"})
+	})
+
+	// Server configuration
+	srv := &http.Server{
+{% if "viper" in Components %}
+		Addr:    fmt.Sprintf("%s:%s", viper.GetString("host"), viper.GetString("port")),
+{% else %}
+		Addr:    ":8080",
+{% endif %}
+		Handler: e,
+	}
+{% elif IsChi %}
+	// Setup Chi router
+	r := chi.NewRouter()
+	r.Use(middleware.RequestID)
+	r.Use(middleware.RealIP)
+	r.Use(middleware.Logger)
+	r.Use(middleware.Recoverer)
+{% if HasPrometheus %}
+	r.Use(metricsMiddleware)
+{% endif %}
+
+	// Health check endpoint
+	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, ` + "`" + `{"status":"ok","service":"{{ ProjectName }}"}` + "`" + `)
+	})
+
+{% if HasPrometheus %}
+	// Prometheus metrics endpoint
+	r.Handle("/metrics", promhttp.Handler())
+{% endif %}
+
+	// API routes
+	r.Route("/api/v1", func(r chi.Router) {
+		r.Get("/ping", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, ` + "`" + `{"message":"pong"}` + "`" + `)
+		})
+	})
+
+	// Server configuration
+	srv := &http.Server{
+{% if "viper" in Components %}
+		Addr:    fmt.Sprintf("%s:%s", viper.GetString("host"), viper.GetString("port")),
+{% else %}
+		Addr:    ":8080",
+{% endif %}
+		Handler: r,
+	}
 {% else %}
 	// Basic HTTP server
 	mux := http.NewServeMux()
@@ -103,6 +323,11 @@ func main() {
 	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
 		fmt.Fprintf(w, ` + "`" + `{"status":"ok","service":"{{ ProjectName }}"}` + "`" + `)
 	})
+{% if HasPrometheus %}
+	
+	// Prometheus metrics endpoint
+	mux.Handle("/metrics", promhttp.Handler())
+{% endif %}
 	
 	srv := &http.Server{
 		Addr:    ":8080",
@@ -139,59 +364,32 @@ func main() {
 go {{ GoVersion }}
 
 require (
-{% if "gin" in Components %}
-	github.com/gin-gonic/gin v1.9.1
+{% if IsGin %}
+	github.com/gin-gonic/gin {{ "github.com/gin-gonic/gin"|version }}
+{% elif IsEcho %}
+	github.com/labstack/echo/v4 {{ "github.com/labstack/echo/v4"|version }}
+{% elif IsChi %}
+	github.com/go-chi/chi/v5 {{ "github.com/go-chi/chi/v5"|version }}
 {% endif %}
 {% if "viper" in Components %}
-	github.com/spf13/viper v1.16.0
+	github.com/spf13/viper {{ "github.com/spf13/viper"|version }}
 {% endif %}
 {% if HasDatabase %}
-	github.com/lib/pq v1.10.9
+	github.com/lib/pq {{ "github.com/lib/pq"|version }}
 {% endif %}
 {% if "gorm" in Components %}
-	gorm.io/gorm v1.25.4
-	gorm.io/driver/postgres v1.5.2
+	gorm.io/gorm {{ "gorm.io/gorm"|version }}
+	gorm.io/driver/postgres {{ "gorm.io/driver/postgres"|version }}
 {% endif %}
 {% if HasPrometheus %}
-	github.com/prometheus/client_golang v1.16.0
+	github.com/prometheus/client_golang {{ "github.com/prometheus/client_golang"|version }}
+{% endif %}
+{% if Environments %}
+	gopkg.in/yaml.v3 {{ "gopkg.in/yaml.v3"|version }}
 {% endif %}
 )`,
 			Requires: []string{},
 		},
-		{
-			Name: "Dockerfile",
-			Path: "Dockerfile",
-			Content: `# Build stage
-FROM {{ DockerBaseImage }}-alpine AS builder
-
-WORKDIR /app
-COPY go.mod go.sum ./
-RUN go mod download
-
-COPY . .
-RUN CGO_ENABLED=0 GOOS=linux go build -a -installsuffix cgo -o {{ ProjectName }} ./cmd/{{ ProjectName }}
-
-# Final stage
-FROM alpine:latest
-RUN apk --no-cache add ca-certificates
-WORKDIR /root/
-
-COPY --from=builder /app/{{ ProjectName }} .
-
-{% if HasDocker and "expose" in DockerBaseImage %}
-EXPOSE {{ DockerBaseImage.expose }}
-{% else %}
-EXPOSE 8080
-{% endif %}
-
-{% if HasDocker and "health_check" in DockerBaseImage %}
-HEALTHCHECK --interval=30s --timeout=3s --start-period=5s --retries=3 \
-  CMD curl -f http://localhost:8080/health || exit 1
-{% endif %}
-
-CMD ["./{{ ProjectName }}"]`,
-			Requires: []string{"HasDocker"},
-		},
 		{
 			Name: "docker-compose.yml",
 			Path: "docker-compose.yml",
@@ -235,6 +433,7 @@ services:
       - "16686:16686"
       - "14268:14268"
 {% endif %}
+` + observabilityServices + `
 
 {% if HasDatabase %}
 volumes:
@@ -351,10 +550,10 @@ go {{ GoVersion }}
 
 require (
 {% if "cobra" in Components %}
-	github.com/spf13/cobra v1.7.0
+	github.com/spf13/cobra {{ "github.com/spf13/cobra"|version }}
 {% endif %}
 {% if "viper" in Components %}
-	github.com/spf13/viper v1.16.0
+	github.com/spf13/viper {{ "github.com/spf13/viper"|version }}
 {% endif %}
 )`,
 			Requires: []string{},
@@ -437,27 +636,7 @@ func main() {
 		{
 			Name: "server.go",
 			Path: "internal/server/server.go",
-			Content: `package server
-
-import (
-	"google.golang.org/grpc"
-	
-	// Import your generated protobuf packages here
-	// pb "{{ ModuleName }}/proto/{{ ProjectName }}"
-)
-
-// RegisterServices registers all gRPC services
-func RegisterServices(s *grpc.Server) {
-	// Register your services here
-	// pb.Register{{ ProjectName }}ServiceServer(s, &{{ ProjectName }}Server{})
-}
-
-// {{ ProjectName }}Server implements the gRPC service
-type {{ ProjectName }}Server struct {
-	// Add your dependencies here
-}
-
-// Implement your gRPC methods here`,
+			Content: grpcServerFile,
 			Requires: []string{},
 		},
 		{
@@ -468,11 +647,11 @@ type {{ ProjectName }}Server struct {
 go {{ GoVersion }}
 
 require (
-	google.golang.org/grpc v1.58.0
-	google.golang.org/protobuf v1.31.0
+	google.golang.org/grpc {{ "google.golang.org/grpc"|version }}
+	google.golang.org/protobuf {{ "google.golang.org/protobuf"|version }}
 {% if HasTracing %}
-	github.com/opentracing/opentracing-go v1.2.0
-	github.com/uber/jaeger-client-go v2.30.0+incompatible
+	github.com/opentracing/opentracing-go {{ "github.com/opentracing/opentracing-go"|version }}
+	github.com/uber/jaeger-client-go {{ "github.com/uber/jaeger-client-go"|version }}
 {% endif %}
 )`,
 			Requires: []string{},
@@ -493,11 +672,20 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+{% if HasPrometheus and (IsGin or IsEcho or IsChi) %}
+	"strconv"
+{% endif %}
 	"syscall"
 	"time"
 	
-{% if "gin" in Components %}
+{% if IsGin %}
 	"github.com/gin-gonic/gin"
+{% elif IsEcho %}
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+{% elif IsChi %}
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
 {% endif %}
 {% if HasPrometheus %}
 	"github.com/prometheus/client_golang/prometheus"
@@ -533,6 +721,64 @@ func init() {
 	prometheus.MustRegister(requestsTotal)
 	prometheus.MustRegister(requestDuration)
 }
+{% if IsGin %}
+
+// metricsMiddleware records every request in requestsTotal and requestDuration
+func metricsMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		endpoint := c.FullPath()
+		if endpoint == "" {
+			endpoint = "unmatched"
+		}
+		requestsTotal.WithLabelValues(c.Request.Method, endpoint, strconv.Itoa(c.Writer.Status())).Inc()
+		requestDuration.WithLabelValues(c.Request.Method, endpoint).Observe(time.Since(start).Seconds())
+	}
+}
+{% elif IsEcho %}
+
+// metricsMiddleware records every request in requestsTotal and requestDuration
+func metricsMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		start := time.Now()
+		if err := next(c); err != nil {
+			// Let the error handler write the response so its status is recorded
+			c.Error(err)
+		}
+
+		endpoint := c.Path()
+		if endpoint == "" {
+			endpoint = "unmatched"
+		}
+		requestsTotal.WithLabelValues(c.Request().Method, endpoint, strconv.Itoa(c.Response().Status)).Inc()
+		requestDuration.WithLabelValues(c.Request().Method, endpoint).Observe(time.Since(start).Seconds())
+		return nil
+	}
+}
+{% elif IsChi %}
+
+// metricsMiddleware records every request in requestsTotal and requestDuration
+func metricsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(ww, r)
+
+		endpoint := chi.RouteContext(r.Context()).RoutePattern()
+		if endpoint == "" {
+			endpoint = "unmatched"
+		}
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+		requestsTotal.WithLabelValues(r.Method, endpoint, strconv.Itoa(status)).Inc()
+		requestDuration.WithLabelValues(r.Method, endpoint).Observe(time.Since(start).Seconds())
+	})
+}
+{% endif %}
 {% endif %}
 
 func main() {
@@ -551,8 +797,11 @@ func main() {
 	opentracing.SetGlobalTracer(tracer)
 {% endif %}
 
-{% if "gin" in Components %}
+{% if IsGin %}
 	r := gin.Default()
+{% if HasPrometheus %}
+	r.Use(metricsMiddleware())
+{% endif %}
 	
 	// Health check
 	r.GET("/health", func(c *gin.Context) {
@@ -574,6 +823,71 @@ func main() {
 	r.GET("/metrics", gin.WrapH(promhttp.Handler()))
 {% endif %}
 
+	srv := &http.Server{
+		Addr:    ":8080",
+		Handler: r,
+	}
+{% elif IsEcho %}
+	e := echo.New()
+	e.HideBanner = true
+	e.Use(middleware.Logger())
+	e.Use(middleware.Recover())
+{% if HasPrometheus %}
+	e.Use(metricsMiddleware)
+{% endif %}
+
+	// Health check
+	e.GET("/health", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, map[string]string{
+			"status":  "ok",
+			"service": "{{ ProjectName }}",
+			"version": "1.0.0",
+		})
+	})
+
+	// Readiness check
+	e.GET("/ready", func(c echo.Context) error {
+		// Add readiness checks here (database, dependencies, etc.)
+		return c.JSON(http.StatusOK, map[string]string{"status": "ready"})
+	})
+
+{% if HasPrometheus %}
+	// Metrics endpoint
+	e.GET("/metrics", echo.WrapHandler(promhttp.Handler()))
+{% endif %}
+
+	srv := &http.Server{
+		Addr:    ":8080",
+		Handler: e,
+	}
+{% elif IsChi %}
+	r := chi.NewRouter()
+	r.Use(middleware.RequestID)
+	r.Use(middleware.RealIP)
+	r.Use(middleware.Logger)
+	r.Use(middleware.Recoverer)
+{% if HasPrometheus %}
+	r.Use(metricsMiddleware)
+{% endif %}
+
+	// Health check
+	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, ` + "`" + `{"status":"ok","service":"{{ ProjectName }}","version":"1.0.0"}` + "`" + `)
+	})
+
+	// Readiness check
+	r.Get("/ready", func(w http.ResponseWriter, r *http.Request) {
+		// Add readiness checks here (database, dependencies, etc.)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, ` + "`" + `{"status":"ready"}` + "`" + `)
+	})
+
+{% if HasPrometheus %}
+	// Metrics endpoint
+	r.Handle("/metrics", promhttp.Handler())
+{% endif %}
+
 	srv := &http.Server{
 		Addr:    ":8080",
 		Handler: r,
@@ -584,6 +898,11 @@ func main() {
 	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
 		fmt.Fprintf(w, ` + "`" + `{"status":"ok","service":"{{ ProjectName }}","version":"1.0.0"}` + "`" + `)
 	})
+{% if HasPrometheus %}
+	
+	// Metrics endpoint
+	mux.Handle("/metrics", promhttp.Handler())
+{% endif %}
 	
 	srv := &http.Server{
 		Addr:    ":8080",
@@ -621,19 +940,891 @@ func main() {
 go {{ GoVersion }}
 
 require (
-{% if "gin" in Components %}
-	github.com/gin-gonic/gin v1.9.1
+{% if IsGin %}
+	github.com/gin-gonic/gin {{ "github.com/gin-gonic/gin"|version }}
+{% elif IsEcho %}
+	github.com/labstack/echo/v4 {{ "github.com/labstack/echo/v4"|version }}
+{% elif IsChi %}
+	github.com/go-chi/chi/v5 {{ "github.com/go-chi/chi/v5"|version }}
 {% endif %}
 {% if HasPrometheus %}
-	github.com/prometheus/client_golang v1.16.0
+	github.com/prometheus/client_golang {{ "github.com/prometheus/client_golang"|version }}
 {% endif %}
 {% if HasTracing %}
-	github.com/opentracing/opentracing-go v1.2.0
-	github.com/uber/jaeger-client-go v2.30.0+incompatible
+	github.com/opentracing/opentracing-go {{ "github.com/opentracing/opentracing-go"|version }}
+	github.com/uber/jaeger-client-go {{ "github.com/uber/jaeger-client-go"|version }}
+{% endif %}
+{% if Environments %}
+	gopkg.in/yaml.v3 {{ "gopkg.in/yaml.v3"|version }}
 {% endif %}
 )`,
 			Requires: []string{},
 		},
+		{
+			Name: "docker-compose.yml",
+			Path: "docker-compose.yml",
+			Content: `services:
+  {{ ProjectName }}:
+    build: .
+    ports:
+      - "8080:8080"
+    environment:
+      - PORT=8080
+{% if HasDatabase %}
+      - DATABASE_URL=postgres://postgres:password@db:5432/{{ ProjectName }}?sslmode=disable
+{% endif %}
+{% if HasTracing %}
+      - JAEGER_AGENT_HOST=jaeger
+      - JAEGER_SERVICE_NAME={{ ProjectName }}
+{% endif %}
+{% if HasDatabase or HasTracing %}
+    depends_on:
+{% if HasDatabase %}
+      - db
+{% endif %}
+{% if HasTracing %}
+      - jaeger
+{% endif %}
+{% endif %}
+{% if HasDatabase %}
+
+  db:
+    image: postgres:15-alpine
+    environment:
+      POSTGRES_DB: {{ ProjectName }}
+      POSTGRES_USER: postgres
+      POSTGRES_PASSWORD: password
+    ports:
+      - "5432:5432"
+    volumes:
+      - postgres_data:/var/lib/postgresql/data
+{% endif %}
+{% if HasTracing %}
+
+  jaeger:
+    image: jaegertracing/all-in-one:latest
+    ports:
+      - "16686:16686"
+      - "6831:6831/udp"
+{% endif %}
+` + observabilityServices + `
+{% if HasDatabase %}
+
+volumes:
+  postgres_data:
+{% endif %}`,
+			Requires: []string{},
+		},
+	}
+
+	// GraphQL stack templates. The gqlgen code of graph/ is generated with
+	// go generate ./... from the schema files.
+	templates["graphql"] = []BlueprintTemplateFile{
+		{
+			Name: "main.go",
+			Path: "cmd/{{ ProjectName }}/main.go",
+			Content: `package main
+
+import (
+	"context"
+	"errors"
+{% if HasSlog %}	"log/slog"
+{% else %}	"log"
+{% endif %}	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/99designs/gqlgen/graphql/playground"
+
+	"{{ ModuleName }}/graph"
+)
+
+func main() {
+{% if HasSlog %}	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	slog.SetDefault(logger)
+
+{% endif %}	srv := handler.New(graph.NewExecutableSchema(graph.Config{Resolvers: graph.NewResolver()}))
+	srv.AddTransport(transport.GET{})
+	srv.AddTransport(transport.POST{})
+	srv.Use(extension.Introspection{})
+
+	mux := http.NewServeMux()
+	mux.Handle("/", playground.Handler("{{ ProjectName }} GraphQL playground", "/query"))
+	mux.Handle("/query", srv)
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(` + "`" + `{"status":"ok"}` + "`" + `))
+	})
+
+	server := &http.Server{
+		Addr:    ":8080",
+		Handler: mux,
+	}
+
+	go func() {
+{% if HasSlog %}		slog.Info("{{ ProjectName }} GraphQL server listening", "addr", server.Addr)
+{% else %}		log.Printf("{{ ProjectName }} GraphQL server listening on %s", server.Addr)
+{% endif %}		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+{% if HasSlog %}			slog.Error("server failed", "error", err)
+{% else %}			log.Printf("server failed: %v", err)
+{% endif %}			os.Exit(1)
+		}
+	}()
+
+	// Graceful shutdown
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	<-sigChan
+
+{% if HasSlog %}	slog.Info("shutting down GraphQL server")
+{% else %}	log.Println("shutting down GraphQL server")
+{% endif %}	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+{% if HasSlog %}		slog.Error("shutdown failed", "error", err)
+{% else %}		log.Printf("shutdown failed: %v", err)
+{% endif %}	}
+}
+`,
+			Requires: []string{},
+		},
+		{
+			Name: "schema.graphqls",
+			Path: "graph/schema.graphqls",
+			Content: `# Schema of {{ ProjectName }}. Types added with gogo generate resolver live in
+# their own .graphqls files and extend Query and Mutation.
+
+type Query {
+  health: String!
+}
+
+type Mutation {
+  echo(message: String!): String!
+}
+`,
+			Requires: []string{},
+		},
+		{
+			Name: "resolver.go",
+			Path: "graph/resolver.go",
+			Content: `package graph
+
+//go:generate go run github.com/99designs/gqlgen generate
+
+// Resolver is the root resolver. It holds the dependencies of the
+// resolvers, such as stores and clients.
+type Resolver struct {
+}
+
+// NewResolver creates the root resolver
+func NewResolver() *Resolver {
+	return &Resolver{}
+}
+`,
+			Requires: []string{},
+		},
+		{
+			Name: "schema.resolvers.go",
+			Path: "graph/schema.resolvers.go",
+			Content: `package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version {{ "github.com/99designs/gqlgen"|version }}
+
+import (
+	"context"
+)
+
+// Echo is the resolver for the echo field.
+func (r *mutationResolver) Echo(ctx context.Context, message string) (string, error) {
+	return message, nil
+}
+
+// Health is the resolver for the health field.
+func (r *queryResolver) Health(ctx context.Context) (string, error) {
+	return "ok", nil
+}
+
+// Mutation returns MutationResolver implementation.
+func (r *Resolver) Mutation() MutationResolver { return &mutationResolver{r} }
+
+// Query returns QueryResolver implementation.
+func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
+
+type mutationResolver struct{ *Resolver }
+type queryResolver struct{ *Resolver }
+`,
+			Requires: []string{},
+		},
+		{
+			Name: "gqlgen.yml",
+			Path: "gqlgen.yml",
+			Content: `# gqlgen configuration, see https://gqlgen.com/config/
+schema:
+  - graph/*.graphqls
+
+exec:
+  filename: graph/generated.go
+  package: graph
+
+model:
+  filename: graph/model/models_gen.go
+  package: model
+
+resolver:
+  layout: follow-schema
+  dir: graph
+  package: graph
+  filename_template: "{name}.resolvers.go"
+`,
+			Requires: []string{},
+		},
+		{
+			Name: "tools.go",
+			Path: "tools.go",
+			Content: `//go:build tools
+
+// Package tools pins the code generators run by go generate
+package tools
+
+import (
+	_ "github.com/99designs/gqlgen"
+)
+`,
+			Requires: []string{},
+		},
+		{
+			Name: "go.mod",
+			Path: "go.mod",
+			Content: `module {{ ModuleName }}
+
+go {{ GoVersion }}
+
+require (
+	github.com/99designs/gqlgen {{ "github.com/99designs/gqlgen"|version }}
+	github.com/vektah/gqlparser/v2 {{ "github.com/vektah/gqlparser/v2"|version }}
+)`,
+			Requires: []string{},
+		},
+	}
+
+	// Worker stack templates. The queue the worker consumes is picked by the
+	// asynq, nats or kafka component; jobs are registered in internal/jobs.
+	templates["worker"] = []BlueprintTemplateFile{
+		{
+			Name: "main.go",
+			Path: "cmd/{{ ProjectName }}/main.go",
+			Content: `package main
+
+import (
+	"context"
+{% if HasSlog %}	"log/slog"
+{% else %}	"log"
+{% endif %}	"os"
+	"os/signal"
+	"syscall"
+
+	"{{ ModuleName }}/internal/jobs"
+	"{{ ModuleName }}/internal/worker"
+)
+
+func main() {
+{% if HasSlog %}	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	slog.SetDefault(logger)
+
+{% endif %}	// Stop taking new jobs on SIGINT or SIGTERM; jobs already running finish
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	mux := worker.NewMux(worker.DefaultRetryPolicy)
+	jobs.Register(mux)
+
+	consumer := worker.NewConsumer(worker.ConfigFromEnv())
+{% if HasSlog %}	slog.Info("{{ ProjectName }} worker starting", "queue", worker.Queue, "jobs", mux.Types())
+{% else %}	log.Printf("{{ ProjectName }} worker starting on %s with jobs %v", worker.Queue, mux.Types())
+{% endif %}	if err := consumer.Run(ctx, mux); err != nil {
+{% if HasSlog %}		slog.Error("worker failed", "error", err)
+{% else %}		log.Printf("worker failed: %v", err)
+{% endif %}		os.Exit(1)
+	}
+{% if HasSlog %}	slog.Info("{{ ProjectName }} worker stopped")
+{% else %}	log.Printf("{{ ProjectName }} worker stopped")
+{% endif %}
+}
+`,
+			Requires: []string{},
+		},
+		{
+			Name: "worker.go",
+			Path: "internal/worker/worker.go",
+			Content: `package worker
+
+import (
+	"context"
+	"fmt"
+{% if HasSlices %}	"slices"
+{% else %}	"sort"
+{% endif %})
+
+// Handler processes the payload of a job. A returned error retries the job
+// with backoff; wrap errors a retry cannot fix with Permanent.
+type Handler func(ctx context.Context, payload []byte) error
+
+// Mux routes jobs to their handlers by job type
+type Mux struct {
+	policy   RetryPolicy
+	handlers map[string]Handler
+}
+
+// NewMux creates a mux retrying failed jobs with policy
+func NewMux(policy RetryPolicy) *Mux {
+	return &Mux{
+		policy:   policy,
+		handlers: make(map[string]Handler),
+	}
+}
+
+// Handle registers the handler of a job type
+func (m *Mux) Handle(jobType string, handler Handler) {
+	m.handlers[jobType] = handler
+}
+
+// Types returns the registered job types in order
+func (m *Mux) Types() []string {
+	types := make([]string, 0, len(m.handlers))
+	for jobType := range m.handlers {
+		types = append(types, jobType)
+	}
+{% if HasSlices %}	slices.Sort(types)
+{% else %}	sort.Strings(types)
+{% endif %}	return types
+}
+
+// Process runs the handler of a job, retrying it as the retry policy says.
+// A started attempt runs to completion after ctx is done, but no further
+// attempt is made.
+func (m *Mux) Process(ctx context.Context, jobType string, payload []byte) error {
+	return m.policy.Do(ctx, func(ctx context.Context) error {
+		return m.run(ctx, jobType, payload)
+	})
+}
+
+// run makes a single attempt at a job
+func (m *Mux) run(ctx context.Context, jobType string, payload []byte) error {
+	handler, ok := m.handlers[jobType]
+	if !ok {
+		return Permanent(fmt.Errorf("no handler for job type %q", jobType))
+	}
+{% if HasWithoutCancel %}	return handler(context.WithoutCancel(ctx), payload)
+{% else %}	// Without context.WithoutCancel, before Go 1.21, the attempt cannot
+	// keep the values of ctx
+	return handler(context.Background(), payload)
+{% endif %}}
+`,
+			Requires: []string{},
+		},
+		{
+			Name: "retry.go",
+			Path: "internal/worker/retry.go",
+			Content: `package worker
+
+import (
+	"context"
+	"errors"
+{% if HasRandV2 %}	"math/rand/v2"
+{% else %}	"math/rand"
+{% endif %}	"time"
+)
+
+// RetryPolicy retries failed jobs with exponential backoff and jitter
+type RetryPolicy struct {
+	MaxAttempts int           // Attempts at a job including the first; 1 disables retries
+	BaseDelay   time.Duration // Delay before the first retry, doubled for each one after
+	MaxDelay    time.Duration // Cap on the delay between attempts
+}
+
+// DefaultRetryPolicy makes five attempts over about fifteen seconds
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 5,
+	BaseDelay:   time.Second,
+	MaxDelay:    time.Minute,
+}
+
+// Backoff returns the delay after the nth failed attempt, with up to a fifth
+// of it added as jitter so failed jobs do not retry in lockstep
+func (p RetryPolicy) Backoff(n int) time.Duration {
+	delay := p.MaxDelay
+	if n < 32 && p.BaseDelay<<(n-1) < p.MaxDelay {
+		delay = p.BaseDelay << (n - 1)
+	}
+	if delay <= 0 {
+		return 0
+	}
+{% if HasRandV2 %}	return delay + rand.N(delay/5+1)
+{% else %}	return delay + time.Duration(rand.Int63n(int64(delay/5+1)))
+{% endif %}}
+
+// Do calls fn until it succeeds, fails permanently or runs out of attempts,
+// or ctx is done while waiting to retry. It returns the last error.
+func (p RetryPolicy) Do(ctx context.Context, fn func(context.Context) error) error {
+	for attempt := 1; ; attempt++ {
+		err := fn(ctx)
+		if err == nil || IsPermanent(err) || attempt >= p.MaxAttempts {
+			return err
+		}
+
+		timer := time.NewTimer(p.Backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return errors.Join(err, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+// permanentError marks an error retrying cannot fix
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent marks err as one a retry cannot fix, such as a malformed
+// payload, so the job fails without further attempts
+func Permanent(err error) error {
+	return &permanentError{err: err}
+}
+
+// IsPermanent reports whether err was marked with Permanent
+func IsPermanent(err error) bool {
+	var permanent *permanentError
+	return errors.As(err, &permanent)
+}
+`,
+			Requires: []string{},
+		},
+		{
+			Name: "consumer.go",
+			Path: "internal/worker/consumer.go",
+			Content: `package worker
+{% if IsNATS %}
+import (
+	"context"
+	"errors"
+	"fmt"
+{% if HasSlog %}	"log/slog"
+{% else %}	"log"
+{% endif %}	"os"
+	"strings"
+
+	"github.com/nats-io/nats.go"
+)
+
+// Queue names the message queue the worker consumes
+const Queue = "nats"
+
+// Config configures the NATS consumer
+type Config struct {
+	URL        string // NATS server URL
+	Subject    string // Jobs are published to <Subject>.<job type>
+	QueueGroup string // Workers in a queue group share the jobs
+}
+
+// ConfigFromEnv reads the consumer configuration from NATS_URL, JOBS_SUBJECT
+// and JOBS_QUEUE_GROUP
+func ConfigFromEnv() Config {
+	return Config{
+		URL:        getenv("NATS_URL", "nats://localhost:4222"),
+		Subject:    getenv("JOBS_SUBJECT", "jobs"),
+		QueueGroup: getenv("JOBS_QUEUE_GROUP", "{{ ProjectName }}"),
+	}
+}
+
+// Consumer takes jobs from a NATS queue group. Core NATS does not redeliver
+// messages, so a job that fails all its attempts is logged and dropped; use
+// JetStream where jobs must survive failures.
+type Consumer struct {
+	config Config
+}
+
+// NewConsumer creates a consumer
+func NewConsumer(config Config) *Consumer {
+	return &Consumer{config: config}
+}
+
+// Run processes jobs until ctx is done, then drains the subscription so jobs
+// already received finish before it returns
+func (c *Consumer) Run(ctx context.Context, mux *Mux) error {
+	closed := make(chan struct{})
+	conn, err := nats.Connect(c.config.URL,
+		nats.Name("{{ ProjectName }}"),
+		nats.ClosedHandler(func(*nats.Conn) { close(closed) }),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to connect to NATS: %w", err)
+	}
+
+	prefix := c.config.Subject + "."
+	_, err = conn.QueueSubscribe(prefix+">", c.config.QueueGroup, func(msg *nats.Msg) {
+		jobType := strings.TrimPrefix(msg.Subject, prefix)
+		if err := mux.Process(ctx, jobType, msg.Data); err != nil {
+{% if HasSlog %}			slog.Error("job failed", "type", jobType, "error", err)
+{% else %}			log.Printf("job %s failed: %v", jobType, err)
+{% endif %}		}
+	})
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to subscribe to %s>: %w", prefix, err)
+	}
+
+	<-ctx.Done()
+	if err := conn.Drain(); err != nil && !errors.Is(err, nats.ErrConnectionClosed) {
+		return fmt.Errorf("failed to drain NATS connection: %w", err)
+	}
+	<-closed
+	return nil
+}
+{% elif IsKafka %}
+import (
+	"context"
+	"fmt"
+{% if HasSlog %}	"log/slog"
+{% else %}	"log"
+{% endif %}	"os"
+	"strings"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// Queue names the message queue the worker consumes
+const Queue = "kafka"
+
+// Config configures the Kafka consumer
+type Config struct {
+	Brokers []string // Kafka broker addresses
+	Topic   string   // Topic of the jobs, keyed by job type
+	GroupID string   // Consumer group sharing the topic's partitions
+}
+
+// ConfigFromEnv reads the consumer configuration from KAFKA_BROKERS (comma
+// separated), JOBS_TOPIC and JOBS_GROUP_ID
+func ConfigFromEnv() Config {
+	return Config{
+		Brokers: strings.Split(getenv("KAFKA_BROKERS", "localhost:9092"), ","),
+		Topic:   getenv("JOBS_TOPIC", "jobs"),
+		GroupID: getenv("JOBS_GROUP_ID", "{{ ProjectName }}"),
+	}
+}
+
+// Consumer takes jobs from a Kafka topic in a consumer group, committing each
+// job once it is processed. A job that fails all its attempts is logged and
+// committed so it does not hold up its partition.
+type Consumer struct {
+	config Config
+}
+
+// NewConsumer creates a consumer
+func NewConsumer(config Config) *Consumer {
+	return &Consumer{config: config}
+}
+
+// Run processes jobs until ctx is done. A job interrupted while waiting to
+// retry is left uncommitted, so the group delivers it again.
+func (c *Consumer) Run(ctx context.Context, mux *Mux) error {
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers: c.config.Brokers,
+		Topic:   c.config.Topic,
+		GroupID: c.config.GroupID,
+	})
+	defer reader.Close()
+
+	for {
+		msg, err := reader.FetchMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("failed to fetch job: %w", err)
+		}
+
+		jobType := string(msg.Key)
+		if err := mux.Process(ctx, jobType, msg.Value); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+{% if HasSlog %}			slog.Error("job failed", "type", jobType, "partition", msg.Partition, "offset", msg.Offset, "error", err)
+{% else %}			log.Printf("job %s at partition %d offset %d failed: %v", jobType, msg.Partition, msg.Offset, err)
+{% endif %}		}
+{% if HasWithoutCancel %}		if err := reader.CommitMessages(context.WithoutCancel(ctx), msg); err != nil {
+{% else %}		if err := reader.CommitMessages(context.Background(), msg); err != nil {
+{% endif %}
+			return fmt.Errorf("failed to commit job: %w", err)
+		}
+	}
+}
+{% else %}
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/hibiken/asynq"
+)
+
+// Queue names the message queue the worker consumes
+const Queue = "asynq"
+
+// Config configures the asynq consumer
+type Config struct {
+	RedisAddr   string // Address of the Redis server backing the queue
+	Concurrency int    // Jobs processed at once
+}
+
+// ConfigFromEnv reads the consumer configuration from REDIS_ADDR and
+// WORKER_CONCURRENCY
+func ConfigFromEnv() Config {
+	concurrency, err := strconv.Atoi(os.Getenv("WORKER_CONCURRENCY"))
+	if err != nil || concurrency <= 0 {
+		concurrency = 10
+	}
+	return Config{
+		RedisAddr:   getenv("REDIS_ADDR", "localhost:6379"),
+		Concurrency: concurrency,
+	}
+}
+
+// Consumer takes jobs from asynq queues in Redis. asynq retries failed jobs
+// itself, waiting as the mux's retry policy says; the number of retries is
+// set per task when it is enqueued.
+type Consumer struct {
+	config Config
+}
+
+// NewConsumer creates a consumer
+func NewConsumer(config Config) *Consumer {
+	return &Consumer{config: config}
+}
+
+// Run processes jobs until ctx is done, then waits for the jobs in progress
+// to finish
+func (c *Consumer) Run(ctx context.Context, mux *Mux) error {
+	server := asynq.NewServer(asynq.RedisClientOpt{Addr: c.config.RedisAddr}, asynq.Config{
+		Concurrency: c.config.Concurrency,
+		RetryDelayFunc: func(retried int, err error, task *asynq.Task) time.Duration {
+			return mux.policy.Backoff(retried + 1)
+		},
+	})
+
+	handlers := asynq.NewServeMux()
+	for _, jobType := range mux.Types() {
+		handlers.HandleFunc(jobType, func(ctx context.Context, task *asynq.Task) error {
+			err := mux.run(ctx, task.Type(), task.Payload())
+			if IsPermanent(err) {
+				return fmt.Errorf("%w: %w", err, asynq.SkipRetry)
+			}
+			return err
+		})
+	}
+
+	if err := server.Start(handlers); err != nil {
+		return fmt.Errorf("failed to start asynq server: %w", err)
+	}
+	<-ctx.Done()
+	server.Shutdown()
+	return nil
+}
+{% endif %}
+func getenv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+`,
+			Requires: []string{},
+		},
+		{
+			Name: "retry_test.go",
+			Path: "internal/worker/retry_test.go",
+			Content: `package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 5, BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+	tests := []struct {
+		attempt int
+		base    time.Duration
+	}{
+		{1, 100 * time.Millisecond},
+		{2, 200 * time.Millisecond},
+		{3, 400 * time.Millisecond},
+		{5, time.Second},
+		{40, time.Second},
+	}
+	for _, tt := range tests {
+		delay := policy.Backoff(tt.attempt)
+		if delay < tt.base || delay > tt.base+tt.base/5 {
+			t.Errorf("Backoff(%d) = %v, want %v plus at most 20%% jitter", tt.attempt, delay, tt.base)
+		}
+	}
+}
+
+func TestRetryPolicy_Do(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+	failure := errors.New("unavailable")
+
+	t.Run("retries until success", func(t *testing.T) {
+		calls := 0
+		err := policy.Do(context.Background(), func(context.Context) error {
+			calls++
+			if calls < 3 {
+				return failure
+			}
+			return nil
+		})
+		if err != nil || calls != 3 {
+			t.Errorf("Do() = %v after %d calls, want nil after 3", err, calls)
+		}
+	})
+
+	t.Run("gives up after max attempts", func(t *testing.T) {
+		calls := 0
+		err := policy.Do(context.Background(), func(context.Context) error {
+			calls++
+			return failure
+		})
+		if !errors.Is(err, failure) || calls != 3 {
+			t.Errorf("Do() = %v after %d calls, want %v after 3", err, calls, failure)
+		}
+	})
+
+	t.Run("does not retry permanent errors", func(t *testing.T) {
+		calls := 0
+		err := policy.Do(context.Background(), func(context.Context) error {
+			calls++
+			return Permanent(failure)
+		})
+		if !IsPermanent(err) || !errors.Is(err, failure) || calls != 1 {
+			t.Errorf("Do() = %v after %d calls, want permanent %v after 1", err, calls, failure)
+		}
+	})
+}
+
+func TestMux_Process(t *testing.T) {
+	mux := NewMux(RetryPolicy{MaxAttempts: 1})
+	var got string
+	mux.Handle("greet", func(ctx context.Context, payload []byte) error {
+		got = string(payload)
+		return nil
+	})
+
+	if err := mux.Process(context.Background(), "greet", []byte("hello")); err != nil || got != "hello" {
+		t.Errorf("Process() = %v with payload %q, want nil with %q", err, got, "hello")
+	}
+	if err := mux.Process(context.Background(), "unknown", nil); !IsPermanent(err) {
+		t.Errorf("Process() of an unknown job type = %v, want a permanent error", err)
+	}
+}
+`,
+			Requires: []string{},
+		},
+		{
+			Name: "jobs.go",
+			Path: "internal/jobs/jobs.go",
+			Content: `// Package jobs holds the handlers of the jobs the worker processes.
+// Scaffold one with gogo generate job <name>.
+package jobs
+
+import "{{ ModuleName }}/internal/worker"
+
+// Register registers the handler of each job type with mux
+func Register(mux *worker.Mux) {
+}
+`,
+			Requires: []string{},
+		},
+		{
+			Name: "go.mod",
+			Path: "go.mod",
+			Content: `module {{ ModuleName }}
+
+go {{ GoVersion }}
+
+require (
+{% if IsNATS %}
+	github.com/nats-io/nats.go {{ "github.com/nats-io/nats.go"|version }}
+{% elif IsKafka %}
+	github.com/segmentio/kafka-go {{ "github.com/segmentio/kafka-go"|version }}
+{% else %}
+	github.com/hibiken/asynq {{ "github.com/hibiken/asynq"|version }}
+{% endif %}
+)`,
+			Requires: []string{},
+		},
+		{
+			Name: "docker-compose.yml",
+			Path: "docker-compose.yml",
+			Content: `services:
+  {{ ProjectName }}:
+    build: .
+    environment:
+{% if IsNATS %}
+      - NATS_URL=nats://nats:4222
+    depends_on:
+      - nats
+
+  nats:
+    image: nats:2-alpine
+    ports:
+      - "4222:4222"
+{% elif IsKafka %}
+      - KAFKA_BROKERS=kafka:9092
+    depends_on:
+      - kafka
+
+  kafka:
+    image: apache/kafka:3.8.0
+    ports:
+      - "9092:9092"
+    environment:
+      KAFKA_NODE_ID: 1
+      KAFKA_PROCESS_ROLES: broker,controller
+      KAFKA_LISTENERS: PLAINTEXT://:9092,CONTROLLER://:9093
+      KAFKA_ADVERTISED_LISTENERS: PLAINTEXT://kafka:9092
+      KAFKA_CONTROLLER_LISTENER_NAMES: CONTROLLER
+      KAFKA_LISTENER_SECURITY_PROTOCOL_MAP: CONTROLLER:PLAINTEXT,PLAINTEXT:PLAINTEXT
+      KAFKA_CONTROLLER_QUORUM_VOTERS: 1@kafka:9093
+      KAFKA_OFFSETS_TOPIC_REPLICATION_FACTOR: 1
+{% else %}
+      - REDIS_ADDR=redis:6379
+    depends_on:
+      - redis
+
+  redis:
+    image: redis:7-alpine
+    ports:
+      - "6379:6379"
+{% endif %}
+`,
+			Requires: []string{},
+		},
 	}
 
 	return templates
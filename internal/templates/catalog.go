@@ -0,0 +1,25 @@
+package templates
+
+import (
+	"fmt"
+
+	"github.com/flosch/pongo2/v6"
+	"github.com/user/gogo/internal/catalog"
+)
+
+func init() {
+	pongo2.RegisterFilter("version", filterVersion)
+}
+
+// filterVersion returns the version the dependency catalog pins the module
+// path it filters to, failing the render for modules it does not list
+func filterVersion(in *pongo2.Value, param *pongo2.Value) (*pongo2.Value, *pongo2.Error) {
+	version, ok := catalog.Current().Lookup(in.String())
+	if !ok {
+		return nil, &pongo2.Error{
+			Sender:    "filter:version",
+			OrigError: fmt.Errorf("module %s is not in the dependency catalog", in.String()),
+		}
+	}
+	return pongo2.AsValue(version), nil
+}
@@ -0,0 +1,23 @@
+package templates
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/user/gogo/internal/catalog"
+)
+
+func TestFilterVersion(t *testing.T) {
+	t.Cleanup(func() { catalog.Use(nil) })
+	catalog.Use(&catalog.Catalog{Modules: map[string]string{"github.com/gin-gonic/gin": "v1.10.0"}})
+	engine := NewEngine()
+
+	result, err := engine.RenderString(context.Background(), `require github.com/gin-gonic/gin {{ "github.com/gin-gonic/gin"|version }}`, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "require github.com/gin-gonic/gin v1.10.0", result)
+
+	_, err = engine.RenderString(context.Background(), `{{ "example.com/unknown"|version }}`, nil)
+	assert.ErrorContains(t, err, "module example.com/unknown is not in the dependency catalog")
+}
@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 
 	"github.com/flosch/pongo2/v6"
+	"github.com/user/gogo/internal/fsutil"
 )
 
 // Template represents a template with metadata
@@ -16,6 +17,7 @@ type Template struct {
 	Kind        string
 	Content     string
 	MetadataJSON string
+	NextSteps   string // Shown after gogo init; rendered with the project variables
 }
 
 // TemplateRenderer interface for rendering templates
@@ -33,27 +35,32 @@ func NewEngine() *Engine {
 	return &Engine{}
 }
 
-// RenderString renders a template string with variables
+// RenderString renders a template string with variables. Failures are
+// *RenderError, locating them in the template.
 func (e *Engine) RenderString(ctx context.Context, template string, variables map[string]any) (string, error) {
 	tpl, err := pongo2.FromString(template)
 	if err != nil {
-		return "", fmt.Errorf("failed to parse template: %w", err)
+		return "", newRenderError(PhaseParse, template, variables, err)
 	}
 
 	result, err := tpl.Execute(variables)
 	if err != nil {
-		return "", fmt.Errorf("failed to execute template: %w", err)
+		return "", newRenderError(PhaseExecute, template, variables, err)
 	}
 
 	return result, nil
 }
 
-// RenderToFile renders a template string to a file
+// RenderToFile renders a template string to a file. The file is replaced
+// atomically, so a failed or interrupted render never leaves it half written.
 func (e *Engine) RenderToFile(ctx context.Context, template string, variables map[string]any, outputPath string) error {
 	result, err := e.RenderString(ctx, template, variables)
 	if err != nil {
 		return err
 	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 
 	// Ensure directory exists
 	dir := filepath.Dir(outputPath)
@@ -62,7 +69,7 @@ func (e *Engine) RenderToFile(ctx context.Context, template string, variables ma
 	}
 
 	// Write file
-	if err := os.WriteFile(outputPath, []byte(result), 0644); err != nil {
+	if err := fsutil.WriteFile(outputPath, []byte(result), 0644); err != nil {
 		return fmt.Errorf("failed to write file %s: %w", outputPath, err)
 	}
 
@@ -125,3 +125,25 @@ func TestTemplateEngine_RenderTemplate(t *testing.T) {
 	expected := "package main\n\nfunc main() {\n\tprintln(\"My CLI App\")\n}"
 	assert.Equal(t, expected, string(content))
 }
+
+func BenchmarkEngine_RenderString(b *testing.B) {
+	engine := NewEngine()
+	ctx := context.Background()
+	template := `package {{ PackageName }}
+
+{% for name in Components %}// {{ name|title }} is wired by {{ ProjectName }}
+{% endfor %}{% if HasDatabase %}import "database/sql"{% endif %}`
+	variables := map[string]any{
+		"PackageName": "bench",
+		"ProjectName": "bench",
+		"Components":  []string{"gin", "gorm", "viper", "prometheus"},
+		"HasDatabase": true,
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := engine.RenderString(ctx, template, variables); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package templates
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/flosch/pongo2/v6"
+)
+
+// Phases of rendering a RenderError can fail in
+const (
+	PhaseParse   = "parse"
+	PhaseExecute = "execute"
+)
+
+// snippetContext is how many lines around the failing one a RenderError shows
+const snippetContext = 2
+
+// maxVariables bounds the variables a RenderError lists
+const maxVariables = 12
+
+// RenderError is a template that failed to parse or execute, with where in
+// the template it failed and what it was rendered with
+type RenderError struct {
+	Name      string // Template, such as the file it renders; set with Named
+	Phase     string // PhaseParse or PhaseExecute
+	Line      int    // 1-based line of the failure, zero when unknown
+	Column    int
+	Near      string   // Token the failure was found at
+	Snippet   string   // Lines around Line, the failing one marked with >
+	Variables []string // Summary of the variables, name=value, sorted by name
+	Err       error
+}
+
+// newRenderError describes err, returned by pongo2 for template rendered
+// with variables
+func newRenderError(phase, template string, variables map[string]any, err error) *RenderError {
+	renderErr := &RenderError{
+		Phase:     phase,
+		Variables: summarizeVariables(variables),
+		Err:       err,
+	}
+	var pongoErr *pongo2.Error
+	if errors.As(err, &pongoErr) {
+		renderErr.Line = pongoErr.Line
+		renderErr.Column = pongoErr.Column
+		if pongoErr.Token != nil {
+			renderErr.Near = pongoErr.Token.Val
+		}
+		if pongoErr.OrigError != nil {
+			renderErr.Err = pongoErr.OrigError
+		}
+	}
+	renderErr.Snippet = snippet(template, renderErr.Line)
+	return renderErr
+}
+
+// Error describes the failure on its first line, followed by the snippet and
+// the variables
+func (e *RenderError) Error() string {
+	var b strings.Builder
+	b.WriteString("failed to " + e.Phase + " template")
+	if e.Line > 0 {
+		fmt.Fprintf(&b, " at line %d, column %d", e.Line, e.Column)
+	}
+	b.WriteString(": " + e.Err.Error())
+	if e.Near != "" {
+		fmt.Fprintf(&b, " (near %q)", e.Near)
+	}
+	if e.Snippet != "" {
+		b.WriteString("\n" + e.Snippet)
+	}
+	if len(e.Variables) > 0 {
+		b.WriteString("\n  variables: " + strings.Join(e.Variables, ", "))
+	}
+	return b.String()
+}
+
+// Unwrap returns the error of the template engine
+func (e *RenderError) Unwrap() error {
+	return e.Err
+}
+
+// Named sets the name of the template on err when it is a *RenderError and
+// returns other errors as they are
+func Named(err error, name string) error {
+	if renderErr, ok := err.(*RenderError); ok {
+		named := *renderErr
+		named.Name = name
+		return &named
+	}
+	return err
+}
+
+// snippet returns the lines of template around line, numbered, with line
+// marked; nothing when the line is unknown
+func snippet(template string, line int) string {
+	lines := strings.Split(template, "\n")
+	if line <= 0 || line > len(lines) {
+		return ""
+	}
+
+	first, last := max(line-snippetContext, 1), min(line+snippetContext, len(lines))
+	width := len(fmt.Sprint(last))
+	var b strings.Builder
+	for n := first; n <= last; n++ {
+		marker := " "
+		if n == line {
+			marker = ">"
+		}
+		fmt.Fprintf(&b, "  %s %*d | %s", marker, width, n, strings.TrimRight(lines[n-1], "\r"))
+		if n < last {
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// summarizeVariables lists the variables by name with short values: strings
+// and scalars as they are, lists and maps by their length
+func summarizeVariables(variables map[string]any) []string {
+	names := make([]string, 0, len(variables))
+	for name := range variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var summary []string
+	for i, name := range names {
+		if i == maxVariables {
+			summary = append(summary, fmt.Sprintf("(+%d more)", len(names)-maxVariables))
+			break
+		}
+		summary = append(summary, name+"="+summarizeValue(variables[name]))
+	}
+	return summary
+}
+
+func summarizeValue(value any) string {
+	if value == nil {
+		return "nil"
+	}
+	switch v := reflect.ValueOf(value); v.Kind() {
+	case reflect.String:
+		text := v.String()
+		if len(text) > 40 {
+			text = text[:37] + "..."
+		}
+		return fmt.Sprintf("%q", text)
+	case reflect.Slice, reflect.Array:
+		return fmt.Sprintf("[%d items]", v.Len())
+	case reflect.Map:
+		return fmt.Sprintf("{%d keys}", v.Len())
+	case reflect.Struct, reflect.Pointer, reflect.Func, reflect.Chan, reflect.Interface:
+		return fmt.Sprintf("(%T)", value)
+	default:
+		return fmt.Sprint(value)
+	}
+}
@@ -0,0 +1,71 @@
+package templates
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderError_Parse(t *testing.T) {
+	template := strings.Join([]string{
+		"package main",
+		"",
+		"func main() {",
+		"{% if HasDatabase %}",
+		"\tconnect()",
+		"{% endfor %}",
+		"}",
+		"",
+		"// end",
+	}, "\n")
+	_, err := NewEngine().RenderString(context.Background(), template, map[string]any{"ProjectName": "orders", "Components": []string{"gin"}})
+	require.Error(t, err)
+
+	var renderErr *RenderError
+	require.True(t, errors.As(err, &renderErr))
+	assert.Equal(t, PhaseParse, renderErr.Phase)
+	assert.Equal(t, 6, renderErr.Line)
+	assert.Equal(t, []string{`Components=[1 items]`, `ProjectName="orders"`}, renderErr.Variables)
+
+	message := err.Error()
+	assert.Contains(t, message, "failed to parse template at line 6")
+	assert.Contains(t, message, "  > 6 | {% endfor %}")
+	assert.Contains(t, message, "    4 | {% if HasDatabase %}")
+	assert.Contains(t, message, "    8 | ")
+	assert.NotContains(t, message, "// end", "the snippet only shows nearby lines")
+	assert.Contains(t, message, `variables: Components=[1 items], ProjectName="orders"`)
+}
+
+func TestRenderError_Execute(t *testing.T) {
+	variables := map[string]any{"Name": "orders"}
+	for i := range maxVariables + 3 {
+		variables[fmt.Sprintf("Var%02d", i)] = i
+	}
+	_, err := NewEngine().RenderString(context.Background(), "line one\n{{ Name|nosuchfilter }}\n", variables)
+	require.Error(t, err)
+
+	var renderErr *RenderError
+	require.True(t, errors.As(err, &renderErr))
+	assert.Equal(t, 2, renderErr.Line)
+	assert.Len(t, renderErr.Variables, maxVariables+1)
+	assert.Equal(t, "(+4 more)", renderErr.Variables[maxVariables])
+}
+
+func TestNamed(t *testing.T) {
+	_, err := NewEngine().RenderString(context.Background(), "{% if %}", nil)
+	require.Error(t, err)
+
+	named := Named(err, "main.go")
+	var renderErr *RenderError
+	require.True(t, errors.As(named, &renderErr))
+	assert.Equal(t, "main.go", renderErr.Name)
+	assert.Empty(t, err.(*RenderError).Name, "the original error is left alone")
+
+	other := errors.New("disk full")
+	assert.Same(t, other, Named(other, "main.go"))
+}
@@ -0,0 +1,131 @@
+package templates
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// TemplateConfigFile declares how a stored template builds on another. It
+// sits at the template root and is not rendered into projects.
+const TemplateConfigFile = "template.yaml"
+
+// TemplateConfig is the content of TemplateConfigFile
+type TemplateConfig struct {
+	// Base names the template whose files this one starts from. Files at the
+	// same path replace the base's; the others are added.
+	Base string `yaml:"base"`
+	// NextSteps is shown after gogo init, rendered with the project
+	// variables. Templates declaring none inherit their base's.
+	NextSteps string `yaml:"next_steps"`
+}
+
+// ParseTemplateConfig returns the TemplateConfigFile among files, or a zero
+// config when there is none
+func ParseTemplateConfig(files []TemplateFile) (TemplateConfig, error) {
+	var config TemplateConfig
+	for _, file := range files {
+		if file.Path != TemplateConfigFile {
+			continue
+		}
+		decoder := yaml.NewDecoder(strings.NewReader(file.Content))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(&config); err != nil && !errors.Is(err, io.EOF) {
+			return TemplateConfig{}, fmt.Errorf("invalid %s: %w", TemplateConfigFile, err)
+		}
+		config.Base = strings.TrimSpace(config.Base)
+	}
+	return config, nil
+}
+
+// resolveBase merges the files of a stored template over those of its base,
+// resolving the base's own base in turn. chain lists the templates being
+// resolved, outermost first, to report inheritance cycles.
+func (r *Repository) resolveBase(ctx context.Context, kind string, files []TemplateFile, chain []string) ([]TemplateFile, error) {
+	config, err := ParseTemplateConfig(files)
+	if err != nil {
+		return nil, fmt.Errorf("template '%s': %w", kind, err)
+	}
+	files = slices.DeleteFunc(slices.Clone(files), func(file TemplateFile) bool {
+		return file.Path == TemplateConfigFile
+	})
+	if config.Base == "" {
+		return files, nil
+	}
+
+	chain = append(chain, kind)
+	var base []TemplateFile
+	if config.Base == kind {
+		// A stored template extending its own name builds on the built-in one
+		predefined, exists := r.templateFiles[kind]
+		if !exists {
+			return nil, fmt.Errorf("template '%s' extends itself and there is no built-in template of that name", kind)
+		}
+		base = predefined
+	} else {
+		if slices.Contains(chain, config.Base) {
+			return nil, fmt.Errorf("template inheritance cycle: %s -> %s", strings.Join(chain, " -> "), config.Base)
+		}
+		base, err = r.resolveTemplateFiles(ctx, config.Base, chain)
+		if err != nil {
+			return nil, fmt.Errorf("base of template '%s': %w", kind, err)
+		}
+	}
+	return mergeTemplateFiles(base, files), nil
+}
+
+// GetNextSteps returns the next steps of a template kind, preferring a
+// stored template of that name over the predefined one
+func (r *Repository) GetNextSteps(ctx context.Context, kind string) (string, error) {
+	return r.resolveNextSteps(ctx, kind, nil)
+}
+
+func (r *Repository) resolveNextSteps(ctx context.Context, kind string, chain []string) (string, error) {
+	if r.store != nil {
+		files, ok, err := r.store.StoredTemplateFiles(ctx, kind)
+		if err != nil {
+			return "", err
+		}
+		if ok {
+			config, err := ParseTemplateConfig(files)
+			if err != nil {
+				return "", fmt.Errorf("template '%s': %w", kind, err)
+			}
+			switch {
+			case config.NextSteps != "" || config.Base == "":
+				return config.NextSteps, nil
+			case config.Base == kind:
+				return r.predefinedTemplates[kind].NextSteps, nil
+			case slices.Contains(chain, config.Base):
+				return "", fmt.Errorf("template inheritance cycle: %s", strings.Join(slices.Concat(chain, []string{kind, config.Base}), " -> "))
+			}
+			return r.resolveNextSteps(ctx, config.Base, append(chain, kind))
+		}
+	}
+	return r.predefinedTemplates[kind].NextSteps, nil
+}
+
+// mergeTemplateFiles returns base with overlay files replacing those at the
+// same path, keeping the base's order, followed by the overlay's new files
+func mergeTemplateFiles(base, overlay []TemplateFile) []TemplateFile {
+	index := make(map[string]int, len(base))
+	merged := make([]TemplateFile, 0, len(base)+len(overlay))
+	for _, file := range base {
+		index[file.Path] = len(merged)
+		merged = append(merged, file)
+	}
+	for _, file := range overlay {
+		if i, ok := index[file.Path]; ok {
+			merged[i] = file
+			continue
+		}
+		index[file.Path] = len(merged)
+		merged = append(merged, file)
+	}
+	return merged
+}
@@ -0,0 +1,101 @@
+package templates
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func configFile(content string) TemplateFile {
+	return TemplateFile{Name: TemplateConfigFile, Path: TemplateConfigFile, Content: content}
+}
+
+func TestRepository_TemplateInheritance(t *testing.T) {
+	ctx := context.Background()
+	repo := NewRepository()
+	repo.SetStore(fakeStore{
+		"org-api": {
+			configFile("base: api\n"),
+			{Name: "README.md", Path: "README.md", Content: "org readme"},
+			{Name: "auth.go", Path: "internal/auth/auth.go", Content: "package auth"},
+		},
+		"team-api": {
+			configFile("base: org-api\n"),
+			{Name: "logging.go", Path: "internal/logging/logging.go", Content: "package logging"},
+		},
+		"cli": {
+			configFile("base: cli"),
+			{Name: "extra.go", Path: "extra.go", Content: "package main"},
+		},
+	})
+
+	api, err := repo.GetTemplateFiles(ctx, "api")
+	require.NoError(t, err)
+
+	files, err := repo.GetTemplateFiles(ctx, "org-api")
+	require.NoError(t, err)
+	assert.Equal(t, append(templatePaths(api), "internal/auth/auth.go"), templatePaths(files), "base order is kept, new files follow")
+	for _, file := range files {
+		if file.Path == "README.md" {
+			assert.Equal(t, "org readme", file.Content, "the overlay replaces the base's file")
+		}
+	}
+
+	files, err = repo.GetTemplateFiles(ctx, "team-api")
+	require.NoError(t, err)
+	assert.Equal(t, append(templatePaths(api), "internal/auth/auth.go", "internal/logging/logging.go"), templatePaths(files))
+	assert.NotContains(t, templatePaths(files), TemplateConfigFile)
+
+	// A stored template based on its own name extends the built-in one
+	files, err = repo.GetTemplateFiles(ctx, "cli")
+	require.NoError(t, err)
+	assert.Contains(t, templatePaths(files), "extra.go")
+	assert.Contains(t, templatePaths(files), "cmd/{{ ProjectName }}/main.go")
+}
+
+func TestRepository_TemplateInheritanceErrors(t *testing.T) {
+	ctx := context.Background()
+	repo := NewRepository()
+	repo.SetStore(fakeStore{
+		"a":       {configFile("base: b")},
+		"b":       {configFile("base: c")},
+		"c":       {configFile("base: a")},
+		"orphan":  {configFile("base: missing")},
+		"invalid": {configFile("bases: api")},
+		"self":    {configFile("base: self")},
+	})
+
+	_, err := repo.GetTemplateFiles(ctx, "a")
+	assert.ErrorContains(t, err, "template inheritance cycle: a -> b -> c -> a")
+	_, err = repo.GetTemplateFiles(ctx, "orphan")
+	assert.ErrorContains(t, err, "template files for kind 'missing' not found")
+	_, err = repo.GetTemplateFiles(ctx, "invalid")
+	assert.ErrorContains(t, err, "invalid template.yaml")
+	_, err = repo.GetTemplateFiles(ctx, "self")
+	assert.ErrorContains(t, err, "extends itself")
+}
+
+func TestRepository_GetNextSteps(t *testing.T) {
+	ctx := context.Background()
+	repo := NewRepository()
+	repo.SetStore(fakeStore{
+		"org-api":  {configFile("base: api")},
+		"team-api": {configFile("base: org-api\nnext_steps: Run make team-setup")},
+		"plain":    {{Name: "main.go", Path: "main.go", Content: "package main"}},
+	})
+
+	api, err := repo.GetPredefinedTemplate(ctx, "api")
+	require.NoError(t, err)
+	for kind, want := range map[string]string{
+		"api":      api.NextSteps,
+		"org-api":  api.NextSteps,
+		"team-api": "Run make team-setup",
+		"plain":    "",
+	} {
+		steps, err := repo.GetNextSteps(ctx, kind)
+		require.NoError(t, err, kind)
+		assert.Equal(t, want, steps, kind)
+	}
+}
@@ -0,0 +1,94 @@
+package templates
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// MinLanguageLevel is the oldest Go release generated code compiles with
+const MinLanguageLevel = "1.20"
+
+// levelPattern matches Go versions such as 1.22 and 1.22.3
+var levelPattern = regexp.MustCompile(`^1\.(\d+)(\.\d+)?$`)
+
+// languageFeatures are the template variables that tell templates whether
+// the language level has a construct, with the minor release adding it;
+// templates fall back to older constructs when one is false
+var languageFeatures = []struct {
+	name  string
+	minor int
+}{
+	{"HasSlog", 21},          // log/slog, instead of log
+	{"HasSlices", 21},        // The slices and maps packages and min, max and clear
+	{"HasWithoutCancel", 21}, // context.WithoutCancel, instead of context.Background
+	{"HasRangeInt", 22},      // for i := range n, instead of three-clause loops
+	{"HasRandV2", 22},        // math/rand/v2, instead of math/rand
+}
+
+// LanguageFeatures lists the template variables LanguageVariables sets
+// besides LanguageLevel
+func LanguageFeatures() []string {
+	names := make([]string, 0, len(languageFeatures))
+	for _, feature := range languageFeatures {
+		names = append(names, feature.name)
+	}
+	return names
+}
+
+// ParseLanguageLevel returns the language level of a Go version, its major
+// and minor release such as 1.22 for 1.22.3
+func ParseLanguageLevel(version string) (string, error) {
+	if _, err := languageMinor(version); err != nil {
+		return "", err
+	}
+	match := levelPattern.FindStringSubmatch(version)
+	return "1." + match[1], nil
+}
+
+// CompareLanguageLevels returns -1, 0 or 1 as Go version a is older than,
+// the same language level as, or newer than b
+func CompareLanguageLevels(a, b string) (int, error) {
+	minorA, err := languageMinor(a)
+	if err != nil {
+		return 0, err
+	}
+	minorB, err := languageMinor(b)
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case minorA < minorB:
+		return -1, nil
+	case minorA > minorB:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// LanguageVariables returns the LanguageLevel variable of level and a
+// feature variable for each construct it has. An empty level has them all.
+func LanguageVariables(level string) map[string]any {
+	minor := -1
+	if level != "" {
+		if parsed, err := languageMinor(level); err == nil {
+			minor = parsed
+			level, _ = ParseLanguageLevel(level)
+		}
+	}
+
+	variables := map[string]any{"LanguageLevel": level}
+	for _, feature := range languageFeatures {
+		variables[feature.name] = minor < 0 || minor >= feature.minor
+	}
+	return variables
+}
+
+// languageMinor returns the minor release of a Go version
+func languageMinor(version string) (int, error) {
+	match := levelPattern.FindStringSubmatch(version)
+	if match == nil {
+		return 0, fmt.Errorf("invalid language level %q (expected format: 1.x or 1.x.y)", version)
+	}
+	return strconv.Atoi(match[1])
+}
@@ -0,0 +1,73 @@
+package templates
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLanguageLevel(t *testing.T) {
+	level, err := ParseLanguageLevel("1.22.3")
+	require.NoError(t, err)
+	assert.Equal(t, "1.22", level)
+
+	level, err = ParseLanguageLevel("1.21")
+	require.NoError(t, err)
+	assert.Equal(t, "1.21", level)
+
+	_, err = ParseLanguageLevel("go1.21")
+	assert.ErrorContains(t, err, "invalid language level")
+}
+
+func TestCompareLanguageLevels(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.20", "1.21", -1},
+		{"1.21.5", "1.21", 0},
+		{"1.25.1", "1.9", 1},
+	}
+	for _, tt := range tests {
+		got, err := CompareLanguageLevels(tt.a, tt.b)
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, got, "%s vs %s", tt.a, tt.b)
+	}
+
+	_, err := CompareLanguageLevels("1.21", "latest")
+	assert.Error(t, err)
+}
+
+func TestLanguageVariables(t *testing.T) {
+	variables := LanguageVariables("1.21.4")
+	assert.Equal(t, "1.21", variables["LanguageLevel"])
+	assert.Equal(t, true, variables["HasSlog"])
+	assert.Equal(t, true, variables["HasSlices"])
+	assert.Equal(t, false, variables["HasRangeInt"])
+	assert.Equal(t, false, variables["HasRandV2"])
+
+	variables = LanguageVariables("1.20")
+	for _, name := range LanguageFeatures() {
+		assert.Equal(t, false, variables[name], name)
+	}
+
+	// Without a level, templates use every construct
+	variables = LanguageVariables("")
+	for _, name := range LanguageFeatures() {
+		assert.Equal(t, true, variables[name], name)
+	}
+}
+
+func TestLanguageVariables_Render(t *testing.T) {
+	engine := NewEngine()
+	template := `{% if HasRangeInt %}for range n {{ "{" }}{% else %}for i := 0; i < n; i++ {{ "{" }}{% endif %}`
+
+	modern, err := engine.RenderString(t.Context(), template, LanguageVariables("1.22"))
+	require.NoError(t, err)
+	assert.Equal(t, "for range n {", modern)
+
+	fallback, err := engine.RenderString(t.Context(), template, LanguageVariables("1.21"))
+	require.NoError(t, err)
+	assert.Equal(t, "for i := 0; i < n; i++ {", fallback)
+}
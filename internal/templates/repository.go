@@ -12,10 +12,125 @@ type TemplateFile struct {
 	Path    string // Relative path within the project
 }
 
+// frameworkRequire is appended to go.mod templates, requiring the web
+// framework selected through the Components list, if any
+const frameworkRequire = `{% if IsGin or IsEcho or IsChi %}
+
+require {% if IsGin %}github.com/gin-gonic/gin {{ "github.com/gin-gonic/gin"|version }}{% elif IsEcho %}github.com/labstack/echo/v4 {{ "github.com/labstack/echo/v4"|version }}{% else %}github.com/go-chi/chi/v5 {{ "github.com/go-chi/chi/v5"|version }}{% endif %}
+{% endif %}`
+
+// grpcServerFile is internal/server/server.go of the grpc templates. gogo
+// generate proto adds a call to RegisterServices for each service.
+const grpcServerFile = `package server
+
+import (
+	"google.golang.org/grpc"
+)
+
+// RegisterServices registers all gRPC services. Scaffold a service with
+// gogo generate proto <name>, which adds its Register call here.
+func RegisterServices(s *grpc.Server) {
+}
+`
+
+// apiSpecFile is api/openapi.yaml of the api template. It documents the
+// endpoints main.go serves.
+const apiSpecFile = `openapi: 3.0.3
+info:
+  title: {{ ProjectName }} API
+  version: 0.1.0
+  description: |
+    {{ Description }}
+servers:
+  - url: http://localhost:8080
+paths:
+  /:
+    get:
+      summary: Root endpoint
+      operationId: getRoot
+      responses:
+        "200":
+          description: Name of the API
+          content:
+            text/plain:
+              schema:
+                type: string
+  /health:
+    get:
+      summary: Health check
+      operationId: getHealth
+      responses:
+        "200":
+          description: The API is up
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/Health"
+components:
+  schemas:
+    Health:
+      type: object
+      required: [status]
+      properties:
+        status:
+          type: string
+          example: ok
+`
+
+// apiSpecHandlersFile is api/openapi.go of the api template. It embeds the
+// specification and, with HasSwagger, serves it along with Swagger UI.
+const apiSpecHandlersFile = `// Package api holds the OpenAPI specification of {{ ProjectName }}
+package api
+
+import (
+	_ "embed"
+{% if HasSwagger %}
+	"net/http"
+{% endif %}
+)
+
+// Spec is the OpenAPI specification in openapi.yaml
+//
+//go:embed openapi.yaml
+var Spec []byte
+{% if HasSwagger %}
+
+// SpecHandler serves Spec
+func SpecHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/yaml")
+	w.Write(Spec)
+}
+
+// DocsHandler serves Swagger UI for Spec. The UI is loaded from a CDN.
+func DocsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write([]byte(docsPage))
+}
+
+const docsPage = ` + "`" + `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>{{ ProjectName }} API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+  <script>
+    window.ui = SwaggerUIBundle({ url: "/openapi.yaml", dom_id: "#swagger-ui" });
+  </script>
+</body>
+</html>
+` + "`" + `
+{% endif %}
+`
+
 // Repository manages template storage and retrieval
 type Repository struct {
 	predefinedTemplates map[string]Template
 	templateFiles       map[string][]TemplateFile
+	store               Store
 }
 
 // NewRepository creates a new template repository
@@ -46,8 +161,25 @@ func (r *Repository) ListPredefinedTemplates(ctx context.Context) ([]Template, e
 	return templates, nil
 }
 
-// GetTemplateFiles returns all files for a template kind
+// GetTemplateFiles returns all files for a template kind, preferring a
+// stored template of that name over the predefined one
 func (r *Repository) GetTemplateFiles(ctx context.Context, kind string) ([]TemplateFile, error) {
+	return r.resolveTemplateFiles(ctx, kind, nil)
+}
+
+// resolveTemplateFiles returns the files of a template kind with those of
+// the base it declares in TemplateConfigFile merged in
+func (r *Repository) resolveTemplateFiles(ctx context.Context, kind string, chain []string) ([]TemplateFile, error) {
+	if r.store != nil {
+		files, ok, err := r.store.StoredTemplateFiles(ctx, kind)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return r.resolveBase(ctx, kind, files, chain)
+		}
+	}
+
 	files, exists := r.templateFiles[kind]
 	if !exists {
 		return nil, fmt.Errorf("template files for kind '%s' not found", kind)
@@ -61,6 +193,7 @@ func (r *Repository) initPredefinedTemplates() {
 	r.predefinedTemplates["cli"] = Template{
 		Name: "CLI Application",
 		Kind: "cli",
+		NextSteps: "Build and run it: {{ TaskRunner }} run",
 		Content: `A command-line application template with {{ ProjectName }}, module {{ ModuleName }}, by {{ Author }}`,
 	}
 	r.templateFiles["cli"] = []TemplateFile{
@@ -145,33 +278,13 @@ go.work
 .DS_Store
 Thumbs.db`,
 		},
-		{
-			Name: "Makefile",
-			Path: "Makefile",
-			Content: `.PHONY: build test clean run
-
-BINARY_NAME={{ ProjectName }}
-MAIN_PATH=./cmd/{{ ProjectName }}
-
-build:
-	go build -o $(BINARY_NAME) $(MAIN_PATH)
-
-test:
-	go test -v ./...
-
-clean:
-	go clean
-	rm -f $(BINARY_NAME)
-
-run: build
-	./$(BINARY_NAME)`,
-		},
 	}
 
 	// Library template
 	r.predefinedTemplates["library"] = Template{
 		Name: "Go Library",
 		Kind: "library",
+		NextSteps: "Run the tests: go test ./...",
 		Content: `A Go library template for {{ ProjectName }}, module {{ ModuleName }}, by {{ Author }}`,
 	}
 	r.templateFiles["library"] = []TemplateFile{
@@ -259,6 +372,7 @@ Thumbs.db`,
 	r.predefinedTemplates["api"] = Template{
 		Name: "Web API",
 		Kind: "api",
+		NextSteps: "Start the API: {{ TaskRunner }} dev\nCheck it responds: curl http://localhost:8080/health\n{% if HasSwagger %}Browse the API docs: http://localhost:8080/docs{% endif %}",
 		Content: `A REST API template for {{ ProjectName }}, module {{ ModuleName }}, by {{ Author }}`,
 	}
 	r.templateFiles["api"] = []TemplateFile{
@@ -268,12 +382,100 @@ Thumbs.db`,
 			Content: `package main
 
 import (
+{% if IsGin or IsEcho or IsChi %}
+	"context"
+{% endif %}
 	"fmt"
 	"log"
 	"net/http"
+{% if IsGin or IsEcho or IsChi %}
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+{% endif %}
+{% if IsGin %}
+
+	"github.com/gin-gonic/gin"
+{% elif IsEcho %}
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+{% elif IsChi %}
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+{% endif %}
+{% if HasSwagger %}
+
+	"{{ ModuleName }}/api"
+{% endif %}
 )
 
 func main() {
+{% if IsGin %}
+	r := gin.Default()
+
+	r.GET("/", func(c *gin.Context) {
+		c.String(http.StatusOK, "{{ ProjectName }} API - by {{ Author }}")
+	})
+
+	r.GET("/health", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	})
+{% if HasSwagger %}
+
+	r.GET("/openapi.yaml", gin.WrapF(api.SpecHandler))
+	r.GET("/docs", gin.WrapF(api.DocsHandler))
+{% endif %}
+
+	serve(r)
+}
+{% elif IsEcho %}
+	e := echo.New()
+	e.HideBanner = true
+	e.Use(middleware.Logger())
+	e.Use(middleware.Recover())
+
+	e.GET("/", func(c echo.Context) error {
+		return c.String(http.StatusOK, "{{ ProjectName }} API - by {{ Author }}")
+	})
+
+	e.GET("/health", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
+	})
+{% if HasSwagger %}
+
+	e.GET("/openapi.yaml", echo.WrapHandler(http.HandlerFunc(api.SpecHandler)))
+	e.GET("/docs", echo.WrapHandler(http.HandlerFunc(api.DocsHandler)))
+{% endif %}
+
+	serve(e)
+}
+{% elif IsChi %}
+	r := chi.NewRouter()
+	r.Use(middleware.RequestID)
+	r.Use(middleware.RealIP)
+	r.Use(middleware.Logger)
+	r.Use(middleware.Recoverer)
+
+	r.Get("/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "{{ ProjectName }} API - by {{ Author }}")
+	})
+
+	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `+"`"+`{"status":"ok"}`+"`"+`)
+	})
+{% if HasSwagger %}
+
+	r.Get("/openapi.yaml", api.SpecHandler)
+	r.Get("/docs", api.DocsHandler)
+{% endif %}
+
+	serve(r)
+}
+{% else %}
 	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 		fmt.Fprintf(w, "{{ ProjectName }} API - by {{ Author }}")
 	})
@@ -281,17 +483,60 @@ func main() {
 	http.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
 		fmt.Fprintf(w, `+"`"+`{"status":"ok"}`+"`"+`)
 	})
+{% if HasSwagger %}
+
+	http.HandleFunc("/openapi.yaml", api.SpecHandler)
+	http.HandleFunc("/docs", api.DocsHandler)
+{% endif %}
 	
 	fmt.Println("Starting {{ ProjectName }} API on :8080")
 	log.Fatal(http.ListenAndServe(":8080", nil))
-}`,
+}
+{% endif %}
+{% if IsGin or IsEcho or IsChi %}
+
+// serve runs the API until SIGINT or SIGTERM, then shuts it down gracefully
+func serve(handler http.Handler) {
+	srv := &http.Server{
+		Addr:    ":8080",
+		Handler: handler,
+	}
+
+	go func() {
+		sigChan := make(chan os.Signal, 1)
+		signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+		<-sigChan
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(ctx); err != nil {
+			log.Printf("Server shutdown error: %v", err)
+		}
+	}()
+
+	fmt.Println("Starting {{ ProjectName }} API on :8080")
+	if err := srv.ListenAndServe(); err != http.ErrServerClosed {
+		log.Fatal(err)
+	}
+}
+{% endif %}`,
+		},
+		{
+			Name:    "openapi.yaml",
+			Path:    "api/openapi.yaml",
+			Content: apiSpecFile,
+		},
+		{
+			Name:    "openapi.go",
+			Path:    "api/openapi.go",
+			Content: apiSpecHandlersFile,
 		},
 		{
 			Name: "go.mod",
 			Path: "go.mod",
 			Content: `module {{ ModuleName }}
 
-go {{ GoVersion }}`,
+go {{ GoVersion }}`+frameworkRequire,
 		},
 		{
 			Name: "README.md",
@@ -310,6 +555,11 @@ go run cmd/{{ ProjectName }}/main.go
 
 - ` + "`GET /`" + ` - Root endpoint
 - ` + "`GET /health`" + ` - Health check
+{% if HasSwagger %}- ` + "`GET /docs`" + ` - API documentation (Swagger UI)
+- ` + "`GET /openapi.yaml`" + ` - OpenAPI specification
+{% endif %}
+The endpoints are documented in ` + "`api/openapi.yaml`" + `; keep it up to date as
+the API grows.
 
 ## Author
 
@@ -346,36 +596,13 @@ go.work
 .DS_Store
 Thumbs.db`,
 		},
-		{
-			Name: "Makefile",
-			Path: "Makefile",
-			Content: `.PHONY: build test clean run
-
-BINARY_NAME={{ ProjectName }}
-MAIN_PATH=./cmd/{{ ProjectName }}
-
-build:
-	go build -o $(BINARY_NAME) $(MAIN_PATH)
-
-test:
-	go test -v ./...
-
-clean:
-	go clean
-	rm -f $(BINARY_NAME)
-
-run: build
-	./$(BINARY_NAME)
-
-dev:
-	go run $(MAIN_PATH)`,
-		},
 	}
 
 	// gRPC template
 	r.predefinedTemplates["grpc"] = Template{
 		Name: "gRPC Service",
 		Kind: "grpc",
+		NextSteps: "Scaffold a service and its server stub: gogo generate proto <name>, then {{ TaskRunner }} proto\nStart the server on :50051: go run ./cmd/{{ ProjectName }}",
 		Content: `A gRPC service template for {{ ProjectName }}, module {{ ModuleName }}, by {{ Author }}`,
 	}
 	r.templateFiles["grpc"] = []TemplateFile{
@@ -390,6 +617,8 @@ import (
 	"net"
 	
 	"google.golang.org/grpc"
+
+	"{{ ModuleName }}/internal/server"
 )
 
 func main() {
@@ -399,7 +628,7 @@ func main() {
 	}
 
 	s := grpc.NewServer()
-	// Register your services here
+	server.RegisterServices(s)
 	
 	fmt.Println("{{ ProjectName }} gRPC server listening on :50051")
 	if err := s.Serve(lis); err != nil {
@@ -415,10 +644,15 @@ func main() {
 go {{ GoVersion }}
 
 require (
-	google.golang.org/grpc v1.58.0
-	google.golang.org/protobuf v1.31.0
+	google.golang.org/grpc {{ "google.golang.org/grpc"|version }}
+	google.golang.org/protobuf {{ "google.golang.org/protobuf"|version }}
 )`,
 		},
+		{
+			Name:    "server.go",
+			Path:    "internal/server/server.go",
+			Content: grpcServerFile,
+		},
 		{
 			Name: "README.md",
 			Path: "README.md",
@@ -476,6 +710,7 @@ Thumbs.db`,
 	r.predefinedTemplates["microservice"] = Template{
 		Name: "Microservice",
 		Kind: "microservice",
+		NextSteps: "Start the service: go run ./cmd/{{ ProjectName }}\nCheck it responds: curl http://localhost:8080/health",
 		Content: `A microservice template for {{ ProjectName }}, module {{ ModuleName }}, by {{ Author }}`,
 	}
 	r.templateFiles["microservice"] = []TemplateFile{
@@ -493,9 +728,75 @@ import (
 	"os/signal"
 	"syscall"
 	"time"
+{% if IsGin %}
+
+	"github.com/gin-gonic/gin"
+{% elif IsEcho %}
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+{% elif IsChi %}
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+{% endif %}
 )
 
 func main() {
+{% if IsGin %}
+	r := gin.Default()
+
+	r.GET("/health", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "ok", "service": "{{ ProjectName }}"})
+	})
+
+	r.GET("/metrics", func(c *gin.Context) {
+		c.String(http.StatusOK, "# Metrics for {{ ProjectName }}")
+	})
+
+	server := &http.Server{
+		Addr:    ":8080",
+		Handler: r,
+	}
+{% elif IsEcho %}
+	e := echo.New()
+	e.HideBanner = true
+	e.Use(middleware.Logger())
+	e.Use(middleware.Recover())
+
+	e.GET("/health", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, map[string]string{"status": "ok", "service": "{{ ProjectName }}"})
+	})
+
+	e.GET("/metrics", func(c echo.Context) error {
+		return c.String(http.StatusOK, "# Metrics for {{ ProjectName }}")
+	})
+
+	server := &http.Server{
+		Addr:    ":8080",
+		Handler: e,
+	}
+{% elif IsChi %}
+	r := chi.NewRouter()
+	r.Use(middleware.RequestID)
+	r.Use(middleware.RealIP)
+	r.Use(middleware.Logger)
+	r.Use(middleware.Recoverer)
+
+	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `+"`"+`{"status":"ok","service":"{{ ProjectName }}"}`+"`"+`)
+	})
+
+	r.Get("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "# Metrics for {{ ProjectName }}")
+	})
+
+	server := &http.Server{
+		Addr:    ":8080",
+		Handler: r,
+	}
+{% else %}
 	mux := http.NewServeMux()
 	
 	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
@@ -510,6 +811,7 @@ func main() {
 		Addr:    ":8080",
 		Handler: mux,
 	}
+{% endif %}
 	
 	// Graceful shutdown
 	go func() {
@@ -533,7 +835,7 @@ func main() {
 			Path: "go.mod",
 			Content: `module {{ ModuleName }}
 
-go {{ GoVersion }}`,
+go {{ GoVersion }}`+frameworkRequire,
 		},
 		{
 			Name: "README.md",
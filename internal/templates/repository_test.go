@@ -112,7 +112,7 @@ func TestRepository_GetTemplateFiles(t *testing.T) {
 		{
 			name:         "CLI template files",
 			templateKind: "cli",
-			expectFiles:  []string{"main.go", "go.mod", "README.md", ".gitignore", "Makefile"},
+			expectFiles:  []string{"main.go", "go.mod", "README.md", ".gitignore"},
 		},
 		{
 			name:         "library template files",
@@ -122,7 +122,7 @@ func TestRepository_GetTemplateFiles(t *testing.T) {
 		{
 			name:         "API template files",
 			templateKind: "api",
-			expectFiles:  []string{"main.go", "go.mod", "README.md", ".gitignore", "Makefile"},
+			expectFiles:  []string{"main.go", "go.mod", "README.md", ".gitignore"},
 		},
 	}
 
@@ -0,0 +1,255 @@
+package templates
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/user/gogo/internal/db"
+)
+
+// Store supplies user templates kept outside the binary, such as those added
+// with gogo template add
+type Store interface {
+	// StoredTemplateFiles returns the files of a stored template; ok is false
+	// when the store has no template of that name
+	StoredTemplateFiles(ctx context.Context, name string) (files []TemplateFile, ok bool, err error)
+}
+
+// SetStore makes the repository resolve templates from store before falling
+// back to the predefined ones
+func (r *Repository) SetStore(store Store) {
+	r.store = store
+}
+
+// DBStore serves templates from the gogo database
+type DBStore struct {
+	templates *db.TemplateManager
+}
+
+// NewDBStore creates a store backed by the templates table
+func NewDBStore(manager *db.Manager) *DBStore {
+	return &DBStore{templates: db.NewTemplateManager(manager)}
+}
+
+// StoredTemplateFiles implements Store
+func (s *DBStore) StoredTemplateFiles(ctx context.Context, name string) ([]TemplateFile, bool, error) {
+	stored, err := s.templates.Get(ctx, name)
+	if errors.Is(err, db.ErrTemplateNotFound) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	files := make([]TemplateFile, len(stored.Files))
+	for i, file := range stored.Files {
+		files[i] = TemplateFile{Name: path.Base(file.Path), Path: file.Path, Content: file.Content}
+	}
+	return files, true, nil
+}
+
+// maxTemplateFileSize bounds single files read into a stored template
+const maxTemplateFileSize = 1 << 20
+
+// Top-level directories of a template pack, a source holding several
+// templates and blueprints
+const (
+	PackTemplatesDir  = "templates"
+	PackBlueprintsDir = "blueprints"
+)
+
+// FixturesFile lists the variable combinations gogo template test renders a
+// template source with. It sits at the source root and is not a template file.
+const FixturesFile = "fixtures.yaml"
+
+// ReadTemplateFiles reads the files of a template from a directory or a
+// .tar.gz, .tgz or .zip archive. Paths are relative to the template root
+// (a single top-level directory in an archive is stripped) and lose a
+// trailing .tmpl extension; .git directories and FixturesFile are skipped.
+func ReadTemplateFiles(source string) ([]TemplateFile, error) {
+	return readTemplateSource(source, false)
+}
+
+// ReadPackFiles reads a source like ReadTemplateFiles, except that a
+// top-level pack directory in an archive is not mistaken for a wrapping
+// directory and stripped
+func ReadPackFiles(source string) ([]TemplateFile, error) {
+	return readTemplateSource(source, true)
+}
+
+func readTemplateSource(source string, pack bool) ([]TemplateFile, error) {
+	info, err := os.Stat(source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template source: %w", err)
+	}
+
+	contents := make(map[string][]byte)
+	switch lower := strings.ToLower(source); {
+	case info.IsDir():
+		err = readTemplateDir(source, contents)
+	case strings.HasSuffix(lower, ".tar.gz") || strings.HasSuffix(lower, ".tgz"):
+		err = readTemplateTar(source, contents)
+	case strings.HasSuffix(lower, ".zip"):
+		err = readTemplateZip(source, contents)
+	default:
+		return nil, fmt.Errorf("unsupported template source %s (use a directory, .tar.gz, .tgz or .zip)", source)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(contents) == 0 {
+		return nil, fmt.Errorf("no template files found in %s", source)
+	}
+
+	// Archives usually wrap the template in one directory
+	prefix := ""
+	if !info.IsDir() {
+		prefix = commonRoot(contents)
+		if pack && (prefix == PackTemplatesDir+"/" || prefix == PackBlueprintsDir+"/") {
+			prefix = ""
+		}
+	}
+	files := make([]TemplateFile, 0, len(contents))
+	for name, content := range contents {
+		if !utf8.Valid(content) {
+			return nil, fmt.Errorf("template file %s is not text", name)
+		}
+		if strings.TrimPrefix(name, prefix) == FixturesFile {
+			continue
+		}
+		rel := strings.TrimSuffix(strings.TrimPrefix(name, prefix), ".tmpl")
+		files = append(files, TemplateFile{Name: path.Base(rel), Path: rel, Content: string(content)})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	return files, nil
+}
+
+func readTemplateDir(dir string, contents map[string][]byte) error {
+	return filepath.WalkDir(dir, func(p string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if entry.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		file, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		return addTemplateFile(contents, filepath.ToSlash(rel), file)
+	})
+}
+
+func readTemplateTar(archive string, contents map[string][]byte) error {
+	file, err := os.Open(archive)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", archive, err)
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", archive, err)
+	}
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", archive, err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		if err := addTemplateFile(contents, header.Name, tr); err != nil {
+			return err
+		}
+	}
+}
+
+func readTemplateZip(archive string, contents map[string][]byte) error {
+	reader, err := zip.OpenReader(archive)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", archive, err)
+	}
+	defer reader.Close()
+
+	for _, entry := range reader.File {
+		if !entry.Mode().IsRegular() {
+			continue
+		}
+		file, err := entry.Open()
+		if err != nil {
+			return fmt.Errorf("failed to read %s from %s: %w", entry.Name, archive, err)
+		}
+		err = addTemplateFile(contents, entry.Name, file)
+		file.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addTemplateFile reads one file, rejecting paths that escape the template
+// root and files too large to be templates
+func addTemplateFile(contents map[string][]byte, name string, r io.Reader) error {
+	clean := path.Clean(strings.TrimPrefix(filepath.ToSlash(name), "./"))
+	if path.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, "../") {
+		return fmt.Errorf("template file %s is outside the template root", name)
+	}
+	for _, part := range strings.Split(clean, "/") {
+		if part == ".git" {
+			return nil
+		}
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r, maxTemplateFileSize+1))
+	if err != nil {
+		return fmt.Errorf("failed to read template file %s: %w", name, err)
+	}
+	if len(data) > maxTemplateFileSize {
+		return fmt.Errorf("template file %s is larger than %d bytes", name, maxTemplateFileSize)
+	}
+	contents[clean] = data
+	return nil
+}
+
+// commonRoot returns the single top-level directory shared by every path,
+// with a trailing slash, or "" when there is none
+func commonRoot(contents map[string][]byte) string {
+	root := ""
+	for name := range contents {
+		dir, _, ok := strings.Cut(name, "/")
+		if !ok || (root != "" && root != dir) {
+			return ""
+		}
+		root = dir
+	}
+	return root + "/"
+}
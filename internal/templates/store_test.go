@@ -0,0 +1,156 @@
+package templates
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeStore map[string][]TemplateFile
+
+func (f fakeStore) StoredTemplateFiles(ctx context.Context, name string) ([]TemplateFile, bool, error) {
+	files, ok := f[name]
+	return files, ok, nil
+}
+
+func TestRepository_StoreTakesPrecedence(t *testing.T) {
+	ctx := context.Background()
+	repo := NewRepository()
+	repo.SetStore(fakeStore{
+		"cli":    {{Name: "main.go", Path: "main.go", Content: "custom"}},
+		"worker": {{Name: "worker.go", Path: "worker.go", Content: "worker"}},
+	})
+
+	files, err := repo.GetTemplateFiles(ctx, "cli")
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, "custom", files[0].Content)
+
+	files, err = repo.GetTemplateFiles(ctx, "worker")
+	require.NoError(t, err)
+	assert.Equal(t, "worker.go", files[0].Path)
+
+	// Predefined templates remain available
+	_, err = repo.GetTemplateFiles(ctx, "api")
+	assert.NoError(t, err)
+	_, err = repo.GetTemplateFiles(ctx, "missing")
+	assert.Error(t, err)
+}
+
+func templatePaths(files []TemplateFile) []string {
+	paths := make([]string, len(files))
+	for i, file := range files {
+		paths[i] = file.Path
+	}
+	return paths
+}
+
+func TestReadTemplateFiles_Dir(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "cmd", "{{ ProjectName }}"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".git"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "cmd", "{{ ProjectName }}", "main.go.tmpl"), []byte("package main\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".git", "HEAD"), []byte("ref\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, FixturesFile), []byte("cases: []\n"), 0644))
+
+	files, err := ReadTemplateFiles(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"cmd/{{ ProjectName }}/main.go"}, templatePaths(files))
+	assert.Equal(t, "main.go", files[0].Name)
+	assert.Equal(t, "package main\n", files[0].Content)
+}
+
+func TestReadTemplateFiles_Archives(t *testing.T) {
+	dir := t.TempDir()
+	entries := map[string]string{
+		"worker/README.md":        "# {{ ProjectName }}\n",
+		"worker/cmd/main.go.tmpl": "package main\n",
+	}
+
+	tarPath := filepath.Join(dir, "worker.tar.gz")
+	file, err := os.Create(tarPath)
+	require.NoError(t, err)
+	gz := gzip.NewWriter(file)
+	tw := tar.NewWriter(gz)
+	for name, content := range entries {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+	require.NoError(t, file.Close())
+
+	zipPath := filepath.Join(dir, "worker.zip")
+	file, err = os.Create(zipPath)
+	require.NoError(t, err)
+	zw := zip.NewWriter(file)
+	for name, content := range entries {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	require.NoError(t, file.Close())
+
+	for _, archive := range []string{tarPath, zipPath} {
+		files, err := ReadTemplateFiles(archive)
+		require.NoError(t, err, archive)
+		assert.Equal(t, []string{"README.md", "cmd/main.go"}, templatePaths(files), archive)
+	}
+}
+
+func TestReadTemplateFiles_Rejects(t *testing.T) {
+	dir := t.TempDir()
+
+	unsupported := filepath.Join(dir, "template.rar")
+	require.NoError(t, os.WriteFile(unsupported, []byte("x"), 0644))
+	_, err := ReadTemplateFiles(unsupported)
+	assert.ErrorContains(t, err, "unsupported template source")
+
+	escaping := filepath.Join(dir, "escape.zip")
+	file, err := os.Create(escaping)
+	require.NoError(t, err)
+	zw := zip.NewWriter(file)
+	_, err = zw.Create("../outside.go")
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+	require.NoError(t, file.Close())
+	_, err = ReadTemplateFiles(escaping)
+	assert.ErrorContains(t, err, "outside the template root")
+
+	_, err = ReadTemplateFiles(filepath.Join(dir, "empty"))
+	assert.Error(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "empty"), 0755))
+	_, err = ReadTemplateFiles(filepath.Join(dir, "empty"))
+	assert.ErrorContains(t, err, "no template files")
+}
+
+func TestReadPackFiles_KeepsPackRoot(t *testing.T) {
+	archive := filepath.Join(t.TempDir(), "pack.zip")
+	file, err := os.Create(archive)
+	require.NoError(t, err)
+	zw := zip.NewWriter(file)
+	for _, name := range []string{"templates/worker/main.go", "templates/cron/main.go"} {
+		_, err := zw.Create(name)
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	require.NoError(t, file.Close())
+
+	files, err := ReadPackFiles(archive)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"templates/cron/main.go", "templates/worker/main.go"}, templatePaths(files))
+
+	files, err = ReadTemplateFiles(archive)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"cron/main.go", "worker/main.go"}, templatePaths(files))
+}
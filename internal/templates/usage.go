@@ -0,0 +1,186 @@
+package templates
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/flosch/pongo2/v6"
+)
+
+// tagPattern matches the variable and block tags of a template
+var tagPattern = regexp.MustCompile(`(?s)\{\{-?(.*?)-?\}\}|\{%-?(.*?)-?%\}`)
+
+// identPattern matches the names an expression may refer to
+var identPattern = regexp.MustCompile(`[A-Za-z_][A-Za-z0-9_]*`)
+
+// stringPattern matches the string literals of an expression
+var stringPattern = regexp.MustCompile(`"(?:[^"\\]|\\.)*"|'(?:[^'\\]|\\.)*'`)
+
+// filterPattern matches the space a filter name may follow its bar with
+var filterPattern = regexp.MustCompile(`\|\s*`)
+
+// keywords are names of expressions that are not variables
+var keywords = map[string]bool{
+	"and": true, "or": true, "not": true, "in": true,
+	"true": true, "false": true, "True": true, "False": true, "nil": true, "None": true,
+}
+
+// DeadBranch is an if or elif condition that is false with the variables,
+// so the block it guards is never rendered
+type DeadBranch struct {
+	Template  string
+	Line      int
+	Condition string
+}
+
+// Usage records, across the templates rendered with one set of variables,
+// which variables they refer to and which of their conditions are false.
+// Conditions are only judged when every name in them is a variable, so those
+// on loop variables are left alone, and conditions nested in a dead block are
+// not reported again.
+type Usage struct {
+	variables  map[string]any
+	referenced map[string]bool
+	dead       []DeadBranch
+}
+
+// NewUsage creates a usage record for templates rendered with variables
+func NewUsage(variables map[string]any) *Usage {
+	return &Usage{variables: variables, referenced: make(map[string]bool)}
+}
+
+// Refer marks variables as used by something other than a template, such as
+// the generator deciding on optional files
+func (u *Usage) Refer(names ...string) {
+	for _, name := range names {
+		u.referenced[name] = true
+	}
+}
+
+// Track records the variables template refers to and its dead branches;
+// name identifies the template in DeadBranch
+func (u *Usage) Track(name, template string) {
+	// Whether the branch being rendered at each nesting level is dead
+	var stack []bool
+	inDead := func() bool {
+		for _, dead := range stack {
+			if dead {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, match := range tagPattern.FindAllStringSubmatchIndex(template, -1) {
+		if match[2] >= 0 {
+			u.Refer(references(template[match[2]:match[3]])...)
+			continue
+		}
+
+		body := strings.TrimSpace(template[match[4]:match[5]])
+		tag, expr, _ := strings.Cut(body, " ")
+		expr = strings.TrimSpace(expr)
+		u.Refer(references(expr)...)
+
+		switch tag {
+		case "if":
+			dead := u.isDead(expr)
+			if dead && !inDead() {
+				u.dead = append(u.dead, DeadBranch{Template: name, Line: lineOf(template, match[0]), Condition: expr})
+			}
+			stack = append(stack, dead)
+		case "elif":
+			if len(stack) == 0 {
+				continue
+			}
+			stack[len(stack)-1] = false
+			dead := u.isDead(expr)
+			if dead && !inDead() {
+				u.dead = append(u.dead, DeadBranch{Template: name, Line: lineOf(template, match[0]), Condition: expr})
+			}
+			stack[len(stack)-1] = dead
+		case "else":
+			if len(stack) > 0 {
+				stack[len(stack)-1] = false
+			}
+		case "endif":
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		}
+	}
+}
+
+// Unused returns the variables no template referred to, sorted
+func (u *Usage) Unused() []string {
+	var unused []string
+	for name := range u.variables {
+		if !u.referenced[name] {
+			unused = append(unused, name)
+		}
+	}
+	sort.Strings(unused)
+	return unused
+}
+
+// DeadBranches returns the conditions that are false with the variables, in
+// the order the templates were tracked
+func (u *Usage) DeadBranches() []DeadBranch {
+	return u.dead
+}
+
+// Warnings describes the unused variables and dead branches, one per line
+func (u *Usage) Warnings() []string {
+	var warnings []string
+	for _, name := range u.Unused() {
+		warnings = append(warnings, fmt.Sprintf("variable %s is never used by a template", name))
+	}
+	for _, branch := range u.dead {
+		warnings = append(warnings, fmt.Sprintf("%s:%d: condition %q is never true", branch.Template, branch.Line, branch.Condition))
+	}
+	return warnings
+}
+
+// isDead reports whether the condition is false with the variables; unknown
+// names and conditions that fail to evaluate are not dead
+func (u *Usage) isDead(condition string) bool {
+	names := references(condition)
+	if len(names) == 0 {
+		return false
+	}
+	for _, name := range names {
+		if _, ok := u.variables[name]; !ok {
+			return false
+		}
+	}
+
+	tpl, err := pongo2.FromString("{% if " + condition + " %}1{% endif %}")
+	if err != nil {
+		return false
+	}
+	result, err := tpl.Execute(u.variables)
+	return err == nil && result == ""
+}
+
+// references returns the names expr refers to: not keywords, attributes or
+// filters, nor anything inside string literals
+func references(expr string) []string {
+	expr = stringPattern.ReplaceAllString(expr, `""`)
+	expr = filterPattern.ReplaceAllString(expr, "|")
+	var names []string
+	for _, loc := range identPattern.FindAllStringIndex(expr, -1) {
+		name := expr[loc[0]:loc[1]]
+		if keywords[name] || (loc[0] > 0 && strings.ContainsRune(".|0123456789", rune(expr[loc[0]-1]))) {
+			continue
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+// lineOf returns the 1-based line of offset in template
+func lineOf(template string, offset int) int {
+	return strings.Count(template[:offset], "\n") + 1
+}
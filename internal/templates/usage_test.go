@@ -0,0 +1,50 @@
+package templates
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUsage(t *testing.T) {
+	variables := map[string]any{
+		"ProjectName": "orders",
+		"IsGin":       true,
+		"IsEcho":      false,
+		"HasDatabase": false,
+		"HasRedis":    true,
+		"Components":  []string{"gin"},
+		"Unused":      "x",
+		"Filtered":    "y",
+	}
+	template := `package main
+{% if IsGin %}
+import "github.com/gin-gonic/gin"
+{% elif IsEcho %}
+import "github.com/labstack/echo/v4"
+{% endif %}
+{% if HasDatabase %}
+{% if HasRedis %}nested{% endif %}
+{% endif %}
+{% for c in Components %}{% if c == "gin" %}{{ c|upper }}{% endif %}{% endfor %}
+{{ "Unused"|lower }} {{ ProjectName | default:Filtered }}
+{% if not HasRedis %}none{% else %}redis{% endif %}`
+
+	usage := NewUsage(variables)
+	usage.Track("main.go", template)
+
+	assert.Equal(t, []string{"Unused"}, usage.Unused(), "names in strings are not references")
+	assert.Equal(t, []DeadBranch{
+		{Template: "main.go", Line: 4, Condition: "IsEcho"},
+		{Template: "main.go", Line: 7, Condition: "HasDatabase"},
+		{Template: "main.go", Line: 12, Condition: "not HasRedis"},
+	}, usage.DeadBranches(), "conditions in dead blocks and on loop variables are not reported")
+
+	usage.Refer("Unused")
+	assert.Empty(t, usage.Unused())
+	assert.Equal(t, []string{
+		`main.go:4: condition "IsEcho" is never true`,
+		`main.go:7: condition "HasDatabase" is never true`,
+		`main.go:12: condition "not HasRedis" is never true`,
+	}, usage.Warnings())
+}
@@ -0,0 +1,401 @@
+package templates
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Variable types inferred by AnalyzeVariables
+const (
+	VarString = "string"
+	VarBool   = "bool"
+	VarNumber = "number"
+	VarList   = "list"
+	VarMap    = "map"
+)
+
+// Source is a named template body to analyze
+type Source struct {
+	Name    string
+	Content string
+}
+
+// VariableUsage is one place a variable is referenced
+type VariableUsage struct {
+	Source string
+	Line   int
+}
+
+// Variable documents a variable referenced by a set of templates
+type Variable struct {
+	Name string
+	Type string
+	// Required is set when the variable is rendered outside any if block
+	// and without a default filter
+	Required bool
+	// When lists the variables whose if blocks render this one, for
+	// variables that are only needed conditionally
+	When   []string
+	Usages []VariableUsage
+}
+
+// varKeywords are pongo2 words that are not variables
+var varKeywords = map[string]bool{
+	"and": true, "or": true, "not": true, "in": true, "is": true,
+	"true": true, "false": true, "True": true, "False": true, "none": true, "None": true,
+	"reversed": true, "sorted": true,
+}
+
+// typeRank orders inferred types; the most specific usage wins
+var typeRank = map[string]int{VarBool: 0, VarString: 1, VarNumber: 2, VarList: 3, VarMap: 4}
+
+// AnalyzeVariables statically scans pongo2 templates and returns every
+// variable they reference, sorted by name. Loop and set variables are local
+// and not reported.
+func AnalyzeVariables(sources []Source) []Variable {
+	a := &varAnalyzer{vars: make(map[string]*Variable)}
+	for _, source := range sources {
+		a.analyze(source)
+	}
+
+	result := make([]Variable, 0, len(a.vars))
+	for _, v := range a.vars {
+		result = append(result, *v)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+type varAnalyzer struct {
+	vars map[string]*Variable
+}
+
+// varFrame is an open if or for block
+type varFrame struct {
+	tag     string
+	guards  map[string]bool // variables tested by the if/elif conditions
+	locals  map[string]bool // loop or set variables
+	isBlock bool
+}
+
+func (a *varAnalyzer) analyze(source Source) {
+	// The root frame holds set variables and is never closed
+	frames := []varFrame{{tag: "root", locals: make(map[string]bool)}}
+	for _, tag := range scanTags(source.Content) {
+		tokens := tokenizeExpr(tag.body)
+		usage := VariableUsage{Source: source.Name, Line: tag.line}
+
+		if tag.kind == '{' {
+			a.recordOutput(tokens, frames, usage)
+			continue
+		}
+		if len(tokens) == 0 {
+			continue
+		}
+
+		switch tokens[0] {
+		case "if":
+			frame := varFrame{tag: "if", guards: make(map[string]bool), isBlock: true}
+			a.recordCondition(tokens[1:], frames, usage, frame.guards)
+			frames = append(frames, frame)
+		case "elif":
+			if n := len(frames); n > 0 && frames[n-1].tag == "if" {
+				a.recordCondition(tokens[1:], frames, usage, frames[n-1].guards)
+			}
+		case "for":
+			frame := varFrame{tag: "for", locals: make(map[string]bool), isBlock: true}
+			i := 1
+			for ; i < len(tokens) && tokens[i] != "in"; i++ {
+				if isIdentifier(tokens[i]) {
+					frame.locals[tokens[i]] = true
+				}
+			}
+			if i < len(tokens) {
+				for _, name := range a.identifiers(tokens[i+1:], frames) {
+					a.record(name.root, hintFor(VarList, name), false, usage)
+				}
+			}
+			frames = append(frames, frame)
+		case "set":
+			// {% set name = expr %} defines a template-wide local
+			if len(tokens) > 1 {
+				frames[0].locals[tokens[1]] = true
+			}
+			if len(tokens) > 3 {
+				a.recordOutput(tokens[3:], frames, usage)
+			}
+		case "endif", "endfor":
+			for n := len(frames) - 1; n >= 0; n-- {
+				if frames[n].isBlock {
+					frames = frames[:n]
+					break
+				}
+			}
+		}
+	}
+}
+
+// varRef is a variable reference within an expression
+type varRef struct {
+	root      string
+	attribute bool // accessed as root.field
+	index     int  // token index of the reference
+	defaulted bool // followed by |default
+}
+
+// identifiers returns the non-local variables referenced by tokens
+func (a *varAnalyzer) identifiers(tokens []string, frames []varFrame) []varRef {
+	var refs []varRef
+	for i, tok := range tokens {
+		if !isIdentifier(tok) || varKeywords[tok] || (i > 0 && tokens[i-1] == "|") {
+			continue
+		}
+		root, _, attribute := strings.Cut(tok, ".")
+		if isLocal(root, frames) {
+			continue
+		}
+		ref := varRef{root: root, attribute: attribute, index: i}
+		for j := i + 1; j+1 < len(tokens) && tokens[j] == "|"; j += 2 {
+			if tokens[j+1] == "default" || tokens[j+1] == "default_if_none" {
+				ref.defaulted = true
+			}
+			if j+2 < len(tokens) && tokens[j+2] == ":" {
+				j += 2 // skip the filter argument
+			}
+		}
+		refs = append(refs, ref)
+	}
+	return refs
+}
+
+func (a *varAnalyzer) recordOutput(tokens []string, frames []varFrame, usage VariableUsage) {
+	var when []string
+	for _, frame := range frames {
+		for guard := range frame.guards {
+			when = append(when, guard)
+		}
+	}
+	sort.Strings(when)
+
+	for _, ref := range a.identifiers(tokens, frames) {
+		switch {
+		case ref.defaulted || isGuarded(ref.root, frames):
+			a.record(ref.root, hintFor(VarString, ref), false, usage)
+		case len(when) > 0:
+			a.record(ref.root, hintFor(VarString, ref), false, usage)
+			if v := a.vars[ref.root]; !v.Required {
+				v.When = mergeNames(v.When, when)
+			}
+		default:
+			a.record(ref.root, hintFor(VarString, ref), true, usage)
+		}
+	}
+}
+
+// mergeNames adds names to the sorted set names
+func mergeNames(set, names []string) []string {
+	for _, name := range names {
+		if i := sort.SearchStrings(set, name); i == len(set) || set[i] != name {
+			set = append(set[:i], append([]string{name}, set[i:]...)...)
+		}
+	}
+	return set
+}
+
+func (a *varAnalyzer) recordCondition(tokens []string, frames []varFrame, usage VariableUsage, guards map[string]bool) {
+	for _, ref := range a.identifiers(tokens, frames) {
+		guards[ref.root] = true
+		a.record(ref.root, hintFor(conditionType(tokens, ref.index), ref), false, usage)
+	}
+}
+
+// conditionType infers a variable's type from its neighbours in a condition
+func conditionType(tokens []string, i int) string {
+	if i > 0 && tokens[i-1] == "in" {
+		return VarList
+	}
+	for _, j := range []int{i - 1, i + 1} {
+		if j < 0 || j >= len(tokens) {
+			continue
+		}
+		switch tokens[j] {
+		case "==", "!=", "<", ">", "<=", ">=":
+			other := i + 2*(j-i)
+			if other < 0 || other >= len(tokens) {
+				continue
+			}
+			if isNumber(tokens[other]) {
+				return VarNumber
+			}
+			if isStringLiteral(tokens[other]) {
+				return VarString
+			}
+		case "in":
+			if j == i+1 {
+				return VarString // "x in List" tests membership of x
+			}
+		}
+	}
+	return VarBool
+}
+
+func hintFor(base string, ref varRef) string {
+	if ref.attribute {
+		return VarMap
+	}
+	return base
+}
+
+func (a *varAnalyzer) record(name, typ string, required bool, usage VariableUsage) {
+	v, ok := a.vars[name]
+	if !ok {
+		v = &Variable{Name: name, Type: typ}
+		a.vars[name] = v
+	}
+	if typeRank[typ] > typeRank[v.Type] {
+		v.Type = typ
+	}
+	v.Required = v.Required || required
+	if v.Required {
+		v.When = nil
+	}
+	if n := len(v.Usages); n == 0 || v.Usages[n-1] != usage {
+		v.Usages = append(v.Usages, usage)
+	}
+}
+
+func isLocal(name string, frames []varFrame) bool {
+	for _, frame := range frames {
+		if frame.locals[name] {
+			return true
+		}
+	}
+	return false
+}
+
+func isGuarded(name string, frames []varFrame) bool {
+	for _, frame := range frames {
+		if frame.guards[name] {
+			return true
+		}
+	}
+	return false
+}
+
+// templateTag is a {{ }} or {% %} tag found in a template
+type templateTag struct {
+	kind byte // '{' or '%'
+	body string
+	line int
+}
+
+// scanTags finds the variable and block tags of a pongo2 template, honouring
+// quoted strings so that {{ "}}" }} is read as one tag. Comments are skipped.
+func scanTags(content string) []templateTag {
+	var tags []templateTag
+	line := 1
+	for i := 0; i+1 < len(content); i++ {
+		if content[i] == '\n' {
+			line++
+			continue
+		}
+		if content[i] != '{' {
+			continue
+		}
+		kind := content[i+1]
+		if kind != '{' && kind != '%' && kind != '#' {
+			continue
+		}
+		closer := byte('}')
+		if kind != '{' {
+			closer = kind
+		}
+
+		var quote byte
+		end := -1
+		for j := i + 2; j+1 < len(content); j++ {
+			c := content[j]
+			switch {
+			case quote != 0:
+				if c == '\\' {
+					j++
+				} else if c == quote {
+					quote = 0
+				}
+			case c == '"' || c == '\'':
+				quote = c
+			case c == closer && content[j+1] == '}':
+				end = j
+			}
+			if end >= 0 {
+				break
+			}
+		}
+		if end < 0 {
+			break
+		}
+
+		if kind != '#' {
+			tags = append(tags, templateTag{kind: kind, body: content[i+2 : end], line: line})
+		}
+		line += strings.Count(content[i:end], "\n")
+		i = end + 1
+	}
+	return tags
+}
+
+// tokenizeExpr splits a tag body into identifiers (with dotted attributes),
+// string and number literals, and operators
+func tokenizeExpr(expr string) []string {
+	var tokens []string
+	for i := 0; i < len(expr); {
+		c := rune(expr[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '"' || c == '\'':
+			j := i + 1
+			for j < len(expr) && expr[j] != expr[i] {
+				if expr[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			j = min(j+1, len(expr))
+			tokens = append(tokens, expr[i:j])
+			i = j
+		case c == '_' || unicode.IsLetter(c) || unicode.IsDigit(c):
+			j := i
+			for j < len(expr) && (expr[j] == '_' || expr[j] == '.' || unicode.IsLetter(rune(expr[j])) || unicode.IsDigit(rune(expr[j]))) {
+				j++
+			}
+			tokens = append(tokens, expr[i:j])
+			i = j
+		default:
+			j := i + 1
+			if j < len(expr) && strings.ContainsRune("=<>!", c) && expr[j] == '=' {
+				j++
+			}
+			tokens = append(tokens, expr[i:j])
+			i = j
+		}
+	}
+	return tokens
+}
+
+func isIdentifier(tok string) bool {
+	if tok == "" {
+		return false
+	}
+	c := rune(tok[0])
+	return c == '_' || unicode.IsLetter(c)
+}
+
+func isNumber(tok string) bool {
+	return tok != "" && unicode.IsDigit(rune(tok[0]))
+}
+
+func isStringLiteral(tok string) bool {
+	return tok != "" && (tok[0] == '"' || tok[0] == '\'')
+}
@@ -0,0 +1,87 @@
+package templates
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzeVariables(t *testing.T) {
+	sources := []Source{
+		{Name: "main.go", Content: `package main
+// {{ ProjectName }} by {{ Author|default:"unknown" }}
+{% if HasDatabase %}
+import "{{ DatabaseDriver }}"
+{% endif %}
+{% if "gin" in Components %}gin{% endif %}
+{% for name in Services %}{{ name|title }} {{ Config.Port }}{% endfor %}
+{% if Replicas > 1 %}scaled{% elif Mode == "dev" %}dev{% endif %}
+{% set greeting = Greeting %}{{ greeting }}
+{# {{ Commented }} #}
+{{ "}}" }}`},
+		{Name: "README.md", Content: "# {{ ProjectName }}\n"},
+	}
+
+	vars := AnalyzeVariables(sources)
+	byName := map[string]Variable{}
+	for _, v := range vars {
+		byName[v.Name] = v
+	}
+
+	names := make([]string, 0, len(vars))
+	for _, v := range vars {
+		names = append(names, v.Name)
+	}
+	assert.Equal(t, []string{"Author", "Components", "Config", "DatabaseDriver", "Greeting", "HasDatabase", "Mode", "ProjectName", "Replicas", "Services"}, names,
+		"loop, set and commented variables are not reported")
+
+	tests := []struct {
+		name     string
+		typ      string
+		required bool
+	}{
+		{"ProjectName", VarString, true},
+		{"Author", VarString, false},
+		{"HasDatabase", VarBool, false},
+		{"DatabaseDriver", VarString, false},
+		{"Components", VarList, false},
+		{"Services", VarList, false},
+		{"Config", VarMap, true},
+		{"Replicas", VarNumber, false},
+		{"Mode", VarString, false},
+		{"Greeting", VarString, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := byName[tt.name]
+			assert.Equal(t, tt.typ, v.Type)
+			assert.Equal(t, tt.required, v.Required)
+		})
+	}
+
+	assert.Equal(t, []VariableUsage{{Source: "main.go", Line: 2}, {Source: "README.md", Line: 1}}, byName["ProjectName"].Usages)
+	assert.Equal(t, []VariableUsage{{Source: "main.go", Line: 4}}, byName["DatabaseDriver"].Usages)
+	assert.Equal(t, []string{"HasDatabase"}, byName["DatabaseDriver"].When)
+	assert.Empty(t, byName["ProjectName"].When)
+}
+
+func TestAnalyzeVariables_PredefinedTemplates(t *testing.T) {
+	repo := NewRepository()
+	files, err := repo.GetTemplateFiles(context.Background(), "cli")
+	require.NoError(t, err)
+
+	var sources []Source
+	for _, file := range files {
+		sources = append(sources, Source{Name: file.Name, Content: file.Content})
+	}
+
+	var required []string
+	for _, v := range AnalyzeVariables(sources) {
+		if v.Required {
+			required = append(required, v.Name)
+		}
+	}
+	assert.Subset(t, required, []string{"ProjectName", "ModuleName", "GoVersion"})
+}
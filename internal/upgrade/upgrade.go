@@ -0,0 +1,292 @@
+// Package upgrade re-applies a project's template with the running gogo. It
+// re-renders the project from its manifest and merges what changed in the
+// generated output into the files on disk: untouched files are replaced,
+// edited files get a three-way merge against the content originally
+// generated, and changes that conflict with local edits are written to .rej
+// files next to them.
+package upgrade
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/user/gogo/internal/fsutil"
+	"github.com/user/gogo/internal/generator"
+	"github.com/user/gogo/internal/git"
+	"github.com/user/gogo/internal/merge"
+	"github.com/user/gogo/internal/project"
+)
+
+// Action says what an upgrade did with a generated file
+type Action string
+
+// Upgrade actions
+const (
+	ActionUnchanged Action = "unchanged" // the template output is what the file already has, or did not change
+	ActionAdded     Action = "added"     // the template generates a new file
+	ActionUpdated   Action = "updated"   // the file had no local edits and was replaced
+	ActionMerged    Action = "merged"    // template changes were merged into local edits
+	ActionConflict  Action = "conflict"  // some template changes conflict with local edits and went to a .rej file
+	ActionSkipped   Action = "skipped"   // the file was deleted locally and is not restored
+	ActionObsolete  Action = "obsolete"  // the template no longer generates the file; it is left as it is
+)
+
+// FileChange is the outcome of upgrading one file
+type FileChange struct {
+	Path     string // slash-separated, relative to the project
+	Action   Action
+	Rejected int    // with ActionConflict, the number of hunks written to the .rej file
+	NoBase   bool   // the originally generated content was not found, so the whole file was compared
+	Sections bool   // only the sections within gogo markers were regenerated
+	Markers  string // why the gogo markers of the file were not used, if invalid
+}
+
+// Options configure an upgrade
+type Options struct {
+	GogoVersion string         // version recorded in the upgraded manifest
+	Resolver    merge.Resolver // decides conflicting hunks; nil writes them all to .rej files
+	DryRun      bool           // report what would change without writing anything
+}
+
+// Report is the outcome of an upgrade
+type Report struct {
+	From  string // gogo version the project was generated with
+	To    string
+	Files []FileChange
+}
+
+// Count returns the number of files upgraded with the given action
+func (r *Report) Count(action Action) int {
+	n := 0
+	for _, file := range r.Files {
+		if file.Action == action {
+			n++
+		}
+	}
+	return n
+}
+
+// Changed returns the files the upgrade writes or reports on, leaving out
+// unchanged ones
+func (r *Report) Changed() []FileChange {
+	var changed []FileChange
+	for _, file := range r.Files {
+		if file.Action != ActionUnchanged {
+			changed = append(changed, file)
+		}
+	}
+	return changed
+}
+
+// Run upgrades the project in dir. gen re-renders the project and must know
+// its template and blueprint.
+func Run(ctx context.Context, dir string, gen *generator.Generator, opts Options) (*Report, error) {
+	manifest, err := project.LoadManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Resolver == nil {
+		opts.Resolver = merge.StaticResolver{Resolution: merge.Skip}
+	}
+
+	scratch, err := os.MkdirTemp("", "gogo-upgrade-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create scratch directory: %w", err)
+	}
+	defer os.RemoveAll(scratch)
+
+	rendered := filepath.Join(scratch, manifest.ProjectName)
+	renderOpts := generator.OptionsFromManifest(manifest, rendered)
+	renderOpts.GogoVersion = opts.GogoVersion
+	if _, err := gen.InitProject(ctx, renderOpts); err != nil {
+		return nil, fmt.Errorf("failed to render the %s template: %w", manifest.Template, err)
+	}
+	upgraded, err := project.LoadManifestFile(rendered)
+	if err != nil {
+		return nil, err
+	}
+	// drift_ignore is the project's to edit
+	upgraded.DriftIgnore = manifest.DriftIgnore
+
+	recorded := make(map[string]string, len(manifest.Files))
+	for _, file := range manifest.Files {
+		recorded[file.Path] = file.SHA256
+	}
+
+	u := &upgrader{dir: dir, rendered: rendered, opts: opts, bases: newBaseFinder(ctx, dir)}
+	report := &Report{From: manifest.GogoVersion, To: opts.GogoVersion}
+	generated := make(map[string]bool, len(upgraded.Files))
+	for _, file := range upgraded.Files {
+		generated[file.Path] = true
+		sum, managed := recorded[file.Path]
+		change, err := u.upgradeFile(ctx, file, sum, managed)
+		if err != nil {
+			return nil, err
+		}
+		report.Files = append(report.Files, change)
+	}
+	for _, file := range manifest.Files {
+		if !generated[file.Path] {
+			report.Files = append(report.Files, FileChange{Path: file.Path, Action: ActionObsolete})
+		}
+	}
+
+	// An up-to-date project keeps its manifest, generation time included
+	upToDate := len(report.Changed()) == 0 && manifest.GogoVersion == opts.GogoVersion
+	if !opts.DryRun && !upToDate {
+		if err := upgraded.Save(dir); err != nil {
+			return nil, err
+		}
+	}
+	return report, nil
+}
+
+// upgrader applies one re-rendered project to the one on disk
+type upgrader struct {
+	dir      string
+	rendered string
+	opts     Options
+	bases    *baseFinder
+}
+
+// upgradeFile brings one generated file up to date. sum is the hash the old
+// manifest recorded for it, if managed.
+func (u *upgrader) upgradeFile(ctx context.Context, file project.ManagedFile, sum string, managed bool) (FileChange, error) {
+	change := FileChange{Path: file.Path}
+	target := filepath.Join(u.dir, filepath.FromSlash(file.Path))
+	source := filepath.Join(u.rendered, filepath.FromSlash(file.Path))
+
+	template, err := os.ReadFile(source)
+	if err != nil {
+		return change, fmt.Errorf("failed to read rendered %s: %w", file.Path, err)
+	}
+	current, err := os.ReadFile(target)
+	missing := os.IsNotExist(err)
+	if err != nil && !missing {
+		return change, fmt.Errorf("failed to read %s: %w", file.Path, err)
+	}
+
+	switch {
+	case missing && managed:
+		change.Action = ActionSkipped
+		return change, nil
+	case missing:
+		change.Action = ActionAdded
+		return change, u.write(target, source, template)
+	case bytes.Equal(current, template), managed && file.SHA256 == sum:
+		// Local edits, if any, are all that differ
+		change.Action = ActionUnchanged
+		return change, nil
+	case managed && hash(current) == sum:
+		change.Action = ActionUpdated
+		return change, u.write(target, source, template)
+	}
+
+	// Both the template and the file changed since generation. In a file
+	// with gogo markers, only the sections within them are gogo's to update.
+	if merge.HasSections(string(template)) && merge.HasSections(string(current)) {
+		replaced, err := merge.ReplaceSections(string(current), string(template))
+		if err == nil {
+			change.Action, change.Sections = ActionMerged, true
+			if replaced == string(current) {
+				change.Action = ActionUnchanged
+				return change, nil
+			}
+			return change, u.write(target, target, []byte(replaced))
+		}
+		change.Markers = err.Error()
+	}
+
+	var base []byte
+	if managed {
+		base = u.bases.find(ctx, file.Path, sum)
+	}
+	change.NoBase = base == nil
+
+	resolver := u.opts.Resolver
+	if u.opts.DryRun {
+		resolver = merge.StaticResolver{Resolution: merge.Skip}
+	}
+	applied, err := merge.ThreeWay(string(base), string(current), string(template)).Apply(file.Path, resolver)
+	if err != nil {
+		return change, err
+	}
+	change.Action = ActionMerged
+	if len(applied.Rejected) > 0 {
+		change.Action = ActionConflict
+		change.Rejected = len(applied.Rejected)
+	}
+	if u.opts.DryRun {
+		return change, nil
+	}
+
+	if applied.Content != string(current) {
+		if err := u.write(target, target, []byte(applied.Content)); err != nil {
+			return change, err
+		}
+	}
+	return change, merge.WriteRejects(target, applied.Rejected)
+}
+
+// write replaces target with content, keeping the mode of modeFrom
+func (u *upgrader) write(target, modeFrom string, content []byte) error {
+	if u.opts.DryRun {
+		return nil
+	}
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(modeFrom); err == nil {
+		mode = info.Mode().Perm()
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", target, err)
+	}
+	if err := fsutil.WriteFile(target, content, mode); err != nil {
+		return fmt.Errorf("failed to write %s: %w", target, err)
+	}
+	return nil
+}
+
+// baseFinder looks up the content gogo originally generated for a file in
+// the project's git history, where gogo init commits it
+type baseFinder struct {
+	git *git.GitManager
+}
+
+func newBaseFinder(ctx context.Context, dir string) *baseFinder {
+	if !git.IsGitInstalled() {
+		return &baseFinder{}
+	}
+	manager := git.NewGitManager(dir)
+	if !manager.IsGitRepository(ctx) {
+		return &baseFinder{}
+	}
+	return &baseFinder{git: manager}
+}
+
+// find returns the committed version of path whose hash is sum, or nil
+func (f *baseFinder) find(ctx context.Context, path, sum string) []byte {
+	if f.git == nil {
+		return nil
+	}
+	revisions, err := f.git.FileRevisions(ctx, path)
+	if err != nil {
+		return nil
+	}
+	for _, rev := range revisions {
+		content, err := f.git.ShowFile(ctx, rev, path)
+		if err == nil && hash(content) == sum {
+			return content
+		}
+	}
+	return nil
+}
+
+func hash(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,238 @@
+package upgrade
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/user/gogo/internal/generator"
+	"github.com/user/gogo/internal/merge"
+	"github.com/user/gogo/internal/project"
+	"github.com/user/gogo/internal/templates"
+)
+
+// templateStore serves the svc template with whatever files the test sets
+type templateStore map[string]string
+
+func (s templateStore) StoredTemplateFiles(ctx context.Context, name string) ([]templates.TemplateFile, bool, error) {
+	if name != "svc" {
+		return nil, false, nil
+	}
+	var files []templates.TemplateFile
+	for path, content := range s {
+		files = append(files, templates.TemplateFile{Name: path, Path: path, Content: content})
+	}
+	return files, true, nil
+}
+
+var v1 = templateStore{
+	"Makefile":     "build:\n\tgo build\n\ntest:\n\tgo test\n",
+	"README.md":    "# {{ ProjectName }}\n\nGenerated by gogo.\n",
+	"Dockerfile":   "FROM golang:1.24\n",
+	"notes.txt":    "notes\n",
+	"obsolete.txt": "going away\n",
+}
+
+var v2 = templateStore{
+	"Makefile":   "build:\n\tgo build ./...\n\ntest:\n\tgo test\n",
+	"README.md":  "# {{ ProjectName }} service\n\nGenerated by gogo.\n",
+	"Dockerfile": "FROM golang:1.25\n",
+	"notes.txt":  "notes, updated\n",
+	"CHANGES.md": "# Changes\n",
+}
+
+func newGenerator(store templateStore) *generator.Generator {
+	repo := templates.NewRepository()
+	repo.SetStore(store)
+	return generator.NewProjectGenerator(templates.NewEngine(), repo)
+}
+
+// initProject generates the svc template with v1 into a fresh directory
+func initProject(t *testing.T) string {
+	dir := filepath.Join(t.TempDir(), "svc")
+	_, err := newGenerator(v1).InitProject(context.Background(), generator.InitOptions{
+		ProjectName: "svc",
+		ModuleName:  "github.com/user/svc",
+		Template:    "svc",
+		GogoVersion: "v1.0.0",
+		OutputDir:   dir,
+	})
+	require.NoError(t, err)
+	return dir
+}
+
+// commitAll commits the project, as gogo init --git does
+func commitAll(t *testing.T, dir string) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "-A"},
+		{"-c", "user.name=Test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "Initial commit"},
+	} {
+		out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+}
+
+func edit(t *testing.T, dir, path, content string) {
+	require.NoError(t, os.WriteFile(filepath.Join(dir, path), []byte(content), 0644))
+}
+
+func read(t *testing.T, dir, path string) string {
+	content, err := os.ReadFile(filepath.Join(dir, path))
+	require.NoError(t, err)
+	return string(content)
+}
+
+func actions(report *Report) map[string]Action {
+	result := make(map[string]Action)
+	for _, file := range report.Files {
+		result[file.Path] = file.Action
+	}
+	return result
+}
+
+func TestRun(t *testing.T) {
+	dir := initProject(t)
+	commitAll(t, dir)
+
+	// Local edits: one merges cleanly, one conflicts, one file is deleted
+	edit(t, dir, "Makefile", "build:\n\tgo build\n\ntest:\n\tgo test\n\nlint:\n\tgolangci-lint run\n")
+	edit(t, dir, "README.md", "# svc, by the platform team\n\nGenerated by gogo.\n")
+	require.NoError(t, os.Remove(filepath.Join(dir, "notes.txt")))
+
+	report, err := Run(context.Background(), dir, newGenerator(v2), Options{GogoVersion: "v1.1.0"})
+	require.NoError(t, err)
+	assert.Equal(t, "v1.0.0", report.From)
+	assert.Equal(t, map[string]Action{
+		"Makefile":     ActionMerged,
+		"README.md":    ActionConflict,
+		"Dockerfile":   ActionUpdated,
+		"notes.txt":    ActionSkipped,
+		"CHANGES.md":   ActionAdded,
+		"obsolete.txt": ActionObsolete,
+	}, actions(report))
+
+	assert.Equal(t, "build:\n\tgo build ./...\n\ntest:\n\tgo test\n\nlint:\n\tgolangci-lint run\n", read(t, dir, "Makefile"))
+	assert.Equal(t, "# svc, by the platform team\n\nGenerated by gogo.\n", read(t, dir, "README.md"), "conflicts keep local edits")
+	assert.Contains(t, read(t, dir, "README.md.rej"), "# svc service\n")
+	assert.Equal(t, "FROM golang:1.25\n", read(t, dir, "Dockerfile"))
+	assert.Equal(t, "# Changes\n", read(t, dir, "CHANGES.md"))
+	assert.NoFileExists(t, filepath.Join(dir, "notes.txt"))
+	assert.FileExists(t, filepath.Join(dir, "obsolete.txt"))
+
+	manifest, err := project.LoadManifest(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "v1.1.0", manifest.GogoVersion)
+	for _, file := range manifest.Files {
+		assert.NotEqual(t, "obsolete.txt", file.Path)
+	}
+
+	// The upgraded project is up to date with the same templates
+	report, err = Run(context.Background(), dir, newGenerator(v2), Options{GogoVersion: "v1.1.0"})
+	require.NoError(t, err)
+	assert.Equal(t, []FileChange{{Path: "notes.txt", Action: ActionSkipped}}, report.Changed())
+}
+
+func TestRun_Resolver(t *testing.T) {
+	dir := initProject(t)
+	commitAll(t, dir)
+	edit(t, dir, "README.md", "# svc, by the platform team\n\nGenerated by gogo.\n")
+
+	report, err := Run(context.Background(), dir, newGenerator(v2), Options{
+		GogoVersion: "v1.1.0",
+		Resolver:    merge.StaticResolver{Resolution: merge.TakeTemplate},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, ActionMerged, actions(report)["README.md"])
+	assert.Equal(t, "# svc service\n\nGenerated by gogo.\n", read(t, dir, "README.md"))
+	assert.NoFileExists(t, filepath.Join(dir, "README.md.rej"))
+}
+
+func TestRun_WithoutHistory(t *testing.T) {
+	dir := initProject(t)
+	edit(t, dir, "Makefile", "build:\n\tgo build\n\ntest:\n\tgo test\n\nlint:\n\tgolangci-lint run\n")
+
+	report, err := Run(context.Background(), dir, newGenerator(v2), Options{GogoVersion: "v1.1.0"})
+	require.NoError(t, err)
+
+	var makefile FileChange
+	for _, file := range report.Files {
+		if file.Path == "Makefile" {
+			makefile = file
+		}
+	}
+	assert.Equal(t, ActionConflict, makefile.Action, "without the original content every difference conflicts")
+	assert.True(t, makefile.NoBase)
+	assert.Contains(t, read(t, dir, "Makefile"), "golangci-lint", "local edits are kept")
+	assert.FileExists(t, filepath.Join(dir, "Makefile.rej"))
+	assert.Equal(t, ActionUpdated, actions(report)["Dockerfile"], "unedited files need no history")
+}
+
+func TestRun_DryRun(t *testing.T) {
+	dir := initProject(t)
+	commitAll(t, dir)
+	edit(t, dir, "README.md", "# svc, by the platform team\n\nGenerated by gogo.\n")
+	before := read(t, dir, project.ManifestFile)
+
+	report, err := Run(context.Background(), dir, newGenerator(v2), Options{GogoVersion: "v1.1.0", DryRun: true})
+	require.NoError(t, err)
+	assert.Equal(t, ActionConflict, actions(report)["README.md"])
+	assert.Equal(t, ActionUpdated, actions(report)["Dockerfile"])
+
+	assert.Equal(t, "FROM golang:1.24\n", read(t, dir, "Dockerfile"))
+	assert.NoFileExists(t, filepath.Join(dir, "CHANGES.md"))
+	assert.NoFileExists(t, filepath.Join(dir, "README.md.rej"))
+	assert.Equal(t, before, read(t, dir, project.ManifestFile))
+}
+
+func TestRun_Sections(t *testing.T) {
+	ci := func(run string) templateStore {
+		return templateStore{"ci.yml": "jobs:\n  # gogo:start test\n  test:\n    run: " + run + "\n  # gogo:end test\n"}
+	}
+	dir := filepath.Join(t.TempDir(), "svc")
+	_, err := newGenerator(ci("go test")).InitProject(context.Background(), generator.InitOptions{
+		ProjectName: "svc",
+		ModuleName:  "github.com/user/svc",
+		Template:    "svc",
+		GogoVersion: "v1.0.0",
+		OutputDir:   dir,
+	})
+	require.NoError(t, err)
+
+	// Without git history a whole-file merge would conflict
+	edited := "on: [push]\njobs:\n  # gogo:start test\n  test:\n    run: go test\n  # gogo:end test\n  deploy:\n    run: ./deploy.sh\n"
+	edit(t, dir, "ci.yml", edited)
+	drift, err := project.DetectDrift(dir, mustManifest(t, dir))
+	require.NoError(t, err)
+	assert.Empty(t, drift, "edits outside the markers are not drift")
+
+	report, err := Run(context.Background(), dir, newGenerator(ci("go test ./...")), Options{GogoVersion: "v1.1.0"})
+	require.NoError(t, err)
+	assert.Contains(t, report.Changed(), FileChange{Path: "ci.yml", Action: ActionMerged, Sections: true})
+	assert.Equal(t, "on: [push]\njobs:\n  # gogo:start test\n  test:\n    run: go test ./...\n  # gogo:end test\n  deploy:\n    run: ./deploy.sh\n", read(t, dir, "ci.yml"))
+	assert.NoFileExists(t, filepath.Join(dir, "ci.yml.rej"))
+
+	drift, err = project.DetectDrift(dir, mustManifest(t, dir))
+	require.NoError(t, err)
+	assert.Empty(t, drift)
+
+	// Edits within the markers are
+	edit(t, dir, "ci.yml", strings.Replace(read(t, dir, "ci.yml"), "go test ./...", "go test -short ./...", 1))
+	drift, err = project.DetectDrift(dir, mustManifest(t, dir))
+	require.NoError(t, err)
+	assert.Equal(t, []project.FileDrift{{Path: "ci.yml", State: project.DriftModified}}, drift)
+}
+
+func mustManifest(t *testing.T, dir string) *project.Manifest {
+	manifest, err := project.LoadManifest(dir)
+	require.NoError(t, err)
+	return manifest
+}
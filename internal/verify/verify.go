@@ -0,0 +1,323 @@
+// Package verify checks the health of a generated project: that its
+// manifest is intact, go.mod still declares the generated module path, the
+// CI configuration parses and the files builds depend on match what gogo
+// renders. It is meant as a quick sanity check after manual edits or merges.
+package verify
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/user/gogo/internal/cicd"
+	"github.com/user/gogo/internal/generator"
+	"github.com/user/gogo/internal/project"
+	"github.com/user/gogo/internal/taskrunner"
+	"golang.org/x/mod/modfile"
+	"gopkg.in/yaml.v3"
+)
+
+// Check outcomes, from best to worst
+const (
+	StatusOK   = "ok"
+	StatusWarn = "warn"
+	StatusFail = "fail"
+)
+
+// Check is the outcome of one verification step
+type Check struct {
+	Name    string
+	Status  string
+	Summary string
+	Details []string // one line per offending file
+}
+
+// Report holds every check run against a project
+type Report struct {
+	Manifest *project.Manifest
+	Checks   []Check
+}
+
+// Count returns the number of checks with the given status
+func (r *Report) Count(status string) int {
+	n := 0
+	for _, check := range r.Checks {
+		if check.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
+// Healthy reports whether no check failed
+func (r *Report) Healthy() bool {
+	return r.Count(StatusFail) == 0
+}
+
+// criticalFiles are the generated files builds and CI depend on, with the
+// file of every task runner, the pipeline of every CI provider and the
+// configuration of every dependency update bot. They only depend on what the
+// manifest records, so a re-render reproduces them.
+var criticalFiles = func() []string {
+	files := []string{taskrunner.MakefilePath, taskrunner.TaskfilePath, "Dockerfile", "docker-compose.yml"}
+	for _, provider := range cicd.Providers {
+		for _, file := range cicd.Files(cicd.Config{Provider: provider}) {
+			if !slices.Contains(files, file) {
+				files = append(files, file)
+			}
+		}
+	}
+	for _, bot := range cicd.UpdateBots {
+		files = append(files, cicd.UpdateBotFile(bot))
+	}
+	return files
+}()
+
+// Run verifies the project in dir. gen re-renders the critical files and
+// must know the project's template and blueprint.
+func Run(ctx context.Context, dir string, gen *generator.Generator) (*Report, error) {
+	manifest, err := project.LoadManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{Manifest: manifest}
+	report.Checks = append(report.Checks,
+		checkManifest(dir, manifest),
+		checkConflictMarkers(dir, manifest),
+		checkGoMod(dir, manifest),
+		checkYAML(dir, manifest),
+		checkRender(ctx, dir, manifest, gen),
+	)
+	return report, nil
+}
+
+// checkManifest confirms the manifest describes a project and every file it
+// manages is still there. Edits to managed files are only worth a warning,
+// and none at all for files matched by drift_ignore.
+func checkManifest(dir string, manifest *project.Manifest) Check {
+	check := Check{Name: "manifest", Status: StatusOK}
+
+	var missingFields []string
+	for _, field := range []struct{ name, value string }{
+		{"project_name", manifest.ProjectName},
+		{"module_name", manifest.ModuleName},
+		{"template", manifest.Template},
+	} {
+		if field.value == "" {
+			missingFields = append(missingFields, field.name)
+		}
+	}
+	if len(missingFields) > 0 {
+		check.Status = StatusFail
+		check.Summary = fmt.Sprintf("%s is missing %s", project.ManifestFile, strings.Join(missingFields, ", "))
+		return check
+	}
+
+	drift, err := project.DetectDrift(dir, manifest)
+	if err != nil {
+		check.Status = StatusFail
+		check.Summary = err.Error()
+		return check
+	}
+	drift, _ = project.IgnoreDrift(drift, manifest.DriftIgnore)
+
+	missing, modified := 0, 0
+	for _, file := range drift {
+		check.Details = append(check.Details, fmt.Sprintf("%-9s %s", file.State, file.Path))
+		if file.State == project.DriftMissing {
+			missing++
+		} else {
+			modified++
+		}
+	}
+	switch {
+	case missing > 0:
+		check.Status = StatusFail
+		check.Summary = fmt.Sprintf("%d of %d managed files are missing", missing, len(manifest.Files))
+	case modified > 0:
+		check.Status = StatusWarn
+		check.Summary = fmt.Sprintf("%d of %d managed files were edited by hand", modified, len(manifest.Files))
+	default:
+		check.Summary = fmt.Sprintf("%d managed files present", len(manifest.Files))
+	}
+	return check
+}
+
+// conflictMarkers start the lines git leaves in files with unresolved merges
+var conflictMarkers = []string{"<<<<<<< ", "=======", ">>>>>>> "}
+
+// checkConflictMarkers looks for unresolved merge conflicts in managed files
+func checkConflictMarkers(dir string, manifest *project.Manifest) Check {
+	check := Check{Name: "merge conflicts", Status: StatusOK}
+	for _, file := range manifest.Files {
+		data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(file.Path)))
+		if err != nil {
+			continue // reported by the manifest check
+		}
+		if line := conflictMarkerLine(data); line > 0 {
+			check.Details = append(check.Details, fmt.Sprintf("%s:%d", file.Path, line))
+		}
+	}
+	if len(check.Details) > 0 {
+		check.Status = StatusFail
+		check.Summary = fmt.Sprintf("%d files have unresolved merge conflicts", len(check.Details))
+	} else {
+		check.Summary = "no conflict markers in managed files"
+	}
+	return check
+}
+
+// conflictMarkerLine returns the first line starting a conflict, or 0. A
+// separator only counts after an opening marker.
+func conflictMarkerLine(data []byte) int {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	opened := 0
+	for n := 1; scanner.Scan(); n++ {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, conflictMarkers[0]):
+			opened = n
+		case opened > 0 && (line == conflictMarkers[1] || strings.HasPrefix(line, conflictMarkers[2])):
+			return opened
+		}
+	}
+	return 0
+}
+
+// checkGoMod confirms go.mod declares the module path the project was
+// generated with
+func checkGoMod(dir string, manifest *project.Manifest) Check {
+	check := Check{Name: "go.mod", Status: StatusFail}
+
+	data, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		check.Summary = "go.mod is missing"
+		if !os.IsNotExist(err) {
+			check.Summary = fmt.Sprintf("failed to read go.mod: %v", err)
+		}
+		return check
+	}
+	modulePath := modfile.ModulePath(data)
+	switch {
+	case modulePath == "":
+		check.Summary = "go.mod declares no module"
+	case modulePath != manifest.ModuleName:
+		check.Summary = fmt.Sprintf("go.mod declares module %s, but the project was generated as %s", modulePath, manifest.ModuleName)
+	default:
+		check.Status = StatusOK
+		check.Summary = "module " + modulePath
+	}
+	return check
+}
+
+// checkYAML parses the CI workflows or pipelines and every managed YAML file
+func checkYAML(dir string, manifest *project.Manifest) Check {
+	check := Check{Name: "CI configuration", Status: StatusOK}
+
+	files := make(map[string]bool)
+	for _, file := range manifest.Files {
+		if isYAML(file.Path) {
+			files[file.Path] = true
+		}
+	}
+	workflows, _ := filepath.Glob(filepath.Join(dir, ".github", "workflows", "*"))
+	for _, workflow := range workflows {
+		if rel, err := filepath.Rel(dir, workflow); err == nil && isYAML(rel) {
+			files[filepath.ToSlash(rel)] = true
+		}
+	}
+	// GitHub workflows are covered above; other providers have one pipeline
+	for _, provider := range cicd.Providers[1:] {
+		if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(cicd.PipelineFile(provider)))); err == nil {
+			files[cicd.PipelineFile(provider)] = true
+		}
+	}
+
+	parsed := 0
+	for file := range files {
+		data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(file)))
+		if err != nil {
+			continue // reported by the manifest check
+		}
+		var node yaml.Node
+		if err := yaml.Unmarshal(data, &node); err != nil {
+			check.Details = append(check.Details, fmt.Sprintf("%s: %v", file, err))
+			continue
+		}
+		parsed++
+	}
+	sort.Strings(check.Details)
+
+	if len(check.Details) > 0 {
+		check.Status = StatusFail
+		check.Summary = fmt.Sprintf("%d YAML files do not parse", len(check.Details))
+	} else {
+		check.Summary = fmt.Sprintf("%d YAML files parse", parsed)
+	}
+	return check
+}
+
+func isYAML(name string) bool {
+	ext := path.Ext(name)
+	return ext == ".yml" || ext == ".yaml"
+}
+
+// checkRender re-renders the project from its manifest and compares the
+// critical files with the ones on disk
+func checkRender(ctx context.Context, dir string, manifest *project.Manifest, gen *generator.Generator) Check {
+	check := Check{Name: "critical files", Status: StatusWarn}
+
+	scratch, err := os.MkdirTemp("", "gogo-verify-")
+	if err != nil {
+		check.Summary = fmt.Sprintf("cannot re-render: %v", err)
+		return check
+	}
+	defer os.RemoveAll(scratch)
+
+	opts := generator.OptionsFromManifest(manifest, filepath.Join(scratch, manifest.ProjectName))
+	if _, err := gen.InitProject(ctx, opts); err != nil {
+		check.Summary = fmt.Sprintf("cannot re-render the %s template: %v", manifest.Template, err)
+		return check
+	}
+
+	managed := make(map[string]bool, len(manifest.Files))
+	for _, file := range manifest.Files {
+		managed[file.Path] = true
+	}
+
+	compared := 0
+	for _, file := range criticalFiles {
+		if !managed[file] {
+			continue
+		}
+		rendered, err := os.ReadFile(filepath.Join(opts.OutputDir, filepath.FromSlash(file)))
+		if err != nil {
+			continue // no longer part of the template
+		}
+		existing, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(file)))
+		if err != nil {
+			continue // reported by the manifest check
+		}
+		compared++
+		if !bytes.Equal(existing, rendered) {
+			check.Details = append(check.Details, file)
+		}
+	}
+
+	if len(check.Details) > 0 {
+		check.Summary = fmt.Sprintf("%d of %d critical files differ from what gogo renders for this project", len(check.Details), compared)
+	} else {
+		check.Status = StatusOK
+		check.Summary = fmt.Sprintf("%d critical files match a fresh render", compared)
+	}
+	return check
+}
@@ -0,0 +1,109 @@
+package verify
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/user/gogo/internal/generator"
+	"github.com/user/gogo/internal/templates"
+)
+
+func newGenerator() *generator.Generator {
+	return generator.NewProjectGenerator(templates.NewEngine(), templates.NewRepository())
+}
+
+// generate renders a cli project with CI configuration and returns its directory
+func generate(t *testing.T) string {
+	dir := filepath.Join(t.TempDir(), "healthy")
+	_, err := newGenerator().InitProject(context.Background(), generator.InitOptions{
+		ProjectName: "healthy",
+		ModuleName:  "github.com/user/healthy",
+		Template:    "cli",
+		OutputDir:   dir,
+		GenerateCI:  true,
+	})
+	require.NoError(t, err)
+	return dir
+}
+
+func checkByName(t *testing.T, report *Report, name string) Check {
+	for _, check := range report.Checks {
+		if check.Name == name {
+			return check
+		}
+	}
+	t.Fatalf("no %s check", name)
+	return Check{}
+}
+
+func TestRun_Healthy(t *testing.T) {
+	dir := generate(t)
+
+	report, err := Run(context.Background(), dir, newGenerator())
+	require.NoError(t, err)
+	for _, check := range report.Checks {
+		assert.Equal(t, StatusOK, check.Status, "%s: %s %v", check.Name, check.Summary, check.Details)
+	}
+	assert.True(t, report.Healthy())
+	assert.Contains(t, checkByName(t, report, "critical files").Summary, "match a fresh render")
+}
+
+func TestRun_Problems(t *testing.T) {
+	dir := generate(t)
+	write := func(name, content string) {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, filepath.FromSlash(name)), []byte(content), 0644))
+	}
+	write("go.mod", "module github.com/user/renamed\n\ngo 1.25.1\n")
+	write(".github/workflows/ci.yml", "name: CI\non: [push\n")
+	write("Makefile", "build:\n<<<<<<< HEAD\n\tgo build ./...\n=======\n\tgo build -v ./...\n>>>>>>> feature\n")
+
+	report, err := Run(context.Background(), dir, newGenerator())
+	require.NoError(t, err)
+	assert.False(t, report.Healthy())
+
+	goMod := checkByName(t, report, "go.mod")
+	assert.Equal(t, StatusFail, goMod.Status)
+	assert.Contains(t, goMod.Summary, "github.com/user/renamed")
+
+	ci := checkByName(t, report, "CI configuration")
+	assert.Equal(t, StatusFail, ci.Status)
+	require.Len(t, ci.Details, 1)
+	assert.Contains(t, ci.Details[0], ".github/workflows/ci.yml")
+
+	conflicts := checkByName(t, report, "merge conflicts")
+	assert.Equal(t, StatusFail, conflicts.Status)
+	assert.Equal(t, []string{"Makefile:2"}, conflicts.Details)
+
+	critical := checkByName(t, report, "critical files")
+	assert.Equal(t, StatusWarn, critical.Status)
+	assert.ElementsMatch(t, []string{"Makefile", ".github/workflows/ci.yml"}, critical.Details)
+
+	manifest := checkByName(t, report, "manifest")
+	assert.Equal(t, StatusWarn, manifest.Status, "go.mod edits are ignored, the Makefile and workflow are not")
+	assert.Len(t, manifest.Details, 2)
+}
+
+func TestRun_MissingFile(t *testing.T) {
+	dir := generate(t)
+	require.NoError(t, os.Remove(filepath.Join(dir, "Makefile")))
+
+	report, err := Run(context.Background(), dir, newGenerator())
+	require.NoError(t, err)
+	manifest := checkByName(t, report, "manifest")
+	assert.Equal(t, StatusFail, manifest.Status)
+	assert.Contains(t, manifest.Details, "missing   Makefile")
+}
+
+func TestRun_NoManifest(t *testing.T) {
+	_, err := Run(context.Background(), t.TempDir(), newGenerator())
+	assert.ErrorContains(t, err, "no .gogo.yaml found")
+}
+
+func TestConflictMarkerLine(t *testing.T) {
+	assert.Equal(t, 0, conflictMarkerLine([]byte("a\n=======\nb\n")), "a separator alone is not a conflict")
+	assert.Equal(t, 2, conflictMarkerLine([]byte("a\n<<<<<<< HEAD\nb\n=======\nc\n>>>>>>> x\n")))
+}
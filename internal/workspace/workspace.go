@@ -0,0 +1,257 @@
+package workspace
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+)
+
+// WorkFile is the name of the Go workspace manifest
+const WorkFile = "go.work"
+
+// Module is a module listed in a go.work use directive
+type Module struct {
+	Dir  string // directory relative to the workspace root
+	Path string // module path from the module's go.mod
+}
+
+// Name returns the service name of the module, which is its directory's base name
+func (m Module) Name() string {
+	return filepath.Base(filepath.FromSlash(m.Dir))
+}
+
+// Workspace is a parsed go.work workspace
+type Workspace struct {
+	Root    string
+	Modules []Module
+	file    *modfile.WorkFile
+}
+
+// Find walks up from dir looking for a go.work file and loads the workspace.
+// It returns nil, nil when dir is not inside a workspace.
+func Find(dir string) (*Workspace, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("invalid directory %s: %w", dir, err)
+	}
+
+	for current := absDir; ; current = filepath.Dir(current) {
+		if _, err := os.Stat(filepath.Join(current, WorkFile)); err == nil {
+			return Load(current)
+		}
+		if filepath.Dir(current) == current {
+			return nil, nil
+		}
+	}
+}
+
+// Load parses the go.work file in root and the go.mod of every used module
+func Load(root string) (*Workspace, error) {
+	path := filepath.Join(root, WorkFile)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	workFile, err := modfile.ParseWork(path, data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	ws := &Workspace{Root: root, file: workFile}
+	for _, use := range workFile.Use {
+		dir := filepath.ToSlash(filepath.Clean(use.Path))
+		modPath, err := readModulePath(filepath.Join(root, filepath.FromSlash(dir)))
+		if err != nil {
+			return nil, err
+		}
+		ws.Modules = append(ws.Modules, Module{Dir: dir, Path: modPath})
+	}
+
+	return ws, nil
+}
+
+// Service resolves a module by service name. The name may be the module
+// directory relative to the root, its base name, or its full module path.
+func (w *Workspace) Service(name string) (Module, error) {
+	clean := filepath.ToSlash(filepath.Clean(name))
+
+	var matches []Module
+	for _, module := range w.Modules {
+		if module.Dir == clean || module.Path == name {
+			return module, nil
+		}
+		if module.Name() == name {
+			matches = append(matches, module)
+		}
+	}
+
+	switch len(matches) {
+	case 1:
+		return matches[0], nil
+	case 0:
+		return Module{}, fmt.Errorf("service '%s' not found in %s (available: %s)", name, WorkFile, strings.Join(w.serviceNames(), ", "))
+	default:
+		dirs := make([]string, len(matches))
+		for i, m := range matches {
+			dirs[i] = m.Dir
+		}
+		return Module{}, fmt.Errorf("service '%s' is ambiguous, use one of: %s", name, strings.Join(dirs, ", "))
+	}
+}
+
+// ModuleFor returns the workspace module containing dir, preferring the deepest match
+func (w *Workspace) ModuleFor(dir string) (Module, bool) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return Module{}, false
+	}
+
+	var best Module
+	found := false
+	for _, module := range w.Modules {
+		moduleDir := w.Abs(module)
+		rel, err := filepath.Rel(moduleDir, absDir)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		if !found || len(module.Dir) > len(best.Dir) {
+			best = module
+			found = true
+		}
+	}
+	return best, found
+}
+
+// Abs returns the absolute directory of a workspace module
+func (w *Workspace) Abs(module Module) string {
+	return filepath.Join(w.Root, filepath.FromSlash(module.Dir))
+}
+
+func (w *Workspace) serviceNames() []string {
+	names := make([]string, len(w.Modules))
+	for i, module := range w.Modules {
+		names[i] = module.Name()
+	}
+	return names
+}
+
+// ModulePath returns the module path declared in dir/go.mod
+func ModulePath(dir string) (string, error) {
+	return readModulePath(dir)
+}
+
+func readModulePath(dir string) (string, error) {
+	path := filepath.Join(dir, "go.mod")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	modPath := modfile.ModulePath(data)
+	if modPath == "" {
+		return "", fmt.Errorf("no module directive in %s", path)
+	}
+	return modPath, nil
+}
+
+// AddUse adds a use directive for dir (relative to the root) and rewrites go.work
+func (w *Workspace) AddUse(dir string) error {
+	dir = filepath.ToSlash(filepath.Clean(dir))
+	for _, module := range w.Modules {
+		if module.Dir == dir {
+			return nil
+		}
+	}
+
+	modPath, err := readModulePath(filepath.Join(w.Root, filepath.FromSlash(dir)))
+	if err != nil {
+		return err
+	}
+
+	if err := w.file.AddUse("./"+dir, modPath); err != nil {
+		return fmt.Errorf("failed to add use directive for %s: %w", dir, err)
+	}
+	w.file.Cleanup()
+
+	path := filepath.Join(w.Root, WorkFile)
+	if err := os.WriteFile(path, modfile.Format(w.file.Syntax), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	w.Modules = append(w.Modules, Module{Dir: dir, Path: modPath})
+	return nil
+}
+
+// AddReplace makes module depend on the local module at targetDir by adding
+// require and replace directives to its go.mod, so it also builds outside the workspace
+func (w *Workspace) AddReplace(module Module, modPath, targetDir string) error {
+	moduleDir := w.Abs(module)
+	path := filepath.Join(moduleDir, "go.mod")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	modFile, err := modfile.Parse(path, data, nil)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	relTarget, err := filepath.Rel(moduleDir, filepath.Join(w.Root, filepath.FromSlash(targetDir)))
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s relative to %s: %w", targetDir, module.Dir, err)
+	}
+	relTarget = filepath.ToSlash(relTarget)
+	if !strings.HasPrefix(relTarget, ".") {
+		relTarget = "./" + relTarget
+	}
+
+	if err := modFile.AddRequire(modPath, "v0.0.0"); err != nil {
+		return fmt.Errorf("failed to add require for %s: %w", modPath, err)
+	}
+	if err := modFile.AddReplace(modPath, "", relTarget, ""); err != nil {
+		return fmt.Errorf("failed to add replace for %s: %w", modPath, err)
+	}
+	modFile.Cleanup()
+
+	formatted, err := modFile.Format()
+	if err != nil {
+		return fmt.Errorf("failed to format %s: %w", path, err)
+	}
+	if err := os.WriteFile(path, formatted, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// SuggestModulePath derives a module path for a new module at dir from the
+// longest path prefix shared by the existing workspace modules
+func (w *Workspace) SuggestModulePath(dir string) string {
+	dir = filepath.ToSlash(filepath.Clean(dir))
+	if len(w.Modules) == 0 {
+		return dir
+	}
+
+	prefix := strings.Split(w.Modules[0].Path, "/")
+	if len(w.Modules) == 1 {
+		prefix = prefix[:len(prefix)-1]
+	}
+	for _, module := range w.Modules[1:] {
+		parts := strings.Split(module.Path, "/")
+		n := 0
+		for n < len(prefix) && n < len(parts) && prefix[n] == parts[n] {
+			n++
+		}
+		prefix = prefix[:n]
+	}
+
+	if len(prefix) == 0 {
+		return dir
+	}
+	return strings.Join(prefix, "/") + "/" + dir
+}
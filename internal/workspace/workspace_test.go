@@ -0,0 +1,96 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupWorkspace(t *testing.T) string {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, WorkFile), "go 1.25.1\n\nuse (\n\t./services/payments\n\t./services/users\n)\n")
+	writeFile(t, filepath.Join(root, "services/payments/go.mod"), "module github.com/acme/payments\n\ngo 1.25.1\n")
+	writeFile(t, filepath.Join(root, "services/users/go.mod"), "module github.com/acme/users\n\ngo 1.25.1\n")
+	return root
+}
+
+func writeFile(t *testing.T, path, content string) {
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+func TestFind(t *testing.T) {
+	root := setupWorkspace(t)
+
+	ws, err := Find(filepath.Join(root, "services", "payments"))
+	require.NoError(t, err)
+	require.NotNil(t, ws)
+	assert.Equal(t, root, ws.Root)
+	assert.Equal(t, []Module{
+		{Dir: "services/payments", Path: "github.com/acme/payments"},
+		{Dir: "services/users", Path: "github.com/acme/users"},
+	}, ws.Modules)
+}
+
+func TestFind_NoWorkspace(t *testing.T) {
+	ws, err := Find(t.TempDir())
+	require.NoError(t, err)
+	assert.Nil(t, ws)
+}
+
+func TestWorkspace_Service(t *testing.T) {
+	ws, err := Load(setupWorkspace(t))
+	require.NoError(t, err)
+
+	for _, name := range []string{"payments", "services/payments", "github.com/acme/payments"} {
+		module, err := ws.Service(name)
+		require.NoError(t, err, name)
+		assert.Equal(t, "github.com/acme/payments", module.Path)
+	}
+
+	_, err = ws.Service("billing")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "payments, users")
+}
+
+func TestWorkspace_ModuleFor(t *testing.T) {
+	root := setupWorkspace(t)
+	ws, err := Load(root)
+	require.NoError(t, err)
+
+	module, ok := ws.ModuleFor(filepath.Join(root, "services", "users", "internal"))
+	require.True(t, ok)
+	assert.Equal(t, "github.com/acme/users", module.Path)
+
+	_, ok = ws.ModuleFor(root)
+	assert.False(t, ok)
+}
+
+func TestWorkspace_AddUseAndReplace(t *testing.T) {
+	root := setupWorkspace(t)
+	ws, err := Load(root)
+	require.NoError(t, err)
+
+	modPath := ws.SuggestModulePath("pkg/common")
+	assert.Equal(t, "github.com/acme/pkg/common", modPath)
+
+	writeFile(t, filepath.Join(root, "pkg/common/go.mod"), "module "+modPath+"\n\ngo 1.25.1\n")
+	require.NoError(t, ws.AddUse("pkg/common"))
+	require.NoError(t, ws.AddUse("pkg/common")) // idempotent
+
+	payments, err := ws.Service("payments")
+	require.NoError(t, err)
+	require.NoError(t, ws.AddReplace(payments, modPath, "pkg/common"))
+
+	reloaded, err := Load(root)
+	require.NoError(t, err)
+	assert.Len(t, reloaded.Modules, 3)
+
+	goMod, err := os.ReadFile(filepath.Join(root, "services/payments/go.mod"))
+	require.NoError(t, err)
+	assert.Contains(t, string(goMod), "require github.com/acme/pkg/common v0.0.0")
+	assert.Contains(t, string(goMod), "replace github.com/acme/pkg/common => ../../pkg/common")
+}
@@ -0,0 +1,211 @@
+// Package scaffoldtest checks, in the tests of a template repository, that
+// its templates render with gogo and build with every Go release they claim
+// to support. Each release's project is rendered with a go.mod and language
+// level of that release, then tidied and built with its own toolchain:
+//
+//	func TestWorkerTemplate(t *testing.T) {
+//		scaffoldtest.Run(t, scaffoldtest.Options{
+//			Source:     "templates/worker",
+//			GoVersions: []string{"1.21", "1.22", "1.23"},
+//			Variables:  map[string]any{"HasDatabase": true, "DatabaseType": "sqlite"},
+//		})
+//	}
+//
+// Toolchains are found on PATH, under ~/sdk where golang.org/dl installs
+// them (go install golang.org/dl/go1.21.13@latest && go1.21.13 download),
+// and in EnvToolchains. Releases without one are skipped, not failed.
+package scaffoldtest
+
+import (
+	"context"
+	"fmt"
+	"go/version"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/user/gogo/internal/fixtures"
+	"github.com/user/gogo/internal/templates"
+)
+
+// EnvToolchains lists go binaries to build with besides those found on PATH
+// and under ~/sdk, separated like PATH
+const EnvToolchains = "GOGO_TOOLCHAINS"
+
+// goVersionPattern matches the release in the output of go env GOVERSION
+var goVersionPattern = regexp.MustCompile(`^go(1\.\d+(?:\.\d+)?)`)
+
+// Toolchain is the go command of one Go release
+type Toolchain struct {
+	Version string // Release, such as 1.22.5
+	Go      string // Path of the go binary
+}
+
+// Options selects the template Run renders and the releases it builds with
+type Options struct {
+	// Source is the directory of a template, or of a pack with templates/
+	// and blueprints/ directories, as gogo template test reads it
+	Source    string
+	Template  string // Template of the source to render; its only one when empty
+	Blueprint string // Blueprint to render the template with, from the source or built in
+	Variables map[string]any
+	// GoVersions are the releases to build with, such as 1.22; the newest
+	// toolchain of each release found is used. Every release found when
+	// empty.
+	GoVersions []string
+	// Toolchains are those to pick from; FindToolchains when empty
+	Toolchains []Toolchain
+}
+
+// FindToolchains returns the Go toolchains installed locally, oldest first
+func FindToolchains(ctx context.Context) ([]Toolchain, error) {
+	var candidates []string
+	if path, err := exec.LookPath("go"); err == nil {
+		candidates = append(candidates, path)
+	}
+	if homeDir, err := os.UserHomeDir(); err == nil {
+		sdks, err := filepath.Glob(filepath.Join(homeDir, "sdk", "go*", "bin", "go"))
+		if err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, sdks...)
+	}
+	candidates = append(candidates, filepath.SplitList(os.Getenv(EnvToolchains))...)
+
+	var toolchains []Toolchain
+	seen := make(map[string]bool)
+	for _, candidate := range candidates {
+		if candidate == "" {
+			continue
+		}
+		release, err := goRelease(ctx, candidate)
+		if err != nil {
+			return nil, err
+		}
+		if release == "" || seen[release] {
+			continue
+		}
+		seen[release] = true
+		toolchains = append(toolchains, Toolchain{Version: release, Go: candidate})
+	}
+	slices.SortFunc(toolchains, func(a, b Toolchain) int {
+		return version.Compare("go"+a.Version, "go"+b.Version)
+	})
+	return toolchains, nil
+}
+
+// Select returns the newest toolchain of each of the releases, such as 1.22,
+// in the order given, and the releases none was found for. Without releases
+// the newest toolchain of every release is selected.
+func Select(toolchains []Toolchain, releases []string) ([]Toolchain, []string) {
+	newest := make(map[string]Toolchain)
+	var found []string
+	for _, toolchain := range toolchains {
+		lang := version.Lang("go" + toolchain.Version)
+		current, ok := newest[lang]
+		if !ok {
+			found = append(found, lang)
+		}
+		if !ok || version.Compare("go"+toolchain.Version, "go"+current.Version) > 0 {
+			newest[lang] = toolchain
+		}
+	}
+	if len(releases) == 0 {
+		slices.SortFunc(found, version.Compare)
+		for _, lang := range found {
+			releases = append(releases, strings.TrimPrefix(lang, "go"))
+		}
+	}
+
+	var selected []Toolchain
+	var missing []string
+	for _, release := range releases {
+		toolchain, ok := newest[version.Lang("go"+release)]
+		if !ok {
+			missing = append(missing, release)
+			continue
+		}
+		selected = append(selected, toolchain)
+	}
+	return selected, missing
+}
+
+// Run renders the template of opts once per Go release and builds each
+// render with that release's toolchain, in a subtest named after the
+// release, such as go1.22. The go.mod and language level of each render are
+// the release's, so templates fall back to the constructs it has.
+func Run(t *testing.T, opts Options) {
+	t.Helper()
+	ctx := context.Background()
+
+	runner, err := fixtures.NewRunner(opts.Source)
+	if err != nil {
+		t.Fatalf("failed to read template source %s: %v", opts.Source, err)
+	}
+	template := opts.Template
+	if template == "" {
+		template = runner.DefaultTemplate()
+	}
+	if template == "" {
+		t.Fatalf("template source %s has several templates; set Options.Template", opts.Source)
+	}
+
+	toolchains := opts.Toolchains
+	if len(toolchains) == 0 {
+		if toolchains, err = FindToolchains(ctx); err != nil {
+			t.Fatalf("failed to find Go toolchains: %v", err)
+		}
+	}
+	selected, missing := Select(toolchains, opts.GoVersions)
+	for _, release := range missing {
+		t.Run("go"+release, func(t *testing.T) {
+			t.Skipf("no go%s toolchain found on PATH, under ~/sdk or in %s", release, EnvToolchains)
+		})
+	}
+	if len(selected) == 0 && len(missing) == 0 {
+		t.Skip("no Go toolchain found")
+	}
+
+	for _, toolchain := range selected {
+		release, err := templates.ParseLanguageLevel(toolchain.Version)
+		if err != nil {
+			t.Fatalf("toolchain %s: %v", toolchain.Go, err)
+		}
+		t.Run("go"+release, func(t *testing.T) {
+			result := runner.WithGo(toolchain.Go).Run(ctx, fixtures.Case{
+				Name:      "go" + release,
+				Template:  template,
+				Blueprint: opts.Blueprint,
+				Variables: opts.Variables,
+				GoVersion: release,
+			}, t.TempDir(), true)
+			if result.Err != nil {
+				t.Fatalf("go%s (%s): %v\n%s", toolchain.Version, toolchain.Go, result.Err, result.Output)
+			}
+			if !result.Built {
+				t.Logf("rendered %d files in %s; nothing to build without a go.mod", result.Files, result.Dir)
+			}
+		})
+	}
+}
+
+// goRelease returns the release of the go binary at path, or nothing for
+// development builds
+func goRelease(ctx context.Context, path string) (string, error) {
+	cmd := exec.CommandContext(ctx, path, "env", "GOVERSION")
+	// Report the toolchain itself, not one a go.mod around would switch to
+	cmd.Env = append(os.Environ(), "GOTOOLCHAIN=local")
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to run %s env GOVERSION: %w", path, err)
+	}
+	match := goVersionPattern.FindStringSubmatch(strings.TrimSpace(string(out)))
+	if match == nil {
+		return "", nil
+	}
+	return match[1], nil
+}
@@ -0,0 +1,83 @@
+package scaffoldtest
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelect(t *testing.T) {
+	toolchains := []Toolchain{
+		{Version: "1.21.13", Go: "/sdk/go1.21.13/bin/go"},
+		{Version: "1.22.1", Go: "/sdk/go1.22.1/bin/go"},
+		{Version: "1.22.5", Go: "/usr/local/go/bin/go"},
+	}
+
+	selected, missing := Select(toolchains, []string{"1.22", "1.21", "1.20"})
+	assert.Equal(t, []Toolchain{toolchains[2], toolchains[0]}, selected)
+	assert.Equal(t, []string{"1.20"}, missing)
+
+	selected, missing = Select(toolchains, nil)
+	assert.Equal(t, []Toolchain{toolchains[0], toolchains[2]}, selected)
+	assert.Empty(t, missing)
+}
+
+func TestFindToolchains(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go is not installed")
+	}
+
+	toolchains, err := FindToolchains(context.Background())
+	require.NoError(t, err)
+	require.NotEmpty(t, toolchains)
+	for _, toolchain := range toolchains {
+		assert.Regexp(t, `^1\.\d+(\.\d+)?$`, toolchain.Version)
+	}
+}
+
+func TestRun(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go is not installed")
+	}
+
+	source := filepath.Join(t.TempDir(), "hello")
+	files := map[string]string{
+		"go.mod.tmpl": "module {{ ModuleName }}\n\ngo {{ GoVersion }}\n",
+		"main.go.tmpl": `package main
+
+import "fmt"
+
+func main() {
+{% if HasRangeInt %}	for i := range 3 {
+{% else %}	for i := 0; i < 3; i++ {
+{% endif %}		fmt.Println("{{ Greeting }}", i)
+	}
+}
+`,
+	}
+	for name, content := range files {
+		require.NoError(t, os.MkdirAll(source, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(source, name), []byte(content), 0644))
+	}
+
+	Run(t, Options{
+		Source:    source,
+		Variables: map[string]any{"Greeting": "hello"},
+	})
+
+	// The go directive of an older release rejects range-over-int, so the
+	// render must fall back to a three-clause loop
+	goCommand, err := exec.LookPath("go")
+	require.NoError(t, err)
+	Run(t, Options{
+		Source:     source,
+		Variables:  map[string]any{"Greeting": "hello"},
+		GoVersions: []string{"1.21"},
+		Toolchains: []Toolchain{{Version: "1.21.0", Go: goCommand}},
+	})
+}